@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/cpufreq"
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/podman"
+	"github.com/ethpandaops/benchmarkoor/pkg/runner"
+	"github.com/ethpandaops/benchmarkoor/pkg/thp"
+	"github.com/spf13/cobra"
+)
+
+// minFreeDiskBytes is the free space below which a directory used for
+// datadir copies, caches or results is reported as a warning.
+const minFreeDiskBytes = 10 << 30
+
+var checkEnvCmd = &cobra.Command{
+	Use:   "check-env",
+	Short: "Report whether the host is ready to run benchmarks",
+	Long: `Probe the host for the capabilities benchmarkoor features depend on:
+the container runtime, drop_caches permission, CPU frequency and THP control,
+ZFS tooling and free disk space.
+
+Each check is reported as PASS, WARN or FAIL. With --config, checks for
+features the config uses fail when the capability is missing; otherwise a
+missing capability is only a warning. The command exits non-zero if any
+check fails.`,
+	RunE: runCheckEnv,
+}
+
+func init() {
+	rootCmd.AddCommand(checkEnvCmd)
+}
+
+// checkStatus is the outcome of a single environment check.
+type checkStatus string
+
+const (
+	checkPass checkStatus = "PASS"
+	checkWarn checkStatus = "WARN"
+	checkFail checkStatus = "FAIL"
+)
+
+// envCheck is a single line of the readiness report.
+type envCheck struct {
+	Name    string      // Capability checked
+	Feature string      // Config feature needing the capability
+	Status  checkStatus // Outcome
+	Detail  string      // Reason or value found
+}
+
+// envProbes probes host capabilities. They are swapped out in tests.
+type envProbes struct {
+	containerRuntime func(ctx context.Context, runtime string) error
+	writeAccess      func(path string) error
+	lookPath         func(file string) (string, error)
+	cpuFreqSupported func(basePath string) bool
+	cpuFreqAccess    func(basePath string) error
+	thpSupported     func(basePath string) bool
+	thpAccess        func(basePath string) error
+	freeDiskBytes    func(path string) (uint64, error)
+}
+
+// defaultEnvProbes returns probes checking the actual host.
+func defaultEnvProbes() envProbes {
+	return envProbes{
+		containerRuntime: probeContainerRuntime,
+		writeAccess:      probeWriteAccess,
+		lookPath:         exec.LookPath,
+		cpuFreqSupported: cpufreq.IsCPUFreqSupported,
+		cpuFreqAccess:    cpufreq.HasWriteAccess,
+		thpSupported:     thp.IsTHPSupported,
+		thpAccess:        thp.HasWriteAccess,
+		freeDiskBytes:    probeFreeDiskBytes,
+	}
+}
+
+func runCheckEnv(cmd *cobra.Command, _ []string) error {
+	cfg := &config.Config{}
+
+	if len(cfgFiles) > 0 {
+		loaded, err := config.Load(cfgFiles...)
+		if err != nil {
+			return withExitCode(runner.ExitCodeConfigError, fmt.Errorf("loading config: %w", err))
+		}
+
+		cfg = loaded
+	}
+
+	checks := runEnvChecks(cmd.Context(), cfg, len(cfgFiles) > 0, defaultEnvProbes())
+
+	if err := writeEnvReport(cmd.OutOrStdout(), checks); err != nil {
+		return err
+	}
+
+	for _, c := range checks {
+		if c.Status == checkFail {
+			return fmt.Errorf("host is not ready: %s check failed", c.Name)
+		}
+	}
+
+	return nil
+}
+
+// runEnvChecks probes every capability and reports it against the features
+// of cfg. A missing capability fails only if cfg uses a feature needing it
+// and strict is set, i.e. a config was given.
+func runEnvChecks(ctx context.Context, cfg *config.Config, strict bool, p envProbes) []envCheck {
+	// missing reports a missing capability.
+	missing := func(needed bool) checkStatus {
+		if strict && needed {
+			return checkFail
+		}
+
+		return checkWarn
+	}
+
+	checks := make([]envCheck, 0, 8)
+
+	// Container runtime, needed by every run.
+	rt := cfg.GetContainerRuntime()
+	if err := p.containerRuntime(ctx, rt); err != nil {
+		checks = append(checks, envCheck{
+			Name: "container runtime", Feature: "container_runtime", Status: missing(true),
+			Detail: fmt.Sprintf("%s: %v", rt, err),
+		})
+	} else {
+		checks = append(checks, envCheck{
+			Name: "container runtime", Feature: "container_runtime", Status: checkPass, Detail: rt,
+		})
+	}
+
+	// Dropping caches, via the configured command or the drop_caches file.
+	dropCaches := envCheck{Name: "drop caches", Feature: "drop_memory_caches", Status: checkPass}
+	needsDropCaches := usesDropMemoryCaches(cfg)
+
+	if command := cfg.Runner.DropCachesCommand; len(command) > 0 {
+		dropCaches.Detail = "drop_caches_command " + command[0]
+
+		if _, err := p.lookPath(command[0]); err != nil {
+			dropCaches.Status = missing(needsDropCaches)
+			dropCaches.Detail = err.Error()
+		}
+	} else {
+		dropCaches.Detail = cfg.GetDropCachesPath()
+
+		if err := p.writeAccess(cfg.GetDropCachesPath()); err != nil {
+			dropCaches.Status = missing(needsDropCaches)
+			dropCaches.Detail = err.Error() + " (set drop_caches_command to run unprivileged)"
+		}
+	}
+
+	checks = append(checks, dropCaches)
+
+	// CPU frequency control.
+	cpuFreq := envCheck{
+		Name: "cpu frequency", Feature: "resource_limits.cpu_freq/cpu_turboboost/cpu_freq_governor",
+		Status: checkPass, Detail: cfg.GetCPUSysfsPath(),
+	}
+
+	if !p.cpuFreqSupported(cfg.GetCPUSysfsPath()) {
+		cpuFreq.Status = missing(needsCPUFreqManager(cfg))
+		cpuFreq.Detail = "cpufreq subsystem not available in " + cfg.GetCPUSysfsPath()
+	} else if err := p.cpuFreqAccess(cfg.GetCPUSysfsPath()); err != nil {
+		cpuFreq.Status = missing(needsCPUFreqManager(cfg))
+		cpuFreq.Detail = err.Error()
+	}
+
+	checks = append(checks, cpuFreq)
+
+	// Transparent huge pages control.
+	thpCheck := envCheck{
+		Name: "transparent huge pages", Feature: "resource_limits.transparent_hugepage",
+		Status: checkPass, Detail: cfg.GetTHPSysfsPath(),
+	}
+
+	if !p.thpSupported(cfg.GetTHPSysfsPath()) {
+		thpCheck.Status = missing(needsTHPManager(cfg))
+		thpCheck.Detail = "THP not available in " + cfg.GetTHPSysfsPath()
+	} else if err := p.thpAccess(cfg.GetTHPSysfsPath()); err != nil {
+		thpCheck.Status = missing(needsTHPManager(cfg))
+		thpCheck.Detail = err.Error()
+	}
+
+	checks = append(checks, thpCheck)
+
+	// Tooling of the datadir methods.
+	methods := dataDirMethods(cfg)
+
+	for _, tool := range []struct{ bin, method string }{
+		{bin: "zfs", method: "zfs"},
+		{bin: "fuse-overlayfs", method: "fuse-overlayfs"},
+	} {
+		c := envCheck{Name: tool.bin, Feature: "datadir.method: " + tool.method, Status: checkPass}
+
+		if path, err := p.lookPath(tool.bin); err != nil {
+			c.Status = missing(methods[tool.method])
+			c.Detail = tool.bin + " not found in PATH"
+		} else {
+			c.Detail = path
+		}
+
+		checks = append(checks, c)
+	}
+
+	// Free disk space of the directories benchmarkoor writes to.
+	for _, dir := range []struct{ name, feature, path string }{
+		{name: "tmp_datadir", feature: "datadir copies", path: tmpDataDirPath(cfg)},
+		{name: "tmp_cachedir", feature: "test sources and caches", path: tmpCacheDirPath(cfg)},
+		{name: "results_dir", feature: "results", path: resultsDirPath(cfg)},
+	} {
+		c := envCheck{Name: "disk space " + dir.name, Feature: dir.feature}
+
+		free, err := p.freeDiskBytes(dir.path)
+
+		switch {
+		case err != nil:
+			c.Status = checkWarn
+			c.Detail = err.Error()
+		case free < minFreeDiskBytes:
+			c.Status = checkWarn
+			c.Detail = fmt.Sprintf("%s: only %s free", dir.path, formatGiB(free))
+		default:
+			c.Status = checkPass
+			c.Detail = fmt.Sprintf("%s: %s free", dir.path, formatGiB(free))
+		}
+
+		checks = append(checks, c)
+	}
+
+	return checks
+}
+
+// writeEnvReport writes the readiness report as a table.
+func writeEnvReport(w io.Writer, checks []envCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "STATUS\tCHECK\tNEEDED FOR\tDETAIL")
+
+	for _, c := range checks {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Status, c.Name, c.Feature, c.Detail)
+	}
+
+	return tw.Flush()
+}
+
+// usesDropMemoryCaches returns true if any instance drops memory caches.
+func usesDropMemoryCaches(cfg *config.Config) bool {
+	for _, instance := range cfg.Runner.Instances {
+		if value := cfg.GetDropMemoryCaches(&instance); value != "" && value != "disabled" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dataDirMethods returns the set of datadir methods the config uses.
+func dataDirMethods(cfg *config.Config) map[string]bool {
+	methods := make(map[string]bool, 2)
+
+	for _, dd := range cfg.Runner.Client.DataDirs {
+		if dd != nil {
+			methods[dd.Method] = true
+		}
+	}
+
+	for _, instance := range cfg.Runner.Instances {
+		if instance.DataDir != nil {
+			methods[instance.DataDir.Method] = true
+		}
+	}
+
+	return methods
+}
+
+// tmpDataDirPath returns the directory datadirs are copied to.
+func tmpDataDirPath(cfg *config.Config) string {
+	if cfg.Runner.Directories.TmpDataDir != "" {
+		return cfg.Runner.Directories.TmpDataDir
+	}
+
+	return os.TempDir()
+}
+
+// tmpCacheDirPath returns the executor cache directory.
+func tmpCacheDirPath(cfg *config.Config) string {
+	if cfg.Runner.Directories.TmpCacheDir != "" {
+		return cfg.Runner.Directories.TmpCacheDir
+	}
+
+	dir, err := getExecutorCacheDir()
+	if err != nil {
+		return os.TempDir()
+	}
+
+	return dir
+}
+
+// resultsDirPath returns the results directory.
+func resultsDirPath(cfg *config.Config) string {
+	if cfg.Runner.Benchmark.ResultsDir != "" {
+		return cfg.Runner.Benchmark.ResultsDir
+	}
+
+	return config.DefaultResultsDir
+}
+
+// formatGiB formats a byte count in GiB.
+func formatGiB(bytes uint64) string {
+	return fmt.Sprintf("%.1f GiB", float64(bytes)/(1<<30))
+}
+
+// probeContainerRuntime checks that the container runtime is reachable.
+func probeContainerRuntime(ctx context.Context, runtime string) error {
+	var (
+		mgr docker.ContainerManager
+		err error
+	)
+
+	switch runtime {
+	case "podman":
+		mgr, err = podman.NewManager(log)
+	default:
+		mgr, err = docker.NewManager(log)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := mgr.Start(ctx); err != nil {
+		return err
+	}
+
+	return mgr.Stop()
+}
+
+// probeWriteAccess checks that path can be opened for writing, without
+// writing to it.
+func probeWriteAccess(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		if os.IsPermission(err) {
+			return fmt.Errorf("no write permission to %s (requires root)", path)
+		}
+
+		return fmt.Errorf("accessing %s: %w", path, err)
+	}
+
+	return file.Close()
+}
+
+// probeFreeDiskBytes returns the space available to unprivileged users on
+// the filesystem of path, or of its nearest existing parent if path does
+// not exist yet.
+func probeFreeDiskBytes(path string) (uint64, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		if _, err := os.Stat(abs); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			break
+		}
+
+		abs = parent
+	}
+
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(abs, &st); err != nil {
+		return 0, fmt.Errorf("statfs %s: %w", abs, err)
+	}
+
+	return st.Bavail * uint64(st.Bsize), nil //nolint:gosec // Block size is positive.
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readyProbes returns probes of a host with every capability.
+func readyProbes() envProbes {
+	return envProbes{
+		containerRuntime: func(context.Context, string) error { return nil },
+		writeAccess:      func(string) error { return nil },
+		lookPath:         func(file string) (string, error) { return "/usr/sbin/" + file, nil },
+		cpuFreqSupported: func(string) bool { return true },
+		cpuFreqAccess:    func(string) error { return nil },
+		thpSupported:     func(string) bool { return true },
+		thpAccess:        func(string) error { return nil },
+		freeDiskBytes:    func(string) (uint64, error) { return 100 << 30, nil },
+	}
+}
+
+// unprivilegedProbes returns probes of a host without root and ZFS.
+func unprivilegedProbes() envProbes {
+	p := readyProbes()
+	p.writeAccess = func(path string) error { return errors.New("no write permission to " + path) }
+	p.cpuFreqAccess = func(string) error { return errors.New("no write permission") }
+	p.thpAccess = func(string) error { return errors.New("no write permission") }
+	p.lookPath = func(file string) (string, error) {
+		if file == "zfs" {
+			return "", errors.New("not found")
+		}
+
+		return "/usr/bin/" + file, nil
+	}
+
+	return p
+}
+
+// statuses returns the status of each check by name.
+func statuses(checks []envCheck) map[string]checkStatus {
+	out := make(map[string]checkStatus, len(checks))
+	for _, c := range checks {
+		out[c.Name] = c.Status
+	}
+
+	return out
+}
+
+func TestRunEnvChecks_Ready(t *testing.T) {
+	checks := runEnvChecks(t.Context(), &config.Config{}, false, readyProbes())
+
+	for _, c := range checks {
+		assert.Equal(t, checkPass, c.Status, c.Name)
+	}
+}
+
+func TestRunEnvChecks_MissingCapabilities(t *testing.T) {
+	enabled := true
+
+	cfg := &config.Config{}
+	cfg.Runner.Client.Config.DropMemoryCaches = "tests"
+	cfg.Runner.Client.Config.ResourceLimits = &config.ResourceLimits{CPUTurboBoost: &enabled}
+	cfg.Runner.Instances = []config.ClientInstance{
+		{ID: "geth", Client: "geth", DataDir: &config.DataDirConfig{SourceDir: "/data", Method: "zfs"}},
+	}
+
+	// Without a config, nothing is known to be needed.
+	got := statuses(runEnvChecks(t.Context(), &config.Config{}, false, unprivilegedProbes()))
+	assert.Equal(t, checkWarn, got["drop caches"])
+	assert.Equal(t, checkWarn, got["cpu frequency"])
+	assert.Equal(t, checkWarn, got["zfs"])
+
+	// Features the config uses fail, others only warn.
+	got = statuses(runEnvChecks(t.Context(), cfg, true, unprivilegedProbes()))
+	assert.Equal(t, checkPass, got["container runtime"])
+	assert.Equal(t, checkFail, got["drop caches"])
+	assert.Equal(t, checkFail, got["cpu frequency"])
+	assert.Equal(t, checkWarn, got["transparent huge pages"])
+	assert.Equal(t, checkFail, got["zfs"])
+	assert.Equal(t, checkPass, got["fuse-overlayfs"])
+}
+
+func TestRunEnvChecks_DropCachesCommand(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Runner.DropCachesCommand = []string{"sudo", "-n", "/usr/local/sbin/drop-caches"}
+	cfg.Runner.Instances = []config.ClientInstance{{ID: "geth", Client: "geth", DropMemoryCaches: "steps"}}
+
+	// The command replaces the drop_caches file permission check.
+	got := statuses(runEnvChecks(t.Context(), cfg, true, unprivilegedProbes()))
+	assert.Equal(t, checkPass, got["drop caches"])
+}
+
+func TestRunEnvChecks_RuntimeAndDiskSpace(t *testing.T) {
+	p := readyProbes()
+	p.containerRuntime = func(_ context.Context, runtime string) error {
+		return errors.New(runtime + " socket not found")
+	}
+	p.freeDiskBytes = func(string) (uint64, error) { return 1 << 30, nil }
+
+	cfg := &config.Config{}
+	cfg.Runner.ContainerRuntime = "podman"
+
+	checks := runEnvChecks(t.Context(), cfg, true, p)
+	got := statuses(checks)
+	assert.Equal(t, checkFail, got["container runtime"])
+	assert.Equal(t, checkWarn, got["disk space tmp_datadir"])
+
+	var buf bytes.Buffer
+	require.NoError(t, writeEnvReport(&buf, checks))
+	assert.Contains(t, buf.String(), "podman socket not found")
+	assert.Contains(t, buf.String(), "only 1.0 GiB free")
+}
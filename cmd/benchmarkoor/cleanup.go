@@ -11,7 +11,9 @@ import (
 	"github.com/ethpandaops/benchmarkoor/pkg/cpufreq"
 	"github.com/ethpandaops/benchmarkoor/pkg/datadir"
 	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/irq"
 	"github.com/ethpandaops/benchmarkoor/pkg/podman"
+	"github.com/ethpandaops/benchmarkoor/pkg/thp"
 	"github.com/spf13/cobra"
 )
 
@@ -27,7 +29,8 @@ Filesystem resources that may be left behind if the process was killed:
   - ZFS clones and snapshots
   - OverlayFS mounts and temp directories
   - fuse-overlayfs mounts and temp directories
-  - CPU frequency state files (restores original CPU settings)`,
+  - CPU frequency state files (restores original CPU settings)
+  - THP and IRQ affinity state files (restore the original THP mode and interrupt affinity)`,
 	RunE: runCleanup,
 }
 
@@ -113,8 +116,21 @@ func performCleanup(ctx context.Context, managers []docker.ContainerManager, for
 		log.WithError(err).Warn("Failed to list CPU frequency state files")
 	}
 
+	// List orphaned THP state files.
+	thpStateFiles, err := thp.ListOrphanedStateFiles(getCPUFreqCacheDir())
+	if err != nil {
+		log.WithError(err).Warn("Failed to list THP state files")
+	}
+
+	// List orphaned IRQ affinity state files.
+	irqStateFiles, err := irq.ListOrphanedStateFiles(getCPUFreqCacheDir())
+	if err != nil {
+		log.WithError(err).Warn("Failed to list IRQ affinity state files")
+	}
+
 	if len(containers) == 0 && len(volumes) == 0 && len(zfsResources) == 0 &&
-		len(overlayMounts) == 0 && len(cpufreqStateFiles) == 0 {
+		len(overlayMounts) == 0 && len(cpufreqStateFiles) == 0 && len(thpStateFiles) == 0 &&
+		len(irqStateFiles) == 0 {
 		log.Info("No benchmarkoor resources found")
 
 		return nil
@@ -161,6 +177,22 @@ func performCleanup(ctx context.Context, managers []docker.ContainerManager, for
 		}
 	}
 
+	if len(thpStateFiles) > 0 {
+		fmt.Printf("\nTHP state files to be restored and removed (%d):\n", len(thpStateFiles))
+
+		for _, sf := range thpStateFiles {
+			fmt.Printf("  - %s (created: %s)\n", sf.Path, sf.Timestamp.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	if len(irqStateFiles) > 0 {
+		fmt.Printf("\nIRQ affinity state files to be restored and removed (%d):\n", len(irqStateFiles))
+
+		for _, sf := range irqStateFiles {
+			fmt.Printf("  - %s (created: %s)\n", sf.Path, sf.Timestamp.Format("2006-01-02 15:04:05"))
+		}
+	}
+
 	fmt.Println()
 
 	// Prompt for confirmation if not forced.
@@ -223,6 +255,24 @@ func performCleanup(ctx context.Context, managers []docker.ContainerManager, for
 		}
 	}
 
+	// Restore the THP mode from orphaned state files and remove them.
+	if len(thpStateFiles) > 0 {
+		if err := thp.CleanupOrphanedTHPState(
+			ctx, log, thpStateFiles, thp.DefaultSysfsPath,
+		); err != nil {
+			log.WithError(err).Warn("Failed to cleanup THP state files")
+		}
+	}
+
+	// Restore the interrupt affinity from orphaned state files and remove them.
+	if len(irqStateFiles) > 0 {
+		if err := irq.CleanupOrphanedIRQState(
+			ctx, log, irqStateFiles, irq.DefaultProcPath,
+		); err != nil {
+			log.WithError(err).Warn("Failed to cleanup IRQ affinity state files")
+		}
+	}
+
 	log.Info("Cleanup completed")
 
 	return nil
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
+	"github.com/ethpandaops/benchmarkoor/pkg/executor"
+	"github.com/ethpandaops/benchmarkoor/pkg/runner"
+	"github.com/spf13/cobra"
+)
+
+// defaultPerTestEstimate is the duration assumed for tests without a
+// baseline duration.
+const defaultPerTestEstimate = 10 * time.Second
+
+var (
+	listTestsJSON      bool
+	listTestsCountOnly bool
+	listTestsBaseline  string
+	listTestsPerTest   time.Duration
+	listTestsOffline   bool
+)
+
+var listTestsCmd = &cobra.Command{
+	Use:   "list-tests",
+	Short: "List the tests the configured source and filter resolve to",
+	Long: `Prepare the configured test source (cloning, downloading or extracting it
+as a run would) and print the pre-run steps and the tests matching
+runner.benchmark.tests.filter, in execution order. No client is started and
+no test is executed.
+
+With --count-only, print the number of tests and RPC calls and an estimate
+of the run time instead. Tests found in the --baseline run are estimated by
+the time their steps took there, other tests by --per-test-estimate.`,
+	RunE: runListTests,
+}
+
+func init() {
+	rootCmd.AddCommand(listTestsCmd)
+	listTestsCmd.Flags().BoolVar(&listTestsJSON, "json", false,
+		"Print the tests as JSON")
+	listTestsCmd.Flags().BoolVar(&listTestsCountOnly, "count-only", false,
+		"Print the test count, RPC call count and estimated duration instead of the tests")
+	listTestsCmd.Flags().StringVar(&listTestsBaseline, "baseline", "",
+		"Run directory (or its result.json) whose per-test durations --count-only estimates from")
+	listTestsCmd.Flags().DurationVar(&listTestsPerTest, "per-test-estimate", defaultPerTestEstimate,
+		"Estimated duration of tests not found in the baseline")
+	listTestsCmd.Flags().BoolVar(&listTestsOffline, "offline", false,
+		"Fail instead of downloading EEST fixtures that are not cached or local")
+}
+
+func runListTests(cmd *cobra.Command, _ []string) error {
+	if len(cfgFiles) == 0 {
+		return fmt.Errorf("config file is required (use --config)")
+	}
+
+	if !listTestsCountOnly && listTestsBaseline != "" {
+		return fmt.Errorf("--baseline requires --count-only")
+	}
+
+	// Read the baseline first so a bad path fails before preparing the source.
+	var baseline *executor.RunResult
+
+	if listTestsBaseline != "" {
+		var err error
+
+		baseline, err = executor.ReadRunResult(listTestsBaseline)
+		if err != nil {
+			return fmt.Errorf("reading baseline: %w", err)
+		}
+	}
+
+	// Keep stdout parseable when printing JSON.
+	if listTestsJSON {
+		log.SetOutput(os.Stderr)
+	}
+
+	cfg, err := config.Load(cfgFiles...)
+	if err != nil {
+		return withExitCode(runner.ExitCodeConfigError, fmt.Errorf("loading config: %w", err))
+	}
+
+	if !cfg.Runner.Benchmark.Tests.Source.IsConfigured() {
+		return withExitCode(runner.ExitCodeConfigError,
+			fmt.Errorf("no test source configured (runner.benchmark.tests.source)"))
+	}
+
+	cacheDir := cfg.Runner.Directories.TmpCacheDir
+	if cacheDir == "" {
+		cacheDir, err = getExecutorCacheDir()
+		if err != nil {
+			return fmt.Errorf("getting cache directory: %w", err)
+		}
+	}
+
+	maxAttempts, backoff, maxBackoff := cfg.GetDownloadRetries()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	listing, err := executor.ListTests(ctx, log, &executor.Config{
+		Source:          &cfg.Runner.Benchmark.Tests.Source,
+		Filter:          cfg.Runner.Benchmark.Tests.Filter,
+		AllowEmptyGlobs: cfg.Runner.Benchmark.Tests.AllowEmptyGlobs,
+		CacheDir:        cacheDir,
+		GitHubToken:     cfg.Runner.GitHubToken,
+		DownloadRetry: download.RetryPolicy{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+			MaxBackoff:  maxBackoff,
+		},
+		Offline: listTestsOffline,
+	})
+	if err != nil {
+		return fmt.Errorf("listing tests: %w", err)
+	}
+
+	if listTestsCountOnly {
+		estimate := executor.EstimateSuite(listing, baseline, listTestsPerTest)
+
+		return writeSuiteEstimate(cmd.OutOrStdout(), estimate, listTestsJSON)
+	}
+
+	return writeTestListing(cmd.OutOrStdout(), listing, listTestsJSON)
+}
+
+// writeTestListing writes the listing as JSON or as one pre-run step or
+// test name per line.
+func writeTestListing(w io.Writer, listing *executor.TestListing, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(listing)
+	}
+
+	if len(listing.PreRunSteps) > 0 {
+		if _, err := fmt.Fprintln(w, "Pre-run steps:"); err != nil {
+			return err
+		}
+
+		for _, step := range listing.PreRunSteps {
+			if _, err := fmt.Fprintf(w, "  %s\n", step); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "Tests (%d):\n", len(listing.Tests)); err != nil {
+		return err
+	}
+
+	for _, test := range listing.Tests {
+		if _, err := fmt.Fprintf(w, "  %s\n", test.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSuiteEstimate writes the estimate as JSON or as one line per figure.
+func writeSuiteEstimate(w io.Writer, estimate *executor.SuiteEstimate, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(estimate)
+	}
+
+	_, err := fmt.Fprintf(w,
+		"Tests: %d\nPre-run steps: %d\nRPC calls: %d\nEstimated duration: %s (%d of %d tests from baseline)\n",
+		estimate.Tests, estimate.PreRunSteps, estimate.RPCCalls,
+		estimate.EstimatedDuration.Round(time.Second), estimate.BaselineTests, estimate.Tests,
+	)
+
+	return err
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTestListing(t *testing.T) {
+	listing := &executor.TestListing{
+		PreRunSteps:    []string{"prerun/funding.txt"},
+		PreRunRPCCalls: 2,
+		Tests: []executor.ListedTest{
+			{Name: "bn128_add/test.txt", Test: "testing/bn128_add/test.txt", RPCCalls: 3},
+			{
+				Name: "bn128_mul/test.txt", Setup: "setup/bn128_mul/test.txt", Test: "testing/bn128_mul/test.txt",
+				RPCCalls: 5,
+			},
+		},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeTestListing(&buf, listing, true))
+		assert.JSONEq(t, `{
+			"pre_run_steps": ["prerun/funding.txt"],
+			"pre_run_rpc_calls": 2,
+			"tests": [
+				{"name": "bn128_add/test.txt", "test": "testing/bn128_add/test.txt", "rpc_calls": 3},
+				{
+					"name": "bn128_mul/test.txt", "setup": "setup/bn128_mul/test.txt",
+					"test": "testing/bn128_mul/test.txt", "rpc_calls": 5
+				}
+			]
+		}`, buf.String())
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeTestListing(&buf, listing, false))
+		assert.Equal(t, "Pre-run steps:\n  prerun/funding.txt\nTests (2):\n  bn128_add/test.txt\n  bn128_mul/test.txt\n",
+			buf.String())
+	})
+
+	t.Run("text without pre-run steps", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeTestListing(&buf, &executor.TestListing{}, false))
+		assert.Equal(t, "Tests (0):\n", buf.String())
+	})
+}
+
+func TestWriteSuiteEstimate(t *testing.T) {
+	estimate := &executor.SuiteEstimate{
+		Tests:             3,
+		PreRunSteps:       1,
+		RPCCalls:          120,
+		EstimatedDuration: 95*time.Second + 400*time.Millisecond,
+		BaselineTests:     2,
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeSuiteEstimate(&buf, estimate, true))
+		assert.JSONEq(t, `{
+			"tests": 3,
+			"pre_run_steps": 1,
+			"rpc_calls": 120,
+			"estimated_duration_ns": 95400000000,
+			"baseline_tests": 2
+		}`, buf.String())
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeSuiteEstimate(&buf, estimate, false))
+		assert.Equal(t,
+			"Tests: 3\nPre-run steps: 1\nRPC calls: 120\nEstimated duration: 1m35s (2 of 3 tests from baseline)\n",
+			buf.String())
+	})
+}
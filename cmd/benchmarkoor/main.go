@@ -2,14 +2,17 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
 	"strings"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/runner"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // utcFormatter wraps a logrus formatter and converts timestamps to UTC.
@@ -23,42 +26,97 @@ func (f *utcFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return f.formatter.Format(entry)
 }
 
+// newTextFormatter returns the default log formatter.
+func newTextFormatter(disableColors bool) logrus.Formatter {
+	return &utcFormatter{
+		formatter: &logrus.TextFormatter{
+			FullTimestamp:   true,
+			TimestampFormat: config.LogTimestampFormat,
+			DisableColors:   disableColors,
+		},
+	}
+}
+
+// colorsDisabled reports whether log output to out should be uncolored:
+// when --no-color is set, NO_COLOR is set to a non-empty value
+// (https://no-color.org) or out is not a terminal.
+func colorsDisabled(out *os.File) bool {
+	return noColor || os.Getenv("NO_COLOR") != "" || !isTerminal(out)
+}
+
+// isTerminal reports whether f is a terminal. It is a variable so tests can
+// stub it.
+var isTerminal = func(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
 // consistentFormatter formats log lines as: "$prefix $TIMESTAMP $LEVEL | $msg $fields\n".
 type consistentFormatter struct {
-	prefix string // e.g. "🔵"
+	prefix  string // e.g. "🔵"
+	palette palette
 }
 
-// ANSI color codes for log levels.
-const (
-	colorReset  = "\033[0m"
-	colorRed    = "\033[31m"
-	colorGreen  = "\033[32m"
-	colorYellow = "\033[33m"
-	colorCyan   = "\033[36m"
-	colorWhite  = "\033[37m"
-)
+// palette holds the ANSI escape codes used to color log levels. The zero
+// palette disables colors.
+type palette struct {
+	reset, red, green, yellow, cyan, white string
+}
+
+// ansiPalette colors log levels with ANSI escape codes.
+var ansiPalette = palette{
+	reset:  "\033[0m",
+	red:    "\033[31m",
+	green:  "\033[32m",
+	yellow: "\033[33m",
+	cyan:   "\033[36m",
+	white:  "\033[37m",
+}
+
+// levelColor returns the color for a log level.
+func (p palette) levelColor(level logrus.Level) string {
+	switch level {
+	case logrus.TraceLevel:
+		return p.white
+	case logrus.DebugLevel:
+		return p.cyan
+	case logrus.InfoLevel:
+		return p.green
+	case logrus.WarnLevel:
+		return p.yellow
+	default:
+		return p.red
+	}
+}
 
-// levelLabel contains the 4-char abbreviation and ANSI color for a log level.
-type levelLabel struct {
-	text  string
-	color string
+// newConsistentFormatter returns a consistentFormatter, colored unless
+// disableColors is set.
+func newConsistentFormatter(prefix string, disableColors bool) *consistentFormatter {
+	f := &consistentFormatter{prefix: prefix}
+	if !disableColors {
+		f.palette = ansiPalette
+	}
+
+	return f
 }
 
-// shortLevel maps logrus levels to colored 4-character abbreviations.
-var shortLevel = map[logrus.Level]levelLabel{
-	logrus.TraceLevel: {text: "TRAC", color: colorWhite},
-	logrus.DebugLevel: {text: "DEBG", color: colorCyan},
-	logrus.InfoLevel:  {text: "INFO", color: colorGreen},
-	logrus.WarnLevel:  {text: "WARN", color: colorYellow},
-	logrus.ErrorLevel: {text: "ERRO", color: colorRed},
-	logrus.FatalLevel: {text: "FATL", color: colorRed},
-	logrus.PanicLevel: {text: "PANC", color: colorRed},
+// shortLevel maps logrus levels to 4-character abbreviations.
+var shortLevel = map[logrus.Level]string{
+	logrus.TraceLevel: "TRAC",
+	logrus.DebugLevel: "DEBG",
+	logrus.InfoLevel:  "INFO",
+	logrus.WarnLevel:  "WARN",
+	logrus.ErrorLevel: "ERRO",
+	logrus.FatalLevel: "FATL",
+	logrus.PanicLevel: "PANC",
 }
 
 func (f *consistentFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	ts := entry.Time.UTC().Format(config.LogTimestampFormat)
-	lbl := shortLevel[entry.Level]
-	level := lbl.color + lbl.text + colorReset
+	level := shortLevel[entry.Level]
+
+	if color := f.palette.levelColor(entry.Level); color != "" {
+		level = color + level + f.palette.reset
+	}
 
 	var buf bytes.Buffer
 
@@ -92,25 +150,48 @@ var (
 
 var (
 	cfgFiles []string
+	envFiles []string
 	logLevel string
+	noColor  bool
 	log      *logrus.Logger
 )
 
 func main() {
 	log = logrus.New()
 	log.SetOutput(os.Stdout)
-	log.SetFormatter(&utcFormatter{
-		formatter: &logrus.TextFormatter{
-			FullTimestamp:   true,
-			TimestampFormat: config.LogTimestampFormat,
-		},
-	})
+	log.SetFormatter(newTextFormatter(false))
 
 	if err := rootCmd.Execute(); err != nil {
-		log.WithError(err).Fatal("Failed to execute command")
+		log.WithError(err).Error("Failed to execute command")
+		os.Exit(exitCode(err))
 	}
 }
 
+// exitError carries the process exit code for a command error.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode wraps err so the process exits with code.
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code carried by err, or runner.ExitCodeFailure.
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	return runner.ExitCodeFailure
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "benchmarkoor",
 	Short: "Ethereum execution layer client benchmarking tool",
@@ -126,6 +207,23 @@ through the Engine API.`,
 
 		log.SetLevel(level)
 
+		if colorsDisabled(os.Stdout) {
+			log.SetFormatter(newTextFormatter(true))
+		}
+
+		// Env files must be loaded before any command calls config.Load.
+		for _, path := range envFiles {
+			if err := config.LoadEnvFile(path); err != nil {
+				return err
+			}
+		}
+
+		if pprofListen != "" {
+			if _, err := startPprofServer(pprofListen); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	},
 }
@@ -142,9 +240,15 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.PersistentFlags().StringArrayVar(&cfgFiles, "config", nil,
-		"config file path (can be specified multiple times)")
+		"config file path or http(s) URL (can be specified multiple times)")
+	rootCmd.PersistentFlags().StringArrayVar(&envFiles, "env-file", nil,
+		"dotenv file to load before expanding config variables (can be specified multiple times)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info",
 		"log level ("+strings.Join(logLevels(), ", ")+")")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false,
+		"disable colored log output (also disabled by NO_COLOR or when stdout is not a terminal)")
+	rootCmd.PersistentFlags().StringVar(&pprofListen, "pprof-listen", "",
+		"serve net/http/pprof on this address, e.g. :6060 (binds to localhost when no host is given)")
 
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatEntry(t *testing.T, f logrus.Formatter, level logrus.Level) string {
+	t.Helper()
+
+	out, err := f.Format(&logrus.Entry{
+		Logger:  logrus.New(),
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   level,
+		Message: "hello",
+		Data:    logrus.Fields{"instance": "geth"},
+	})
+	require.NoError(t, err)
+
+	return string(out)
+}
+
+func TestConsistentFormatter(t *testing.T) {
+	t.Run("colors", func(t *testing.T) {
+		out := formatEntry(t, newConsistentFormatter("🔵", false), logrus.WarnLevel)
+		assert.Contains(t, out, ansiPalette.yellow+"WARN"+ansiPalette.reset+" | hello instance=geth\n")
+	})
+
+	t.Run("no colors", func(t *testing.T) {
+		for _, level := range logrus.AllLevels {
+			out := formatEntry(t, newConsistentFormatter("🔵", true), level)
+			assert.NotContains(t, out, "\033[", level.String())
+			assert.True(t, strings.HasPrefix(out, "🔵 "), out)
+			assert.Contains(t, out, shortLevel[level]+" | hello instance=geth\n")
+		}
+	})
+}
+
+func TestTextFormatterNoColors(t *testing.T) {
+	f := newTextFormatter(true)
+
+	// DisableColors wins over ForceColors, which stands in for a terminal.
+	f.(*utcFormatter).formatter.(*logrus.TextFormatter).ForceColors = true
+
+	assert.NotContains(t, formatEntry(t, f, logrus.ErrorLevel), "\033[")
+}
+
+func TestColorsDisabled(t *testing.T) {
+	// A regular file is never a terminal.
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	t.Setenv("NO_COLOR", "")
+	assert.True(t, colorsDisabled(file), "not a terminal")
+
+	orig := isTerminal
+	isTerminal = func(*os.File) bool { return true }
+
+	t.Cleanup(func() { isTerminal = orig })
+
+	assert.False(t, colorsDisabled(file), "terminal")
+
+	t.Setenv("NO_COLOR", "1")
+	assert.True(t, colorsDisabled(file), "NO_COLOR set")
+
+	t.Setenv("NO_COLOR", "")
+
+	noColor = true
+
+	t.Cleanup(func() { noColor = false })
+
+	assert.True(t, colorsDisabled(file), "--no-color set")
+}
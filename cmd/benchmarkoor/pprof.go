@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// pprofListen is the address of the optional pprof server (empty = disabled).
+var pprofListen string
+
+// pprofAddr returns the address to bind the pprof server to. A missing host
+// binds to localhost rather than all interfaces.
+func pprofAddr(listen string) (string, error) {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return "", fmt.Errorf("invalid --pprof-listen address %q: %w", listen, err)
+	}
+
+	if host == "" {
+		host = "localhost"
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// isLoopbackHost reports whether host only accepts local connections.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// startPprofServer serves the net/http/pprof handlers on listen and returns
+// the bound address. The server runs until the process exits.
+func startPprofServer(listen string) (net.Addr, error) {
+	addr, err := pprofAddr(listen)
+	if err != nil {
+		return nil, err
+	}
+
+	if host, _, _ := net.SplitHostPort(addr); !isLoopbackHost(host) {
+		log.WithField("address", addr).Warn("pprof server is reachable from other hosts")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("starting pprof server: %w", err)
+	}
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Warn("pprof server stopped")
+		}
+	}()
+
+	log.WithField("address", ln.Addr().String()).Info("pprof server listening")
+
+	return ln.Addr(), nil
+}
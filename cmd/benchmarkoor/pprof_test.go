@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPprofAddr(t *testing.T) {
+	tests := []struct {
+		listen string
+		want   string
+	}{
+		{listen: ":6060", want: "localhost:6060"},
+		{listen: "127.0.0.1:6060", want: "127.0.0.1:6060"},
+		{listen: "0.0.0.0:6060", want: "0.0.0.0:6060"},
+	}
+
+	for _, tt := range tests {
+		addr, err := pprofAddr(tt.listen)
+		require.NoError(t, err, tt.listen)
+		assert.Equal(t, tt.want, addr, tt.listen)
+	}
+
+	_, err := pprofAddr("6060")
+	require.Error(t, err)
+}
+
+func TestStartPprofServer(t *testing.T) {
+	log = logrus.New()
+	log.SetOutput(io.Discard)
+
+	addr, err := startPprofServer("127.0.0.1:0")
+	require.NoError(t, err)
+
+	resp, err := http.Get("http://" + addr.String() + "/debug/pprof/")
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "goroutine")
+}
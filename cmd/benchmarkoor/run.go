@@ -3,21 +3,27 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/client"
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
 	"github.com/ethpandaops/benchmarkoor/pkg/cpufreq"
 	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
 	"github.com/ethpandaops/benchmarkoor/pkg/executor"
 	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
 	"github.com/ethpandaops/benchmarkoor/pkg/podman"
 	"github.com/ethpandaops/benchmarkoor/pkg/runner"
+	"github.com/ethpandaops/benchmarkoor/pkg/thp"
+	"github.com/ethpandaops/benchmarkoor/pkg/tracing"
 	"github.com/ethpandaops/benchmarkoor/pkg/upload"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -27,8 +33,21 @@ var (
 	limitInstanceIDs     []string
 	limitInstanceClients []string
 	metadataLabels       []string
+	outputDir            string
+	tmpDataDir           string
+	tmpCacheDir          string
+	jsonSummary          bool
+	rerunFailed          string
+	quiet                bool
+	progressEvery        int
+	progressInterval     time.Duration
+	offline              bool
+	runDirsFile          string
 )
 
+// tracingShutdownTimeout bounds how long the run waits to flush spans.
+const tracingShutdownTimeout = 10 * time.Second
+
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run the benchmark",
@@ -44,6 +63,26 @@ func init() {
 		"Limit to instances with these client types (comma-separated or repeated flag)")
 	runCmd.Flags().StringSliceVar(&metadataLabels, "metadata.label", nil,
 		"Add metadata label as key=value (can be repeated)")
+	runCmd.Flags().StringVar(&outputDir, "output-dir", "",
+		"Override runner.benchmark.results_dir")
+	runCmd.Flags().StringVar(&tmpDataDir, "tmp-datadir", "",
+		"Override runner.directories.tmp_datadir")
+	runCmd.Flags().StringVar(&tmpCacheDir, "tmp-cachedir", "",
+		"Override runner.directories.tmp_cachedir")
+	runCmd.Flags().BoolVar(&jsonSummary, "json", false,
+		"Print the end-of-run summary as JSON instead of a table")
+	runCmd.Flags().StringVar(&rerunFailed, "rerun-failed", "",
+		"Run only the tests that failed in this previous run directory (or its result.json)")
+	runCmd.Flags().BoolVar(&quiet, "quiet", false,
+		"Log each RPC call and progress line at debug instead of info level (failed calls are still logged)")
+	runCmd.Flags().IntVar(&progressEvery, "progress-every", 0,
+		"Log test progress every N finished tests (0 = disabled)")
+	runCmd.Flags().DurationVar(&progressInterval, "progress-interval", time.Minute,
+		"Log test progress at least this often (0 = disabled)")
+	runCmd.Flags().BoolVar(&offline, "offline", false,
+		"Fail instead of downloading EEST fixtures or genesis files that are not cached or local")
+	runCmd.Flags().StringVar(&runDirsFile, "run-dirs-file", "",
+		"Append the path of each run directory to this file as soon as it is created")
 }
 
 func runBenchmark(cmd *cobra.Command, args []string) error {
@@ -51,12 +90,24 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("config file is required (use --config)")
 	}
 
+	// Keep stdout clean for the machine-readable summary.
+	if jsonSummary {
+		log.SetOutput(os.Stderr)
+	}
+
 	// Load configuration.
 	cfg, err := config.Load(cfgFiles...)
 	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
+		return withExitCode(runner.ExitCodeConfigError, fmt.Errorf("loading config: %w", err))
 	}
 
+	// CLI directory flags take precedence over config files and env vars.
+	cfg.ApplyDirectoryOverrides(config.DirectoryOverrides{
+		ResultsDir:  outputDir,
+		TmpDataDir:  tmpDataDir,
+		TmpCacheDir: tmpCacheDir,
+	})
+
 	// Merge CLI metadata labels into config (CLI wins on conflict).
 	for _, entry := range metadataLabels {
 		k, v, ok := strings.Cut(entry, "=")
@@ -77,9 +128,19 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("parsing results_owner: %w", err)
 	}
 
-	// Use consistent log format when client logs go to stdout.
+	resultsMode, err := fsutil.ParseMode(cfg.Runner.Benchmark.ResultsMode)
+	if err != nil {
+		return fmt.Errorf("parsing results_mode: %w", err)
+	}
+
+	resultsOwner = fsutil.WithMode(resultsOwner, resultsMode)
+
+	// Use consistent log format when client logs go to stdout. Log files
+	// get the same format without colors.
+	logFileFormatter := newTextFormatter(true)
 	if cfg.Runner.ClientLogsToStdout {
-		log.SetFormatter(&consistentFormatter{prefix: "🔵"})
+		log.SetFormatter(newConsistentFormatter("🔵", colorsDisabled(os.Stdout)))
+		logFileFormatter = newConsistentFormatter("🔵", true)
 	}
 
 	// Setup context with signal handling.
@@ -111,7 +172,35 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		log.WithField("signal", sig).Fatal("Received second signal, forcing exit")
 	}()
 
+	// Most severe outcome of the instance runs, reported as the exit code.
+	runExitCode := runner.ExitCodeSuccess
+
 	if !cfg.Runner.Benchmark.SkipTestRun {
+		var rerunTests []string
+
+		if rerunFailed != "" {
+			if !cfg.Runner.Benchmark.Tests.Source.IsConfigured() {
+				return withExitCode(runner.ExitCodeConfigError,
+					fmt.Errorf("--rerun-failed requires a test source (runner.benchmark.tests.source)"))
+			}
+
+			rerunTests, err = loadFailedTests(rerunFailed)
+			if err != nil {
+				return withExitCode(runner.ExitCodeConfigError, err)
+			}
+
+			if len(rerunTests) == 0 {
+				log.WithField("run", rerunFailed).Info("No failed tests to re-run")
+
+				return nil
+			}
+
+			log.WithFields(logrus.Fields{
+				"run":   rerunFailed,
+				"tests": len(rerunTests),
+			}).Info("Re-running failed tests")
+		}
+
 		// Filter instances if limits are specified (before validation so we
 		// can scope datadir checks to active instances only).
 		instances := filterInstances(
@@ -146,8 +235,13 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		}
 
 		// Validate configuration.
-		if err := cfg.Validate(validateOpts); err != nil {
-			return fmt.Errorf("validating config: %w", err)
+		validation := cfg.ValidateAll(validateOpts)
+		if err := validation.Err(); err != nil {
+			return withExitCode(runner.ExitCodeValidationError, fmt.Errorf("validating config: %w", err))
+		}
+
+		for _, warning := range validation.Warnings {
+			log.WithField("field", warning.Field).Warn(warning.Message)
 		}
 
 		// Create container manager based on configured runtime.
@@ -193,6 +287,13 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		// Create client registry.
 		registry := client.NewRegistry()
 
+		maxAttempts, backoff, maxBackoff := cfg.GetDownloadRetries()
+		downloadRetry := download.RetryPolicy{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+			MaxBackoff:  maxBackoff,
+		}
+
 		// Create executor if tests are configured.
 		var exec executor.Executor
 
@@ -207,6 +308,21 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			tracerProvider, shutdownTracing, err := tracing.NewTracerProvider(ctx, cfg.Global.Tracing, version)
+			if err != nil {
+				return fmt.Errorf("setting up tracing: %w", err)
+			}
+
+			defer func() {
+				// Flush pending spans even when the run was cancelled.
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+				defer cancel()
+
+				if err := shutdownTracing(shutdownCtx); err != nil {
+					log.WithError(err).Warn("Failed to flush traces")
+				}
+			}()
+
 			// Pass suite metadata to executor only when labels are present.
 			var suiteMetadata *config.MetadataConfig
 			if len(cfg.Runner.Benchmark.Tests.Metadata.Labels) > 0 {
@@ -216,12 +332,21 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 			execCfg := &executor.Config{
 				Source:                          &cfg.Runner.Benchmark.Tests.Source,
 				Filter:                          cfg.Runner.Benchmark.Tests.Filter,
+				AllowEmptyGlobs:                 cfg.Runner.Benchmark.Tests.AllowEmptyGlobs,
+				SkipTestOnSetupFailure:          cfg.Runner.Benchmark.Tests.SkipTestOnSetupFailure,
+				MaxConsecutiveUnauthorized:      cfg.Runner.Benchmark.Tests.GetMaxConsecutiveUnauthorized(),
 				Metadata:                        suiteMetadata,
 				CacheDir:                        cacheDir,
 				ResultsDir:                      cfg.Runner.Benchmark.ResultsDir,
 				ResultsOwner:                    resultsOwner,
 				SystemResourceCollectionEnabled: *cfg.Runner.Benchmark.SystemResourceCollectionEnabled,
 				GitHubToken:                     cfg.Runner.GitHubToken,
+				DownloadRetry:                   downloadRetry,
+				Offline:                         offline,
+				TracerProvider:                  tracerProvider,
+				QuietRPCLogs:                    quiet,
+				ProgressEveryTests:              progressEvery,
+				ProgressInterval:                progressInterval,
 			}
 
 			exec = executor.NewExecutor(log, execCfg)
@@ -264,6 +389,32 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 			log.Info("CPU frequency manager initialized")
 		}
 
+		// Create THP manager if a transparent_hugepage mode is configured.
+		var thpMgr thp.Manager
+		if needsTHPManager(cfg) {
+			cacheDir := cfg.Runner.Directories.TmpCacheDir
+			if cacheDir == "" {
+				var err error
+				cacheDir, err = getExecutorCacheDir()
+				if err != nil {
+					return fmt.Errorf("getting cache directory: %w", err)
+				}
+			}
+
+			thpMgr = thp.NewManager(log, cacheDir, cfg.GetTHPSysfsPath())
+			if err := thpMgr.Start(ctx); err != nil {
+				return fmt.Errorf("starting THP manager: %w", err)
+			}
+
+			defer func() {
+				if err := thpMgr.Stop(); err != nil {
+					log.WithError(err).Warn("Failed to stop THP manager")
+				}
+			}()
+
+			log.Info("THP manager initialized")
+		}
+
 		// Create S3 uploader if configured.
 		var resultsUploader upload.Uploader
 
@@ -283,22 +434,45 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 			log.Info("S3 upload preflight check passed")
 		}
 
+		// IRQ affinity state files go where cleanup looks for them.
+		stateDir := cfg.Runner.Directories.TmpCacheDir
+		if stateDir == "" {
+			stateDir, err = getExecutorCacheDir()
+			if err != nil {
+				return fmt.Errorf("getting cache directory: %w", err)
+			}
+		}
+
 		// Create runner.
 		runnerCfg := &runner.Config{
-			ResultsDir:         cfg.Runner.Benchmark.ResultsDir,
-			ResultsOwner:       resultsOwner,
-			ClientLogsToStdout: cfg.Runner.ClientLogsToStdout,
-			ContainerNetwork:   cfg.Runner.ContainerNetwork,
-			JWT:                cfg.Runner.Client.Config.JWT,
-			GenesisURLs:        cfg.Runner.Client.Config.Genesis,
-			DataDirs:           cfg.Runner.Client.DataDirs,
-			TmpDataDir:         cfg.Runner.Directories.TmpDataDir,
-			TmpCacheDir:        cfg.Runner.Directories.TmpCacheDir,
-			TestFilter:         cfg.Runner.Benchmark.Tests.Filter,
-			FullConfig:         cfg,
+			ResultsDir:              cfg.Runner.Benchmark.ResultsDir,
+			ResultsOwner:            resultsOwner,
+			ResultsDirTemplate:      cfg.GetResultsDirTemplate(),
+			ResultsLayout:           cfg.GetResultsLayout(),
+			ClientLogsToStdout:      cfg.Runner.ClientLogsToStdout,
+			ClientLogTimestamps:     cfg.Runner.ClientLogTimestamps,
+			DisableLogColors:        colorsDisabled(os.Stdout),
+			LogFileFormatter:        logFileFormatter,
+			ContainerNetwork:        cfg.Runner.ContainerNetwork,
+			JWT:                     cfg.Runner.Client.Config.JWT,
+			GenesisURLs:             cfg.Runner.Client.Config.Genesis,
+			DownloadRetry:           downloadRetry,
+			Offline:                 offline,
+			DataDirs:                cfg.Runner.Client.DataDirs,
+			TmpDataDir:              cfg.Runner.Directories.TmpDataDir,
+			TmpCacheDir:             cfg.Runner.Directories.TmpCacheDir,
+			StateDir:                stateDir,
+			RunDirsFile:             runDirsFile,
+			TestFilter:              cfg.Runner.Benchmark.Tests.Filter,
+			RerunTests:              rerunTests,
+			VerifyGenesisHash:       cfg.Runner.Benchmark.Tests.Source.VerifyGenesisHash(),
+			GenesisGroupParallelism: cfg.Runner.Benchmark.Tests.GenesisGroupParallelism,
+			FullConfig:              cfg,
 		}
 
-		r := runner.NewRunner(log, runnerCfg, containerMgr, registry, exec, cpufreqMgr, resultsUploader)
+		r := runner.NewRunner(
+			log, runnerCfg, containerMgr, registry, exec, cpufreqMgr, thpMgr, resultsUploader,
+		)
 
 		if err := r.Start(ctx); err != nil {
 			return fmt.Errorf("starting runner: %w", err)
@@ -316,7 +490,11 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 			case <-ctx.Done():
 				log.Info("Benchmark interrupted")
 
-				return ctx.Err()
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return withExitCode(runner.ExitCodeFailure, ctx.Err())
+				}
+
+				return withExitCode(runner.ExitCodeCancelled, ctx.Err())
 			default:
 			}
 
@@ -325,6 +503,8 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 			if err := r.RunInstance(ctx, &instance); err != nil {
 				log.WithError(err).WithField("instance", instance.ID).Error("Instance failed")
 
+				runExitCode = runner.WorseExitCode(runExitCode, runner.ExitCodeFailure)
+
 				// Continue with next instance on failure.
 				continue
 			}
@@ -332,7 +512,13 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 			log.WithField("instance", instance.ID).Info("Instance completed successfully")
 		}
 
+		runExitCode = runner.WorseExitCode(runExitCode, r.ExitCode())
+
 		log.Info("Benchmark completed")
+
+		if err := printRunSummary(cmd.OutOrStdout(), r.RunDirs(), jsonSummary); err != nil {
+			log.WithError(err).Warn("Failed to print run summary")
+		}
 	} else {
 		log.Info("Skipping test runs (skip_test_run is enabled)")
 	}
@@ -351,9 +537,24 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if runExitCode != runner.ExitCodeSuccess {
+		return withExitCode(runExitCode, fmt.Errorf("benchmark did not succeed (exit code %d)", runExitCode))
+	}
+
 	return nil
 }
 
+// loadFailedTests returns the names of the failed tests of a previous run,
+// given its run directory or result.json.
+func loadFailedTests(path string) ([]string, error) {
+	result, err := executor.ReadRunResult(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading previous run: %w", err)
+	}
+
+	return executor.FailedTests(result), nil
+}
+
 // getExecutorCacheDir returns the cache directory for the executor.
 func getExecutorCacheDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -389,6 +590,17 @@ func needsCPUFreqManager(cfg *config.Config) bool {
 	return false
 }
 
+// needsTHPManager returns true if any instance sets a transparent_hugepage mode.
+func needsTHPManager(cfg *config.Config) bool {
+	for _, instance := range cfg.Runner.Instances {
+		if limits := cfg.GetResourceLimits(&instance); limits != nil && limits.TransparentHugepage != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 // generateResultsIndex generates index.json using either the local filesystem or S3.
 func generateResultsIndex(
 	cmd *cobra.Command,
@@ -640,3 +852,24 @@ func filterInstances(instances []config.ClientInstance, ids, clients []string) [
 
 	return filtered
 }
+
+// printRunSummary prints a summary of the given run directories to w, as a
+// table or as JSON.
+func printRunSummary(w io.Writer, runDirs []string, asJSON bool) error {
+	if len(runDirs) == 0 {
+		return nil
+	}
+
+	summaries, err := executor.BuildRunSummaries(runDirs)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return executor.WriteRunSummaryJSON(w, summaries)
+	}
+
+	_, _ = fmt.Fprintln(w)
+
+	return executor.WriteRunSummaryTable(w, summaries)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFailedTests(t *testing.T) {
+	runDir := t.TempDir()
+	result := `{"tests": {
+		"passed/test.txt": {"dir": "", "steps": {"test": {"aggregated": {"success": 3, "fail": 0}}}},
+		"setup-failed/test.txt": {"dir": "", "steps": {
+			"setup": {"aggregated": {"success": 0, "fail": 1}},
+			"test": {"aggregated": {"success": 1, "fail": 0}}
+		}},
+		"failed/test.txt": {"dir": "", "steps": {"test": {"aggregated": {"success": 1, "fail": 2}}}}
+	}}`
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "result.json"), []byte(result), 0o644))
+
+	failed, err := loadFailedTests(runDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"failed/test.txt", "setup-failed/test.txt"}, failed)
+
+	_, err = loadFailedTests(filepath.Join(runDir, "missing"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading previous run")
+}
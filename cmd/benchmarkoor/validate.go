@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/runner"
+	"github.com/spf13/cobra"
+)
+
+var validateJSON bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration",
+	Long: `Validate the configuration and report every error found, so all of them
+can be fixed in one pass, along with warnings about valid settings that are
+likely to skew results. Each error and warning names the config field it
+concerns.
+
+The command exits non-zero if the config is invalid. Warnings alone do not
+fail it.`,
+	RunE: runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().BoolVar(&validateJSON, "json", false,
+		"Print the validation result as JSON")
+}
+
+// validationReport is the JSON output of the validate command.
+type validationReport struct {
+	Valid    bool                       `json:"valid"`
+	Errors   []config.ValidationError   `json:"errors"`
+	Warnings []config.ValidationWarning `json:"warnings"`
+}
+
+func runValidate(cmd *cobra.Command, _ []string) error {
+	if len(cfgFiles) == 0 {
+		return fmt.Errorf("config file is required (use --config)")
+	}
+
+	// Keep stdout parseable when printing JSON.
+	if validateJSON {
+		log.SetOutput(os.Stderr)
+	}
+
+	cfg, err := config.Load(cfgFiles...)
+	if err != nil {
+		return withExitCode(runner.ExitCodeConfigError, fmt.Errorf("loading config: %w", err))
+	}
+
+	result := cfg.ValidateAll()
+
+	if err := writeValidationReport(cmd.OutOrStdout(), result, validateJSON); err != nil {
+		return err
+	}
+
+	if len(result.Errors) > 0 {
+		return withExitCode(runner.ExitCodeValidationError,
+			fmt.Errorf("config has %d validation error(s)", len(result.Errors)))
+	}
+
+	return nil
+}
+
+// writeValidationReport writes the validation result as JSON or as one
+// "field: message" line per error, followed by the warnings.
+func writeValidationReport(w io.Writer, result *config.ValidationResult, asJSON bool) error {
+	if asJSON {
+		report := validationReport{
+			Valid:    len(result.Errors) == 0,
+			Errors:   result.Errors,
+			Warnings: result.Warnings,
+		}
+
+		if report.Errors == nil {
+			report.Errors = []config.ValidationError{}
+		}
+
+		if report.Warnings == nil {
+			report.Warnings = []config.ValidationWarning{}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(report)
+	}
+
+	for _, e := range result.Errors {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", e.Field, e.Message); err != nil {
+			return err
+		}
+	}
+
+	for _, warning := range result.Warnings {
+		if _, err := fmt.Fprintf(w, "warning: %s: %s\n", warning.Field, warning.Message); err != nil {
+			return err
+		}
+	}
+
+	if len(result.Errors) == 0 {
+		_, err := fmt.Fprintln(w, "Config is valid")
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteValidationReport(t *testing.T) {
+	cfg := &config.Config{Runner: config.RunnerConfig{
+		Instances: []config.ClientInstance{
+			{ID: "geth-1", Client: "nope", ResourceLimits: &config.ResourceLimits{Memory: "16g"}},
+			{ID: "geth-1", Client: "geth", ClientCommit: "main"},
+		},
+	}}
+
+	result := cfg.ValidateAll()
+	require.Len(t, result.Errors, 3)
+	require.Len(t, result.Warnings, 1)
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeValidationReport(&buf, result, true))
+
+		type issue struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		}
+
+		var report struct {
+			Valid    bool    `json:"valid"`
+			Errors   []issue `json:"errors"`
+			Warnings []issue `json:"warnings"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+		assert.False(t, report.Valid)
+		require.Len(t, report.Errors, 3)
+		assert.Equal(t, "runner.instances[0].client", report.Errors[0].Field)
+		assert.Contains(t, report.Errors[0].Message, `unknown client type "nope"`)
+		assert.Equal(t, "runner.instances[1].id", report.Errors[1].Field)
+		assert.Equal(t, "runner.instances[1].client_commit", report.Errors[2].Field)
+		require.Len(t, report.Warnings, 1)
+		assert.Equal(t, "runner.instances[1].resource_limits", report.Warnings[0].Field)
+	})
+
+	t.Run("json valid", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeValidationReport(&buf, &config.ValidationResult{}, true))
+		assert.JSONEq(t, `{"valid": true, "errors": [], "warnings": []}`, buf.String())
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeValidationReport(&buf, result, false))
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 4)
+		assert.Contains(t, string(lines[1]), `runner.instances[1].id: instance 1: duplicate id "geth-1"`)
+		assert.Contains(t, string(lines[3]), "warning: runner.instances[1].resource_limits:")
+	})
+}
@@ -299,6 +299,37 @@ func (s *server) handleDeleteSessionByID(
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// handleRevokeUserSessions deletes every session belonging to a user,
+// forcing them to sign in again on their next request.
+func (s *server) handleRevokeUserSessions(
+	w http.ResponseWriter, r *http.Request,
+) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest,
+			errorResponse{err.Error()})
+
+		return
+	}
+
+	if _, err := s.store.GetUserByID(r.Context(), id); err != nil {
+		writeJSON(w, http.StatusNotFound,
+			errorResponse{"user not found"})
+
+		return
+	}
+
+	if err := s.store.DeleteSessionsByUserID(r.Context(), id); err != nil {
+		s.log.WithError(err).Error("Failed to revoke user sessions")
+		writeJSON(w, http.StatusInternalServerError,
+			errorResponse{"internal error"})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
 // --- Admin API key management ---
 
 type adminAPIKeyResponse struct {
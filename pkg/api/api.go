@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/api/indexer"
 	"github.com/ethpandaops/benchmarkoor/pkg/api/indexstore"
+	"github.com/ethpandaops/benchmarkoor/pkg/api/jobqueue"
 	"github.com/ethpandaops/benchmarkoor/pkg/api/storage"
 	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
@@ -40,6 +42,10 @@ type server struct {
 	indexer        indexer.Indexer
 	storageReader  storage.Reader
 	storageDeleter storage.Deleter
+	jobQueue       jobqueue.Queue
+	githubAPIURL   string // overrides githubAPIBaseURL in tests
+	teamCache      githubTeamCache
+	httpMetrics    *httpMetrics
 	httpServer     *http.Server
 	wg             sync.WaitGroup
 	done           chan struct{}
@@ -113,6 +119,13 @@ func (s *server) Start(ctx context.Context) error {
 		}
 	}
 
+	// Start the remote run queue if configured.
+	if s.cfg.Runs != nil && s.cfg.Runs.Enabled {
+		if err := s.startJobQueue(ctx); err != nil {
+			return fmt.Errorf("starting job queue: %w", err)
+		}
+	}
+
 	// Build router and start HTTP server.
 	router := s.buildRouter()
 
@@ -198,6 +211,12 @@ func (s *server) Stop() error {
 
 	s.wg.Wait()
 
+	if s.jobQueue != nil {
+		if err := s.jobQueue.Stop(); err != nil {
+			s.log.WithError(err).Warn("Job queue stop error")
+		}
+	}
+
 	if s.indexer != nil {
 		if err := s.indexer.Stop(); err != nil {
 			s.log.WithError(err).Warn("Indexer stop error")
@@ -273,3 +292,29 @@ func (s *server) prepareIndexing(ctx context.Context) error {
 
 	return nil
 }
+
+// startJobQueue creates and starts the queue that executes remotely
+// triggered runs by re-invoking the current binary.
+func (s *server) startJobQueue(ctx context.Context) error {
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("resolving executable path: %w", err)
+	}
+
+	executor := jobqueue.NewProcessExecutor(
+		s.log, binary, s.cfg.Runs.BaseConfigs, s.cfg.Runs.WorkDir,
+	)
+
+	s.jobQueue = jobqueue.NewQueue(
+		s.log, s.store, executor,
+		s.cfg.Runs.Concurrency, s.cfg.Runs.QueueSize,
+	)
+
+	if err := s.jobQueue.Start(ctx); err != nil {
+		return err
+	}
+
+	s.log.Info("Remote runs enabled")
+
+	return nil
+}
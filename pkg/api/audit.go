@@ -0,0 +1,272 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	// maxAuditBodyBytes bounds how much of a request body is recorded.
+	maxAuditBodyBytes = 64 << 10
+
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+
+	redactedValue = "[REDACTED]"
+)
+
+// sensitiveParamKeys are substrings of JSON keys whose values are never
+// written to the audit log.
+var sensitiveParamKeys = []string{"password", "secret", "token"}
+
+// auditParams is the JSON document stored in AuditLog.Params.
+type auditParams struct {
+	URLParams map[string]string   `json:"url_params,omitempty"`
+	Query     map[string][]string `json:"query,omitempty"`
+	Body      any                 `json:"body,omitempty"`
+}
+
+// auditLog records every mutating request made by an authenticated user.
+// It must be installed after requireAuth so the user is in the context.
+func (s *server) auditLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		user := userFromContext(r.Context())
+		if user == nil {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		body := captureAuditBody(r)
+
+		ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		params := auditParams{Body: body}
+
+		if len(r.URL.Query()) > 0 {
+			params.Query = r.URL.Query()
+		}
+
+		action := r.Method + " " + r.URL.Path
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				action = r.Method + " " + pattern
+			}
+
+			for i, key := range rctx.URLParams.Keys {
+				if key == "*" || i >= len(rctx.URLParams.Values) {
+					continue
+				}
+
+				if params.URLParams == nil {
+					params.URLParams = make(map[string]string, 1)
+				}
+
+				params.URLParams[key] = rctx.URLParams.Values[i]
+			}
+		}
+
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			s.log.WithError(err).Warn("Failed to encode audit params")
+		}
+
+		entry := &store.AuditLog{
+			UserID:   user.ID,
+			Username: user.Username,
+			Action:   action,
+			Path:     r.URL.Path,
+			Params:   string(encoded),
+			Status:   status,
+		}
+
+		// Record the entry even if the client has gone away.
+		if err := s.store.CreateAuditLog(
+			context.WithoutCancel(r.Context()), entry,
+		); err != nil {
+			s.log.WithError(err).
+				WithField("action", action).
+				Warn("Failed to write audit log entry")
+		}
+	})
+}
+
+// isMutatingMethod reports whether the HTTP method changes server state.
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// captureAuditBody reads the request body for the audit log and restores
+// it for the handler. JSON bodies are decoded so sensitive fields can be
+// redacted; other or oversized bodies are summarized.
+func captureAuditBody(r *http.Request) any {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(r.Body, maxAuditBodyBytes+1))
+
+	// Hand the handler the bytes already consumed plus the unread rest.
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+
+	if err != nil || len(buf) == 0 {
+		return nil
+	}
+
+	if len(buf) > maxAuditBodyBytes {
+		return "[body larger than " + strconv.Itoa(maxAuditBodyBytes) +
+			" bytes omitted]"
+	}
+
+	var decoded any
+	if err := json.Unmarshal(buf, &decoded); err != nil {
+		return "[non-JSON body omitted]"
+	}
+
+	return redactSensitive(decoded)
+}
+
+// redactSensitive replaces the values of sensitive keys in decoded JSON.
+func redactSensitive(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, inner := range val {
+			if isSensitiveKey(k) {
+				val[k] = redactedValue
+
+				continue
+			}
+
+			val[k] = redactSensitive(inner)
+		}
+
+		return val
+	case []any:
+		for i := range val {
+			val[i] = redactSensitive(val[i])
+		}
+
+		return val
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+
+	for _, s := range sensitiveParamKeys {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type auditLogResponse struct {
+	ID        uint            `json:"id"`
+	UserID    uint            `json:"user_id"`
+	Username  string          `json:"username"`
+	Action    string          `json:"action"`
+	Path      string          `json:"path"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Status    int             `json:"status"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// handleListAuditLog returns a page of audit log entries, newest first.
+func (s *server) handleListAuditLog(w http.ResponseWriter, r *http.Request) {
+	limit := defaultAuditPageSize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeJSON(w, http.StatusBadRequest,
+				errorResponse{"limit must be a positive integer"})
+
+			return
+		}
+
+		limit = min(n, maxAuditPageSize)
+	}
+
+	offset := 0
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeJSON(w, http.StatusBadRequest,
+				errorResponse{"offset must be a non-negative integer"})
+
+			return
+		}
+
+		offset = n
+	}
+
+	entries, total, err := s.store.ListAuditLogs(r.Context(), limit, offset)
+	if err != nil {
+		s.log.WithError(err).Error("Failed to list audit log")
+		writeJSON(w, http.StatusInternalServerError,
+			errorResponse{"internal error"})
+
+		return
+	}
+
+	data := make([]auditLogResponse, 0, len(entries))
+	for i := range entries {
+		e := &entries[i]
+
+		resp := auditLogResponse{
+			ID:        e.ID,
+			UserID:    e.UserID,
+			Username:  e.Username,
+			Action:    e.Action,
+			Path:      e.Path,
+			Status:    e.Status,
+			CreatedAt: e.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+
+		if json.Valid([]byte(e.Params)) {
+			resp.Params = json.RawMessage(e.Params)
+		}
+
+		data = append(data, resp)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data":   data,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
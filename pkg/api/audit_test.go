@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditLogPage struct {
+	Data   []auditLogResponse `json:"data"`
+	Total  int64              `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
+
+func listAuditLog(
+	t *testing.T, handler http.Handler, token, query string,
+) auditLogPage {
+	t.Helper()
+
+	rec := doRequest(
+		t, handler, http.MethodGet, "/api/v1/admin/audit-log"+query, token, "",
+	)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var page auditLogPage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+
+	return page
+}
+
+func TestAuditLog_RecordsMutations(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	router := srv.buildRouter()
+
+	rec := doRequest(t, router, http.MethodPost, "/api/v1/admin/users", adminToken,
+		`{"username":"new-user","password":"hunter2","role":"readonly"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created userResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	// Reads are not audited.
+	rec = doRequest(t, router, http.MethodGet, "/api/v1/admin/users", adminToken, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(t, router, http.MethodDelete,
+		fmt.Sprintf("/api/v1/admin/users/%d", created.ID), adminToken, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	page := listAuditLog(t, router, adminToken, "")
+	require.Len(t, page.Data, 2)
+	assert.Equal(t, int64(2), page.Total)
+
+	// Newest first.
+	deletion, creation := page.Data[0], page.Data[1]
+
+	assert.Equal(t, "DELETE /api/v1/admin/users/{id}", deletion.Action)
+	assert.Equal(t, "admin-user", deletion.Username)
+	assert.Equal(t, http.StatusOK, deletion.Status)
+	assert.Contains(t, string(deletion.Params),
+		fmt.Sprintf(`"id":"%d"`, created.ID))
+
+	assert.Equal(t, "POST /api/v1/admin/users", creation.Action)
+	assert.Equal(t, http.StatusCreated, creation.Status)
+	assert.Contains(t, string(creation.Params), `"username":"new-user"`)
+	assert.NotContains(t, string(creation.Params), "hunter2")
+	assert.Contains(t, string(creation.Params), redactedValue)
+}
+
+func TestAuditLog_RecordsFailedMutations(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	router := srv.buildRouter()
+
+	rec := doRequest(t, router, http.MethodPost, "/api/v1/admin/users",
+		adminToken, `not json`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	page := listAuditLog(t, router, adminToken, "")
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, http.StatusBadRequest, page.Data[0].Status)
+	assert.Contains(t, string(page.Data[0].Params), "non-JSON body omitted")
+}
+
+func TestAuditLog_Pagination(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	router := srv.buildRouter()
+
+	for i := range 3 {
+		rec := doRequest(t, router, http.MethodPost, "/api/v1/admin/users",
+			adminToken, fmt.Sprintf(
+				`{"username":"user-%d","password":"pw","role":"readonly"}`, i,
+			))
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	page := listAuditLog(t, router, adminToken, "?limit=2")
+	assert.Equal(t, int64(3), page.Total)
+	assert.Equal(t, 2, page.Limit)
+	assert.Len(t, page.Data, 2)
+
+	page = listAuditLog(t, router, adminToken, "?limit=2&offset=2")
+	require.Len(t, page.Data, 1)
+	assert.Contains(t, string(page.Data[0].Params), "user-0")
+
+	rec := doRequest(t, router, http.MethodGet,
+		"/api/v1/admin/audit-log?limit=abc", adminToken, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAuditLog_RequiresAdmin(t *testing.T) {
+	srv, _, readonlyToken := setupTestServer(t)
+	router := srv.buildRouter()
+
+	rec := doRequest(t, router, http.MethodGet, "/api/v1/admin/audit-log",
+		readonlyToken, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestRedactSensitive(t *testing.T) {
+	var body any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "x",
+		"Password": "p",
+		"nested": {"client_secret": "s", "keep": 1},
+		"list": [{"api_token": "t"}]
+	}`), &body))
+
+	out, err := json.Marshal(redactSensitive(body))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, strings.Count(string(out), redactedValue))
+	assert.Contains(t, string(out), `"name":"x"`)
+	assert.Contains(t, string(out), `"keep":1`)
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// compressMiddleware gzips responses for clients that accept it and
+// transparently decodes gzip-encoded request bodies. Responses are buffered
+// until they reach the configured minimum size; a handler that flushes
+// before then (e.g. log streaming) is served uncompressed so each chunk
+// reaches the client immediately.
+func (s *server) compressMiddleware() func(http.Handler) http.Handler {
+	cfg := s.cfg.Server.Compression
+
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+
+	contentTypes := make(map[string]bool, len(cfg.ContentTypes))
+	for _, ct := range cfg.ContentTypes {
+		contentTypes[strings.ToLower(ct)] = true
+	}
+
+	level := cfg.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	pool := &sync.Pool{
+		New: func() any {
+			gz, _ := gzip.NewWriterLevel(io.Discard, level)
+
+			return gz
+		},
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Encoding") != "" {
+				if !decodeGzipRequest(w, r) {
+					return
+				}
+			}
+
+			if r.Method == http.MethodHead ||
+				!acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			gw := &gzipResponseWriter{
+				ResponseWriter: w,
+				minSize:        cfg.MinSize,
+				contentTypes:   contentTypes,
+				pool:           pool,
+			}
+			defer gw.close()
+
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// decodeGzipRequest replaces a gzip-encoded request body with its decoded
+// form. It writes an error response and returns false when the body uses
+// an unsupported encoding or is not valid gzip.
+func decodeGzipRequest(w http.ResponseWriter, r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		writeJSON(w, http.StatusUnsupportedMediaType,
+			errorResponse{"unsupported content encoding"})
+
+		return false
+	}
+
+	gz, err := gzip.NewReader(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest,
+			errorResponse{"invalid gzip request body"})
+
+		return false
+	}
+
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{gz, r.Body}
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+
+	return true
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value allows a
+// gzip response.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		q, found := strings.CutPrefix(
+			strings.ReplaceAll(strings.ToLower(params), " ", ""), "q=",
+		)
+		if !found {
+			return true
+		}
+
+		if v, err := strconv.ParseFloat(q, 64); err == nil && v > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response to decide whether it
+// is worth compressing. The decision is made once minSize bytes have been
+// written, on Flush, or when the handler returns.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+
+	minSize      int
+	contentTypes map[string]bool
+	pool         *sync.Pool
+
+	status  int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+
+		return
+	}
+
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+
+		if len(w.buf) < w.minSize {
+			return len(p), nil
+		}
+
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+
+		return len(p), nil
+	}
+
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends any buffered data to the client. Flushing before the minimum
+// size is reached commits the response to being uncompressed.
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(false); err != nil {
+			return
+		}
+	}
+
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the underlying writer supports it.
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijacking not supported")
+	}
+
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide writes the status line and the buffered body, compressing when
+// sizeReached is set and the response qualifies.
+func (w *gzipResponseWriter) decide(sizeReached bool) error {
+	w.decided = true
+
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	h := w.Header()
+
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if sizeReached && w.compressible(status) {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+
+		gz, _ := w.pool.Get().(*gzip.Writer)
+		gz.Reset(w.ResponseWriter)
+		w.gz = gz
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+
+	buf := w.buf
+	w.buf = nil
+
+	if len(buf) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+
+	return err
+}
+
+// compressible reports whether the pending response may be gzipped.
+func (w *gzipResponseWriter) compressible(status int) bool {
+	if status < http.StatusOK ||
+		status == http.StatusNoContent ||
+		status == http.StatusNotModified ||
+		status == http.StatusPartialContent {
+		return false
+	}
+
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil || mediaType == "text/event-stream" {
+		return false
+	}
+
+	return w.contentTypes[mediaType]
+}
+
+// close finishes the response once the handler has returned.
+func (w *gzipResponseWriter) close() {
+	if !w.decided {
+		// Small bodies never reach the minimum size.
+		_ = w.decide(false)
+	}
+
+	if w.gz != nil {
+		_ = w.gz.Close()
+		w.gz.Reset(io.Discard)
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
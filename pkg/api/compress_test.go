@@ -0,0 +1,298 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompressTestServer(t *testing.T) *server {
+	t.Helper()
+
+	srv, _, _ := setupTestServer(t)
+	srv.cfg.Server.Compression = config.APICompressionConfig{
+		Level:        5,
+		MinSize:      1024,
+		ContentTypes: []string{"application/json", "text/plain"},
+	}
+
+	return srv
+}
+
+func TestCompress_Responses(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("a", 4096) + `"}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		wantGzip       bool
+	}{
+		{
+			name:           "large json is compressed",
+			acceptEncoding: "gzip, deflate, br",
+			contentType:    "application/json",
+			body:           large,
+			wantGzip:       true,
+		},
+		{
+			name:           "small json is not compressed",
+			acceptEncoding: "gzip",
+			contentType:    "application/json",
+			body:           `{"ok":true}`,
+		},
+		{
+			name:        "client without gzip support",
+			contentType: "application/json",
+			body:        large,
+		},
+		{
+			name:           "gzip explicitly refused",
+			acceptEncoding: "gzip;q=0, br",
+			contentType:    "application/json",
+			body:           large,
+		},
+		{
+			name:           "content type outside the allowlist",
+			acceptEncoding: "gzip",
+			contentType:    "application/octet-stream",
+			body:           large,
+		},
+		{
+			name:           "content type parameters are ignored",
+			acceptEncoding: "gzip",
+			contentType:    "text/plain; charset=utf-8",
+			body:           strings.Repeat("line\n", 1024),
+			wantGzip:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newCompressTestServer(t)
+
+			handler := srv.compressMiddleware()(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Content-Type", tt.contentType)
+					w.Header().Set("Content-Length",
+						strconv.Itoa(len(tt.body)))
+					_, _ = io.WriteString(w, tt.body)
+				},
+			))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			if !tt.wantGzip {
+				assert.Empty(t, rec.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.body, rec.Body.String())
+
+				return
+			}
+
+			assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+			assert.Empty(t, rec.Header().Get("Content-Length"))
+			assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+			assert.Less(t, rec.Body.Len(), len(tt.body))
+
+			gz, err := gzip.NewReader(rec.Body)
+			require.NoError(t, err)
+
+			decoded, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(decoded))
+		})
+	}
+}
+
+func TestCompress_FlushedStreamIsNotCompressed(t *testing.T) {
+	srv := newCompressTestServer(t)
+
+	handler := srv.compressMiddleware()(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+
+			for range 3 {
+				_, _ = io.WriteString(w, strings.Repeat("log line\n", 256))
+				w.(http.Flusher).Flush()
+			}
+		},
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, strings.Repeat("log line\n", 768), rec.Body.String())
+}
+
+func TestCompress_EventStreamIsNotCompressed(t *testing.T) {
+	srv := newCompressTestServer(t)
+	srv.cfg.Server.Compression.ContentTypes = append(
+		srv.cfg.Server.Compression.ContentTypes, "text/event-stream",
+	)
+
+	body := strings.Repeat("data: event\n\n", 512)
+
+	handler := srv.compressMiddleware()(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, body)
+		},
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, rec.Body.String())
+}
+
+func TestCompress_Disabled(t *testing.T) {
+	srv := newCompressTestServer(t)
+
+	disabled := false
+	srv.cfg.Server.Compression.Enabled = &disabled
+
+	body := `{"data":"` + strings.Repeat("a", 4096) + `"}`
+
+	handler := srv.compressMiddleware()(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, body)
+		},
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, rec.Body.String())
+}
+
+func TestCompress_GzipRequestBody(t *testing.T) {
+	var compressed bytes.Buffer
+
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte(`{"name":"test"}`))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	tests := []struct {
+		name            string
+		contentEncoding string
+		body            []byte
+		wantStatus      int
+		wantBody        string
+	}{
+		{
+			name:            "gzip body is decoded",
+			contentEncoding: "gzip",
+			body:            compressed.Bytes(),
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"name":"test"}`,
+		},
+		{
+			name:       "plain body is untouched",
+			body:       []byte(`{"name":"plain"}`),
+			wantStatus: http.StatusOK,
+			wantBody:   `{"name":"plain"}`,
+		},
+		{
+			name:            "invalid gzip body",
+			contentEncoding: "gzip",
+			body:            []byte("not gzip"),
+			wantStatus:      http.StatusBadRequest,
+		},
+		{
+			name:            "unsupported encoding",
+			contentEncoding: "br",
+			body:            []byte("x"),
+			wantStatus:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newCompressTestServer(t)
+
+			handler := srv.compressMiddleware()(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					assert.Empty(t, r.Header.Get("Content-Encoding"))
+
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+
+					_, _ = w.Write(body)
+				},
+			))
+
+			req := httptest.NewRequest(
+				http.MethodPost, "/", bytes.NewReader(tt.body),
+			)
+			if tt.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", tt.contentEncoding)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "gzip", want: true},
+		{header: "GZIP", want: true},
+		{header: "deflate, gzip;q=0.5", want: true},
+		{header: "gzip;q=0", want: false},
+		{header: "gzip; q=0.0, br", want: false},
+		{header: "*", want: true},
+		{header: "br, identity", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, acceptsGzip(tt.header))
+		})
+	}
+}
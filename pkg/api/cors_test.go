@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS_Preflight(t *testing.T) {
+	allow := true
+	deny := false
+
+	tests := []struct {
+		name            string
+		cors            config.APICORSConfig
+		origin          string
+		method          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{
+			name:       "same-origin only by default",
+			cors:       config.APICORSConfig{},
+			origin:     "https://ui.example.com",
+			method:     http.MethodPost,
+			wantOrigin: "",
+		},
+		{
+			name: "allowed origin",
+			cors: config.APICORSConfig{
+				AllowedOrigins:   []string{"https://ui.example.com"},
+				AllowedMethods:   []string{"GET", "POST"},
+				AllowedHeaders:   []string{"Content-Type"},
+				AllowCredentials: &allow,
+			},
+			origin:          "https://ui.example.com",
+			method:          http.MethodPost,
+			wantOrigin:      "https://ui.example.com",
+			wantCredentials: "true",
+		},
+		{
+			name: "disallowed origin",
+			cors: config.APICORSConfig{
+				AllowedOrigins: []string{"https://ui.example.com"},
+				AllowedMethods: []string{"GET", "POST"},
+			},
+			origin:     "https://evil.example.com",
+			method:     http.MethodPost,
+			wantOrigin: "",
+		},
+		{
+			name: "disallowed method",
+			cors: config.APICORSConfig{
+				AllowedOrigins: []string{"https://ui.example.com"},
+				AllowedMethods: []string{"GET"},
+			},
+			origin:     "https://ui.example.com",
+			method:     http.MethodDelete,
+			wantOrigin: "",
+		},
+		{
+			name: "wildcard reflects the origin",
+			cors: config.APICORSConfig{
+				AllowedOrigins:   []string{"*"},
+				AllowedMethods:   []string{"GET"},
+				AllowCredentials: &allow,
+			},
+			origin:          "https://anywhere.example.com",
+			method:          http.MethodGet,
+			wantOrigin:      "https://anywhere.example.com",
+			wantCredentials: "true",
+		},
+		{
+			name: "credentials disabled",
+			cors: config.APICORSConfig{
+				AllowedOrigins:   []string{"https://ui.example.com"},
+				AllowedMethods:   []string{"GET"},
+				AllowCredentials: &deny,
+			},
+			origin:     "https://ui.example.com",
+			method:     http.MethodGet,
+			wantOrigin: "https://ui.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _, _ := setupTestServer(t)
+			srv.cfg.Server.CORS = tt.cors
+			router := srv.buildRouter()
+
+			req := httptest.NewRequest(
+				http.MethodOptions, "/api/v1/auth/login", nil,
+			)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantOrigin,
+				rec.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.wantCredentials,
+				rec.Header().Get("Access-Control-Allow-Credentials"))
+
+			if tt.wantOrigin != "" {
+				assert.Equal(t, tt.method,
+					rec.Header().Get("Access-Control-Allow-Methods"))
+			}
+		})
+	}
+}
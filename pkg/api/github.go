@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
@@ -38,6 +39,53 @@ type githubOrg struct {
 	Login string `json:"login"`
 }
 
+type githubTeam struct {
+	Slug         string    `json:"slug"`
+	Organization githubOrg `json:"organization"`
+}
+
+// githubTeamCache caches each GitHub user's team memberships so repeated
+// logins within a session TTL don't hit the GitHub API again.
+type githubTeamCache struct {
+	mu      sync.Mutex
+	entries map[string]githubTeamCacheEntry
+}
+
+type githubTeamCacheEntry struct {
+	teams     []githubTeam
+	expiresAt time.Time
+}
+
+// get returns the cached teams for the given login, if still valid.
+func (c *githubTeamCache) get(login string, now time.Time) ([]githubTeam, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[strings.ToLower(login)]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.teams, true
+}
+
+// set stores the teams for the given login until expiresAt.
+func (c *githubTeamCache) set(
+	login string, teams []githubTeam, expiresAt time.Time,
+) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]githubTeamCacheEntry, 1)
+	}
+
+	c.entries[strings.ToLower(login)] = githubTeamCacheEntry{
+		teams:     teams,
+		expiresAt: expiresAt,
+	}
+}
+
 // handleGitHubAuth initiates the GitHub OAuth flow.
 func (s *server) handleGitHubAuth(
 	w http.ResponseWriter, r *http.Request,
@@ -120,7 +168,7 @@ func (s *server) handleGitHubCallback(
 	}
 
 	// Fetch GitHub user info.
-	ghUser, err := fetchGitHubUser(accessToken)
+	ghUser, err := fetchGitHubUser(s.githubAPI(), accessToken)
 	if err != nil {
 		s.log.WithError(err).Error("Failed to fetch GitHub user")
 		writeJSON(w, http.StatusInternalServerError,
@@ -130,7 +178,7 @@ func (s *server) handleGitHubCallback(
 	}
 
 	// Fetch user's organizations.
-	orgs, err := fetchGitHubUserOrgs(accessToken)
+	orgs, err := fetchGitHubUserOrgs(s.githubAPI(), accessToken)
 	if err != nil {
 		s.log.WithError(err).Error("Failed to fetch GitHub orgs")
 		writeJSON(w, http.StatusInternalServerError,
@@ -139,8 +187,22 @@ func (s *server) handleGitHubCallback(
 		return
 	}
 
+	// Fetch user's teams (only needed when team mappings are configured).
+	var teams []githubTeam
+
+	if len(s.cfg.Auth.GitHub.TeamRoleMapping) > 0 {
+		teams, err = s.githubUserTeams(ghUser.Login, accessToken)
+		if err != nil {
+			s.log.WithError(err).Error("Failed to fetch GitHub teams")
+			writeJSON(w, http.StatusInternalServerError,
+				errorResponse{"github authentication failed"})
+
+			return
+		}
+	}
+
 	// Resolve role from mappings.
-	role, err := s.resolveGitHubRole(r.Context(), ghUser.Login, orgs)
+	role, err := s.resolveGitHubRole(r.Context(), ghUser.Login, orgs, teams)
 	if err != nil {
 		writeJSON(w, http.StatusForbidden,
 			errorResponse{err.Error()})
@@ -227,11 +289,13 @@ func (s *server) handleGitHubCallback(
 }
 
 // resolveGitHubRole determines the user's role from GitHub mappings.
-// User-level mapping takes precedence; then org-level (highest privilege wins).
+// User-level mapping takes precedence, then team-level, then org-level.
+// Within the team and org levels the highest privilege wins.
 func (s *server) resolveGitHubRole(
 	ctx context.Context,
 	username string,
 	orgs []githubOrg,
+	teams []githubTeam,
 ) (string, error) {
 	// Check user-level mapping first.
 	userMappings, err := s.store.ListGitHubUserMappings(ctx)
@@ -245,6 +309,13 @@ func (s *server) resolveGitHubRole(
 		}
 	}
 
+	// Check team-level mappings.
+	if role := resolveGitHubTeamRole(
+		s.cfg.Auth.GitHub.TeamRoleMapping, teams,
+	); role != "" {
+		return role, nil
+	}
+
 	// Check org-level mappings.
 	orgMappings, err := s.store.ListGitHubOrgMappings(ctx)
 	if err != nil {
@@ -281,6 +352,74 @@ func (s *server) resolveGitHubRole(
 	)
 }
 
+// resolveGitHubTeamRole returns the highest-privilege role mapped to any of
+// the given teams, or "" when none match. Mapping keys are "org/team-slug".
+func resolveGitHubTeamRole(
+	mapping map[string]string, teams []githubTeam,
+) string {
+	if len(mapping) == 0 {
+		return ""
+	}
+
+	normalized := make(map[string]string, len(mapping))
+	for team, role := range mapping {
+		normalized[strings.ToLower(team)] = role
+	}
+
+	bestRole := ""
+
+	for _, team := range teams {
+		key := strings.ToLower(team.Organization.Login + "/" + team.Slug)
+
+		role, ok := normalized[key]
+		if !ok {
+			continue
+		}
+
+		// "admin" takes precedence over "readonly".
+		if role == "admin" {
+			return "admin"
+		}
+
+		if bestRole == "" {
+			bestRole = role
+		}
+	}
+
+	return bestRole
+}
+
+// githubUserTeams returns the user's team memberships, served from the
+// cache when fetched within the last session TTL.
+func (s *server) githubUserTeams(
+	login, accessToken string,
+) ([]githubTeam, error) {
+	now := time.Now()
+
+	if teams, ok := s.teamCache.get(login, now); ok {
+		return teams, nil
+	}
+
+	teams, err := fetchGitHubUserTeams(s.githubAPI(), accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, _ := time.ParseDuration(s.cfg.Auth.SessionTTL)
+	s.teamCache.set(login, teams, now.Add(ttl))
+
+	return teams, nil
+}
+
+// githubAPI returns the base URL of the GitHub REST API.
+func (s *server) githubAPI() string {
+	if s.githubAPIURL != "" {
+		return s.githubAPIURL
+	}
+
+	return githubAPIBaseURL
+}
+
 // exchangeGitHubCode exchanges an authorization code for an access token.
 func (s *server) exchangeGitHubCode(code string) (string, error) {
 	data := url.Values{
@@ -325,9 +464,9 @@ func (s *server) exchangeGitHubCode(code string) (string, error) {
 }
 
 // fetchGitHubUser retrieves the authenticated GitHub user's profile.
-func fetchGitHubUser(accessToken string) (*githubUser, error) {
+func fetchGitHubUser(baseURL, accessToken string) (*githubUser, error) {
 	req, err := http.NewRequest(
-		http.MethodGet, githubAPIBaseURL+"/user", nil,
+		http.MethodGet, baseURL+"/user", nil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -358,9 +497,9 @@ func fetchGitHubUser(accessToken string) (*githubUser, error) {
 
 // fetchGitHubUserOrgs retrieves the organizations the authenticated user
 // belongs to.
-func fetchGitHubUserOrgs(accessToken string) ([]githubOrg, error) {
+func fetchGitHubUserOrgs(baseURL, accessToken string) ([]githubOrg, error) {
 	req, err := http.NewRequest(
-		http.MethodGet, githubAPIBaseURL+"/user/orgs", nil,
+		http.MethodGet, baseURL+"/user/orgs", nil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -389,6 +528,39 @@ func fetchGitHubUserOrgs(accessToken string) ([]githubOrg, error) {
 	return orgs, nil
 }
 
+// fetchGitHubUserTeams retrieves the teams the authenticated user belongs
+// to across all organizations.
+func fetchGitHubUserTeams(baseURL, accessToken string) ([]githubTeam, error) {
+	req, err := http.NewRequest(
+		http.MethodGet, baseURL+"/user/teams?per_page=100", nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: githubHTTPTimeout}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching teams: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("github api returned status %d", resp.StatusCode)
+	}
+
+	var teams []githubTeam
+	if err := json.NewDecoder(resp.Body).Decode(&teams); err != nil {
+		return nil, fmt.Errorf("decoding teams: %w", err)
+	}
+
+	return teams, nil
+}
+
 // generateState creates a random OAuth state parameter.
 func generateState() (string, error) {
 	b := make([]byte, githubStateBytes)
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveGitHubRole_Teams(t *testing.T) {
+	srv, _, _ := setupTestServer(t)
+	ctx := context.Background()
+
+	srv.cfg.Auth.GitHub.TeamRoleMapping = map[string]string{
+		"ethpandaops/core":      "admin",
+		"ethpandaops/observers": "readonly",
+		"Other-Org/Benchmarks":  "admin",
+	}
+
+	require.NoError(t, srv.store.UpsertGitHubOrgMapping(ctx,
+		&store.GitHubOrgMapping{Org: "ethpandaops", Role: "readonly"}))
+	require.NoError(t, srv.store.UpsertGitHubOrgMapping(ctx,
+		&store.GitHubOrgMapping{Org: "admins-org", Role: "admin"}))
+	require.NoError(t, srv.store.UpsertGitHubUserMapping(ctx,
+		&store.GitHubUserMapping{Username: "pinned", Role: "readonly"}))
+
+	team := func(org, slug string) githubTeam {
+		return githubTeam{Slug: slug, Organization: githubOrg{Login: org}}
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		orgs     []githubOrg
+		teams    []githubTeam
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "team mapping grants admin over org readonly",
+			username: "alice",
+			orgs:     []githubOrg{{Login: "ethpandaops"}},
+			teams:    []githubTeam{team("ethpandaops", "core")},
+			want:     "admin",
+		},
+		{
+			name:     "highest privilege wins across teams",
+			username: "bob",
+			teams: []githubTeam{
+				team("ethpandaops", "observers"),
+				team("ethpandaops", "core"),
+			},
+			want: "admin",
+		},
+		{
+			name:     "team mapping takes precedence over org mapping",
+			username: "carol",
+			orgs:     []githubOrg{{Login: "admins-org"}},
+			teams:    []githubTeam{team("ethpandaops", "observers")},
+			want:     "readonly",
+		},
+		{
+			name:     "team keys match case-insensitively",
+			username: "dave",
+			teams:    []githubTeam{team("other-org", "benchmarks")},
+			want:     "admin",
+		},
+		{
+			name:     "falls back to org mapping without matching team",
+			username: "erin",
+			orgs:     []githubOrg{{Login: "ethpandaops"}},
+			teams:    []githubTeam{team("ethpandaops", "unmapped")},
+			want:     "readonly",
+		},
+		{
+			name:     "user mapping takes precedence over team mapping",
+			username: "pinned",
+			teams:    []githubTeam{team("ethpandaops", "core")},
+			want:     "readonly",
+		},
+		{
+			name:     "same team slug in another org does not match",
+			username: "frank",
+			teams:    []githubTeam{team("elsewhere", "core")},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := srv.resolveGitHubRole(
+				ctx, tt.username, tt.orgs, tt.teams,
+			)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, role)
+		})
+	}
+}
+
+func TestGitHubUserTeams_CachesMembership(t *testing.T) {
+	var calls atomic.Int32
+
+	gh := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/user/teams" {
+				http.NotFound(w, r)
+
+				return
+			}
+
+			assert.Equal(t, "Bearer token-123", r.Header.Get("Authorization"))
+			calls.Add(1)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(
+				`[{"slug":"core","organization":{"login":"ethpandaops"}}]`,
+			))
+		},
+	))
+	defer gh.Close()
+
+	srv, _, _ := setupTestServer(t)
+	srv.githubAPIURL = gh.URL
+
+	teams, err := srv.githubUserTeams("alice", "token-123")
+	require.NoError(t, err)
+	require.Len(t, teams, 1)
+	assert.Equal(t, "core", teams[0].Slug)
+	assert.Equal(t, "ethpandaops", teams[0].Organization.Login)
+
+	// A second lookup within the session TTL is served from the cache.
+	_, err = srv.githubUserTeams("Alice", "token-123")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Other users are fetched separately.
+	_, err = srv.githubUserTeams("bob", "token-123")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestGitHubUserTeams_APIError(t *testing.T) {
+	gh := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		},
+	))
+	defer gh.Close()
+
+	srv, _, _ := setupTestServer(t)
+	srv.githubAPIURL = gh.URL
+
+	_, err := srv.githubUserTeams("alice", "token-123")
+	require.Error(t, err)
+
+	// Failures are not cached.
+	_, ok := srv.teamCache.get("alice", time.Now())
+	assert.False(t, ok)
+}
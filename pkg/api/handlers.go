@@ -85,6 +85,9 @@ func (s *server) handleConfig(w http.ResponseWriter, _ *http.Request) {
 	resp["indexing"] = map[string]any{
 		"enabled": s.indexStore != nil,
 	}
+	resp["runs"] = map[string]any{
+		"enabled": s.jobQueue != nil,
+	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
@@ -100,6 +103,13 @@ func (s *server) handleFileRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.canAccessPath(r, filePath) {
+		writeJSON(w, http.StatusForbidden,
+			errorResponse{"path not allowed"})
+
+		return
+	}
+
 	// Local file serving takes priority.
 	if s.localServer != nil {
 		if err := s.localServer.ServeFile(w, r, filePath); err != nil {
@@ -176,6 +186,56 @@ func (s *server) handleS3Head(
 	w.WriteHeader(http.StatusOK)
 }
 
+type artifactURLResponse struct {
+	Key       string `json:"key"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// handleArtifactURL returns a presigned S3 URL for a single file inside a
+// run directory so the UI can link to large artifacts directly.
+func (s *server) handleArtifactURL(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	discoveryPath := q.Get("discovery_path")
+	runID := q.Get("run_id")
+	artifact := q.Get("path")
+
+	if discoveryPath == "" || runID == "" || artifact == "" {
+		writeJSON(w, http.StatusBadRequest,
+			errorResponse{"discovery_path, run_id and path are required"})
+
+		return
+	}
+
+	if !s.canAccessDiscoveryPath(r, discoveryPath) {
+		writeJSON(w, http.StatusForbidden,
+			errorResponse{"path not allowed"})
+
+		return
+	}
+
+	result, err := s.presigner.PresignArtifact(
+		r.Context(), discoveryPath, runID, artifact,
+	)
+	if err != nil {
+		s.log.WithError(err).
+			WithField("run_id", runID).
+			Warn("Failed to generate artifact URL")
+
+		writeJSON(w, http.StatusForbidden,
+			errorResponse{"path not allowed or presign failed"})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, artifactURLResponse{
+		Key:       result.Key,
+		URL:       result.URL,
+		ExpiresAt: result.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"),
+	})
+}
+
 // --- Auth handlers ---
 
 type loginRequest struct {
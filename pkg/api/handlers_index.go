@@ -35,6 +35,10 @@ func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) {
 	for i := range runs {
 		run := &runs[i]
 
+		if !s.canAccessDiscoveryPath(r, run.DiscoveryPath) {
+			continue
+		}
+
 		// Unmarshal steps JSON back to the struct.
 		var steps *executor.IndexStepsStats
 		if run.StepsJSON != "" {
@@ -114,8 +118,12 @@ func (s *server) handleSuiteStats(w http.ResponseWriter, r *http.Request) {
 
 	maxRuns = max(1, min(200, maxRuns))
 
+	// Test stats have no discovery path, so restricted callers only see
+	// the stats of runs under their allowed discovery paths.
+	discoveryPaths, _ := s.allowedDiscoveryPaths(r)
+
 	durations, err := s.indexStore.ListTestStatsBySuiteRecent(
-		r.Context(), suiteHash, maxRuns,
+		r.Context(), suiteHash, maxRuns, discoveryPaths,
 	)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError,
@@ -208,6 +216,8 @@ func (s *server) handleQueryRuns(w http.ResponseWriter, r *http.Request) {
 		r.Header.Get("Prefer"), "count=exact",
 	)
 
+	s.restrictQueryToAllowedPaths(r, params)
+
 	result, err := s.indexStore.QueryRuns(r.Context(), params)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError,
@@ -238,6 +248,8 @@ func (s *server) handleQueryTestStats(
 		r.Header.Get("Prefer"), "count=exact",
 	)
 
+	params.DiscoveryPaths, _ = s.allowedDiscoveryPaths(r)
+
 	result, err := s.indexStore.QueryTestStats(r.Context(), params)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError,
@@ -268,6 +280,8 @@ func (s *server) handleQuerySuites(
 		r.Header.Get("Prefer"), "count=exact",
 	)
 
+	s.restrictQueryToAllowedPaths(r, params)
+
 	result, err := s.indexStore.QuerySuites(r.Context(), params)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError,
@@ -298,6 +312,8 @@ func (s *server) handleQueryTestStatsBlockLogs(
 		r.Header.Get("Prefer"), "count=exact",
 	)
 
+	params.DiscoveryPaths, _ = s.allowedDiscoveryPaths(r)
+
 	result, err := s.indexStore.QueryTestStatsBlockLogs(
 		r.Context(), params,
 	)
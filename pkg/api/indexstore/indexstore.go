@@ -38,6 +38,7 @@ type Store interface {
 	) ([]TestStat, error)
 	ListTestStatsBySuiteRecent(
 		ctx context.Context, suiteHash string, maxRunsPerClient int,
+		discoveryPaths []string,
 	) ([]TestStat, error)
 	DeleteTestStatsForRun(ctx context.Context, runID string) error
 
@@ -482,17 +483,21 @@ type clientRunRow struct {
 
 // ListTestStatsBySuiteRecent returns test stats for the N most recent runs
 // per client within a suite. This avoids fetching the entire suite history
-// which can be millions of rows for large suites.
+// which can be millions of rows for large suites. Non-nil discoveryPaths
+// limit it to runs under these discovery paths.
 func (s *store) ListTestStatsBySuiteRecent(
 	ctx context.Context, suiteHash string, maxRunsPerClient int,
+	discoveryPaths []string,
 ) ([]TestStat, error) {
 	// Step 1: lightweight query to get distinct client/run combos.
-	var rows []clientRunRow
-	if err := s.readDB.WithContext(ctx).
+	q := s.readDB.WithContext(ctx).
 		Model(&TestStat{}).
 		Select("DISTINCT client, run_id, run_start").
-		Where("suite_hash = ?", suiteHash).
-		Order("run_start DESC").
+		Where("suite_hash = ?", suiteHash)
+	q = restrictToDiscoveryPaths(s.readDB.WithContext(ctx), q, discoveryPaths)
+
+	var rows []clientRunRow
+	if err := q.Order("run_start DESC").
 		Find(&rows).Error; err != nil {
 		return nil, fmt.Errorf(
 			"listing recent client runs: %w", err,
@@ -743,6 +748,9 @@ func (s *store) QueryTestStats(
 	q := applyQuery(
 		s.readDB.WithContext(ctx), &TestStat{}, params,
 	)
+	q = restrictToDiscoveryPaths(
+		s.readDB.WithContext(ctx), q, params.DiscoveryPaths,
+	)
 
 	// When select is specified, scan into maps so the JSON response
 	// only contains the requested columns (no zero-valued extras).
@@ -790,6 +798,9 @@ func (s *store) QueryTestStatsBlockLogs(
 	q := applyQuery(
 		s.readDB.WithContext(ctx), &TestStatsBlockLog{}, params,
 	)
+	q = restrictToDiscoveryPaths(
+		s.readDB.WithContext(ctx), q, params.DiscoveryPaths,
+	)
 
 	// When select is specified, scan into maps so the JSON response
 	// only contains the requested columns (no zero-valued extras).
@@ -166,6 +166,11 @@ type QueryParams struct {
 	Offset     int
 	Select     []string
 	CountExact bool
+
+	// DiscoveryPaths restricts queries on tables keyed by run_id, which
+	// have no discovery_path column, to rows of runs under these discovery
+	// paths. Nil applies no restriction.
+	DiscoveryPaths []string
 }
 
 // QueryResult wraps the paginated response.
@@ -480,6 +485,19 @@ func applyQuery(
 	return q
 }
 
+// restrictToDiscoveryPaths limits q, a query on a table keyed by run_id, to
+// rows of runs under the given discovery paths. db is used to build the runs
+// subquery. Nil paths apply no restriction.
+func restrictToDiscoveryPaths(db, q *gorm.DB, paths []string) *gorm.DB {
+	if paths == nil {
+		return q
+	}
+
+	return q.Where("run_id IN (?)",
+		db.Model(&Run{}).Select("run_id").Where("discovery_path IN ?", paths),
+	)
+}
+
 // applyFilter applies a single filter to the GORM chain.
 func applyFilter(db *gorm.DB, f Filter) *gorm.DB {
 	if f.Operator == "is" {
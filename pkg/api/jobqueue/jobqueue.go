@@ -0,0 +1,265 @@
+package jobqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	jobIDBytes = 8
+
+	// defaultConcurrency is the number of jobs executed in parallel when
+	// no explicit concurrency value is configured. Benchmarks compete for
+	// the same host resources, so one at a time is the safe default.
+	defaultConcurrency = 1
+
+	// defaultQueueSize is the maximum number of jobs waiting to run when
+	// no explicit queue size is configured.
+	defaultQueueSize = 100
+)
+
+// ErrQueueFull is returned by Enqueue when no more jobs can be accepted.
+var ErrQueueFull = errors.New("job queue is full")
+
+// Spec is the user-supplied description of a remote run. Config is a
+// subset of the benchmarkoor config that is layered on top of the
+// server's base config files.
+type Spec struct {
+	Config           map[string]any `json:"config"`
+	LimitInstanceIDs []string       `json:"limit_instance_ids,omitempty"`
+}
+
+// Executor runs a single job to completion.
+type Executor interface {
+	Execute(ctx context.Context, job *store.Job) error
+}
+
+// Queue persists submitted jobs and executes them in the background.
+type Queue interface {
+	Start(ctx context.Context) error
+	Stop() error
+	// Enqueue assigns an ID to the job, stores it as queued and schedules
+	// it for execution.
+	Enqueue(ctx context.Context, job *store.Job) error
+}
+
+// Compile-time interface check.
+var _ Queue = (*queue)(nil)
+
+type queue struct {
+	log         logrus.FieldLogger
+	store       store.Store
+	executor    Executor
+	concurrency int
+	pending     chan string
+	sendMu      sync.Mutex // serializes sends to pending
+	done        chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewQueue creates a new job queue backed by the given store.
+func NewQueue(
+	log logrus.FieldLogger,
+	st store.Store,
+	executor Executor,
+	concurrency int,
+	queueSize int,
+) Queue {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	return &queue{
+		log:         log.WithField("component", "jobqueue"),
+		store:       st,
+		executor:    executor,
+		concurrency: concurrency,
+		pending:     make(chan string, queueSize),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start recovers jobs left over from a previous server process and
+// launches the worker goroutines. Jobs that were running when the server
+// stopped are marked failed; queued jobs are rescheduled.
+func (q *queue) Start(ctx context.Context) error {
+	running, err := q.store.ListJobsByStatus(ctx, store.JobStatusRunning)
+	if err != nil {
+		return fmt.Errorf("listing running jobs: %w", err)
+	}
+
+	for i := range running {
+		q.finish(ctx, &running[i], errors.New("interrupted by server restart"))
+	}
+
+	queued, err := q.store.ListJobsByStatus(ctx, store.JobStatusQueued)
+	if err != nil {
+		return fmt.Errorf("listing queued jobs: %w", err)
+	}
+
+	for i := range queued {
+		select {
+		case q.pending <- queued[i].ID:
+		default:
+			q.finish(ctx, &queued[i], ErrQueueFull)
+		}
+	}
+
+	q.log.WithFields(logrus.Fields{
+		"concurrency": q.concurrency,
+		"recovered":   len(queued),
+	}).Info("Starting job queue")
+
+	for range q.concurrency {
+		q.wg.Add(1)
+
+		go func() {
+			defer q.wg.Done()
+
+			q.worker(ctx)
+		}()
+	}
+
+	return nil
+}
+
+// Stop signals the workers to stop and waits for running jobs to return.
+func (q *queue) Stop() error {
+	close(q.done)
+	q.wg.Wait()
+
+	q.log.Info("Job queue stopped")
+
+	return nil
+}
+
+// Enqueue stores the job as queued and schedules it for execution.
+func (q *queue) Enqueue(ctx context.Context, job *store.Job) error {
+	id, err := generateJobID()
+	if err != nil {
+		return err
+	}
+
+	job.ID = id
+	job.Status = store.JobStatusQueued
+
+	// All senders hold sendMu, so a free slot observed here is still free
+	// when the ID is pushed and the send below never blocks.
+	q.sendMu.Lock()
+	defer q.sendMu.Unlock()
+
+	if len(q.pending) == cap(q.pending) {
+		return ErrQueueFull
+	}
+
+	if err := q.store.CreateJob(ctx, job); err != nil {
+		return err
+	}
+
+	q.pending <- job.ID
+
+	q.log.WithField("job_id", job.ID).Info("Job queued")
+
+	return nil
+}
+
+// worker pulls job IDs off the pending channel and executes them.
+func (q *queue) worker(ctx context.Context) {
+	for {
+		select {
+		case <-q.done:
+			return
+		case <-ctx.Done():
+			return
+		case id := <-q.pending:
+			q.run(ctx, id)
+		}
+	}
+}
+
+// run executes a single job and records its outcome.
+func (q *queue) run(ctx context.Context, id string) {
+	log := q.log.WithField("job_id", id)
+
+	job, err := q.store.GetJob(ctx, id)
+	if err != nil {
+		log.WithError(err).Warn("Failed to load queued job")
+
+		return
+	}
+
+	now := time.Now().UTC()
+	job.Status = store.JobStatusRunning
+	job.StartedAt = &now
+
+	if err := q.store.UpdateJob(ctx, job); err != nil {
+		log.WithError(err).Warn("Failed to mark job running")
+
+		return
+	}
+
+	// Cancel the job when the queue is stopped.
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-q.done:
+			cancel()
+		case <-jobCtx.Done():
+		}
+	}()
+
+	log.Info("Job started")
+
+	execErr := q.executor.Execute(jobCtx, job)
+
+	// Record the outcome even if the lifecycle context was canceled.
+	q.finish(context.WithoutCancel(ctx), job, execErr)
+}
+
+// finish marks the job as completed or failed depending on err.
+func (q *queue) finish(ctx context.Context, job *store.Job, err error) {
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	job.Status = store.JobStatusCompleted
+
+	if err != nil {
+		job.Status = store.JobStatusFailed
+		job.Error = err.Error()
+	}
+
+	if updateErr := q.store.UpdateJob(ctx, job); updateErr != nil {
+		q.log.WithError(updateErr).WithField("job_id", job.ID).
+			Warn("Failed to record job outcome")
+
+		return
+	}
+
+	q.log.WithFields(logrus.Fields{
+		"job_id": job.ID,
+		"status": job.Status,
+	}).Info("Job finished")
+}
+
+// generateJobID creates a random hex job ID.
+func generateJobID() (string, error) {
+	b := make([]byte, jobIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating job id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,144 @@
+package jobqueue_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/jobqueue"
+	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+)
+
+type executorFunc func(ctx context.Context, job *store.Job) error
+
+func (f executorFunc) Execute(ctx context.Context, job *store.Job) error {
+	return f(ctx, job)
+}
+
+func setupTestStore(t *testing.T) store.Store {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	s := store.NewStore(log, &config.APIDatabaseConfig{
+		Driver: "sqlite",
+		SQLite: config.SQLiteDatabaseConfig{Path: ":memory:"},
+	})
+	require.NoError(t, s.Start(context.Background()))
+
+	t.Cleanup(func() { _ = s.Stop() })
+
+	return s
+}
+
+func waitForStatus(
+	t *testing.T, s store.Store, id, status string,
+) *store.Job {
+	t.Helper()
+
+	var job *store.Job
+
+	require.Eventually(t, func() bool {
+		j, err := s.GetJob(context.Background(), id)
+		if err != nil {
+			return false
+		}
+
+		job = j
+
+		return j.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return job
+}
+
+func TestQueue_ExecutesJobs(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	q := jobqueue.NewQueue(log, s, executorFunc(
+		func(_ context.Context, job *store.Job) error {
+			if job.Spec == "fail" {
+				return errors.New("boom")
+			}
+
+			return nil
+		},
+	), 1, 10)
+	require.NoError(t, q.Start(ctx))
+
+	t.Cleanup(func() { _ = q.Stop() })
+
+	ok := &store.Job{Spec: "ok", CreatedBy: 1}
+	require.NoError(t, q.Enqueue(ctx, ok))
+	assert.NotEmpty(t, ok.ID)
+
+	bad := &store.Job{Spec: "fail", CreatedBy: 1}
+	require.NoError(t, q.Enqueue(ctx, bad))
+
+	done := waitForStatus(t, s, ok.ID, store.JobStatusCompleted)
+	assert.NotNil(t, done.StartedAt)
+	assert.NotNil(t, done.FinishedAt)
+
+	failed := waitForStatus(t, s, bad.ID, store.JobStatusFailed)
+	assert.Equal(t, "boom", failed.Error)
+}
+
+func TestQueue_RejectsWhenFull(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	// Not started, so nothing drains the pending channel.
+	q := jobqueue.NewQueue(log, s, executorFunc(
+		func(context.Context, *store.Job) error { return nil },
+	), 1, 1)
+
+	require.NoError(t, q.Enqueue(ctx, &store.Job{Spec: "a"}))
+
+	err := q.Enqueue(ctx, &store.Job{Spec: "b"})
+	require.ErrorIs(t, err, jobqueue.ErrQueueFull)
+
+	jobs, err := s.ListJobs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestQueue_RecoversOnStart(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.CreateJob(ctx, &store.Job{
+		ID: "stale", Status: store.JobStatusRunning, Spec: "x",
+	}))
+	require.NoError(t, s.CreateJob(ctx, &store.Job{
+		ID: "pending", Status: store.JobStatusQueued, Spec: "x",
+	}))
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	q := jobqueue.NewQueue(log, s, executorFunc(
+		func(context.Context, *store.Job) error { return nil },
+	), 1, 10)
+	require.NoError(t, q.Start(ctx))
+
+	t.Cleanup(func() { _ = q.Stop() })
+
+	stale := waitForStatus(t, s, "stale", store.JobStatusFailed)
+	assert.Contains(t, stale.Error, "restart")
+
+	waitForStatus(t, s, "pending", store.JobStatusCompleted)
+}
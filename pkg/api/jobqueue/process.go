@@ -0,0 +1,139 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SpecFileName is the name of the per-job config overlay file.
+	SpecFileName = "spec.json"
+
+	// LogFileName is the name of the per-job log file capturing the
+	// child process' stdout and stderr.
+	LogFileName = "benchmarkoor.log"
+
+	// RunDirsFileName is the name of the per-job file the child process
+	// appends each run directory to, one per line.
+	RunDirsFileName = "run_dirs"
+)
+
+// Compile-time interface check.
+var _ Executor = (*processExecutor)(nil)
+
+type processExecutor struct {
+	log         logrus.FieldLogger
+	binary      string
+	baseConfigs []string
+	workDir     string
+}
+
+// NewProcessExecutor returns an Executor that runs each job by invoking
+// `<binary> run` with the base config files followed by the job's spec
+// overlay. Each job gets its own directory under workDir holding the
+// overlay and the process log.
+func NewProcessExecutor(
+	log logrus.FieldLogger,
+	binary string,
+	baseConfigs []string,
+	workDir string,
+) Executor {
+	return &processExecutor{
+		log:         log.WithField("component", "job-executor"),
+		binary:      binary,
+		baseConfigs: baseConfigs,
+		workDir:     workDir,
+	}
+}
+
+// JobDir returns the directory holding the files for the given job.
+func JobDir(workDir, jobID string) string {
+	return filepath.Join(workDir, jobID)
+}
+
+// ReadRunDirs returns the run directories the given job has created so far,
+// in order. It returns nil before the first one is created.
+func ReadRunDirs(workDir, jobID string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(JobDir(workDir, jobID), RunDirsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading run dirs: %w", err)
+	}
+
+	// Drop a trailing line that is still being written.
+	lines := strings.Split(string(data), "\n")
+
+	return slices.DeleteFunc(lines[:len(lines)-1], func(line string) bool {
+		return line == ""
+	}), nil
+}
+
+// Execute writes the job's config overlay and runs benchmarkoor against it.
+func (e *processExecutor) Execute(ctx context.Context, job *store.Job) error {
+	var spec Spec
+	if err := json.Unmarshal([]byte(job.Spec), &spec); err != nil {
+		return fmt.Errorf("parsing job spec: %w", err)
+	}
+
+	dir := JobDir(e.workDir, job.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating job directory: %w", err)
+	}
+
+	// JSON is valid YAML, so the config loader accepts the overlay as is.
+	overlay, err := json.Marshal(spec.Config)
+	if err != nil {
+		return fmt.Errorf("marshaling config overlay: %w", err)
+	}
+
+	specPath := filepath.Join(dir, SpecFileName)
+	if err := os.WriteFile(specPath, overlay, 0600); err != nil {
+		return fmt.Errorf("writing config overlay: %w", err)
+	}
+
+	logFile, err := os.Create(filepath.Join(dir, LogFileName))
+	if err != nil {
+		return fmt.Errorf("creating job log file: %w", err)
+	}
+	defer func() { _ = logFile.Close() }()
+
+	args := make([]string, 0, 2*(len(e.baseConfigs)+len(spec.LimitInstanceIDs))+5)
+	args = append(args, "run", "--run-dirs-file", filepath.Join(dir, RunDirsFileName))
+
+	for _, path := range e.baseConfigs {
+		args = append(args, "--config", path)
+	}
+
+	args = append(args, "--config", specPath)
+
+	for _, id := range spec.LimitInstanceIDs {
+		args = append(args, "--limit-instance-id", id)
+	}
+
+	cmd := exec.CommandContext(ctx, e.binary, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	e.log.WithFields(logrus.Fields{
+		"job_id": job.ID,
+		"dir":    dir,
+	}).Info("Executing benchmark run")
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("benchmark run failed: %w", err)
+	}
+
+	return nil
+}
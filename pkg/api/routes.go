@@ -15,8 +15,21 @@ func (s *server) buildRouter() http.Handler {
 	// Global middleware.
 	r.Use(chimw.Recoverer)
 	r.Use(s.requestLogger)
+
+	if s.cfg.Server.Metrics.Enabled {
+		if s.httpMetrics == nil {
+			s.httpMetrics = newHTTPMetrics()
+		}
+
+		r.Use(s.httpMetrics.middleware)
+	}
+
 	r.Use(s.corsMiddleware())
-	r.Use(chimw.Compress(5))
+	r.Use(s.compressMiddleware())
+
+	if s.httpMetrics != nil {
+		r.Handle("/metrics", s.httpMetrics.handler())
+	}
 
 	r.Route("/api/v1", func(r chi.Router) {
 		// Public endpoints.
@@ -38,6 +51,7 @@ func (s *server) buildRouter() http.Handler {
 
 			r.Group(func(r chi.Router) {
 				r.Use(s.requireAuth)
+				r.Use(s.auditLog)
 				r.Get("/me", s.handleMe)
 
 				// API key management (authenticated users).
@@ -69,6 +83,23 @@ func (s *server) buildRouter() http.Handler {
 			r.Head("/*", s.handleFileRequest)
 		})
 
+		// Presigned artifact URLs (S3 storage only).
+		if s.presigner != nil {
+			r.Route("/artifacts", func(r chi.Router) {
+				if !s.cfg.Auth.AnonymousRead {
+					r.Use(s.requireAuth)
+				}
+
+				if s.cfg.Server.RateLimit.Enabled {
+					r.Use(s.rateLimitMiddleware(
+						s.cfg.Server.RateLimit.Authenticated,
+					))
+				}
+
+				r.Get("/url", s.handleArtifactURL)
+			})
+		}
+
 		// Index endpoints (when indexing is enabled).
 		if s.indexStore != nil {
 			r.Route("/index", func(r chi.Router) {
@@ -96,10 +127,31 @@ func (s *server) buildRouter() http.Handler {
 			})
 		}
 
+		// Remote run endpoints (require auth + admin role).
+		if s.jobQueue != nil {
+			r.Route("/runs", func(r chi.Router) {
+				r.Use(s.requireAuth)
+				r.Use(s.requireRole("admin"))
+				r.Use(s.auditLog)
+
+				if s.cfg.Server.RateLimit.Enabled {
+					r.Use(s.rateLimitMiddleware(
+						s.cfg.Server.RateLimit.Authenticated,
+					))
+				}
+
+				r.Get("/", s.handleListRuns)
+				r.Post("/", s.handleCreateRun)
+				r.Get("/{id}", s.handleGetRun)
+				r.Get("/{id}/logs", s.handleStreamRunLogs)
+			})
+		}
+
 		// Admin endpoints (require auth + admin role).
 		r.Route("/admin", func(r chi.Router) {
 			r.Use(s.requireAuth)
 			r.Use(s.requireRole("admin"))
+			r.Use(s.auditLog)
 
 			if s.cfg.Server.RateLimit.Enabled {
 				r.Use(s.rateLimitMiddleware(
@@ -112,6 +164,7 @@ func (s *server) buildRouter() http.Handler {
 			r.Post("/users", s.handleCreateUser)
 			r.Put("/users/{id}", s.handleUpdateUser)
 			r.Delete("/users/{id}", s.handleDeleteUser)
+			r.Delete("/users/{id}/sessions", s.handleRevokeUserSessions)
 
 			// Session management.
 			r.Get("/sessions", s.handleListSessions)
@@ -135,6 +188,9 @@ func (s *server) buildRouter() http.Handler {
 			r.Delete("/github/user-mappings/{id}",
 				s.handleDeleteUserMapping)
 
+			// Audit log of mutating requests.
+			r.Get("/audit-log", s.handleListAuditLog)
+
 			// Run deletion (requires indexing).
 			if s.indexStore != nil {
 				r.Post("/runs/delete", s.handleDeleteRuns)
@@ -151,23 +207,29 @@ func (s *server) buildRouter() http.Handler {
 }
 
 // corsMiddleware returns a CORS handler configured from the API config.
+// Without allowed origins no CORS headers are sent, restricting browsers
+// to same-origin requests.
 func (s *server) corsMiddleware() func(http.Handler) http.Handler {
-	opts := cors.Options{
-		AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "Prefer"},
-		AllowCredentials: true,
-		MaxAge:           300,
+	cfg := s.cfg.Server.CORS
+
+	if len(cfg.AllowedOrigins) == 0 {
+		return func(next http.Handler) http.Handler { return next }
 	}
 
-	origins := s.cfg.Server.CORSOrigins
+	opts := cors.Options{
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		AllowCredentials: cfg.AllowCredentials != nil && *cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
 
-	if len(origins) == 0 || (len(origins) == 1 && origins[0] == "*") {
+	if len(cfg.AllowedOrigins) == 1 && cfg.AllowedOrigins[0] == "*" {
 		// Reflect the requesting origin so credentials work from any origin.
 		opts.AllowOriginFunc = func(_ *http.Request, _ string) bool {
 			return true
 		}
 	} else {
-		opts.AllowedOrigins = origins
+		opts.AllowedOrigins = cfg.AllowedOrigins
 	}
 
 	return cors.Handler(opts)
@@ -0,0 +1,519 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/jobqueue"
+	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// maxRunSpecBytes bounds the size of a submitted run spec.
+	maxRunSpecBytes = 1 << 20
+
+	// runLogPollInterval is how often a followed log file is checked for
+	// new output while its run is still in progress.
+	runLogPollInterval = 500 * time.Millisecond
+)
+
+// allowedRunSpecSections lists the top-level config sections a remote run
+// spec may override. The api section is deliberately excluded.
+var allowedRunSpecSections = map[string]struct{}{
+	"global": {},
+	"runner": {},
+}
+
+// deniedRunSpecKeys lists config keys a remote run spec may not set, as
+// dotted paths below the spec root where "[]" matches every list item and
+// "*" every map value. They run commands on the API host or give the run
+// access to its files and devices, so only the base configs may set them.
+var deniedRunSpecKeys = []string{
+	"runner.post_run_command",
+	"runner.drop_caches_command",
+	"runner.client.config.pre_run_command",
+	"runner.client.datadirs.*.source_dir",
+}
+
+// deniedRunSpecInstanceKeys lists the instance fields a remote run spec may
+// not set, in an instance, in runner.instance_defaults, which is copied into
+// every instance, or through a matrix axis.
+var deniedRunSpecInstanceKeys = []string{
+	"pre_run_command",
+	"secret_files",
+	"devices",
+	"datadir.source_dir",
+	"build.context",
+}
+
+// runSpecInstancePaths are the paths of the spec holding instance fields.
+var runSpecInstancePaths = []string{
+	"runner.instances[]",
+	"runner.instance_defaults",
+}
+
+// validateRunSpecConfig rejects denied keys and environment variable
+// references in a remote run spec. The overlay is loaded like any config
+// file, so a "$" would otherwise expand the API host's environment into the
+// run's config.json.
+func validateRunSpecConfig(cfg map[string]any) error {
+	denied := slices.Clone(deniedRunSpecKeys)
+
+	for _, instancePath := range runSpecInstancePaths {
+		for _, key := range deniedRunSpecInstanceKeys {
+			denied = append(denied, instancePath+"."+key)
+		}
+	}
+
+	for _, key := range denied {
+		if specHasKey(cfg, strings.Split(key, ".")) {
+			return fmt.Errorf("config key %q is not allowed", key)
+		}
+	}
+
+	if err := specRejectMatrixAxes(cfg); err != nil {
+		return err
+	}
+
+	return specRejectEnvRefs(cfg, "")
+}
+
+// specRejectMatrixAxes returns an error for the first matrix axis of an
+// instance that sets a denied instance field, or an object holding one.
+func specRejectMatrixAxes(cfg map[string]any) error {
+	runner, _ := cfg["runner"].(map[string]any)
+	instances, _ := runner["instances"].([]any)
+
+	for _, item := range instances {
+		instance, _ := item.(map[string]any)
+		matrix, _ := instance["matrix"].(map[string]any)
+
+		for axis := range matrix {
+			for _, key := range deniedRunSpecInstanceKeys {
+				if axis == key || strings.HasPrefix(key, axis+".") ||
+					strings.HasPrefix(axis, key+".") {
+					return fmt.Errorf("matrix axis %q is not allowed", axis)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// specHasKey reports whether the path of keys exists in value. A key with a
+// "[]" suffix descends into every item of the list it names, a "*" key into
+// every value of the map.
+func specHasKey(value any, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+
+	m, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+
+	if path[0] == "*" {
+		for _, child := range m {
+			if specHasKey(child, path[1:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	key, isList := strings.CutSuffix(path[0], "[]")
+
+	child, ok := m[key]
+	if !ok {
+		return false
+	}
+
+	if !isList {
+		return specHasKey(child, path[1:])
+	}
+
+	items, ok := child.([]any)
+	if !ok {
+		return false
+	}
+
+	for _, item := range items {
+		if specHasKey(item, path[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// specRejectEnvRefs returns an error for the first key or string value under
+// value that contains a "$".
+func specRejectEnvRefs(value any, at string) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			keyPath := key
+			if at != "" {
+				keyPath = at + "." + key
+			}
+
+			if strings.Contains(key, "$") {
+				return fmt.Errorf(
+					"config key %q must not reference environment variables", keyPath)
+			}
+
+			if err := specRejectEnvRefs(child, keyPath); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if err := specRejectEnvRefs(item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case string:
+		if strings.Contains(v, "$") {
+			return fmt.Errorf(
+				"config key %q must not reference environment variables", at)
+		}
+	}
+
+	return nil
+}
+
+type runJobResponse struct {
+	ID         string  `json:"id"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	CreatedBy  uint    `json:"created_by"`
+	CreatedAt  string  `json:"created_at"`
+	StartedAt  *string `json:"started_at"`
+	FinishedAt *string `json:"finished_at"`
+}
+
+func toRunJobResponse(j *store.Job) runJobResponse {
+	resp := runJobResponse{
+		ID:        j.ID,
+		Status:    j.Status,
+		Error:     j.Error,
+		CreatedBy: j.CreatedBy,
+		CreatedAt: j.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+
+	if j.StartedAt != nil {
+		s := j.StartedAt.UTC().Format("2006-01-02T15:04:05Z")
+		resp.StartedAt = &s
+	}
+
+	if j.FinishedAt != nil {
+		s := j.FinishedAt.UTC().Format("2006-01-02T15:04:05Z")
+		resp.FinishedAt = &s
+	}
+
+	return resp
+}
+
+// handleCreateRun validates a run spec and enqueues it for execution.
+func (s *server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	if user == nil {
+		writeJSON(w, http.StatusUnauthorized,
+			errorResponse{"not authenticated"})
+
+		return
+	}
+
+	var spec jobqueue.Spec
+	if err := json.NewDecoder(
+		http.MaxBytesReader(w, r.Body, maxRunSpecBytes),
+	).Decode(&spec); err != nil {
+		writeJSON(w, http.StatusBadRequest,
+			errorResponse{"invalid request body"})
+
+		return
+	}
+
+	if len(spec.Config) == 0 {
+		writeJSON(w, http.StatusBadRequest,
+			errorResponse{"config is required"})
+
+		return
+	}
+
+	for section := range spec.Config {
+		if _, ok := allowedRunSpecSections[section]; !ok {
+			writeJSON(w, http.StatusBadRequest,
+				errorResponse{"config section \"" + section +
+					"\" is not allowed (use \"global\" or \"runner\")"})
+
+			return
+		}
+	}
+
+	if err := validateRunSpecConfig(spec.Config); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+
+		return
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		s.log.WithError(err).Error("Failed to marshal run spec")
+		writeJSON(w, http.StatusInternalServerError,
+			errorResponse{"internal error"})
+
+		return
+	}
+
+	job := &store.Job{
+		Spec:      string(data),
+		CreatedBy: user.ID,
+	}
+
+	if err := s.jobQueue.Enqueue(r.Context(), job); err != nil {
+		if errors.Is(err, jobqueue.ErrQueueFull) {
+			writeJSON(w, http.StatusServiceUnavailable,
+				errorResponse{err.Error()})
+
+			return
+		}
+
+		s.log.WithError(err).Error("Failed to enqueue run")
+		writeJSON(w, http.StatusInternalServerError,
+			errorResponse{"internal error"})
+
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, toRunJobResponse(job))
+}
+
+// handleListRuns returns all remote runs, newest first.
+func (s *server) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	jobs, err := s.store.ListJobs(r.Context())
+	if err != nil {
+		s.log.WithError(err).Error("Failed to list runs")
+		writeJSON(w, http.StatusInternalServerError,
+			errorResponse{"internal error"})
+
+		return
+	}
+
+	resp := make([]runJobResponse, 0, len(jobs))
+	for i := range jobs {
+		resp = append(resp, toRunJobResponse(&jobs[i]))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleGetRun returns the status of a single remote run.
+func (s *server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	job, err := s.store.GetJob(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound,
+			errorResponse{"run not found"})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toRunJobResponse(job))
+}
+
+// handleStreamRunLogs streams a run's log file using chunked transfer
+// encoding. While the run is queued or running, the response stays open
+// and new output is flushed as it is written. The stream ends once the
+// run has finished and the file has been fully sent.
+//
+// The stream query parameter selects the file: "process" (the default) is
+// the output of the benchmarkoor process, while "benchmarkoor" and
+// "container" are the benchmarkoor.log and container.log of one of the
+// job's run directories, picked by the zero-based run parameter and
+// defaulting to the latest run directory when the file is opened.
+func (s *server) handleStreamRunLogs(w http.ResponseWriter, r *http.Request) {
+	job, err := s.store.GetJob(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound,
+			errorResponse{"run not found"})
+
+		return
+	}
+
+	logPath, err := s.runLogPath(job.ID, r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError,
+			errorResponse{"streaming not supported"})
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var logFile *os.File
+
+	defer func() {
+		if logFile != nil {
+			_ = logFile.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(runLogPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Snapshot the status before draining so output written just
+		// before the run finished is still sent.
+		finished := job.Status == store.JobStatusCompleted ||
+			job.Status == store.JobStatusFailed
+
+		// The log file only exists once the run has started.
+		if logFile == nil {
+			logFile, err = openRunLog(logPath)
+			if err != nil {
+				s.log.WithError(err).WithField("job_id", job.ID).
+					Warn("Failed to open run log")
+
+				return
+			}
+		}
+
+		if logFile != nil {
+			n, err := io.Copy(w, logFile)
+			if err != nil {
+				return
+			}
+
+			if n > 0 {
+				flusher.Flush()
+			}
+		}
+
+		if finished {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		job, err = s.store.GetJob(r.Context(), job.ID)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// runLogStreams maps the stream parameter of the logs endpoint to the file
+// streamed from one of the job's run directories.
+var runLogStreams = map[string]string{
+	"benchmarkoor": "benchmarkoor.log",
+	"container":    "container.log",
+}
+
+// runLogPath returns a function resolving the log file selected by the
+// stream and run query parameters. It resolves to "" while the file's run
+// directory has not been created yet.
+func (s *server) runLogPath(
+	jobID string, query url.Values,
+) (func() (string, error), error) {
+	stream := query.Get("stream")
+	runParam := query.Get("run")
+
+	if stream == "" || stream == "process" {
+		if runParam != "" {
+			return nil, errors.New(
+				"run requires stream benchmarkoor or container")
+		}
+
+		path := filepath.Join(
+			jobqueue.JobDir(s.cfg.Runs.WorkDir, jobID), jobqueue.LogFileName,
+		)
+
+		return func() (string, error) { return path, nil }, nil
+	}
+
+	fileName, ok := runLogStreams[stream]
+	if !ok {
+		return nil, fmt.Errorf(
+			"invalid stream %q: must be process, benchmarkoor or container",
+			stream)
+	}
+
+	index := -1
+
+	if runParam != "" {
+		n, err := strconv.Atoi(runParam)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf(
+				"invalid run %q: must be a non-negative integer", runParam)
+		}
+
+		index = n
+	}
+
+	return func() (string, error) {
+		dirs, err := jobqueue.ReadRunDirs(s.cfg.Runs.WorkDir, jobID)
+		if err != nil {
+			return "", err
+		}
+
+		i := index
+		if i < 0 {
+			i = len(dirs) - 1
+		}
+
+		if i < 0 || i >= len(dirs) {
+			return "", nil
+		}
+
+		return filepath.Join(dirs[i], fileName), nil
+	}, nil
+}
+
+// openRunLog opens the log file resolved by logPath. It returns nil
+// without error while the file does not exist yet.
+func openRunLog(logPath func() (string, error)) (*os.File, error) {
+	path, err := logPath()
+	if err != nil || path == "" {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,501 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/jobqueue"
+	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQueue records enqueued jobs and persists them without executing.
+type fakeQueue struct {
+	mu    sync.Mutex
+	store store.Store
+	jobs  []*store.Job
+	err   error
+}
+
+func (q *fakeQueue) Start(context.Context) error { return nil }
+func (q *fakeQueue) Stop() error                 { return nil }
+
+func (q *fakeQueue) Enqueue(ctx context.Context, job *store.Job) error {
+	if q.err != nil {
+		return q.err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job.ID = "job" + string(rune('a'+len(q.jobs)))
+	job.Status = store.JobStatusQueued
+	q.jobs = append(q.jobs, job)
+
+	return q.store.CreateJob(ctx, job)
+}
+
+// setupTestServer returns a server backed by an in-memory store along
+// with session tokens for an admin and a readonly user.
+func setupTestServer(t *testing.T) (srv *server, adminToken, readonlyToken string) {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	st := store.NewStore(log, &config.APIDatabaseConfig{
+		Driver: "sqlite",
+		SQLite: config.SQLiteDatabaseConfig{Path: ":memory:"},
+	})
+	require.NoError(t, st.Start(context.Background()))
+
+	t.Cleanup(func() { _ = st.Stop() })
+
+	srv = &server{
+		log: log,
+		cfg: &config.APIConfig{
+			Auth: config.APIAuthConfig{SessionTTL: "1h"},
+		},
+		store: st,
+		done:  make(chan struct{}),
+	}
+
+	adminToken = createTestSession(t, st, "admin-user", "admin")
+	readonlyToken = createTestSession(t, st, "readonly-user", "readonly")
+
+	return srv, adminToken, readonlyToken
+}
+
+// createTestSession creates a user with the given role and returns a
+// valid session token for it.
+func createTestSession(
+	t *testing.T, st store.Store, username, role string,
+) string {
+	t.Helper()
+
+	ctx := context.Background()
+
+	user := &store.User{
+		Username:     username,
+		PasswordHash: "x",
+		Role:         role,
+		Source:       store.SourceAdmin,
+	}
+	require.NoError(t, st.CreateUser(ctx, user))
+
+	token := "token-" + username
+	require.NoError(t, st.CreateSession(ctx, &store.Session{
+		Token:     token,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	}))
+
+	return token
+}
+
+// doRequest issues a request against the server's router, optionally
+// authenticated with the given session token.
+func doRequest(
+	t *testing.T, handler http.Handler, method, path, token, body string,
+) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.AddCookie(&http.Cookie{Name: "benchmarkoor_session", Value: token})
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRuns_AuthEnforcement(t *testing.T) {
+	srv, _, readonlyToken := setupTestServer(t)
+	srv.jobQueue = &fakeQueue{store: srv.store}
+	router := srv.buildRouter()
+
+	body := `{"config":{"runner":{"instances":[{"id":"geth","client":"geth"}]}}}`
+
+	t.Run("unauthenticated is rejected", func(t *testing.T) {
+		rec := doRequest(t, router, http.MethodPost, "/api/v1/runs", "", body)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("readonly role is rejected", func(t *testing.T) {
+		rec := doRequest(
+			t, router, http.MethodPost, "/api/v1/runs", readonlyToken, body,
+		)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("readonly cannot list runs", func(t *testing.T) {
+		rec := doRequest(
+			t, router, http.MethodGet, "/api/v1/runs", readonlyToken, "",
+		)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestRuns_RoutesDisabledWithoutQueue(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	router := srv.buildRouter()
+
+	rec := doRequest(
+		t, router, http.MethodPost, "/api/v1/runs", adminToken, `{}`,
+	)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRuns_CreateAndPoll(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	queue := &fakeQueue{store: srv.store}
+	srv.jobQueue = queue
+	router := srv.buildRouter()
+
+	body := `{"config":{"runner":{"instances":[{"id":"geth","client":"geth"}]}},` +
+		`"limit_instance_ids":["geth"]}`
+
+	rec := doRequest(
+		t, router, http.MethodPost, "/api/v1/runs", adminToken, body,
+	)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	var created runJobResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, store.JobStatusQueued, created.Status)
+
+	// The stored spec round-trips into the queue's Spec type.
+	require.Len(t, queue.jobs, 1)
+
+	var spec jobqueue.Spec
+	require.NoError(t, json.Unmarshal([]byte(queue.jobs[0].Spec), &spec))
+	assert.Equal(t, []string{"geth"}, spec.LimitInstanceIDs)
+	assert.Contains(t, spec.Config, "runner")
+
+	// Poll the job by ID.
+	rec = doRequest(
+		t, router, http.MethodGet, "/api/v1/runs/"+created.ID, adminToken, "",
+	)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var polled runJobResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &polled))
+	assert.Equal(t, created.ID, polled.ID)
+
+	// List contains the job.
+	rec = doRequest(t, router, http.MethodGet, "/api/v1/runs", adminToken, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var listed []runJobResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, created.ID, listed[0].ID)
+
+	// Unknown IDs return 404.
+	rec = doRequest(
+		t, router, http.MethodGet, "/api/v1/runs/missing", adminToken, "",
+	)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRuns_CreateValidation(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	srv.jobQueue = &fakeQueue{store: srv.store}
+	router := srv.buildRouter()
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{name: "invalid json", body: `{`, code: http.StatusBadRequest},
+		{name: "missing config", body: `{}`, code: http.StatusBadRequest},
+		{
+			name: "api section rejected",
+			body: `{"config":{"api":{"server":{"listen":":1"}}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "post run command rejected",
+			body: `{"config":{"runner":{"post_run_command":["sh","-c","id"]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "drop caches command rejected",
+			body: `{"config":{"runner":{"drop_caches_command":["id"]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "client pre run command rejected",
+			body: `{"config":{"runner":{"client":{"config":{"pre_run_command":["id"]}}}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "instance pre run command rejected",
+			body: `{"config":{"runner":{"instances":[` +
+				`{"id":"a","client":"geth"},` +
+				`{"id":"b","client":"geth","pre_run_command":["id"]}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "instance secret files rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"secret_files":[{"file":"/etc/shadow","container_path":"/s"}]}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "instance default pre run command rejected",
+			body: `{"config":{"runner":{"instance_defaults":{"pre_run_command":["id"]}}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "instance default secret files rejected",
+			body: `{"config":{"runner":{"instance_defaults":` +
+				`{"secret_files":[{"file":"/etc/shadow","container_path":"/s"}]}}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "matrix pre run command rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"matrix":{"pre_run_command":[["sh","-c","id"]]}}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "matrix datadir object rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"matrix":{"datadir":[{"source_dir":"/etc"}]}}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "matrix datadir source dir rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"matrix":{"datadir.source_dir":["/etc"]}}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "instance devices rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"devices":[{"host_path":"/dev/sda"}]}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "instance datadir source dir rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"datadir":{"source_dir":"/etc"}}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "client datadir source dir rejected",
+			body: `{"config":{"runner":{"client":{"datadirs":{"geth":{"source_dir":"/etc"}}}}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "instance build context rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"build":{"context":"/root"}}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "matrix of other fields accepted",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"matrix":{"resource_limits.cpuset_count":[1,2]}}]}}}`,
+			code: http.StatusAccepted,
+		},
+		{
+			name: "env reference in value rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"environment":{"X":"${GITHUB_TOKEN}"}}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "env reference in key rejected",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth",` +
+				`"environment":{"$HOME":"x"}}]}}}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "plain instances accepted",
+			body: `{"config":{"runner":{"instances":[{"id":"a","client":"geth"}]}}}`,
+			code: http.StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(
+				t, router, http.MethodPost, "/api/v1/runs", adminToken, tt.body,
+			)
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
+
+func TestRuns_QueueFull(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	srv.jobQueue = &fakeQueue{store: srv.store, err: jobqueue.ErrQueueFull}
+	router := srv.buildRouter()
+
+	rec := doRequest(
+		t, router, http.MethodPost, "/api/v1/runs", adminToken,
+		`{"config":{"runner":{}}}`,
+	)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestRuns_StreamLogs(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	srv.cfg.Runs = &config.APIRunsConfig{Enabled: true, WorkDir: t.TempDir()}
+	srv.jobQueue = &fakeQueue{store: srv.store}
+
+	ctx := context.Background()
+	job := &store.Job{ID: "streaming", Status: store.JobStatusRunning, Spec: "{}"}
+	require.NoError(t, srv.store.CreateJob(ctx, job))
+
+	jobDir := jobqueue.JobDir(srv.cfg.Runs.WorkDir, job.ID)
+	require.NoError(t, os.MkdirAll(jobDir, 0o755))
+
+	logFile, err := os.Create(filepath.Join(jobDir, jobqueue.LogFileName))
+	require.NoError(t, err)
+
+	defer func() { _ = logFile.Close() }()
+
+	_, err = logFile.WriteString("first line\n")
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(srv.buildRouter())
+	defer ts.Close()
+
+	req, err := http.NewRequest(
+		http.MethodGet, ts.URL+"/api/v1/runs/"+job.ID+"/logs", nil,
+	)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "benchmarkoor_session", Value: adminToken})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "first line\n", line)
+
+	// Output appended while the run is in progress is streamed.
+	_, err = logFile.WriteString("second line\n")
+	require.NoError(t, err)
+
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "second line\n", line)
+
+	// Final output is drained and the stream ends once the run finishes.
+	_, err = logFile.WriteString("last line\n")
+	require.NoError(t, err)
+
+	job.Status = store.JobStatusCompleted
+	require.NoError(t, srv.store.UpdateJob(ctx, job))
+
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "last line\n", string(rest))
+}
+
+func TestRuns_StreamRunDirLogs(t *testing.T) {
+	srv, adminToken, _ := setupTestServer(t)
+	srv.cfg.Runs = &config.APIRunsConfig{Enabled: true, WorkDir: t.TempDir()}
+	srv.jobQueue = &fakeQueue{store: srv.store}
+	router := srv.buildRouter()
+
+	ctx := context.Background()
+	job := &store.Job{ID: "run-dirs", Status: store.JobStatusCompleted, Spec: "{}"}
+	require.NoError(t, srv.store.CreateJob(ctx, job))
+
+	jobDir := jobqueue.JobDir(srv.cfg.Runs.WorkDir, job.ID)
+	require.NoError(t, os.MkdirAll(jobDir, 0o755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(jobDir, jobqueue.LogFileName), []byte("process\n"), 0o644,
+	))
+
+	runDirs := []string{filepath.Join(t.TempDir(), "geth"), filepath.Join(t.TempDir(), "reth")}
+	for _, dir := range runDirs {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, "container.log"), []byte(filepath.Base(dir)+" container\n"), 0o644,
+		))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, "benchmarkoor.log"), []byte(filepath.Base(dir)+" runner\n"), 0o644,
+		))
+	}
+
+	// The last line is still being written and is ignored.
+	require.NoError(t, os.WriteFile(
+		filepath.Join(jobDir, jobqueue.RunDirsFileName),
+		[]byte(runDirs[0]+"\n"+runDirs[1]+"\n/partial"), 0o644,
+	))
+
+	tests := []struct {
+		query    string
+		wantCode int
+		wantBody string
+	}{
+		{query: "", wantCode: http.StatusOK, wantBody: "process\n"},
+		{query: "?stream=process", wantCode: http.StatusOK, wantBody: "process\n"},
+		{query: "?stream=container", wantCode: http.StatusOK, wantBody: "reth container\n"},
+		{query: "?stream=container&run=0", wantCode: http.StatusOK, wantBody: "geth container\n"},
+		{query: "?stream=benchmarkoor&run=1", wantCode: http.StatusOK, wantBody: "reth runner\n"},
+		// A run that never started streams nothing.
+		{query: "?stream=container&run=2", wantCode: http.StatusOK, wantBody: ""},
+		{query: "?stream=stdout", wantCode: http.StatusBadRequest},
+		{query: "?stream=container&run=-1", wantCode: http.StatusBadRequest},
+		{query: "?run=0", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := doRequest(
+				t, router, http.MethodGet,
+				"/api/v1/runs/"+job.ID+"/logs"+tt.query, adminToken, "",
+			)
+			require.Equal(t, tt.wantCode, rec.Code, rec.Body.String())
+
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRuns_StreamLogsAuth(t *testing.T) {
+	srv, _, readonlyToken := setupTestServer(t)
+	srv.cfg.Runs = &config.APIRunsConfig{Enabled: true, WorkDir: t.TempDir()}
+	srv.jobQueue = &fakeQueue{store: srv.store}
+	router := srv.buildRouter()
+
+	rec := doRequest(t, router, http.MethodGet, "/api/v1/runs/x/logs", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doRequest(
+		t, router, http.MethodGet, "/api/v1/runs/x/logs", readonlyToken, "",
+	)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
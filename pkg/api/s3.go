@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -15,10 +16,15 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// presignCacheEntry holds a cached presigned URL and its expiration time.
+// maxPresignExpiry is the longest validity SigV4 allows for presigned URLs.
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// presignCacheEntry holds a cached presigned URL, the time the cache entry
+// expires and the time the URL itself stops being valid.
 type presignCacheEntry struct {
-	url       string
-	expiresAt time.Time
+	url          string
+	expiresAt    time.Time
+	urlExpiresAt time.Time
 }
 
 // s3Presigner generates presigned GET URLs for objects stored in S3.
@@ -45,7 +51,7 @@ func newS3Presigner(
 	log logrus.FieldLogger,
 	cfg *config.APIS3Config,
 ) (*s3Presigner, error) {
-	expiry, err := time.ParseDuration(cfg.PresignedURLs.Expiry)
+	expiry, err := parsePresignExpiry(cfg.PresignedURLs.Expiry)
 	if err != nil {
 		return nil, fmt.Errorf("parsing presigned_urls.expiry: %w", err)
 	}
@@ -71,6 +77,27 @@ func newS3Presigner(
 	}, nil
 }
 
+// parsePresignExpiry parses a presigned URL expiry duration, rejecting
+// values that S3 would refuse to sign.
+func parsePresignExpiry(value string) (time.Duration, error) {
+	expiry, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if expiry <= 0 {
+		return 0, fmt.Errorf("expiry must be positive, got %s", expiry)
+	}
+
+	if expiry > maxPresignExpiry {
+		return 0, fmt.Errorf(
+			"expiry must not exceed %s, got %s", maxPresignExpiry, expiry,
+		)
+	}
+
+	return expiry, nil
+}
+
 // GeneratePresignedURL returns a presigned GET URL for the given S3 key.
 // Results are cached for half the presigned URL expiry duration to avoid
 // redundant presigning while ensuring URLs always have sufficient validity.
@@ -78,8 +105,86 @@ func (p *s3Presigner) GeneratePresignedURL(
 	ctx context.Context,
 	key string,
 ) (string, error) {
+	entry, err := p.presign(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	return entry.url, nil
+}
+
+// presignedArtifact is a presigned URL for a single run artifact.
+type presignedArtifact struct {
+	Key       string
+	URL       string
+	ExpiresAt time.Time
+}
+
+// PresignArtifact returns a presigned GET URL for a file inside a run
+// directory, i.e. the key "<discoveryPath>/runs/<runID>/<artifact>".
+func (p *s3Presigner) PresignArtifact(
+	ctx context.Context,
+	discoveryPath, runID, artifact string,
+) (*presignedArtifact, error) {
+	key, err := p.artifactKey(discoveryPath, runID, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := p.presign(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &presignedArtifact{
+		Key:       key,
+		URL:       entry.url,
+		ExpiresAt: entry.urlExpiresAt,
+	}, nil
+}
+
+// artifactKey validates the components of a run artifact location and
+// joins them into an S3 key under the given discovery path.
+func (p *s3Presigner) artifactKey(
+	discoveryPath, runID, artifact string,
+) (string, error) {
+	discoveryPath = strings.TrimRight(discoveryPath, "/")
+	if !slices.Contains(p.discoveryPaths, discoveryPath) {
+		return "", fmt.Errorf(
+			"discovery path %q is not configured", discoveryPath,
+		)
+	}
+
+	if runID == "" || runID == "." || strings.Contains(runID, "..") ||
+		strings.ContainsAny(runID, "/\\") {
+		return "", fmt.Errorf("invalid run id %q", runID)
+	}
+
+	if artifact == "" || strings.HasPrefix(artifact, "/") ||
+		strings.Contains(artifact, "..") || path.Clean(artifact) != artifact {
+		return "", fmt.Errorf("invalid artifact path %q", artifact)
+	}
+
+	key := path.Join(discoveryPath, "runs", runID, artifact)
 	if !p.isAllowedPath(key) {
-		return "", fmt.Errorf("path %q is not within any allowed discovery path", key)
+		return "", fmt.Errorf(
+			"path %q is not within any allowed discovery path", key,
+		)
+	}
+
+	return key, nil
+}
+
+// presign returns a presigned GET URL for the given S3 key, served from the
+// cache when a sufficiently fresh entry exists.
+func (p *s3Presigner) presign(
+	ctx context.Context,
+	key string,
+) (presignCacheEntry, error) {
+	if !p.isAllowedPath(key) {
+		return presignCacheEntry{}, fmt.Errorf(
+			"path %q is not within any allowed discovery path", key,
+		)
 	}
 
 	now := time.Now()
@@ -89,7 +194,7 @@ func (p *s3Presigner) GeneratePresignedURL(
 	if entry, ok := p.cache[key]; ok && now.Before(entry.expiresAt) {
 		p.mu.RUnlock()
 
-		return entry.url, nil
+		return entry, nil
 	}
 	p.mu.RUnlock()
 
@@ -98,7 +203,7 @@ func (p *s3Presigner) GeneratePresignedURL(
 	defer p.mu.Unlock()
 
 	if entry, ok := p.cache[key]; ok && now.Before(entry.expiresAt) {
-		return entry.url, nil
+		return entry, nil
 	}
 
 	result, err := p.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
@@ -106,15 +211,19 @@ func (p *s3Presigner) GeneratePresignedURL(
 		Key:    aws.String(key),
 	}, s3.WithPresignExpires(p.expiry))
 	if err != nil {
-		return "", fmt.Errorf("presigning URL for %q: %w", key, err)
+		return presignCacheEntry{}, fmt.Errorf(
+			"presigning URL for %q: %w", key, err,
+		)
 	}
 
-	p.cache[key] = presignCacheEntry{
-		url:       result.URL,
-		expiresAt: now.Add(p.cacheTTL),
+	entry := presignCacheEntry{
+		url:          result.URL,
+		expiresAt:    now.Add(p.cacheTTL),
+		urlExpiresAt: now.Add(p.expiry),
 	}
+	p.cache[key] = entry
 
-	return result.URL, nil
+	return entry, nil
 }
 
 // HeadObject retrieves object metadata from S3 for the given key.
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
 	"github.com/sirupsen/logrus"
@@ -145,3 +146,184 @@ func TestS3Presigner_CachesURLs(t *testing.T) {
 	assert.NotEqual(t, url1, url3,
 		"expected different key to produce different URL")
 }
+
+func TestParsePresignExpiry(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "hours", value: "1h", want: time.Hour},
+		{name: "minutes", value: "15m", want: 15 * time.Minute},
+		{name: "seven days is the maximum", value: "168h", want: 168 * time.Hour},
+		{name: "empty", value: "", wantErr: true},
+		{name: "not a duration", value: "soon", wantErr: true},
+		{name: "zero", value: "0s", wantErr: true},
+		{name: "negative", value: "-1h", wantErr: true},
+		{name: "beyond seven days", value: "169h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePresignExpiry(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestS3Presigner_ArtifactKey(t *testing.T) {
+	log := logrus.New()
+
+	presigner, err := newS3Presigner(log, &config.APIS3Config{
+		Enabled:        true,
+		Bucket:         "test-bucket",
+		Region:         "us-east-1",
+		DiscoveryPaths: []string{"results", "archive/2024/"},
+		PresignedURLs: config.APIS3PresignedURLConfig{
+			Expiry: "1h",
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		discoveryPath string
+		runID         string
+		artifact      string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:          "file in run directory",
+			discoveryPath: "results",
+			runID:         "abc",
+			artifact:      "result.json",
+			want:          "results/runs/abc/result.json",
+		},
+		{
+			name:          "nested artifact under nested discovery path",
+			discoveryPath: "archive/2024",
+			runID:         "abc",
+			artifact:      "traces/block-1.json",
+			want:          "archive/2024/runs/abc/traces/block-1.json",
+		},
+		{
+			name:          "trailing slash on discovery path is ignored",
+			discoveryPath: "results/",
+			runID:         "abc",
+			artifact:      "result.json",
+			want:          "results/runs/abc/result.json",
+		},
+		{
+			name:          "unknown discovery path",
+			discoveryPath: "other",
+			runID:         "abc",
+			artifact:      "result.json",
+			wantErr:       true,
+		},
+		{
+			name:          "discovery path prefix only",
+			discoveryPath: "archive",
+			runID:         "abc",
+			artifact:      "result.json",
+			wantErr:       true,
+		},
+		{
+			name:          "run id traversal",
+			discoveryPath: "results",
+			runID:         "..",
+			artifact:      "result.json",
+			wantErr:       true,
+		},
+		{
+			name:          "run id with slash",
+			discoveryPath: "results",
+			runID:         "abc/def",
+			artifact:      "result.json",
+			wantErr:       true,
+		},
+		{
+			name:          "artifact traversal",
+			discoveryPath: "results",
+			runID:         "abc",
+			artifact:      "../def/result.json",
+			wantErr:       true,
+		},
+		{
+			name:          "absolute artifact path",
+			discoveryPath: "results",
+			runID:         "abc",
+			artifact:      "/etc/passwd",
+			wantErr:       true,
+		},
+		{
+			name:          "unclean artifact path",
+			discoveryPath: "results",
+			runID:         "abc",
+			artifact:      "traces//block.json",
+			wantErr:       true,
+		},
+		{
+			name:          "empty artifact path",
+			discoveryPath: "results",
+			runID:         "abc",
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := presigner.artifactKey(
+				tt.discoveryPath, tt.runID, tt.artifact,
+			)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestS3Presigner_PresignArtifact(t *testing.T) {
+	log := logrus.New()
+
+	presigner, err := newS3Presigner(log, &config.APIS3Config{
+		Enabled:         true,
+		Bucket:          "test-bucket",
+		Region:          "us-east-1",
+		EndpointURL:     "http://localhost:9000",
+		ForcePathStyle:  true,
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		DiscoveryPaths:  []string{"results"},
+		PresignedURLs: config.APIS3PresignedURLConfig{
+			Expiry: "30m",
+		},
+	})
+	require.NoError(t, err)
+
+	before := time.Now()
+
+	result, err := presigner.PresignArtifact(
+		context.Background(), "results", "abc", "trace.json",
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "results/runs/abc/trace.json", result.Key)
+	assert.Contains(t, result.URL, "results/runs/abc/trace.json")
+	assert.Contains(t, result.URL, "X-Amz-Expires=1800")
+	assert.WithinDuration(t, before.Add(30*time.Minute), result.ExpiresAt,
+		5*time.Second)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions_LogoutRevokesSession(t *testing.T) {
+	srv, _, readonlyToken := setupTestServer(t)
+	router := srv.buildRouter()
+
+	rec := doRequest(t, router, http.MethodGet, "/api/v1/auth/me", readonlyToken, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(
+		t, router, http.MethodPost, "/api/v1/auth/logout", readonlyToken, "",
+	)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(t, router, http.MethodGet, "/api/v1/auth/me", readonlyToken, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestSessions_AdminRevokesUserSessions(t *testing.T) {
+	srv, adminToken, readonlyToken := setupTestServer(t)
+	router := srv.buildRouter()
+	ctx := context.Background()
+
+	user, err := srv.store.GetUserByUsername(ctx, "readonly-user")
+	require.NoError(t, err)
+
+	// A second session for the same user, e.g. from another browser.
+	secondToken := "token-readonly-user-2"
+	require.NoError(t, srv.store.CreateSession(ctx, &store.Session{
+		Token:     secondToken,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	}))
+
+	path := fmt.Sprintf("/api/v1/admin/users/%d/sessions", user.ID)
+
+	t.Run("readonly cannot revoke", func(t *testing.T) {
+		rec := doRequest(t, router, http.MethodDelete, path, readonlyToken, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		rec := doRequest(
+			t, router, http.MethodDelete,
+			"/api/v1/admin/users/9999/sessions", adminToken, "",
+		)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("revoked sessions are rejected", func(t *testing.T) {
+		rec := doRequest(t, router, http.MethodDelete, path, adminToken, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		for _, token := range []string{readonlyToken, secondToken} {
+			rec = doRequest(t, router, http.MethodGet, "/api/v1/auth/me", token, "")
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		}
+
+		// Other users' sessions are untouched.
+		rec = doRequest(t, router, http.MethodGet, "/api/v1/auth/me", adminToken, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/executor"
 )
 
 // Compile-time interface checks.
@@ -42,7 +43,8 @@ func (r *localReader) DiscoveryPaths() []string {
 	return keys
 }
 
-// ListRunIDs returns run directory names under {dirPath}/runs/.
+// ListRunIDs returns the run IDs under {dirPath}/runs/, i.e. the paths of
+// the run directories relative to it, including those in date partitions.
 func (r *localReader) ListRunIDs(
 	_ context.Context, discoveryPath string,
 ) ([]string, error) {
@@ -53,9 +55,7 @@ func (r *localReader) ListRunIDs(
 		)
 	}
 
-	runsDir := filepath.Join(dirPath, "runs")
-
-	entries, err := os.ReadDir(runsDir)
+	ids, err := executor.ListRunDirs(filepath.Join(dirPath, "runs"))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -64,13 +64,6 @@ func (r *localReader) ListRunIDs(
 		return nil, fmt.Errorf("reading runs directory: %w", err)
 	}
 
-	ids := make([]string, 0, len(entries))
-	for _, e := range entries {
-		if e.IsDir() {
-			ids = append(ids, e.Name())
-		}
-	}
-
 	return ids, nil
 }
 
@@ -67,6 +67,35 @@ func TestLocalReader_ListRunIDs(t *testing.T) {
 		assert.ElementsMatch(t, []string{"run-aaa", "run-bbb"}, ids)
 	})
 
+	t.Run("descends into date partitions", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		runsDir := filepath.Join(dir, "runs")
+		require.NoError(t, os.MkdirAll(filepath.Join(runsDir, "1718000000_aaa_geth"), 0o755))
+		require.NoError(t, os.MkdirAll(filepath.Join(runsDir, "2024", "06", "10", "1718000001_bbb_geth"), 0o755))
+		require.NoError(t, os.MkdirAll(filepath.Join(runsDir, "2024", "06", "11", "1718090000_ccc_reth"), 0o755))
+
+		reader := setupLocalReader(t, map[string]string{"dp": dir})
+
+		ids, err := reader.ListRunIDs(ctx, "dp")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"1718000000_aaa_geth",
+			"2024/06/10/1718000001_bbb_geth",
+			"2024/06/11/1718090000_ccc_reth",
+		}, ids)
+
+		// Files of partitioned runs are read by their run ID.
+		require.NoError(t, os.WriteFile(
+			filepath.Join(runsDir, "2024", "06", "10", "1718000001_bbb_geth", "config.json"), []byte(`{}`), 0o644,
+		))
+
+		data, err := reader.GetRunFile(ctx, "dp", "2024/06/10/1718000001_bbb_geth", "config.json")
+		require.NoError(t, err)
+		assert.Equal(t, []byte(`{}`), data)
+	})
+
 	t.Run("missing runs directory returns nil", func(t *testing.T) {
 		t.Parallel()
 
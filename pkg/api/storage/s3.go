@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"path"
 	"sort"
 	"strings"
 	"sync"
@@ -16,13 +15,15 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/executor"
 	"github.com/sirupsen/logrus"
 )
 
 // Compile-time interface checks.
 var (
-	_ Reader  = (*s3Reader)(nil)
-	_ Deleter = (*s3Reader)(nil)
+	_ Reader                     = (*s3Reader)(nil)
+	_ Deleter                    = (*s3Reader)(nil)
+	_ executor.IndexObjectReader = (*s3Reader)(nil)
 )
 
 type s3Reader struct {
@@ -58,12 +59,31 @@ func (r *s3Reader) DiscoveryPaths() []string {
 	return r.discoveryPaths
 }
 
-// ListRunIDs lists run IDs (common prefixes) under {dp}/runs/.
+// ListRunIDs lists the run IDs under {dp}/runs/, i.e. the run prefixes
+// relative to it, including those in date partitions.
 func (r *s3Reader) ListRunIDs(
 	ctx context.Context, discoveryPath string,
 ) ([]string, error) {
-	prefix := discoveryPath + "/runs/"
+	runsPrefix := discoveryPath + "/runs/"
 
+	prefixes, err := executor.ListRunPrefixes(ctx, r, runsPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		// "dp/runs/2024/06/10/abc123/" → "2024/06/10/abc123"
+		ids = append(ids, strings.TrimRight(strings.TrimPrefix(p, runsPrefix), "/"))
+	}
+
+	return ids, nil
+}
+
+// ListPrefixes lists the immediate sub-prefixes under prefix.
+func (r *s3Reader) ListPrefixes(
+	ctx context.Context, prefix string,
+) ([]string, error) {
 	paginator := s3.NewListObjectsV2Paginator(
 		r.client, &s3.ListObjectsV2Input{
 			Bucket:    aws.String(r.bucket),
@@ -72,7 +92,7 @@ func (r *s3Reader) ListRunIDs(
 		},
 	)
 
-	var ids []string
+	var prefixes []string
 
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
@@ -84,14 +104,18 @@ func (r *s3Reader) ListRunIDs(
 
 		for _, cp := range page.CommonPrefixes {
 			if cp.Prefix != nil {
-				// Extract run ID: "dp/runs/abc123/" → "abc123"
-				id := path.Base(strings.TrimRight(*cp.Prefix, "/"))
-				ids = append(ids, id)
+				prefixes = append(prefixes, *cp.Prefix)
 			}
 		}
 	}
 
-	return ids, nil
+	return prefixes, nil
+}
+
+// GetObject returns the contents of key, or (nil, nil) when the key does
+// not exist.
+func (r *s3Reader) GetObject(ctx context.Context, key string) ([]byte, error) {
+	return r.getObject(ctx, key)
 }
 
 // GetRunFile reads {dp}/runs/{runID}/{filename} from S3.
@@ -0,0 +1,101 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/storage"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+)
+
+// listBucketResult is the ListObjectsV2 response of the fake S3 server.
+type listBucketResult struct {
+	XMLName        xml.Name       `xml:"ListBucketResult"`
+	Name           string         `xml:"Name"`
+	Prefix         string         `xml:"Prefix"`
+	Delimiter      string         `xml:"Delimiter"`
+	KeyCount       int            `xml:"KeyCount"`
+	IsTruncated    bool           `xml:"IsTruncated"`
+	CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// newFakeS3 serves ListObjectsV2 requests with a delimiter for a bucket
+// holding keys.
+func newFakeS3(t *testing.T, keys []string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := r.URL.Query().Get("prefix")
+		delimiter := r.URL.Query().Get("delimiter")
+
+		seen := make(map[string]struct{}, len(keys))
+
+		for _, key := range keys {
+			rest, ok := strings.CutPrefix(key, prefix)
+			if !ok {
+				continue
+			}
+
+			if i := strings.Index(rest, delimiter); i >= 0 {
+				seen[prefix+rest[:i+1]] = struct{}{}
+			}
+		}
+
+		result := listBucketResult{Name: "bucket", Prefix: prefix, Delimiter: delimiter}
+		for p := range seen {
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: p})
+		}
+
+		sort.Slice(result.CommonPrefixes, func(i, j int) bool {
+			return result.CommonPrefixes[i].Prefix < result.CommonPrefixes[j].Prefix
+		})
+
+		result.KeyCount = len(result.CommonPrefixes)
+
+		w.Header().Set("Content-Type", "application/xml")
+		require.NoError(t, xml.NewEncoder(w).Encode(result))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestS3Reader_ListRunIDs(t *testing.T) {
+	srv := newFakeS3(t, []string{
+		"results/runs/1718000000_aaa_geth/config.json",
+		"results/runs/2024/06/10/1718000001_bbb_geth/config.json",
+		"results/runs/2024/06/11/1718090000_ccc_reth/result.json",
+		"results/suites/abc/summary.json",
+	})
+
+	reader := storage.NewS3Reader(logrus.New(), &config.APIS3Config{
+		Enabled:         true,
+		EndpointURL:     srv.URL,
+		Bucket:          "bucket",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		ForcePathStyle:  true,
+		DiscoveryPaths:  []string{"results"},
+	})
+
+	ids, err := reader.ListRunIDs(context.Background(), "results")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"1718000000_aaa_geth",
+		"2024/06/10/1718000001_bbb_geth",
+		"2024/06/11/1718090000_ccc_reth",
+	}, ids)
+}
@@ -57,3 +57,35 @@ type GitHubUserMapping struct {
 	Username string `gorm:"uniqueIndex;not null" json:"username"`
 	Role     string `gorm:"not null" json:"role"`
 }
+
+// Job status constants.
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// Job represents a remotely triggered benchmark run.
+type Job struct {
+	ID         string     `gorm:"primaryKey" json:"id"`
+	Status     string     `gorm:"not null;index" json:"status"`
+	Spec       string     `gorm:"type:text;not null" json:"-"`
+	CreatedBy  uint       `gorm:"not null" json:"created_by"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// AuditLog records a mutating API request made by an authenticated user.
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Username  string    `gorm:"not null" json:"username"`
+	Action    string    `gorm:"not null;index" json:"action"`
+	Path      string    `gorm:"not null" json:"path"`
+	Params    string    `gorm:"type:text" json:"params"`
+	Status    int       `gorm:"not null" json:"status"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
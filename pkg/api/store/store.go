@@ -35,6 +35,7 @@ type Store interface {
 	UpdateSessionLastActive(ctx context.Context, id uint, t time.Time) error
 	DeleteSession(ctx context.Context, token string) error
 	DeleteSessionByID(ctx context.Context, id uint) error
+	DeleteSessionsByUserID(ctx context.Context, userID uint) error
 	DeleteExpiredSessions(ctx context.Context) error
 
 	// GitHub org mapping CRUD.
@@ -56,6 +57,19 @@ type Store interface {
 	UpdateAPIKeyLastUsed(ctx context.Context, id uint, t time.Time) error
 	DeleteExpiredAPIKeys(ctx context.Context) error
 
+	// Job CRUD.
+	CreateJob(ctx context.Context, job *Job) error
+	GetJob(ctx context.Context, id string) (*Job, error)
+	ListJobs(ctx context.Context) ([]Job, error)
+	ListJobsByStatus(ctx context.Context, status string) ([]Job, error)
+	UpdateJob(ctx context.Context, job *Job) error
+
+	// Audit log.
+	CreateAuditLog(ctx context.Context, entry *AuditLog) error
+	ListAuditLogs(
+		ctx context.Context, limit, offset int,
+	) ([]AuditLog, int64, error)
+
 	// Seeding from config.
 	SeedUsers(ctx context.Context, users []config.BasicAuthUser) error
 	SeedGitHubMappings(
@@ -125,6 +139,8 @@ func (s *store) Start(ctx context.Context) error {
 		&APIKey{},
 		&GitHubOrgMapping{},
 		&GitHubUserMapping{},
+		&Job{},
+		&AuditLog{},
 	); err != nil {
 		return fmt.Errorf("running migrations: %w", err)
 	}
@@ -359,6 +375,18 @@ func (s *store) DeleteSessionByID(ctx context.Context, id uint) error {
 	return nil
 }
 
+func (s *store) DeleteSessionsByUserID(
+	ctx context.Context, userID uint,
+) error {
+	if err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&Session{}).Error; err != nil {
+		return fmt.Errorf("deleting sessions by user id: %w", err)
+	}
+
+	return nil
+}
+
 func (s *store) DeleteExpiredSessions(ctx context.Context) error {
 	result := s.db.WithContext(ctx).
 		Where("expires_at < ?", time.Now().UTC()).
@@ -541,6 +569,94 @@ func (s *store) DeleteGitHubUserMapping(
 	return nil
 }
 
+// --- Job CRUD ---
+
+func (s *store) CreateJob(ctx context.Context, job *Job) error {
+	if err := s.db.WithContext(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("creating job: %w", err)
+	}
+
+	return nil
+}
+
+func (s *store) GetJob(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	if err := s.readDB.WithContext(ctx).
+		Where("id = ?", id).
+		First(&job).Error; err != nil {
+		return nil, fmt.Errorf("getting job: %w", err)
+	}
+
+	return &job, nil
+}
+
+func (s *store) ListJobs(ctx context.Context) ([]Job, error) {
+	var jobs []Job
+	if err := s.readDB.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("listing jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (s *store) ListJobsByStatus(
+	ctx context.Context, status string,
+) ([]Job, error) {
+	var jobs []Job
+	if err := s.readDB.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("listing jobs by status: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (s *store) UpdateJob(ctx context.Context, job *Job) error {
+	if err := s.db.WithContext(ctx).Save(job).Error; err != nil {
+		return fmt.Errorf("updating job: %w", err)
+	}
+
+	return nil
+}
+
+// --- Audit log ---
+
+func (s *store) CreateAuditLog(ctx context.Context, entry *AuditLog) error {
+	if err := s.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("creating audit log entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListAuditLogs returns a page of audit log entries, newest first, along
+// with the total number of entries.
+func (s *store) ListAuditLogs(
+	ctx context.Context, limit, offset int,
+) ([]AuditLog, int64, error) {
+	var total int64
+	if err := s.readDB.WithContext(ctx).
+		Model(&AuditLog{}).
+		Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting audit log entries: %w", err)
+	}
+
+	var entries []AuditLog
+	if err := s.readDB.WithContext(ctx).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("listing audit log entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
 // --- Seeding ---
 
 // SeedUsers upserts config-sourced users. Only users with source="config"
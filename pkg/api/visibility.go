@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/indexstore"
+)
+
+// allowedDiscoveryPaths returns the discovery paths visible to the caller.
+// The boolean is false when the caller is not restricted.
+func (s *server) allowedDiscoveryPaths(r *http.Request) ([]string, bool) {
+	// Unauthenticated callers are treated like readonly users.
+	role := "readonly"
+	if user := userFromContext(r.Context()); user != nil {
+		role = user.Role
+	}
+
+	if role == "admin" {
+		return nil, false
+	}
+
+	paths, ok := s.cfg.Auth.RolePathAllowlist[role]
+	if !ok {
+		return nil, false
+	}
+
+	normalized := make([]string, 0, len(paths))
+	for _, p := range paths {
+		normalized = append(normalized, strings.TrimRight(p, "/"))
+	}
+
+	return normalized, true
+}
+
+// canAccessPath reports whether the caller may read the given storage key,
+// i.e. the key lies under one of the caller's allowed discovery paths.
+func (s *server) canAccessPath(r *http.Request, key string) bool {
+	paths, restricted := s.allowedDiscoveryPaths(r)
+	if !restricted {
+		return true
+	}
+
+	for _, p := range paths {
+		if key == p || strings.HasPrefix(key, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canAccessDiscoveryPath reports whether the caller may see results stored
+// under the given discovery path.
+func (s *server) canAccessDiscoveryPath(
+	r *http.Request, discoveryPath string,
+) bool {
+	paths, restricted := s.allowedDiscoveryPaths(r)
+	if !restricted {
+		return true
+	}
+
+	discoveryPath = strings.TrimRight(discoveryPath, "/")
+
+	for _, p := range paths {
+		if p == discoveryPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// restrictQueryToAllowedPaths narrows an index query to the caller's
+// allowed discovery paths. Filters are ANDed, so user-supplied filters on
+// discovery_path cannot widen the result.
+func (s *server) restrictQueryToAllowedPaths(
+	r *http.Request, params *indexstore.QueryParams,
+) {
+	paths, restricted := s.allowedDiscoveryPaths(r)
+	if !restricted {
+		return
+	}
+
+	params.Filters = append(params.Filters, indexstore.Filter{
+		Column:   "discovery_path",
+		Operator: "in",
+		Value:    strings.Join(paths, ","),
+	})
+}
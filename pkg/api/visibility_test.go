@@ -0,0 +1,295 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/api/indexstore"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRestrictedServer returns a test server with two local discovery
+// paths, "public" and "private", where readonly users may only see
+// "public".
+func setupRestrictedServer(
+	t *testing.T,
+) (srv *server, adminToken, readonlyToken string) {
+	t.Helper()
+
+	srv, adminToken, readonlyToken = setupTestServer(t)
+
+	discoveryPaths := make(map[string]string, 2)
+
+	for _, name := range []string{"public", "private"} {
+		root := t.TempDir()
+		runDir := filepath.Join(root, "runs", "abc")
+		require.NoError(t, os.MkdirAll(runDir, 0o755))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(runDir, "result.json"), []byte(`{"ok":true}`), 0o644,
+		))
+
+		discoveryPaths[name] = root
+	}
+
+	localCfg := &config.APILocalStorageConfig{
+		Enabled:        true,
+		DiscoveryPaths: discoveryPaths,
+	}
+
+	srv.cfg.Storage.Local = localCfg
+	srv.cfg.Auth.RolePathAllowlist = map[string][]string{
+		"readonly": {"public"},
+	}
+	srv.localServer = newLocalFileServer(srv.log, localCfg)
+
+	return srv, adminToken, readonlyToken
+}
+
+func TestVisibility_Files(t *testing.T) {
+	srv, adminToken, readonlyToken := setupRestrictedServer(t)
+	router := srv.buildRouter()
+
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		code  int
+	}{
+		{
+			name:  "readonly can read allowed prefix",
+			token: readonlyToken,
+			path:  "/api/v1/files/public/runs/abc/result.json",
+			code:  http.StatusOK,
+		},
+		{
+			name:  "readonly cannot read restricted prefix",
+			token: readonlyToken,
+			path:  "/api/v1/files/private/runs/abc/result.json",
+			code:  http.StatusForbidden,
+		},
+		{
+			name:  "admin is not restricted",
+			token: adminToken,
+			path:  "/api/v1/files/private/runs/abc/result.json",
+			code:  http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(t, router, http.MethodGet, tt.path, tt.token, "")
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
+
+func TestVisibility_Index(t *testing.T) {
+	srv, adminToken, readonlyToken := setupRestrictedServer(t)
+
+	idx := indexstore.NewStore(srv.log, &config.APIDatabaseConfig{
+		Driver: "sqlite",
+		SQLite: config.SQLiteDatabaseConfig{Path: ":memory:"},
+	})
+	require.NoError(t, idx.Start(context.Background()))
+
+	t.Cleanup(func() { _ = idx.Stop() })
+
+	for _, dp := range []string{"public", "private"} {
+		require.NoError(t, idx.UpsertRun(context.Background(), &indexstore.Run{
+			DiscoveryPath: dp,
+			RunID:         "abc",
+		}))
+	}
+
+	srv.indexStore = idx
+	router := srv.buildRouter()
+
+	// discoveryPathsOf extracts the discovery paths from either an index
+	// response ("entries") or a query response ("data").
+	discoveryPathsOf := func(body []byte) []string {
+		type entry struct {
+			DiscoveryPath string `json:"discovery_path"`
+		}
+
+		var resp struct {
+			Entries []entry `json:"entries"`
+			Data    []entry `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &resp))
+
+		paths := make([]string, 0, len(resp.Entries)+len(resp.Data))
+		for _, e := range append(resp.Entries, resp.Data...) {
+			paths = append(paths, e.DiscoveryPath)
+		}
+
+		return paths
+	}
+
+	t.Run("index listing is filtered for readonly", func(t *testing.T) {
+		rec := doRequest(
+			t, router, http.MethodGet, "/api/v1/index", readonlyToken, "",
+		)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{"public"}, discoveryPathsOf(rec.Body.Bytes()))
+	})
+
+	t.Run("run queries are filtered for readonly", func(t *testing.T) {
+		// An explicit filter on the restricted path must not widen access.
+		rec := doRequest(
+			t, router, http.MethodGet,
+			"/api/v1/index/query/runs?discovery_path=eq.private",
+			readonlyToken, "",
+		)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, discoveryPathsOf(rec.Body.Bytes()))
+	})
+
+	t.Run("admin sees all runs", func(t *testing.T) {
+		rec := doRequest(
+			t, router, http.MethodGet, "/api/v1/index/query/runs",
+			adminToken, "",
+		)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.ElementsMatch(t, []string{"public", "private"},
+			discoveryPathsOf(rec.Body.Bytes()))
+	})
+}
+
+func TestVisibility_TestStats(t *testing.T) {
+	srv, adminToken, readonlyToken := setupRestrictedServer(t)
+
+	idx := indexstore.NewStore(srv.log, &config.APIDatabaseConfig{
+		Driver: "sqlite",
+		SQLite: config.SQLiteDatabaseConfig{Path: ":memory:"},
+	})
+	require.NoError(t, idx.Start(context.Background()))
+
+	t.Cleanup(func() { _ = idx.Stop() })
+
+	ctx := context.Background()
+
+	// Test stats and block logs carry no discovery path, only the run ID.
+	for dp, runID := range map[string]string{"public": "run-public", "private": "run-private"} {
+		require.NoError(t, idx.UpsertRun(ctx, &indexstore.Run{
+			DiscoveryPath: dp,
+			RunID:         runID,
+			SuiteHash:     "suite1",
+		}))
+		require.NoError(t, idx.BulkUpsertTestStats(ctx, []*indexstore.TestStat{{
+			SuiteHash: "suite1",
+			RunID:     runID,
+			TestName:  "test_a",
+			Client:    "geth",
+		}}))
+		require.NoError(t, idx.BulkInsertTestStatsBlockLogs(ctx, []*indexstore.TestStatsBlockLog{{
+			SuiteHash: "suite1",
+			RunID:     runID,
+			TestName:  "test_a",
+			Client:    "geth",
+		}}))
+	}
+
+	srv.indexStore = idx
+	router := srv.buildRouter()
+
+	// runIDsOf extracts the run IDs from a query response.
+	runIDsOf := func(body []byte) []string {
+		var resp struct {
+			Data []struct {
+				RunID string `json:"run_id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &resp))
+
+		ids := make([]string, 0, len(resp.Data))
+		for _, d := range resp.Data {
+			ids = append(ids, d.RunID)
+		}
+
+		return ids
+	}
+
+	// suiteStatsRunIDsOf extracts the run IDs from a suite stats response.
+	suiteStatsRunIDsOf := func(body []byte) []string {
+		var stats map[string]struct {
+			Durations []struct {
+				ID string `json:"id"`
+			} `json:"durations"`
+		}
+		require.NoError(t, json.Unmarshal(body, &stats))
+
+		ids := make([]string, 0, 2)
+		for _, td := range stats {
+			for _, d := range td.Durations {
+				ids = append(ids, d.ID)
+			}
+		}
+
+		return ids
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		path    string
+		runIDs  func([]byte) []string
+		wantIDs []string
+	}{
+		{
+			name:    "readonly test stats are filtered",
+			token:   readonlyToken,
+			path:    "/api/v1/index/query/test_stats",
+			runIDs:  runIDsOf,
+			wantIDs: []string{"run-public"},
+		},
+		{
+			name:    "readonly filter on a restricted run does not widen access",
+			token:   readonlyToken,
+			path:    "/api/v1/index/query/test_stats?run_id=eq.run-private",
+			runIDs:  runIDsOf,
+			wantIDs: []string{},
+		},
+		{
+			name:    "readonly block logs are filtered",
+			token:   readonlyToken,
+			path:    "/api/v1/index/query/test_stats_block_logs",
+			runIDs:  runIDsOf,
+			wantIDs: []string{"run-public"},
+		},
+		{
+			name:    "readonly suite stats are filtered",
+			token:   readonlyToken,
+			path:    "/api/v1/index/suites/suite1/stats",
+			runIDs:  suiteStatsRunIDsOf,
+			wantIDs: []string{"run-public"},
+		},
+		{
+			name:    "admin sees all test stats",
+			token:   adminToken,
+			path:    "/api/v1/index/query/test_stats",
+			runIDs:  runIDsOf,
+			wantIDs: []string{"run-public", "run-private"},
+		},
+		{
+			name:    "admin sees all suite stats",
+			token:   adminToken,
+			path:    "/api/v1/index/suites/suite1/stats",
+			runIDs:  suiteStatsRunIDsOf,
+			wantIDs: []string{"run-public", "run-private"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(t, router, http.MethodGet, tt.path, tt.token, "")
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.ElementsMatch(t, tt.wantIDs, tt.runIDs(rec.Body.Bytes()))
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package client
 
+import "strconv"
+
 type besuSpec struct{}
 
 // NewBesuSpec creates a new Besu client specification.
@@ -23,11 +25,6 @@ func (s *besuSpec) DefaultCommand() []string {
 		// Data directory - should always point to /data
 		"--data-path=/data",
 		"--data-storage-format=BONSAI",
-		// Peering / Syncing / TXPool
-		"--p2p-enabled=false",
-		"--sync-mode=FULL",
-		"--max-peers=0",
-		"--discovery-enabled=false",
 		// "Public" JSON RPC API
 		"--rpc-http-enabled=true",
 		"--rpc-http-host=0.0.0.0",
@@ -51,6 +48,22 @@ func (s *besuSpec) DefaultCommand() []string {
 	}
 }
 
+func (s *besuSpec) BenchmarkDefaultArgs() []string {
+	return []string{
+		// Execute every block instead of syncing state.
+		"--sync-mode=FULL",
+	}
+}
+
+func (s *besuSpec) IsolationArgs() []string {
+	return []string{
+		// No peers or discovery.
+		"--p2p-enabled=false",
+		"--max-peers=0",
+		"--discovery-enabled=false",
+	}
+}
+
 func (s *besuSpec) GenesisFlag() string {
 	return "--genesis-file="
 }
@@ -103,3 +116,12 @@ func (s *besuSpec) RPCRollbackSpec() *RPCRollbackSpec {
 func (s *besuSpec) DefaultConfigFiles() map[string]string {
 	return nil
 }
+
+func (s *besuSpec) ManagedFlags() []ManagedFlag {
+	return []ManagedFlag{
+		{Name: "--data-path", Value: s.DataDir()},
+		{Name: "--engine-jwt-secret", Value: s.JWTPath()},
+		{Name: "--engine-rpc-port", Value: strconv.Itoa(s.EnginePort())},
+		{Name: "--rpc-http-port", Value: strconv.Itoa(s.RPCPort())},
+	}
+}
@@ -2,6 +2,8 @@ package client
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -37,6 +39,12 @@ type RPCRollbackSpec struct {
 	RPCMethod string // e.g. "debug_setHead", "debug_resetHead"
 }
 
+// ManagedFlag is a command-line flag whose value benchmarkoor depends on.
+type ManagedFlag struct {
+	Name  string // e.g. "--datadir"
+	Value string // e.g. "/data"
+}
+
 // Spec provides client-specific container configuration.
 type Spec interface {
 	// Type returns the client type.
@@ -48,6 +56,16 @@ type Spec interface {
 	// DefaultCommand returns the default command arguments.
 	DefaultCommand() []string
 
+	// BenchmarkDefaultArgs returns baseline flags for reproducible
+	// benchmarking. The runner appends them to both the default and a custom
+	// command, before extra_args, so extra_args can override them.
+	BenchmarkDefaultArgs() []string
+
+	// IsolationArgs returns the flags that disable peer discovery and P2P.
+	// They are appended like BenchmarkDefaultArgs unless isolate_network is
+	// false. Returns nil if the client does not support network isolation.
+	IsolationArgs() []string
+
 	// GenesisFlag returns the genesis flag format (e.g., "--genesis-file=").
 	// Returns empty string if client doesn't use a genesis flag (e.g., Erigon uses init container).
 	GenesisFlag() string
@@ -87,6 +105,11 @@ type Spec interface {
 	// Keys are target paths inside the container, values are file contents.
 	// Returns nil if no config files are needed.
 	DefaultConfigFiles() map[string]string
+
+	// ManagedFlags returns the flags the runner relies on (data directory,
+	// JWT secret, RPC and Engine API ports) with the values it expects.
+	// The genesis flag is reported separately by GenesisFlag.
+	ManagedFlags() []ManagedFlag
 }
 
 // Registry manages client specifications.
@@ -154,3 +177,108 @@ func (r *registry) List() []ClientType {
 
 	return types
 }
+
+// CheckArgConflicts returns an error if extraArgs change the value of a
+// managed flag, or if a custom command sets the genesis flag that the
+// runner appends when genesisInjected is true. dataDir is the data
+// directory mount path inside the container; managed flags pointing at
+// spec.DataDir() are expected to point at dataDir instead.
+func CheckArgConflicts(
+	spec Spec, command, extraArgs []string, genesisInjected bool, dataDir string,
+) error {
+	expected := make([]ManagedFlag, 0, len(spec.ManagedFlags())+1)
+
+	for _, flag := range spec.ManagedFlags() {
+		if flag.Value == spec.DataDir() && dataDir != "" {
+			flag.Value = dataDir
+		}
+
+		expected = append(expected, flag)
+	}
+
+	genesisFlag := strings.TrimSuffix(spec.GenesisFlag(), "=")
+	if genesisInjected && genesisFlag != "" {
+		expected = append(expected, ManagedFlag{Name: genesisFlag, Value: spec.GenesisPath()})
+
+		// A custom command would end up with the genesis flag twice.
+		for i := range command {
+			if _, ok := flagValue(command, i, genesisFlag); ok {
+				return fmt.Errorf(
+					"command arg %q conflicts with %s, which benchmarkoor appends for %s",
+					command[i], genesisFlag, spec.Type(),
+				)
+			}
+		}
+	}
+
+	for i := range extraArgs {
+		for _, flag := range expected {
+			value, ok := flagValue(extraArgs, i, flag.Name)
+			if ok && value != flag.Value {
+				return fmt.Errorf(
+					"extra_args %q conflicts with %s=%s, which benchmarkoor manages for %s",
+					extraArgs[i], flag.Name, flag.Value, spec.Type(),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// flagValue reports whether args[i] sets flag, either as "--flag=value" or
+// as "--flag value", and returns the value.
+func flagValue(args []string, i int, flag string) (string, bool) {
+	arg := args[i]
+
+	if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+		return value, true
+	}
+
+	if arg != flag {
+		return "", false
+	}
+
+	if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+		return args[i+1], true
+	}
+
+	return "", true
+}
+
+// CheckRemovedDefaultArgs returns an error if a remove_default_args prefix
+// would remove a managed flag from the default command.
+func CheckRemovedDefaultArgs(spec Spec, removeArgs []string) error {
+	for _, prefix := range removeArgs {
+		for _, flag := range spec.ManagedFlags() {
+			if strings.HasPrefix(flag.Name+"="+flag.Value, prefix) {
+				return fmt.Errorf(
+					"remove_default_args %q would remove %s=%s, which benchmarkoor manages for %s",
+					prefix, flag.Name, flag.Value, spec.Type(),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RemoveArgs returns args without the entries starting with any of the
+// given prefixes.
+func RemoveArgs(args, prefixes []string) []string {
+	if len(prefixes) == 0 {
+		return args
+	}
+
+	kept := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if !slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(arg, prefix)
+		}) {
+			kept = append(kept, arg)
+		}
+	}
+
+	return kept
+}
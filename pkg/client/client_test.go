@@ -0,0 +1,238 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckArgConflicts(t *testing.T) {
+	geth := NewGethSpec()
+	reth := NewRethSpec()
+
+	tests := []struct {
+		name            string
+		spec            Spec
+		command         []string
+		extraArgs       []string
+		genesisInjected bool
+		dataDir         string
+		errSubstr       string
+	}{
+		{
+			name:      "geth unrelated extra args",
+			spec:      geth,
+			extraArgs: []string{"--cache=4096", "--syncmode=snap"},
+		},
+		{
+			name:      "geth datadir override",
+			spec:      geth,
+			extraArgs: []string{"--datadir=/other"},
+			errSubstr: `extra_args "--datadir=/other" conflicts with --datadir=/data`,
+		},
+		{
+			name:      "geth datadir as separate arg",
+			spec:      geth,
+			extraArgs: []string{"--datadir", "/other"},
+			errSubstr: "conflicts with --datadir=/data",
+		},
+		{
+			name:      "geth datadir matching custom container dir",
+			spec:      geth,
+			extraArgs: []string{"--datadir=/snapshot"},
+			dataDir:   "/snapshot",
+		},
+		{
+			name:      "geth same value is not a conflict",
+			spec:      geth,
+			extraArgs: []string{"--authrpc.port=8551"},
+		},
+		{
+			name:      "geth engine port override",
+			spec:      geth,
+			extraArgs: []string{"--authrpc.port=9551"},
+			errSubstr: "conflicts with --authrpc.port=8551",
+		},
+		{
+			name:            "geth genesis override",
+			spec:            geth,
+			extraArgs:       []string{"--override.genesis=/custom.json"},
+			genesisInjected: true,
+			errSubstr:       "conflicts with --override.genesis=/tmp/genesis.json",
+		},
+		{
+			name:            "geth genesis flag in custom command",
+			spec:            geth,
+			command:         []string{"--datadir=/data", "--override.genesis=/tmp/genesis.json"},
+			genesisInjected: true,
+			errSubstr:       "which benchmarkoor appends for geth",
+		},
+		{
+			name:      "reth chain allowed without injected genesis",
+			spec:      reth,
+			extraArgs: []string{"--chain=mainnet"},
+		},
+		{
+			name:            "reth chain conflicts with injected genesis",
+			spec:            reth,
+			extraArgs:       []string{"--chain=mainnet"},
+			genesisInjected: true,
+			errSubstr:       "conflicts with --chain=/tmp/genesis.json",
+		},
+		{
+			name:      "reth datadir override",
+			spec:      reth,
+			extraArgs: []string{"--datadir=/data"},
+			errSubstr: "conflicts with --datadir=/var/lib/reth",
+		},
+		{
+			name:      "reth jwt override",
+			spec:      reth,
+			extraArgs: []string{"--authrpc.jwtsecret=/jwt.hex"},
+			errSubstr: "conflicts with --authrpc.jwtsecret=/tmp/jwtsecret",
+		},
+		{
+			name:    "reth custom command with managed flags",
+			spec:    reth,
+			command: []string{"node", "--datadir=/var/lib/reth", "--http.port=8545"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckArgConflicts(tt.spec, tt.command, tt.extraArgs, tt.genesisInjected, tt.dataDir)
+			if tt.errSubstr == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstr)
+		})
+	}
+}
+
+func TestManagedFlags_MatchDefaultCommand(t *testing.T) {
+	registry := NewRegistry()
+
+	for _, clientType := range registry.List() {
+		spec, err := registry.Get(clientType)
+		require.NoError(t, err)
+
+		t.Run(string(clientType), func(t *testing.T) {
+			// The default command must not conflict with its own managed flags.
+			require.NoError(t, CheckArgConflicts(spec, nil, spec.DefaultCommand(), true, ""))
+
+			for _, flag := range spec.ManagedFlags() {
+				assert.Contains(t, spec.DefaultCommand(), flag.Name+"="+flag.Value)
+			}
+		})
+	}
+}
+
+func TestCheckRemovedDefaultArgs(t *testing.T) {
+	geth := NewGethSpec()
+
+	require.NoError(t, CheckRemovedDefaultArgs(geth, nil))
+	require.NoError(t, CheckRemovedDefaultArgs(geth, []string{"--syncmode", "--metrics"}))
+
+	err := CheckRemovedDefaultArgs(geth, []string{"--snapshot", "--datadir"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `remove_default_args "--datadir" would remove --datadir=/data`)
+
+	// A prefix covering a managed flag is rejected too.
+	err = CheckRemovedDefaultArgs(geth, []string{"--authrpc"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "which benchmarkoor manages for geth")
+}
+
+func TestRemoveArgs(t *testing.T) {
+	args := []string{"--syncmode=full", "--http", "--http.addr=0.0.0.0", "--metrics"}
+
+	assert.Equal(t, args, RemoveArgs(args, nil))
+	assert.Equal(t, []string{"--http", "--http.addr=0.0.0.0", "--metrics"},
+		RemoveArgs(args, []string{"--syncmode"}))
+	assert.Equal(t, []string{"--syncmode=full", "--metrics"},
+		RemoveArgs(args, []string{"--http"}))
+	assert.Equal(t, []string{"--syncmode=full", "--http", "--metrics"},
+		RemoveArgs(args, []string{"--http.", "--unknown"}))
+}
+
+func TestIsolationArgs(t *testing.T) {
+	tests := []struct {
+		spec Spec
+		want []string
+	}{
+		{
+			spec: NewGethSpec(),
+			want: []string{"--port=0", "--maxpeers=0", "--nodiscover", "--bootnodes=", "--nat=none"},
+		},
+		{
+			spec: NewErigonSpec(),
+			want: []string{"--nat=none", "--maxpeers=0", "--nodiscover"},
+		},
+		{
+			spec: NewBesuSpec(),
+			want: []string{"--p2p-enabled=false", "--max-peers=0", "--discovery-enabled=false"},
+		},
+		{
+			spec: NewNethermindSpec(),
+			want: []string{
+				"--Network.DiscoveryPort=0",
+				"--Network.MaxActivePeers=0",
+				"--Init.DiscoveryEnabled=false",
+				"--Network.ExternalIp=127.0.0.1",
+			},
+		},
+		{
+			spec: NewNimbusSpec(),
+			want: []string{"--max-peers=0"},
+		},
+		{
+			spec: NewRethSpec(),
+			want: []string{"--disable-discovery", "--max-outbound-peers=0", "--max-inbound-peers=0", "--nat=none"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.spec.Type()), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.spec.IsolationArgs())
+
+			// Isolation args must not be duplicated in the default command.
+			for _, arg := range tt.spec.IsolationArgs() {
+				assert.NotContains(t, tt.spec.DefaultCommand(), arg)
+			}
+		})
+	}
+}
+
+func TestBenchmarkDefaultArgs(t *testing.T) {
+	tests := []struct {
+		spec Spec
+		want []string
+	}{
+		{spec: NewGethSpec(), want: []string{"--syncmode=full", "--snapshot=false"}},
+		{spec: NewBesuSpec(), want: []string{"--sync-mode=FULL"}},
+		{spec: NewRethSpec(), want: []string{"--engine.disable-precompile-cache"}},
+		{spec: NewNimbusSpec(), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.spec.Type()), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.spec.BenchmarkDefaultArgs())
+		})
+	}
+
+	// Benchmark default args must not be duplicated in the default command
+	// or overlap the isolation args.
+	for _, spec := range []Spec{
+		NewGethSpec(), NewErigonSpec(), NewBesuSpec(), NewNethermindSpec(), NewNimbusSpec(), NewRethSpec(),
+	} {
+		for _, arg := range spec.BenchmarkDefaultArgs() {
+			assert.NotContains(t, spec.DefaultCommand(), arg, spec.Type())
+			assert.NotContains(t, spec.IsolationArgs(), arg, spec.Type())
+		}
+	}
+}
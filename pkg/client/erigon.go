@@ -1,5 +1,7 @@
 package client
 
+import "strconv"
+
 type erigonSpec struct{}
 
 // NewErigonSpec creates a new Erigon client specification.
@@ -22,14 +24,6 @@ func (s *erigonSpec) DefaultCommand() []string {
 	return []string{
 		// Data directory - should always point to /data
 		"--datadir=/data",
-		// Peering / Syncing / TXPool
-		"--nat=none",
-		"--maxpeers=0",
-		"--txpool.disable",
-		"--nodiscover",
-		"--no-downloader",
-		"--torrent.download.rate=0",
-		"--torrent.upload.rate=0",
 		// "Public" JSON RPC API
 		"--http",
 		"--http.addr=0.0.0.0",
@@ -51,13 +45,33 @@ func (s *erigonSpec) DefaultCommand() []string {
 		"--log.dir.disable",               // We just need logs on the console
 		"--private.api.addr=0.0.0.0:9090", // Erigon specific API
 		"--externalcl",                    // Disables built in Caplin CL client.
-		"--fcu.timeout=0",                 // Setting to 0 disables async FCU treatment (Default is 1s and then goes async)
-		"--fcu.background.prune=false",    // Disables background pruning post FCU
+	}
+}
+
+func (s *erigonSpec) BenchmarkDefaultArgs() []string {
+	return []string{
+		// Syncing / TXPool
+		"--txpool.disable",
+		"--no-downloader",
+		"--torrent.download.rate=0",
+		"--torrent.upload.rate=0",
+		// Block processing
+		"--fcu.timeout=0",              // Setting to 0 disables async FCU treatment (Default is 1s and then goes async)
+		"--fcu.background.prune=false", // Disables background pruning post FCU
 		//"--fcu.background.commit=false",   // Needs erigon > v3.3.7
 		"--sync.parallel-state-flushing=false", // Disable parallel state flushing
 	}
 }
 
+func (s *erigonSpec) IsolationArgs() []string {
+	return []string{
+		// No peers, discovery or NAT traversal.
+		"--nat=none",
+		"--maxpeers=0",
+		"--nodiscover",
+	}
+}
+
 func (s *erigonSpec) GenesisFlag() string {
 	return "" // Erigon uses init container for genesis, not a command flag.
 }
@@ -109,3 +123,12 @@ func (s *erigonSpec) RPCRollbackSpec() *RPCRollbackSpec {
 func (s *erigonSpec) DefaultConfigFiles() map[string]string {
 	return nil
 }
+
+func (s *erigonSpec) ManagedFlags() []ManagedFlag {
+	return []ManagedFlag{
+		{Name: "--datadir", Value: s.DataDir()},
+		{Name: "--authrpc.jwtsecret", Value: s.JWTPath()},
+		{Name: "--authrpc.port", Value: strconv.Itoa(s.EnginePort())},
+		{Name: "--http.port", Value: strconv.Itoa(s.RPCPort())},
+	}
+}
@@ -1,5 +1,7 @@
 package client
 
+import "strconv"
+
 type gethSpec struct{}
 
 // NewGethSpec creates a new Geth client specification.
@@ -24,15 +26,7 @@ func (s *gethSpec) DefaultCommand() []string {
 		"--config=/tmp/config.toml",
 		// Data directory - should always point to /data
 		"--datadir=/data",
-		// Peering / Syncing
-		"--port=0",
-		"--syncmode=full",
-		"--maxpeers=0",
-		"--nodiscover",
-		"--bootnodes=",
 		//"--gcmode=archive",
-		"--snapshot=false",
-		"--nat=none",
 		// "Public" JSON RPC API
 		"--http",
 		"--http.addr=0.0.0.0",
@@ -51,6 +45,26 @@ func (s *gethSpec) DefaultCommand() []string {
 	}
 }
 
+func (s *gethSpec) BenchmarkDefaultArgs() []string {
+	return []string{
+		// Full sync without the snapshot, so blocks are executed against
+		// the trie.
+		"--syncmode=full",
+		"--snapshot=false",
+	}
+}
+
+func (s *gethSpec) IsolationArgs() []string {
+	return []string{
+		// No peers, discovery or NAT traversal.
+		"--port=0",
+		"--maxpeers=0",
+		"--nodiscover",
+		"--bootnodes=",
+		"--nat=none",
+	}
+}
+
 func (s *gethSpec) GenesisFlag() string {
 	return "--override.genesis="
 }
@@ -108,3 +122,12 @@ IdleTimeout = 120000000000 # 120s
 `,
 	}
 }
+
+func (s *gethSpec) ManagedFlags() []ManagedFlag {
+	return []ManagedFlag{
+		{Name: "--datadir", Value: s.DataDir()},
+		{Name: "--authrpc.jwtsecret", Value: s.JWTPath()},
+		{Name: "--authrpc.port", Value: strconv.Itoa(s.EnginePort())},
+		{Name: "--http.port", Value: strconv.Itoa(s.RPCPort())},
+	}
+}
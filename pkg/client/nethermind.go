@@ -1,5 +1,7 @@
 package client
 
+import "strconv"
+
 type nethermindSpec struct{}
 
 // NewNethermindSpec creates a new Nethermind client specification.
@@ -22,12 +24,6 @@ func (s *nethermindSpec) DefaultCommand() []string {
 	return []string{
 		// Data directory - should always point to /data
 		"--datadir=/data",
-		// Peering / Syncing
-		"--Network.DiscoveryPort=0",
-		"--Network.MaxActivePeers=0",
-		"--Init.DiscoveryEnabled=false",
-		"--Sync.MaxAttemptsToUpdatePivot=0",
-		"--Network.ExternalIp=127.0.0.1",
 		// "Public" JSON RPC API
 		"--JsonRpc.Enabled=true",
 		"--JsonRpc.Host=0.0.0.0",
@@ -46,10 +42,28 @@ func (s *nethermindSpec) DefaultCommand() []string {
 		"--Init.AutoDump=None",
 		"--Merge.NewPayloadBlockProcessingTimeout=70000",
 		"--Merge.TerminalTotalDifficulty=0",
+	}
+}
+
+func (s *nethermindSpec) BenchmarkDefaultArgs() []string {
+	return []string{
+		// Syncing
+		"--Sync.MaxAttemptsToUpdatePivot=0",
+		// Block processing
 		"--Blocks.CachePrecompilesOnBlockProcessing=false",
 	}
 }
 
+func (s *nethermindSpec) IsolationArgs() []string {
+	return []string{
+		// No peers or discovery.
+		"--Network.DiscoveryPort=0",
+		"--Network.MaxActivePeers=0",
+		"--Init.DiscoveryEnabled=false",
+		"--Network.ExternalIp=127.0.0.1",
+	}
+}
+
 func (s *nethermindSpec) GenesisFlag() string {
 	return "--Init.ChainSpecPath="
 }
@@ -100,3 +114,12 @@ func (s *nethermindSpec) RPCRollbackSpec() *RPCRollbackSpec {
 func (s *nethermindSpec) DefaultConfigFiles() map[string]string {
 	return nil
 }
+
+func (s *nethermindSpec) ManagedFlags() []ManagedFlag {
+	return []ManagedFlag{
+		{Name: "--datadir", Value: s.DataDir()},
+		{Name: "--JsonRpc.JwtSecretFile", Value: s.JWTPath()},
+		{Name: "--JsonRpc.EnginePort", Value: strconv.Itoa(s.EnginePort())},
+		{Name: "--JsonRpc.Port", Value: strconv.Itoa(s.RPCPort())},
+	}
+}
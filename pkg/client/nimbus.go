@@ -1,5 +1,7 @@
 package client
 
+import "strconv"
+
 type nimbusSpec struct{}
 
 // NewNimbusSpec creates a new Nimbus client specification.
@@ -22,8 +24,6 @@ func (s *nimbusSpec) DefaultCommand() []string {
 	return []string{
 		// Data directory - should always point to /data
 		"--data-dir=/data",
-		// Peering
-		"--max-peers=0",
 		// "Public" JSON RPC API
 		"--rpc=true",
 		"--http-address=0.0.0.0",
@@ -41,6 +41,18 @@ func (s *nimbusSpec) DefaultCommand() []string {
 	}
 }
 
+func (s *nimbusSpec) BenchmarkDefaultArgs() []string {
+	// Nimbus needs no flags beyond its default command and isolation args.
+	return nil
+}
+
+func (s *nimbusSpec) IsolationArgs() []string {
+	return []string{
+		// No peers.
+		"--max-peers=0",
+	}
+}
+
 func (s *nimbusSpec) GenesisFlag() string {
 	return "--custom-network="
 }
@@ -88,3 +100,12 @@ func (s *nimbusSpec) RPCRollbackSpec() *RPCRollbackSpec {
 func (s *nimbusSpec) DefaultConfigFiles() map[string]string {
 	return nil
 }
+
+func (s *nimbusSpec) ManagedFlags() []ManagedFlag {
+	return []ManagedFlag{
+		{Name: "--data-dir", Value: s.DataDir()},
+		{Name: "--jwt-secret", Value: s.JWTPath()},
+		{Name: "--engine-api-port", Value: strconv.Itoa(s.EnginePort())},
+		{Name: "--http-port", Value: strconv.Itoa(s.RPCPort())},
+	}
+}
@@ -1,5 +1,7 @@
 package client
 
+import "strconv"
+
 type rethSpec struct{}
 
 // NewRethSpec creates a new Reth client specification.
@@ -23,11 +25,6 @@ func (s *rethSpec) DefaultCommand() []string {
 		"node",
 		// Data directory - should always point to /data
 		"--datadir=/var/lib/reth",
-		// Peering / Syncing
-		//"--disable-discovery",
-		//"--netrestrict=127.0.0.1/32",
-		//"--max-peers=0",
-		//"--trusted-only",
 		// "Public" JSON RPC API
 		"--http",
 		"--http.addr=0.0.0.0",
@@ -37,12 +34,28 @@ func (s *rethSpec) DefaultCommand() []string {
 		"--authrpc.jwtsecret=/tmp/jwtsecret",
 		"--authrpc.addr=0.0.0.0",
 		"--authrpc.port=8551",
-		"--engine.disable-precompile-cache",
 		// Others
 		"--full",
 	}
 }
 
+func (s *rethSpec) BenchmarkDefaultArgs() []string {
+	return []string{
+		// Block processing
+		"--engine.disable-precompile-cache",
+	}
+}
+
+func (s *rethSpec) IsolationArgs() []string {
+	return []string{
+		// No peers, discovery or NAT traversal.
+		"--disable-discovery",
+		"--max-outbound-peers=0",
+		"--max-inbound-peers=0",
+		"--nat=none",
+	}
+}
+
 func (s *rethSpec) GenesisFlag() string {
 	return "--chain="
 }
@@ -90,3 +103,12 @@ func (s *rethSpec) RPCRollbackSpec() *RPCRollbackSpec {
 func (s *rethSpec) DefaultConfigFiles() map[string]string {
 	return nil
 }
+
+func (s *rethSpec) ManagedFlags() []ManagedFlag {
+	return []ManagedFlag{
+		{Name: "--datadir", Value: s.DataDir()},
+		{Name: "--authrpc.jwtsecret", Value: s.JWTPath()},
+		{Name: "--authrpc.port", Value: strconv.Itoa(s.EnginePort())},
+		{Name: "--http.port", Value: strconv.Itoa(s.RPCPort())},
+	}
+}
@@ -7,6 +7,18 @@ type APIConfig struct {
 	Database APIDatabaseConfig  `yaml:"database" mapstructure:"database"`
 	Storage  APIStorageConfig   `yaml:"storage,omitempty" mapstructure:"storage"`
 	Indexing *APIIndexingConfig `yaml:"indexing,omitempty" mapstructure:"indexing"`
+	Runs     *APIRunsConfig     `yaml:"runs,omitempty" mapstructure:"runs"`
+}
+
+// APIRunsConfig enables remotely triggered benchmark runs. Each submitted
+// run spec is layered on top of the base config files and executed by
+// spawning `benchmarkoor run` as a child process.
+type APIRunsConfig struct {
+	Enabled     bool     `yaml:"enabled" mapstructure:"enabled"`
+	BaseConfigs []string `yaml:"base_configs,omitempty" mapstructure:"base_configs"`
+	WorkDir     string   `yaml:"work_dir,omitempty" mapstructure:"work_dir"`
+	Concurrency int      `yaml:"concurrency,omitempty" mapstructure:"concurrency"`
+	QueueSize   int      `yaml:"queue_size,omitempty" mapstructure:"queue_size"`
 }
 
 // APIIndexingConfig configures the background indexing service that
@@ -55,9 +67,42 @@ type APIS3PresignedURLConfig struct {
 
 // APIServerConfig contains HTTP server settings.
 type APIServerConfig struct {
-	Listen      string          `yaml:"listen" mapstructure:"listen"`
-	CORSOrigins []string        `yaml:"cors_origins,omitempty" mapstructure:"cors_origins"`
-	RateLimit   RateLimitConfig `yaml:"rate_limit,omitempty" mapstructure:"rate_limit"`
+	Listen string `yaml:"listen" mapstructure:"listen"`
+	// CORSOrigins is deprecated in favor of CORS.AllowedOrigins and is only
+	// used when the latter is empty.
+	CORSOrigins []string             `yaml:"cors_origins,omitempty" mapstructure:"cors_origins"`
+	CORS        APICORSConfig        `yaml:"cors,omitempty" mapstructure:"cors"`
+	Compression APICompressionConfig `yaml:"compression,omitempty" mapstructure:"compression"`
+	Metrics     APIMetricsConfig     `yaml:"metrics,omitempty" mapstructure:"metrics"`
+	RateLimit   RateLimitConfig      `yaml:"rate_limit,omitempty" mapstructure:"rate_limit"`
+}
+
+// APIMetricsConfig exposes Prometheus metrics about the API server's own
+// HTTP traffic at /metrics. The endpoint is unauthenticated.
+type APIMetricsConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+}
+
+// APICompressionConfig configures gzip compression of API responses and
+// decompression of gzip-encoded request bodies. Responses are only
+// compressed when the client sends a matching Accept-Encoding, the body
+// reaches MinSize bytes and its media type is listed in ContentTypes.
+type APICompressionConfig struct {
+	Enabled      *bool    `yaml:"enabled,omitempty" mapstructure:"enabled"`
+	Level        int      `yaml:"level,omitempty" mapstructure:"level"`
+	MinSize      int      `yaml:"min_size,omitempty" mapstructure:"min_size"`
+	ContentTypes []string `yaml:"content_types,omitempty" mapstructure:"content_types"`
+}
+
+// APICORSConfig configures cross-origin resource sharing. With no allowed
+// origins, CORS headers are never sent and only same-origin browser
+// requests succeed.
+type APICORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins,omitempty" mapstructure:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods,omitempty" mapstructure:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers,omitempty" mapstructure:"allowed_headers"`
+	AllowCredentials *bool    `yaml:"allow_credentials,omitempty" mapstructure:"allow_credentials"`
+	MaxAge           int      `yaml:"max_age,omitempty" mapstructure:"max_age"`
 }
 
 // RateLimitConfig configures per-IP rate limiting.
@@ -79,6 +124,9 @@ type APIAuthConfig struct {
 	AnonymousRead bool             `yaml:"anonymous_read" mapstructure:"anonymous_read"`
 	Basic         BasicAuthConfig  `yaml:"basic,omitempty" mapstructure:"basic"`
 	GitHub        GitHubAuthConfig `yaml:"github,omitempty" mapstructure:"github"`
+	// RolePathAllowlist restricts the storage discovery paths a non-admin
+	// role may see. Roles without an entry can see every discovery path.
+	RolePathAllowlist map[string][]string `yaml:"role_path_allowlist,omitempty" mapstructure:"role_path_allowlist"`
 }
 
 // BasicAuthConfig configures username/password authentication.
@@ -102,6 +150,8 @@ type GitHubAuthConfig struct {
 	RedirectURL     string            `yaml:"redirect_url,omitempty" mapstructure:"redirect_url"`
 	OrgRoleMapping  map[string]string `yaml:"org_role_mapping,omitempty" mapstructure:"org_role_mapping"`
 	UserRoleMapping map[string]string `yaml:"user_role_mapping,omitempty" mapstructure:"user_role_mapping"`
+	// TeamRoleMapping maps "org/team-slug" to a role.
+	TeamRoleMapping map[string]string `yaml:"team_role_mapping,omitempty" mapstructure:"team_role_mapping"`
 }
 
 // APIDatabaseConfig contains database connection settings.
@@ -1,18 +1,29 @@
 package config
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
+	"maps"
+	"math"
+	"mime"
+	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/docker/go-units"
 	"github.com/ethpandaops/benchmarkoor/pkg/cpufreq"
+	"github.com/ethpandaops/benchmarkoor/pkg/diskbench"
+	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
+	"github.com/ethpandaops/benchmarkoor/pkg/thp"
 	"github.com/mitchellh/mapstructure"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/spf13/viper"
@@ -32,18 +43,57 @@ const (
 	// DefaultResultsDir is the default directory for benchmark results.
 	DefaultResultsDir = "./results"
 
+	// DefaultResultsDirTemplate is the default name of a run directory
+	// under <results_dir>/runs.
+	DefaultResultsDirTemplate = "{{.Timestamp}}_{{.RunID}}_{{.Instance}}"
+
 	// DefaultPullPolicy is the default image pull policy.
 	DefaultPullPolicy = "always"
 
+	// DefaultDownloadMaxAttempts is the default number of attempts per
+	// download URL, including the first.
+	DefaultDownloadMaxAttempts = 3
+
+	// DefaultDownloadBackoff is the default delay before the first download retry.
+	DefaultDownloadBackoff = "2s"
+
+	// DefaultDownloadMaxBackoff is the default upper bound of the download retry delay.
+	DefaultDownloadMaxBackoff = "30s"
+
+	// DefaultDiskBenchmarkSize is the default size of the disk probe file.
+	DefaultDiskBenchmarkSize = "256m"
+
+	// DefaultDiskBenchmarkDuration is the default time cap of each disk probe phase.
+	DefaultDiskBenchmarkDuration = "10s"
+
+	// DiskBenchmarkMinSize is the smallest disk probe file: one 1 MiB write block.
+	DiskBenchmarkMinSize = 1024 * 1024
+
 	// DefaultDropCachesPath is the default path to the Linux drop_caches file.
 	DefaultDropCachesPath = "/proc/sys/vm/drop_caches"
 
+	// DefaultAPIRunsWorkDir is the default directory for remote run specs and logs.
+	DefaultAPIRunsWorkDir = "./api-runs"
+
 	// DefaultCPUSysfsPath is the default sysfs path for CPU frequency control.
 	DefaultCPUSysfsPath = "/sys/devices/system/cpu"
 
+	// DefaultTHPSysfsPath is the default sysfs path for transparent huge pages.
+	DefaultTHPSysfsPath = "/sys/kernel/mm/transparent_hugepage"
+
 	// LogTimestampFormat is the UTC timestamp format for log lines.
 	LogTimestampFormat = "2006-01-02T15:04:05.000Z"
 
+	// DefaultTracingServiceName is the default service.name of exported spans.
+	DefaultTracingServiceName = "benchmarkoor"
+
+	// DefaultGitDepth is the default history depth of git source clones.
+	DefaultGitDepth = 1
+
+	// DefaultMaxConsecutiveUnauthorized is the default number of consecutive
+	// Engine API calls rejected with HTTP 401 after which a run is aborted.
+	DefaultMaxConsecutiveUnauthorized = 10
+
 	// RollbackStrategyNone disables rollback after tests.
 	RollbackStrategyNone = "none"
 
@@ -59,6 +109,34 @@ const (
 	// is ready, then instantly restore both per-test.
 	// Requires container_runtime: "podman" and datadir.method: "zfs".
 	RollbackStrategyCheckpointRestore = "container-checkpoint-restore"
+
+	// IRQAffinityCheck records the CPUs servicing NVMe and NIC interrupts and
+	// warns when they overlap the pinned cpuset.
+	IRQAffinityCheck = "check"
+
+	// IRQAffinityMove additionally moves overlapping interrupts to unpinned
+	// CPUs for the duration of the run. Requires root.
+	IRQAffinityMove = "move"
+
+	// MinCPUPeriod and MaxCPUPeriod bound cpu_period in microseconds, matching
+	// the range accepted by the kernel's CFS bandwidth controller.
+	MinCPUPeriod = 1000
+	MaxCPUPeriod = 1000000
+
+	// MinCPUQuota is the smallest cpu_quota in microseconds.
+	MinCPUQuota = 1000
+
+	// DefaultDevicePermissions are the cgroup permissions granted to a
+	// passed-through device: read, write and mknod.
+	DefaultDevicePermissions = "rwm"
+
+	// DefaultBuildDockerfile is the Dockerfile used for image builds,
+	// relative to the build context.
+	DefaultBuildDockerfile = "Dockerfile"
+
+	// DefaultRestartPolicy is the container restart policy. Containers are
+	// not restarted by default, as a restart mid-run corrupts results.
+	DefaultRestartPolicy = "no"
 )
 
 // Config is the root configuration for benchmarkoor.
@@ -70,18 +148,52 @@ type Config struct {
 
 // RunnerConfig contains all run-specific configuration settings.
 type RunnerConfig struct {
-	ContainerRuntime   string            `yaml:"container_runtime,omitempty" mapstructure:"container_runtime"`
-	ClientLogsToStdout bool              `yaml:"client_logs_to_stdout" mapstructure:"client_logs_to_stdout"`
-	ContainerNetwork   string            `yaml:"container_network" mapstructure:"container_network"`
-	CleanupOnStart     bool              `yaml:"cleanup_on_start" mapstructure:"cleanup_on_start"`
-	RunTimeout         string            `yaml:"run_timeout,omitempty" mapstructure:"run_timeout"`
-	Directories        DirectoriesConfig `yaml:"directories,omitempty" mapstructure:"directories"`
-	DropCachesPath     string            `yaml:"drop_caches_path,omitempty" mapstructure:"drop_caches_path"`
-	CPUSysfsPath       string            `yaml:"cpu_sysfs_path,omitempty" mapstructure:"cpu_sysfs_path"`
-	GitHubToken        string            `yaml:"github_token,omitempty" mapstructure:"github_token"`
-	Benchmark          BenchmarkConfig   `yaml:"benchmark" mapstructure:"benchmark"`
-	Client             ClientConfig      `yaml:"client" mapstructure:"client"`
-	Instances          []ClientInstance  `yaml:"instances" mapstructure:"instances"`
+	ContainerRuntime     string               `yaml:"container_runtime,omitempty" mapstructure:"container_runtime"`
+	ClientLogsToStdout   bool                 `yaml:"client_logs_to_stdout" mapstructure:"client_logs_to_stdout"`
+	ClientLogTimestamps  bool                 `yaml:"client_log_timestamps,omitempty" mapstructure:"client_log_timestamps"`
+	ContainerNetwork     string               `yaml:"container_network" mapstructure:"container_network"`
+	CleanupOnStart       bool                 `yaml:"cleanup_on_start" mapstructure:"cleanup_on_start"`
+	RunTimeout           string               `yaml:"run_timeout,omitempty" mapstructure:"run_timeout"`
+	Directories          DirectoriesConfig    `yaml:"directories,omitempty" mapstructure:"directories"`
+	DropCachesPath       string               `yaml:"drop_caches_path,omitempty" mapstructure:"drop_caches_path"`
+	DropCachesCommand    []string             `yaml:"drop_caches_command,omitempty" mapstructure:"drop_caches_command"`
+	CPUSysfsPath         string               `yaml:"cpu_sysfs_path,omitempty" mapstructure:"cpu_sysfs_path"`
+	THPSysfsPath         string               `yaml:"thp_sysfs_path,omitempty" mapstructure:"thp_sysfs_path"`
+	FailOnHostSwap       bool                 `yaml:"fail_on_host_swap,omitempty" mapstructure:"fail_on_host_swap"`
+	SkipBlkioDeviceCheck bool                 `yaml:"skip_blkio_device_check,omitempty" mapstructure:"skip_blkio_device_check"`
+	DiskBenchmark        *DiskBenchmarkConfig `yaml:"disk_benchmark,omitempty" mapstructure:"disk_benchmark"`
+	GitHubToken          string               `yaml:"github_token,omitempty" mapstructure:"github_token"`
+	DownloadRetries      *DownloadRetryConfig `yaml:"download_retries,omitempty" mapstructure:"download_retries"`
+	Benchmark            BenchmarkConfig      `yaml:"benchmark" mapstructure:"benchmark"`
+	Client               ClientConfig         `yaml:"client" mapstructure:"client"`
+	// PostRunCommand is run after each instance run, once its results are
+	// written and uploaded. See runner.runPostRunCommand.
+	PostRunCommand         []string `yaml:"post_run_command,omitempty" mapstructure:"post_run_command"`
+	PostRunCommandRequired bool     `yaml:"post_run_command_required,omitempty" mapstructure:"post_run_command_required"`
+	// RedactEnv lists environment variable name patterns whose values are
+	// replaced in config.json. See GetRedactEnvPatterns.
+	RedactEnv []string `yaml:"redact_env,omitempty" mapstructure:"redact_env"`
+	// InstanceDefaults is a template every instance inherits fields from
+	// unless it sets them itself. See applyInstanceDefaults.
+	InstanceDefaults *ClientInstance  `yaml:"instance_defaults,omitempty" mapstructure:"instance_defaults"`
+	Instances        []ClientInstance `yaml:"instances" mapstructure:"instances"`
+}
+
+// DownloadRetryConfig configures retries of genesis file and EEST fixture
+// downloads after server errors (5xx) and connection failures.
+type DownloadRetryConfig struct {
+	MaxAttempts int    `yaml:"max_attempts" mapstructure:"max_attempts"`
+	Backoff     string `yaml:"backoff" mapstructure:"backoff"`
+	MaxBackoff  string `yaml:"max_backoff,omitempty" mapstructure:"max_backoff"`
+}
+
+// DiskBenchmarkConfig configures the optional disk probe that runs on the
+// datadir's filesystem before each client starts.
+type DiskBenchmarkConfig struct {
+	Enabled  bool   `yaml:"enabled" mapstructure:"enabled"`
+	Tool     string `yaml:"tool,omitempty" mapstructure:"tool"`
+	Size     string `yaml:"size,omitempty" mapstructure:"size"`
+	Duration string `yaml:"duration,omitempty" mapstructure:"duration"`
 }
 
 // MetadataConfig contains arbitrary metadata labels for a benchmark run.
@@ -91,7 +203,31 @@ type MetadataConfig struct {
 
 // GlobalConfig contains global application settings.
 type GlobalConfig struct {
-	LogLevel string `yaml:"log_level" mapstructure:"log_level"`
+	LogLevel string         `yaml:"log_level" mapstructure:"log_level"`
+	Tracing  *TracingConfig `yaml:"tracing,omitempty" mapstructure:"tracing"`
+}
+
+// TracingConfig configures OTLP trace export of test and RPC spans.
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector, as host:port or a full URL
+	// (e.g. http://localhost:4318/v1/traces).
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+	// Insecure disables TLS when Endpoint is given as host:port.
+	Insecure bool `yaml:"insecure,omitempty" mapstructure:"insecure"`
+	// Headers are sent with every export request, e.g. for authentication.
+	Headers map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+	// ServiceName is the service.name resource attribute. Defaults to
+	// "benchmarkoor".
+	ServiceName string `yaml:"service_name,omitempty" mapstructure:"service_name"`
+}
+
+// GetServiceName returns the configured service name or the default.
+func (t *TracingConfig) GetServiceName() string {
+	if t.ServiceName != "" {
+		return t.ServiceName
+	}
+
+	return DefaultTracingServiceName
 }
 
 // DirectoriesConfig contains directory path configurations.
@@ -108,6 +244,9 @@ type DirectoriesConfig struct {
 type BenchmarkConfig struct {
 	ResultsDir                      string               `yaml:"results_dir" mapstructure:"results_dir"`
 	ResultsOwner                    string               `yaml:"results_owner,omitempty" mapstructure:"results_owner"`
+	ResultsMode                     string               `yaml:"results_mode,omitempty" mapstructure:"results_mode"`
+	ResultsDirTemplate              string               `yaml:"results_dir_template,omitempty" mapstructure:"results_dir_template"`
+	ResultsLayout                   string               `yaml:"results_layout,omitempty" mapstructure:"results_layout"`
 	SkipTestRun                     bool                 `yaml:"skip_test_run" mapstructure:"skip_test_run"`
 	SystemResourceCollectionEnabled *bool                `yaml:"system_resource_collection_enabled,omitempty" mapstructure:"system_resource_collection_enabled"`
 	GenerateResultsIndex            bool                 `yaml:"generate_results_index" mapstructure:"generate_results_index"`
@@ -143,6 +282,30 @@ type TestsConfig struct {
 	Filter   string         `yaml:"filter,omitempty" mapstructure:"filter"`
 	Metadata MetadataConfig `yaml:"metadata,omitempty" mapstructure:"metadata"`
 	Source   SourceConfig   `yaml:"source,omitempty" mapstructure:"source"`
+	// AllowEmptyGlobs logs a warning instead of failing when a pre-run step
+	// or step glob matches no files.
+	AllowEmptyGlobs bool `yaml:"allow_empty_globs,omitempty" mapstructure:"allow_empty_globs"`
+	// SkipTestOnSetupFailure skips the test step of a test whose setup step
+	// failed and records the test as skipped instead of failed. Cleanup
+	// still runs.
+	SkipTestOnSetupFailure bool `yaml:"skip_test_on_setup_failure,omitempty" mapstructure:"skip_test_on_setup_failure"`
+	// MaxConsecutiveUnauthorized aborts a run once this many consecutive
+	// Engine API calls were rejected with HTTP 401, a sign of a JWT secret
+	// mismatch (0 = never abort, nil = DefaultMaxConsecutiveUnauthorized).
+	MaxConsecutiveUnauthorized *int `yaml:"max_consecutive_unauthorized,omitempty" mapstructure:"max_consecutive_unauthorized"`
+	// GenesisGroupParallelism runs up to this many genesis groups of an
+	// instance at once, each in its own container (0 or 1 = one at a time).
+	GenesisGroupParallelism int `yaml:"genesis_group_parallelism,omitempty" mapstructure:"genesis_group_parallelism"`
+}
+
+// GetMaxConsecutiveUnauthorized returns the number of consecutive HTTP 401
+// responses after which a run is aborted, 0 if it never is.
+func (t *TestsConfig) GetMaxConsecutiveUnauthorized() int {
+	if t.MaxConsecutiveUnauthorized == nil {
+		return DefaultMaxConsecutiveUnauthorized
+	}
+
+	return *t.MaxConsecutiveUnauthorized
 }
 
 // SourceConfig defines where to find test files.
@@ -162,6 +325,12 @@ type EESTFixturesSource struct {
 	FixturesURL    string `yaml:"fixtures_url,omitempty" mapstructure:"fixtures_url"`
 	GenesisURL     string `yaml:"genesis_url,omitempty" mapstructure:"genesis_url"`
 	FixturesSubdir string `yaml:"fixtures_subdir,omitempty" mapstructure:"fixtures_subdir"`
+	// Mirror URLs tried in order when the release tarball download fails.
+	FixturesMirrors []string `yaml:"fixtures_mirrors,omitempty" mapstructure:"fixtures_mirrors"`
+	GenesisMirrors  []string `yaml:"genesis_mirrors,omitempty" mapstructure:"genesis_mirrors"`
+	// DownloadParts splits each release tarball into this many concurrent
+	// range requests when the server supports them. 0 or 1 streams it.
+	DownloadParts int `yaml:"download_parts,omitempty" mapstructure:"download_parts"`
 	// GitHub Actions artifact support (alternative to releases).
 	FixturesArtifactName  string `yaml:"fixtures_artifact_name,omitempty" mapstructure:"fixtures_artifact_name"`
 	GenesisArtifactName   string `yaml:"genesis_artifact_name,omitempty" mapstructure:"genesis_artifact_name"`
@@ -173,6 +342,12 @@ type EESTFixturesSource struct {
 	// Local tarball support (.tar.gz files).
 	LocalFixturesTarball string `yaml:"local_fixtures_tarball,omitempty" mapstructure:"local_fixtures_tarball"`
 	LocalGenesisTarball  string `yaml:"local_genesis_tarball,omitempty" mapstructure:"local_genesis_tarball"`
+	// CacheMaxSize bounds the total size of the extracted fixtures in the
+	// cache dir, e.g. "50g". The least recently used are evicted first.
+	CacheMaxSize string `yaml:"cache_max_size,omitempty" mapstructure:"cache_max_size"`
+	// VerifyGenesisHash checks, before running the tests of a pre_alloc
+	// genesis group, that the client's genesis block has the group's hash.
+	VerifyGenesisHash bool `yaml:"verify_genesis_hash,omitempty" mapstructure:"verify_genesis_hash"`
 }
 
 // UseArtifacts returns true if the source is configured to use GitHub Actions artifacts.
@@ -236,6 +411,36 @@ func (e *EESTFixturesSource) validate() error {
 		return fmt.Errorf("eest_fixtures.github_repo is required for release/artifact modes")
 	}
 
+	if (len(e.FixturesMirrors) > 0 || len(e.GenesisMirrors) > 0) && !hasRelease {
+		return fmt.Errorf("eest_fixtures: fixtures_mirrors/genesis_mirrors require github_release")
+	}
+
+	if e.DownloadParts < 0 {
+		return fmt.Errorf("eest_fixtures.download_parts must not be negative")
+	}
+
+	if e.DownloadParts > 1 && !hasRelease {
+		return fmt.Errorf("eest_fixtures: download_parts requires github_release")
+	}
+
+	if err := validateMirrorURLs(e.FixturesMirrors, "eest_fixtures.fixtures_mirrors"); err != nil {
+		return err
+	}
+
+	if err := validateMirrorURLs(e.GenesisMirrors, "eest_fixtures.genesis_mirrors"); err != nil {
+		return err
+	}
+
+	if e.CacheMaxSize != "" {
+		if hasLocalDir {
+			return fmt.Errorf("eest_fixtures: cache_max_size does not apply to local_fixtures_dir/local_genesis_dir")
+		}
+
+		if _, err := ParseByteSize(e.CacheMaxSize); err != nil {
+			return fmt.Errorf("eest_fixtures.cache_max_size: %w", err)
+		}
+	}
+
 	// Validate local dir mode.
 	if hasLocalDir {
 		if e.LocalFixturesDir == "" {
@@ -277,6 +482,17 @@ func (e *EESTFixturesSource) validate() error {
 	return nil
 }
 
+// validateMirrorURLs checks that every mirror is an http(s) URL.
+func validateMirrorURLs(mirrors []string, field string) error {
+	for i, mirror := range mirrors {
+		if !strings.HasPrefix(mirror, "http://") && !strings.HasPrefix(mirror, "https://") {
+			return fmt.Errorf("%s[%d]: %q must be an http(s) URL", field, i, mirror)
+		}
+	}
+
+	return nil
+}
+
 // validateDirExists checks that the given path exists and is a directory.
 func validateDirExists(path, field string) error {
 	info, err := os.Stat(path)
@@ -322,6 +538,46 @@ type GitSourceV2 struct {
 	Version     string       `yaml:"version" mapstructure:"version"`
 	PreRunSteps []string     `yaml:"pre_run_steps,omitempty" mapstructure:"pre_run_steps"`
 	Steps       *StepsConfig `yaml:"steps,omitempty" mapstructure:"steps"`
+	// Depth is the history depth to fetch (0 = full history, nil = DefaultGitDepth).
+	Depth *int `yaml:"depth,omitempty" mapstructure:"depth"`
+	// SparsePaths enables a sparse checkout of these paths (gitignore-style
+	// patterns) plus the pre-run step and step globs. Empty = full checkout.
+	SparsePaths []string `yaml:"sparse_paths,omitempty" mapstructure:"sparse_paths"`
+	// PreRunStepsSource is an optional separate repository, e.g. a shared
+	// setup repo, whose pre_run_steps run before this source's own.
+	PreRunStepsSource *GitSourceV2 `yaml:"pre_run_steps_source,omitempty" mapstructure:"pre_run_steps_source"`
+}
+
+// validate checks the git source fields, prefixing errors with field.
+func (g *GitSourceV2) validate(field string) error {
+	if g.Repo == "" {
+		return fmt.Errorf("%s.repo is required", field)
+	}
+
+	if g.Version == "" {
+		return fmt.Errorf("%s.version is required", field)
+	}
+
+	if g.Depth != nil && *g.Depth < 0 {
+		return fmt.Errorf("%s.depth must be >= 0, got %d", field, *g.Depth)
+	}
+
+	for _, p := range g.SparsePaths {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("%s.sparse_paths must not contain empty paths", field)
+		}
+	}
+
+	return nil
+}
+
+// GetDepth returns the configured clone depth or the default.
+func (g *GitSourceV2) GetDepth() int {
+	if g.Depth != nil {
+		return *g.Depth
+	}
+
+	return DefaultGitDepth
 }
 
 // LocalSourceV2 defines a local directory source for tests with step-based structure.
@@ -353,6 +609,12 @@ func (s *SourceConfig) IsConfigured() bool {
 	return s.Git != nil || s.Local != nil || s.Archive != nil || s.EESTFixtures != nil
 }
 
+// VerifyGenesisHash returns true if the genesis block hash of each genesis
+// group is verified after the client starts.
+func (s *SourceConfig) VerifyGenesisHash() bool {
+	return s.EESTFixtures != nil && s.EESTFixtures.VerifyGenesisHash
+}
+
 // DefaultContainerDir is the default container mount path for data directories.
 const DefaultContainerDir = "/data"
 
@@ -404,14 +666,20 @@ type DumpConfig struct {
 
 // ResourceLimits configures container resource constraints.
 type ResourceLimits struct {
-	CpusetCount   *int         `yaml:"cpuset_count,omitempty" mapstructure:"cpuset_count" json:"cpuset_count,omitempty"`
-	Cpuset        []int        `yaml:"cpuset,omitempty" mapstructure:"cpuset" json:"cpuset,omitempty"`
-	Memory        string       `yaml:"memory,omitempty" mapstructure:"memory" json:"memory,omitempty"`
-	SwapDisabled  bool         `yaml:"swap_disabled,omitempty" mapstructure:"swap_disabled" json:"swap_disabled,omitempty"`
-	BlkioConfig   *BlkioConfig `yaml:"blkio_config,omitempty" mapstructure:"blkio_config" json:"blkio_config,omitempty"`
-	CPUFreq       string       `yaml:"cpu_freq,omitempty" mapstructure:"cpu_freq" json:"cpu_freq,omitempty"`
-	CPUTurboBoost *bool        `yaml:"cpu_turboboost,omitempty" mapstructure:"cpu_turboboost" json:"cpu_turboboost,omitempty"`
-	CPUGovernor   string       `yaml:"cpu_freq_governor,omitempty" mapstructure:"cpu_freq_governor" json:"cpu_freq_governor,omitempty"`
+	CpusetCount         *int         `yaml:"cpuset_count,omitempty" mapstructure:"cpuset_count" json:"cpuset_count,omitempty"`
+	Cpuset              []int        `yaml:"cpuset,omitempty" mapstructure:"cpuset" json:"cpuset,omitempty"`
+	CPUs                string       `yaml:"cpus,omitempty" mapstructure:"cpus" json:"cpus,omitempty"`
+	CPUQuota            int64        `yaml:"cpu_quota,omitempty" mapstructure:"cpu_quota" json:"cpu_quota,omitempty"`
+	CPUPeriod           uint64       `yaml:"cpu_period,omitempty" mapstructure:"cpu_period" json:"cpu_period,omitempty"`
+	Memory              string       `yaml:"memory,omitempty" mapstructure:"memory" json:"memory,omitempty"`
+	MemoryReservation   string       `yaml:"memory_reservation,omitempty" mapstructure:"memory_reservation" json:"memory_reservation,omitempty"`
+	SwapDisabled        bool         `yaml:"swap_disabled,omitempty" mapstructure:"swap_disabled" json:"swap_disabled,omitempty"`
+	BlkioConfig         *BlkioConfig `yaml:"blkio_config,omitempty" mapstructure:"blkio_config" json:"blkio_config,omitempty"`
+	CPUFreq             string       `yaml:"cpu_freq,omitempty" mapstructure:"cpu_freq" json:"cpu_freq,omitempty"`
+	CPUTurboBoost       *bool        `yaml:"cpu_turboboost,omitempty" mapstructure:"cpu_turboboost" json:"cpu_turboboost,omitempty"`
+	CPUGovernor         string       `yaml:"cpu_freq_governor,omitempty" mapstructure:"cpu_freq_governor" json:"cpu_freq_governor,omitempty"`
+	TransparentHugepage string       `yaml:"transparent_hugepage,omitempty" mapstructure:"transparent_hugepage" json:"transparent_hugepage,omitempty"`
+	IRQAffinity         string       `yaml:"irq_affinity,omitempty" mapstructure:"irq_affinity" json:"irq_affinity,omitempty"`
 }
 
 // BlkioConfig configures container block I/O limits.
@@ -420,14 +688,60 @@ type BlkioConfig struct {
 	DeviceReadIOps  []ThrottleDevice `yaml:"device_read_iops,omitempty" mapstructure:"device_read_iops" json:"device_read_iops,omitempty"`
 	DeviceWriteBps  []ThrottleDevice `yaml:"device_write_bps,omitempty" mapstructure:"device_write_bps" json:"device_write_bps,omitempty"`
 	DeviceWriteIOps []ThrottleDevice `yaml:"device_write_iops,omitempty" mapstructure:"device_write_iops" json:"device_write_iops,omitempty"`
+	// Weight is the container's relative I/O weight (10-1000) for
+	// proportional sharing between concurrent instances. 0 leaves it unset.
+	Weight       uint16         `yaml:"weight,omitempty" mapstructure:"weight" json:"weight,omitempty"`
+	DeviceWeight []WeightDevice `yaml:"device_weight,omitempty" mapstructure:"device_weight" json:"device_weight,omitempty"`
+}
+
+// Blkio weight bounds accepted by the kernel's proportional I/O controller.
+const (
+	MinBlkioWeight = 10
+	MaxBlkioWeight = 1000
+)
+
+// BlkioDeviceAuto is the blkio device path that is resolved at run time
+// to the block device backing the instance's datadir.
+const BlkioDeviceAuto = "auto"
+
+// UsesAutoDevice returns true if any device path is "auto".
+func (b *BlkioConfig) UsesAutoDevice() bool {
+	if b == nil {
+		return false
+	}
+
+	for _, devices := range [][]ThrottleDevice{
+		b.DeviceReadBps, b.DeviceReadIOps, b.DeviceWriteBps, b.DeviceWriteIOps,
+	} {
+		for _, dev := range devices {
+			if dev.Path == BlkioDeviceAuto {
+				return true
+			}
+		}
+	}
+
+	for _, dev := range b.DeviceWeight {
+		if dev.Path == BlkioDeviceAuto {
+			return true
+		}
+	}
+
+	return false
 }
 
 // ThrottleDevice defines a device throttle setting.
 type ThrottleDevice struct {
-	Path string `yaml:"path" mapstructure:"path" json:"path"`
+	Path string `yaml:"path" mapstructure:"path" json:"path"` // Device path, or "auto" for the device backing the datadir.
 	Rate string `yaml:"rate" mapstructure:"rate" json:"rate"` // For bps: supports units like "12mb", "1024k". For iops: integer string.
 }
 
+// WeightDevice defines a per-device relative I/O weight, overriding the
+// container's weight on that device.
+type WeightDevice struct {
+	Path   string `yaml:"path" mapstructure:"path" json:"path"` // Device path, or "auto" for the device backing the datadir.
+	Weight uint16 `yaml:"weight" mapstructure:"weight" json:"weight"`
+}
+
 // Validate checks the resource limits configuration for errors.
 func (r *ResourceLimits) Validate(prefix string) error {
 	if r == nil {
@@ -473,6 +787,27 @@ func (r *ResourceLimits) Validate(prefix string) error {
 		}
 	}
 
+	// Validate CPU bandwidth limits.
+	if err := r.validateCPUBandwidth(prefix, numCPUs); err != nil {
+		return err
+	}
+
+	// Validate irq_affinity.
+	switch r.IRQAffinity {
+	case "":
+	case IRQAffinityCheck, IRQAffinityMove:
+		if r.CpusetCount == nil && len(r.Cpuset) == 0 {
+			return fmt.Errorf("%s: irq_affinity requires cpuset or cpuset_count", prefix)
+		}
+
+		if runtime.GOOS != "linux" {
+			return fmt.Errorf("%s: irq_affinity is only supported on Linux (current OS: %s)", prefix, runtime.GOOS)
+		}
+	default:
+		return fmt.Errorf("%s: invalid irq_affinity %q (must be %q or %q)",
+			prefix, r.IRQAffinity, IRQAffinityCheck, IRQAffinityMove)
+	}
+
 	// Validate memory format.
 	if r.Memory != "" {
 		if _, err := units.RAMInBytes(r.Memory); err != nil {
@@ -480,6 +815,27 @@ func (r *ResourceLimits) Validate(prefix string) error {
 		}
 	}
 
+	// Validate memory_reservation.
+	if r.MemoryReservation != "" {
+		reservation, err := ParseByteSize(r.MemoryReservation)
+		if err != nil {
+			return fmt.Errorf("%s: invalid memory_reservation: %w", prefix, err)
+		}
+
+		if reservation == 0 {
+			return fmt.Errorf("%s: memory_reservation must be greater than 0", prefix)
+		}
+
+		if r.Memory != "" {
+			// Memory format is validated above.
+			limit, _ := units.RAMInBytes(r.Memory)
+			if reservation > uint64(limit) {
+				return fmt.Errorf("%s: memory_reservation (%s) must not exceed memory (%s)",
+					prefix, r.MemoryReservation, r.Memory)
+			}
+		}
+	}
+
 	// Validate blkio_config.
 	if r.BlkioConfig != nil {
 		if err := r.BlkioConfig.Validate(prefix + ".blkio_config"); err != nil {
@@ -490,6 +846,70 @@ func (r *ResourceLimits) Validate(prefix string) error {
 	return nil
 }
 
+// validateCPUBandwidth validates the cpus, cpu_quota and cpu_period options.
+func (r *ResourceLimits) validateCPUBandwidth(prefix string, numCPUs int) error {
+	if r.CPUs != "" && (r.CPUQuota != 0 || r.CPUPeriod != 0) {
+		return fmt.Errorf("%s: cpus and cpu_quota/cpu_period are mutually exclusive", prefix)
+	}
+
+	// Upper bound for the bandwidth limit: the pinned CPUs if any, otherwise
+	// all online CPUs.
+	maxCPUs := numCPUs
+	if r.CpusetCount != nil {
+		maxCPUs = *r.CpusetCount
+	} else if len(r.Cpuset) > 0 {
+		maxCPUs = len(r.Cpuset)
+	}
+
+	if r.CPUs != "" {
+		nanoCPUs, err := ParseNanoCPUs(r.CPUs)
+		if err != nil {
+			return fmt.Errorf("%s: invalid cpus %q: %w", prefix, r.CPUs, err)
+		}
+
+		if nanoCPUs > int64(maxCPUs)*1e9 {
+			return fmt.Errorf("%s: cpus (%s) exceeds available CPUs (%d)", prefix, r.CPUs, maxCPUs)
+		}
+	}
+
+	if r.CPUPeriod != 0 {
+		if r.CPUPeriod < MinCPUPeriod || r.CPUPeriod > MaxCPUPeriod {
+			return fmt.Errorf("%s: cpu_period must be between %d and %d microseconds",
+				prefix, MinCPUPeriod, MaxCPUPeriod)
+		}
+
+		if r.CPUQuota == 0 {
+			return fmt.Errorf("%s: cpu_period requires cpu_quota", prefix)
+		}
+	}
+
+	if r.CPUQuota != 0 && r.CPUQuota < MinCPUQuota {
+		return fmt.Errorf("%s: cpu_quota must be at least %d microseconds", prefix, MinCPUQuota)
+	}
+
+	return nil
+}
+
+// ParseNanoCPUs parses a fractional CPU count such as "2.5" into billionths
+// of a CPU, the unit Docker uses for NanoCPUs.
+func ParseNanoCPUs(s string) (int64, error) {
+	cpus, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing CPU count: %w", err)
+	}
+
+	if math.IsNaN(cpus) || math.IsInf(cpus, 0) {
+		return 0, fmt.Errorf("CPU count must be a finite number")
+	}
+
+	nanoCPUs := int64(math.Round(cpus * 1e9))
+	if nanoCPUs <= 0 {
+		return 0, fmt.Errorf("CPU count must be greater than 0")
+	}
+
+	return nanoCPUs, nil
+}
+
 // Validate checks the blkio configuration for errors.
 func (b *BlkioConfig) Validate(prefix string) error {
 	// Validate device_read_bps (bandwidth rates).
@@ -520,6 +940,35 @@ func (b *BlkioConfig) Validate(prefix string) error {
 		}
 	}
 
+	// Validate weight (0 = unset).
+	if b.Weight != 0 {
+		if err := validateBlkioWeight(b.Weight, prefix+".weight"); err != nil {
+			return err
+		}
+	}
+
+	// Validate device_weight.
+	for i, dev := range b.DeviceWeight {
+		devPrefix := fmt.Sprintf("%s.device_weight[%d]", prefix, i)
+
+		if dev.Path == "" {
+			return fmt.Errorf("%s: path is required", devPrefix)
+		}
+
+		if err := validateBlkioWeight(dev.Weight, devPrefix+".weight"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateBlkioWeight checks that a blkio weight is within the kernel's range.
+func validateBlkioWeight(weight uint16, prefix string) error {
+	if weight < MinBlkioWeight || weight > MaxBlkioWeight {
+		return fmt.Errorf("%s: %d must be between %d and %d", prefix, weight, MinBlkioWeight, MaxBlkioWeight)
+	}
+
 	return nil
 }
 
@@ -599,7 +1048,10 @@ type ClientConfig struct {
 type ClientDefaults struct {
 	JWT                              string                            `yaml:"jwt" mapstructure:"jwt"`
 	Genesis                          map[string]string                 `yaml:"genesis" mapstructure:"genesis"`
+	GenesisMirrors                   map[string][]string               `yaml:"genesis_mirrors,omitempty" mapstructure:"genesis_mirrors"`
 	DropMemoryCaches                 string                            `yaml:"drop_memory_caches,omitempty" mapstructure:"drop_memory_caches"`
+	PrimePageCache                   *bool                             `yaml:"prime_page_cache,omitempty" mapstructure:"prime_page_cache"`
+	SyncBetweenTests                 *bool                             `yaml:"sync_between_tests,omitempty" mapstructure:"sync_between_tests"`
 	RollbackStrategy                 string                            `yaml:"rollback_strategy,omitempty" mapstructure:"rollback_strategy"`
 	ResourceLimits                   *ResourceLimits                   `yaml:"resource_limits,omitempty" mapstructure:"resource_limits"`
 	RetryNewPayloadsSyncingState     *RetryNewPayloadsSyncingConfig    `yaml:"retry_new_payloads_syncing_state,omitempty" mapstructure:"retry_new_payloads_syncing_state"`
@@ -609,6 +1061,9 @@ type ClientDefaults struct {
 	PostTestSleepDuration            string                            `yaml:"post_test_sleep_duration,omitempty" mapstructure:"post_test_sleep_duration"`
 	BootstrapFCU                     *BootstrapFCUConfig               `yaml:"bootstrap_fcu,omitempty" mapstructure:"bootstrap_fcu"`
 	CheckpointRestoreStrategyOptions *CheckpointRestoreStrategyOptions `yaml:"checkpoint_restore_strategy_options,omitempty" mapstructure:"checkpoint_restore_strategy_options"`
+	IsolateNetwork                   *bool                             `yaml:"isolate_network,omitempty" mapstructure:"isolate_network"`
+	FCUKeepaliveInterval             string                            `yaml:"fcu_keepalive_interval,omitempty" mapstructure:"fcu_keepalive_interval"`
+	PreRunCommand                    []string                          `yaml:"pre_run_command,omitempty" mapstructure:"pre_run_command"`
 	Metadata                         MetadataConfig                    `yaml:"metadata,omitempty" mapstructure:"metadata"`
 }
 
@@ -617,15 +1072,22 @@ type ClientInstance struct {
 	ID                               string                            `yaml:"id" mapstructure:"id"`
 	Client                           string                            `yaml:"client" mapstructure:"client"`
 	Image                            string                            `yaml:"image,omitempty" mapstructure:"image"`
+	ImageDigest                      string                            `yaml:"image_digest,omitempty" mapstructure:"image_digest"`
+	ClientCommit                     string                            `yaml:"client_commit,omitempty" mapstructure:"client_commit"`
+	Build                            *BuildConfig                      `yaml:"build,omitempty" mapstructure:"build"`
 	Entrypoint                       []string                          `yaml:"entrypoint,omitempty" mapstructure:"entrypoint"`
 	Command                          []string                          `yaml:"command,omitempty" mapstructure:"command"`
 	ExtraArgs                        []string                          `yaml:"extra_args,omitempty" mapstructure:"extra_args"`
+	RemoveDefaultArgs                []string                          `yaml:"remove_default_args,omitempty" mapstructure:"remove_default_args"`
 	PullPolicy                       string                            `yaml:"pull_policy,omitempty" mapstructure:"pull_policy"`
 	Restart                          string                            `yaml:"restart,omitempty" mapstructure:"restart"`
 	Environment                      map[string]string                 `yaml:"environment,omitempty" mapstructure:"environment"`
 	Genesis                          string                            `yaml:"genesis,omitempty" mapstructure:"genesis"`
+	GenesisMirrors                   []string                          `yaml:"genesis_mirrors,omitempty" mapstructure:"genesis_mirrors"`
 	DataDir                          *DataDirConfig                    `yaml:"datadir,omitempty" mapstructure:"datadir"`
 	DropMemoryCaches                 string                            `yaml:"drop_memory_caches,omitempty" mapstructure:"drop_memory_caches"`
+	PrimePageCache                   *bool                             `yaml:"prime_page_cache,omitempty" mapstructure:"prime_page_cache"`
+	SyncBetweenTests                 *bool                             `yaml:"sync_between_tests,omitempty" mapstructure:"sync_between_tests"`
 	RollbackStrategy                 string                            `yaml:"rollback_strategy,omitempty" mapstructure:"rollback_strategy"`
 	ResourceLimits                   *ResourceLimits                   `yaml:"resource_limits,omitempty" mapstructure:"resource_limits"`
 	RetryNewPayloadsSyncingState     *RetryNewPayloadsSyncingConfig    `yaml:"retry_new_payloads_syncing_state,omitempty" mapstructure:"retry_new_payloads_syncing_state"`
@@ -635,7 +1097,202 @@ type ClientInstance struct {
 	PostTestSleepDuration            string                            `yaml:"post_test_sleep_duration,omitempty" mapstructure:"post_test_sleep_duration"`
 	BootstrapFCU                     *BootstrapFCUConfig               `yaml:"bootstrap_fcu,omitempty" mapstructure:"bootstrap_fcu"`
 	CheckpointRestoreStrategyOptions *CheckpointRestoreStrategyOptions `yaml:"checkpoint_restore_strategy_options,omitempty" mapstructure:"checkpoint_restore_strategy_options"`
+	IsolateNetwork                   *bool                             `yaml:"isolate_network,omitempty" mapstructure:"isolate_network"`
+	FCUKeepaliveInterval             string                            `yaml:"fcu_keepalive_interval,omitempty" mapstructure:"fcu_keepalive_interval"`
+	Devices                          []Device                          `yaml:"devices,omitempty" mapstructure:"devices"`
+	SecretFiles                      []SecretFile                      `yaml:"secret_files,omitempty" mapstructure:"secret_files"`
+	PreRunCommand                    []string                          `yaml:"pre_run_command,omitempty" mapstructure:"pre_run_command"`
 	Metadata                         MetadataConfig                    `yaml:"metadata,omitempty" mapstructure:"metadata"`
+	// Matrix expands the instance into one instance per combination of the
+	// values of its axes, keyed by instance field path. See expandMatrices.
+	Matrix map[string][]any `yaml:"matrix,omitempty" mapstructure:"matrix"`
+}
+
+// Device maps a host device (e.g. a raw NVMe block device) into the container.
+type Device struct {
+	HostPath      string `yaml:"host_path" mapstructure:"host_path" json:"host_path"`
+	ContainerPath string `yaml:"container_path,omitempty" mapstructure:"container_path" json:"container_path,omitempty"`
+	Permissions   string `yaml:"permissions,omitempty" mapstructure:"permissions" json:"permissions,omitempty"`
+}
+
+// GetContainerPath returns the container path, defaulting to the host path.
+func (d *Device) GetContainerPath() string {
+	if d.ContainerPath != "" {
+		return d.ContainerPath
+	}
+
+	return d.HostPath
+}
+
+// GetPermissions returns the cgroup permissions, defaulting to "rwm".
+func (d *Device) GetPermissions() string {
+	if d.Permissions != "" {
+		return d.Permissions
+	}
+
+	return DefaultDevicePermissions
+}
+
+// Validate checks the device mapping and that the host device exists.
+func (d *Device) Validate(prefix string) error {
+	if d.HostPath == "" {
+		return fmt.Errorf("%s: host_path is required", prefix)
+	}
+
+	if !filepath.IsAbs(d.HostPath) {
+		return fmt.Errorf("%s: host_path %q must be absolute", prefix, d.HostPath)
+	}
+
+	if d.ContainerPath != "" && !filepath.IsAbs(d.ContainerPath) {
+		return fmt.Errorf("%s: container_path %q must be absolute", prefix, d.ContainerPath)
+	}
+
+	seen := make(map[rune]struct{}, len(d.Permissions))
+
+	for _, p := range d.Permissions {
+		if !strings.ContainsRune(DefaultDevicePermissions, p) {
+			return fmt.Errorf("%s: invalid permissions %q (must be a combination of r, w and m)",
+				prefix, d.Permissions)
+		}
+
+		if _, exists := seen[p]; exists {
+			return fmt.Errorf("%s: duplicate permission %q in %q", prefix, p, d.Permissions)
+		}
+
+		seen[p] = struct{}{}
+	}
+
+	info, err := os.Stat(d.HostPath)
+	if err != nil {
+		return fmt.Errorf("%s: host device %q: %w", prefix, d.HostPath, err)
+	}
+
+	if info.Mode()&os.ModeDevice == 0 {
+		return fmt.Errorf("%s: host_path %q is not a device", prefix, d.HostPath)
+	}
+
+	return nil
+}
+
+// SecretFile mounts a secret into the container as a read-only file. The
+// value is read from a host file or a host environment variable at run time,
+// so unlike environment it is never written to the run's config.json.
+type SecretFile struct {
+	ContainerPath string `yaml:"container_path" mapstructure:"container_path"`
+	File          string `yaml:"file,omitempty" mapstructure:"file"`
+	Env           string `yaml:"env,omitempty" mapstructure:"env"`
+}
+
+// Validate checks that the secret has an absolute container path and exactly
+// one source, which exists on this host.
+func (s *SecretFile) Validate(prefix string) error {
+	if s.ContainerPath == "" {
+		return fmt.Errorf("%s: container_path is required", prefix)
+	}
+
+	if !filepath.IsAbs(s.ContainerPath) {
+		return fmt.Errorf("%s: container_path %q must be absolute", prefix, s.ContainerPath)
+	}
+
+	switch {
+	case s.File == "" && s.Env == "":
+		return fmt.Errorf("%s: one of file or env is required", prefix)
+	case s.File != "" && s.Env != "":
+		return fmt.Errorf("%s: file and env are mutually exclusive", prefix)
+	case s.File != "":
+		if !filepath.IsAbs(s.File) {
+			return fmt.Errorf("%s: file %q must be absolute", prefix, s.File)
+		}
+
+		info, err := os.Stat(s.File)
+		if err != nil {
+			return fmt.Errorf("%s: %w", prefix, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s: file %q is not a regular file", prefix, s.File)
+		}
+	default:
+		if os.Getenv(s.Env) == "" {
+			return fmt.Errorf("%s: environment variable %s is not set", prefix, s.Env)
+		}
+	}
+
+	return nil
+}
+
+// BuildConfig builds the client image from a local Dockerfile instead of
+// pulling it.
+type BuildConfig struct {
+	Context    string            `yaml:"context" mapstructure:"context" json:"context"`
+	Dockerfile string            `yaml:"dockerfile,omitempty" mapstructure:"dockerfile" json:"dockerfile,omitempty"`
+	BuildArgs  map[string]string `yaml:"build_args,omitempty" mapstructure:"build_args" json:"build_args,omitempty"`
+}
+
+// GetDockerfile returns the Dockerfile path relative to the build context,
+// defaulting to "Dockerfile".
+func (b *BuildConfig) GetDockerfile() string {
+	if b.Dockerfile != "" {
+		return b.Dockerfile
+	}
+
+	return DefaultBuildDockerfile
+}
+
+// Validate checks that the build context and Dockerfile exist.
+func (b *BuildConfig) Validate(prefix string) error {
+	if b.Context == "" {
+		return fmt.Errorf("%s: context is required", prefix)
+	}
+
+	info, err := os.Stat(b.Context)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s: context %q does not exist", prefix, b.Context)
+		}
+
+		return fmt.Errorf("%s: checking context: %w", prefix, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s: context %q is not a directory", prefix, b.Context)
+	}
+
+	dockerfile := b.GetDockerfile()
+	if filepath.IsAbs(dockerfile) {
+		return fmt.Errorf("%s: dockerfile %q must be relative to the context", prefix, dockerfile)
+	}
+
+	if _, err := os.Stat(filepath.Join(b.Context, dockerfile)); err != nil {
+		return fmt.Errorf("%s: dockerfile %q: %w", prefix, dockerfile, err)
+	}
+
+	return nil
+}
+
+// DirectoryOverrides holds directory paths set on the command line. Empty
+// fields leave the configured value unchanged.
+type DirectoryOverrides struct {
+	ResultsDir  string
+	TmpDataDir  string
+	TmpCacheDir string
+}
+
+// ApplyDirectoryOverrides replaces the configured results and temporary
+// directories with the non-empty overrides. It is applied after Load, so
+// the overrides take precedence over config files and environment variables.
+func (c *Config) ApplyDirectoryOverrides(o DirectoryOverrides) {
+	if o.ResultsDir != "" {
+		c.Runner.Benchmark.ResultsDir = o.ResultsDir
+	}
+
+	if o.TmpDataDir != "" {
+		c.Runner.Directories.TmpDataDir = o.TmpDataDir
+	}
+
+	if o.TmpCacheDir != "" {
+		c.Runner.Directories.TmpCacheDir = o.TmpCacheDir
+	}
 }
 
 // expandEnvWithDefaults is a mapping function for os.Expand that supports
@@ -656,6 +1313,7 @@ func expandEnvWithDefaults(s string) string {
 }
 
 // Load reads and parses configuration files from the given paths.
+// A path may also be an http(s):// URL, in which case the config is downloaded.
 // When multiple paths are provided, configs are merged in order (later values override earlier).
 // Environment variables can be substituted in config values using ${VAR}, $VAR, or
 // ${VAR:-default} syntax (the default is used when VAR is unset or empty).
@@ -681,12 +1339,16 @@ func Load(paths ...string) (*Config, error) {
 	rawYAMLs := make([]string, 0, len(paths))
 
 	for i, path := range paths {
-		content, err := os.ReadFile(path)
+		content, err := readConfigSource(path)
 		if err != nil {
-			return nil, fmt.Errorf("reading config file %q: %w", path, err)
+			return nil, err
+		}
+
+		expanded, err := resolveIncludes(os.Expand(string(content), expandEnvWithDefaults), path)
+		if err != nil {
+			return nil, err
 		}
 
-		expanded := os.Expand(string(content), expandEnvWithDefaults)
 		rawYAMLs = append(rawYAMLs, expanded)
 
 		if i == 0 {
@@ -717,17 +1379,82 @@ func Load(paths ...string) (*Config, error) {
 
 	restoreEnvironmentKeyCasing(&cfg, rawYAMLs)
 
+	if err := cfg.applyInstanceDefaults(); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
+	if err := cfg.expandMatrices(); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
 	cfg.applyDefaults()
 
 	return &cfg, nil
 }
 
-// bindEnvKeys explicitly binds configuration keys to environment variables.
-// This is required for Viper to recognize env vars for keys not present in the config file.
-func bindEnvKeys(v *viper.Viper) {
-	keys := []string{
-		// Global settings
-		"global.log_level",
+const (
+	// configFetchTimeout bounds how long downloading a remote config may take.
+	configFetchTimeout = 30 * time.Second
+
+	// maxRemoteConfigSize caps the size of a downloaded config.
+	maxRemoteConfigSize = 10 * 1024 * 1024
+)
+
+// isConfigURL reports whether a config path refers to a remote config.
+func isConfigURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// readConfigSource returns the raw content of a config file or URL. Remote
+// configs must parse as a YAML mapping, so that error pages served with a
+// 200 status are rejected with a clear message. Environment variables are
+// expanded by the caller, after the download.
+func readConfigSource(path string) ([]byte, error) {
+	if !isConfigURL(path) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file %q: %w", path, err)
+		}
+
+		return content, nil
+	}
+
+	client := &http.Client{Timeout: configFetchTimeout}
+
+	resp, err := client.Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("fetching config %q: %w", path, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching config %q: unexpected status code: %d", path, resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading config %q: %w", path, err)
+	}
+
+	if len(content) > maxRemoteConfigSize {
+		return nil, fmt.Errorf("config %q exceeds %d bytes", path, maxRemoteConfigSize)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("config %q is not a valid YAML document: %w", path, err)
+	}
+
+	return content, nil
+}
+
+// bindEnvKeys explicitly binds configuration keys to environment variables.
+// This is required for Viper to recognize env vars for keys not present in the config file.
+func bindEnvKeys(v *viper.Viper) {
+	keys := []string{
+		// Global settings
+		"global.log_level",
 		// Runner settings
 		"runner.container_runtime",
 		"runner.client_logs_to_stdout",
@@ -738,10 +1465,24 @@ func bindEnvKeys(v *viper.Viper) {
 		"runner.directories.tmp_cachedir",
 		"runner.github_token",
 		"runner.drop_caches_path",
+		"runner.drop_caches_command",
+		"runner.post_run_command",
+		"runner.post_run_command_required",
+		"runner.redact_env",
 		"runner.cpu_sysfs_path",
+		"runner.thp_sysfs_path",
+		"runner.fail_on_host_swap",
+		"runner.skip_blkio_device_check",
+		"runner.disk_benchmark.enabled",
+		"runner.disk_benchmark.tool",
+		"runner.disk_benchmark.size",
+		"runner.disk_benchmark.duration",
 		// Runner benchmark settings
 		"runner.benchmark.results_dir",
 		"runner.benchmark.results_owner",
+		"runner.benchmark.results_mode",
+		"runner.benchmark.results_dir_template",
+		"runner.benchmark.results_layout",
 		"runner.benchmark.skip_test_run",
 		"runner.benchmark.system_resource_collection_enabled",
 		"runner.benchmark.generate_results_index",
@@ -752,16 +1493,25 @@ func bindEnvKeys(v *viper.Viper) {
 		// Runner client settings
 		"runner.client.config.jwt",
 		"runner.client.config.drop_memory_caches",
+		"runner.client.config.prime_page_cache",
+		"runner.client.config.sync_between_tests",
 		"runner.client.config.rollback_strategy",
 		"runner.client.config.wait_after_rpc_ready",
 		"runner.client.config.run_timeout",
+		"runner.client.config.pre_run_command",
 		// Runner client resource limits
 		"runner.client.config.resource_limits.cpuset_count",
+		"runner.client.config.resource_limits.cpus",
+		"runner.client.config.resource_limits.cpu_quota",
+		"runner.client.config.resource_limits.cpu_period",
 		"runner.client.config.resource_limits.memory",
+		"runner.client.config.resource_limits.memory_reservation",
 		"runner.client.config.resource_limits.swap_disabled",
 		"runner.client.config.resource_limits.cpu_freq",
 		"runner.client.config.resource_limits.cpu_turboboost",
 		"runner.client.config.resource_limits.cpu_freq_governor",
+		"runner.client.config.resource_limits.transparent_hugepage",
+		"runner.client.config.resource_limits.irq_affinity",
 		// Runner client retry new payloads syncing state
 		"runner.client.config.retry_new_payloads_syncing_state.enabled",
 		"runner.client.config.retry_new_payloads_syncing_state.max_retries",
@@ -794,6 +1544,9 @@ func bindEnvKeys(v *viper.Viper) {
 		"api.storage.s3.secret_access_key",
 		"api.storage.s3.force_path_style",
 		"api.storage.s3.presigned_urls.expiry",
+		// API remote run settings
+		"api.runs.enabled",
+		"api.runs.work_dir",
 	}
 
 	for _, key := range keys {
@@ -828,6 +1581,36 @@ func (c *Config) applyDefaults() {
 		c.Runner.Client.Config.Genesis = make(map[string]string, 6)
 	}
 
+	if c.Runner.DownloadRetries == nil {
+		c.Runner.DownloadRetries = &DownloadRetryConfig{}
+	}
+
+	if c.Runner.DownloadRetries.MaxAttempts == 0 {
+		c.Runner.DownloadRetries.MaxAttempts = DefaultDownloadMaxAttempts
+	}
+
+	if c.Runner.DownloadRetries.Backoff == "" {
+		c.Runner.DownloadRetries.Backoff = DefaultDownloadBackoff
+	}
+
+	if c.Runner.DownloadRetries.MaxBackoff == "" {
+		c.Runner.DownloadRetries.MaxBackoff = DefaultDownloadMaxBackoff
+	}
+
+	if c.Runner.DiskBenchmark != nil {
+		if c.Runner.DiskBenchmark.Tool == "" {
+			c.Runner.DiskBenchmark.Tool = diskbench.ToolAuto
+		}
+
+		if c.Runner.DiskBenchmark.Size == "" {
+			c.Runner.DiskBenchmark.Size = DefaultDiskBenchmarkSize
+		}
+
+		if c.Runner.DiskBenchmark.Duration == "" {
+			c.Runner.DiskBenchmark.Duration = DefaultDiskBenchmarkDuration
+		}
+	}
+
 	if c.Runner.Benchmark.ResultsUpload != nil &&
 		c.Runner.Benchmark.ResultsUpload.S3 != nil &&
 		c.Runner.Benchmark.ResultsUpload.S3.ParallelUploads == 0 {
@@ -851,6 +1634,59 @@ func (c *Config) applyDefaults() {
 			c.API.Server.Listen = ":9090"
 		}
 
+		// Apply CORS defaults, honoring the deprecated cors_origins list.
+		cors := &c.API.Server.CORS
+		if len(cors.AllowedOrigins) == 0 {
+			cors.AllowedOrigins = c.API.Server.CORSOrigins
+		}
+
+		if len(cors.AllowedMethods) == 0 {
+			cors.AllowedMethods = []string{
+				"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS",
+			}
+		}
+
+		if len(cors.AllowedHeaders) == 0 {
+			cors.AllowedHeaders = []string{
+				"Content-Type", "Authorization", "Prefer",
+			}
+		}
+
+		if cors.AllowCredentials == nil {
+			allow := true
+			cors.AllowCredentials = &allow
+		}
+
+		if cors.MaxAge == 0 {
+			cors.MaxAge = 300
+		}
+
+		compression := &c.API.Server.Compression
+		if compression.Enabled == nil {
+			enabled := true
+			compression.Enabled = &enabled
+		}
+
+		if compression.Level == 0 {
+			compression.Level = 5
+		}
+
+		if compression.MinSize == 0 {
+			compression.MinSize = 1024
+		}
+
+		if len(compression.ContentTypes) == 0 {
+			compression.ContentTypes = []string{
+				"application/json",
+				"application/javascript",
+				"text/html",
+				"text/css",
+				"text/plain",
+				"text/csv",
+				"image/svg+xml",
+			}
+		}
+
 		if c.API.Auth.SessionTTL == "" {
 			c.API.Auth.SessionTTL = "24h"
 		}
@@ -884,6 +1720,21 @@ func (c *Config) applyDefaults() {
 			}
 		}
 
+		// Apply remote run defaults.
+		if c.API.Runs != nil && c.API.Runs.Enabled {
+			if c.API.Runs.WorkDir == "" {
+				c.API.Runs.WorkDir = DefaultAPIRunsWorkDir
+			}
+
+			if c.API.Runs.Concurrency == 0 {
+				c.API.Runs.Concurrency = 1
+			}
+
+			if c.API.Runs.QueueSize == 0 {
+				c.API.Runs.QueueSize = 100
+			}
+		}
+
 		if c.API.Server.RateLimit.Enabled {
 			if c.API.Server.RateLimit.Auth.RequestsPerMinute == 0 {
 				c.API.Server.RateLimit.Auth.RequestsPerMinute = 10
@@ -937,79 +1788,229 @@ func (o ValidateOpts) isInstanceActive(id string) bool {
 	return ok
 }
 
-// Validate checks the configuration for errors.
+// ValidationError is a single configuration problem found by ValidateAll.
+type ValidationError struct {
+	// Field is the path of the offending config field, e.g.
+	// runner.instances[0].datadir. Checks spanning the global and instance
+	// level use the option name, e.g. rollback_strategy.
+	Field   string `json:"field"`
+	Message string `json:"message"`
+
+	err error
+}
+
+// Error returns the message of the validation error.
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying error.
+func (e ValidationError) Unwrap() error {
+	return e.err
+}
+
+// validationErrors accumulates the errors found while validating.
+type validationErrors []ValidationError
+
+// add records err for field, if err is not nil.
+func (v *validationErrors) add(field string, err error) {
+	if err == nil {
+		return
+	}
+
+	*v = append(*v, ValidationError{Field: field, Message: err.Error(), err: err})
+}
+
+// ValidationWarning is a configuration choice that is valid but likely to
+// skew benchmark results.
+type ValidationWarning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationResult holds every error and warning found by ValidateAll.
+type ValidationResult struct {
+	Errors   []ValidationError   `json:"errors"`
+	Warnings []ValidationWarning `json:"warnings"`
+}
+
+// Err returns the first validation error, or nil if the config is valid.
+func (r *ValidationResult) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+
+	return r.Errors[0].err
+}
+
+// Validate checks the configuration for errors and returns the first one.
 // When opts is provided, datadir validation is scoped to active instances/clients.
 func (c *Config) Validate(opts ...ValidateOpts) error {
+	return c.ValidateAll(opts...).Err()
+}
+
+// ValidateAll checks the configuration and returns every error found, in
+// the order Validate would report them, along with warnings about risky
+// but valid settings.
+func (c *Config) ValidateAll(opts ...ValidateOpts) *ValidationResult {
 	var opt ValidateOpts
 	if len(opts) > 0 {
 		opt = opts[0]
 	}
 
+	return &ValidationResult{
+		Errors:   c.collectErrors(opt),
+		Warnings: c.collectWarnings(opt),
+	}
+}
+
+// collectErrors returns every error in the configuration.
+//
+//nolint:gocognit,cyclop // Validation covers the whole config.
+func (c *Config) collectErrors(opt ValidateOpts) []ValidationError {
+	var errs validationErrors
+
 	if len(c.Runner.Instances) == 0 {
-		return fmt.Errorf("at least one client instance must be configured")
+		errs.add("runner.instances", fmt.Errorf("at least one client instance must be configured"))
+	}
+
+	if c.Global.Tracing != nil && c.Global.Tracing.Endpoint == "" {
+		errs.add("global.tracing.endpoint", fmt.Errorf("global.tracing.endpoint is required when tracing is configured"))
 	}
 
 	seenIDs := make(map[string]struct{}, len(c.Runner.Instances))
 
 	for i, instance := range c.Runner.Instances {
-		if instance.ID == "" {
-			return fmt.Errorf("instance %d: id is required", i)
-		}
+		field := fmt.Sprintf("runner.instances[%d]", i)
 
-		if _, exists := seenIDs[instance.ID]; exists {
-			return fmt.Errorf("instance %d: duplicate id %q", i, instance.ID)
+		if instance.ID == "" {
+			errs.add(field+".id", fmt.Errorf("instance %d: id is required", i))
+		} else if _, exists := seenIDs[instance.ID]; exists {
+			errs.add(field+".id", fmt.Errorf("instance %d: duplicate id %q", i, instance.ID))
 		}
 
 		seenIDs[instance.ID] = struct{}{}
 
 		if instance.Client == "" {
-			return fmt.Errorf("instance %q: client type is required", instance.ID)
+			errs.add(field+".client", fmt.Errorf("instance %q: client type is required", instance.ID))
+		} else if !isValidClient(instance.Client) {
+			errs.add(field+".client", fmt.Errorf("instance %q: unknown client type %q", instance.ID, instance.Client))
+		}
+
+		// Validate instance-level datadir (skip if not in active set).
+		if instance.DataDir != nil && opt.isInstanceActive(instance.ID) {
+			errs.add(field+".datadir", instance.DataDir.Validate(fmt.Sprintf("instance %q datadir", instance.ID)))
 		}
 
-		if !isValidClient(instance.Client) {
-			return fmt.Errorf("instance %q: unknown client type %q", instance.ID, instance.Client)
+		errs.add(field+".genesis_mirrors", validateMirrorURLs(
+			instance.GenesisMirrors, fmt.Sprintf("instance %q genesis_mirrors", instance.ID),
+		))
+
+		if instance.ImageDigest != "" && !isValidImageDigest(instance.ImageDigest) {
+			errs.add(field+".image_digest", fmt.Errorf(
+				"instance %q: image_digest %q must be in the form sha256:<64 hex characters>",
+				instance.ID, instance.ImageDigest,
+			))
 		}
 
-		// Validate instance-level datadir (skip if not in active set).
-		if instance.DataDir != nil {
-			if len(opt.ActiveInstanceIDs) == 0 {
-				if err := instance.DataDir.Validate(fmt.Sprintf("instance %q datadir", instance.ID)); err != nil {
-					return err
-				}
-			} else if _, ok := opt.ActiveInstanceIDs[instance.ID]; ok {
-				if err := instance.DataDir.Validate(fmt.Sprintf("instance %q datadir", instance.ID)); err != nil {
-					return err
-				}
+		// Validate device passthrough (skip if not in active set, the host
+		// devices may only exist on the machine running that instance).
+		if opt.isInstanceActive(instance.ID) {
+			for j := range instance.Devices {
+				errs.add(fmt.Sprintf("%s.devices[%d]", field, j), instance.Devices[j].Validate(
+					fmt.Sprintf("instance %q devices[%d]", instance.ID, j),
+				))
 			}
 		}
 
+		// Secret sources are likewise only checked for active instances.
+		secretPaths := make(map[string]bool, len(instance.SecretFiles))
+
+		for j := range instance.SecretFiles {
+			secretField := fmt.Sprintf("%s.secret_files[%d]", field, j)
+			secret := &instance.SecretFiles[j]
+
+			if secretPaths[secret.ContainerPath] {
+				errs.add(secretField, fmt.Errorf(
+					"instance %q: duplicate secret container_path %q", instance.ID, secret.ContainerPath,
+				))
+			}
+
+			secretPaths[secret.ContainerPath] = true
+
+			if opt.isInstanceActive(instance.ID) {
+				errs.add(secretField, secret.Validate(
+					fmt.Sprintf("instance %q secret_files[%d]", instance.ID, j),
+				))
+			}
+		}
+
+		if _, err := ParseRestartPolicy(instance.Restart); err != nil {
+			errs.add(field+".restart", fmt.Errorf("instance %q: %w", instance.ID, err))
+		}
+
+		if instance.Build != nil {
+			if instance.ImageDigest != "" {
+				errs.add(field+".build", fmt.Errorf(
+					"instance %q: image_digest cannot be combined with build", instance.ID,
+				))
+			}
+
+			// Skip the context check if not in active set, the build
+			// context may only exist on the machine running that instance.
+			if opt.isInstanceActive(instance.ID) {
+				errs.add(field+".build", instance.Build.Validate(
+					fmt.Sprintf("instance %q build", instance.ID),
+				))
+			}
+		}
+
+		for j, arg := range instance.RemoveDefaultArgs {
+			if !strings.HasPrefix(arg, "-") {
+				errs.add(fmt.Sprintf("%s.remove_default_args[%d]", field, j), fmt.Errorf(
+					"instance %q: remove_default_args entry %q must be a flag prefix starting with '-'",
+					instance.ID, arg,
+				))
+			}
+		}
+
+		if instance.ClientCommit != "" && !isValidCommit(instance.ClientCommit) {
+			errs.add(field+".client_commit", fmt.Errorf(
+				"instance %q: client_commit %q must be a git commit hash (7 to 64 hex characters)",
+				instance.ID, instance.ClientCommit,
+			))
+		}
+
 		// Validate instance-level resource limits.
 		if instance.ResourceLimits != nil {
-			if err := instance.ResourceLimits.Validate(fmt.Sprintf("instance %q resource_limits", instance.ID)); err != nil {
-				return err
-			}
+			errs.add(field+".resource_limits", instance.ResourceLimits.Validate(
+				fmt.Sprintf("instance %q resource_limits", instance.ID),
+			))
 		}
 	}
 
+	for _, client := range slices.Sorted(maps.Keys(c.Runner.Client.Config.GenesisMirrors)) {
+		errs.add("runner.client.config.genesis_mirrors."+client, validateMirrorURLs(
+			c.Runner.Client.Config.GenesisMirrors[client],
+			fmt.Sprintf("runner.client.config.genesis_mirrors.%s", client),
+		))
+	}
+
 	// Validate global resource limits.
 	if c.Runner.Client.Config.ResourceLimits != nil {
-		if err := c.Runner.Client.Config.ResourceLimits.Validate("runner.client.config.resource_limits"); err != nil {
-			return err
-		}
+		errs.add("runner.client.config.resource_limits",
+			c.Runner.Client.Config.ResourceLimits.Validate("runner.client.config.resource_limits"))
 	}
 
 	// Validate global datadirs (skip if client not in active set).
-	for client, dd := range c.Runner.Client.DataDirs {
-		if dd != nil {
-			if len(opt.ActiveClients) == 0 {
-				if err := dd.Validate(fmt.Sprintf("client.datadirs.%s", client)); err != nil {
-					return err
-				}
-			} else if _, ok := opt.ActiveClients[client]; ok {
-				if err := dd.Validate(fmt.Sprintf("client.datadirs.%s", client)); err != nil {
-					return err
-				}
-			}
+	for _, client := range slices.Sorted(maps.Keys(c.Runner.Client.DataDirs)) {
+		dd := c.Runner.Client.DataDirs[client]
+		if dd == nil {
+			continue
+		}
+
+		if _, ok := opt.ActiveClients[client]; ok || len(opt.ActiveClients) == 0 {
+			errs.add("runner.client.datadirs."+client, dd.Validate(fmt.Sprintf("client.datadirs.%s", client)))
 		}
 	}
 
@@ -1017,77 +2018,111 @@ func (c *Config) Validate(opts ...ValidateOpts) error {
 		dir := filepath.Dir(c.Runner.Benchmark.ResultsDir)
 		if dir != "." && dir != ".." {
 			if _, err := os.Stat(dir); os.IsNotExist(err) {
-				return fmt.Errorf("results directory parent %q does not exist", dir)
+				errs.add("runner.benchmark.results_dir", fmt.Errorf("results directory parent %q does not exist", dir))
 			}
 		}
 	}
 
 	// Validate test source configuration.
 	if err := c.Runner.Benchmark.Tests.Source.Validate(); err != nil {
-		return fmt.Errorf("tests config: %w", err)
-	}
-
-	// Validate container_runtime setting.
-	if err := c.validateContainerRuntime(); err != nil {
-		return err
-	}
-
-	// Validate rollback_strategy settings.
-	if err := c.validateRollbackStrategy(opt); err != nil {
-		return err
-	}
-
-	// Validate drop_memory_caches settings.
-	if err := c.validateDropMemoryCaches(); err != nil {
-		return err
-	}
-
-	// Validate cpu_freq settings.
-	if err := c.validateCPUFreq(); err != nil {
-		return err
-	}
-
-	// Validate retry_new_payloads_syncing_state settings.
-	if err := c.validateRetryNewPayloadsSyncingState(); err != nil {
-		return err
-	}
-
-	// Validate wait_after_rpc_ready settings.
-	if err := c.validateWaitAfterRPCReady(); err != nil {
-		return err
-	}
+		errs.add("runner.benchmark.tests.source", fmt.Errorf("tests config: %w", err))
+	}
+
+	if n := c.Runner.Benchmark.Tests.MaxConsecutiveUnauthorized; n != nil && *n < 0 {
+		errs.add("runner.benchmark.tests.max_consecutive_unauthorized", fmt.Errorf(
+			"runner.benchmark.tests.max_consecutive_unauthorized must be >= 0, got %d", *n,
+		))
+	}
+
+	if err := c.validateGenesisGroupParallelism(); err != nil {
+		errs.add("runner.benchmark.tests.genesis_group_parallelism", err)
+	}
+
+	// Validate settings resolved from the global and instance level.
+	for _, check := range []struct {
+		field    string
+		validate func() error
+	}{
+		{"runner.container_runtime", c.validateContainerRuntime},
+		{"rollback_strategy", func() error { return c.validateRollbackStrategy(opt) }},
+		{"drop_memory_caches", c.validateDropMemoryCaches},
+		{"pre_run_command", c.validatePreRunCommand},
+		{"runner.post_run_command", c.validatePostRunCommand},
+		{"runner.redact_env", c.validateRedactEnv},
+		{"resource_limits.cpu_freq", c.validateCPUFreq},
+		{"resource_limits.transparent_hugepage", c.validateTransparentHugepage},
+		{"resource_limits.blkio_config", c.validateBlkioAutoDevice},
+		{"resource_limits.blkio_config", func() error { return c.validateBlkioDevices(opt) }},
+		{"retry_new_payloads_syncing_state", c.validateRetryNewPayloadsSyncingState},
+		{"wait_after_rpc_ready", c.validateWaitAfterRPCReady},
+		{"post_test_sleep_duration", c.validatePostTestSleepDuration},
+		{"run_timeout", c.validateRunTimeout},
+		{"runner.download_retries", c.validateDownloadRetries},
+		{"runner.disk_benchmark", c.validateDiskBenchmark},
+		{"fcu_keepalive_interval", c.validateFCUKeepaliveInterval},
+		{"post_test_rpc_calls", c.validatePostTestRPCCalls},
+		{"bootstrap_fcu", c.validateBootstrapFCU},
+		{"runner.benchmark.results_owner", c.validateResultsOwner},
+		{"runner.benchmark.results_mode", c.validateResultsMode},
+		{"runner.benchmark.results_dir_template", c.validateResultsDirTemplate},
+		{"runner.benchmark.results_layout", c.validateResultsLayout},
+		{"runner.benchmark.results_upload", c.validateResultsUpload},
+		{"api", c.ValidateAPI},
+	} {
+		errs.add(check.field, check.validate())
+	}
+
+	return errs
+}
 
-	// Validate post_test_sleep_duration settings.
-	if err := c.validatePostTestSleepDuration(); err != nil {
-		return err
-	}
+// collectWarnings returns warnings about settings of active instances
+// that are valid but likely to skew benchmark results.
+func (c *Config) collectWarnings(opt ValidateOpts) []ValidationWarning {
+	var warnings []ValidationWarning
 
-	// Validate run_timeout settings.
-	if err := c.validateRunTimeout(); err != nil {
-		return err
-	}
+	for i, instance := range c.Runner.Instances {
+		if !opt.isInstanceActive(instance.ID) {
+			continue
+		}
 
-	// Validate post_test_rpc_calls settings.
-	if err := c.validatePostTestRPCCalls(); err != nil {
-		return err
-	}
+		field := fmt.Sprintf("runner.instances[%d]", i)
+		limits := c.GetResourceLimits(&instance)
 
-	// Validate bootstrap_fcu settings.
-	if err := c.validateBootstrapFCU(); err != nil {
-		return err
-	}
+		if limits == nil {
+			warnings = append(warnings, ValidationWarning{
+				Field: field + ".resource_limits",
+				Message: fmt.Sprintf("instance %q: no resource_limits configured, "+
+					"results depend on the host's other load", instance.ID),
+			})
+		} else if limits.CPUGovernor == "powersave" {
+			warnings = append(warnings, ValidationWarning{
+				Field: field + ".resource_limits.cpu_freq_governor",
+				Message: fmt.Sprintf("instance %q: cpu_freq_governor \"powersave\" "+
+					"keeps CPUs at low frequencies and inflates timings", instance.ID),
+			})
+		}
 
-	// Validate results_upload settings.
-	if err := c.validateResultsUpload(); err != nil {
-		return err
-	}
+		if c.Runner.Benchmark.Tests.GenesisGroupParallelism > 1 {
+			warnings = append(warnings, ValidationWarning{
+				Field: "runner.benchmark.tests.genesis_group_parallelism",
+				Message: fmt.Sprintf("instance %q: genesis groups running in parallel "+
+					"share the host's CPUs, memory and disk, which skews their timings", instance.ID),
+			})
+		}
 
-	// Validate API settings.
-	if err := c.ValidateAPI(); err != nil {
-		return err
+		// Disk reads of the datadir are served from memory once cached.
+		dropCaches := c.GetDropMemoryCaches(&instance)
+		if c.resolveDataDir(&instance) != nil && (dropCaches == "" || dropCaches == "disabled") &&
+			!c.GetPrimePageCache(&instance) {
+			warnings = append(warnings, ValidationWarning{
+				Field: field + ".drop_memory_caches",
+				Message: fmt.Sprintf("instance %q: drop_memory_caches is disabled, "+
+					"so datadir reads may be served from the page cache instead of disk", instance.ID),
+			})
+		}
 	}
 
-	return nil
+	return warnings
 }
 
 // Validate checks the source configuration for errors.
@@ -1120,12 +2155,28 @@ func (s *SourceConfig) Validate() error {
 	}
 
 	if s.Git != nil {
-		if s.Git.Repo == "" {
-			return fmt.Errorf("git.repo is required")
+		if err := s.Git.validate("git"); err != nil {
+			return err
 		}
 
-		if s.Git.Version == "" {
-			return fmt.Errorf("git.version is required")
+		if pre := s.Git.PreRunStepsSource; pre != nil {
+			const field = "git.pre_run_steps_source"
+
+			if err := pre.validate(field); err != nil {
+				return err
+			}
+
+			if len(pre.PreRunSteps) == 0 {
+				return fmt.Errorf("%s.pre_run_steps is required", field)
+			}
+
+			if pre.Steps != nil {
+				return fmt.Errorf("%s.steps is not supported", field)
+			}
+
+			if pre.PreRunStepsSource != nil {
+				return fmt.Errorf("%s.pre_run_steps_source is not supported", field)
+			}
 		}
 	}
 
@@ -1166,10 +2217,11 @@ var validClients = map[string]struct{}{
 
 // validDropMemoryCachesValues contains valid values for drop_memory_caches.
 var validDropMemoryCachesValues = map[string]bool{
-	"":         true, // Unset (inherits or disabled)
-	"disabled": true, // Explicitly disabled (default)
-	"tests":    true, // Between tests
-	"steps":    true, // Between all steps
+	"":          true, // Unset (inherits or disabled)
+	"disabled":  true, // Explicitly disabled (default)
+	"tests":     true, // Between tests
+	"steps":     true, // Between all steps
+	"test-only": true, // Only before each test step
 }
 
 // isValidClient checks if the given client type is supported.
@@ -1179,6 +2231,88 @@ func isValidClient(client string) bool {
 	return ok
 }
 
+// isValidImageDigest reports whether digest is a "sha256:" prefixed
+// image digest with 64 hex characters.
+func isValidImageDigest(digest string) bool {
+	hash, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok || len(hash) != 64 {
+		return false
+	}
+
+	for _, ch := range hash {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", ch) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RestartPolicy is a parsed container restart policy.
+type RestartPolicy struct {
+	Name       string // "no", "always", "unless-stopped" or "on-failure".
+	MaxRetries int    // Only set for "on-failure".
+}
+
+// String returns the policy in Docker's --restart flag format.
+func (p RestartPolicy) String() string {
+	if p.MaxRetries > 0 {
+		return fmt.Sprintf("%s:%d", p.Name, p.MaxRetries)
+	}
+
+	return p.Name
+}
+
+// ParseRestartPolicy parses a restart policy in Docker's --restart flag
+// format ("no", "always", "unless-stopped", "on-failure[:max-retries]").
+// An empty value and "never" mean "no".
+func ParseRestartPolicy(s string) (RestartPolicy, error) {
+	name, retries, hasRetries := strings.Cut(strings.TrimSpace(s), ":")
+
+	switch name {
+	case "", "never":
+		name = DefaultRestartPolicy
+	case "no", "always", "unless-stopped", "on-failure":
+	default:
+		return RestartPolicy{}, fmt.Errorf(
+			"invalid restart policy %q, must be: no, always, unless-stopped, on-failure[:max-retries]", s,
+		)
+	}
+
+	policy := RestartPolicy{Name: name}
+
+	if hasRetries {
+		if name != "on-failure" {
+			return RestartPolicy{}, fmt.Errorf("restart policy %q: max retries are only allowed with on-failure", s)
+		}
+
+		n, err := strconv.Atoi(retries)
+		if err != nil || n < 0 {
+			return RestartPolicy{}, fmt.Errorf("restart policy %q: max retries must be a non-negative integer", s)
+		}
+
+		policy.MaxRetries = n
+	}
+
+	return policy, nil
+}
+
+// isValidCommit reports whether commit is a full or abbreviated git commit
+// hash: 7 to 64 hex characters.
+func isValidCommit(commit string) bool {
+	if len(commit) < 7 || len(commit) > 64 {
+		return false
+	}
+
+	for _, ch := range commit {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", ch) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetGenesisURL returns the genesis URL for a client instance.
 func (c *Config) GetGenesisURL(instance *ClientInstance) string {
 	if instance.Genesis != "" {
@@ -1188,6 +2322,22 @@ func (c *Config) GetGenesisURL(instance *ClientInstance) string {
 	return c.Runner.Client.Config.Genesis[instance.Client]
 }
 
+// GetGenesisMirrors returns the mirror URLs to try, in order, when the
+// genesis URL of an instance cannot be downloaded. Instance-level mirrors
+// take precedence. Global mirrors only back the global genesis URL, so they
+// are not used when the instance overrides genesis.
+func (c *Config) GetGenesisMirrors(instance *ClientInstance) []string {
+	if len(instance.GenesisMirrors) > 0 {
+		return instance.GenesisMirrors
+	}
+
+	if instance.Genesis != "" {
+		return nil
+	}
+
+	return c.Runner.Client.Config.GenesisMirrors[instance.Client]
+}
+
 // GetDropMemoryCaches returns the drop_memory_caches setting for an instance.
 // Instance-level setting takes precedence over global default.
 // Returns empty string if neither is set (disabled).
@@ -1274,6 +2424,16 @@ func (c *Config) GetCPUSysfsPath() string {
 	return DefaultCPUSysfsPath
 }
 
+// GetTHPSysfsPath returns the sysfs base path for transparent huge pages.
+// Returns the configured path or the default (/sys/kernel/mm/transparent_hugepage).
+func (c *Config) GetTHPSysfsPath() string {
+	if c.Runner.THPSysfsPath != "" {
+		return c.Runner.THPSysfsPath
+	}
+
+	return DefaultTHPSysfsPath
+}
+
 // GetResourceLimits returns the resource limits for an instance.
 // Instance-level limits take precedence over global defaults.
 // Returns nil if no limits are configured.
@@ -1344,6 +2504,69 @@ func (c *Config) GetPostTestSleepDuration(instance *ClientInstance) time.Duratio
 	return d
 }
 
+// GetPreRunCommand returns the command run before an instance's container
+// is created. Instance-level config takes precedence over global defaults.
+func (c *Config) GetPreRunCommand(instance *ClientInstance) []string {
+	if len(instance.PreRunCommand) > 0 {
+		return instance.PreRunCommand
+	}
+
+	return c.Runner.Client.Config.PreRunCommand
+}
+
+// GetFCUKeepaliveInterval returns how often an engine_forkchoiceUpdated
+// keepalive is sent to an idle client. Instance-level config takes
+// precedence over global defaults. Returns 0 (disabled) if not set.
+func (c *Config) GetFCUKeepaliveInterval(instance *ClientInstance) time.Duration {
+	s := instance.FCUKeepaliveInterval
+	if s == "" {
+		s = c.Runner.Client.Config.FCUKeepaliveInterval
+	}
+
+	if s == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0
+	}
+
+	return d
+}
+
+// GetSyncBetweenTests returns whether pending writes are flushed to disk
+// between tests. Instance-level config takes precedence over global defaults.
+func (c *Config) GetSyncBetweenTests(instance *ClientInstance) bool {
+	if instance.SyncBetweenTests != nil {
+		return *instance.SyncBetweenTests
+	}
+
+	return c.Runner.Client.Config.SyncBetweenTests != nil && *c.Runner.Client.Config.SyncBetweenTests
+}
+
+// GetPrimePageCache returns whether the datadir is read into the page cache
+// before the first test. Instance-level config takes precedence over global
+// defaults.
+func (c *Config) GetPrimePageCache(instance *ClientInstance) bool {
+	if instance.PrimePageCache != nil {
+		return *instance.PrimePageCache
+	}
+
+	return c.Runner.Client.Config.PrimePageCache != nil && *c.Runner.Client.Config.PrimePageCache
+}
+
+// GetIsolateNetwork returns the isolate_network setting for an instance.
+// Instance-level config takes precedence over global defaults. Returns nil
+// if not set, in which case the runner isolates clients that support it.
+func (c *Config) GetIsolateNetwork(instance *ClientInstance) *bool {
+	if instance.IsolateNetwork != nil {
+		return instance.IsolateNetwork
+	}
+
+	return c.Runner.Client.Config.IsolateNetwork
+}
+
 // GetRunnerRunTimeout returns the global runner-level timeout that caps
 // the entire run (all instances, setup, and teardown). Returns 0 if not set.
 func (c *Config) GetRunnerRunTimeout() time.Duration {
@@ -1359,6 +2582,22 @@ func (c *Config) GetRunnerRunTimeout() time.Duration {
 	return d
 }
 
+// GetDownloadRetries returns the download retry settings. Missing fields
+// are filled with the DefaultDownload* values by applyDefaults; a config
+// that never went through it (nil block) attempts every URL once.
+func (c *Config) GetDownloadRetries() (maxAttempts int, backoff, maxBackoff time.Duration) {
+	cfg := c.Runner.DownloadRetries
+	if cfg == nil {
+		return 1, 0, 0
+	}
+
+	// Durations are checked by Validate; unparsable values fall back to 0.
+	backoff, _ = time.ParseDuration(cfg.Backoff)
+	maxBackoff, _ = time.ParseDuration(cfg.MaxBackoff)
+
+	return max(cfg.MaxAttempts, 1), backoff, maxBackoff
+}
+
 // GetRunTimeout returns the maximum duration for test execution.
 // Instance-level config takes precedence over global defaults. Returns 0 if not set.
 func (c *Config) GetRunTimeout(instance *ClientInstance) time.Duration {
@@ -1525,6 +2764,16 @@ func ParseByteSize(s string) (uint64, error) {
 
 // validateDropMemoryCaches validates drop_memory_caches settings and checks permissions.
 func (c *Config) validateDropMemoryCaches() error {
+	if len(c.Runner.DropCachesCommand) > 0 {
+		if c.Runner.DropCachesPath != "" {
+			return fmt.Errorf("drop_caches_path and drop_caches_command are mutually exclusive")
+		}
+
+		if c.Runner.DropCachesCommand[0] == "" {
+			return fmt.Errorf("drop_caches_command: executable must not be empty")
+		}
+	}
+
 	// Check all instances for valid values and if feature is enabled.
 	enabled := false
 
@@ -1532,11 +2781,18 @@ func (c *Config) validateDropMemoryCaches() error {
 		value := c.GetDropMemoryCaches(&instance)
 
 		if !validDropMemoryCachesValues[value] {
-			return fmt.Errorf("instance %q: invalid drop_memory_caches value %q (must be \"disabled\", \"tests\", or \"steps\")",
+			return fmt.Errorf("instance %q: invalid drop_memory_caches value %q (must be \"disabled\", \"tests\", \"steps\", or \"test-only\")",
 				instance.ID, value)
 		}
 
 		if value != "" && value != "disabled" {
+			if c.GetPrimePageCache(&instance) {
+				return fmt.Errorf(
+					"instance %q: prime_page_cache cannot be combined with drop_memory_caches %q",
+					instance.ID, value,
+				)
+			}
+
 			enabled = true
 		}
 	}
@@ -1545,6 +2801,16 @@ func (c *Config) validateDropMemoryCaches() error {
 		return nil
 	}
 
+	// A command drops caches with its own privileges, so only check that
+	// it can be found.
+	if len(c.Runner.DropCachesCommand) > 0 {
+		if _, err := exec.LookPath(c.Runner.DropCachesCommand[0]); err != nil {
+			return fmt.Errorf("drop_caches_command: %w", err)
+		}
+
+		return nil
+	}
+
 	dropCachesPath := c.GetDropCachesPath()
 
 	// Check OS - drop_memory_caches is Linux-only (skip if custom path is configured).
@@ -1556,7 +2822,10 @@ func (c *Config) validateDropMemoryCaches() error {
 	file, err := os.OpenFile(dropCachesPath, os.O_WRONLY, 0)
 	if err != nil {
 		if os.IsPermission(err) {
-			return fmt.Errorf("drop_memory_caches is enabled but no write permission to %s (requires root)", dropCachesPath)
+			return fmt.Errorf(
+				"drop_memory_caches is enabled but no write permission to %s (requires root, or set drop_caches_command)",
+				dropCachesPath,
+			)
 		}
 
 		return fmt.Errorf("drop_memory_caches: cannot access %s: %w", dropCachesPath, err)
@@ -1649,69 +2918,301 @@ func (c *Config) validateContainerRuntime() error {
 	return nil
 }
 
-// validateCPUFreq validates cpu_freq settings and checks system capabilities.
-func (c *Config) validateCPUFreq() error {
-	// Check all instances for CPU frequency settings.
+// validatePreRunCommand validates that the pre-run command of every instance
+// can be found.
+func (c *Config) validatePreRunCommand() error {
+	for _, instance := range c.Runner.Instances {
+		command := c.GetPreRunCommand(&instance)
+		if len(command) == 0 {
+			continue
+		}
+
+		if command[0] == "" {
+			return fmt.Errorf("instance %q: pre_run_command: executable must not be empty", instance.ID)
+		}
+
+		if _, err := exec.LookPath(command[0]); err != nil {
+			return fmt.Errorf("instance %q: pre_run_command: %w", instance.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// validatePostRunCommand validates that the post-run command can be found.
+func (c *Config) validatePostRunCommand() error {
+	if len(c.Runner.PostRunCommand) == 0 {
+		if c.Runner.PostRunCommandRequired {
+			return fmt.Errorf("post_run_command_required is set but post_run_command is empty")
+		}
+
+		return nil
+	}
+
+	if c.Runner.PostRunCommand[0] == "" {
+		return fmt.Errorf("executable must not be empty")
+	}
+
+	if _, err := exec.LookPath(c.Runner.PostRunCommand[0]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateResultsOwner validates that results_owner names existing users
+// and groups or numeric IDs.
+func (c *Config) validateResultsOwner() error {
+	_, err := fsutil.ParseOwner(c.Runner.Benchmark.ResultsOwner)
+
+	return err
+}
+
+// validateResultsMode validates that results_mode is an octal permission mode.
+func (c *Config) validateResultsMode() error {
+	_, err := fsutil.ParseMode(c.Runner.Benchmark.ResultsMode)
+
+	return err
+}
+
+// validateGenesisGroupParallelism validates genesis_group_parallelism. Host
+// settings applied per container lifecycle are restored when a group ends, so
+// they cannot be combined with groups running in parallel.
+func (c *Config) validateGenesisGroupParallelism() error {
+	parallelism := c.Runner.Benchmark.Tests.GenesisGroupParallelism
+	if parallelism < 0 {
+		return fmt.Errorf(
+			"runner.benchmark.tests.genesis_group_parallelism must be >= 0, got %d", parallelism,
+		)
+	}
+
+	if parallelism <= 1 {
+		return nil
+	}
+
+	for _, instance := range c.Runner.Instances {
+		limits := c.GetResourceLimits(&instance)
+		if limits == nil {
+			continue
+		}
+
+		if limits.CPUFreq != "" || limits.CPUTurboBoost != nil || limits.CPUGovernor != "" ||
+			limits.TransparentHugepage != "" || limits.IRQAffinity == IRQAffinityMove {
+			return fmt.Errorf(
+				"instance %q: genesis_group_parallelism > 1 cannot be combined with "+
+					"resource_limits cpu_freq, transparent_hugepage or irq_affinity: move settings",
+				instance.ID,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateCPUFreq validates cpu_freq settings and checks system capabilities.
+func (c *Config) validateCPUFreq() error {
+	// Check all instances for CPU frequency settings.
+	enabled := false
+
+	for _, instance := range c.Runner.Instances {
+		limits := c.GetResourceLimits(&instance)
+		if limits == nil {
+			continue
+		}
+
+		if limits.CPUFreq != "" || limits.CPUTurboBoost != nil || limits.CPUGovernor != "" {
+			enabled = true
+
+			break
+		}
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	// Check OS - CPU frequency control is Linux-only.
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("cpu_freq is only supported on Linux (current OS: %s)", runtime.GOOS)
+	}
+
+	sysfsPath := c.GetCPUSysfsPath()
+
+	// Check if cpufreq subsystem is available.
+	if !cpufreq.IsCPUFreqSupported(sysfsPath) {
+		return fmt.Errorf("cpu_freq: cpufreq subsystem not available (no scaling_governor in sysfs)")
+	}
+
+	// Check write access.
+	if err := cpufreq.HasWriteAccess(sysfsPath); err != nil {
+		return fmt.Errorf("cpu_freq: %w", err)
+	}
+
+	// Validate each instance's settings.
+	for _, instance := range c.Runner.Instances {
+		limits := c.GetResourceLimits(&instance)
+		if limits == nil {
+			continue
+		}
+
+		// Validate frequency format and bounds.
+		if limits.CPUFreq != "" && strings.ToUpper(limits.CPUFreq) != "MAX" {
+			freqKHz, err := cpufreq.ParseFrequency(limits.CPUFreq)
+			if err != nil {
+				return fmt.Errorf("instance %q: invalid cpu_freq %q: %w", instance.ID, limits.CPUFreq, err)
+			}
+
+			if err := cpufreq.ValidateFrequency(sysfsPath, freqKHz); err != nil {
+				return fmt.Errorf("instance %q: %w", instance.ID, err)
+			}
+		}
+
+		// Validate governor.
+		if limits.CPUGovernor != "" {
+			if err := cpufreq.ValidateGovernor(sysfsPath, limits.CPUGovernor); err != nil {
+				return fmt.Errorf("instance %q: %w", instance.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBlkioAutoDevice checks that instances throttling the "auto" blkio
+// device have a datadir to resolve it from.
+func (c *Config) validateBlkioAutoDevice() error {
+	for _, instance := range c.Runner.Instances {
+		limits := c.GetResourceLimits(&instance)
+		if limits == nil || !limits.BlkioConfig.UsesAutoDevice() {
+			continue
+		}
+
+		if c.resolveDataDir(&instance) == nil {
+			return fmt.Errorf(
+				"instance %q: blkio_config device path %q requires a datadir",
+				instance.ID, BlkioDeviceAuto,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateBlkioDevices checks that the blkio device paths of active
+// instances are block devices on this host, catching typos before a run.
+// The check is Linux-only and can be disabled with skip_blkio_device_check.
+func (c *Config) validateBlkioDevices(opt ValidateOpts) error {
+	if c.Runner.SkipBlkioDeviceCheck || runtime.GOOS != "linux" {
+		return nil
+	}
+
+	for _, instance := range c.Runner.Instances {
+		if !opt.isInstanceActive(instance.ID) {
+			continue
+		}
+
+		limits := c.GetResourceLimits(&instance)
+		if limits == nil || limits.BlkioConfig == nil {
+			continue
+		}
+
+		blkio := limits.BlkioConfig
+
+		for _, group := range []struct {
+			field   string
+			devices []ThrottleDevice
+		}{
+			{"device_read_bps", blkio.DeviceReadBps},
+			{"device_read_iops", blkio.DeviceReadIOps},
+			{"device_write_bps", blkio.DeviceWriteBps},
+			{"device_write_iops", blkio.DeviceWriteIOps},
+		} {
+			for i, dev := range group.devices {
+				if dev.Path == BlkioDeviceAuto {
+					continue
+				}
+
+				if err := validateBlockDevice(dev.Path); err != nil {
+					return fmt.Errorf("instance %q: blkio_config.%s[%d]: %w",
+						instance.ID, group.field, i, err)
+				}
+			}
+		}
+
+		for i, dev := range blkio.DeviceWeight {
+			if dev.Path == BlkioDeviceAuto {
+				continue
+			}
+
+			if err := validateBlockDevice(dev.Path); err != nil {
+				return fmt.Errorf("instance %q: blkio_config.device_weight[%d]: %w", instance.ID, i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBlockDevice checks that path exists and is a block device.
+func validateBlockDevice(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("device %q: %w", path, err)
+	}
+
+	if info.Mode()&os.ModeDevice == 0 || info.Mode()&os.ModeCharDevice != 0 {
+		return fmt.Errorf("device %q is not a block device", path)
+	}
+
+	return nil
+}
+
+// validateTransparentHugepage validates transparent_hugepage settings and
+// checks system capabilities.
+func (c *Config) validateTransparentHugepage() error {
 	enabled := false
 
 	for _, instance := range c.Runner.Instances {
 		limits := c.GetResourceLimits(&instance)
-		if limits == nil {
+		if limits == nil || limits.TransparentHugepage == "" {
 			continue
 		}
 
-		if limits.CPUFreq != "" || limits.CPUTurboBoost != nil || limits.CPUGovernor != "" {
-			enabled = true
-
-			break
+		if !slices.Contains(thp.ValidModes, limits.TransparentHugepage) {
+			return fmt.Errorf("instance %q: invalid transparent_hugepage %q (must be one of: %s)",
+				instance.ID, limits.TransparentHugepage, strings.Join(thp.ValidModes, ", "))
 		}
+
+		enabled = true
 	}
 
 	if !enabled {
 		return nil
 	}
 
-	// Check OS - CPU frequency control is Linux-only.
+	// Check OS - THP control is Linux-only.
 	if runtime.GOOS != "linux" {
-		return fmt.Errorf("cpu_freq is only supported on Linux (current OS: %s)", runtime.GOOS)
+		return fmt.Errorf("transparent_hugepage is only supported on Linux (current OS: %s)", runtime.GOOS)
 	}
 
-	sysfsPath := c.GetCPUSysfsPath()
+	sysfsPath := c.GetTHPSysfsPath()
 
-	// Check if cpufreq subsystem is available.
-	if !cpufreq.IsCPUFreqSupported(sysfsPath) {
-		return fmt.Errorf("cpu_freq: cpufreq subsystem not available (no scaling_governor in sysfs)")
+	if !thp.IsTHPSupported(sysfsPath) {
+		return fmt.Errorf("transparent_hugepage: THP not available (no readable %s/enabled)", sysfsPath)
 	}
 
-	// Check write access.
-	if err := cpufreq.HasWriteAccess(sysfsPath); err != nil {
-		return fmt.Errorf("cpu_freq: %w", err)
+	if err := thp.HasWriteAccess(sysfsPath); err != nil {
+		return fmt.Errorf("transparent_hugepage: %w", err)
 	}
 
-	// Validate each instance's settings.
 	for _, instance := range c.Runner.Instances {
 		limits := c.GetResourceLimits(&instance)
-		if limits == nil {
+		if limits == nil || limits.TransparentHugepage == "" {
 			continue
 		}
 
-		// Validate frequency format and bounds.
-		if limits.CPUFreq != "" && strings.ToUpper(limits.CPUFreq) != "MAX" {
-			freqKHz, err := cpufreq.ParseFrequency(limits.CPUFreq)
-			if err != nil {
-				return fmt.Errorf("instance %q: invalid cpu_freq %q: %w", instance.ID, limits.CPUFreq, err)
-			}
-
-			if err := cpufreq.ValidateFrequency(sysfsPath, freqKHz); err != nil {
-				return fmt.Errorf("instance %q: %w", instance.ID, err)
-			}
-		}
-
-		// Validate governor.
-		if limits.CPUGovernor != "" {
-			if err := cpufreq.ValidateGovernor(sysfsPath, limits.CPUGovernor); err != nil {
-				return fmt.Errorf("instance %q: %w", instance.ID, err)
-			}
+		if err := thp.ValidateMode(sysfsPath, limits.TransparentHugepage); err != nil {
+			return fmt.Errorf("instance %q: %w", instance.ID, err)
 		}
 	}
 
@@ -1783,6 +3284,33 @@ func (c *Config) validatePostTestSleepDuration() error {
 	return nil
 }
 
+// validateFCUKeepaliveInterval validates fcu_keepalive_interval settings.
+func (c *Config) validateFCUKeepaliveInterval() error {
+	for _, instance := range c.Runner.Instances {
+		s := instance.FCUKeepaliveInterval
+		if s == "" {
+			s = c.Runner.Client.Config.FCUKeepaliveInterval
+		}
+
+		if s == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("instance %q: invalid fcu_keepalive_interval %q: %w",
+				instance.ID, s, err)
+		}
+
+		if d <= 0 {
+			return fmt.Errorf("instance %q: fcu_keepalive_interval must be positive, got %q",
+				instance.ID, s)
+		}
+	}
+
+	return nil
+}
+
 // validateRunTimeout validates run_timeout settings.
 func (c *Config) validateRunTimeout() error {
 	if c.Runner.RunTimeout != "" {
@@ -1809,6 +3337,94 @@ func (c *Config) validateRunTimeout() error {
 	return nil
 }
 
+// validateDiskBenchmark validates disk_benchmark settings.
+func (c *Config) validateDiskBenchmark() error {
+	cfg := c.Runner.DiskBenchmark
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	switch cfg.Tool {
+	case "", diskbench.ToolAuto, diskbench.ToolInternal:
+	case diskbench.ToolFio:
+		if _, err := exec.LookPath("fio"); err != nil {
+			return fmt.Errorf("runner.disk_benchmark.tool is %q but fio is not installed", cfg.Tool)
+		}
+	default:
+		return fmt.Errorf("runner.disk_benchmark.tool: invalid value %q (must be %q, %q or %q)",
+			cfg.Tool, diskbench.ToolAuto, diskbench.ToolFio, diskbench.ToolInternal)
+	}
+
+	if cfg.Size != "" {
+		size, err := ParseByteSize(cfg.Size)
+		if err != nil {
+			return fmt.Errorf("runner.disk_benchmark.size: %w", err)
+		}
+
+		if size < DiskBenchmarkMinSize {
+			return fmt.Errorf("runner.disk_benchmark.size must be at least %d bytes", DiskBenchmarkMinSize)
+		}
+	}
+
+	if cfg.Duration != "" {
+		d, err := time.ParseDuration(cfg.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid runner.disk_benchmark.duration %q: %w", cfg.Duration, err)
+		}
+
+		if d < time.Second {
+			return fmt.Errorf("runner.disk_benchmark.duration must be at least 1s")
+		}
+	}
+
+	return nil
+}
+
+// validateDownloadRetries validates download_retries settings.
+func (c *Config) validateDownloadRetries() error {
+	cfg := c.Runner.DownloadRetries
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.MaxAttempts < 0 {
+		return fmt.Errorf("runner.download_retries.max_attempts must not be negative")
+	}
+
+	var backoff, maxBackoff time.Duration
+
+	if cfg.Backoff != "" {
+		d, err := time.ParseDuration(cfg.Backoff)
+		if err != nil {
+			return fmt.Errorf("invalid runner.download_retries.backoff %q: %w", cfg.Backoff, err)
+		}
+
+		backoff = d
+	}
+
+	if cfg.MaxBackoff != "" {
+		d, err := time.ParseDuration(cfg.MaxBackoff)
+		if err != nil {
+			return fmt.Errorf("invalid runner.download_retries.max_backoff %q: %w", cfg.MaxBackoff, err)
+		}
+
+		maxBackoff = d
+	}
+
+	if backoff < 0 || maxBackoff < 0 {
+		return fmt.Errorf("runner.download_retries backoff durations must not be negative")
+	}
+
+	if maxBackoff > 0 && maxBackoff < backoff {
+		return fmt.Errorf(
+			"runner.download_retries.max_backoff %q must not be less than backoff %q",
+			cfg.MaxBackoff, cfg.Backoff,
+		)
+	}
+
+	return nil
+}
+
 // validatePostTestRPCCalls validates post_test_rpc_calls settings.
 func (c *Config) validatePostTestRPCCalls() error {
 	// Validate global-level calls.
@@ -1962,6 +3578,16 @@ func (c *Config) ValidateAPI() error {
 		}
 	}
 
+	// Validate CORS settings.
+	if err := c.validateAPICORS(); err != nil {
+		return err
+	}
+
+	// Validate compression settings.
+	if err := c.validateAPICompression(); err != nil {
+		return err
+	}
+
 	// Validate session TTL is parseable.
 	if _, err := time.ParseDuration(c.API.Auth.SessionTTL); err != nil {
 		return fmt.Errorf(
@@ -2053,6 +3679,24 @@ func (c *Config) ValidateAPI() error {
 				)
 			}
 		}
+
+		for team, role := range c.API.Auth.GitHub.TeamRoleMapping {
+			org, slug, ok := strings.Cut(team, "/")
+			if !ok || org == "" || slug == "" || strings.Contains(slug, "/") {
+				return fmt.Errorf(
+					"api.auth.github.team_role_mapping[%q]: "+
+						"key must be in the form \"org/team\"",
+					team,
+				)
+			}
+
+			if !validRoles[role] {
+				return fmt.Errorf(
+					"api.auth.github.team_role_mapping[%q]: invalid role %q",
+					team, role,
+				)
+			}
+		}
 	}
 
 	// Validate storage settings.
@@ -2065,6 +3709,245 @@ func (c *Config) ValidateAPI() error {
 		return err
 	}
 
+	// Validate per-role discovery path restrictions.
+	if err := c.validateAPIRolePathAllowlist(); err != nil {
+		return err
+	}
+
+	// Validate remote run settings.
+	if err := c.validateAPIRuns(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validCORSMethods lists the HTTP methods accepted in
+// api.server.cors.allowed_methods.
+var validCORSMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// validateAPICORS validates that CORS origins are "*" or bare
+// scheme://host[:port] URLs and that methods and headers are well-formed.
+func (c *Config) validateAPICORS() error {
+	cors := c.API.Server.CORS
+
+	for i, origin := range cors.AllowedOrigins {
+		if origin == "*" {
+			if len(cors.AllowedOrigins) > 1 {
+				return fmt.Errorf(
+					"api.server.cors.allowed_origins[%d]: "+
+						"\"*\" cannot be combined with other origins", i,
+				)
+			}
+
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil {
+			return fmt.Errorf(
+				"api.server.cors.allowed_origins[%d]: invalid origin %q: %w",
+				i, origin, err,
+			)
+		}
+
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf(
+				"api.server.cors.allowed_origins[%d]: origin %q must be \"*\" "+
+					"or an http(s) URL such as \"https://example.com\"",
+				i, origin,
+			)
+		}
+
+		// Browsers send the Origin header without a path, so anything but
+		// scheme://host[:port], including a trailing slash, never matches.
+		if origin != u.Scheme+"://"+u.Host {
+			return fmt.Errorf(
+				"api.server.cors.allowed_origins[%d]: origin %q must only "+
+					"contain a scheme, host and optional port",
+				i, origin,
+			)
+		}
+	}
+
+	for i, method := range cors.AllowedMethods {
+		if !validCORSMethods[strings.ToUpper(method)] {
+			return fmt.Errorf(
+				"api.server.cors.allowed_methods[%d]: invalid method %q",
+				i, method,
+			)
+		}
+	}
+
+	for i, header := range cors.AllowedHeaders {
+		if strings.TrimSpace(header) == "" || strings.ContainsAny(header, " ,:") {
+			return fmt.Errorf(
+				"api.server.cors.allowed_headers[%d]: invalid header %q",
+				i, header,
+			)
+		}
+	}
+
+	if cors.MaxAge < 0 {
+		return fmt.Errorf("api.server.cors.max_age: must not be negative")
+	}
+
+	return nil
+}
+
+// validateAPICompression validates the gzip level, minimum size and
+// content-type allowlist.
+func (c *Config) validateAPICompression() error {
+	compression := c.API.Server.Compression
+
+	// A zero level is left unset and uses the gzip default.
+	if compression.Level != 0 && (compression.Level < gzip.BestSpeed ||
+		compression.Level > gzip.BestCompression) {
+		return fmt.Errorf(
+			"api.server.compression.level: must be between %d and %d, got %d",
+			gzip.BestSpeed, gzip.BestCompression, compression.Level,
+		)
+	}
+
+	if compression.MinSize < 0 {
+		return fmt.Errorf("api.server.compression.min_size: must not be negative")
+	}
+
+	for i, contentType := range compression.ContentTypes {
+		mediaType, params, err := mime.ParseMediaType(contentType)
+		if err != nil || len(params) > 0 || !strings.Contains(mediaType, "/") {
+			return fmt.Errorf(
+				"api.server.compression.content_types[%d]: "+
+					"invalid media type %q", i, contentType,
+			)
+		}
+
+		if mediaType == "text/event-stream" {
+			return fmt.Errorf(
+				"api.server.compression.content_types[%d]: "+
+					"streaming responses cannot be compressed", i,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateAPIRolePathAllowlist validates that each restricted role is a
+// non-admin role and only references configured storage discovery paths.
+func (c *Config) validateAPIRolePathAllowlist() error {
+	allowlist := c.API.Auth.RolePathAllowlist
+	if len(allowlist) == 0 {
+		return nil
+	}
+
+	// Anonymous reads skip authentication, so restrictions could be
+	// bypassed by simply omitting credentials.
+	if c.API.Auth.AnonymousRead {
+		return fmt.Errorf(
+			"api.auth.role_path_allowlist: cannot be combined with anonymous_read",
+		)
+	}
+
+	configured := make(map[string]struct{}, 4)
+
+	if s3Cfg := c.API.Storage.S3; s3Cfg != nil && s3Cfg.Enabled {
+		for _, p := range s3Cfg.DiscoveryPaths {
+			configured[strings.TrimRight(p, "/")] = struct{}{}
+		}
+	}
+
+	if localCfg := c.API.Storage.Local; localCfg != nil && localCfg.Enabled {
+		for name := range localCfg.DiscoveryPaths {
+			configured[name] = struct{}{}
+		}
+	}
+
+	for role, paths := range allowlist {
+		if !validRoles[role] {
+			return fmt.Errorf(
+				"api.auth.role_path_allowlist[%q]: invalid role", role,
+			)
+		}
+
+		if role == "admin" {
+			return fmt.Errorf(
+				"api.auth.role_path_allowlist[%q]: admin access cannot be restricted",
+				role,
+			)
+		}
+
+		if len(paths) == 0 {
+			return fmt.Errorf(
+				"api.auth.role_path_allowlist[%q]: at least one path is required",
+				role,
+			)
+		}
+
+		for i, p := range paths {
+			if _, ok := configured[strings.TrimRight(p, "/")]; !ok {
+				return fmt.Errorf(
+					"api.auth.role_path_allowlist[%q][%d]: %q is not a configured "+
+						"storage discovery path",
+					role, i, p,
+				)
+			}
+
+			// Paths are passed to the index query layer as a comma-separated
+			// IN filter.
+			if strings.Contains(p, ",") {
+				return fmt.Errorf(
+					"api.auth.role_path_allowlist[%q][%d]: path must not contain \",\"",
+					role, i,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAPIRuns validates the remote run configuration.
+func (c *Config) validateAPIRuns() error {
+	runs := c.API.Runs
+	if runs == nil || !runs.Enabled {
+		return nil
+	}
+
+	if len(runs.BaseConfigs) == 0 {
+		return fmt.Errorf(
+			"api.runs: at least one base_config is required when enabled",
+		)
+	}
+
+	for i, path := range runs.BaseConfigs {
+		if err := validateFileExists(
+			path, fmt.Sprintf("api.runs.base_configs[%d]", i),
+		); err != nil {
+			return err
+		}
+	}
+
+	if runs.Concurrency < 0 {
+		return fmt.Errorf(
+			"api.runs.concurrency: must be >= 0 (0 means default)",
+		)
+	}
+
+	if runs.QueueSize < 0 {
+		return fmt.Errorf(
+			"api.runs.queue_size: must be >= 0 (0 means default)",
+		)
+	}
+
 	return nil
 }
 
@@ -2122,13 +4005,22 @@ func (c *Config) validateAPIS3Storage() error {
 		}
 	}
 
-	if _, err := time.ParseDuration(s3Cfg.PresignedURLs.Expiry); err != nil {
+	expiry, err := time.ParseDuration(s3Cfg.PresignedURLs.Expiry)
+	if err != nil {
 		return fmt.Errorf(
 			"api.storage.s3.presigned_urls.expiry: invalid duration %q: %w",
 			s3Cfg.PresignedURLs.Expiry, err,
 		)
 	}
 
+	// SigV4 presigned URLs are valid for at most seven days.
+	if expiry <= 0 || expiry > 7*24*time.Hour {
+		return fmt.Errorf(
+			"api.storage.s3.presigned_urls.expiry: must be between 0s and 168h, got %q",
+			s3Cfg.PresignedURLs.Expiry,
+		)
+	}
+
 	return nil
 }
 
@@ -2315,6 +4207,9 @@ func bootstrapFCUDecodeHook() mapstructure.DecodeHookFuncType {
 // with their original casing, since Viper lowercases all map keys internally.
 type rawRunnerConfig struct {
 	Runner struct {
+		InstanceDefaults *struct {
+			Environment map[string]string `yaml:"environment"`
+		} `yaml:"instance_defaults"`
 		Instances []struct {
 			ID          string            `yaml:"id"`
 			Environment map[string]string `yaml:"environment"`
@@ -2327,12 +4222,18 @@ type rawRunnerConfig struct {
 func restoreEnvironmentKeyCasing(cfg *Config, rawYAMLs []string) {
 	envByID := make(map[string]map[string]string, len(cfg.Runner.Instances))
 
+	var defaultsEnv map[string]string
+
 	for _, raw := range rawYAMLs {
 		var parsed rawRunnerConfig
 		if err := yaml.Unmarshal([]byte(raw), &parsed); err != nil {
 			continue
 		}
 
+		if parsed.Runner.InstanceDefaults != nil && parsed.Runner.InstanceDefaults.Environment != nil {
+			defaultsEnv = parsed.Runner.InstanceDefaults.Environment
+		}
+
 		for _, inst := range parsed.Runner.Instances {
 			if inst.Environment != nil {
 				envByID[inst.ID] = inst.Environment
@@ -2340,6 +4241,10 @@ func restoreEnvironmentKeyCasing(cfg *Config, rawYAMLs []string) {
 		}
 	}
 
+	if defaultsEnv != nil && cfg.Runner.InstanceDefaults != nil {
+		cfg.Runner.InstanceDefaults.Environment = defaultsEnv
+	}
+
 	for i := range cfg.Runner.Instances {
 		if orig, ok := envByID[cfg.Runner.Instances[i].ID]; ok {
 			cfg.Runner.Instances[i].Environment = orig
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// fakeBlockDevice creates a block device node in a temp dir, skipping the
+// test if the process may not create device nodes.
+func fakeBlockDevice(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "sdb")
+
+	err := unix.Mknod(path, unix.S_IFBLK|0o600, int(unix.Mkdev(8, 16)))
+	if errors.Is(err, unix.EPERM) {
+		t.Skip("creating device nodes requires CAP_MKNOD")
+	}
+
+	require.NoError(t, err)
+
+	return path
+}
+
+func TestValidateBlkioDevices(t *testing.T) {
+	blockDevice := fakeBlockDevice(t)
+
+	regularFile := filepath.Join(t.TempDir(), "disk.img")
+	require.NoError(t, os.WriteFile(regularFile, nil, 0o644))
+
+	tests := []struct {
+		name      string
+		path      string
+		skip      bool
+		errSubstr string
+	}{
+		{name: "block device", path: blockDevice},
+		{name: "auto", path: BlkioDeviceAuto},
+		{name: "missing device", path: "/dev/sdaa-benchmarkoor-missing", errSubstr: "no such file"},
+		{name: "regular file", path: regularFile, errSubstr: "is not a block device"},
+		{name: "character device", path: "/dev/null", errSubstr: "is not a block device"},
+		{name: "check skipped", path: regularFile, skip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Runner.SkipBlkioDeviceCheck = tt.skip
+			cfg.Runner.Instances = []ClientInstance{{
+				ID: "geth-1", Client: "geth",
+				ResourceLimits: &ResourceLimits{BlkioConfig: &BlkioConfig{
+					DeviceWriteIOps: []ThrottleDevice{{Path: tt.path, Rate: "100"}},
+				}},
+			}}
+
+			err := cfg.validateBlkioDevices(ValidateOpts{})
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "blkio_config.device_write_iops[0]")
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("inactive instance", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Runner.Client.Config.ResourceLimits = &ResourceLimits{BlkioConfig: &BlkioConfig{
+			DeviceReadBps: []ThrottleDevice{{Path: regularFile, Rate: "10mb"}},
+		}}
+		cfg.Runner.Instances = []ClientInstance{{ID: "geth-1", Client: "geth"}}
+
+		err := cfg.validateBlkioDevices(ValidateOpts{
+			ActiveInstanceIDs: map[string]struct{}{"reth-1": {}},
+		})
+		require.NoError(t, err)
+	})
+}
@@ -3,8 +3,12 @@ package config
 import (
 	"archive/tar"
 	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -321,6 +325,8 @@ func TestLoad_InvalidYAML(t *testing.T) {
 }
 
 func TestSourceConfig_Validate(t *testing.T) {
+	depthPtr := func(d int) *int { return &d }
+
 	tmpDir := t.TempDir()
 
 	// Create test tarballs for local tarball validation tests.
@@ -418,6 +424,64 @@ func TestSourceConfig_Validate(t *testing.T) {
 			wantErr:   true,
 			errSubstr: "must specify one of",
 		},
+		{
+			name: "eest_fixtures download_parts with release",
+			source: SourceConfig{
+				EESTFixtures: &EESTFixturesSource{
+					GitHubRepo:    "ethereum/execution-spec-tests",
+					GitHubRelease: "benchmark@v0.0.6",
+					DownloadParts: 4,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "eest_fixtures negative download_parts",
+			source: SourceConfig{
+				EESTFixtures: &EESTFixturesSource{
+					GitHubRepo:    "ethereum/execution-spec-tests",
+					GitHubRelease: "benchmark@v0.0.6",
+					DownloadParts: -1,
+				},
+			},
+			wantErr:   true,
+			errSubstr: "download_parts must not be negative",
+		},
+		{
+			name: "eest_fixtures download_parts without release",
+			source: SourceConfig{
+				EESTFixtures: &EESTFixturesSource{
+					GitHubRepo:           "ethereum/execution-spec-tests",
+					FixturesArtifactName: "fixtures_benchmark",
+					DownloadParts:        4,
+				},
+			},
+			wantErr:   true,
+			errSubstr: "download_parts requires github_release",
+		},
+		{
+			name: "eest_fixtures cache_max_size",
+			source: SourceConfig{
+				EESTFixtures: &EESTFixturesSource{
+					GitHubRepo:    "ethereum/execution-spec-tests",
+					GitHubRelease: "benchmark@v0.0.6",
+					CacheMaxSize:  "50g",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "eest_fixtures invalid cache_max_size",
+			source: SourceConfig{
+				EESTFixtures: &EESTFixturesSource{
+					GitHubRepo:    "ethereum/execution-spec-tests",
+					GitHubRelease: "benchmark@v0.0.6",
+					CacheMaxSize:  "lots",
+				},
+			},
+			wantErr:   true,
+			errSubstr: "eest_fixtures.cache_max_size: invalid byte size",
+		},
 		{
 			name: "valid eest_fixtures with artifacts",
 			source: SourceConfig{
@@ -591,6 +655,125 @@ func TestSourceConfig_Validate(t *testing.T) {
 			wantErr:   true,
 			errSubstr: "git.version is required",
 		},
+		{
+			name: "git negative depth",
+			source: SourceConfig{
+				Git: &GitSourceV2{
+					Repo:    "https://github.com/test/repo",
+					Version: "main",
+					Depth:   depthPtr(-1),
+				},
+			},
+			wantErr:   true,
+			errSubstr: "git.depth must be >= 0",
+		},
+		{
+			name: "git full history with sparse paths",
+			source: SourceConfig{
+				Git: &GitSourceV2{
+					Repo:        "https://github.com/test/repo",
+					Version:     "main",
+					Depth:       depthPtr(0),
+					SparsePaths: []string{"tests/"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "git empty sparse path",
+			source: SourceConfig{
+				Git: &GitSourceV2{
+					Repo:        "https://github.com/test/repo",
+					Version:     "main",
+					SparsePaths: []string{" "},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "git.sparse_paths must not contain empty paths",
+		},
+		{
+			name: "git with pre_run_steps_source",
+			source: SourceConfig{
+				Git: &GitSourceV2{
+					Repo:    "https://github.com/test/repo",
+					Version: "main",
+					PreRunStepsSource: &GitSourceV2{
+						Repo:        "https://github.com/test/setup",
+						Version:     "v1.0.0",
+						PreRunSteps: []string{"warmup/*.txt"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "git pre_run_steps_source missing version",
+			source: SourceConfig{
+				Git: &GitSourceV2{
+					Repo:    "https://github.com/test/repo",
+					Version: "main",
+					PreRunStepsSource: &GitSourceV2{
+						Repo:        "https://github.com/test/setup",
+						PreRunSteps: []string{"warmup/*.txt"},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "git.pre_run_steps_source.version is required",
+		},
+		{
+			name: "git pre_run_steps_source without pre_run_steps",
+			source: SourceConfig{
+				Git: &GitSourceV2{
+					Repo:    "https://github.com/test/repo",
+					Version: "main",
+					PreRunStepsSource: &GitSourceV2{
+						Repo:    "https://github.com/test/setup",
+						Version: "v1.0.0",
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "git.pre_run_steps_source.pre_run_steps is required",
+		},
+		{
+			name: "git pre_run_steps_source with steps",
+			source: SourceConfig{
+				Git: &GitSourceV2{
+					Repo:    "https://github.com/test/repo",
+					Version: "main",
+					PreRunStepsSource: &GitSourceV2{
+						Repo:        "https://github.com/test/setup",
+						Version:     "v1.0.0",
+						PreRunSteps: []string{"warmup/*.txt"},
+						Steps:       &StepsConfig{Test: []string{"tests/*.txt"}},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "git.pre_run_steps_source.steps is not supported",
+		},
+		{
+			name: "git nested pre_run_steps_source",
+			source: SourceConfig{
+				Git: &GitSourceV2{
+					Repo:    "https://github.com/test/repo",
+					Version: "main",
+					PreRunStepsSource: &GitSourceV2{
+						Repo:        "https://github.com/test/setup",
+						Version:     "v1.0.0",
+						PreRunSteps: []string{"warmup/*.txt"},
+						PreRunStepsSource: &GitSourceV2{
+							Repo:        "https://github.com/test/other",
+							Version:     "main",
+							PreRunSteps: []string{"*.txt"},
+						},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "git.pre_run_steps_source.pre_run_steps_source is not supported",
+		},
 		{
 			name: "local missing base_dir",
 			source: SourceConfig{
@@ -1071,6 +1254,389 @@ runner:
 	assert.False(t, hasLower)
 }
 
+func TestLoad_Include(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	t.Run("nested relative includes", func(t *testing.T) {
+		writeFile("nested/main.yaml", `
+runner:
+  client:
+    config: !include shared/client.yaml
+  instances:
+    - id: besu
+      client: besu
+    - !include shared/instances.yaml
+`)
+		writeFile("nested/shared/client.yaml", `
+resource_limits: !include limits.yaml
+drop_memory_caches: steps
+`)
+		writeFile("nested/shared/limits.yaml", `
+memory: ${INCLUDE_MEMORY:-8g}
+cpuset_count: 2
+`)
+		writeFile("nested/shared/instances.yaml", `
+- id: geth
+  client: geth
+- id: reth
+  client: reth
+`)
+		t.Setenv("INCLUDE_MEMORY", "16g")
+
+		cfg, err := Load(filepath.Join(dir, "nested/main.yaml"))
+		require.NoError(t, err)
+
+		require.NotNil(t, cfg.Runner.Client.Config.ResourceLimits)
+		assert.Equal(t, "16g", cfg.Runner.Client.Config.ResourceLimits.Memory)
+		require.NotNil(t, cfg.Runner.Client.Config.ResourceLimits.CpusetCount)
+		assert.Equal(t, 2, *cfg.Runner.Client.Config.ResourceLimits.CpusetCount)
+		assert.Equal(t, "steps", cfg.Runner.Client.Config.DropMemoryCaches)
+
+		ids := make([]string, len(cfg.Runner.Instances))
+		for i, instance := range cfg.Runner.Instances {
+			ids[i] = instance.ID
+		}
+
+		assert.Equal(t, []string{"besu", "geth", "reth"}, ids)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		writeFile("cycle/main.yaml", "runner: !include a.yaml\n")
+		writeFile("cycle/a.yaml", "client: !include b.yaml\n")
+		writeFile("cycle/b.yaml", "config: !include a.yaml\n")
+
+		_, err := Load(filepath.Join(dir, "cycle/main.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "include cycle")
+		assert.Contains(t, err.Error(), "a.yaml -> "+filepath.Join(dir, "cycle/b.yaml")+" -> ")
+	})
+
+	t.Run("self include", func(t *testing.T) {
+		writeFile("self.yaml", "runner: !include self.yaml\n")
+
+		_, err := Load(filepath.Join(dir, "self.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "include cycle")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		writeFile("missing.yaml", "runner: !include does-not-exist.yaml\n")
+
+		_, err := Load(filepath.Join(dir, "missing.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does-not-exist.yaml")
+	})
+
+	t.Run("include without path", func(t *testing.T) {
+		writeFile("nopath.yaml", "runner: !include\n")
+
+		_, err := Load(filepath.Join(dir, "nopath.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "requires a file path")
+	})
+
+	t.Run("remote config", func(t *testing.T) {
+		writeFile("local-limits.yaml", "memory: 8g\n")
+
+		files := map[string]string{
+			"/configs/main.yaml":          "runner:\n  client:\n    config: !include shared/client.yaml\n",
+			"/configs/shared/client.yaml": "drop_memory_caches: steps\n",
+			"/configs/absolute.yaml": "runner:\n  client:\n    config:\n      resource_limits: !include " +
+				filepath.Join(dir, "local-limits.yaml") + "\n",
+			"/configs/file-url.yaml": "runner:\n  client:\n    config:\n      resource_limits: !include file:///etc/passwd\n",
+		}
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			content, ok := files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(content))
+		}))
+		defer srv.Close()
+
+		cfg, err := Load(srv.URL + "/configs/main.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "steps", cfg.Runner.Client.Config.DropMemoryCaches)
+
+		_, err = Load(srv.URL + "/configs/absolute.yaml")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "remote config cannot include local path")
+
+		_, err = Load(srv.URL + "/configs/file-url.yaml")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "remote config cannot include non-http(s) source")
+	})
+}
+
+func TestLoad_Matrix(t *testing.T) {
+	configContent := `
+runner:
+  instances:
+    - id: besu
+      client: besu
+    - id: geth
+      client: geth
+      image: ethereum/client-go:stable
+      environment:
+        GETH_CACHE: "4096"
+      resource_limits:
+        swap_disabled: true
+      matrix:
+        resource_limits.cpuset_count: [2, 4, 8]
+        resource_limits.memory: [8g, 16G]
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o644))
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+
+	ids := make([]string, len(cfg.Runner.Instances))
+	for i, instance := range cfg.Runner.Instances {
+		ids[i] = instance.ID
+	}
+
+	assert.Equal(t, []string{
+		"besu",
+		"geth-2-8g", "geth-2-16g",
+		"geth-4-8g", "geth-4-16g",
+		"geth-8-8g", "geth-8-16g",
+	}, ids)
+
+	generated := cfg.Runner.Instances[4]
+	assert.Equal(t, "geth", generated.Client)
+	assert.Equal(t, "ethereum/client-go:stable", generated.Image)
+	assert.Equal(t, "4096", generated.Environment["GETH_CACHE"])
+	assert.Nil(t, generated.Matrix)
+	require.NotNil(t, generated.ResourceLimits)
+	require.NotNil(t, generated.ResourceLimits.CpusetCount)
+	assert.Equal(t, 4, *generated.ResourceLimits.CpusetCount)
+	assert.Equal(t, "16G", generated.ResourceLimits.Memory)
+	assert.True(t, generated.ResourceLimits.SwapDisabled)
+
+	// Generated instances do not share state.
+	assert.NotSame(t, cfg.Runner.Instances[1].ResourceLimits, cfg.Runner.Instances[2].ResourceLimits)
+}
+
+func TestExpandMatrices(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []ClientInstance
+		wantIDs   []string
+		errSubstr string
+	}{
+		{
+			name:      "no matrix",
+			instances: []ClientInstance{{ID: "geth", Client: "geth"}},
+			wantIDs:   []string{"geth"},
+		},
+		{
+			name: "single axis with unsafe values",
+			instances: []ClientInstance{{
+				ID: "reth", Client: "reth",
+				Matrix: map[string][]any{"resource_limits.cpus": {"0.5", "1 cpu"}},
+			}},
+			wantIDs: []string{"reth-0.5", "reth-1_cpu"},
+		},
+		{
+			name: "generated id collides with instance",
+			instances: []ClientInstance{
+				{ID: "geth-2", Client: "geth"},
+				{ID: "geth", Client: "geth", Matrix: map[string][]any{"resource_limits.cpuset_count": {2}}},
+			},
+			errSubstr: `duplicate instance id "geth-2"`,
+		},
+		{
+			name: "values collide after sanitizing",
+			instances: []ClientInstance{{
+				ID: "geth", Client: "geth",
+				Matrix: map[string][]any{"resource_limits.memory": {"8g", "8G"}},
+			}},
+			errSubstr: `duplicate instance id "geth-8g"`,
+		},
+		{
+			name: "unknown field",
+			instances: []ClientInstance{{
+				ID: "geth", Client: "geth",
+				Matrix: map[string][]any{"resource_limits.cores": {2}},
+			}},
+			errSubstr: "cores",
+		},
+		{
+			name: "empty axis",
+			instances: []ClientInstance{{
+				ID: "geth", Client: "geth",
+				Matrix: map[string][]any{"resource_limits.memory": {}},
+			}},
+			errSubstr: "has no values",
+		},
+		{
+			name: "id axis",
+			instances: []ClientInstance{{
+				ID: "geth", Client: "geth",
+				Matrix: map[string][]any{"id": {"a", "b"}},
+			}},
+			errSubstr: "cannot be varied",
+		},
+		{
+			name: "path through scalar",
+			instances: []ClientInstance{{
+				ID: "geth", Client: "geth", Image: "geth:latest",
+				Matrix: map[string][]any{"image.tag": {"a"}},
+			}},
+			errSubstr: "is not an object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Runner: RunnerConfig{Instances: tt.instances}}
+
+			err := cfg.expandMatrices()
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]string, len(cfg.Runner.Instances))
+			for i, instance := range cfg.Runner.Instances {
+				ids[i] = instance.ID
+			}
+
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestLoad_InstanceDefaults(t *testing.T) {
+	configContent := `
+runner:
+  instance_defaults:
+    image: ethereum/client-go:stable
+    extra_args: ["--cache", "4096"]
+    environment:
+      GETH_LOG: debug
+    resource_limits:
+      memory: 8g
+      cpuset_count: 2
+  instances:
+    - id: geth-default
+      client: geth
+    - id: geth-override
+      client: geth
+      image: ethereum/client-go:latest
+      extra_args: ["--syncmode", "full"]
+      environment:
+        OTHER_VAR: "1"
+      resource_limits:
+        memory: 16g
+    - id: geth-matrix
+      client: geth
+      matrix:
+        resource_limits.cpuset_count: [4]
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o644))
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Runner.Instances, 3)
+
+	inherited := cfg.Runner.Instances[0]
+	assert.Equal(t, "ethereum/client-go:stable", inherited.Image)
+	assert.Equal(t, []string{"--cache", "4096"}, inherited.ExtraArgs)
+	assert.Equal(t, map[string]string{"GETH_LOG": "debug"}, inherited.Environment)
+	require.NotNil(t, inherited.ResourceLimits)
+	assert.Equal(t, "8g", inherited.ResourceLimits.Memory)
+	require.NotNil(t, inherited.ResourceLimits.CpusetCount)
+	assert.Equal(t, 2, *inherited.ResourceLimits.CpusetCount)
+
+	// Fields set on the instance replace the default as a whole.
+	overridden := cfg.Runner.Instances[1]
+	assert.Equal(t, "ethereum/client-go:latest", overridden.Image)
+	assert.Equal(t, []string{"--syncmode", "full"}, overridden.ExtraArgs)
+	assert.Equal(t, map[string]string{"OTHER_VAR": "1"}, overridden.Environment)
+	require.NotNil(t, overridden.ResourceLimits)
+	assert.Equal(t, "16g", overridden.ResourceLimits.Memory)
+	assert.Nil(t, overridden.ResourceLimits.CpusetCount)
+
+	// Matrix axes override the defaults they expand.
+	expanded := cfg.Runner.Instances[2]
+	assert.Equal(t, "geth-matrix-4", expanded.ID)
+	assert.Equal(t, "ethereum/client-go:stable", expanded.Image)
+	require.NotNil(t, expanded.ResourceLimits)
+	assert.Equal(t, "8g", expanded.ResourceLimits.Memory)
+	require.NotNil(t, expanded.ResourceLimits.CpusetCount)
+	assert.Equal(t, 4, *expanded.ResourceLimits.CpusetCount)
+
+	// Instances do not share the default values.
+	assert.NotSame(t, inherited.ResourceLimits, expanded.ResourceLimits)
+}
+
+func TestApplyInstanceDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		defaults  *ClientInstance
+		errSubstr string
+	}{
+		{
+			name: "no defaults",
+		},
+		{
+			name:     "valid defaults",
+			defaults: &ClientInstance{Image: "img", PullPolicy: "never"},
+		},
+		{
+			name:      "id not allowed",
+			defaults:  &ClientInstance{ID: "geth"},
+			errSubstr: "id cannot have a default",
+		},
+		{
+			name:      "matrix not allowed",
+			defaults:  &ClientInstance{Matrix: map[string][]any{"image": {"a"}}},
+			errSubstr: "matrix cannot have a default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Runner: RunnerConfig{
+				InstanceDefaults: tt.defaults,
+				Instances:        []ClientInstance{{ID: "geth", Client: "geth", PullPolicy: "always"}},
+			}}
+
+			err := cfg.applyInstanceDefaults()
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "geth", cfg.Runner.Instances[0].ID)
+			assert.Equal(t, "always", cfg.Runner.Instances[0].PullPolicy)
+
+			if tt.defaults != nil {
+				assert.Equal(t, tt.defaults.Image, cfg.Runner.Instances[0].Image)
+			}
+		})
+	}
+}
+
 func TestLoad_BootstrapFCU(t *testing.T) {
 	t.Run("shorthand bool true", func(t *testing.T) {
 		configContent := `
@@ -1473,7 +2039,29 @@ func TestValidateAPIStorage(t *testing.T) {
 			errSubstr: "invalid duration",
 		},
 		{
-			name: "multiple valid discovery paths",
+			name: "non-positive expiry",
+			s3Cfg: &APIS3Config{
+				Enabled:        true,
+				Bucket:         "my-bucket",
+				DiscoveryPaths: []string{"results"},
+				PresignedURLs:  APIS3PresignedURLConfig{Expiry: "0s"},
+			},
+			wantErr:   true,
+			errSubstr: "must be between",
+		},
+		{
+			name: "expiry beyond seven days",
+			s3Cfg: &APIS3Config{
+				Enabled:        true,
+				Bucket:         "my-bucket",
+				DiscoveryPaths: []string{"results"},
+				PresignedURLs:  APIS3PresignedURLConfig{Expiry: "169h"},
+			},
+			wantErr:   true,
+			errSubstr: "must be between",
+		},
+		{
+			name: "multiple valid discovery paths",
 			s3Cfg: &APIS3Config{
 				Enabled:        true,
 				Bucket:         "my-bucket",
@@ -2477,6 +3065,463 @@ func TestValidateAPIIndexing(t *testing.T) {
 	}
 }
 
+func TestValidateAPIRuns(t *testing.T) {
+	baseConfig := filepath.Join(t.TempDir(), "base.yaml")
+	require.NoError(t, os.WriteFile(baseConfig, []byte("runner: {}\n"), 0o644))
+
+	tests := []struct {
+		name      string
+		runs      *APIRunsConfig
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name:    "nil runs config is valid",
+			runs:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "disabled runs is valid",
+			runs:    &APIRunsConfig{Enabled: false},
+			wantErr: false,
+		},
+		{
+			name: "valid runs config",
+			runs: &APIRunsConfig{
+				Enabled:     true,
+				BaseConfigs: []string{baseConfig},
+				Concurrency: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "missing base configs",
+			runs:      &APIRunsConfig{Enabled: true},
+			wantErr:   true,
+			errSubstr: "at least one base_config is required",
+		},
+		{
+			name: "base config does not exist",
+			runs: &APIRunsConfig{
+				Enabled:     true,
+				BaseConfigs: []string{"/nonexistent/base.yaml"},
+			},
+			wantErr:   true,
+			errSubstr: "api.runs.base_configs[0]",
+		},
+		{
+			name: "negative concurrency",
+			runs: &APIRunsConfig{
+				Enabled:     true,
+				BaseConfigs: []string{baseConfig},
+				Concurrency: -1,
+			},
+			wantErr:   true,
+			errSubstr: "api.runs.concurrency",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{API: &APIConfig{Runs: tt.runs}}
+			err := cfg.validateAPIRuns()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAPIRolePathAllowlist(t *testing.T) {
+	storage := APIStorageConfig{
+		S3: &APIS3Config{
+			Enabled:        true,
+			Bucket:         "my-bucket",
+			DiscoveryPaths: []string{"results", "archive/2024"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		allowlist     map[string][]string
+		anonymousRead bool
+		wantErr       bool
+		errSubstr     string
+	}{
+		{
+			name:      "empty allowlist is valid",
+			allowlist: nil,
+			wantErr:   false,
+		},
+		{
+			name:          "anonymous read is rejected",
+			allowlist:     map[string][]string{"readonly": {"results"}},
+			anonymousRead: true,
+			wantErr:       true,
+			errSubstr:     "cannot be combined with anonymous_read",
+		},
+		{
+			name:      "readonly restricted to a discovery path",
+			allowlist: map[string][]string{"readonly": {"results"}},
+			wantErr:   false,
+		},
+		{
+			name:      "trailing slash is ignored",
+			allowlist: map[string][]string{"readonly": {"archive/2024/"}},
+			wantErr:   false,
+		},
+		{
+			name:      "unknown role",
+			allowlist: map[string][]string{"viewer": {"results"}},
+			wantErr:   true,
+			errSubstr: "invalid role",
+		},
+		{
+			name:      "admin cannot be restricted",
+			allowlist: map[string][]string{"admin": {"results"}},
+			wantErr:   true,
+			errSubstr: "admin access cannot be restricted",
+		},
+		{
+			name:      "empty path list",
+			allowlist: map[string][]string{"readonly": {}},
+			wantErr:   true,
+			errSubstr: "at least one path is required",
+		},
+		{
+			name:      "unknown discovery path",
+			allowlist: map[string][]string{"readonly": {"other"}},
+			wantErr:   true,
+			errSubstr: "not a configured storage discovery path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{API: &APIConfig{
+				Auth: APIAuthConfig{
+					AnonymousRead:     tt.anonymousRead,
+					RolePathAllowlist: tt.allowlist,
+				},
+				Storage: storage,
+			}}
+			err := cfg.validateAPIRolePathAllowlist()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAPIGitHubTeamRoleMapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		mapping   map[string]string
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name:    "valid team mapping",
+			mapping: map[string]string{"my-org/core": "admin"},
+			wantErr: false,
+		},
+		{
+			name:      "missing team slug",
+			mapping:   map[string]string{"my-org": "admin"},
+			wantErr:   true,
+			errSubstr: "key must be in the form",
+		},
+		{
+			name:      "empty org",
+			mapping:   map[string]string{"/core": "admin"},
+			wantErr:   true,
+			errSubstr: "key must be in the form",
+		},
+		{
+			name:      "nested team path",
+			mapping:   map[string]string{"my-org/core/sub": "admin"},
+			wantErr:   true,
+			errSubstr: "key must be in the form",
+		},
+		{
+			name:      "invalid role",
+			mapping:   map[string]string{"my-org/core": "owner"},
+			wantErr:   true,
+			errSubstr: "invalid role",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{API: &APIConfig{
+				Database: APIDatabaseConfig{Driver: "sqlite"},
+				Auth: APIAuthConfig{
+					SessionTTL: "24h",
+					GitHub: GitHubAuthConfig{
+						Enabled:         true,
+						ClientID:        "id",
+						ClientSecret:    "secret",
+						RedirectURL:     "http://localhost/callback",
+						TeamRoleMapping: tt.mapping,
+					},
+				},
+			}}
+			err := cfg.ValidateAPI()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAPICORS(t *testing.T) {
+	tests := []struct {
+		name      string
+		cors      APICORSConfig
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name:    "no origins means same-origin only",
+			cors:    APICORSConfig{},
+			wantErr: false,
+		},
+		{
+			name: "valid origins",
+			cors: APICORSConfig{AllowedOrigins: []string{
+				"https://ui.example.com", "http://localhost:5173",
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "wildcard",
+			cors:    APICORSConfig{AllowedOrigins: []string{"*"}},
+			wantErr: false,
+		},
+		{
+			name: "wildcard mixed with origins",
+			cors: APICORSConfig{AllowedOrigins: []string{
+				"*", "https://ui.example.com",
+			}},
+			wantErr:   true,
+			errSubstr: "cannot be combined",
+		},
+		{
+			name:      "missing scheme",
+			cors:      APICORSConfig{AllowedOrigins: []string{"ui.example.com"}},
+			wantErr:   true,
+			errSubstr: "must be \"*\" or an http(s) URL",
+		},
+		{
+			name:      "unsupported scheme",
+			cors:      APICORSConfig{AllowedOrigins: []string{"ftp://ui.example.com"}},
+			wantErr:   true,
+			errSubstr: "must be \"*\" or an http(s) URL",
+		},
+		{
+			name: "origin with path",
+			cors: APICORSConfig{AllowedOrigins: []string{
+				"https://ui.example.com/app",
+			}},
+			wantErr:   true,
+			errSubstr: "must only contain a scheme, host and optional port",
+		},
+		{
+			name: "origin with trailing slash",
+			cors: APICORSConfig{AllowedOrigins: []string{
+				"https://ui.example.com/",
+			}},
+			wantErr:   true,
+			errSubstr: "must only contain a scheme, host and optional port",
+		},
+		{
+			name: "origin with empty query",
+			cors: APICORSConfig{AllowedOrigins: []string{
+				"https://ui.example.com?",
+			}},
+			wantErr:   true,
+			errSubstr: "must only contain a scheme, host and optional port",
+		},
+		{
+			name: "origin with credentials",
+			cors: APICORSConfig{AllowedOrigins: []string{
+				"https://user@ui.example.com",
+			}},
+			wantErr:   true,
+			errSubstr: "must only contain a scheme, host and optional port",
+		},
+		{
+			name:      "invalid method",
+			cors:      APICORSConfig{AllowedMethods: []string{"FETCH"}},
+			wantErr:   true,
+			errSubstr: "invalid method",
+		},
+		{
+			name:      "invalid header",
+			cors:      APICORSConfig{AllowedHeaders: []string{"X-A, X-B"}},
+			wantErr:   true,
+			errSubstr: "invalid header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{API: &APIConfig{
+				Server: APIServerConfig{CORS: tt.cors},
+			}}
+			err := cfg.validateAPICORS()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_APICORS(t *testing.T) {
+	t.Run("defaults to same-origin only", func(t *testing.T) {
+		cfg := Config{API: &APIConfig{}}
+		cfg.applyDefaults()
+
+		cors := cfg.API.Server.CORS
+		assert.Empty(t, cors.AllowedOrigins)
+		assert.Contains(t, cors.AllowedMethods, "POST")
+		assert.Contains(t, cors.AllowedHeaders, "Authorization")
+		require.NotNil(t, cors.AllowCredentials)
+		assert.True(t, *cors.AllowCredentials)
+		assert.Equal(t, 300, cors.MaxAge)
+	})
+
+	t.Run("deprecated cors_origins is honored", func(t *testing.T) {
+		cfg := Config{API: &APIConfig{Server: APIServerConfig{
+			CORSOrigins: []string{"https://ui.example.com"},
+		}}}
+		cfg.applyDefaults()
+
+		assert.Equal(t, []string{"https://ui.example.com"},
+			cfg.API.Server.CORS.AllowedOrigins)
+	})
+
+	t.Run("cors block takes precedence", func(t *testing.T) {
+		cfg := Config{API: &APIConfig{Server: APIServerConfig{
+			CORSOrigins: []string{"https://old.example.com"},
+			CORS: APICORSConfig{
+				AllowedOrigins: []string{"https://new.example.com"},
+			},
+		}}}
+		cfg.applyDefaults()
+
+		assert.Equal(t, []string{"https://new.example.com"},
+			cfg.API.Server.CORS.AllowedOrigins)
+	})
+}
+
+func TestValidateAPICompression(t *testing.T) {
+	valid := APICompressionConfig{
+		Level:        5,
+		MinSize:      1024,
+		ContentTypes: []string{"application/json", "text/plain"},
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(c *APICompressionConfig)
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name:    "valid",
+			modify:  func(_ *APICompressionConfig) {},
+			wantErr: false,
+		},
+		{
+			name:      "level too low",
+			modify:    func(c *APICompressionConfig) { c.Level = -1 },
+			wantErr:   true,
+			errSubstr: "must be between 1 and 9",
+		},
+		{
+			name:      "level too high",
+			modify:    func(c *APICompressionConfig) { c.Level = 10 },
+			wantErr:   true,
+			errSubstr: "must be between 1 and 9",
+		},
+		{
+			name:      "negative min size",
+			modify:    func(c *APICompressionConfig) { c.MinSize = -1 },
+			wantErr:   true,
+			errSubstr: "min_size: must not be negative",
+		},
+		{
+			name: "invalid media type",
+			modify: func(c *APICompressionConfig) {
+				c.ContentTypes = []string{"json"}
+			},
+			wantErr:   true,
+			errSubstr: "invalid media type",
+		},
+		{
+			name: "media type with parameters",
+			modify: func(c *APICompressionConfig) {
+				c.ContentTypes = []string{"text/plain; charset=utf-8"}
+			},
+			wantErr:   true,
+			errSubstr: "invalid media type",
+		},
+		{
+			name: "event stream",
+			modify: func(c *APICompressionConfig) {
+				c.ContentTypes = []string{"text/event-stream"}
+			},
+			wantErr:   true,
+			errSubstr: "streaming responses cannot be compressed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compression := valid
+			compression.ContentTypes = append([]string(nil), valid.ContentTypes...)
+			tt.modify(&compression)
+
+			cfg := Config{API: &APIConfig{
+				Server: APIServerConfig{Compression: compression},
+			}}
+			err := cfg.validateAPICompression()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_APICompression(t *testing.T) {
+	cfg := Config{API: &APIConfig{}}
+	cfg.applyDefaults()
+
+	compression := cfg.API.Server.Compression
+	require.NotNil(t, compression.Enabled)
+	assert.True(t, *compression.Enabled)
+	assert.Equal(t, 5, compression.Level)
+	assert.Equal(t, 1024, compression.MinSize)
+	assert.Contains(t, compression.ContentTypes, "application/json")
+	assert.NoError(t, cfg.validateAPICompression())
+}
+
 func TestGetRunTimeout(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -2650,3 +3695,1910 @@ func TestValidateRunTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateImageDigest(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name   string
+		digest string
+		want   bool
+	}{
+		{name: "valid", digest: digest, want: true},
+		{name: "uppercase prefix", digest: "SHA256:" + digest[7:], want: false},
+		{name: "uppercase hash", digest: "sha256:0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF", want: true},
+		{name: "missing prefix", digest: digest[7:], want: false},
+		{name: "other algorithm", digest: "sha512:" + digest[7:], want: false},
+		{name: "too short", digest: digest[:len(digest)-1], want: false},
+		{name: "non-hex", digest: digest[:len(digest)-1] + "z", want: false},
+		{name: "image reference", digest: "ethpandaops/geth@" + digest, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isValidImageDigest(tt.digest))
+		})
+	}
+
+	t.Run("rejected by Validate", func(t *testing.T) {
+		cfg := &Config{
+			Runner: RunnerConfig{
+				Instances: []ClientInstance{
+					{ID: "geth", Client: "geth", ImageDigest: "latest"},
+				},
+			},
+		}
+
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "image_digest")
+	})
+}
+
+func TestValidate_RemoveDefaultArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		field     string
+		errSubstr string
+	}{
+		{name: "flag prefixes", args: []string{"--syncmode", "--http."}},
+		{
+			name:      "not a flag",
+			args:      []string{"--syncmode", "node"},
+			field:     "runner.instances[0].remove_default_args[1]",
+			errSubstr: `remove_default_args entry "node"`,
+		},
+		{
+			name:      "empty entry",
+			args:      []string{""},
+			field:     "runner.instances[0].remove_default_args[0]",
+			errSubstr: "must be a flag prefix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Runner: RunnerConfig{
+					Instances: []ClientInstance{
+						{ID: "geth", Client: "geth", RemoveDefaultArgs: tt.args},
+					},
+				},
+			}
+
+			result := cfg.ValidateAll()
+
+			if tt.errSubstr == "" {
+				for _, e := range result.Errors {
+					assert.NotContains(t, e.Field, "remove_default_args")
+				}
+
+				return
+			}
+
+			require.Len(t, result.Errors, 1)
+			assert.Equal(t, tt.field, result.Errors[0].Field)
+			assert.Contains(t, result.Errors[0].Message, tt.errSubstr)
+		})
+	}
+}
+
+func TestGetGenesisMirrors(t *testing.T) {
+	cfg := &Config{
+		Runner: RunnerConfig{
+			Client: ClientConfig{
+				Config: ClientDefaults{
+					GenesisMirrors: map[string][]string{
+						"geth": {"https://mirror.example.com/geth.json"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		instance ClientInstance
+		want     []string
+	}{
+		{
+			name:     "global mirrors back the global genesis",
+			instance: ClientInstance{Client: "geth"},
+			want:     []string{"https://mirror.example.com/geth.json"},
+		},
+		{
+			name:     "no mirrors for other clients",
+			instance: ClientInstance{Client: "reth"},
+			want:     nil,
+		},
+		{
+			name: "instance genesis override drops global mirrors",
+			instance: ClientInstance{
+				Client:  "geth",
+				Genesis: "https://example.com/custom.json",
+			},
+			want: nil,
+		},
+		{
+			name: "instance mirrors take precedence",
+			instance: ClientInstance{
+				Client:         "geth",
+				Genesis:        "https://example.com/custom.json",
+				GenesisMirrors: []string{"https://backup.example.com/custom.json"},
+			},
+			want: []string{"https://backup.example.com/custom.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cfg.GetGenesisMirrors(&tt.instance))
+		})
+	}
+}
+
+func TestValidateGenesisMirrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name: "invalid instance mirror",
+			cfg: Config{Runner: RunnerConfig{
+				Instances: []ClientInstance{{
+					ID:             "geth",
+					Client:         "geth",
+					GenesisMirrors: []string{"/tmp/genesis.json"},
+				}},
+			}},
+			wantErr:   true,
+			errSubstr: `instance "geth" genesis_mirrors[0]`,
+		},
+		{
+			name: "invalid global mirror",
+			cfg: Config{Runner: RunnerConfig{
+				Client: ClientConfig{Config: ClientDefaults{
+					GenesisMirrors: map[string][]string{
+						"geth": {"ftp://mirror.example.com/geth.json"},
+					},
+				}},
+				Instances: []ClientInstance{{ID: "geth", Client: "geth"}},
+			}},
+			wantErr:   true,
+			errSubstr: "runner.client.config.genesis_mirrors.geth[0]",
+		},
+		{
+			name: "eest mirrors without release",
+			cfg: Config{Runner: RunnerConfig{
+				Instances: []ClientInstance{{ID: "geth", Client: "geth"}},
+				Benchmark: BenchmarkConfig{Tests: TestsConfig{Source: SourceConfig{
+					EESTFixtures: &EESTFixturesSource{
+						GitHubRepo:           "ethereum/execution-spec-tests",
+						FixturesArtifactName: "fixtures_benchmark",
+						FixturesMirrors:      []string{"https://mirror.example.com/f.tar.gz"},
+					},
+				}}},
+			}},
+			wantErr:   true,
+			errSubstr: "require github_release",
+		},
+		{
+			name: "invalid eest mirror",
+			cfg: Config{Runner: RunnerConfig{
+				Instances: []ClientInstance{{ID: "geth", Client: "geth"}},
+				Benchmark: BenchmarkConfig{Tests: TestsConfig{Source: SourceConfig{
+					EESTFixtures: &EESTFixturesSource{
+						GitHubRepo:     "ethereum/execution-spec-tests",
+						GitHubRelease:  "benchmark@v0.0.1",
+						GenesisMirrors: []string{"mirror.example.com/g.tar.gz"},
+					},
+				}}},
+			}},
+			wantErr:   true,
+			errSubstr: "eest_fixtures.genesis_mirrors[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDownloadRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		retries   *DownloadRetryConfig
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name:    "unset",
+			retries: nil,
+		},
+		{
+			name:    "valid",
+			retries: &DownloadRetryConfig{MaxAttempts: 5, Backoff: "1s", MaxBackoff: "1m"},
+		},
+		{
+			name:      "negative attempts",
+			retries:   &DownloadRetryConfig{MaxAttempts: -1},
+			wantErr:   true,
+			errSubstr: "max_attempts must not be negative",
+		},
+		{
+			name:      "invalid backoff",
+			retries:   &DownloadRetryConfig{MaxAttempts: 3, Backoff: "soon"},
+			wantErr:   true,
+			errSubstr: "invalid runner.download_retries.backoff",
+		},
+		{
+			name:      "invalid max backoff",
+			retries:   &DownloadRetryConfig{MaxAttempts: 3, MaxBackoff: "later"},
+			wantErr:   true,
+			errSubstr: "invalid runner.download_retries.max_backoff",
+		},
+		{
+			name:      "negative backoff",
+			retries:   &DownloadRetryConfig{MaxAttempts: 3, Backoff: "-1s"},
+			wantErr:   true,
+			errSubstr: "must not be negative",
+		},
+		{
+			name:      "max backoff below backoff",
+			retries:   &DownloadRetryConfig{MaxAttempts: 3, Backoff: "10s", MaxBackoff: "1s"},
+			wantErr:   true,
+			errSubstr: "must not be less than backoff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Runner: RunnerConfig{DownloadRetries: tt.retries}}
+
+			err := cfg.validateDownloadRetries()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetDownloadRetries(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.applyDefaults()
+
+		attempts, backoff, maxBackoff := cfg.GetDownloadRetries()
+		assert.Equal(t, DefaultDownloadMaxAttempts, attempts)
+		assert.Equal(t, 2*time.Second, backoff)
+		assert.Equal(t, 30*time.Second, maxBackoff)
+	})
+
+	t.Run("partial config keeps explicit values", func(t *testing.T) {
+		cfg := &Config{Runner: RunnerConfig{
+			DownloadRetries: &DownloadRetryConfig{MaxAttempts: 1},
+		}}
+		cfg.applyDefaults()
+
+		attempts, backoff, _ := cfg.GetDownloadRetries()
+		assert.Equal(t, 1, attempts)
+		assert.Equal(t, 2*time.Second, backoff)
+	})
+
+	t.Run("unset tries once", func(t *testing.T) {
+		cfg := &Config{}
+
+		attempts, backoff, maxBackoff := cfg.GetDownloadRetries()
+		assert.Equal(t, 1, attempts)
+		assert.Zero(t, backoff)
+		assert.Zero(t, maxBackoff)
+	})
+}
+
+func TestLoad_FromURL(t *testing.T) {
+	remote := `
+global:
+  log_level: ${REMOTE_LOG_LEVEL:-info}
+runner:
+  container_network: remote-network
+  instances:
+    - id: remote-instance
+      client: geth
+`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.yaml":
+			_, _ = w.Write([]byte(remote))
+		case "/error.html":
+			_, _ = w.Write([]byte("<html><body>Service Unavailable</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("expands env vars after download", func(t *testing.T) {
+		t.Setenv("REMOTE_LOG_LEVEL", "debug")
+
+		cfg, err := Load(srv.URL + "/config.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "debug", cfg.Global.LogLevel)
+		assert.Equal(t, "remote-network", cfg.Runner.ContainerNetwork)
+		require.Len(t, cfg.Runner.Instances, 1)
+		assert.Equal(t, "remote-instance", cfg.Runner.Instances[0].ID)
+	})
+
+	t.Run("merges with local files in order", func(t *testing.T) {
+		override := filepath.Join(t.TempDir(), "override.yaml")
+		require.NoError(t, os.WriteFile(override,
+			[]byte("runner:\n  container_network: local-network\n"), 0o644))
+
+		cfg, err := Load(srv.URL+"/config.yaml", override)
+		require.NoError(t, err)
+		assert.Equal(t, "local-network", cfg.Runner.ContainerNetwork)
+		assert.Equal(t, "info", cfg.Global.LogLevel)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := Load(srv.URL + "/missing.yaml")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected status code: 404")
+	})
+
+	t.Run("rejects non-YAML content", func(t *testing.T) {
+		_, err := Load(srv.URL + "/error.html")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a valid YAML document")
+	})
+}
+
+func TestParseEnvFile(t *testing.T) {
+	content := `
+# A comment line
+PLAIN=value
+export EXPORTED=yes
+SPACED = padded value   # trailing comment
+HASH_IN_VALUE=abc#def
+SINGLE='literal $HOME \n # not a comment'
+DOUBLE="line1\nline2 \"quoted\" \$HOME" # comment
+EMPTY=
+`
+	vars, err := parseEnvFile(strings.NewReader(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, [][2]string{
+		{"PLAIN", "value"},
+		{"EXPORTED", "yes"},
+		{"SPACED", "padded value"},
+		{"HASH_IN_VALUE", "abc#def"},
+		{"SINGLE", `literal $HOME \n # not a comment`},
+		{"DOUBLE", "line1\nline2 \"quoted\" $HOME"},
+		{"EMPTY", ""},
+	}, vars)
+}
+
+func TestParseEnvFile_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		errSubstr string
+	}{
+		{name: "missing equals", content: "NOVALUE", errSubstr: "line 1: expected KEY=VALUE"},
+		{name: "invalid key", content: "1BAD=x", errSubstr: `invalid variable name "1BAD"`},
+		{name: "unterminated quote", content: `A="open`, errSubstr: "unterminated"},
+		{name: "content after quote", content: `A="x" y`, errSubstr: "after closing quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseEnvFile(strings.NewReader(tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstr)
+		})
+	}
+}
+
+func TestLoadEnvFile_UsedForExpansion(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	envPath := filepath.Join(tmpDir, ".env")
+	require.NoError(t, os.WriteFile(envPath, []byte(
+		"BMK_TEST_NETWORK=\"from-env-file\"\nBMK_TEST_LOG_LEVEL=debug\n",
+	), 0o644))
+
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+global:
+  log_level: ${BMK_TEST_LOG_LEVEL}
+runner:
+  container_network: ${BMK_TEST_NETWORK}
+`), 0o644))
+
+	// Register cleanup for the variables the env file sets, and keep an
+	// ambient value that must win over the file.
+	t.Setenv("BMK_TEST_NETWORK", "")
+	require.NoError(t, os.Unsetenv("BMK_TEST_NETWORK"))
+	t.Setenv("BMK_TEST_LOG_LEVEL", "warn")
+
+	require.NoError(t, LoadEnvFile(envPath))
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "from-env-file", cfg.Runner.ContainerNetwork)
+	assert.Equal(t, "warn", cfg.Global.LogLevel)
+}
+
+func TestLoadEnvFile_NotFound(t *testing.T) {
+	err := LoadEnvFile(filepath.Join(t.TempDir(), "missing.env"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "opening env file")
+}
+
+func TestGetIsolateNetwork(t *testing.T) {
+	enabled, disabled := true, false
+
+	cfg := &Config{}
+	assert.Nil(t, cfg.GetIsolateNetwork(&ClientInstance{}))
+
+	cfg.Runner.Client.Config.IsolateNetwork = &disabled
+	assert.Equal(t, &disabled, cfg.GetIsolateNetwork(&ClientInstance{}))
+	assert.Equal(t, &enabled, cfg.GetIsolateNetwork(&ClientInstance{IsolateNetwork: &enabled}))
+}
+
+func TestValidateFCUKeepaliveInterval(t *testing.T) {
+	tests := []struct {
+		name      string
+		global    string
+		instance  string
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "unset"},
+		{name: "global valid", global: "12s"},
+		{name: "instance valid", instance: "500ms"},
+		{
+			name:      "invalid",
+			instance:  "often",
+			wantErr:   true,
+			errSubstr: "invalid fcu_keepalive_interval",
+		},
+		{
+			name:      "zero",
+			global:    "0s",
+			wantErr:   true,
+			errSubstr: "must be positive",
+		},
+		{
+			name:      "negative",
+			instance:  "-5s",
+			wantErr:   true,
+			errSubstr: "must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Runner.Client.Config.FCUKeepaliveInterval = tt.global
+			cfg.Runner.Instances = []ClientInstance{
+				{ID: "geth-1", Client: "geth", FCUKeepaliveInterval: tt.instance},
+			}
+
+			err := cfg.validateFCUKeepaliveInterval()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGetFCUKeepaliveInterval(t *testing.T) {
+	cfg := &Config{}
+	assert.Zero(t, cfg.GetFCUKeepaliveInterval(&ClientInstance{}))
+
+	cfg.Runner.Client.Config.FCUKeepaliveInterval = "12s"
+	assert.Equal(t, 12*time.Second, cfg.GetFCUKeepaliveInterval(&ClientInstance{}))
+	assert.Equal(t, 3*time.Second,
+		cfg.GetFCUKeepaliveInterval(&ClientInstance{FCUKeepaliveInterval: "3s"}))
+}
+
+func TestValidateTransparentHugepage(t *testing.T) {
+	fakeSysfs := func(t *testing.T) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "enabled"), []byte("always [madvise] never\n"), 0644))
+
+		return dir
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Runner.THPSysfsPath = t.TempDir()
+		cfg.Runner.Instances = []ClientInstance{{ID: "geth-1", Client: "geth"}}
+
+		require.NoError(t, cfg.validateTransparentHugepage())
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.Runner.Client.Config.ResourceLimits = &ResourceLimits{TransparentHugepage: "sometimes"}
+		cfg.Runner.Instances = []ClientInstance{{ID: "geth-1", Client: "geth"}}
+
+		err := cfg.validateTransparentHugepage()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid transparent_hugepage")
+	})
+
+	t.Run("valid mode", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			t.Skip("transparent_hugepage is Linux-only")
+		}
+
+		cfg := &Config{}
+		cfg.Runner.THPSysfsPath = fakeSysfs(t)
+		cfg.Runner.Instances = []ClientInstance{{
+			ID: "geth-1", Client: "geth",
+			ResourceLimits: &ResourceLimits{TransparentHugepage: "never"},
+		}}
+
+		require.NoError(t, cfg.validateTransparentHugepage())
+	})
+
+	t.Run("thp unavailable", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			t.Skip("transparent_hugepage is Linux-only")
+		}
+
+		cfg := &Config{}
+		cfg.Runner.THPSysfsPath = t.TempDir()
+		cfg.Runner.Client.Config.ResourceLimits = &ResourceLimits{TransparentHugepage: "never"}
+		cfg.Runner.Instances = []ClientInstance{{ID: "geth-1", Client: "geth"}}
+
+		err := cfg.validateTransparentHugepage()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "THP not available")
+	})
+}
+
+func TestValidateGenesisGroupParallelism(t *testing.T) {
+	tests := []struct {
+		name        string
+		parallelism int
+		limits      *ResourceLimits
+		wantErr     string
+	}{
+		{name: "unset"},
+		{name: "parallel", parallelism: 4},
+		{name: "sequential with cpu_freq", parallelism: 1, limits: &ResourceLimits{CPUFreq: "2000MHz"}},
+		{name: "negative", parallelism: -1, wantErr: "must be >= 0"},
+		{
+			name:        "parallel with cpu_freq",
+			parallelism: 2,
+			limits:      &ResourceLimits{CPUGovernor: "performance"},
+			wantErr:     `instance "geth-1": genesis_group_parallelism > 1 cannot be combined`,
+		},
+		{
+			name:        "parallel with transparent_hugepage",
+			parallelism: 2,
+			limits:      &ResourceLimits{TransparentHugepage: "never"},
+			wantErr:     `instance "geth-1": genesis_group_parallelism > 1 cannot be combined`,
+		},
+		{
+			name:        "parallel with irq_affinity move",
+			parallelism: 2,
+			limits:      &ResourceLimits{IRQAffinity: IRQAffinityMove},
+			wantErr:     `instance "geth-1": genesis_group_parallelism > 1 cannot be combined`,
+		},
+		{
+			name:        "parallel with irq_affinity check",
+			parallelism: 2,
+			limits:      &ResourceLimits{IRQAffinity: IRQAffinityCheck},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Runner.Benchmark.Tests.GenesisGroupParallelism = tt.parallelism
+			cfg.Runner.Instances = []ClientInstance{{ID: "geth-1", Client: "geth", ResourceLimits: tt.limits}}
+
+			err := cfg.validateGenesisGroupParallelism()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateBlkioAutoDevice(t *testing.T) {
+	autoLimits := &ResourceLimits{BlkioConfig: &BlkioConfig{
+		DeviceWriteBps: []ThrottleDevice{{Path: BlkioDeviceAuto, Rate: "100mb"}},
+	}}
+	fixedLimits := &ResourceLimits{BlkioConfig: &BlkioConfig{
+		DeviceWriteBps: []ThrottleDevice{{Path: "/dev/sdb", Rate: "100mb"}},
+	}}
+
+	tests := []struct {
+		name     string
+		limits   *ResourceLimits
+		datadirs map[string]*DataDirConfig
+		wantErr  bool
+	}{
+		{name: "fixed device", limits: fixedLimits},
+		{name: "auto without datadir", limits: autoLimits, wantErr: true},
+		{
+			name:     "auto with datadir",
+			limits:   autoLimits,
+			datadirs: map[string]*DataDirConfig{"geth": {SourceDir: "/data/geth"}},
+		},
+		{
+			name:     "auto with datadir of another client",
+			limits:   autoLimits,
+			datadirs: map[string]*DataDirConfig{"reth": {SourceDir: "/data/reth"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Runner.Client.Config.ResourceLimits = tt.limits
+			cfg.Runner.Client.DataDirs = tt.datadirs
+			cfg.Runner.Instances = []ClientInstance{{ID: "geth-1", Client: "geth"}}
+
+			err := cfg.validateBlkioAutoDevice()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "requires a datadir")
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBlkioConfigValidate_Weight(t *testing.T) {
+	tests := []struct {
+		name      string
+		blkio     BlkioConfig
+		errSubstr string
+	}{
+		{name: "unset", blkio: BlkioConfig{}},
+		{name: "minimum weight", blkio: BlkioConfig{Weight: MinBlkioWeight}},
+		{name: "maximum weight", blkio: BlkioConfig{Weight: MaxBlkioWeight}},
+		{name: "weight too low", blkio: BlkioConfig{Weight: 9}, errSubstr: "blkio_config.weight: 9 must be between 10 and 1000"},
+		{name: "weight too high", blkio: BlkioConfig{Weight: 1001}, errSubstr: "must be between 10 and 1000"},
+		{
+			name:  "device weight",
+			blkio: BlkioConfig{DeviceWeight: []WeightDevice{{Path: "/dev/sdb", Weight: 500}}},
+		},
+		{
+			name:      "device weight without path",
+			blkio:     BlkioConfig{DeviceWeight: []WeightDevice{{Weight: 500}}},
+			errSubstr: "device_weight[0]: path is required",
+		},
+		{
+			name:      "device weight unset",
+			blkio:     BlkioConfig{DeviceWeight: []WeightDevice{{Path: "/dev/sdb"}}},
+			errSubstr: "device_weight[0].weight: 0 must be between",
+		},
+		{
+			name:      "device weight too high",
+			blkio:     BlkioConfig{DeviceWeight: []WeightDevice{{Path: "/dev/sdb", Weight: 2000}}},
+			errSubstr: "device_weight[0].weight: 2000 must be between",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.blkio.Validate("blkio_config")
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("auto device weight", func(t *testing.T) {
+		blkio := &BlkioConfig{DeviceWeight: []WeightDevice{{Path: BlkioDeviceAuto, Weight: 100}}}
+		assert.True(t, blkio.UsesAutoDevice())
+	})
+}
+
+func TestResourceLimitsValidate_IRQAffinity(t *testing.T) {
+	count := 1
+
+	tests := []struct {
+		name      string
+		limits    ResourceLimits
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "unset", limits: ResourceLimits{}},
+		{name: "check with cpuset", limits: ResourceLimits{Cpuset: []int{0}, IRQAffinity: IRQAffinityCheck}},
+		{name: "move with cpuset_count", limits: ResourceLimits{CpusetCount: &count, IRQAffinity: IRQAffinityMove}},
+		{
+			name:      "without cpuset",
+			limits:    ResourceLimits{IRQAffinity: IRQAffinityCheck},
+			wantErr:   true,
+			errSubstr: "requires cpuset",
+		},
+		{
+			name:      "invalid mode",
+			limits:    ResourceLimits{Cpuset: []int{0}, IRQAffinity: "pin"},
+			wantErr:   true,
+			errSubstr: "invalid irq_affinity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS != "linux" && tt.limits.IRQAffinity != "" && !tt.wantErr {
+				t.Skip("irq_affinity is Linux-only")
+			}
+
+			err := tt.limits.Validate("resource_limits")
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseNanoCPUs(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "1", want: 1_000_000_000},
+		{input: "2.5", want: 2_500_000_000},
+		{input: "0.25", want: 250_000_000},
+		{input: " 1.5 ", want: 1_500_000_000},
+		{input: "0", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "NaN", wantErr: true},
+		{input: "two", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseNanoCPUs(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResourceLimitsValidate_CPUBandwidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    ResourceLimits
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "fractional cpus", limits: ResourceLimits{CPUs: "0.5"}},
+		{name: "quota only", limits: ResourceLimits{CPUQuota: 50000}},
+		{name: "quota and period", limits: ResourceLimits{CPUQuota: 25000, CPUPeriod: 50000}},
+		{
+			name:      "cpus and quota",
+			limits:    ResourceLimits{CPUs: "0.5", CPUQuota: 50000},
+			wantErr:   true,
+			errSubstr: "mutually exclusive",
+		},
+		{
+			name:      "invalid cpus",
+			limits:    ResourceLimits{CPUs: "half"},
+			wantErr:   true,
+			errSubstr: "invalid cpus",
+		},
+		{
+			name:      "cpus exceed cpuset",
+			limits:    ResourceLimits{Cpuset: []int{0}, CPUs: "1.5"},
+			wantErr:   true,
+			errSubstr: "exceeds available CPUs",
+		},
+		{
+			name:      "period without quota",
+			limits:    ResourceLimits{CPUPeriod: 100000},
+			wantErr:   true,
+			errSubstr: "requires cpu_quota",
+		},
+		{
+			name:      "period out of range",
+			limits:    ResourceLimits{CPUQuota: 50000, CPUPeriod: 500},
+			wantErr:   true,
+			errSubstr: "cpu_period must be between",
+		},
+		{
+			name:      "quota too small",
+			limits:    ResourceLimits{CPUQuota: 10},
+			wantErr:   true,
+			errSubstr: "cpu_quota must be at least",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Validate("resource_limits")
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestResourceLimitsValidate_MemoryReservation(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    ResourceLimits
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "reservation only", limits: ResourceLimits{MemoryReservation: "4g"}},
+		{name: "below memory", limits: ResourceLimits{Memory: "16g", MemoryReservation: "12g"}},
+		{name: "equal to memory", limits: ResourceLimits{Memory: "16g", MemoryReservation: "16384m"}},
+		{
+			name:      "above memory",
+			limits:    ResourceLimits{Memory: "8g", MemoryReservation: "12g"},
+			wantErr:   true,
+			errSubstr: "must not exceed memory",
+		},
+		{
+			name:      "invalid format",
+			limits:    ResourceLimits{MemoryReservation: "lots"},
+			wantErr:   true,
+			errSubstr: "invalid memory_reservation",
+		},
+		{
+			name:      "zero",
+			limits:    ResourceLimits{MemoryReservation: "0"},
+			wantErr:   true,
+			errSubstr: "greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Validate("resource_limits")
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDeviceValidate(t *testing.T) {
+	if _, err := os.Stat("/dev/null"); err != nil {
+		t.Skip("/dev/null not available")
+	}
+
+	regularFile := filepath.Join(t.TempDir(), "disk.img")
+	require.NoError(t, os.WriteFile(regularFile, nil, 0o644))
+
+	tests := []struct {
+		name      string
+		device    Device
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "host path only", device: Device{HostPath: "/dev/null"}},
+		{name: "full mapping", device: Device{HostPath: "/dev/null", ContainerPath: "/dev/bench", Permissions: "rw"}},
+		{name: "missing host path", device: Device{}, wantErr: true, errSubstr: "host_path is required"},
+		{name: "relative host path", device: Device{HostPath: "dev/null"}, wantErr: true, errSubstr: "must be absolute"},
+		{
+			name:      "relative container path",
+			device:    Device{HostPath: "/dev/null", ContainerPath: "bench"},
+			wantErr:   true,
+			errSubstr: "must be absolute",
+		},
+		{
+			name:      "invalid permissions",
+			device:    Device{HostPath: "/dev/null", Permissions: "rx"},
+			wantErr:   true,
+			errSubstr: "invalid permissions",
+		},
+		{
+			name:      "duplicate permissions",
+			device:    Device{HostPath: "/dev/null", Permissions: "rr"},
+			wantErr:   true,
+			errSubstr: "duplicate permission",
+		},
+		{
+			name:      "nonexistent device",
+			device:    Device{HostPath: "/dev/benchmarkoor-does-not-exist"},
+			wantErr:   true,
+			errSubstr: "host device",
+		},
+		{
+			name:      "not a device",
+			device:    Device{HostPath: regularFile},
+			wantErr:   true,
+			errSubstr: "is not a device",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.device.Validate("devices[0]")
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSecretFileValidate(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("s3cret"), 0o600))
+	t.Setenv("TEST_SECRET_FILE_VALUE", "s3cret")
+
+	tests := []struct {
+		name      string
+		secret    SecretFile
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "file source", secret: SecretFile{ContainerPath: "/secrets/token", File: secretFile}},
+		{name: "env source", secret: SecretFile{ContainerPath: "/secrets/token", Env: "TEST_SECRET_FILE_VALUE"}},
+		{name: "missing container path", secret: SecretFile{File: secretFile}, wantErr: true, errSubstr: "container_path is required"},
+		{
+			name:      "relative container path",
+			secret:    SecretFile{ContainerPath: "secrets/token", File: secretFile},
+			wantErr:   true,
+			errSubstr: "must be absolute",
+		},
+		{
+			name:      "no source",
+			secret:    SecretFile{ContainerPath: "/secrets/token"},
+			wantErr:   true,
+			errSubstr: "one of file or env is required",
+		},
+		{
+			name:      "both sources",
+			secret:    SecretFile{ContainerPath: "/secrets/token", File: secretFile, Env: "TEST_SECRET_FILE_VALUE"},
+			wantErr:   true,
+			errSubstr: "mutually exclusive",
+		},
+		{
+			name:      "relative file",
+			secret:    SecretFile{ContainerPath: "/secrets/token", File: "token"},
+			wantErr:   true,
+			errSubstr: "must be absolute",
+		},
+		{
+			name:      "missing file",
+			secret:    SecretFile{ContainerPath: "/secrets/token", File: secretFile + ".missing"},
+			wantErr:   true,
+			errSubstr: "no such file",
+		},
+		{
+			name:      "directory",
+			secret:    SecretFile{ContainerPath: "/secrets/token", File: t.TempDir()},
+			wantErr:   true,
+			errSubstr: "is not a regular file",
+		},
+		{
+			name:      "unset env",
+			secret:    SecretFile{ContainerPath: "/secrets/token", Env: "TEST_SECRET_FILE_UNSET"},
+			wantErr:   true,
+			errSubstr: "TEST_SECRET_FILE_UNSET is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.secret.Validate("secret_files[0]")
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRedactEnv(t *testing.T) {
+	env := map[string]string{
+		"GITHUB_TOKEN":  "t",
+		"AwsSecretKey":  "s",
+		"DB_PASSWORD":   "p",
+		"RUST_LOG":      "info",
+		"TOKENIZER_URL": "u",
+	}
+
+	assert.Equal(t, map[string]string{
+		"GITHUB_TOKEN":  RedactedValue,
+		"AwsSecretKey":  RedactedValue,
+		"DB_PASSWORD":   RedactedValue,
+		"RUST_LOG":      "info",
+		"TOKENIZER_URL": RedactedValue,
+	}, RedactEnv(env, DefaultRedactEnvPatterns))
+	assert.Equal(t, "t", env["GITHUB_TOKEN"], "input is not modified")
+
+	assert.Equal(t, map[string]string{
+		"GITHUB_TOKEN":  "t",
+		"AwsSecretKey":  "s",
+		"DB_PASSWORD":   "p",
+		"RUST_LOG":      RedactedValue,
+		"TOKENIZER_URL": "u",
+	}, RedactEnv(env, []string{"rust_*"}))
+
+	assert.Nil(t, RedactEnv(nil, DefaultRedactEnvPatterns))
+}
+
+func TestValidateRedactEnv(t *testing.T) {
+	cfg := &Config{}
+	assert.Equal(t, DefaultRedactEnvPatterns, cfg.GetRedactEnvPatterns())
+	require.NoError(t, cfg.validateRedactEnv())
+
+	cfg.Runner.RedactEnv = []string{"*_KEY", "API_?"}
+	assert.Equal(t, []string{"*_KEY", "API_?"}, cfg.GetRedactEnvPatterns())
+	require.NoError(t, cfg.validateRedactEnv())
+
+	cfg.Runner.RedactEnv = []string{"[KEY"}
+	require.ErrorContains(t, cfg.validateRedactEnv(), "invalid pattern")
+
+	cfg.Runner.RedactEnv = []string{""}
+	require.ErrorContains(t, cfg.validateRedactEnv(), "must not be empty")
+}
+
+func TestDeviceDefaults(t *testing.T) {
+	d := Device{HostPath: "/dev/nvme1n1"}
+	assert.Equal(t, "/dev/nvme1n1", d.GetContainerPath())
+	assert.Equal(t, DefaultDevicePermissions, d.GetPermissions())
+
+	d = Device{HostPath: "/dev/nvme1n1", ContainerPath: "/dev/bench", Permissions: "r"}
+	assert.Equal(t, "/dev/bench", d.GetContainerPath())
+	assert.Equal(t, "r", d.GetPermissions())
+}
+
+func TestValidateDiskBenchmark(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *DiskBenchmarkConfig
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "unset"},
+		{name: "disabled with invalid values", cfg: &DiskBenchmarkConfig{Tool: "dd", Size: "tiny"}},
+		{name: "defaults", cfg: &DiskBenchmarkConfig{Enabled: true}},
+		{name: "internal", cfg: &DiskBenchmarkConfig{Enabled: true, Tool: "internal", Size: "64m", Duration: "5s"}},
+		{
+			name:      "invalid tool",
+			cfg:       &DiskBenchmarkConfig{Enabled: true, Tool: "dd"},
+			wantErr:   true,
+			errSubstr: "invalid value",
+		},
+		{
+			name:      "invalid size",
+			cfg:       &DiskBenchmarkConfig{Enabled: true, Size: "tiny"},
+			wantErr:   true,
+			errSubstr: "runner.disk_benchmark.size",
+		},
+		{
+			name:      "size too small",
+			cfg:       &DiskBenchmarkConfig{Enabled: true, Size: "4k"},
+			wantErr:   true,
+			errSubstr: "must be at least",
+		},
+		{
+			name:      "duration too short",
+			cfg:       &DiskBenchmarkConfig{Enabled: true, Duration: "100ms"},
+			wantErr:   true,
+			errSubstr: "must be at least 1s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Runner: RunnerConfig{DiskBenchmark: tt.cfg}}
+
+			err := cfg.validateDiskBenchmark()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestApplyDefaults_DiskBenchmark(t *testing.T) {
+	cfg := &Config{Runner: RunnerConfig{DiskBenchmark: &DiskBenchmarkConfig{Enabled: true}}}
+	cfg.applyDefaults()
+
+	assert.Equal(t, "auto", cfg.Runner.DiskBenchmark.Tool)
+	assert.Equal(t, DefaultDiskBenchmarkSize, cfg.Runner.DiskBenchmark.Size)
+	assert.Equal(t, DefaultDiskBenchmarkDuration, cfg.Runner.DiskBenchmark.Duration)
+
+	cfg = &Config{}
+	cfg.applyDefaults()
+	assert.Nil(t, cfg.Runner.DiskBenchmark)
+}
+
+func TestGetPrimePageCache(t *testing.T) {
+	enabled, disabled := true, false
+
+	cfg := &Config{}
+	assert.False(t, cfg.GetPrimePageCache(&ClientInstance{}))
+
+	cfg.Runner.Client.Config.PrimePageCache = &enabled
+	assert.True(t, cfg.GetPrimePageCache(&ClientInstance{}))
+	assert.False(t, cfg.GetPrimePageCache(&ClientInstance{PrimePageCache: &disabled}))
+}
+
+func TestGetSyncBetweenTests(t *testing.T) {
+	enabled, disabled := true, false
+
+	cfg := &Config{}
+	assert.False(t, cfg.GetSyncBetweenTests(&ClientInstance{}))
+
+	cfg.Runner.Client.Config.SyncBetweenTests = &enabled
+	assert.True(t, cfg.GetSyncBetweenTests(&ClientInstance{}))
+	assert.False(t, cfg.GetSyncBetweenTests(&ClientInstance{SyncBetweenTests: &disabled}))
+}
+
+func TestValidateDropMemoryCaches_PrimePageCache(t *testing.T) {
+	enabled := true
+
+	cfg := &Config{
+		Runner: RunnerConfig{
+			Instances: []ClientInstance{
+				{ID: "geth", Client: "geth", DropMemoryCaches: "tests", PrimePageCache: &enabled},
+			},
+		},
+	}
+
+	err := cfg.validateDropMemoryCaches()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prime_page_cache cannot be combined")
+}
+
+func TestValidateDropMemoryCaches_DropCachesCommand(t *testing.T) {
+	instances := []ClientInstance{{ID: "geth", Client: "geth", DropMemoryCaches: "tests"}}
+
+	tests := []struct {
+		name    string
+		runner  RunnerConfig
+		wantErr string
+	}{
+		{
+			name: "command replaces the file permission check",
+			runner: RunnerConfig{
+				DropCachesCommand: []string{"sh", "-c", "echo 3 > /proc/sys/vm/drop_caches"},
+				Instances:         instances,
+			},
+		},
+		{
+			name: "command not found",
+			runner: RunnerConfig{
+				DropCachesCommand: []string{"benchmarkoor-no-such-drop-caches-helper"},
+				Instances:         instances,
+			},
+			wantErr: "drop_caches_command",
+		},
+		{
+			name: "empty executable",
+			runner: RunnerConfig{
+				DropCachesCommand: []string{""},
+				Instances:         instances,
+			},
+			wantErr: "executable must not be empty",
+		},
+		{
+			name: "both path and command",
+			runner: RunnerConfig{
+				DropCachesPath:    "/host/drop_caches",
+				DropCachesCommand: []string{"sh"},
+				Instances:         instances,
+			},
+			wantErr: "mutually exclusive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Runner: tt.runner}
+
+			err := cfg.validateDropMemoryCaches()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestIsValidCommit(t *testing.T) {
+	tests := []struct {
+		commit string
+		want   bool
+	}{
+		{commit: "4f2a9c1", want: true},
+		{commit: "4f2a9c1d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a39", want: true},
+		{commit: "4F2A9C1D", want: true},
+		{commit: "4f2a9c", want: false},
+		{commit: "v1.15.0", want: false},
+		{commit: "main", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.commit, func(t *testing.T) {
+			assert.Equal(t, tt.want, isValidCommit(tt.commit))
+		})
+	}
+
+	t.Run("rejected by Validate", func(t *testing.T) {
+		cfg := &Config{
+			Runner: RunnerConfig{
+				Instances: []ClientInstance{
+					{ID: "geth", Client: "geth", ClientCommit: "main"},
+				},
+			},
+		}
+
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "client_commit")
+	})
+}
+
+func TestBuildConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch\n"), 0o644))
+
+	tests := []struct {
+		name      string
+		build     BuildConfig
+		errSubstr string
+	}{
+		{name: "default dockerfile", build: BuildConfig{Context: dir}},
+		{name: "missing context", build: BuildConfig{}, errSubstr: "context is required"},
+		{
+			name:      "context does not exist",
+			build:     BuildConfig{Context: filepath.Join(dir, "missing")},
+			errSubstr: "does not exist",
+		},
+		{
+			name:      "context is a file",
+			build:     BuildConfig{Context: filepath.Join(dir, "Dockerfile")},
+			errSubstr: "is not a directory",
+		},
+		{
+			name:      "dockerfile does not exist",
+			build:     BuildConfig{Context: dir, Dockerfile: "Dockerfile.dev"},
+			errSubstr: "Dockerfile.dev",
+		},
+		{
+			name:      "absolute dockerfile",
+			build:     BuildConfig{Context: dir, Dockerfile: filepath.Join(dir, "Dockerfile")},
+			errSubstr: "must be relative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build.Validate("instance \"geth\" build")
+			if tt.errSubstr == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstr)
+		})
+	}
+}
+
+func TestValidate_BuildWithImageDigest(t *testing.T) {
+	cfg := &Config{
+		Runner: RunnerConfig{
+			Instances: []ClientInstance{
+				{
+					ID:          "geth",
+					Client:      "geth",
+					ImageDigest: "sha256:" + strings.Repeat("a", 64),
+					Build:       &BuildConfig{Context: t.TempDir()},
+				},
+			},
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "image_digest cannot be combined with build")
+}
+
+func TestParseRestartPolicy(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      RestartPolicy
+		errSubstr string
+	}{
+		{input: "", want: RestartPolicy{Name: "no"}},
+		{input: "never", want: RestartPolicy{Name: "no"}},
+		{input: "no", want: RestartPolicy{Name: "no"}},
+		{input: "always", want: RestartPolicy{Name: "always"}},
+		{input: "unless-stopped", want: RestartPolicy{Name: "unless-stopped"}},
+		{input: "on-failure", want: RestartPolicy{Name: "on-failure"}},
+		{input: "on-failure:3", want: RestartPolicy{Name: "on-failure", MaxRetries: 3}},
+		{input: "sometimes", errSubstr: "invalid restart policy"},
+		{input: "always:3", errSubstr: "only allowed with on-failure"},
+		{input: "on-failure:-1", errSubstr: "non-negative integer"},
+		{input: "on-failure:x", errSubstr: "non-negative integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRestartPolicy(tt.input)
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, "on-failure:3", RestartPolicy{Name: "on-failure", MaxRetries: 3}.String())
+	assert.Equal(t, "no", RestartPolicy{Name: "no"}.String())
+}
+
+func TestValidate_RestartPolicy(t *testing.T) {
+	cfg := &Config{
+		Runner: RunnerConfig{
+			Instances: []ClientInstance{
+				{ID: "geth", Client: "geth", Restart: "sometimes"},
+			},
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid restart policy")
+}
+
+func TestApplyDirectoryOverrides(t *testing.T) {
+	configContent := `
+runner:
+  directories:
+    tmp_datadir: /config/datadir
+    tmp_cachedir: /config/cache
+  benchmark:
+    results_dir: /config/results
+  instances:
+    - id: test
+      client: geth
+`
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o644))
+
+	t.Setenv("BENCHMARKOOR_RUNNER_BENCHMARK_RESULTS_DIR", "/env/results")
+
+	t.Run("flags take precedence over config and env", func(t *testing.T) {
+		cfg, err := Load(configPath)
+		require.NoError(t, err)
+		require.Equal(t, "/env/results", cfg.Runner.Benchmark.ResultsDir)
+
+		cfg.ApplyDirectoryOverrides(DirectoryOverrides{
+			ResultsDir:  "/flag/results",
+			TmpDataDir:  "/flag/datadir",
+			TmpCacheDir: "/flag/cache",
+		})
+
+		assert.Equal(t, "/flag/results", cfg.Runner.Benchmark.ResultsDir)
+		assert.Equal(t, "/flag/datadir", cfg.Runner.Directories.TmpDataDir)
+		assert.Equal(t, "/flag/cache", cfg.Runner.Directories.TmpCacheDir)
+	})
+
+	t.Run("unset flags keep config values", func(t *testing.T) {
+		cfg, err := Load(configPath)
+		require.NoError(t, err)
+
+		cfg.ApplyDirectoryOverrides(DirectoryOverrides{TmpCacheDir: "/flag/cache"})
+
+		assert.Equal(t, "/env/results", cfg.Runner.Benchmark.ResultsDir)
+		assert.Equal(t, "/config/datadir", cfg.Runner.Directories.TmpDataDir)
+		assert.Equal(t, "/flag/cache", cfg.Runner.Directories.TmpCacheDir)
+	})
+}
+
+func TestLoad_Tracing(t *testing.T) {
+	configContent := `
+global:
+  tracing:
+    endpoint: http://localhost:4318/v1/traces
+    headers:
+      x-api-key: secret
+runner:
+  instances:
+    - id: test
+      client: geth
+`
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o644))
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+
+	require.NotNil(t, cfg.Global.Tracing)
+	assert.Equal(t, "http://localhost:4318/v1/traces", cfg.Global.Tracing.Endpoint)
+	assert.Equal(t, map[string]string{"x-api-key": "secret"}, cfg.Global.Tracing.Headers)
+	assert.Equal(t, DefaultTracingServiceName, cfg.Global.Tracing.GetServiceName())
+}
+
+func TestValidate_TracingEndpointRequired(t *testing.T) {
+	cfg := &Config{
+		Global: GlobalConfig{Tracing: &TracingConfig{ServiceName: "bench"}},
+		Runner: RunnerConfig{
+			Instances: []ClientInstance{{ID: "geth", Client: "geth"}},
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "global.tracing.endpoint")
+}
+
+func TestValidateAll_ReportsAllErrors(t *testing.T) {
+	cfg := &Config{
+		Global: GlobalConfig{Tracing: &TracingConfig{ServiceName: "bench"}},
+		Runner: RunnerConfig{
+			ContainerRuntime: "lxc",
+			RunTimeout:       "soon",
+			Instances: []ClientInstance{
+				{Client: "geth"},
+				{ID: "reth-1", Client: "nope", ImageDigest: "latest"},
+				{ID: "reth-1", Client: "reth", Restart: "sometimes"},
+			},
+		},
+	}
+
+	errs := cfg.ValidateAll().Errors
+
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field
+	}
+
+	assert.Equal(t, []string{
+		"global.tracing.endpoint",
+		"runner.instances[0].id",
+		"runner.instances[1].client",
+		"runner.instances[1].image_digest",
+		"runner.instances[2].id",
+		"runner.instances[2].restart",
+		"runner.container_runtime",
+		"run_timeout",
+	}, fields)
+
+	assert.Contains(t, errs[2].Message, `unknown client type "nope"`)
+	assert.Contains(t, errs[4].Message, `duplicate id "reth-1"`)
+	assert.Contains(t, errs[7].Message, `invalid runner.run_timeout "soon"`)
+
+	// Validate reports the first of them.
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, errs[0].Message, err.Error())
+}
+
+func TestValidateAll_Valid(t *testing.T) {
+	cfg := &Config{Runner: RunnerConfig{
+		ContainerRuntime: "docker",
+		Instances:        []ClientInstance{{ID: "geth-1", Client: "geth"}},
+	}}
+
+	result := cfg.ValidateAll()
+	assert.Empty(t, result.Errors)
+	assert.NoError(t, result.Err())
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateAll_Warnings(t *testing.T) {
+	limits := &ResourceLimits{Memory: "16g"}
+	datadir := &DataDirConfig{SourceDir: "/data/geth"}
+	primed := true
+
+	tests := []struct {
+		name     string
+		instance ClientInstance
+		want     []string
+	}{
+		{
+			name:     "no resource limits",
+			instance: ClientInstance{ID: "geth-1", Client: "geth"},
+			want:     []string{"runner.instances[0].resource_limits"},
+		},
+		{
+			name:     "resource limits",
+			instance: ClientInstance{ID: "geth-1", Client: "geth", ResourceLimits: limits},
+		},
+		{
+			name: "powersave governor",
+			instance: ClientInstance{
+				ID: "geth-1", Client: "geth",
+				ResourceLimits: &ResourceLimits{CPUGovernor: "powersave"},
+			},
+			want: []string{"runner.instances[0].resource_limits.cpu_freq_governor"},
+		},
+		{
+			name:     "datadir without dropping caches",
+			instance: ClientInstance{ID: "geth-1", Client: "geth", ResourceLimits: limits, DataDir: datadir},
+			want:     []string{"runner.instances[0].drop_memory_caches"},
+		},
+		{
+			name: "datadir dropping caches",
+			instance: ClientInstance{
+				ID: "geth-1", Client: "geth", ResourceLimits: limits, DataDir: datadir,
+				DropMemoryCaches: "tests",
+			},
+		},
+		{
+			name: "datadir with primed page cache",
+			instance: ClientInstance{
+				ID: "geth-1", Client: "geth", ResourceLimits: limits, DataDir: datadir,
+				PrimePageCache: &primed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Runner: RunnerConfig{Instances: []ClientInstance{tt.instance}}}
+
+			var fields []string
+			for _, w := range cfg.ValidateAll().Warnings {
+				fields = append(fields, w.Field)
+			}
+
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+
+	t.Run("inactive instances", func(t *testing.T) {
+		cfg := &Config{Runner: RunnerConfig{Instances: []ClientInstance{
+			{ID: "geth-1", Client: "geth"},
+			{ID: "reth-1", Client: "reth", ResourceLimits: limits},
+		}}}
+
+		result := cfg.ValidateAll(ValidateOpts{ActiveInstanceIDs: map[string]struct{}{"reth-1": {}}})
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("warnings do not fail validation", func(t *testing.T) {
+		cfg := &Config{Runner: RunnerConfig{Instances: []ClientInstance{{ID: "geth-1", Client: "geth"}}}}
+
+		require.NoError(t, cfg.Validate())
+		assert.NotEmpty(t, cfg.ValidateAll().Warnings)
+	})
+}
+
+func TestGitSourceV2_GetDepth(t *testing.T) {
+	depthPtr := func(d int) *int { return &d }
+
+	assert.Equal(t, DefaultGitDepth, (&GitSourceV2{}).GetDepth())
+	assert.Equal(t, 0, (&GitSourceV2{Depth: depthPtr(0)}).GetDepth())
+	assert.Equal(t, 50, (&GitSourceV2{Depth: depthPtr(50)}).GetDepth())
+}
+
+func TestRenderRunDirName(t *testing.T) {
+	data := RunDirTemplateData{
+		Timestamp: 1718000000,
+		RunID:     "a1b2c3d4",
+		Instance:  "geth-latest",
+		Client:    "geth",
+		SuiteHash: "0123abcd",
+	}
+
+	tests := []struct {
+		name      string
+		tmpl      string
+		want      string
+		errSubstr string
+	}{
+		{name: "default", tmpl: DefaultResultsDirTemplate, want: "1718000000_a1b2c3d4_geth-latest"},
+		{
+			name: "client and suite first",
+			tmpl: "{{.Client}}.{{.SuiteHash}}.{{.Instance}}.{{.Timestamp}}.{{.RunID}}",
+			want: "geth.0123abcd.geth-latest.1718000000.a1b2c3d4",
+		},
+		{name: "literal prefix", tmpl: "ci-{{.RunID}}", want: "ci-a1b2c3d4"},
+		{
+			name: "conditional suite hash",
+			tmpl: `{{.RunID}}{{if .SuiteHash}}_{{.SuiteHash}}{{end}}`,
+			want: "a1b2c3d4_0123abcd",
+		},
+		{name: "path separator", tmpl: "{{.Client}}/{{.RunID}}", errSubstr: "path separators"},
+		{name: "empty", tmpl: "{{if false}}x{{end}}", errSubstr: "name is empty"},
+		{name: "dot dot", tmpl: "..", errSubstr: `must not be ".."`},
+		{name: "control character", tmpl: "{{.RunID}}\t", errSubstr: "control characters"},
+		{name: "unknown field", tmpl: "{{.Commit}}", errSubstr: "executing results_dir_template"},
+		{name: "invalid syntax", tmpl: "{{.RunID", errSubstr: "parsing results_dir_template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderRunDirName(tt.tmpl, data)
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateResultsDirTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		tmpl      string
+		errSubstr string
+	}{
+		{name: "unset"},
+		{name: "with run id", tmpl: "{{.Client}}_{{.Instance}}_{{.RunID}}"},
+		{name: "without run id", tmpl: "{{.Timestamp}}_{{.Instance}}", errSubstr: "must include {{.RunID}}"},
+		{name: "unsafe", tmpl: "runs/{{.RunID}}", errSubstr: "path separators"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Runner: RunnerConfig{Benchmark: BenchmarkConfig{ResultsDirTemplate: tt.tmpl}}}
+
+			err := cfg.validateResultsDirTemplate()
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	cfg := &Config{}
+	assert.Equal(t, DefaultResultsDirTemplate, cfg.GetResultsDirTemplate())
+}
+
+func TestRunDirPath(t *testing.T) {
+	// 1718000000 is 2024-06-10T06:13:20Z.
+	const name = "1718000000_a1b2c3d4_geth"
+
+	tests := []struct {
+		name      string
+		layout    string
+		timestamp int64
+		want      string
+	}{
+		{name: "unset", layout: "", timestamp: 1718000000, want: name},
+		{name: "flat", layout: ResultsLayoutFlat, timestamp: 1718000000, want: name},
+		{name: "date", layout: ResultsLayoutDate, timestamp: 1718000000, want: "2024/06/10/" + name},
+		// Last second of 2023 in UTC.
+		{name: "date uses UTC", layout: ResultsLayoutDate, timestamp: 1704067199, want: "2023/12/31/" + name},
+		{name: "date new year", layout: ResultsLayoutDate, timestamp: 1704067200, want: "2024/01/01/" + name},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RunDirPath(tt.layout, tt.timestamp, name))
+		})
+	}
+}
+
+func TestRunDirRelPath(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{name: "flat", dir: "results/runs/1718000000_a1b2c3d4_geth", want: "1718000000_a1b2c3d4_geth"},
+		{name: "flat trailing slash", dir: "results/runs/abc/", want: "abc"},
+		{
+			name: "date partitioned",
+			dir:  "/data/results/runs/2024/06/10/1718000000_a1b2c3d4_geth",
+			want: "2024/06/10/1718000000_a1b2c3d4_geth",
+		},
+		{name: "relative date partitioned", dir: "2024/06/10/abc", want: "2024/06/10/abc"},
+		{name: "incomplete partition", dir: "results/runs/06/10/abc", want: "abc"},
+		{name: "invalid month", dir: "results/2024/6/10/abc", want: "abc"},
+		{name: "bare name", dir: "abc", want: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RunDirRelPath(tt.dir))
+		})
+	}
+}
+
+func TestValidateResultsLayout(t *testing.T) {
+	for _, layout := range []string{"", ResultsLayoutFlat, ResultsLayoutDate} {
+		cfg := &Config{Runner: RunnerConfig{Benchmark: BenchmarkConfig{ResultsLayout: layout}}}
+		assert.NoError(t, cfg.validateResultsLayout(), layout)
+	}
+
+	cfg := &Config{Runner: RunnerConfig{Benchmark: BenchmarkConfig{ResultsLayout: "monthly"}}}
+	err := cfg.validateResultsLayout()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid results_layout "monthly"`)
+}
+
+func TestValidateResultsMode(t *testing.T) {
+	for _, mode := range []string{"", "0640", "600"} {
+		cfg := &Config{Runner: RunnerConfig{Benchmark: BenchmarkConfig{ResultsMode: mode}}}
+		assert.NoError(t, cfg.validateResultsMode(), mode)
+	}
+
+	cfg := &Config{Runner: RunnerConfig{Benchmark: BenchmarkConfig{ResultsMode: "0689"}}}
+	err := cfg.validateResultsMode()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid mode "0689"`)
+}
+
+func TestValidatePostRunCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   []string
+		required  bool
+		errSubstr string
+	}{
+		{name: "unset"},
+		{name: "found", command: []string{"sh", "-c", "true"}, required: true},
+		{name: "empty executable", command: []string{""}, errSubstr: "executable must not be empty"},
+		{name: "not found", command: []string{"benchmarkoor-no-such-hook"}, errSubstr: "executable file not found"},
+		{name: "required without command", required: true, errSubstr: "post_run_command is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Runner: RunnerConfig{PostRunCommand: tt.command, PostRunCommandRequired: tt.required}}
+
+			err := cfg.validatePostRunCommand()
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGetPreRunCommand(t *testing.T) {
+	cfg := &Config{Runner: RunnerConfig{Client: ClientConfig{Config: ClientDefaults{
+		PreRunCommand: []string{"warm-cache"},
+	}}}}
+
+	assert.Equal(t, []string{"warm-cache"}, cfg.GetPreRunCommand(&ClientInstance{ID: "geth"}))
+	assert.Equal(t, []string{"setup-nvme", "/dev/nvme1n1"},
+		cfg.GetPreRunCommand(&ClientInstance{ID: "reth", PreRunCommand: []string{"setup-nvme", "/dev/nvme1n1"}}))
+
+	cfg.Runner.Instances = []ClientInstance{{ID: "geth"}, {ID: "reth", PreRunCommand: []string{"sh", "-c", "true"}}}
+	err := cfg.validatePreRunCommand()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `instance "geth": pre_run_command:`)
+
+	cfg.Runner.Client.Config.PreRunCommand = []string{"true"}
+	assert.NoError(t, cfg.validatePreRunCommand())
+}
+
+func TestTestsConfig_MaxConsecutiveUnauthorized(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	assert.Equal(t, DefaultMaxConsecutiveUnauthorized, (&TestsConfig{}).GetMaxConsecutiveUnauthorized())
+	assert.Equal(t, 0, (&TestsConfig{MaxConsecutiveUnauthorized: intPtr(0)}).GetMaxConsecutiveUnauthorized())
+	assert.Equal(t, 3, (&TestsConfig{MaxConsecutiveUnauthorized: intPtr(3)}).GetMaxConsecutiveUnauthorized())
+
+	for _, tt := range []struct {
+		name    string
+		value   *int
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "disabled", value: intPtr(0)},
+		{name: "positive", value: intPtr(5)},
+		{name: "negative", value: intPtr(-1), wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Runner: RunnerConfig{
+					Instances: []ClientInstance{{ID: "geth", Client: "geth"}},
+					Benchmark: BenchmarkConfig{
+						Tests: TestsConfig{MaxConsecutiveUnauthorized: tt.value},
+					},
+				},
+			}
+
+			var fields []string
+			for _, e := range cfg.ValidateAll().Errors {
+				fields = append(fields, e.Field)
+			}
+
+			if tt.wantErr {
+				assert.Contains(t, fields, "runner.benchmark.tests.max_consecutive_unauthorized")
+			} else {
+				assert.NotContains(t, fields, "runner.benchmark.tests.max_consecutive_unauthorized")
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadEnvFile reads KEY=VALUE pairs from a dotenv file into the process
+// environment so they are available to ${VAR} expansion and BENCHMARKOOR_
+// overrides in Load. Variables already set in the environment take
+// precedence over the file.
+//
+// Supported syntax: blank lines, # comments (whole-line, or after an unquoted
+// value preceded by whitespace), an optional "export " prefix, 'single quoted'
+// literal values and "double quoted" values with \n, \t, \", \\ and \$ escapes.
+func LoadEnvFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening env file %q: %w", path, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	vars, err := parseEnvFile(f)
+	if err != nil {
+		return fmt.Errorf("parsing env file %q: %w", path, err)
+	}
+
+	for _, kv := range vars {
+		if _, ok := os.LookupEnv(kv[0]); ok {
+			continue
+		}
+
+		if err := os.Setenv(kv[0], kv[1]); err != nil {
+			return fmt.Errorf("setting %s from env file %q: %w", kv[0], path, err)
+		}
+	}
+
+	return nil
+}
+
+// parseEnvFile parses dotenv content into key/value pairs in file order.
+func parseEnvFile(r io.Reader) ([][2]string, error) {
+	var vars [][2]string
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNum)
+		}
+
+		key = strings.TrimSpace(key)
+		if !isEnvKey(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNum, key)
+		}
+
+		value, err := parseEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		vars = append(vars, [2]string{key, value})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
+
+// parseEnvValue unquotes a dotenv value and strips trailing comments.
+func parseEnvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	switch quote := raw[0]; quote {
+	case '\'', '"':
+		end := closingQuote(raw, quote)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated %c quote", quote)
+		}
+
+		if rest := strings.TrimSpace(raw[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected content after closing quote: %q", rest)
+		}
+
+		inner := raw[1:end]
+		if quote == '\'' {
+			return inner, nil
+		}
+
+		return unescapeDoubleQuoted(inner), nil
+	default:
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+
+		if i := strings.Index(raw, "\t#"); i >= 0 {
+			raw = raw[:i]
+		}
+
+		return strings.TrimSpace(raw), nil
+	}
+}
+
+// closingQuote returns the index of the quote closing raw[0], honouring
+// backslash escapes inside double quotes, or -1 if there is none.
+func closingQuote(raw string, quote byte) int {
+	for i := 1; i < len(raw); i++ {
+		if quote == '"' && raw[i] == '\\' {
+			i++
+
+			continue
+		}
+
+		if raw[i] == quote {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func unescapeDoubleQuoted(s string) string {
+	var b strings.Builder
+
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+
+			continue
+		}
+
+		i++
+
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\', '$':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String()
+}
+
+// isEnvKey reports whether key is a valid environment variable name.
+func isEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, c := range key {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
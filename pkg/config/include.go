@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeTag is the YAML tag that replaces a node with the content of
+// another config file, e.g. `resource_limits: !include limits.yaml`.
+const includeTag = "!include"
+
+// resolveIncludes replaces every !include node of a config with the parsed
+// content of the referenced file, recursively. Relative include paths are
+// resolved against the directory (or URL) of the including config. Inside
+// a sequence, an included sequence is spliced into it, so a file holding a
+// list of instances can be included as a single item. Environment variables
+// of included files are expanded like those of the config itself.
+func resolveIncludes(content, source string) (string, error) {
+	if !strings.Contains(content, includeTag) {
+		return content, nil
+	}
+
+	key, err := includeKey(source)
+	if err != nil {
+		return "", err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return "", fmt.Errorf("parsing config %q: %w", source, err)
+	}
+
+	if err := resolveIncludeNode(&doc, source, []string{key}); err != nil {
+		return "", err
+	}
+
+	resolved, err := yaml.Marshal(&doc)
+	if err != nil {
+		return "", fmt.Errorf("encoding config %q: %w", source, err)
+	}
+
+	return string(resolved), nil
+}
+
+// resolveIncludeNode resolves the !include nodes in node and its children.
+// stack holds the files being included, outermost first, to detect cycles.
+func resolveIncludeNode(node *yaml.Node, source string, stack []string) error {
+	if node.Tag == includeTag {
+		included, err := loadInclude(node, source, stack)
+		if err != nil {
+			return err
+		}
+
+		*node = *included
+
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.MappingNode:
+		for _, child := range node.Content {
+			if err := resolveIncludeNode(child, source, stack); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		items := make([]*yaml.Node, 0, len(node.Content))
+
+		for _, item := range node.Content {
+			if item.Tag != includeTag {
+				if err := resolveIncludeNode(item, source, stack); err != nil {
+					return err
+				}
+
+				items = append(items, item)
+
+				continue
+			}
+
+			included, err := loadInclude(item, source, stack)
+			if err != nil {
+				return err
+			}
+
+			if included.Kind == yaml.SequenceNode {
+				items = append(items, included.Content...)
+			} else {
+				items = append(items, included)
+			}
+		}
+
+		node.Content = items
+	}
+
+	return nil
+}
+
+// loadInclude reads, parses and resolves the file referenced by an
+// !include node of source.
+func loadInclude(node *yaml.Node, source string, stack []string) (*yaml.Node, error) {
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return nil, fmt.Errorf("%s line %d: %s requires a file path", source, node.Line, includeTag)
+	}
+
+	target, err := resolveIncludePath(source, node.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s line %d: %w", source, node.Line, err)
+	}
+
+	key, err := includeKey(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(stack, key) {
+		return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), key)
+	}
+
+	content, err := readConfigSource(target)
+	if err != nil {
+		return nil, fmt.Errorf("%s line %d: including: %w", source, node.Line, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(os.Expand(string(content), expandEnvWithDefaults)), &doc); err != nil {
+		return nil, fmt.Errorf("parsing included config %q: %w", target, err)
+	}
+
+	// An empty file includes null.
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+
+	if err := resolveIncludeNode(&doc, target, append(slices.Clone(stack), key)); err != nil {
+		return nil, err
+	}
+
+	return doc.Content[0], nil
+}
+
+// resolveIncludePath resolves an include path relative to the config that
+// includes it. A config fetched from a URL may only include other URLs, so
+// a remote file cannot read local files from the host.
+func resolveIncludePath(source, path string) (string, error) {
+	if isConfigURL(path) {
+		return path, nil
+	}
+
+	if isConfigURL(source) {
+		if filepath.IsAbs(path) {
+			return "", fmt.Errorf(
+				"remote config cannot include local path %q", path)
+		}
+
+		base, err := url.Parse(source)
+		if err != nil {
+			return "", fmt.Errorf("parsing config URL %q: %w", source, err)
+		}
+
+		ref, err := url.Parse(path)
+		if err != nil {
+			return "", fmt.Errorf("parsing include path %q: %w", path, err)
+		}
+
+		target := base.ResolveReference(ref).String()
+		if !isConfigURL(target) {
+			return "", fmt.Errorf(
+				"remote config cannot include non-http(s) source %q", path)
+		}
+
+		return target, nil
+	}
+
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	return filepath.Join(filepath.Dir(source), path), nil
+}
+
+// includeKey returns the canonical form of a config source used to detect
+// include cycles.
+func includeKey(source string) (string, error) {
+	if isConfigURL(source) {
+		return source, nil
+	}
+
+	abs, err := filepath.Abs(source)
+	if err != nil {
+		return "", fmt.Errorf("resolving config path %q: %w", source, err)
+	}
+
+	return abs, nil
+}
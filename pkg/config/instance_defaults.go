@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// applyInstanceDefaults copies every field set in runner.instance_defaults
+// into the instances that leave it unset. An instance field that is set
+// replaces the default as a whole, e.g. an instance's resource_limits is
+// not merged with the default resource_limits. Defaults are applied before
+// matrices are expanded, so matrix axes override them.
+func (c *Config) applyInstanceDefaults() error {
+	defaults := c.Runner.InstanceDefaults
+	if defaults == nil {
+		return nil
+	}
+
+	if defaults.ID != "" {
+		return fmt.Errorf("instance_defaults: id cannot have a default")
+	}
+
+	if len(defaults.Matrix) > 0 {
+		return fmt.Errorf("instance_defaults: matrix cannot have a default")
+	}
+
+	// Round-trip through YAML to give each instance its own copy of the
+	// defaults, like expandMatrix does for generated instances.
+	raw, err := yaml.Marshal(defaults)
+	if err != nil {
+		return fmt.Errorf("instance_defaults: encoding: %w", err)
+	}
+
+	for i := range c.Runner.Instances {
+		var fields map[string]any
+		if err := yaml.Unmarshal(raw, &fields); err != nil {
+			return fmt.Errorf("instance_defaults: decoding: %w", err)
+		}
+
+		var copied ClientInstance
+		if err := decodeInstance(fields, &copied); err != nil {
+			return fmt.Errorf("instance_defaults: decoding: %w", err)
+		}
+
+		instance := reflect.ValueOf(&c.Runner.Instances[i]).Elem()
+		fallback := reflect.ValueOf(copied)
+
+		for f := range instance.NumField() {
+			if instance.Field(f).IsZero() {
+				instance.Field(f).Set(fallback.Field(f))
+			}
+		}
+	}
+
+	return nil
+}
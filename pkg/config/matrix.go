@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v3"
+)
+
+// matrixIDUnsafe matches characters not allowed in generated instance IDs.
+var matrixIDUnsafe = regexp.MustCompile(`[^a-z0-9._]+`)
+
+// expandMatrices replaces every instance with a matrix by one instance per
+// combination of its axis values. Each axis is a dot-separated field path
+// relative to the instance (e.g. resource_limits.cpuset_count), and each
+// generated instance is suffixed with its values, in axis name order:
+//
+//	id: geth
+//	client: geth
+//	matrix:
+//	  resource_limits.cpuset_count: [2, 4]
+//	  resource_limits.memory: [8g, 16g]
+//
+// expands to geth-2-8g, geth-2-16g, geth-4-8g and geth-4-16g.
+func (c *Config) expandMatrices() error {
+	expanded := make([]ClientInstance, 0, len(c.Runner.Instances))
+
+	for _, instance := range c.Runner.Instances {
+		if len(instance.Matrix) == 0 {
+			expanded = append(expanded, instance)
+
+			continue
+		}
+
+		generated, err := expandMatrix(instance)
+		if err != nil {
+			return fmt.Errorf("instance %q: expanding matrix: %w", instance.ID, err)
+		}
+
+		expanded = append(expanded, generated...)
+	}
+
+	seen := make(map[string]struct{}, len(expanded))
+
+	for _, instance := range expanded {
+		if instance.ID == "" {
+			continue
+		}
+
+		if _, exists := seen[instance.ID]; exists {
+			return fmt.Errorf("matrix expansion produced duplicate instance id %q", instance.ID)
+		}
+
+		seen[instance.ID] = struct{}{}
+	}
+
+	c.Runner.Instances = expanded
+
+	return nil
+}
+
+// expandMatrix returns the instances generated from the matrix of base.
+func expandMatrix(base ClientInstance) ([]ClientInstance, error) {
+	matrix := base.Matrix
+	axes := slices.Sorted(maps.Keys(matrix))
+
+	for _, axis := range axes {
+		if axis == "id" || axis == "matrix" {
+			return nil, fmt.Errorf("axis %q cannot be varied", axis)
+		}
+
+		if len(matrix[axis]) == 0 {
+			return nil, fmt.Errorf("axis %q has no values", axis)
+		}
+	}
+
+	base.Matrix = nil
+
+	// Round-trip through YAML to get an independent copy of the instance
+	// for each combination, with the axis values set by field path.
+	raw, err := yaml.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("encoding instance: %w", err)
+	}
+
+	combinations := [][]any{{}}
+
+	for _, axis := range axes {
+		next := make([][]any, 0, len(combinations)*len(matrix[axis]))
+
+		for _, combination := range combinations {
+			for _, value := range matrix[axis] {
+				next = append(next, append(slices.Clone(combination), value))
+			}
+		}
+
+		combinations = next
+	}
+
+	instances := make([]ClientInstance, 0, len(combinations))
+
+	for _, combination := range combinations {
+		var fields map[string]any
+		if err := yaml.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("decoding instance: %w", err)
+		}
+
+		suffix := make([]string, len(axes))
+
+		for i, axis := range axes {
+			if err := setFieldPath(fields, axis, combination[i]); err != nil {
+				return nil, err
+			}
+
+			suffix[i] = matrixIDUnsafe.ReplaceAllString(strings.ToLower(fmt.Sprint(combination[i])), "_")
+		}
+
+		var instance ClientInstance
+		if err := decodeInstance(fields, &instance); err != nil {
+			return nil, fmt.Errorf("applying %s: %w", strings.Join(axes, ", "), err)
+		}
+
+		instance.ID = base.ID + "-" + strings.Join(suffix, "-")
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// setFieldPath sets the value at a dot-separated path in fields, creating
+// intermediate maps as needed.
+func setFieldPath(fields map[string]any, path string, value any) error {
+	keys := strings.Split(path, ".")
+
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := fields[key]
+		if !ok || next == nil {
+			child := make(map[string]any, 1)
+			fields[key] = child
+			fields = child
+
+			continue
+		}
+
+		child, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("axis %q: %q is not an object", path, key)
+		}
+
+		fields = child
+	}
+
+	fields[keys[len(keys)-1]] = value
+
+	return nil
+}
+
+// decodeInstance decodes instance fields the way Load decodes the config,
+// rejecting fields that do not exist so misspelled axes are caught.
+func decodeInstance(fields map[string]any, instance *ClientInstance) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+			dumpConfigDecodeHook(),
+			bootstrapFCUDecodeHook(),
+		),
+		ErrorUnused:      true,
+		WeaklyTypedInput: true,
+		Result:           instance,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(fields)
+}
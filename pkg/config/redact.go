@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// RedactedValue replaces redacted environment values in config.json.
+const RedactedValue = "***"
+
+// DefaultRedactEnvPatterns match the environment variable names whose values
+// are redacted from config.json when runner.redact_env is unset.
+var DefaultRedactEnvPatterns = []string{"*TOKEN*", "*SECRET*", "*PASSWORD*"}
+
+// GetRedactEnvPatterns returns the environment variable name patterns whose
+// values are redacted from config.json.
+func (c *Config) GetRedactEnvPatterns() []string {
+	if len(c.Runner.RedactEnv) > 0 {
+		return c.Runner.RedactEnv
+	}
+
+	return DefaultRedactEnvPatterns
+}
+
+// RedactEnv returns a copy of env with the values of variables whose names
+// match any of the patterns replaced by RedactedValue. Patterns use
+// path.Match syntax and match case-insensitively.
+func RedactEnv(env map[string]string, patterns []string) map[string]string {
+	if env == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(env))
+
+	for k, v := range env {
+		if matchesRedactPattern(k, patterns) {
+			v = RedactedValue
+		}
+
+		redacted[k] = v
+	}
+
+	return redacted
+}
+
+// matchesRedactPattern reports whether name matches any of the patterns.
+func matchesRedactPattern(name string, patterns []string) bool {
+	name = strings.ToUpper(name)
+
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToUpper(p), name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateRedactEnv validates the runner.redact_env patterns.
+func (c *Config) validateRedactEnv() error {
+	for _, p := range c.Runner.RedactEnv {
+		if p == "" {
+			return fmt.Errorf("pattern must not be empty")
+		}
+
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// maxRunDirNameLength is the longest file name most filesystems accept.
+const maxRunDirNameLength = 255
+
+// Results layouts, selecting where run directories are placed under
+// <results_dir>/runs.
+const (
+	// ResultsLayoutFlat places every run directly under runs/.
+	ResultsLayoutFlat = "flat"
+	// ResultsLayoutDate nests runs under runs/YYYY/MM/DD/, derived from the
+	// UTC run start time.
+	ResultsLayoutDate = "date"
+)
+
+// datePartitionFormat is the layout of the date partition directories.
+const datePartitionFormat = "2006/01/02"
+
+// datePartitionWidths are the name lengths of the year, month and day
+// partition directories.
+var datePartitionWidths = []int{4, 2, 2}
+
+// RunDirTemplateData holds the variables available to
+// runner.benchmark.results_dir_template.
+type RunDirTemplateData struct {
+	Timestamp int64  // Unix timestamp of the run start
+	RunID     string // Random 8 character hex ID of the run
+	Instance  string // Instance ID
+	Client    string // Client type
+	SuiteHash string // Test suite hash (empty without tests)
+}
+
+// GetResultsDirTemplate returns the run directory name template.
+func (c *Config) GetResultsDirTemplate() string {
+	if c.Runner.Benchmark.ResultsDirTemplate != "" {
+		return c.Runner.Benchmark.ResultsDirTemplate
+	}
+
+	return DefaultResultsDirTemplate
+}
+
+// GetResultsLayout returns the results layout, defaulting to flat.
+func (c *Config) GetResultsLayout() string {
+	if c.Runner.Benchmark.ResultsLayout != "" {
+		return c.Runner.Benchmark.ResultsLayout
+	}
+
+	return ResultsLayoutFlat
+}
+
+// RunDirPath returns the slash-separated path of a run directory relative to
+// <results_dir>/runs for the given layout. The path doubles as the run ID in
+// the results index.
+func RunDirPath(layout string, timestamp int64, name string) string {
+	if layout != ResultsLayoutDate {
+		return name
+	}
+
+	return path.Join(time.Unix(timestamp, 0).UTC().Format(datePartitionFormat), name)
+}
+
+// DatePartitionDepth is the number of directory levels of the date layout
+// between runs/ and the run directories.
+const DatePartitionDepth = 3
+
+// IsDatePartitionDir reports whether name is a valid date partition
+// directory name at the given depth below runs/ (0 for the year, 1 for the
+// month and 2 for the day).
+func IsDatePartitionDir(depth int, name string) bool {
+	if depth < 0 || depth >= len(datePartitionWidths) || len(name) != datePartitionWidths[depth] {
+		return false
+	}
+
+	for _, r := range name {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RunDirRelPath returns the path of a local run directory relative to
+// <results_dir>/runs, keeping the date partition directories it is nested
+// in, if any.
+func RunDirRelPath(dir string) string {
+	dir = filepath.Clean(dir)
+	name := filepath.Base(dir)
+	parent := filepath.Dir(dir)
+	partitions := make([]string, DatePartitionDepth)
+
+	for depth := DatePartitionDepth - 1; depth >= 0; depth-- {
+		partitions[depth] = filepath.Base(parent)
+		if !IsDatePartitionDir(depth, partitions[depth]) {
+			return name
+		}
+
+		parent = filepath.Dir(parent)
+	}
+
+	return path.Join(append(partitions, name)...)
+}
+
+// RenderRunDirName renders a run directory name template and checks that the
+// result is a single, filesystem-safe path component.
+func RenderRunDirName(tmpl string, data RunDirTemplateData) (string, error) {
+	parsed, err := template.New("results_dir_template").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parsing results_dir_template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("executing results_dir_template: %w", err)
+	}
+
+	name := buf.String()
+	if err := validateRunDirName(name); err != nil {
+		return "", fmt.Errorf("results_dir_template rendered %q: %w", name, err)
+	}
+
+	return name, nil
+}
+
+// validateRunDirName checks that name can be used as a run directory name.
+// Run directories are a single path component under runs/ (or under its date
+// partitions), where the index and the UI look for them.
+func validateRunDirName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("name is empty")
+	case name == "." || name == "..":
+		return fmt.Errorf("name must not be %q", name)
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("name must not contain path separators")
+	case strings.ContainsFunc(name, unicode.IsControl):
+		return fmt.Errorf("name must not contain control characters")
+	case len(name) > maxRunDirNameLength:
+		return fmt.Errorf("name is longer than %d bytes", maxRunDirNameLength)
+	}
+
+	return nil
+}
+
+// validateResultsDirTemplate renders the template with sample values and
+// checks that it produces safe names that differ for every run.
+func (c *Config) validateResultsDirTemplate() error {
+	if c.Runner.Benchmark.ResultsDirTemplate == "" {
+		return nil
+	}
+
+	sample := RunDirTemplateData{
+		Timestamp: 1700000000,
+		RunID:     "0a1b2c3d",
+		Instance:  "geth",
+		Client:    "geth",
+		SuiteHash: "4e5f6a7b8c9d0e1f",
+	}
+
+	first, err := RenderRunDirName(c.Runner.Benchmark.ResultsDirTemplate, sample)
+	if err != nil {
+		return err
+	}
+
+	// Runs of the same instance may start within the same second, only the
+	// run ID tells them apart.
+	sample.RunID = "4e5f6a7b"
+
+	second, err := RenderRunDirName(c.Runner.Benchmark.ResultsDirTemplate, sample)
+	if err != nil {
+		return err
+	}
+
+	if first == second {
+		return fmt.Errorf("results_dir_template must include {{.RunID}} so run directory names are unique")
+	}
+
+	return nil
+}
+
+// validateResultsLayout checks that results_layout names a known layout.
+func (c *Config) validateResultsLayout() error {
+	switch c.Runner.Benchmark.ResultsLayout {
+	case "", ResultsLayoutFlat, ResultsLayoutDate:
+		return nil
+	default:
+		return fmt.Errorf(
+			"invalid results_layout %q (must be %q or %q)",
+			c.Runner.Benchmark.ResultsLayout, ResultsLayoutFlat, ResultsLayoutDate,
+		)
+	}
+}
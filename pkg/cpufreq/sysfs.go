@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -189,6 +190,34 @@ func getGovernor(basePath string, cpuID int) (string, error) {
 	return readSysfsString(cpufreqPath(basePath, cpuID, scalingGovernorFile))
 }
 
+// GetGovernors returns the distinct governors in use across online CPUs,
+// sorted. CPUs without a readable governor are skipped.
+func GetGovernors(basePath string) ([]string, error) {
+	cpus, err := getOnlineCPUs(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var govs []string
+
+	for _, cpuID := range cpus {
+		gov, err := getGovernor(basePath, cpuID)
+		if err != nil || slices.Contains(govs, gov) {
+			continue
+		}
+
+		govs = append(govs, gov)
+	}
+
+	if len(govs) == 0 {
+		return nil, fmt.Errorf("no CPU governor found under %s", basePath)
+	}
+
+	slices.Sort(govs)
+
+	return govs, nil
+}
+
 // setGovernor sets the governor for a CPU.
 func setGovernor(basePath string, cpuID int, governor string) error {
 	return writeSysfsString(cpufreqPath(basePath, cpuID, scalingGovernorFile), governor)
@@ -85,6 +85,7 @@ func (p *fuseOverlayFSProvider) Prepare(ctx context.Context, cfg *ProviderConfig
 	// Return prepared directory with cleanup function.
 	return &PreparedDir{
 		MountPath: mergedDir,
+		UpperDir:  upperDir,
 		Cleanup: func() error {
 			return p.cleanup(mergedDir, baseDir)
 		},
@@ -23,7 +23,20 @@ type ProviderConfig struct {
 // PreparedDir represents a prepared data directory ready for mounting.
 type PreparedDir struct {
 	MountPath string
-	Cleanup   func() error
+	// UpperDir is the directory receiving writes to MountPath when it is an
+	// overlay mount.
+	UpperDir string
+	Cleanup  func() error
+}
+
+// BackingDir returns the host directory whose filesystem stores the data
+// written to the mounted datadir.
+func (p *PreparedDir) BackingDir() string {
+	if p.UpperDir != "" {
+		return p.UpperDir
+	}
+
+	return p.MountPath
 }
 
 // NewProvider creates a new Provider based on the method.
@@ -0,0 +1,104 @@
+// Package diskbench runs a quick disk throughput and latency probe so
+// benchmark results can be put into the context of the host's storage.
+package diskbench
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Supported probe tools.
+const (
+	// ToolAuto uses fio when it is installed and the internal probe otherwise.
+	ToolAuto = "auto"
+	// ToolFio shells out to fio.
+	ToolFio = "fio"
+	// ToolInternal uses the built-in probe.
+	ToolInternal = "internal"
+)
+
+const (
+	// seqBlockSize is the block size of the sequential write phase.
+	seqBlockSize = 1024 * 1024
+	// randBlockSize is the block size of the random read phase.
+	randBlockSize = 4096
+	// probeFileName is the name of the file written in the probe directory.
+	probeFileName = "benchmarkoor-diskbench.dat"
+)
+
+// Config configures a disk probe.
+type Config struct {
+	// Dir is the directory the probe file is written to. It should be on the
+	// same filesystem as the client's datadir.
+	Dir string
+	// Tool is one of ToolAuto, ToolFio or ToolInternal.
+	Tool string
+	// Size is the size of the probe file in bytes.
+	Size int64
+	// Duration caps each phase of the probe.
+	Duration time.Duration
+}
+
+// Result contains the probe measurements.
+type Result struct {
+	Tool              string
+	SeqWriteMBps      float64
+	RandReadIOPS      float64
+	RandReadLatencyUs float64
+}
+
+// Run probes the disk backing cfg.Dir. The probe file is removed afterwards.
+func Run(ctx context.Context, log logrus.FieldLogger, cfg *Config) (*Result, error) {
+	if cfg.Size < randBlockSize {
+		return nil, fmt.Errorf("probe size %d is smaller than %d bytes", cfg.Size, randBlockSize)
+	}
+
+	tool := cfg.Tool
+	if tool == "" || tool == ToolAuto {
+		tool = ToolInternal
+		if _, err := exec.LookPath("fio"); err == nil {
+			tool = ToolFio
+		}
+	}
+
+	dir, err := os.MkdirTemp(cfg.Dir, "benchmarkoor-diskbench-")
+	if err != nil {
+		return nil, fmt.Errorf("creating probe directory: %w", err)
+	}
+
+	defer func() {
+		if rmErr := os.RemoveAll(dir); rmErr != nil {
+			log.WithError(rmErr).Warn("Failed to remove disk probe directory")
+		}
+	}()
+
+	log.WithFields(logrus.Fields{
+		"tool": tool,
+		"dir":  cfg.Dir,
+		"size": cfg.Size,
+	}).Info("Running disk probe")
+
+	var result *Result
+
+	switch tool {
+	case ToolFio:
+		result, err = runFio(ctx, dir, cfg)
+	case ToolInternal:
+		result, err = runInternal(ctx, dir, cfg)
+	default:
+		return nil, fmt.Errorf("unknown disk probe tool %q", tool)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.Tool = tool
+
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package diskbench
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fioJSON = `{
+  "fio version" : "fio-3.36",
+  "jobs" : [
+    {
+      "jobname" : "seqwrite",
+      "error" : 0,
+      "read" : {"bw" : 0, "bw_bytes" : 0, "iops" : 0, "lat_ns" : {"mean" : 0}},
+      "write" : {"bw" : 1953125, "bw_bytes" : 2000000000, "iops" : 1907.3, "lat_ns" : {"mean" : 520000.5}}
+    },
+    {
+      "jobname" : "randread",
+      "error" : 0,
+      "read" : {"bw" : 312500, "bw_bytes" : 320000000, "iops" : 78125.0, "lat_ns" : {"mean" : 12500.0}},
+      "write" : {"bw" : 0, "bw_bytes" : 0, "iops" : 0, "lat_ns" : {"mean" : 0}}
+    }
+  ]
+}`
+
+func TestParseFioOutput(t *testing.T) {
+	result, err := ParseFioOutput([]byte(fioJSON))
+	require.NoError(t, err)
+
+	assert.InDelta(t, 2000.0, result.SeqWriteMBps, 0.001)
+	assert.InDelta(t, 78125.0, result.RandReadIOPS, 0.001)
+	assert.InDelta(t, 12.5, result.RandReadLatencyUs, 0.001)
+}
+
+func TestParseFioOutput_WithoutBWBytes(t *testing.T) {
+	out := `{"jobs": [
+		{"jobname": "seqwrite", "write": {"bw": 1000}},
+		{"jobname": "randread", "read": {"iops": 500, "lat_ns": {"mean": 2000000}}}
+	]}`
+
+	result, err := ParseFioOutput([]byte(out))
+	require.NoError(t, err)
+
+	assert.InDelta(t, 1.024, result.SeqWriteMBps, 0.001)
+	assert.InDelta(t, 500.0, result.RandReadIOPS, 0.001)
+	assert.InDelta(t, 2000.0, result.RandReadLatencyUs, 0.001)
+}
+
+func TestParseFioOutput_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		errSubstr string
+	}{
+		{name: "invalid json", input: "fio: unrecognized option", errSubstr: "parsing fio output"},
+		{
+			name:      "missing job",
+			input:     `{"jobs": [{"jobname": "seqwrite", "write": {"bw": 1000}}]}`,
+			errSubstr: "missing",
+		},
+		{
+			name:      "job error",
+			input:     `{"jobs": [{"jobname": "seqwrite", "error": 22}, {"jobname": "randread"}]}`,
+			errSubstr: "failed with error 22",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFioOutput([]byte(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstr)
+		})
+	}
+}
+
+func TestFioArgs(t *testing.T) {
+	args := fioArgs("/data/probe", &Config{Size: 64 * 1024 * 1024, Duration: 10 * time.Second})
+
+	assert.Contains(t, args, "--output-format=json")
+	assert.Contains(t, args, "--directory=/data/probe")
+	assert.Contains(t, args, "--size=67108864")
+	assert.Contains(t, args, "--runtime=10")
+	assert.Contains(t, args, "--name=seqwrite")
+	assert.Contains(t, args, "--name=randread")
+}
+
+func TestRun_Internal(t *testing.T) {
+	dir := t.TempDir()
+
+	result, err := Run(context.Background(), logrus.New(), &Config{
+		Dir:      dir,
+		Tool:     ToolInternal,
+		Size:     2 * seqBlockSize,
+		Duration: time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, ToolInternal, result.Tool)
+	assert.Positive(t, result.SeqWriteMBps)
+	assert.Positive(t, result.RandReadIOPS)
+
+	// The probe directory is removed afterwards.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRun_SizeTooSmall(t *testing.T) {
+	_, err := Run(context.Background(), logrus.New(), &Config{
+		Dir:      t.TempDir(),
+		Tool:     ToolInternal,
+		Size:     100,
+		Duration: time.Second,
+	})
+	require.Error(t, err)
+}
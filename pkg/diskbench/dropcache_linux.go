@@ -0,0 +1,12 @@
+package diskbench
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// dropFileCache evicts the file's pages from the page cache.
+func dropFileCache(f *os.File) error {
+	return unix.Fadvise(int(f.Fd()), 0, 0, unix.FADV_DONTNEED)
+}
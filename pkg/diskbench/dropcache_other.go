@@ -0,0 +1,11 @@
+//go:build !linux
+
+package diskbench
+
+import "os"
+
+// dropFileCache is a no-op outside Linux; random reads may then be served
+// from the page cache.
+func dropFileCache(_ *os.File) error {
+	return nil
+}
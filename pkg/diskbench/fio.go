@@ -0,0 +1,111 @@
+package diskbench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// fioOutput is the subset of fio's JSON output used by the probe.
+type fioOutput struct {
+	Jobs []fioJob `json:"jobs"`
+}
+
+type fioJob struct {
+	JobName string   `json:"jobname"`
+	Error   int      `json:"error"`
+	Read    fioStats `json:"read"`
+	Write   fioStats `json:"write"`
+}
+
+type fioStats struct {
+	BW      float64 `json:"bw"` // KiB/s
+	BWBytes float64 `json:"bw_bytes"`
+	IOPS    float64 `json:"iops"`
+	LatNs   struct {
+		Mean float64 `json:"mean"`
+	} `json:"lat_ns"`
+}
+
+// bytesPerSecond returns the bandwidth in bytes/s. bw_bytes is only
+// reported by fio 3.5 and later.
+func (s *fioStats) bytesPerSecond() float64 {
+	if s.BWBytes > 0 {
+		return s.BWBytes
+	}
+
+	return s.BW * 1024
+}
+
+// fioArgs builds the fio command line: a sequential write job followed by a
+// random read job over the same file. fio invalidates the file's page cache
+// before each job, so the reads hit the disk.
+func fioArgs(dir string, cfg *Config) []string {
+	return []string{
+		"--output-format=json",
+		"--directory=" + dir,
+		"--filename=" + probeFileName,
+		"--size=" + strconv.FormatInt(cfg.Size, 10),
+		"--runtime=" + strconv.FormatInt(int64(cfg.Duration.Seconds()), 10),
+		"--ioengine=psync",
+		"--name=seqwrite",
+		"--rw=write",
+		"--bs=" + strconv.Itoa(seqBlockSize),
+		"--end_fsync=1",
+		"--name=randread",
+		"--stonewall",
+		"--rw=randread",
+		"--bs=" + strconv.Itoa(randBlockSize),
+	}
+}
+
+// runFio runs the probe with fio.
+func runFio(ctx context.Context, dir string, cfg *Config) (*Result, error) {
+	out, err := exec.CommandContext(ctx, "fio", fioArgs(dir, cfg)...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("running fio: %w: %s", err, exitErr.Stderr)
+		}
+
+		return nil, fmt.Errorf("running fio: %w", err)
+	}
+
+	return ParseFioOutput(out)
+}
+
+// ParseFioOutput extracts the probe results from fio's JSON output.
+func ParseFioOutput(data []byte) (*Result, error) {
+	var out fioOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing fio output: %w", err)
+	}
+
+	var (
+		result            Result
+		seqWrite, rndRead bool
+	)
+
+	for _, job := range out.Jobs {
+		if job.Error != 0 {
+			return nil, fmt.Errorf("fio job %q failed with error %d", job.JobName, job.Error)
+		}
+
+		switch job.JobName {
+		case "seqwrite":
+			result.SeqWriteMBps = job.Write.bytesPerSecond() / 1e6
+			seqWrite = true
+		case "randread":
+			result.RandReadIOPS = job.Read.IOPS
+			result.RandReadLatencyUs = job.Read.LatNs.Mean / 1e3
+			rndRead = true
+		}
+	}
+
+	if !seqWrite || !rndRead {
+		return nil, fmt.Errorf("fio output is missing the seqwrite or randread job")
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,113 @@
+package diskbench
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// runInternal runs the built-in probe: a sequential write of the probe file
+// in 1 MiB blocks followed by an fsync, then 4 KiB reads at random offsets
+// after dropping the file from the page cache. Each phase stops early once
+// cfg.Duration has elapsed.
+func runInternal(ctx context.Context, dir string, cfg *Config) (*Result, error) {
+	path := filepath.Join(dir, probeFileName)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("creating probe file: %w", err)
+	}
+	defer f.Close()
+
+	written, writeElapsed, err := seqWrite(ctx, f, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dropFileCache(f); err != nil {
+		return nil, fmt.Errorf("dropping probe file from page cache: %w", err)
+	}
+
+	reads, readElapsed, err := randRead(ctx, f, written, cfg.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		SeqWriteMBps:      float64(written) / writeElapsed.Seconds() / 1e6,
+		RandReadIOPS:      float64(reads) / readElapsed.Seconds(),
+		RandReadLatencyUs: float64(readElapsed.Microseconds()) / float64(reads),
+	}, nil
+}
+
+// seqWrite writes up to cfg.Size bytes and syncs the file. It returns the
+// number of bytes written and the time taken including the sync.
+func seqWrite(ctx context.Context, f *os.File, cfg *Config) (int64, time.Duration, error) {
+	buf := make([]byte, seqBlockSize)
+	for i := range buf {
+		buf[i] = byte(rand.IntN(256))
+	}
+
+	var written int64
+
+	start := time.Now()
+
+	for written < cfg.Size {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+
+		// Always write at least one random-read block.
+		if written >= randBlockSize && time.Since(start) >= cfg.Duration {
+			break
+		}
+
+		chunk := buf[:min(int64(len(buf)), cfg.Size-written)]
+
+		n, err := f.Write(chunk)
+		if err != nil {
+			return 0, 0, fmt.Errorf("writing probe file: %w", err)
+		}
+
+		written += int64(n)
+	}
+
+	if err := f.Sync(); err != nil {
+		return 0, 0, fmt.Errorf("syncing probe file: %w", err)
+	}
+
+	return written, time.Since(start), nil
+}
+
+// randRead reads 4 KiB blocks at random aligned offsets within the first
+// size bytes of f, one read per block at most, until duration elapses.
+func randRead(ctx context.Context, f *os.File, size int64, duration time.Duration) (int64, time.Duration, error) {
+	blocks := size / randBlockSize
+	buf := make([]byte, randBlockSize)
+
+	var reads int64
+
+	start := time.Now()
+
+	for reads < blocks {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+
+		if reads > 0 && time.Since(start) >= duration {
+			break
+		}
+
+		offset := rand.Int64N(blocks) * randBlockSize
+		if _, err := f.ReadAt(buf, offset); err != nil {
+			return 0, 0, fmt.Errorf("reading probe file: %w", err)
+		}
+
+		reads++
+	}
+
+	return reads, time.Since(start), nil
+}
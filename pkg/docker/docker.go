@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types/blkiodev"
+	"github.com/docker/docker/api/types/build"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
@@ -16,8 +19,10 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/sirupsen/logrus"
+	"go.podman.io/storage/pkg/archive"
 )
 
 // ContainerManager defines container runtime operations.
@@ -45,9 +50,13 @@ type ContainerManager interface {
 	// Image operations.
 	PullImage(ctx context.Context, imageName string, policy string) error
 	GetImageDigest(ctx context.Context, imageName string) (string, error)
+	GetImageRepoDigests(ctx context.Context, imageName string) ([]string, error)
+	GetImageLabels(ctx context.Context, imageName string) (map[string]string, error)
+	BuildImage(ctx context.Context, spec *BuildSpec, output io.Writer) error
 
 	// Container info.
 	GetContainerIP(ctx context.Context, containerID, networkName string) (string, error)
+	GetContainerRestartCount(ctx context.Context, containerID string) (int, error)
 
 	// Volume operations.
 	CreateVolume(ctx context.Context, name string, labels map[string]string) error
@@ -72,15 +81,22 @@ type Manager interface {
 
 // ResourceLimits defines container resource constraints.
 type ResourceLimits struct {
-	CpusetCpus       string // Comma-separated CPU IDs (e.g., "0,1,2")
-	MemoryBytes      int64  // Memory limit in bytes
-	MemorySwapBytes  int64  // Memory+swap limit (-1 = unlimited, same as MemoryBytes = no swap)
-	MemorySwappiness *int64 // 0-100, controls swappiness
+	CpusetCpus        string // Comma-separated CPU IDs (e.g., "0,1,2")
+	NanoCPUs          int64  // CPU bandwidth in billionths of a CPU (e.g., 2.5 CPUs = 2500000000)
+	CPUQuota          int64  // CFS quota in microseconds per CPUPeriod
+	CPUPeriod         int64  // CFS period in microseconds
+	MemoryBytes       int64  // Memory limit in bytes
+	MemoryReservation int64  // Memory soft limit in bytes
+	MemorySwapBytes   int64  // Memory+swap limit (-1 = unlimited, same as MemoryBytes = no swap)
+	MemorySwappiness  *int64 // 0-100, controls swappiness
 	// Blkio throttling.
 	BlkioDeviceReadBps   []BlkioThrottleDevice
 	BlkioDeviceWriteBps  []BlkioThrottleDevice
 	BlkioDeviceReadIOps  []BlkioThrottleDevice
 	BlkioDeviceWriteIOps []BlkioThrottleDevice
+	// Blkio proportional weights.
+	BlkioWeight       uint16 // 10-1000, 0 = unset
+	BlkioWeightDevice []BlkioWeightDevice
 }
 
 // BlkioThrottleDevice defines a block I/O throttle setting.
@@ -89,6 +105,12 @@ type BlkioThrottleDevice struct {
 	Rate uint64
 }
 
+// BlkioWeightDevice defines a per-device block I/O weight.
+type BlkioWeightDevice struct {
+	Path   string
+	Weight uint16
+}
+
 // ContainerSpec defines container configuration.
 type ContainerSpec struct {
 	Name           string
@@ -102,6 +124,37 @@ type ContainerSpec struct {
 	ResourceLimits *ResourceLimits
 	CapAdd         []string // Additional Linux capabilities (e.g., "SYS_PTRACE" for CRIU).
 	SecurityOpt    []string // Security options (e.g., "seccomp=unconfined").
+	Devices        []Device // Host devices to pass through.
+	RestartPolicy  string   // "no", "always", "unless-stopped" or "on-failure".
+	RestartRetries int      // Max restarts for "on-failure" (0 = unlimited).
+}
+
+// Device defines a host device mapped into the container.
+type Device struct {
+	HostPath      string
+	ContainerPath string
+	Permissions   string // cgroup permissions, e.g. "rwm".
+}
+
+// BuildSpec defines an image build from a local build context.
+type BuildSpec struct {
+	ContextDir string            // Build context directory.
+	Dockerfile string            // Dockerfile path relative to ContextDir.
+	Tag        string            // Tag applied to the built image.
+	BuildArgs  map[string]string // Build-time variables.
+}
+
+// restartPolicy converts the spec's restart policy to Docker's. An empty
+// policy means the container is never restarted.
+func restartPolicy(spec *ContainerSpec) container.RestartPolicy {
+	if spec.RestartPolicy == "" {
+		return container.RestartPolicy{Name: container.RestartPolicyDisabled}
+	}
+
+	return container.RestartPolicy{
+		Name:              container.RestartPolicyMode(spec.RestartPolicy),
+		MaximumRetryCount: spec.RestartRetries,
+	}
 }
 
 // Mount defines a volume mount.
@@ -250,16 +303,29 @@ func (m *manager) CreateContainer(ctx context.Context, spec *ContainerSpec) (str
 	}
 
 	hostCfg := &container.HostConfig{
-		Mounts:      mounts,
-		NetworkMode: container.NetworkMode(spec.NetworkName),
-		CapAdd:      spec.CapAdd,
-		SecurityOpt: spec.SecurityOpt,
+		Mounts:        mounts,
+		NetworkMode:   container.NetworkMode(spec.NetworkName),
+		CapAdd:        spec.CapAdd,
+		SecurityOpt:   spec.SecurityOpt,
+		RestartPolicy: restartPolicy(spec),
+	}
+
+	for _, dev := range spec.Devices {
+		hostCfg.Devices = append(hostCfg.Devices, container.DeviceMapping{
+			PathOnHost:        dev.HostPath,
+			PathInContainer:   dev.ContainerPath,
+			CgroupPermissions: dev.Permissions,
+		})
 	}
 
 	// Apply resource limits if configured.
 	if spec.ResourceLimits != nil {
 		hostCfg.CpusetCpus = spec.ResourceLimits.CpusetCpus
+		hostCfg.NanoCPUs = spec.ResourceLimits.NanoCPUs
+		hostCfg.CPUQuota = spec.ResourceLimits.CPUQuota
+		hostCfg.CPUPeriod = spec.ResourceLimits.CPUPeriod
 		hostCfg.Memory = spec.ResourceLimits.MemoryBytes
+		hostCfg.MemoryReservation = spec.ResourceLimits.MemoryReservation
 		hostCfg.MemorySwap = spec.ResourceLimits.MemorySwapBytes
 		hostCfg.MemorySwappiness = spec.ResourceLimits.MemorySwappiness
 
@@ -279,6 +345,13 @@ func (m *manager) CreateContainer(ctx context.Context, spec *ContainerSpec) (str
 		if len(spec.ResourceLimits.BlkioDeviceWriteIOps) > 0 {
 			hostCfg.BlkioDeviceWriteIOps = convertBlkioDevices(spec.ResourceLimits.BlkioDeviceWriteIOps)
 		}
+
+		// Apply blkio weights.
+		hostCfg.BlkioWeight = spec.ResourceLimits.BlkioWeight
+
+		if len(spec.ResourceLimits.BlkioWeightDevice) > 0 {
+			hostCfg.BlkioWeightDevice = convertBlkioWeightDevices(spec.ResourceLimits.BlkioWeightDevice)
+		}
 	}
 
 	networkCfg := &network.NetworkingConfig{}
@@ -448,19 +521,149 @@ func (m *manager) GetImageDigest(ctx context.Context, imageName string) (string,
 		return "", fmt.Errorf("inspecting image: %w", err)
 	}
 
-	// RepoDigests contains "image@sha256:hash" format.
-	// Extract just the "sha256:..." portion.
-	if len(inspect.RepoDigests) > 0 {
-		digest := inspect.RepoDigests[0]
+	if digests := RepoDigestHashes(inspect.RepoDigests); len(digests) > 0 {
+		return digests[0], nil
+	}
+
+	// Fallback to image ID (already in sha256:... format).
+	return inspect.ID, nil
+}
+
+// GetImageRepoDigests returns the "sha256:..." digests of all repository
+// references of an image. An image pulled by several names or from several
+// registries has one per reference; a locally built image has none.
+func (m *manager) GetImageRepoDigests(ctx context.Context, imageName string) ([]string, error) {
+	inspect, _, err := m.client.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting image: %w", err)
+	}
+
+	return RepoDigestHashes(inspect.RepoDigests), nil
+}
+
+// RepoDigestHashes extracts the "sha256:..." portion of each entry of an
+// image's RepoDigests, which are in "image@sha256:hash" format.
+func RepoDigestHashes(repoDigests []string) []string {
+	digests := make([]string, 0, len(repoDigests))
+
+	for _, digest := range repoDigests {
 		if idx := strings.Index(digest, "sha256:"); idx != -1 {
-			return digest[idx:], nil
+			digest = digest[idx:]
 		}
 
-		return digest, nil
+		digests = append(digests, digest)
 	}
 
-	// Fallback to image ID (already in sha256:... format).
-	return inspect.ID, nil
+	return digests
+}
+
+// GetImageLabels returns the labels of a local image.
+func (m *manager) GetImageLabels(ctx context.Context, imageName string) (map[string]string, error) {
+	inspect, _, err := m.client.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting image: %w", err)
+	}
+
+	if inspect.Config == nil {
+		return nil, nil
+	}
+
+	return inspect.Config.Labels, nil
+}
+
+// BuildImage builds an image from spec's build context and tags it. The
+// build output is written to output. Files matched by the context's
+// .dockerignore are excluded from the context sent to the daemon.
+func (m *manager) BuildImage(ctx context.Context, spec *BuildSpec, output io.Writer) error {
+	log := m.log.WithFields(logrus.Fields{
+		"context": spec.ContextDir,
+		"tag":     spec.Tag,
+	})
+
+	excludes, err := readDockerignore(spec.ContextDir)
+	if err != nil {
+		return err
+	}
+
+	buildCtx, err := archive.TarWithOptions(spec.ContextDir, &archive.TarOptions{
+		ExcludePatterns: excludes,
+	})
+	if err != nil {
+		return fmt.Errorf("archiving build context: %w", err)
+	}
+	defer func() { _ = buildCtx.Close() }()
+
+	buildArgs := make(map[string]*string, len(spec.BuildArgs))
+	for k, v := range spec.BuildArgs {
+		buildArgs[k] = &v
+	}
+
+	log.Info("Building image")
+
+	resp, err := m.client.ImageBuild(ctx, buildCtx, build.ImageBuildOptions{
+		Tags:        []string{spec.Tag},
+		Dockerfile:  spec.Dockerfile,
+		BuildArgs:   buildArgs,
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return fmt.Errorf("building image %s: %w", spec.Tag, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Errors from the build steps are reported in the response stream.
+	if err := jsonmessage.DisplayJSONMessagesStream(resp.Body, output, 0, false, nil); err != nil {
+		return fmt.Errorf("building image %s: %w", spec.Tag, err)
+	}
+
+	log.Info("Image built successfully")
+
+	return nil
+}
+
+// readDockerignore returns the exclude patterns from the .dockerignore file
+// in contextDir, or nil if there is none.
+func readDockerignore(contextDir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(contextDir, ".dockerignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading .dockerignore: %w", err)
+	}
+
+	var patterns []string
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(line, "!")
+		pattern := filepath.Clean(strings.TrimPrefix(strings.TrimPrefix(line, "!"), "/"))
+
+		if negate {
+			pattern = "!" + pattern
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
+
+// GetContainerRestartCount returns how often the daemon restarted the
+// container under its restart policy.
+func (m *manager) GetContainerRestartCount(ctx context.Context, containerID string) (int, error) {
+	inspect, err := m.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return 0, fmt.Errorf("inspecting container: %w", err)
+	}
+
+	return inspect.RestartCount, nil
 }
 
 // GetContainerIP returns the IP address of a container in the specified network.
@@ -635,3 +838,16 @@ func convertBlkioDevices(devices []BlkioThrottleDevice) []*blkiodev.ThrottleDevi
 
 	return result
 }
+
+// convertBlkioWeightDevices converts internal BlkioWeightDevice slice to Docker SDK format.
+func convertBlkioWeightDevices(devices []BlkioWeightDevice) []*blkiodev.WeightDevice {
+	result := make([]*blkiodev.WeightDevice, len(devices))
+	for i, dev := range devices {
+		result[i] = &blkiodev.WeightDevice{
+			Path:   dev.Path,
+			Weight: dev.Weight,
+		}
+	}
+
+	return result
+}
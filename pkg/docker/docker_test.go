@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/ethpandaops/benchmarkoor/pkg/stats"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManager_InterfaceCompliance(t *testing.T) {
+	// Creating the client does not connect to the daemon.
+	mgr, err := NewManager(logrus.New())
+	require.NoError(t, err)
+
+	assert.Implements(t, (*ContainerManager)(nil), mgr)
+
+	// The runner hands the client to the stats package for the Docker
+	// Stats API fallback reader.
+	assert.Implements(t, (*stats.StatsClient)(nil), mgr.GetClient())
+}
+
+func TestReadDockerignore(t *testing.T) {
+	dir := t.TempDir()
+
+	patterns, err := readDockerignore(dir)
+	require.NoError(t, err)
+	assert.Nil(t, patterns)
+
+	content := "# build output\n\nbuild/\n/.git\n!build/keep\n  *.log  \n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte(content), 0o644))
+
+	patterns, err = readDockerignore(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"build", ".git", "!build/keep", "*.log"}, patterns)
+}
+
+func TestRestartPolicy(t *testing.T) {
+	assert.Equal(t, container.RestartPolicy{Name: container.RestartPolicyDisabled},
+		restartPolicy(&ContainerSpec{}))
+	assert.Equal(t, container.RestartPolicy{Name: container.RestartPolicyAlways},
+		restartPolicy(&ContainerSpec{RestartPolicy: "always"}))
+	assert.Equal(t, container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+		restartPolicy(&ContainerSpec{RestartPolicy: "on-failure", RestartRetries: 3}))
+}
@@ -0,0 +1,62 @@
+// Package download provides helpers for fetching remote files such as
+// genesis files and EEST fixture tarballs.
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrOffline is returned instead of fetching a remote file in offline mode.
+var ErrOffline = errors.New("network fetch required in offline mode")
+
+// FetchFunc downloads a single URL. It is called once per candidate URL
+// by WithFallback and must leave no partial state behind on failure.
+type FetchFunc func(ctx context.Context, url string) error
+
+// WithFallback calls fetch for each URL in order until one succeeds.
+// The first URL is the primary source; the rest are mirrors that are only
+// tried when every earlier URL failed. If all URLs fail, the returned error
+// joins the error of every attempt.
+func WithFallback(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	urls []string,
+	fetch FetchFunc,
+) error {
+	if len(urls) == 0 {
+		return errors.New("no download URLs configured")
+	}
+
+	errs := make([]error, 0, len(urls))
+
+	for i, url := range urls {
+		err := fetch(ctx, url)
+		if err == nil {
+			if i > 0 {
+				log.WithField("url", url).Info("Downloaded from mirror")
+			}
+
+			return nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", url, err))
+
+		// Do not try mirrors once the caller has given up.
+		if ctx.Err() != nil {
+			break
+		}
+
+		if i < len(urls)-1 {
+			log.WithError(err).
+				WithField("url", url).
+				WithField("next", urls[i+1]).
+				Warn("Download failed, trying next mirror")
+		}
+	}
+
+	return fmt.Errorf("all %d download URLs failed: %w", len(urls), errors.Join(errs...))
+}
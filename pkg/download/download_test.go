@@ -0,0 +1,135 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() logrus.FieldLogger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return log
+}
+
+// httpFetch returns a FetchFunc that stores the body of a 200 response.
+func httpFetch(body *string) FetchFunc {
+	return func(ctx context.Context, url string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return errors.New(resp.Status)
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		*body = string(data)
+
+		return nil
+	}
+}
+
+func TestWithFallback_MirrorSucceeds(t *testing.T) {
+	var primaryCalls int
+
+	primary := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			primaryCalls++
+
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"config":{}}`))
+		},
+	))
+	defer mirror.Close()
+
+	var body string
+
+	err := WithFallback(context.Background(), testLogger(),
+		[]string{primary.URL, mirror.URL}, httpFetch(&body))
+	require.NoError(t, err)
+	assert.Equal(t, `{"config":{}}`, body)
+	assert.Equal(t, 1, primaryCalls)
+}
+
+func TestWithFallback_PrimarySucceeds(t *testing.T) {
+	var fetched []string
+
+	err := WithFallback(context.Background(), testLogger(),
+		[]string{"https://primary", "https://mirror"},
+		func(_ context.Context, url string) error {
+			fetched = append(fetched, url)
+
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://primary"}, fetched)
+}
+
+func TestWithFallback_AllFail(t *testing.T) {
+	errPrimary := errors.New("primary down")
+	errMirror := errors.New("mirror down")
+
+	err := WithFallback(context.Background(), testLogger(),
+		[]string{"https://primary", "https://mirror"},
+		func(_ context.Context, url string) error {
+			if url == "https://primary" {
+				return errPrimary
+			}
+
+			return errMirror
+		})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errPrimary)
+	assert.ErrorIs(t, err, errMirror)
+	assert.Contains(t, err.Error(), "all 2 download URLs failed")
+}
+
+func TestWithFallback_StopsWhenContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var fetched []string
+
+	err := WithFallback(ctx, testLogger(),
+		[]string{"https://primary", "https://mirror"},
+		func(ctx context.Context, url string) error {
+			fetched = append(fetched, url)
+			cancel()
+
+			return ctx.Err()
+		})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"https://primary"}, fetched)
+}
+
+func TestWithFallback_NoURLs(t *testing.T) {
+	err := WithFallback(context.Background(), testLogger(), nil,
+		func(context.Context, string) error { return nil })
+	require.Error(t, err)
+}
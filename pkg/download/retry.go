@@ -0,0 +1,105 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StatusError is returned by a FetchFunc when the server responds with an
+// unexpected HTTP status code.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// RetryPolicy bounds how often a single URL is retried after a transient
+// failure. The zero value makes exactly one attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles after every
+	// further failure, up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Wrap returns a FetchFunc that retries fetch according to the policy.
+// Only transient failures are retried: 5xx and 429 responses, and network
+// errors such as timeouts, connection resets or a truncated body. Other
+// HTTP statuses (e.g. 404) and local failures fail immediately.
+func (p RetryPolicy) Wrap(log logrus.FieldLogger, fetch FetchFunc) FetchFunc {
+	return func(ctx context.Context, url string) error {
+		attempts := max(p.MaxAttempts, 1)
+		backoff := p.Backoff
+
+		var err error
+
+		for attempt := 1; ; attempt++ {
+			err = fetch(ctx, url)
+			if err == nil || attempt >= attempts || !IsRetryable(err) ||
+				ctx.Err() != nil {
+				return err
+			}
+
+			log.WithError(err).WithFields(logrus.Fields{
+				"url":     url,
+				"attempt": attempt,
+				"backoff": backoff,
+			}).Warn("Download failed, retrying")
+
+			select {
+			case <-ctx.Done():
+				return errors.Join(err, ctx.Err())
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+			if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+				backoff = p.MaxBackoff
+			}
+		}
+	}
+}
+
+// IsRetryable reports whether a download error is likely transient. Errors
+// that are neither network errors nor a StatusError, e.g. a full disk while
+// extracting, are not retried.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError ||
+			statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	// syscall.Errno satisfies net.Error too, so a bare errno from a local
+	// file operation must not count as a network failure.
+	var netErr net.Error
+	if !errors.As(err, &netErr) {
+		return false
+	}
+
+	_, isErrno := netErr.(syscall.Errno)
+
+	return !isErrno
+}
@@ -0,0 +1,203 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusFetch returns a FetchFunc that reports non-200 responses as a
+// StatusError, like the genesis and fixture downloaders.
+func statusFetch(body *string) FetchFunc {
+	return func(ctx context.Context, url string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return &StatusError{StatusCode: resp.StatusCode}
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		*body = string(data)
+
+		return nil
+	}
+}
+
+func TestRetryPolicy_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+
+				return
+			}
+
+			_, _ = w.Write([]byte("genesis"))
+		},
+	))
+	defer srv.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	var body string
+
+	err := policy.Wrap(testLogger(), statusFetch(&body))(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "genesis", body)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetryPolicy_RetriesConnectionErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				// Drop the connection without a response.
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+
+				_ = conn.Close()
+
+				return
+			}
+
+			_, _ = w.Write([]byte("genesis"))
+		},
+	))
+	defer srv.Close()
+
+	policy := RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}
+
+	var body string
+
+	err := policy.Wrap(testLogger(), statusFetch(&body))(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "genesis", body)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryPolicy_DoesNotRetryNotFound(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		},
+	))
+	defer srv.Close()
+
+	policy := RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond}
+
+	var body string
+
+	err := policy.Wrap(testLogger(), statusFetch(&body))(context.Background(), srv.URL)
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryPolicy_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int
+
+	policy := RetryPolicy{
+		MaxAttempts: 4,
+		Backoff:     time.Millisecond,
+		MaxBackoff:  2 * time.Millisecond,
+	}
+
+	err := policy.Wrap(testLogger(), func(context.Context, string) error {
+		calls++
+
+		return &StatusError{StatusCode: http.StatusServiceUnavailable}
+	})(context.Background(), "https://example.com")
+	require.Error(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestRetryPolicy_ZeroValueTriesOnce(t *testing.T) {
+	var calls int
+
+	err := RetryPolicy{}.Wrap(testLogger(), func(context.Context, string) error {
+		calls++
+
+		return syscall.ECONNRESET
+	})(context.Background(), "https://example.com")
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicy_StopsWhenContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int
+
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}
+
+	err := policy.Wrap(testLogger(), func(context.Context, string) error {
+		calls++
+		cancel()
+
+		return syscall.ECONNRESET
+	})(ctx, "https://example.com")
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: &StatusError{StatusCode: http.StatusInternalServerError}, want: true},
+		{err: &StatusError{StatusCode: http.StatusServiceUnavailable}, want: true},
+		{err: &StatusError{StatusCode: http.StatusTooManyRequests}, want: true},
+		{err: &StatusError{StatusCode: http.StatusNotFound}, want: false},
+		{err: &StatusError{StatusCode: http.StatusForbidden}, want: false},
+		{err: fmt.Errorf("downloading: %w", &StatusError{StatusCode: 502}), want: true},
+		{err: io.ErrUnexpectedEOF, want: true},
+		{err: fmt.Errorf("reading body: %w", syscall.ECONNRESET), want: true},
+		{err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, want: true},
+		{err: &url.Error{Op: "Get", URL: "https://example.com", Err: &net.DNSError{Err: "no such host"}}, want: true},
+		{err: fmt.Errorf("writing file: %w", syscall.ENOSPC), want: false},
+		{err: errors.New("invalid tar header"), want: false},
+		{err: context.Canceled, want: false},
+		{err: fmt.Errorf("request: %w", context.DeadlineExceeded), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
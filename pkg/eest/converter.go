@@ -13,6 +13,9 @@ type ConvertedTest struct {
 	GenesisHash  string   // Genesis block hash for forkchoiceUpdated calls
 	FinalHash    string   // Final block hash after all payloads
 	PayloadCount int      // Total number of payloads in the fixture
+	// ExpectedPayloadStatus maps the block hashes of payloads the fixture
+	// expects to be rejected to "INVALID" (nil if all are expected VALID).
+	ExpectedPayloadStatus map[string]string
 }
 
 // ConvertFixture converts an EEST fixture to JSON-RPC calls.
@@ -52,6 +55,14 @@ func ConvertFixture(name string, fixture *Fixture) (*ConvertedTest, error) {
 			return nil, fmt.Errorf("converting payload %d: %w", i, err)
 		}
 
+		if payload.ValidationError != nil {
+			if result.ExpectedPayloadStatus == nil {
+				result.ExpectedPayloadStatus = make(map[string]string, 1)
+			}
+
+			result.ExpectedPayloadStatus[payload.ExecutionPayload.BlockHash] = "INVALID"
+		}
+
 		if isLastPayload {
 			result.TestLines = append(result.TestLines, lines...)
 			result.FinalHash = payload.ExecutionPayload.BlockHash
@@ -135,6 +135,37 @@ func TestConvertFixture_MultiplePayloads(t *testing.T) {
 	err = json.Unmarshal([]byte(result.SetupLines[0]), &rpcCall)
 	require.NoError(t, err)
 	assert.Equal(t, "engine_newPayloadV3", rpcCall["method"])
+	assert.Nil(t, result.ExpectedPayloadStatus)
+}
+
+func TestConvertFixture_ExpectedInvalidPayload(t *testing.T) {
+	payload := func(parent, hash string, validationErr *ValidationError) *EngineNewPayload {
+		return &EngineNewPayload{
+			ExecutionPayload: &ExecutionPayload{
+				ParentHash:   parent,
+				BlockNumber:  "0x1",
+				BlockHash:    hash,
+				Transactions: []string{},
+			},
+			NewPayloadVersion:        3,
+			ForkchoiceUpdatedVersion: 3,
+			BlobVersionedHashes:      []string{},
+			ParentBeaconBlockRoot:    "0xbeacon",
+			ValidationError:          validationErr,
+		}
+	}
+
+	fixture := &Fixture{
+		GenesisBlockHeader: &BlockHeader{Hash: "0xgenesis"},
+		EngineNewPayloads: []*EngineNewPayload{
+			payload("0xgenesis", "0xblock1", nil),
+			payload("0xblock1", "0xblock2", &ValidationError{Message: "TransactionException.INTRINSIC_GAS_TOO_LOW"}),
+		},
+	}
+
+	result, err := ConvertFixture("test_fixture", fixture)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"0xblock2": "INVALID"}, result.ExpectedPayloadStatus)
 }
 
 func TestConvertFixture_NilFixture(t *testing.T) {
@@ -297,6 +328,52 @@ func TestFixture_IsSupportedFormat(t *testing.T) {
 	}
 }
 
+func TestFixture_CheckFormatVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        string
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:        "no version marker",
+			info:        `{"fixture-format": "blockchain_test_engine_x"}`,
+			wantVersion: "1",
+		},
+		{
+			name:        "supported version",
+			info:        `{"fixture-format": "blockchain_test_engine_x", "fixture-format-version": "1"}`,
+			wantVersion: "1",
+		},
+		{
+			name:        "unsupported numeric version",
+			info:        `{"fixture-format": "blockchain_test_engine_x", "fixture-format-version": 2}`,
+			wantVersion: "2",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixtures, err := ParseFixtureFile([]byte(`{"test": {"_info": ` + tt.info + `}}`))
+			require.NoError(t, err)
+
+			fixture := fixtures["test"]
+			assert.Equal(t, tt.wantVersion, fixture.FormatVersion())
+
+			err = fixture.CheckFormatVersion()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), `unsupported blockchain_test_engine_x format version "2"`)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestEngineNewPayload_UnmarshalJSON(t *testing.T) {
 	// Test with actual EEST fixture format.
 	jsonData := `{
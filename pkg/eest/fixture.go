@@ -3,12 +3,22 @@ package eest
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 // SupportedFixtureFormat is the fixture format we support.
 const SupportedFixtureFormat = "blockchain_test_engine_x"
 
+// DefaultFixtureFormatVersion is the format version of fixtures without a
+// fixture-format-version marker.
+const DefaultFixtureFormatVersion = "1"
+
+// SupportedFixtureFormatVersions are the versions of SupportedFixtureFormat
+// the converter understands.
+var SupportedFixtureFormatVersions = []string{DefaultFixtureFormatVersion}
+
 // Fixture represents a single EEST test fixture.
 type Fixture struct {
 	Info               *FixtureInfo        `json:"_info"`
@@ -20,6 +30,7 @@ type Fixture struct {
 // FixtureInfo contains metadata about the fixture.
 type FixtureInfo struct {
 	FixtureFormat         string         `json:"fixture-format"`
+	FixtureFormatVersion  FormatVersion  `json:"fixture-format-version,omitempty"`
 	Hash                  string         `json:"hash,omitempty"`
 	OpcodeCount           map[string]int `json:"opcode_count,omitempty"`
 	Comment               string         `json:"comment,omitempty"`
@@ -28,11 +39,58 @@ type FixtureInfo struct {
 	URL                   string         `json:"url,omitempty"`
 }
 
+// FormatVersion is a fixture format version, given as a JSON string or
+// number.
+type FormatVersion string
+
+// UnmarshalJSON accepts both "2" and 2.
+func (v *FormatVersion) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = FormatVersion(s)
+
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid fixture format version %s", data)
+	}
+
+	*v = FormatVersion(n.String())
+
+	return nil
+}
+
 // IsSupportedFormat returns true if the fixture has a supported format.
 func (f *Fixture) IsSupportedFormat() bool {
 	return f.Info != nil && f.Info.FixtureFormat == SupportedFixtureFormat
 }
 
+// FormatVersion returns the fixture's format version, or
+// DefaultFixtureFormatVersion if it has no version marker.
+func (f *Fixture) FormatVersion() string {
+	if f.Info == nil || f.Info.FixtureFormatVersion == "" {
+		return DefaultFixtureFormatVersion
+	}
+
+	return string(f.Info.FixtureFormatVersion)
+}
+
+// CheckFormatVersion returns an error if the fixture's format version is not
+// supported, since converting it could produce empty or wrong tests.
+func (f *Fixture) CheckFormatVersion() error {
+	version := f.FormatVersion()
+	if slices.Contains(SupportedFixtureFormatVersions, version) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"unsupported %s format version %q (supported: %s)",
+		SupportedFixtureFormat, version, strings.Join(SupportedFixtureFormatVersions, ", "),
+	)
+}
+
 // BlockHeader represents an Ethereum block header.
 type BlockHeader struct {
 	ParentHash            string `json:"parentHash"`
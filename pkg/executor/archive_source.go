@@ -31,6 +31,8 @@ type ArchiveSource struct {
 	githubToken    string
 	basePath       string // temp directory where archive was extracted
 	opcodeBasePath string // temp directory for separate opcode archive
+
+	allowEmptyGlobs bool // Warn instead of failing when a glob matches no files
 }
 
 // Prepare downloads (if URL) and extracts the archive, then discovers tests.
@@ -68,7 +70,7 @@ func (s *ArchiveSource) Prepare(ctx context.Context) (*PreparedSource, error) {
 	s.log.WithField("path", s.basePath).Info("Extracted archive")
 
 	prepared, err := discoverTestsFromConfig(
-		s.basePath, s.cfg.PreRunSteps, s.cfg.Steps, s.filter, s.log,
+		s.basePath, s.cfg.PreRunSteps, s.cfg.Steps, s.filter, s.allowEmptyGlobs, s.log,
 	)
 	if err != nil {
 		_ = os.RemoveAll(s.basePath)
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// acquireCacheLease takes a shared lock on the cache entry in dir, creating
+// the directory if needed, and returns the function releasing it. The lock
+// is held by the kernel, so it is dropped even if the process dies.
+func acquireCacheLease(dir string) (func(), error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating cache entry: %w", err)
+	}
+
+	for {
+		f, err := lockCacheFile(dir, unix.LOCK_SH)
+		if err != nil {
+			return nil, err
+		}
+
+		// The entry may have been evicted while waiting for the lock, which
+		// leaves the lock on an unlinked file. Start over in that case.
+		if sameCacheLockFile(f, dir) {
+			return func() { _ = f.Close() }, nil
+		}
+
+		_ = f.Close()
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("creating cache entry: %w", err)
+		}
+	}
+}
+
+// tryLockCacheEntry takes an exclusive lock on the cache entry in dir
+// without waiting. It reports false if another run holds a lease on it.
+func tryLockCacheEntry(dir string) (func(), bool, error) {
+	f, err := lockCacheFile(dir, unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return func() { _ = f.Close() }, true, nil
+}
+
+// lockCacheFile opens the lock file of the cache entry in dir and flocks it
+// with how.
+func lockCacheFile(dir string, how int) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(dir, cacheLockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening cache lock: %w", err)
+	}
+
+	if err := unix.Flock(int(f.Fd()), how); err != nil {
+		_ = f.Close()
+
+		return nil, fmt.Errorf("locking cache entry: %w", err)
+	}
+
+	return f, nil
+}
+
+// sameCacheLockFile reports whether f is still the lock file of the cache
+// entry in dir.
+func sameCacheLockFile(f *os.File, dir string) bool {
+	held, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	current, err := os.Stat(filepath.Join(dir, cacheLockFile))
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(held, current)
+}
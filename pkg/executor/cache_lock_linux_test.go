@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneCacheLRU_SkipsLeasedEntries(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cacheDir := t.TempDir()
+
+	leased := filepath.Join(cacheDir, "eest", "aaaa", "benchmark@v0.0.5")
+	idle := filepath.Join(cacheDir, "eest", "aaaa", "benchmark@v0.0.6")
+	current := filepath.Join(cacheDir, "eest", "aaaa", "benchmark@v0.0.7")
+
+	writeCacheEntry(t, leased, 100, base)
+	writeCacheEntry(t, idle, 100, base.Add(time.Hour))
+	writeCacheEntry(t, current, 100, base.Add(2*time.Hour))
+
+	// Another run is still reading the least recently used entry.
+	release, err := acquireCacheLease(leased)
+	require.NoError(t, err)
+
+	dirs, err := eestCacheEntries(cacheDir)
+	require.NoError(t, err)
+
+	log, _ := logtest.NewNullLogger()
+	require.NoError(t, pruneCacheLRU(log, dirs, current, 150))
+
+	assert.DirExists(t, leased)
+	assert.NoDirExists(t, idle)
+	assert.DirExists(t, current)
+
+	// Once released, the entry is evicted.
+	release()
+
+	dirs, err = eestCacheEntries(cacheDir)
+	require.NoError(t, err)
+	require.NoError(t, pruneCacheLRU(log, dirs, current, 150))
+
+	assert.NoDirExists(t, leased)
+	assert.DirExists(t, current)
+}
+
+func TestAcquireCacheLease_RecreatesEvictedEntry(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "eest", "aaaa", "benchmark@v0.0.5")
+	writeCacheEntry(t, dir, 10, time.Now())
+
+	removed, err := removeCacheEntry(dir)
+	require.NoError(t, err)
+	require.True(t, removed)
+
+	release, err := acquireCacheLease(dir)
+	require.NoError(t, err)
+
+	defer release()
+
+	assert.FileExists(t, filepath.Join(dir, cacheLockFile))
+
+	// A leased entry cannot be removed.
+	removed, err = removeCacheEntry(dir)
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
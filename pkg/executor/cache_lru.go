@@ -0,0 +1,187 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// cacheStampFile is the file in a cache entry recording its size and when it
+// was last used.
+const cacheStampFile = ".benchmarkoor-cache.json"
+
+// cacheLockFile is the file in a cache entry that runs using the entry hold
+// a shared lock on, so that concurrent runs cannot evict it.
+const cacheLockFile = ".benchmarkoor-cache.lock"
+
+// cacheStamp is the content of a cache entry's stamp file.
+type cacheStamp struct {
+	Size       int64     `json:"size"`
+	LastAccess time.Time `json:"last_access"`
+}
+
+// cacheEntry is a cache entry directory with its stamp.
+type cacheEntry struct {
+	dir   string
+	stamp cacheStamp
+}
+
+// touchCacheEntry records that the cache entry in dir was used at now. The
+// size of the entry is computed the first time it is touched, since cache
+// entries are not modified once extracted.
+func touchCacheEntry(dir string, now time.Time) error {
+	stamp, err := readCacheStamp(dir)
+	if err != nil {
+		return err
+	}
+
+	stamp.LastAccess = now
+
+	data, err := json.Marshal(stamp)
+	if err != nil {
+		return fmt.Errorf("marshaling cache stamp: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, cacheStampFile), data, 0644); err != nil {
+		return fmt.Errorf("writing cache stamp: %w", err)
+	}
+
+	return nil
+}
+
+// readCacheStamp returns the stamp of the cache entry in dir. Entries without
+// a stamp, e.g. extracted before stamps were recorded, are measured and
+// treated as last used when the directory was last modified.
+func readCacheStamp(dir string) (cacheStamp, error) {
+	data, err := os.ReadFile(filepath.Join(dir, cacheStampFile))
+	if err == nil {
+		var stamp cacheStamp
+		if err := json.Unmarshal(data, &stamp); err == nil {
+			return stamp, nil
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return cacheStamp{}, fmt.Errorf("reading cache stamp: %w", err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return cacheStamp{}, fmt.Errorf("stat cache entry: %w", err)
+	}
+
+	size, err := dirSize(dir)
+	if err != nil {
+		return cacheStamp{}, fmt.Errorf("measuring cache entry: %w", err)
+	}
+
+	return cacheStamp{Size: size, LastAccess: info.ModTime()}, nil
+}
+
+// dirSize returns the total size of the regular files below dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() || d.Name() == cacheStampFile ||
+			d.Name() == cacheLockFile {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
+
+// pruneCacheLRU removes the least recently used of the cache entry dirs until
+// their total size is at most maxSize. The entry in keep, which is in use, is
+// never removed, even if it alone exceeds maxSize. Entries leased by another
+// run (see acquireCacheLease) are skipped as well.
+func pruneCacheLRU(log logrus.FieldLogger, dirs []string, keep string, maxSize int64) error {
+	entries := make([]cacheEntry, 0, len(dirs))
+
+	var total int64
+
+	for _, dir := range dirs {
+		stamp, err := readCacheStamp(dir)
+		if err != nil {
+			return fmt.Errorf("reading cache entry %s: %w", dir, err)
+		}
+
+		entries = append(entries, cacheEntry{dir: dir, stamp: stamp})
+		total += stamp.Size
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].stamp.LastAccess.Before(entries[j].stamp.LastAccess)
+	})
+
+	for _, entry := range entries {
+		if total <= maxSize {
+			break
+		}
+
+		if filepath.Clean(entry.dir) == filepath.Clean(keep) {
+			continue
+		}
+
+		removed, err := removeCacheEntry(entry.dir)
+		if err != nil {
+			return err
+		}
+
+		if !removed {
+			log.WithField("path", entry.dir).Debug("Not evicting cache entry in use by another run")
+
+			continue
+		}
+
+		total -= entry.stamp.Size
+
+		log.WithFields(logrus.Fields{
+			"path":        entry.dir,
+			"size":        entry.stamp.Size,
+			"last_access": entry.stamp.LastAccess,
+		}).Info("Evicted least recently used cache entry")
+	}
+
+	return nil
+}
+
+// removeCacheEntry removes the cache entry in dir unless another run holds
+// a lease on it, which is reported as false.
+func removeCacheEntry(dir string) (bool, error) {
+	unlock, locked, err := tryLockCacheEntry(dir)
+	if err != nil {
+		return false, fmt.Errorf("locking cache entry %s: %w", dir, err)
+	}
+
+	if !locked {
+		return false, nil
+	}
+
+	defer unlock()
+
+	if err := os.RemoveAll(dir); err != nil {
+		return false, fmt.Errorf("removing cache entry %s: %w", dir, err)
+	}
+
+	return true, nil
+}
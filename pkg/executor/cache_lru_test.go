@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCacheEntry creates an EEST cache entry in dir holding size bytes of
+// fixtures, last used at lastAccess.
+func writeCacheEntry(t *testing.T, dir string, size int, lastAccess time.Time) {
+	t.Helper()
+
+	fixturesDir := filepath.Join(dir, "fixtures")
+	require.NoError(t, os.MkdirAll(fixturesDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(fixturesDir, "test.json"), make([]byte, size), 0644))
+	require.NoError(t, touchCacheEntry(dir, lastAccess))
+}
+
+func TestPruneCacheLRU(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cacheDir := t.TempDir()
+
+	oldest := filepath.Join(cacheDir, "eest", "aaaa", "benchmark@v0.0.5")
+	middle := filepath.Join(cacheDir, "eest-artifacts", "aaaa", "fixtures_benchmark-1")
+	newest := filepath.Join(cacheDir, "eest-local", "bbbb")
+
+	writeCacheEntry(t, middle, 100, base.Add(time.Hour))
+	writeCacheEntry(t, oldest, 100, base)
+	writeCacheEntry(t, newest, 100, base.Add(2*time.Hour))
+
+	dirs, err := eestCacheEntries(cacheDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{oldest, middle, newest}, dirs)
+
+	log, hook := logtest.NewNullLogger()
+
+	t.Run("within budget", func(t *testing.T) {
+		require.NoError(t, pruneCacheLRU(log, dirs, newest, 300))
+
+		assert.DirExists(t, oldest)
+		assert.DirExists(t, middle)
+		assert.Empty(t, hook.AllEntries())
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		require.NoError(t, pruneCacheLRU(log, dirs, newest, 250))
+
+		assert.NoDirExists(t, oldest)
+		assert.DirExists(t, middle)
+		assert.DirExists(t, newest)
+		require.Len(t, hook.AllEntries(), 1)
+		assert.Equal(t, oldest, hook.LastEntry().Data["path"])
+	})
+
+	t.Run("keeps the entry in use", func(t *testing.T) {
+		// Touching middle makes newest the least recently used entry, but
+		// it is in use.
+		require.NoError(t, touchCacheEntry(middle, base.Add(3*time.Hour)))
+
+		dirs, err := eestCacheEntries(cacheDir)
+		require.NoError(t, err)
+		require.NoError(t, pruneCacheLRU(log, dirs, newest, 50))
+
+		assert.NoDirExists(t, middle)
+		assert.DirExists(t, newest)
+	})
+}
+
+func TestReadCacheStamp_Unstamped(t *testing.T) {
+	dir := t.TempDir()
+
+	fixturesDir := filepath.Join(dir, "fixtures")
+	require.NoError(t, os.MkdirAll(fixturesDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(fixturesDir, "a.json"), make([]byte, 40), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(fixturesDir, "b.json"), make([]byte, 2), 0644))
+
+	modTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(dir, modTime, modTime))
+
+	stamp, err := readCacheStamp(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), stamp.Size)
+	assert.True(t, stamp.LastAccess.Equal(modTime))
+
+	// Touching keeps the measured size.
+	require.NoError(t, touchCacheEntry(dir, modTime.Add(time.Hour)))
+
+	stamp, err = readCacheStamp(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), stamp.Size)
+	assert.True(t, stamp.LastAccess.Equal(modTime.Add(time.Hour)))
+}
+
+func TestEESTSource_UpdateCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	stale := filepath.Join(cacheDir, "eest", "aaaa", "benchmark@v0.0.5")
+	current := filepath.Join(cacheDir, "eest", "aaaa", "benchmark@v0.0.6")
+
+	writeCacheEntry(t, stale, 100, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	writeCacheEntry(t, current, 100, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	log, _ := logtest.NewNullLogger()
+	s := NewEESTSource(log, &config.EESTFixturesSource{CacheMaxSize: "150"}, cacheDir, "", "", download.RetryPolicy{}, false)
+
+	// Using current makes stale the least recently used entry.
+	s.updateCache(current)
+
+	assert.NoDirExists(t, stale)
+	assert.DirExists(t, current)
+}
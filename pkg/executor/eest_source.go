@@ -5,15 +5,20 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
 	"github.com/ethpandaops/benchmarkoor/pkg/eest"
 	"github.com/sirupsen/logrus"
 )
@@ -25,15 +30,26 @@ type EESTSource struct {
 	cacheDir      string
 	filter        string
 	githubToken   string
+	downloadRetry download.RetryPolicy
+	offline       bool // Fail instead of fetching anything missing from the cache
 	fixturesDir   string
 	genesisDir    string
-	tests         []*TestWithSteps
-	genesisGroups []*GenesisGroup
+	// fixturesSubdir is the subdirectory tests were discovered in, which
+	// differs from the configured one when it was auto-detected.
+	fixturesSubdir string
+	// fixtureFormatVersions are the format versions of the discovered
+	// fixtures.
+	fixtureFormatVersions []string
+	tests                 []*TestWithSteps
+	genesisGroups         []*GenesisGroup
 	// resolvedFixturesRunID and resolvedGenesisRunID store the actual run IDs
 	// used when downloading artifacts. When the config doesn't specify a run ID,
 	// these capture the latest run ID that was resolved during download.
 	resolvedFixturesRunID string
 	resolvedGenesisRunID  string
+	// releaseCacheLease releases the lease on the cache entry in use, which
+	// keeps concurrent runs from evicting it.
+	releaseCacheLease func()
 }
 
 // preAllocFile represents the JSON structure of a pre_alloc file.
@@ -42,13 +58,21 @@ type preAllocFile struct {
 }
 
 // NewEESTSource creates a new EEST source.
-func NewEESTSource(log logrus.FieldLogger, cfg *config.EESTFixturesSource, cacheDir, filter, githubToken string) *EESTSource {
+func NewEESTSource(
+	log logrus.FieldLogger,
+	cfg *config.EESTFixturesSource,
+	cacheDir, filter, githubToken string,
+	downloadRetry download.RetryPolicy,
+	offline bool,
+) *EESTSource {
 	return &EESTSource{
-		log:         log.WithField("source", "eest"),
-		cfg:         cfg,
-		cacheDir:    cacheDir,
-		filter:      filter,
-		githubToken: githubToken,
+		log:           log.WithField("source", "eest"),
+		cfg:           cfg,
+		cacheDir:      cacheDir,
+		filter:        filter,
+		githubToken:   githubToken,
+		downloadRetry: downloadRetry,
+		offline:       offline,
 	}
 }
 
@@ -79,8 +103,17 @@ func (s *EESTSource) Prepare(ctx context.Context) (*PreparedSource, error) {
 	var cacheBase string
 
 	if s.cfg.UseArtifacts() {
-		// GitHub token is required for all artifact operations.
-		if s.githubToken == "" {
+		// The latest run can only be looked up online.
+		if s.offline && (s.cfg.FixturesArtifactRunID == "" || s.cfg.GenesisArtifactRunID == "") {
+			return nil, fmt.Errorf(
+				"fixtures_artifact_run_id and genesis_artifact_run_id must be set to "+
+					"use cached artifacts: %w", download.ErrOffline,
+			)
+		}
+
+		// GitHub token is required for all artifact operations. Offline,
+		// only cached artifacts are used.
+		if s.githubToken == "" && !s.offline {
 			return nil, fmt.Errorf(
 				"GitHub token is required for artifact downloads. " +
 					"Set runner.github_token in config or BENCHMARKOOR_RUNNER_GITHUB_TOKEN env var",
@@ -135,8 +168,16 @@ func (s *EESTSource) Prepare(ctx context.Context) (*PreparedSource, error) {
 	s.fixturesDir = filepath.Join(cacheBase, "fixtures")
 	s.genesisDir = filepath.Join(cacheBase, "genesis")
 
+	if err := s.leaseCache(cacheBase); err != nil {
+		return nil, err
+	}
+
 	// Check if already extracted.
 	if _, err := os.Stat(s.fixturesDir); os.IsNotExist(err) {
+		if s.offline {
+			return nil, fmt.Errorf("EEST fixtures not cached in %s: %w", cacheBase, download.ErrOffline)
+		}
+
 		if s.cfg.UseArtifacts() {
 			s.log.Info("Downloading EEST fixtures from GitHub artifacts")
 
@@ -154,6 +195,8 @@ func (s *EESTSource) Prepare(ctx context.Context) (*PreparedSource, error) {
 		s.log.WithField("path", cacheBase).Info("Using cached EEST fixtures")
 	}
 
+	s.updateCache(cacheBase)
+
 	// Parse fixtures and build tests.
 	return s.discoverTests()
 }
@@ -184,20 +227,43 @@ func (s *EESTSource) downloadAndExtract(ctx context.Context, cacheBase string) e
 	// Download and extract fixtures.
 	s.log.WithField("url", fixturesURL).Info("Downloading fixtures tarball")
 
-	if err := s.downloadAndExtractTarball(ctx, fixturesURL, s.fixturesDir); err != nil {
+	if err := s.downloadAndExtractWithFallback(
+		ctx, append([]string{fixturesURL}, s.cfg.FixturesMirrors...), s.fixturesDir,
+	); err != nil {
 		return fmt.Errorf("extracting fixtures: %w", err)
 	}
 
 	// Download and extract genesis.
 	s.log.WithField("url", genesisURL).Info("Downloading genesis tarball")
 
-	if err := s.downloadAndExtractTarball(ctx, genesisURL, s.genesisDir); err != nil {
+	if err := s.downloadAndExtractWithFallback(
+		ctx, append([]string{genesisURL}, s.cfg.GenesisMirrors...), s.genesisDir,
+	); err != nil {
 		return fmt.Errorf("extracting genesis: %w", err)
 	}
 
 	return nil
 }
 
+// downloadAndExtractWithFallback extracts the tarball from the first URL
+// that succeeds. Partially extracted files are removed before the next URL
+// is tried so a failed attempt cannot leave a mix of two tarballs behind.
+func (s *EESTSource) downloadAndExtractWithFallback(
+	ctx context.Context, urls []string, targetDir string,
+) error {
+	return download.WithFallback(ctx, s.log, urls, s.downloadRetry.Wrap(s.log,
+		func(ctx context.Context, url string) error {
+			err := s.downloadAndExtractTarball(ctx, url, targetDir)
+			if err != nil {
+				if rmErr := os.RemoveAll(targetDir); rmErr != nil {
+					return fmt.Errorf("%w (cleanup failed: %w)", err, rmErr)
+				}
+			}
+
+			return err
+		}))
+}
+
 // downloadArtifacts downloads fixtures and genesis from GitHub Actions artifacts.
 func (s *EESTSource) downloadArtifacts(ctx context.Context, cacheBase string) error {
 	if err := os.MkdirAll(cacheBase, 0755); err != nil {
@@ -258,6 +324,10 @@ func (s *EESTSource) prepareLocalTarballs() (*PreparedSource, error) {
 	s.fixturesDir = filepath.Join(cacheBase, "fixtures")
 	s.genesisDir = filepath.Join(cacheBase, "genesis")
 
+	if err := s.leaseCache(cacheBase); err != nil {
+		return nil, err
+	}
+
 	// Check if already extracted.
 	if _, err := os.Stat(s.fixturesDir); os.IsNotExist(err) {
 		s.log.WithFields(logrus.Fields{
@@ -281,9 +351,98 @@ func (s *EESTSource) prepareLocalTarballs() (*PreparedSource, error) {
 		s.log.WithField("path", cacheBase).Info("Using cached local EEST tarballs")
 	}
 
+	s.updateCache(cacheBase)
+
 	return s.discoverTests()
 }
 
+// eestCacheRoots are the subdirectories of the cache dir holding extracted
+// EEST fixtures.
+var eestCacheRoots = []string{"eest", "eest-artifacts", "eest-local"}
+
+// leaseCache takes a lease on the cache entry in cacheBase, held until
+// Cleanup, so that runs sharing the cache dir do not evict it while its
+// fixtures are read.
+func (s *EESTSource) leaseCache(cacheBase string) error {
+	release, err := acquireCacheLease(cacheBase)
+	if err != nil {
+		return fmt.Errorf("leasing cache entry %s: %w", cacheBase, err)
+	}
+
+	if s.releaseCacheLease != nil {
+		s.releaseCacheLease()
+	}
+
+	s.releaseCacheLease = release
+
+	return nil
+}
+
+// updateCache records the use of the cache entry in cacheBase and, when
+// cache_max_size is set, evicts the least recently used EEST cache entries
+// until the cache fits. Failures are logged, as the fixtures are usable
+// regardless.
+func (s *EESTSource) updateCache(cacheBase string) {
+	if err := touchCacheEntry(cacheBase, time.Now()); err != nil {
+		s.log.WithError(err).Warn("Failed to record EEST cache access")
+	}
+
+	if s.cfg.CacheMaxSize == "" {
+		return
+	}
+
+	// Validated in config.
+	maxSize, _ := config.ParseByteSize(s.cfg.CacheMaxSize)
+
+	dirs, err := eestCacheEntries(s.cacheDir)
+	if err != nil {
+		s.log.WithError(err).Warn("Failed to list EEST cache entries")
+
+		return
+	}
+
+	if err := pruneCacheLRU(s.log, dirs, cacheBase, int64(maxSize)); err != nil {
+		s.log.WithError(err).Warn("Failed to prune EEST cache")
+	}
+}
+
+// eestCacheEntries returns the extracted EEST fixture directories in the
+// cache dir. An entry is a directory holding a fixtures directory.
+func eestCacheEntries(cacheDir string) ([]string, error) {
+	var dirs []string
+
+	for _, root := range eestCacheRoots {
+		rootDir := filepath.Join(cacheDir, root)
+
+		err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && path == rootDir {
+					return fs.SkipAll
+				}
+
+				return err
+			}
+
+			if !d.IsDir() || path == rootDir {
+				return nil
+			}
+
+			if info, err := os.Stat(filepath.Join(path, "fixtures")); err == nil && info.IsDir() {
+				dirs = append(dirs, path)
+
+				return fs.SkipDir
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("walking %s: %w", rootDir, err)
+		}
+	}
+
+	return dirs, nil
+}
+
 // ghArtifactList represents a GitHub API response listing artifacts.
 type ghArtifactList struct {
 	Artifacts []ghArtifact `json:"artifacts"`
@@ -480,8 +639,73 @@ func (s *EESTSource) extractLocalTarball(tarballPath, targetDir string) error {
 	return extractTarGzFile(tarballPath, targetDir)
 }
 
+// downloadPartsAndExtract downloads a tarball in cfg.DownloadParts concurrent
+// range requests to a temporary file and extracts it. It returns false
+// without an error when the server cannot serve ranges, so the caller can
+// fall back to a single stream.
+func (s *EESTSource) downloadPartsAndExtract(
+	ctx context.Context, url, targetDir string,
+) (bool, error) {
+	log := s.log.WithField("url", url)
+
+	totalSize, supportsRange, err := probeDownload(ctx, url, "")
+	if err != nil {
+		log.WithError(err).Debug("Range probe failed, downloading as a single stream")
+
+		return false, nil
+	}
+
+	parts := s.cfg.DownloadParts
+	if !supportsRange || totalSize < int64(parts) {
+		log.Info("Server does not support range requests, downloading as a single stream")
+
+		return false, nil
+	}
+
+	parentDir := filepath.Dir(targetDir)
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return false, fmt.Errorf("creating download directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(parentDir, "download-*.tar.gz")
+	if err != nil {
+		return false, fmt.Errorf("creating temp file: %w", err)
+	}
+
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	log.WithFields(logrus.Fields{
+		"size":  formatBytes(totalSize),
+		"parts": parts,
+	}).Info("Downloading with parallel range requests")
+
+	chunkSize := (totalSize + int64(parts) - 1) / int64(parts)
+
+	if err := downloadParallel(
+		ctx, url, tmpPath, "", totalSize, chunkSize, parts, log,
+	); err != nil {
+		return false, err
+	}
+
+	if err := extractTarGzFile(tmpPath, targetDir); err != nil {
+		return false, fmt.Errorf("extracting tarball: %w", err)
+	}
+
+	return true, nil
+}
+
 // downloadAndExtractTarball downloads a tarball and extracts it to the target directory.
 func (s *EESTSource) downloadAndExtractTarball(ctx context.Context, url, targetDir string) error {
+	if s.cfg.DownloadParts > 1 {
+		done, err := s.downloadPartsAndExtract(ctx, url, targetDir)
+		if done || err != nil {
+			return err
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
@@ -495,11 +719,15 @@ func (s *EESTSource) downloadAndExtractTarball(ctx context.Context, url, targetD
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return &download.StatusError{StatusCode: resp.StatusCode}
 	}
 
+	log := s.log.WithField("url", url)
+	body := newProgressReader(resp.Body, resp.ContentLength,
+		progressReportInterval, logDownloadProgress(log))
+
 	// Create gzip reader.
-	gzr, err := gzip.NewReader(resp.Body)
+	gzr, err := gzip.NewReader(body)
 	if err != nil {
 		return fmt.Errorf("creating gzip reader: %w", err)
 	}
@@ -560,6 +788,65 @@ func (s *EESTSource) downloadAndExtractTarball(ctx context.Context, url, targetD
 	return nil
 }
 
+// eestFixturesDirNames are the names of the directories holding engine
+// blockchain tests in known EEST fixture layouts.
+var eestFixturesDirNames = []string{"blockchain_tests_engine_x"}
+
+// detectFixturesSubdir searches fixturesDir for a directory holding engine
+// blockchain tests and returns its path relative to fixturesDir. The
+// shallowest match is used; several matches at that depth are ambiguous.
+func detectFixturesSubdir(fixturesDir string) (string, error) {
+	var (
+		matches []string
+		depth   = -1
+	)
+
+	err := filepath.WalkDir(fixturesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() || path == fixturesDir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(fixturesDir, path)
+		if err != nil {
+			return err
+		}
+
+		relDepth := strings.Count(rel, string(filepath.Separator))
+		if depth >= 0 && relDepth > depth {
+			return fs.SkipDir
+		}
+
+		if !slices.Contains(eestFixturesDirNames, d.Name()) {
+			return nil
+		}
+
+		if depth < 0 || relDepth < depth {
+			depth = relDepth
+			matches = matches[:0]
+		}
+
+		matches = append(matches, rel)
+
+		return fs.SkipDir
+	})
+	if err != nil {
+		return "", fmt.Errorf("searching fixtures: %w", err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s directory found", strings.Join(eestFixturesDirNames, " or "))
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("found several fixtures directories: %s", strings.Join(matches, ", "))
+	}
+}
+
 // discoverTests parses fixture files and creates test entries.
 func (s *EESTSource) discoverTests() (*PreparedSource, error) {
 	// Determine the fixtures search directory.
@@ -570,15 +857,32 @@ func (s *EESTSource) discoverTests() (*PreparedSource, error) {
 
 	searchDir := filepath.Join(s.fixturesDir, fixturesSubdir)
 
-	// Verify the search directory exists.
+	// Look for the engine blockchain tests elsewhere in the tree when the
+	// layout differs from what is configured.
 	if _, err := os.Stat(searchDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("fixtures subdirectory %q does not exist", fixturesSubdir)
+		detected, detectErr := detectFixturesSubdir(s.fixturesDir)
+		if detectErr != nil {
+			return nil, fmt.Errorf(
+				"fixtures subdirectory %q does not exist: %w", fixturesSubdir, detectErr,
+			)
+		}
+
+		s.log.WithFields(logrus.Fields{
+			"configured": fixturesSubdir,
+			"detected":   detected,
+		}).Warn("Fixtures subdirectory not found, using detected subdirectory")
+
+		fixturesSubdir = detected
+		searchDir = filepath.Join(s.fixturesDir, fixturesSubdir)
 	}
 
+	s.fixturesSubdir = fixturesSubdir
+
 	result := &PreparedSource{
 		BasePath:    searchDir,
 		PreRunSteps: make([]*StepFile, 0),
 		Tests:       make([]*TestWithSteps, 0),
+		TestOrder:   TestOrderName,
 	}
 
 	s.log.WithField("path", searchDir).Info("Searching for fixtures")
@@ -586,6 +890,14 @@ func (s *EESTSource) discoverTests() (*PreparedSource, error) {
 	// Map fixture keys (testIds) to their TestWithSteps for pre_alloc matching.
 	testsByFixtureKey := make(map[string]*TestWithSteps, 256)
 
+	// Map fixture keys to the hash of their genesis block.
+	genesisBlockHashes := make(map[string]string, 256)
+
+	// Fixture formats skipped as unsupported, and the format versions of the
+	// supported fixtures.
+	skippedFormats := make(map[string]struct{})
+	formatVersions := make(map[string]struct{})
+
 	// Walk fixture directory for JSON files.
 	err := filepath.Walk(searchDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -635,9 +947,17 @@ func (s *EESTSource) discoverTests() (*PreparedSource, error) {
 					"format":  format,
 				}).Debug("Skipping fixture with unsupported format")
 
+				skippedFormats[format] = struct{}{}
+
 				continue
 			}
 
+			if err := fixture.CheckFormatVersion(); err != nil {
+				return fmt.Errorf("fixture %s in %s: %w", name, path, err)
+			}
+
+			formatVersions[fixture.FormatVersion()] = struct{}{}
+
 			// Apply filter to individual test names too.
 			if s.filter != "" && !strings.Contains(name, s.filter) {
 				continue
@@ -669,6 +989,15 @@ func (s *EESTSource) discoverTests() (*PreparedSource, error) {
 				EESTInfo: fixture.Info,
 			}
 
+			if len(converted.ExpectedPayloadStatus) > 0 {
+				expected, err := NewExpectedResults(converted.ExpectedPayloadStatus)
+				if err != nil {
+					return fmt.Errorf("fixture %s: %w", name, err)
+				}
+
+				test.Expected = expected
+			}
+
 			// Create setup step if there are setup lines.
 			if len(converted.SetupLines) > 0 {
 				test.Setup = &StepFile{
@@ -687,6 +1016,7 @@ func (s *EESTSource) discoverTests() (*PreparedSource, error) {
 
 			result.Tests = append(result.Tests, test)
 			testsByFixtureKey[name] = test
+			genesisBlockHashes[name] = converted.GenesisHash
 		}
 
 		return nil
@@ -696,17 +1026,28 @@ func (s *EESTSource) discoverTests() (*PreparedSource, error) {
 		return nil, fmt.Errorf("walking fixtures directory: %w", err)
 	}
 
+	// Fixtures of only other formats mean the format was renamed or
+	// replaced, which would otherwise silently run nothing.
+	if len(formatVersions) == 0 && len(skippedFormats) > 0 {
+		formats := slices.Sorted(maps.Keys(skippedFormats))
+
+		return nil, fmt.Errorf(
+			"no %s fixtures found in %s, only unsupported formats: %s",
+			eest.SupportedFixtureFormat, searchDir, strings.Join(formats, ", "),
+		)
+	}
+
+	s.fixtureFormatVersions = slices.Sorted(maps.Keys(formatVersions))
+
 	// Sort tests by name for consistent ordering.
-	sort.Slice(result.Tests, func(i, j int) bool {
-		return result.Tests[i].Name < result.Tests[j].Name
-	})
+	sortTests(result.Tests)
 
 	s.tests = result.Tests
 
 	s.log.WithField("count", len(result.Tests)).Info("Discovered EEST fixtures")
 
 	// Parse pre_alloc directory for multi-genesis support.
-	if err := s.parsePreAlloc(searchDir, testsByFixtureKey); err != nil {
+	if err := s.parsePreAlloc(searchDir, testsByFixtureKey, genesisBlockHashes); err != nil {
 		s.log.WithError(err).Warn("Failed to parse pre_alloc directory")
 	}
 
@@ -721,20 +1062,31 @@ func (s *EESTSource) discoverTests() (*PreparedSource, error) {
 		}
 
 		result.Tests = reordered
+		result.TestOrder = TestOrderGenesisName
 		s.tests = reordered
 	}
 
 	return result, nil
 }
 
-// Cleanup is a no-op for EEST sources (we keep the cache).
+// Cleanup releases the lease on the cache entry; the cache itself is kept.
 func (s *EESTSource) Cleanup() error {
+	if s.releaseCacheLease != nil {
+		s.releaseCacheLease()
+		s.releaseCacheLease = nil
+	}
+
 	return nil
 }
 
 // GetSourceInfo returns source information for the suite summary.
 func (s *EESTSource) GetSourceInfo() (*SuiteSource, error) {
-	fixturesSubdir := s.cfg.FixturesSubdir
+	// Use the detected subdir when the configured one was not found.
+	fixturesSubdir := s.fixturesSubdir
+	if fixturesSubdir == "" {
+		fixturesSubdir = s.cfg.FixturesSubdir
+	}
+
 	if fixturesSubdir == "" {
 		fixturesSubdir = config.DefaultEESTFixturesSubdir
 	}
@@ -757,6 +1109,7 @@ func (s *EESTSource) GetSourceInfo() (*SuiteSource, error) {
 			FixturesURL:           s.cfg.FixturesURL,
 			GenesisURL:            s.cfg.GenesisURL,
 			FixturesSubdir:        fixturesSubdir,
+			FixtureFormatVersions: s.fixtureFormatVersions,
 			FixturesArtifactName:  s.cfg.FixturesArtifactName,
 			GenesisArtifactName:   s.cfg.GenesisArtifactName,
 			FixturesArtifactRunID: fixturesRunID,
@@ -769,10 +1122,12 @@ func (s *EESTSource) GetSourceInfo() (*SuiteSource, error) {
 	}, nil
 }
 
-// parsePreAlloc scans the pre_alloc directory and builds genesis groups.
+// parsePreAlloc scans the pre_alloc directory and builds genesis groups. The
+// expected genesis block hash of a group is taken from its tests' fixtures.
 func (s *EESTSource) parsePreAlloc(
 	searchDir string,
 	testsByFixtureKey map[string]*TestWithSteps,
+	genesisBlockHashes map[string]string,
 ) error {
 	preAllocDir := filepath.Join(searchDir, "pre_alloc")
 
@@ -816,10 +1171,23 @@ func (s *EESTSource) parsePreAlloc(
 		hash := strings.TrimSuffix(entry.Name(), ".json")
 		matched := make([]*TestWithSteps, 0, len(paf.TestIDs))
 
+		var genesisBlockHash string
+
 		for _, testID := range paf.TestIDs {
 			if t, ok := testsByFixtureKey[testID]; ok {
 				t.GenesisHash = hash
 				matched = append(matched, t)
+
+				if blockHash := genesisBlockHashes[testID]; genesisBlockHash == "" {
+					genesisBlockHash = blockHash
+				} else if blockHash != "" && blockHash != genesisBlockHash {
+					s.log.WithFields(logrus.Fields{
+						"test_id":      testID,
+						"genesis_hash": hash,
+						"expected":     genesisBlockHash,
+						"got":          blockHash,
+					}).Warn("pre_alloc group tests disagree on the genesis block hash")
+				}
 			} else {
 				s.log.WithFields(logrus.Fields{
 					"test_id":      testID,
@@ -830,13 +1198,12 @@ func (s *EESTSource) parsePreAlloc(
 
 		if len(matched) > 0 {
 			// Sort tests by name for consistent ordering within each group.
-			sort.Slice(matched, func(i, j int) bool {
-				return matched[i].Name < matched[j].Name
-			})
+			sortTests(matched)
 
 			groups = append(groups, &GenesisGroup{
-				GenesisHash: hash,
-				Tests:       matched,
+				GenesisHash:      hash,
+				Tests:            matched,
+				GenesisBlockHash: genesisBlockHash,
 			})
 		}
 	}
@@ -858,7 +1225,7 @@ func (s *EESTSource) GetGenesisGroups() []*GenesisGroup {
 // GetGenesisPathForGroup returns the genesis file path for a specific
 // genesis hash and client type.
 func (s *EESTSource) GetGenesisPathForGroup(genesisHash, clientType string) string {
-	clientDir, filename := s.resolveClientGenesis(clientType)
+	clientDir, filename := clientGenesisFile(clientType)
 
 	genesisPath := filepath.Join(
 		s.genesisDir, "genesis", genesisHash, clientDir, filename,
@@ -877,24 +1244,10 @@ func (s *EESTSource) GetGenesisPathForGroup(genesisHash, clientType string) stri
 	return ""
 }
 
-// resolveClientGenesis maps a client type to its genesis directory and filename.
-func (s *EESTSource) resolveClientGenesis(clientType string) (string, string) {
-	switch clientType {
-	case "geth", "erigon", "reth", "nimbus":
-		return "go-ethereum", "genesis.json"
-	case "nethermind":
-		return "nethermind", "chainspec.json"
-	case "besu":
-		return "besu", "genesis.json"
-	default:
-		return "go-ethereum", "genesis.json"
-	}
-}
-
 // GetGenesisPath returns the genesis file path for a client type.
 // Maps client types to their genesis directories in the EEST release.
 func (s *EESTSource) GetGenesisPath(clientType string) string {
-	clientDir, filename := s.resolveClientGenesis(clientType)
+	clientDir, filename := clientGenesisFile(clientType)
 
 	// Genesis files are in genesis/genesis/<hash>/<client>/<filename>
 	// Find the hash subdirectory (there should typically be one).
@@ -949,6 +1302,9 @@ type EESTSourceInfo struct {
 	FixturesURL    string `json:"fixtures_url,omitempty"`
 	GenesisURL     string `json:"genesis_url,omitempty"`
 	FixturesSubdir string `json:"fixtures_subdir,omitempty"`
+	// FixtureFormatVersions are the format versions of the discovered
+	// fixtures, "1" for fixtures without a version marker.
+	FixtureFormatVersions []string `json:"fixture_format_versions,omitempty"`
 	// Artifact fields (alternative to releases).
 	FixturesArtifactName  string `json:"fixtures_artifact_name,omitempty"`
 	GenesisArtifactName   string `json:"genesis_artifact_name,omitempty"`
@@ -0,0 +1,356 @@
+package executor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEESTSource_DownloadAndExtractWithFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tarballPath := filepath.Join(tmpDir, "fixtures.tar.gz")
+	createTestTarGz(t, tarballPath, map[string]string{
+		"blockchain_tests/test.json": `{"ok":true}`,
+	})
+
+	tarball, err := os.ReadFile(tarballPath)
+	require.NoError(t, err)
+
+	var primaryCalls int
+
+	primary := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			primaryCalls++
+
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	))
+	defer primary.Close()
+
+	// A mirror that returns a truncated tarball, leaving partial output.
+	broken := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(tarball[:len(tarball)/2])
+		},
+	))
+	defer broken.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(tarball)
+		},
+	))
+	defer mirror.Close()
+
+	source := NewEESTSource(logrus.New(), &config.EESTFixturesSource{}, tmpDir, "", "", download.RetryPolicy{}, false)
+	targetDir := filepath.Join(tmpDir, "fixtures")
+
+	err = source.downloadAndExtractWithFallback(t.Context(),
+		[]string{primary.URL, broken.URL, mirror.URL}, targetDir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, primaryCalls)
+
+	data, err := os.ReadFile(filepath.Join(targetDir, "blockchain_tests", "test.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok":true}`, string(data))
+}
+
+func TestEESTSource_DownloadAndExtractWithFallback_AllFail(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	))
+	defer failing.Close()
+
+	tmpDir := t.TempDir()
+	source := NewEESTSource(logrus.New(), &config.EESTFixturesSource{}, tmpDir, "", "", download.RetryPolicy{}, false)
+	targetDir := filepath.Join(tmpDir, "fixtures")
+
+	err := source.downloadAndExtractWithFallback(t.Context(),
+		[]string{failing.URL, failing.URL + "/mirror"}, targetDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 500")
+
+	_, statErr := os.Stat(targetDir)
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestEESTSource_DownloadAndExtractTarball_Parts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Random content keeps the tarball large enough to span every part.
+	content := make([]byte, 256*1024)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+
+	tarballPath := filepath.Join(tmpDir, "fixtures.tar.gz")
+	createTestTarGz(t, tarballPath, map[string]string{
+		"blockchain_tests/big.bin": string(content),
+	})
+
+	tarball, err := os.ReadFile(tarballPath)
+	require.NoError(t, err)
+
+	var rangeRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.Header.Get("Range") != "" {
+			rangeRequests.Add(1)
+		}
+
+		http.ServeContent(w, r, "fixtures.tar.gz", timeZero, newByteReadSeeker(tarball))
+	}))
+	defer srv.Close()
+
+	source := NewEESTSource(logrus.New(), &config.EESTFixturesSource{DownloadParts: 4},
+		tmpDir, "", "", download.RetryPolicy{}, false)
+	targetDir := filepath.Join(tmpDir, "fixtures")
+
+	require.NoError(t, source.downloadAndExtractTarball(t.Context(), srv.URL, targetDir))
+	assert.Equal(t, int32(4), rangeRequests.Load())
+
+	got, err := os.ReadFile(filepath.Join(targetDir, "blockchain_tests", "big.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, sha256.Sum256(content), sha256.Sum256(got))
+
+	// The assembled temporary tarball is removed after extraction.
+	leftovers, err := filepath.Glob(filepath.Join(tmpDir, "download-*.tar.gz"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestEESTSource_DownloadAndExtractTarball_PartsFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tarballPath := filepath.Join(tmpDir, "fixtures.tar.gz")
+	createTestTarGz(t, tarballPath, map[string]string{
+		"blockchain_tests/test.json": `{"ok":true}`,
+	})
+
+	tarball, err := os.ReadFile(tarballPath)
+	require.NoError(t, err)
+
+	var gets atomic.Int32
+
+	// Server that does NOT support range requests.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(tarball)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(tarball)
+	}))
+	defer srv.Close()
+
+	source := NewEESTSource(logrus.New(), &config.EESTFixturesSource{DownloadParts: 4},
+		tmpDir, "", "", download.RetryPolicy{}, false)
+	targetDir := filepath.Join(tmpDir, "fixtures")
+
+	require.NoError(t, source.downloadAndExtractTarball(t.Context(), srv.URL, targetDir))
+	assert.Equal(t, int32(1), gets.Load())
+
+	data, err := os.ReadFile(filepath.Join(targetDir, "blockchain_tests", "test.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok":true}`, string(data))
+}
+
+func TestEESTSource_PrepareOffline(t *testing.T) {
+	release := &config.EESTFixturesSource{
+		GitHubRepo:    "ethereum/execution-spec-tests",
+		GitHubRelease: "benchmark@v0.0.7",
+		// Unreachable, so a cache miss cannot be satisfied by a download.
+		FixturesURL: "http://127.0.0.1:1/fixtures.tar.gz",
+		GenesisURL:  "http://127.0.0.1:1/genesis.tar.gz",
+	}
+
+	t.Run("cache miss", func(t *testing.T) {
+		source := NewEESTSource(logrus.New(), release, t.TempDir(), "", "", download.RetryPolicy{}, true)
+
+		_, err := source.Prepare(t.Context())
+		require.ErrorIs(t, err, download.ErrOffline)
+	})
+
+	t.Run("cache hit", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		cacheBase := filepath.Join(cacheDir, "eest", hashRepoURL(release.GitHubRepo), release.GitHubRelease)
+		require.NoError(t, os.MkdirAll(
+			filepath.Join(cacheBase, "fixtures", config.DefaultEESTFixturesSubdir), 0755,
+		))
+
+		source := NewEESTSource(logrus.New(), release, cacheDir, "", "", download.RetryPolicy{}, true)
+
+		_, err := source.Prepare(t.Context())
+		require.NoError(t, err)
+	})
+
+	t.Run("artifact without run ID", func(t *testing.T) {
+		source := NewEESTSource(logrus.New(), &config.EESTFixturesSource{
+			GitHubRepo:           "ethereum/execution-spec-tests",
+			FixturesArtifactName: "fixtures_benchmark",
+		}, t.TempDir(), "", "", download.RetryPolicy{}, true)
+
+		_, err := source.Prepare(t.Context())
+		require.ErrorIs(t, err, download.ErrOffline)
+		assert.Contains(t, err.Error(), "fixtures_artifact_run_id")
+	})
+}
+
+func TestEESTSource_DetectsFixturesSubdir(t *testing.T) {
+	fixturesDir := t.TempDir()
+	genesisDir := t.TempDir()
+
+	// A layout where the engine tests moved below a release directory.
+	subdir := filepath.Join("benchmark", "fixtures", "blockchain_tests_engine_x")
+	require.NoError(t, os.MkdirAll(filepath.Join(fixturesDir, subdir, "osaka"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(fixturesDir, "benchmark", "fixtures", "state_tests"), 0755))
+
+	source := NewEESTSource(logrus.New(), &config.EESTFixturesSource{
+		LocalFixturesDir: fixturesDir,
+		LocalGenesisDir:  genesisDir,
+	}, t.TempDir(), "", "", download.RetryPolicy{}, false)
+
+	prepared, err := source.Prepare(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(fixturesDir, subdir), prepared.BasePath)
+
+	info, err := source.GetSourceInfo()
+	require.NoError(t, err)
+	assert.Equal(t, subdir, info.EEST.FixturesSubdir)
+}
+
+func TestDetectFixturesSubdir(t *testing.T) {
+	tests := []struct {
+		name    string
+		dirs    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "shallowest match wins",
+			dirs: []string{
+				"a/b/c/blockchain_tests_engine_x",
+				"z/blockchain_tests_engine_x",
+			},
+			want: filepath.Join("z", "blockchain_tests_engine_x"),
+		},
+		{
+			name:    "no match",
+			dirs:    []string{"fixtures/state_tests"},
+			wantErr: "no blockchain_tests_engine_x directory found",
+		},
+		{
+			name: "ambiguous",
+			dirs: []string{
+				"a/blockchain_tests_engine_x",
+				"b/blockchain_tests_engine_x",
+			},
+			wantErr: "found several fixtures directories",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, d := range tt.dirs {
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, d), 0755))
+			}
+
+			got, err := detectFixturesSubdir(dir)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEESTSource_FixtureFormatVersion(t *testing.T) {
+	prepare := func(t *testing.T, info string) (*EESTSource, error) {
+		t.Helper()
+
+		fixturesDir := t.TempDir()
+
+		writeFiles(t, fixturesDir, map[string]string{
+			"fixtures/blockchain_tests_engine_x/test.json": `{"test_a": {"_info": ` + info + `}}`,
+		})
+
+		source := NewEESTSource(logrus.New(), &config.EESTFixturesSource{
+			LocalFixturesDir: fixturesDir,
+			LocalGenesisDir:  t.TempDir(),
+		}, t.TempDir(), "", "", download.RetryPolicy{}, false)
+
+		_, err := source.Prepare(t.Context())
+
+		return source, err
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		source, err := prepare(t,
+			`{"fixture-format": "blockchain_test_engine_x", "fixture-format-version": "1"}`)
+		require.NoError(t, err)
+
+		info, err := source.GetSourceInfo()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1"}, info.EEST.FixtureFormatVersions)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := prepare(t,
+			`{"fixture-format": "blockchain_test_engine_x", "fixture-format-version": "2"}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported blockchain_test_engine_x format version "2"`)
+	})
+
+	t.Run("only other formats", func(t *testing.T) {
+		_, err := prepare(t, `{"fixture-format": "blockchain_test"}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only unsupported formats: blockchain_test")
+	})
+}
+
+func TestEESTSource_GenesisBlockHash(t *testing.T) {
+	fixturesDir := t.TempDir()
+
+	writeFiles(t, fixturesDir, map[string]string{
+		"fixtures/blockchain_tests_engine_x/test.json": `{"test_a": {` +
+			`"_info": {"fixture-format": "blockchain_test_engine_x"},` +
+			`"genesisBlockHeader": {"hash": "0xaaaa"},` +
+			`"engineNewPayloads": [{"params": [{"blockHash": "0x02"}],` +
+			`"newPayloadVersion": "1", "forkchoiceUpdatedVersion": "1"}]}}`,
+		"fixtures/blockchain_tests_engine_x/pre_alloc/0x01.json": `{"testIds": ["test_a"]}`,
+	})
+
+	source := NewEESTSource(logrus.New(), &config.EESTFixturesSource{
+		LocalFixturesDir: fixturesDir,
+		LocalGenesisDir:  t.TempDir(),
+	}, t.TempDir(), "", "", download.RetryPolicy{}, false)
+
+	_, err := source.Prepare(t.Context())
+	require.NoError(t, err)
+
+	groups := source.GetGenesisGroups()
+	require.Len(t, groups, 1)
+	assert.Equal(t, "0x01", groups[0].GenesisHash)
+	assert.Equal(t, "0xaaaa", groups[0].GenesisBlockHash)
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,18 +15,24 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
-	"github.com/docker/docker/client"
 	clientpkg "github.com/ethpandaops/benchmarkoor/pkg/client"
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
 	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
 	"github.com/ethpandaops/benchmarkoor/pkg/jsonrpc"
 	"github.com/ethpandaops/benchmarkoor/pkg/stats"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrJWTAuthFailing is returned when the Engine API keeps rejecting calls with
+// HTTP 401, which almost always means the client uses a different JWT secret.
+var ErrJWTAuthFailing = errors.New("JWT authentication failing")
+
 // Executor runs Engine API tests against a client.
 type Executor interface {
 	Start(ctx context.Context) error
@@ -40,6 +47,10 @@ type Executor interface {
 	// includes the pre-run effects. Returns the number of steps executed.
 	RunPreRunSteps(ctx context.Context, opts *ExecuteOptions) (int, error)
 
+	// FinishRun releases the results accumulated for the run writing to
+	// resultsDir. It is called once no more tests of the run execute.
+	FinishRun(resultsDir string)
+
 	// GetSuiteHash returns the hash of the test suite.
 	GetSuiteHash() string
 
@@ -55,6 +66,20 @@ type BlockLogCollector interface {
 	RegisterBlockHash(testName, blockHash string)
 }
 
+// TestMarker is an interface for marking test boundaries in client logs.
+type TestMarker interface {
+	MarkTestStart(testName string)
+	MarkTestEnd(testName, status string)
+}
+
+// Test statuses reported to a TestMarker when a test ends.
+const (
+	TestMarkerStatusPassed      = "passed"
+	TestMarkerStatusFailed      = "failed"
+	TestMarkerStatusSkipped     = "skipped"
+	TestMarkerStatusInterrupted = "interrupted"
+)
+
 // ExecuteOptions contains options for test execution.
 type ExecuteOptions struct {
 	EngineEndpoint                string
@@ -62,17 +87,37 @@ type ExecuteOptions struct {
 	ResultsDir                    string
 	Filter                        string
 	ContainerID                   string                                // Container ID for stats collection.
-	DockerClient                  *client.Client                        // Docker client for fallback stats reader.
-	DropMemoryCaches              string                                // "tests", "steps", or "" (disabled).
+	DockerClient                  stats.StatsClient                     // Docker client for fallback stats reader (nil if not Docker).
+	DropMemoryCaches              string                                // "tests", "steps", "test-only", or "" (disabled).
 	DropCachesPath                string                                // Path to drop_caches file (default: /proc/sys/vm/drop_caches).
+	DropCachesCommand             []string                              // Command dropping caches instead of writing DropCachesPath (e.g. a sudo-wrapped helper).
+	SyncBetweenTests              bool                                  // Flush pending writes to disk between tests, without dropping caches.
 	RollbackStrategy              string                                // "rpc-debug-setHead" or "" (disabled).
 	RPCEndpoint                   string                                // RPC endpoint for rollback calls (e.g. http://host:port).
 	ClientRPCRollbackSpec         *clientpkg.RPCRollbackSpec            // Client-specific rollback method and param format.
 	Tests                         []*TestWithSteps                      // Optional subset of tests to run (nil = run all).
+	PreRunStepsWithTests          bool                                  // Run pre-run steps even when Tests is set (e.g. when re-running failed tests).
 	BlockLogCollector             BlockLogCollector                     // Optional collector for capturing block logs from client.
+	TestMarker                    TestMarker                            // Optional writer for test boundary markers in the client log.
 	RetryNewPayloadsSyncingConfig *config.RetryNewPayloadsSyncingConfig // Retry config for SYNCING responses.
 	PostTestRPCCalls              []config.PostTestRPCCall              // Arbitrary RPC calls to execute after the test step.
 	PostTestSleepDuration         time.Duration                         // Sleep duration after each test (0 = disabled).
+	EngineLock                    sync.Locker                           // Optional lock held while steps and rollbacks run, so out-of-band Engine API calls never interleave.
+
+	// statsReader reads the resource usage of ContainerID while ExecuteTests
+	// runs. It is per call, since calls for different containers may run
+	// concurrently.
+	statsReader stats.Reader
+
+	// unauthorized counts consecutive Engine API calls rejected with HTTP
+	// 401 during ExecuteTests, so a previous call's count never carries over.
+	unauthorized *unauthorizedCounter
+}
+
+// unauthorizedCounter counts consecutive calls rejected with HTTP 401.
+type unauthorizedCounter struct {
+	mu    sync.Mutex
+	count int
 }
 
 // ExecutionResult contains the overall execution summary.
@@ -80,6 +125,7 @@ type ExecutionResult struct {
 	TotalTests        int
 	Passed            int
 	Failed            int
+	Skipped           int // tests excluded by the filter, without a test step, or skipped after a setup failure
 	TotalDuration     time.Duration
 	StatsReaderType   string // "cgroupv2", "dockerstats", or empty if not available
 	ContainerDied     bool   // true if container exited during execution
@@ -90,12 +136,21 @@ type ExecutionResult struct {
 type Config struct {
 	Source                          *config.SourceConfig
 	Filter                          string
+	AllowEmptyGlobs                 bool                   // Warn instead of failing when a step glob matches no files
+	SkipTestOnSetupFailure          bool                   // Skip the test step, not fail the test, when its setup fails
 	Metadata                        *config.MetadataConfig // Suite-level metadata labels
 	CacheDir                        string
 	ResultsDir                      string
-	ResultsOwner                    *fsutil.OwnerConfig // Optional file ownership for results directory
-	SystemResourceCollectionEnabled bool                // Enable system resource collection (cgroups/Docker Stats)
-	GitHubToken                     string              // Optional GitHub token for API-based artifact downloads
+	ResultsOwner                    *fsutil.OwnerConfig  // Optional file ownership for results directory
+	SystemResourceCollectionEnabled bool                 // Enable system resource collection (cgroups/Docker Stats)
+	GitHubToken                     string               // Optional GitHub token for API-based artifact downloads
+	DownloadRetry                   download.RetryPolicy // Retries for fixture tarball downloads
+	Offline                         bool                 // Fail instead of downloading fixtures missing from the cache
+	TracerProvider                  trace.TracerProvider // Optional provider for test and RPC spans (nil = no-op)
+	QuietRPCLogs                    bool                 // Log each completed RPC call and progress line at debug instead of info level
+	ProgressEveryTests              int                  // Log execution progress every N finished tests (0 = disabled)
+	ProgressInterval                time.Duration        // Log execution progress at least this often (0 = disabled)
+	MaxConsecutiveUnauthorized      int                  // Abort after this many consecutive HTTP 401 responses (0 = disabled)
 }
 
 // NewExecutor creates a new executor instance.
@@ -104,17 +159,23 @@ func NewExecutor(log logrus.FieldLogger, cfg *Config) Executor {
 		log:       log.WithField("component", "executor"),
 		cfg:       cfg,
 		validator: jsonrpc.DefaultValidator(),
+		tracer:    newTracer(cfg.TracerProvider),
+		sync:      syncFilesystems,
+		now:       time.Now,
 	}
 }
 
 type executor struct {
-	log         logrus.FieldLogger
-	cfg         *Config
-	source      Source
-	prepared    *PreparedSource
-	suiteHash   string
-	validator   jsonrpc.Validator
-	statsReader stats.Reader
+	log       logrus.FieldLogger
+	cfg       *Config
+	source    Source
+	prepared  *PreparedSource
+	suiteHash string
+	validator jsonrpc.Validator
+	tracer    trace.Tracer
+	results   runResultAccumulator
+	sync      func() error     // Flushes pending writes to disk
+	now       func() time.Time // Clock for progress reporting
 }
 
 // Ensure interface compliance.
@@ -122,7 +183,10 @@ var _ Executor = (*executor)(nil)
 
 // Start initializes the executor and prepares test sources.
 func (e *executor) Start(ctx context.Context) error {
-	e.source = NewSource(e.log, e.cfg.Source, e.cfg.CacheDir, e.cfg.Filter, e.cfg.GitHubToken)
+	e.source = NewSource(
+		e.log, e.cfg.Source, e.cfg.CacheDir, e.cfg.Filter, e.cfg.GitHubToken, e.cfg.DownloadRetry,
+		e.cfg.AllowEmptyGlobs, e.cfg.Offline,
+	)
 	if e.source == nil {
 		return fmt.Errorf("no test source configured")
 	}
@@ -154,30 +218,32 @@ func (e *executor) Start(ctx context.Context) error {
 
 // createSuiteOutput computes hash and creates suite directory.
 func (e *executor) createSuiteOutput() error {
+	// Get source information.
+	sourceInfo, err := e.source.GetSourceInfo()
+	if err != nil {
+		return fmt.Errorf("getting source info: %w", err)
+	}
+
 	// Compute suite hash from file contents.
-	hash, err := ComputeSuiteHash(e.prepared)
+	hash, err := e.computeSuiteHash(sourceInfo)
 	if err != nil {
 		return fmt.Errorf("computing suite hash: %w", err)
 	}
 
 	e.suiteHash = hash
 
-	// Get source information.
-	sourceInfo, err := e.source.GetSourceInfo()
-	if err != nil {
-		return fmt.Errorf("getting source info: %w", err)
-	}
-
 	// Build suite info.
 	suiteInfo := &SuiteInfo{
-		Hash:     hash,
-		Source:   sourceInfo,
-		Filter:   e.cfg.Filter,
-		Metadata: e.cfg.Metadata,
+		Hash:      hash,
+		Source:    sourceInfo,
+		Filter:    e.cfg.Filter,
+		Metadata:  e.cfg.Metadata,
+		TestOrder: e.prepared.TestOrder,
 	}
 
 	// Create suite output directory.
-	if err := CreateSuiteOutput(e.cfg.ResultsDir, hash, suiteInfo, e.prepared, e.cfg.ResultsOwner); err != nil {
+	reused, err := CreateSuiteOutput(e.cfg.ResultsDir, hash, suiteInfo, e.prepared, e.cfg.ResultsOwner)
+	if err != nil {
 		return fmt.Errorf("creating suite output: %w", err)
 	}
 
@@ -185,6 +251,7 @@ func (e *executor) createSuiteOutput() error {
 		"hash":          hash,
 		"pre_run_steps": len(e.prepared.PreRunSteps),
 		"tests":         len(e.prepared.Tests),
+		"reused":        reused,
 	}).Info("Suite output created")
 
 	return nil
@@ -203,6 +270,12 @@ func (e *executor) Stop() error {
 	return nil
 }
 
+// FinishRun releases the results accumulated for the run writing to
+// resultsDir.
+func (e *executor) FinishRun(resultsDir string) {
+	e.results.forget(resultsDir)
+}
+
 // GetSuiteHash returns the hash of the test suite.
 func (e *executor) GetSuiteHash() string {
 	return e.suiteHash
@@ -247,15 +320,19 @@ func (e *executor) RunPreRunSteps(ctx context.Context, opts *ExecuteOptions) (in
 		log.Info("Running pre-run step")
 
 		preRunResult := NewTestResult(step.Name)
-		if err := e.runStepFile(ctx, opts, step, preRunResult, false); err != nil {
+		if err := e.runStepFile(ctx, opts, step, preRunResult, nil, false); err != nil {
 			log.WithError(err).Warn("Pre-run step failed")
 
 			if ctx.Err() != nil {
 				return 0, fmt.Errorf("context cancelled during pre-run step execution: %w", ctx.Err())
 			}
+
+			if errors.Is(err, ErrJWTAuthFailing) {
+				return 0, fmt.Errorf("running pre-run step %s: %w", step.Name, err)
+			}
 		} else {
-			if err := WriteStepResults(
-				opts.ResultsDir, step.Name, StepTypePreRun, preRunResult, e.cfg.ResultsOwner,
+			if err := e.writeStepResults(
+				opts.ResultsDir, step.Name, StepTypePreRun, preRunResult,
 			); err != nil {
 				log.WithError(err).Warn("Failed to write pre-run step results")
 			}
@@ -267,25 +344,45 @@ func (e *executor) RunPreRunSteps(ctx context.Context, opts *ExecuteOptions) (in
 	return len(e.prepared.PreRunSteps), nil
 }
 
+// writeStepResults writes a step's result files and records its aggregated
+// stats for the run result.
+func (e *executor) writeStepResults(
+	resultsDir, testName string, stepType StepType, result *TestResult,
+) error {
+	stats, err := writeStepResults(resultsDir, testName, stepType, result, e.cfg.ResultsOwner)
+	if err != nil {
+		return err
+	}
+
+	e.results.add(resultsDir, testName, stepType, stats)
+
+	return nil
+}
+
 // ExecuteTests runs all tests against the specified Engine API endpoint.
 // If the context is cancelled (e.g., due to container death), execution stops
-// but partial results are still written.
+// but partial results are still written. Execution is likewise aborted, and the
+// partial result returned along with an ErrJWTAuthFailing error, when the
+// client keeps rejecting calls with HTTP 401.
 func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*ExecutionResult, error) {
 	startTime := time.Now()
 
+	opts.unauthorized = &unauthorizedCounter{}
+	defer func() { opts.unauthorized = nil }()
+
 	// Create stats reader if container ID is provided and collection is enabled.
 	if opts.ContainerID != "" && e.cfg.SystemResourceCollectionEnabled {
 		reader, err := stats.NewReader(e.log, opts.DockerClient, opts.ContainerID)
 		if err != nil {
 			e.log.WithError(err).Warn("Failed to create stats reader, continuing without resource metrics")
 		} else {
-			e.statsReader = reader
+			opts.statsReader = reader
 			defer func() {
 				if closeErr := reader.Close(); closeErr != nil {
 					e.log.WithError(closeErr).Debug("Failed to close stats reader")
 				}
 
-				e.statsReader = nil
+				opts.statsReader = nil
 			}()
 
 			e.log.WithField("type", reader.Type()).Info("Stats reader initialized")
@@ -303,22 +400,35 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 		"tests":         len(tests),
 	}).Info("Starting test execution")
 
-	// Track if execution was interrupted.
+	// Track if execution was interrupted, or aborted with an error.
 	var interrupted bool
 	var interruptReason string
+	var abortErr error
 
-	// Track passed/failed counts directly from the test loop to avoid
-	// miscounts when the results directory is shared across calls.
+	// Track passed/failed/skipped counts directly from the test loop to
+	// avoid miscounts when the results directory is shared across calls.
 	testsPassed := 0
 	testsFailed := 0
+	testsSkipped := 0
+
+	progress := e.newProgressReporter(len(tests))
+
+	// Name of the test whose start marker has no end marker yet, and the
+	// span of the test in progress.
+	var (
+		markedTest string
+		testSpan   trace.Span
+	)
 
 	// Determine cache dropping behavior.
 	dropBetweenTests := opts.DropMemoryCaches == "tests" || opts.DropMemoryCaches == "steps"
 	dropBetweenSteps := opts.DropMemoryCaches == "steps"
+	dropBeforeTestStep := opts.DropMemoryCaches == "test-only"
 	dropCachesPath := opts.DropCachesPath
 
-	// Run pre-run steps first (skip when running a test subset, e.g. multi-genesis).
-	if len(e.prepared.PreRunSteps) > 0 && opts.Tests == nil {
+	// Run pre-run steps first (skip when running a test subset, e.g.
+	// multi-genesis, unless the subset asks for them).
+	if len(e.prepared.PreRunSteps) > 0 && (opts.Tests == nil || opts.PreRunStepsWithTests) {
 		e.log.Info("Running pre-run steps")
 
 		for _, step := range e.prepared.PreRunSteps {
@@ -337,7 +447,7 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 			log.Info("Running pre-run step")
 
 			preRunResult := NewTestResult(step.Name)
-			if err := e.runStepFile(ctx, opts, step, preRunResult, false); err != nil {
+			if err := e.runStepFile(ctx, opts, step, preRunResult, nil, false); err != nil {
 				log.WithError(err).Warn("Pre-run step failed")
 
 				// Check if the failure was due to context cancellation.
@@ -347,8 +457,15 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 
 					goto writeResults
 				}
+
+				if errors.Is(err, ErrJWTAuthFailing) {
+					abortErr = err
+					interruptReason = err.Error()
+
+					goto writeResults
+				}
 			} else {
-				if err := WriteStepResults(opts.ResultsDir, step.Name, StepTypePreRun, preRunResult, e.cfg.ResultsOwner); err != nil {
+				if err := e.writeStepResults(opts.ResultsDir, step.Name, StepTypePreRun, preRunResult); err != nil {
 					log.WithError(err).Warn("Failed to write pre-run step results")
 				}
 			}
@@ -370,11 +487,41 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 		default:
 		}
 
-		// Drop caches between tests (not before first test).
+		if !test.matchesFilter(opts.Filter) {
+			e.log.WithField("test", test.Name).Debug("Skipping test excluded by filter")
+			e.results.skip(opts.ResultsDir, test.Name)
+
+			testsSkipped++
+
+			progress.testFinished(false)
+
+			continue
+		}
+
+		// Running only the setup and cleanup of a test measures nothing, so
+		// a test without a test step is skipped as a whole: none of its
+		// steps run and no rollback point is captured for it.
+		if test.Test == nil {
+			e.log.WithField("test", test.Name).Warn("Skipping test without a test step")
+			e.results.skip(opts.ResultsDir, test.Name)
+
+			testsSkipped++
+
+			progress.testFinished(false)
+
+			continue
+		}
+
+		// Drop caches between tests (not before first test). Dropping
+		// caches syncs first, so a separate sync is only needed otherwise.
 		if dropBetweenTests && i > 0 {
-			if err := e.dropMemoryCaches(dropCachesPath); err != nil {
+			if err := e.dropMemoryCaches(dropCachesPath, opts.DropCachesCommand); err != nil {
 				e.log.WithError(err).Warn("Failed to drop memory caches between tests")
 			}
+		} else if opts.SyncBetweenTests && i > 0 {
+			if err := e.sync(); err != nil {
+				e.log.WithError(err).Warn("Failed to sync between tests")
+			}
 		}
 
 		log := e.log.WithFields(logrus.Fields{
@@ -383,6 +530,14 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 		})
 		log.Info("Running test")
 
+		if opts.TestMarker != nil {
+			opts.TestMarker.MarkTestStart(test.Name)
+			markedTest = test.Name
+		}
+
+		testCtx, span := e.startTestSpan(ctx, test.Name)
+		testSpan = span
+
 		// Capture block info for rollback before the test starts.
 		var rollbackInfo *blockInfo
 		if opts.RollbackStrategy == config.RollbackStrategyRPCDebugSetHead && opts.RPCEndpoint != "" {
@@ -404,6 +559,7 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 		}
 
 		testPassed := true
+		setupPassed := true
 
 		// Run setup step if present.
 		if test.Setup != nil {
@@ -411,9 +567,9 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 
 			setupResult := NewTestResult(test.Name)
 
-			if err := e.runStepFile(ctx, opts, test.Setup, setupResult, false); err != nil {
+			if err := e.runStepFile(testCtx, opts, test.Setup, setupResult, test.Expected, false); err != nil {
 				log.WithError(err).Error("Setup step failed")
-				testPassed = false
+				setupPassed = false
 
 				// Check if the failure was due to context cancellation.
 				if ctx.Err() != nil {
@@ -422,32 +578,49 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 
 					goto writeResults
 				}
+
+				if errors.Is(err, ErrJWTAuthFailing) {
+					abortErr = err
+					interruptReason = err.Error()
+
+					goto writeResults
+				}
 			} else {
 				if setupResult.Failed > 0 {
-					testPassed = false
+					setupPassed = false
 				}
 
 				// Write setup results.
-				if err := WriteStepResults(opts.ResultsDir, test.Name, StepTypeSetup, setupResult, e.cfg.ResultsOwner); err != nil {
+				if err := e.writeStepResults(opts.ResultsDir, test.Name, StepTypeSetup, setupResult); err != nil {
 					log.WithError(err).Warn("Failed to write setup results")
 				}
 			}
 		}
 
-		// Drop caches between setup and test.
-		if dropBetweenSteps && test.Setup != nil && test.Test != nil {
-			if err := e.dropMemoryCaches(dropCachesPath); err != nil {
+		// A failed setup fails the test, unless the test step is skipped
+		// instead.
+		testSkipped := !setupPassed && e.cfg.SkipTestOnSetupFailure
+		if !setupPassed && !testSkipped {
+			testPassed = false
+		}
+
+		runTest := !testSkipped
+
+		// Drop caches between setup and test, or right before every test
+		// step in test-only mode.
+		if runTest && (dropBeforeTestStep || dropBetweenSteps && test.Setup != nil) {
+			if err := e.dropMemoryCaches(dropCachesPath, opts.DropCachesCommand); err != nil {
 				e.log.WithError(err).Warn("Failed to drop memory caches before test step")
 			}
 		}
 
 		// Run test step if present.
-		if test.Test != nil {
+		if runTest {
 			log.Info("Running test step")
 
 			testResult := NewTestResult(test.Name)
 
-			if err := e.runStepFile(ctx, opts, test.Test, testResult, true); err != nil {
+			if err := e.runStepFile(testCtx, opts, test.Test, testResult, test.Expected, true); err != nil {
 				log.WithError(err).Error("Test step failed")
 				testPassed = false
 
@@ -458,26 +631,33 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 
 					goto writeResults
 				}
+
+				if errors.Is(err, ErrJWTAuthFailing) {
+					abortErr = err
+					interruptReason = err.Error()
+
+					goto writeResults
+				}
 			} else {
 				if testResult.Failed > 0 {
 					testPassed = false
 				}
 
 				// Write test results.
-				if err := WriteStepResults(opts.ResultsDir, test.Name, StepTypeTest, testResult, e.cfg.ResultsOwner); err != nil {
+				if err := e.writeStepResults(opts.ResultsDir, test.Name, StepTypeTest, testResult); err != nil {
 					log.WithError(err).Warn("Failed to write test results")
 				}
 			}
 		}
 
 		// Execute post-test RPC calls (not timed, does not affect test results).
-		if len(opts.PostTestRPCCalls) > 0 && opts.RPCEndpoint != "" {
+		if !testSkipped && len(opts.PostTestRPCCalls) > 0 && opts.RPCEndpoint != "" {
 			e.executePostTestRPCCalls(ctx, opts, test.Name, log)
 		}
 
 		// Drop caches between test and cleanup.
-		if dropBetweenSteps && test.Test != nil && test.Cleanup != nil {
-			if err := e.dropMemoryCaches(dropCachesPath); err != nil {
+		if dropBetweenSteps && runTest && test.Cleanup != nil {
+			if err := e.dropMemoryCaches(dropCachesPath, opts.DropCachesCommand); err != nil {
 				e.log.WithError(err).Warn("Failed to drop memory caches before cleanup step")
 			}
 		}
@@ -488,7 +668,7 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 
 			cleanupResult := NewTestResult(test.Name)
 
-			if err := e.runStepFile(ctx, opts, test.Cleanup, cleanupResult, false); err != nil {
+			if err := e.runStepFile(testCtx, opts, test.Cleanup, cleanupResult, test.Expected, false); err != nil {
 				log.WithError(err).Error("Cleanup step failed")
 				testPassed = false
 
@@ -499,13 +679,20 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 
 					goto writeResults
 				}
+
+				if errors.Is(err, ErrJWTAuthFailing) {
+					abortErr = err
+					interruptReason = err.Error()
+
+					goto writeResults
+				}
 			} else {
 				if cleanupResult.Failed > 0 {
 					testPassed = false
 				}
 
 				// Write cleanup results.
-				if err := WriteStepResults(opts.ResultsDir, test.Name, StepTypeCleanup, cleanupResult, e.cfg.ResultsOwner); err != nil {
+				if err := e.writeStepResults(opts.ResultsDir, test.Name, StepTypeCleanup, cleanupResult); err != nil {
 					log.WithError(err).Warn("Failed to write cleanup results")
 				}
 			}
@@ -518,6 +705,8 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 				"rpc_method":   opts.ClientRPCRollbackSpec.RPCMethod,
 			}).Info("Rolling back chain state")
 
+			unlockEngine := lockEngine(opts.EngineLock)
+
 			if rbErr := e.rollback(ctx, opts.RPCEndpoint, opts.ClientRPCRollbackSpec, rollbackInfo); rbErr != nil {
 				log.WithError(rbErr).Warn("Failed to rollback chain state")
 			} else {
@@ -535,6 +724,8 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 					)
 				}
 			}
+
+			unlockEngine()
 		}
 
 		if opts.PostTestSleepDuration > 0 {
@@ -542,16 +733,48 @@ func (e *executor) ExecuteTests(ctx context.Context, opts *ExecuteOptions) (*Exe
 			time.Sleep(opts.PostTestSleepDuration)
 		}
 
-		if testPassed {
-			testsPassed++
-			log.Info("Test completed successfully")
-		} else {
+		var status string
+
+		// A failed cleanup still fails a skipped test.
+		switch {
+		case !testPassed:
 			testsFailed++
+			status = TestMarkerStatusFailed
+
 			log.Warn("Test completed with failures")
+		case testSkipped:
+			testsSkipped++
+			status = TestMarkerStatusSkipped
+
+			e.results.skip(opts.ResultsDir, test.Name)
+			log.Warn("Test skipped after setup failure")
+		default:
+			testsPassed++
+			status = TestMarkerStatusPassed
+
+			log.Info("Test completed successfully")
 		}
+
+		if opts.TestMarker != nil {
+			opts.TestMarker.MarkTestEnd(test.Name, status)
+			markedTest = ""
+		}
+
+		endTestSpan(testSpan, status)
+		testSpan = nil
+
+		progress.testFinished(!testPassed)
 	}
 
 writeResults:
+	if markedTest != "" {
+		opts.TestMarker.MarkTestEnd(markedTest, TestMarkerStatusInterrupted)
+	}
+
+	if testSpan != nil {
+		endTestSpan(testSpan, TestMarkerStatusInterrupted)
+	}
+
 	// Build execution result.
 	result := &ExecutionResult{
 		TotalTests:        len(tests),
@@ -561,14 +784,14 @@ writeResults:
 	}
 
 	// Set stats reader type if available.
-	if e.statsReader != nil {
-		switch e.statsReader.Type() {
+	if opts.statsReader != nil {
+		switch opts.statsReader.Type() {
 		case "cgroup":
 			result.StatsReaderType = "cgroupv2"
 		case "docker":
 			result.StatsReaderType = "dockerstats"
 		default:
-			result.StatsReaderType = e.statsReader.Type()
+			result.StatsReaderType = opts.statsReader.Type()
 		}
 	}
 
@@ -576,44 +799,58 @@ writeResults:
 	// when the results directory is shared across multiple executor calls.
 	result.Passed = testsPassed
 	result.Failed = testsFailed
+	result.Skipped = testsSkipped
 
-	// Write the run result file.
-	runResult, err := GenerateRunResult(opts.ResultsDir)
+	// Write the run result file from the step results accumulated while
+	// writing them, including those of earlier calls sharing the directory.
+	runResult, err := e.results.writeRunResult(opts.ResultsDir, e.cfg.ResultsOwner)
 	if err != nil {
-		e.log.WithError(err).Warn("Failed to generate run result")
+		e.log.WithError(err).Warn("Failed to write run result")
 	} else {
-		if err := WriteRunResult(opts.ResultsDir, runResult, e.cfg.ResultsOwner); err != nil {
-			e.log.WithError(err).Warn("Failed to write run result")
-		} else {
-			e.log.WithFields(logrus.Fields{
-				"tests_count": len(runResult.Tests),
-				"interrupted": interrupted,
-			}).Info("Run result written")
-		}
+		e.log.WithFields(logrus.Fields{
+			"tests_count": len(runResult.Tests),
+			"interrupted": interrupted,
+		}).Info("Run result written")
 	}
 
 	if interrupted {
 		e.log.WithField("reason", interruptReason).Warn("Test execution was interrupted")
 	}
 
-	return result, nil
+	return result, abortErr
 }
 
 // runStepFile executes a single step file or provider.
+// Responses are validated against expected, if set, for the calls it has
+// expectations for.
 // If captureBlockLogs is true, blockHashes from engine_newPayload calls are registered for log matching.
 func (e *executor) runStepFile(
 	ctx context.Context,
 	opts *ExecuteOptions,
 	step *StepFile,
 	result *TestResult,
+	expected *ExpectedResults,
 	captureBlockLogs bool,
 ) error {
+	defer lockEngine(opts.EngineLock)()
+
 	// Use provider if available, otherwise read from file.
 	if step.Provider != nil {
-		return e.runStepLines(ctx, opts, step.Name, step.Provider.Lines(), result, captureBlockLogs)
+		return e.runStepLines(ctx, opts, step.Name, step.Provider.Lines(), result, expected, captureBlockLogs)
 	}
 
-	return e.runStepFromFile(ctx, opts, step, result, captureBlockLogs)
+	return e.runStepFromFile(ctx, opts, step, result, expected, captureBlockLogs)
+}
+
+// lockEngine acquires l, if set, and returns the matching unlock function.
+func lockEngine(l sync.Locker) func() {
+	if l == nil {
+		return func() {}
+	}
+
+	l.Lock()
+
+	return l.Unlock
 }
 
 // runStepFromFile reads and executes lines from a file.
@@ -622,6 +859,7 @@ func (e *executor) runStepFromFile(
 	opts *ExecuteOptions,
 	step *StepFile,
 	result *TestResult,
+	expected *ExpectedResults,
 	captureBlockLogs bool,
 ) error {
 	file, err := os.Open(step.Path)
@@ -652,7 +890,18 @@ func (e *executor) runStepFromFile(
 		}
 	}
 
-	return e.runStepLines(ctx, opts, step.Name, lines, result, captureBlockLogs)
+	return e.runStepLines(ctx, opts, step.Name, lines, result, expected, captureBlockLogs)
+}
+
+// quietLogLevel returns the level completed RPC calls and progress lines are
+// logged at, debug when QuietRPCLogs is set. Failed calls are logged
+// separately at warn level either way.
+func (e *executor) quietLogLevel() logrus.Level {
+	if e.cfg.QuietRPCLogs {
+		return logrus.DebugLevel
+	}
+
+	return logrus.InfoLevel
 }
 
 // runStepLines executes JSON-RPC lines.
@@ -663,6 +912,7 @@ func (e *executor) runStepLines(
 	stepName string,
 	lines []string,
 	result *TestResult,
+	expected *ExpectedResults,
 	captureBlockLogs bool,
 ) error {
 	for lineNum, line := range lines {
@@ -696,15 +946,19 @@ func (e *executor) runStepLines(
 		}
 
 		// Execute RPC call.
-		response, duration, fullDuration, resourceDelta, err := e.executeRPC(ctx, opts.EngineEndpoint, opts.JWT, line)
+		rpcCtx, rpcSpan := e.startRPCSpan(ctx, stepName, method, line)
+		response, httpStatus, duration, fullDuration, resourceDelta, err := e.executeRPC(
+			rpcCtx, opts.statsReader, opts.EngineEndpoint, opts.JWT, line,
+		)
 		succeeded := err == nil
 
 		e.log.WithFields(logrus.Fields{
 			"method":        method,
+			"http_status":   httpStatus,
 			"duration":      time.Duration(duration),
 			"full_duration": time.Duration(fullDuration),
 			"overhead":      time.Duration(fullDuration - duration),
-		}).Info("RPC call completed")
+		}).Log(e.quietLogLevel(), "RPC call completed")
 
 		if err != nil {
 			e.log.WithFields(logrus.Fields{
@@ -714,29 +968,46 @@ func (e *executor) runStepLines(
 			}).WithError(err).Warn("RPC call failed")
 		}
 
-		// Validate response AFTER timing, BEFORE storing result.
-		if succeeded && e.validator != nil && response != "" {
-			if resp, parseErr := jsonrpc.Parse(response); parseErr != nil {
+		// Calls the test has an expected payload status for are checked
+		// against it instead of the default validation.
+		validator := e.validator
+		if status, ok := expected.payloadStatusFor(method, line); ok {
+			validator = jsonrpc.ExpectedStatusValidator(status)
+		}
+
+		unexpected := false
+		malformed := false
+
+		// Validate response AFTER timing, BEFORE storing result. A body
+		// that isn't a JSON-RPC response, e.g. an HTML error page from a
+		// proxy or an empty body, is kept for debugging.
+		if succeeded && validator != nil {
+			if resp, parseErr := parseRPCResponse(response); parseErr != nil {
 				e.log.WithFields(logrus.Fields{
-					"line":   lineNum + 1,
-					"method": method,
-					"step":   stepName,
-				}).WithError(parseErr).Warn("Failed to parse JSON-RPC response")
+					"line":        lineNum + 1,
+					"method":      method,
+					"step":        stepName,
+					"bytes":       len(response),
+					"http_status": httpStatus,
+				}).WithError(parseErr).Warn("Client returned a malformed JSON-RPC response")
 
 				succeeded = false
-			} else if validationErr := e.validator.Validate(method, resp); validationErr != nil {
+				malformed = true
+			} else if validationErr := validator.Validate(method, resp); validationErr != nil {
 				// Check if this is a SYNCING error and retry is enabled.
 				if jsonrpc.IsSyncingError(validationErr) && opts.RetryNewPayloadsSyncingConfig != nil &&
 					opts.RetryNewPayloadsSyncingConfig.Enabled {
-					retrySucceeded, retryResponse, retryDuration := e.retryNewPayloadSyncing(
-						ctx, opts, line, method, stepName, lineNum,
+					retrySucceeded, retryResponse, retryStatus, retryDuration, retryErr := e.retryNewPayloadSyncing(
+						ctx, opts, validator, line, method, stepName, lineNum,
 					)
 					if retrySucceeded {
 						succeeded = true
 						response = retryResponse
+						httpStatus = retryStatus
 						duration = retryDuration
 					} else {
 						succeeded = false
+						unexpected = jsonrpc.IsUnexpectedStatusError(retryErr)
 					}
 				} else {
 					e.log.WithFields(logrus.Fields{
@@ -746,26 +1017,77 @@ func (e *executor) runStepLines(
 					}).WithError(validationErr).Warn("Response validation failed")
 
 					succeeded = false
+					unexpected = jsonrpc.IsUnexpectedStatusError(validationErr)
 				}
 			}
 		}
 
+		endRPCSpan(rpcSpan, duration, succeeded, err)
+
 		if result != nil {
-			result.AddResult(method, line, response, duration, succeeded, resourceDelta)
+			switch {
+			case malformed:
+				result.AddMalformedResult(method, line, response, duration, resourceDelta)
+			case unexpected:
+				result.AddUnexpectedResult(method, line, response, duration, resourceDelta)
+			default:
+				result.AddResult(method, line, response, duration, succeeded, resourceDelta)
+			}
+
+			result.SetHTTPStatus(httpStatus)
+		}
+
+		if err := e.trackUnauthorized(opts.unauthorized, httpStatus); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// trackUnauthorized counts consecutive calls rejected with HTTP 401 and
+// returns an error wrapping ErrJWTAuthFailing once MaxConsecutiveUnauthorized
+// is reached. Calls without a response don't reset the count. Calls made
+// outside ExecuteTests have no counter and are not tracked.
+func (e *executor) trackUnauthorized(counter *unauthorizedCounter, httpStatus int) error {
+	if counter == nil {
+		return nil
+	}
+
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	switch httpStatus {
+	case 0:
+		return nil
+	case http.StatusUnauthorized:
+		counter.count++
+	default:
+		counter.count = 0
+
+		return nil
+	}
+
+	if e.cfg.MaxConsecutiveUnauthorized <= 0 || counter.count < e.cfg.MaxConsecutiveUnauthorized {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: %d consecutive Engine API calls were rejected with HTTP 401, check that the client uses the configured jwt secret",
+		ErrJWTAuthFailing, counter.count,
+	)
+}
+
 // retryNewPayloadSyncing retries an engine_newPayload call when it returns SYNCING status.
-// Returns whether the retry succeeded, the response, and the duration.
+// Returns whether the retry succeeded, the response, its HTTP status code, the
+// duration, and the validation error of the last response that wasn't SYNCING.
 func (e *executor) retryNewPayloadSyncing(
 	ctx context.Context,
 	opts *ExecuteOptions,
+	validator jsonrpc.Validator,
 	payload, method, stepName string,
 	lineNum int,
-) (succeeded bool, response string, duration int64) {
+) (succeeded bool, response string, httpStatus int, duration int64, validationErr error) {
 	cfg := opts.RetryNewPayloadsSyncingConfig
 	backoff, _ := time.ParseDuration(cfg.Backoff) // Already validated in config
 
@@ -782,12 +1104,14 @@ func (e *executor) retryNewPayloadSyncing(
 		// Wait for backoff duration.
 		select {
 		case <-ctx.Done():
-			return false, "", 0
+			return false, "", 0, 0, nil
 		case <-time.After(backoff):
 		}
 
 		// Re-execute RPC call.
-		retryResponse, retryDuration, _, _, err := e.executeRPC(ctx, opts.EngineEndpoint, opts.JWT, payload)
+		retryResponse, retryStatus, retryDuration, _, _, err := e.executeRPC(
+			ctx, opts.statsReader, opts.EngineEndpoint, opts.JWT, payload,
+		)
 		if err != nil {
 			e.log.WithFields(logrus.Fields{
 				"line":    lineNum + 1,
@@ -800,7 +1124,7 @@ func (e *executor) retryNewPayloadSyncing(
 		}
 
 		// Validate the retry response.
-		resp, parseErr := jsonrpc.Parse(retryResponse)
+		resp, parseErr := parseRPCResponse(retryResponse)
 		if parseErr != nil {
 			e.log.WithFields(logrus.Fields{
 				"line":    lineNum + 1,
@@ -812,7 +1136,7 @@ func (e *executor) retryNewPayloadSyncing(
 			continue
 		}
 
-		validationErr := e.validator.Validate(method, resp)
+		validationErr := validator.Validate(method, resp)
 		if validationErr == nil {
 			e.log.WithFields(logrus.Fields{
 				"line":    lineNum + 1,
@@ -821,7 +1145,7 @@ func (e *executor) retryNewPayloadSyncing(
 				"attempt": attempt,
 			}).Info("Retry succeeded")
 
-			return true, retryResponse, retryDuration
+			return true, retryResponse, retryStatus, retryDuration, nil
 		}
 
 		// If still SYNCING, continue retrying.
@@ -844,7 +1168,7 @@ func (e *executor) retryNewPayloadSyncing(
 			"attempt": attempt,
 		}).WithError(validationErr).Warn("Retry validation failed with non-SYNCING error")
 
-		return false, retryResponse, retryDuration
+		return false, retryResponse, retryStatus, retryDuration, validationErr
 	}
 
 	e.log.WithFields(logrus.Fields{
@@ -854,25 +1178,26 @@ func (e *executor) retryNewPayloadSyncing(
 		"max_retries": cfg.MaxRetries,
 	}).Warn("Max retries exceeded for SYNCING status")
 
-	return false, "", 0
+	return false, "", 0, 0, nil
 }
 
 // executeRPC executes a single JSON-RPC call against the Engine API.
-// Returns the response body, duration (server time), full duration (total round-trip),
-// resource delta, and error.
+// Returns the response body, HTTP status code (0 without a response), duration
+// (server time), full duration (total round-trip), resource delta, and error.
 func (e *executor) executeRPC(
 	ctx context.Context,
+	statsReader stats.Reader,
 	endpoint, jwt, payload string,
-) (string, int64, int64, *ResourceDelta, error) {
+) (string, int, int64, int64, *ResourceDelta, error) {
 	token, err := GenerateJWTToken(jwt)
 	if err != nil {
-		return "", 0, 0, nil, fmt.Errorf("generating JWT: %w", err)
+		return "", 0, 0, 0, nil, fmt.Errorf("generating JWT: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint,
 		strings.NewReader(payload))
 	if err != nil {
-		return "", 0, 0, nil, fmt.Errorf("creating request: %w", err)
+		return "", 0, 0, 0, nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -881,18 +1206,18 @@ func (e *executor) executeRPC(
 	// Set up httptrace to measure server time (request written → body fully read).
 	var wroteRequest time.Time
 
-	trace := &httptrace.ClientTrace{
+	clientTrace := &httptrace.ClientTrace{
 		WroteRequest: func(_ httptrace.WroteRequestInfo) {
 			wroteRequest = time.Now()
 		},
 	}
 
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), clientTrace))
 
 	// Read stats BEFORE the request (if reader available).
 	var beforeStats *stats.Stats
-	if e.statsReader != nil {
-		beforeStats, _ = e.statsReader.ReadStats()
+	if statsReader != nil {
+		beforeStats, _ = statsReader.ReadStats()
 	}
 
 	start := time.Now()
@@ -901,8 +1226,8 @@ func (e *executor) executeRPC(
 	// Read stats AFTER the request completes and compute delta.
 	// This captures resource usage during server processing, not during body read.
 	var delta *ResourceDelta
-	if e.statsReader != nil && beforeStats != nil {
-		if afterStats, readErr := e.statsReader.ReadStats(); readErr == nil {
+	if statsReader != nil && beforeStats != nil {
+		if afterStats, readErr := statsReader.ReadStats(); readErr == nil {
 			statsDelta := stats.ComputeDelta(beforeStats, afterStats)
 			if statsDelta != nil {
 				delta = &ResourceDelta{
@@ -921,7 +1246,7 @@ func (e *executor) executeRPC(
 	if err != nil {
 		fullDuration := time.Since(start).Nanoseconds()
 
-		return "", 0, fullDuration, delta, fmt.Errorf("executing request: %w", err)
+		return "", 0, 0, fullDuration, delta, fmt.Errorf("executing request: %w", err)
 	}
 
 	defer func() { _ = resp.Body.Close() }()
@@ -938,10 +1263,10 @@ func (e *executor) executeRPC(
 	}
 
 	if err != nil {
-		return "", duration, fullDuration, delta, fmt.Errorf("reading response: %w", err)
+		return "", resp.StatusCode, duration, fullDuration, delta, fmt.Errorf("reading response: %w", err)
 	}
 
-	return strings.TrimSpace(string(body)), duration, fullDuration, delta, nil
+	return strings.TrimSpace(string(body)), resp.StatusCode, duration, fullDuration, delta, nil
 }
 
 // rpcRequest is used to parse the method from a JSON-RPC request.
@@ -963,11 +1288,53 @@ func extractMethod(payload string) (string, error) {
 	return req.Method, nil
 }
 
+// parseRPCResponse parses a response body as a JSON-RPC response. Empty
+// bodies and JSON without a result or error, e.g. "{}" or "null", are
+// rejected along with non-JSON bodies.
+func parseRPCResponse(body string) (*jsonrpc.Response, error) {
+	if body == "" {
+		return nil, fmt.Errorf("empty response body")
+	}
+
+	resp, err := jsonrpc.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Result == nil && resp.Error == nil {
+		return nil, fmt.Errorf("response has neither a result nor an error")
+	}
+
+	return resp, nil
+}
+
 // dropMemoryCaches syncs filesystem and drops Linux memory caches.
-func (e *executor) dropMemoryCaches(path string) error {
+func (e *executor) dropMemoryCaches(path string, command []string) error {
 	// Sync to flush pending writes to disk.
-	if err := exec.Command("sync").Run(); err != nil {
-		return fmt.Errorf("sync failed: %w", err)
+	if err := e.sync(); err != nil {
+		return err
+	}
+
+	if err := DropCaches(path, command); err != nil {
+		return err
+	}
+
+	e.log.Debug("Dropped memory caches")
+
+	return nil
+}
+
+// DropCaches drops the page cache, dentries and inodes, by running command
+// if set, so an unprivileged process can use a setuid or sudo-wrapped
+// helper, or by writing to the drop_caches file at path otherwise. It does
+// not sync first.
+func DropCaches(path string, command []string) error {
+	if len(command) > 0 {
+		if out, err := exec.Command(command[0], command[1:]...).CombinedOutput(); err != nil {
+			return fmt.Errorf("drop_caches_command: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+
+		return nil
 	}
 
 	// Drop all caches (3 = pagecache + dentries + inodes).
@@ -975,7 +1342,14 @@ func (e *executor) dropMemoryCaches(path string) error {
 		return fmt.Errorf("drop_caches: %w", err)
 	}
 
-	e.log.Debug("Dropped memory caches")
+	return nil
+}
+
+// syncFilesystems flushes pending writes of all filesystems to disk.
+func syncFilesystems() error {
+	if err := exec.Command("sync").Run(); err != nil {
+		return fmt.Errorf("sync failed: %w", err)
+	}
 
 	return nil
 }
@@ -1337,11 +1711,11 @@ func executeSimpleRPC(ctx context.Context, endpoint, payload string) (string, er
 }
 
 // dumpPostTestResponse writes a post-test RPC response to a file.
-// The file is written to {resultsDir}/{testName}/post_test_rpc_calls/{filename}.json.
+// The file is written to {resultsDir}/{testDir}/post_test_rpc_calls/{filename}.json.
 func (e *executor) dumpPostTestResponse(
 	resultsDir, testName, filename, response string,
 ) error {
-	postTestDir := filepath.Join(resultsDir, testName, "post_test_rpc_calls")
+	postTestDir := filepath.Join(resultsDir, testDirName(testName), "post_test_rpc_calls")
 	if err := fsutil.MkdirAll(postTestDir, 0755, e.cfg.ResultsOwner); err != nil {
 		return fmt.Errorf("creating post_test_rpc_calls directory: %w", err)
 	}
@@ -1,8 +1,23 @@
 package executor
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	clientpkg "github.com/ethpandaops/benchmarkoor/pkg/client"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -107,3 +122,895 @@ func TestBuildJSONRPCPayload(t *testing.T) {
 	assert.Contains(t, payload, `"id":1`)
 	assert.Contains(t, payload, `"0x4d2"`)
 }
+
+func TestRunStepFile_HoldsEngineLock(t *testing.T) {
+	var (
+		engineLock sync.Mutex
+		calls      atomic.Int32
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+
+		// The lock must be held for the duration of the call.
+		if engineLock.TryLock() {
+			engineLock.Unlock()
+			t.Error("engine lock not held during step")
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	e := NewExecutor(log, &Config{}).(*executor)
+	step := &StepFile{
+		Name: "test",
+		Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[],"id":1}`,
+			`{"jsonrpc":"2.0","method":"engine_forkchoiceUpdatedV3","params":[],"id":2}`,
+		}},
+	}
+	opts := &ExecuteOptions{
+		EngineEndpoint: srv.URL,
+		JWT:            config.DefaultJWT,
+		EngineLock:     &engineLock,
+	}
+
+	require.NoError(t, e.runStepFile(t.Context(), opts, step, NewTestResult("test"), nil, false))
+	assert.Equal(t, int32(2), calls.Load())
+
+	// Released once the step finishes.
+	require.True(t, engineLock.TryLock())
+	engineLock.Unlock()
+}
+
+func TestRunStepLines_QuietRPCLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "engine_forkchoiceUpdatedV3") {
+			// Drop the connection to fail the call.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			_ = conn.Close()
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	lines := []string{
+		`{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[],"id":1}`,
+		`{"jsonrpc":"2.0","method":"engine_forkchoiceUpdatedV3","params":[],"id":2}`,
+	}
+
+	// run returns the level of each logged message.
+	run := func(t *testing.T, quiet bool) map[string][]logrus.Level {
+		t.Helper()
+
+		log, hook := logtest.NewNullLogger()
+		log.SetLevel(logrus.DebugLevel)
+
+		e := NewExecutor(log, &Config{QuietRPCLogs: quiet}).(*executor)
+		opts := &ExecuteOptions{EngineEndpoint: srv.URL, JWT: config.DefaultJWT}
+
+		require.NoError(t, e.runStepLines(t.Context(), opts, "test", lines, NewTestResult("test"), nil, false))
+
+		levels := make(map[string][]logrus.Level)
+		for _, entry := range hook.AllEntries() {
+			levels[entry.Message] = append(levels[entry.Message], entry.Level)
+		}
+
+		return levels
+	}
+
+	t.Run("default", func(t *testing.T) {
+		levels := run(t, false)
+		assert.Equal(t, []logrus.Level{logrus.InfoLevel, logrus.InfoLevel}, levels["RPC call completed"])
+		assert.Equal(t, []logrus.Level{logrus.WarnLevel}, levels["RPC call failed"])
+	})
+
+	t.Run("quiet", func(t *testing.T) {
+		levels := run(t, true)
+		assert.Equal(t, []logrus.Level{logrus.DebugLevel, logrus.DebugLevel}, levels["RPC call completed"])
+		assert.Equal(t, []logrus.Level{logrus.WarnLevel}, levels["RPC call failed"], "failures stay at warn")
+	})
+}
+
+// recordingTestMarker records the test boundary markers it receives.
+type recordingTestMarker struct {
+	markers []string
+}
+
+func (m *recordingTestMarker) MarkTestStart(testName string) {
+	m.markers = append(m.markers, "start "+testName)
+}
+
+func (m *recordingTestMarker) MarkTestEnd(testName, status string) {
+	m.markers = append(m.markers, "end "+testName+" "+status)
+}
+
+func TestExecuteTests_TestMarkers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "engine_bad") {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"bad"}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	stepFile := func(method string) *StepFile {
+		return &StepFile{Name: "test", Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"` + method + `","params":[],"id":1}`,
+		}}}
+	}
+
+	e := NewExecutor(log, &Config{}).(*executor)
+	e.prepared = &PreparedSource{}
+
+	marker := &recordingTestMarker{}
+
+	result, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+		EngineEndpoint: srv.URL,
+		JWT:            config.DefaultJWT,
+		ResultsDir:     t.TempDir(),
+		TestMarker:     marker,
+		Tests: []*TestWithSteps{
+			{Name: "test_a.txt", Test: stepFile("engine_newPayloadV3")},
+			{Name: "test_b.txt", Test: stepFile("engine_bad")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Passed)
+	assert.Equal(t, 1, result.Failed)
+
+	assert.Equal(t, []string{
+		"start test_a.txt",
+		"end test_a.txt passed",
+		"start test_b.txt",
+		"end test_b.txt failed",
+	}, marker.markers)
+}
+
+func TestRunStepFile_ExpectedPayloadStatus(t *testing.T) {
+	const (
+		validHash   = "0x1111111111111111111111111111111111111111111111111111111111111111"
+		invalidHash = "0x2222222222222222222222222222222222222222222222222222222222222222"
+	)
+
+	newPayload := func(hash string) string {
+		return `{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[{"blockHash":"` + hash + `"}],"id":1}`
+	}
+
+	tests := []struct {
+		name     string
+		status   string
+		statuses []int
+		failed   int
+	}{
+		{
+			name:     "matching",
+			status:   "INVALID",
+			statuses: []int{CallStatusSuccess, CallStatusSuccess},
+		},
+		{
+			name:     "mismatching",
+			status:   "VALID",
+			statuses: []int{CallStatusSuccess, CallStatusUnexpected},
+			failed:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				status := "VALID"
+				if strings.Contains(string(body), invalidHash) {
+					status = tt.status
+				}
+
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"` + status + `"}}`))
+			}))
+			defer srv.Close()
+
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+
+			expected, err := NewExpectedResults(map[string]string{invalidHash: "invalid"})
+			require.NoError(t, err)
+
+			e := NewExecutor(log, &Config{}).(*executor)
+			step := &StepFile{
+				Name:     "test",
+				Provider: &linesProvider{lines: []string{newPayload(validHash), newPayload(invalidHash)}},
+			}
+			opts := &ExecuteOptions{EngineEndpoint: srv.URL, JWT: config.DefaultJWT}
+
+			result := NewTestResult("test")
+			require.NoError(t, e.runStepFile(t.Context(), opts, step, result, expected, false))
+			assert.Equal(t, tt.statuses, result.Statuses)
+			assert.Equal(t, tt.failed, result.Failed)
+		})
+	}
+}
+
+func TestRunStepLines_MalformedResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		response string
+	}{
+		{
+			name:     "html error page",
+			body:     "<html>\n<body>502 Bad Gateway</body>\n</html>\n",
+			response: `"\u003chtml\u003e\n\u003cbody\u003e502 Bad Gateway\u003c/body\u003e\n\u003c/html\u003e"`,
+		},
+		{
+			name:     "empty body",
+			body:     "",
+			response: `""`,
+		},
+		{
+			name:     "json without result or error",
+			body:     "{}",
+			response: `"{}"`,
+		},
+		{
+			name:     "truncated body",
+			body:     strings.Repeat("x", maxMalformedResponseBytes+100),
+			response: `"` + strings.Repeat("x", maxMalformedResponseBytes) + `...(truncated)"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+
+			e := NewExecutor(log, &Config{}).(*executor)
+			opts := &ExecuteOptions{EngineEndpoint: srv.URL, JWT: config.DefaultJWT}
+			lines := []string{`{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[],"id":1}`}
+
+			result := NewTestResult("test")
+			require.NoError(t, e.runStepLines(t.Context(), opts, "test", lines, result, nil, false))
+
+			assert.Equal(t, []int{CallStatusMalformed}, result.Statuses)
+			assert.Equal(t, 1, result.Failed)
+			require.Len(t, result.Responses, 1)
+			assert.Equal(t, tt.response, result.Responses[0])
+			assert.NotContains(t, result.Responses[0], "\n", "response stays on one line")
+		})
+	}
+}
+
+func TestRunStepLines_HTTPStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		switch {
+		case strings.Contains(string(body), "engine_unauthorized"):
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+		case strings.Contains(string(body), "engine_rpcError"):
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"bad"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	e := NewExecutor(log, &Config{}).(*executor)
+	opts := &ExecuteOptions{EngineEndpoint: srv.URL, JWT: config.DefaultJWT}
+	lines := []string{
+		`{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[],"id":1}`,
+		`{"jsonrpc":"2.0","method":"engine_unauthorized","params":[],"id":2}`,
+		`{"jsonrpc":"2.0","method":"engine_rpcError","params":[],"id":3}`,
+	}
+
+	result := NewTestResult("test")
+	require.NoError(t, e.runStepLines(t.Context(), opts, "test", lines, result, nil, false))
+
+	assert.Equal(t, []int{CallStatusSuccess, CallStatusMalformed, CallStatusFailed}, result.Statuses)
+	assert.Equal(t, map[int]int{0: http.StatusOK, 1: http.StatusUnauthorized, 2: http.StatusOK}, result.HTTPStatus)
+
+	// The status codes are written to the result details.
+	dir := t.TempDir()
+	require.NoError(t, WriteStepResults(dir, "test", StepTypeTest, result, nil))
+
+	data, err := os.ReadFile(filepath.Join(dir, "test", "test.result-details.json"))
+	require.NoError(t, err)
+
+	var details ResultDetails
+	require.NoError(t, json.Unmarshal(data, &details))
+	assert.Equal(t, result.HTTPStatus, details.HTTPStatus)
+}
+
+func TestTestResult_SetHTTPStatus(t *testing.T) {
+	result := NewTestResult("test")
+
+	// Nothing to attach the status to yet.
+	result.SetHTTPStatus(http.StatusOK)
+	assert.Empty(t, result.HTTPStatus)
+
+	// A call without a response has no status.
+	result.AddResult("engine_newPayloadV3", "{}", "", 0, false, nil)
+	result.SetHTTPStatus(0)
+	assert.Empty(t, result.HTTPStatus)
+
+	result.AddResult("engine_newPayloadV3", "{}", "", 0, false, nil)
+	result.SetHTTPStatus(http.StatusInternalServerError)
+	assert.Equal(t, map[int]int{1: http.StatusInternalServerError}, result.HTTPStatus)
+}
+
+func TestExecuteTests_AbortsOnConsecutiveUnauthorized(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	stepFile := func(name string) *StepFile {
+		return &StepFile{Name: name, Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[],"id":1}`,
+			`{"jsonrpc":"2.0","method":"engine_forkchoiceUpdatedV3","params":[],"id":2}`,
+		}}}
+	}
+
+	tests := make([]*TestWithSteps, 0, 10)
+	for i := range 10 {
+		name := fmt.Sprintf("test_%d.txt", i)
+		tests = append(tests, &TestWithSteps{Name: name, Test: stepFile(name)})
+	}
+
+	e := NewExecutor(log, &Config{MaxConsecutiveUnauthorized: 3}).(*executor)
+	e.prepared = &PreparedSource{}
+
+	marker := &recordingTestMarker{}
+
+	result, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+		EngineEndpoint: srv.URL,
+		JWT:            config.DefaultJWT,
+		ResultsDir:     t.TempDir(),
+		TestMarker:     marker,
+		Tests:          tests,
+	})
+	require.ErrorIs(t, err, ErrJWTAuthFailing)
+	assert.Contains(t, err.Error(), "3 consecutive Engine API calls were rejected with HTTP 401")
+
+	// The first test fails, the second is aborted at its first call.
+	require.NotNil(t, result)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, err.Error(), result.TerminationReason)
+	assert.Equal(t, []string{
+		"start test_0.txt",
+		"end test_0.txt failed",
+		"start test_1.txt",
+		"end test_1.txt interrupted",
+	}, marker.markers)
+}
+
+func TestExecuteTests_UnauthorizedCountIsPerCall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	e := NewExecutor(log, &Config{MaxConsecutiveUnauthorized: 3}).(*executor)
+	e.prepared = &PreparedSource{}
+
+	// Each call makes two rejected calls, below the limit on its own.
+	for range 2 {
+		_, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+			EngineEndpoint: srv.URL,
+			JWT:            config.DefaultJWT,
+			ResultsDir:     t.TempDir(),
+			Tests: []*TestWithSteps{{
+				Name: "test.txt",
+				Test: &StepFile{Name: "test.txt", Provider: &linesProvider{lines: []string{
+					`{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[],"id":1}`,
+					`{"jsonrpc":"2.0","method":"engine_forkchoiceUpdatedV3","params":[],"id":2}`,
+				}}},
+			}},
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestTrackUnauthorized(t *testing.T) {
+	e := NewExecutor(logrus.New(), &Config{MaxConsecutiveUnauthorized: 2}).(*executor)
+	counter := &unauthorizedCounter{}
+
+	// A response other than 401 resets the count, no response doesn't.
+	require.NoError(t, e.trackUnauthorized(counter, http.StatusUnauthorized))
+	require.NoError(t, e.trackUnauthorized(counter, http.StatusOK))
+	require.NoError(t, e.trackUnauthorized(counter, http.StatusUnauthorized))
+	require.NoError(t, e.trackUnauthorized(counter, 0))
+	require.ErrorIs(t, e.trackUnauthorized(counter, http.StatusUnauthorized), ErrJWTAuthFailing)
+
+	// Without a counter nothing is tracked.
+	for range 100 {
+		require.NoError(t, e.trackUnauthorized(nil, http.StatusUnauthorized))
+	}
+
+	disabled := NewExecutor(logrus.New(), &Config{}).(*executor)
+	disabledCounter := &unauthorizedCounter{}
+
+	for range 100 {
+		require.NoError(t, disabled.trackUnauthorized(disabledCounter, http.StatusUnauthorized))
+	}
+}
+
+func TestExecuteTests_SkipTestOnSetupFailure(t *testing.T) {
+	var calls sync.Map
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.Unmarshal(body, &req)
+
+		count, _ := calls.LoadOrStore(req.Method, new(atomic.Int32))
+		count.(*atomic.Int32).Add(1)
+
+		if req.Method == "engine_bad" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"bad"}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	stepFile := func(method string) *StepFile {
+		return &StepFile{Name: "step", Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"` + method + `","params":[],"id":1}`,
+		}}}
+	}
+
+	callCount := func(method string) int32 {
+		count, ok := calls.Load(method)
+		if !ok {
+			return 0
+		}
+
+		return count.(*atomic.Int32).Load()
+	}
+
+	tests := []struct {
+		name      string
+		skip      bool
+		testCalls int32
+		passed    int
+		failed    int
+		skipped   int
+		markers   []string
+	}{
+		{
+			name:      "disabled",
+			testCalls: 2,
+			passed:    1,
+			failed:    1,
+			markers: []string{
+				"start test_a.txt", "end test_a.txt failed",
+				"start test_b.txt", "end test_b.txt passed",
+			},
+		},
+		{
+			name:      "enabled",
+			skip:      true,
+			testCalls: 1,
+			passed:    1,
+			skipped:   1,
+			markers: []string{
+				"start test_a.txt", "end test_a.txt skipped",
+				"start test_b.txt", "end test_b.txt passed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Clear()
+
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+
+			e := NewExecutor(log, &Config{SkipTestOnSetupFailure: tt.skip}).(*executor)
+			e.prepared = &PreparedSource{}
+
+			marker := &recordingTestMarker{}
+
+			result, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+				EngineEndpoint: srv.URL,
+				JWT:            config.DefaultJWT,
+				ResultsDir:     t.TempDir(),
+				TestMarker:     marker,
+				Tests: []*TestWithSteps{
+					{
+						Name:    "test_a.txt",
+						Setup:   stepFile("engine_bad"),
+						Test:    stepFile("engine_newPayloadV3"),
+						Cleanup: stepFile("test_cleanup"),
+					},
+					{
+						Name:    "test_b.txt",
+						Setup:   stepFile("test_setup"),
+						Test:    stepFile("engine_newPayloadV3"),
+						Cleanup: stepFile("test_cleanup"),
+					},
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.passed, result.Passed)
+			assert.Equal(t, tt.failed, result.Failed)
+			assert.Equal(t, tt.skipped, result.Skipped)
+			assert.Equal(t, tt.markers, marker.markers)
+
+			assert.Equal(t, tt.testCalls, callCount("engine_newPayloadV3"))
+			assert.Equal(t, int32(2), callCount("test_cleanup"), "cleanup always runs")
+		})
+	}
+}
+
+func TestExecuteTests_SkippedCount(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	stepFile := func(name string) *StepFile {
+		return &StepFile{Name: name, Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"test_step","params":[],"id":1}`,
+		}}}
+	}
+
+	e := NewExecutor(log, &Config{}).(*executor)
+	e.prepared = &PreparedSource{}
+
+	resultsDir := t.TempDir()
+	marker := &recordingTestMarker{}
+
+	result, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+		EngineEndpoint: srv.URL,
+		JWT:            config.DefaultJWT,
+		ResultsDir:     resultsDir,
+		Filter:         "bn128",
+		TestMarker:     marker,
+		Tests: []*TestWithSteps{
+			{Name: "bn128_add.txt", Test: stepFile("test/bn128_add.txt")},
+			{Name: "sha256.txt", Test: stepFile("test/sha256.txt")},
+			{Name: "bn128_mul.txt", Setup: stepFile("setup/bn128_mul.txt")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.TotalTests)
+	assert.Equal(t, 1, result.Passed)
+	assert.Equal(t, 0, result.Failed)
+	assert.Equal(t, 2, result.Skipped)
+
+	// Neither the filtered out test nor the test without a test step runs.
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, []string{
+		"start bn128_add.txt",
+		"end bn128_add.txt passed",
+	}, marker.markers)
+
+	assert.Equal(t, 2, e.results.runResult(resultsDir).Skipped)
+}
+
+func TestExecuteTests_PreRunStepsWithTests(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		methods []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req)
+
+		mu.Lock()
+		methods = append(methods, req.Method)
+		mu.Unlock()
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	stepFile := func(method string) *StepFile {
+		return &StepFile{Name: method, Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"` + method + `","params":[],"id":1}`,
+		}}}
+	}
+
+	for _, withPreRun := range []bool{false, true} {
+		methods = nil
+
+		e := NewExecutor(log, &Config{}).(*executor)
+		e.prepared = &PreparedSource{PreRunSteps: []*StepFile{stepFile("pre_run")}}
+
+		_, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+			EngineEndpoint:       srv.URL,
+			JWT:                  config.DefaultJWT,
+			ResultsDir:           t.TempDir(),
+			Tests:                []*TestWithSteps{{Name: "test_a.txt", Test: stepFile("test_a")}},
+			PreRunStepsWithTests: withPreRun,
+		})
+		require.NoError(t, err)
+
+		if withPreRun {
+			assert.Equal(t, []string{"pre_run", "test_a"}, methods)
+		} else {
+			assert.Equal(t, []string{"test_a"}, methods)
+		}
+	}
+}
+
+func TestExecuteTests_SetupOnlyTest(t *testing.T) {
+	var calls, rpcCalls atomic.Int32
+
+	engine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer engine.Close()
+
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		rpcCalls.Add(1)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x1","hash":"0xabc"}}`))
+	}))
+	defer rpc.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	stepFile := func(name string) *StepFile {
+		return &StepFile{Name: name, Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"test_step","params":[],"id":1}`,
+		}}}
+	}
+
+	e := NewExecutor(log, &Config{}).(*executor)
+	e.prepared = &PreparedSource{}
+
+	marker := &recordingTestMarker{}
+
+	result, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+		EngineEndpoint:        engine.URL,
+		JWT:                   config.DefaultJWT,
+		ResultsDir:            t.TempDir(),
+		TestMarker:            marker,
+		RollbackStrategy:      config.RollbackStrategyRPCDebugSetHead,
+		RPCEndpoint:           rpc.URL,
+		ClientRPCRollbackSpec: &clientpkg.RPCRollbackSpec{RPCMethod: "debug_setHead"},
+		Tests: []*TestWithSteps{
+			{Name: "only_setup.txt", Setup: stepFile("setup/only_setup.txt"), Cleanup: stepFile("cleanup/only_setup.txt")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Passed)
+	assert.Equal(t, 0, result.Failed)
+	assert.Equal(t, 1, result.Skipped)
+
+	assert.Zero(t, calls.Load(), "no step of a test without a test step runs")
+	assert.Zero(t, rpcCalls.Load(), "no rollback point is captured")
+	assert.Empty(t, marker.markers)
+}
+
+func TestExecuteTests_DropMemoryCachesTestOnly(t *testing.T) {
+	dropCachesPath := filepath.Join(t.TempDir(), "drop_caches")
+	require.NoError(t, os.WriteFile(dropCachesPath, nil, 0o644))
+
+	// Record each call, preceded by "drop" if caches were dropped since the
+	// previous call.
+	var events []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if data, _ := os.ReadFile(dropCachesPath); len(data) > 0 {
+			events = append(events, "drop")
+			_ = os.WriteFile(dropCachesPath, nil, 0o644)
+		}
+
+		var req struct {
+			Method string `json:"method"`
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req)
+		events = append(events, req.Method)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	stepFile := func(method string) *StepFile {
+		return &StepFile{Name: method, Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"` + method + `","params":[],"id":1}`,
+		}}}
+	}
+
+	e := NewExecutor(log, &Config{}).(*executor)
+	e.prepared = &PreparedSource{}
+
+	_, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+		EngineEndpoint:   srv.URL,
+		JWT:              config.DefaultJWT,
+		ResultsDir:       t.TempDir(),
+		DropMemoryCaches: "test-only",
+		DropCachesPath:   dropCachesPath,
+		Tests: []*TestWithSteps{
+			{Name: "a.txt", Setup: stepFile("test_setup"), Test: stepFile("test_a"), Cleanup: stepFile("test_cleanup")},
+			{Name: "b.txt", Test: stepFile("test_b"), Cleanup: stepFile("test_cleanup")},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"test_setup", "drop", "test_a", "test_cleanup",
+		"drop", "test_b", "test_cleanup",
+	}, events)
+}
+
+func TestExecuteTests_SyncBetweenTests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	stepFile := func(name string) *StepFile {
+		return &StepFile{Name: name, Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"test_step","params":[],"id":1}`,
+		}}}
+	}
+
+	tests := []struct {
+		name             string
+		sync             bool
+		dropMemoryCaches string
+		syncs            int
+	}{
+		{name: "disabled"},
+		{name: "enabled", sync: true, syncs: 2},
+		// Dropping caches already syncs, so there is no extra sync.
+		{name: "with drop between tests", sync: true, dropMemoryCaches: "tests", syncs: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+
+			e := NewExecutor(log, &Config{}).(*executor)
+			e.prepared = &PreparedSource{}
+
+			syncs := 0
+			e.sync = func() error {
+				syncs++
+
+				return nil
+			}
+
+			_, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+				EngineEndpoint:   srv.URL,
+				JWT:              config.DefaultJWT,
+				ResultsDir:       t.TempDir(),
+				SyncBetweenTests: tt.sync,
+				DropMemoryCaches: tt.dropMemoryCaches,
+				DropCachesPath:   filepath.Join(t.TempDir(), "drop_caches"),
+				Tests: []*TestWithSteps{
+					{Name: "a.txt", Test: stepFile("a.txt")},
+					{Name: "b.txt", Test: stepFile("b.txt")},
+					{Name: "c.txt", Test: stepFile("c.txt")},
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.syncs, syncs)
+		})
+	}
+}
+
+func TestExecuteTests_DropCachesCommand(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	stepFile := func(name string) *StepFile {
+		return &StepFile{Name: name, Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"test_step","params":[],"id":1}`,
+		}}}
+	}
+
+	e := NewExecutor(log, &Config{}).(*executor)
+	e.prepared = &PreparedSource{}
+	e.sync = func() error { return nil }
+
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "dropped")
+
+	_, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+		EngineEndpoint:    srv.URL,
+		JWT:               config.DefaultJWT,
+		ResultsDir:        t.TempDir(),
+		DropMemoryCaches:  "tests",
+		DropCachesPath:    filepath.Join(dir, "drop_caches"),
+		DropCachesCommand: []string{"sh", "-c", `echo 3 >> "$0"`, marker},
+		Tests: []*TestWithSteps{
+			{Name: "a.txt", Test: stepFile("a.txt")},
+			{Name: "b.txt", Test: stepFile("b.txt")},
+			{Name: "c.txt", Test: stepFile("c.txt")},
+		},
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(marker)
+	require.NoError(t, err)
+	assert.Equal(t, "3\n3\n", string(data), "the command drops caches between tests")
+
+	_, err = os.Stat(filepath.Join(dir, "drop_caches"))
+	assert.True(t, os.IsNotExist(err), "the drop_caches file is not written")
+}
+
+func TestDropCaches_CommandError(t *testing.T) {
+	err := DropCaches("", []string{"sh", "-c", "echo permission denied >&2; exit 1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
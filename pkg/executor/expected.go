@@ -0,0 +1,149 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// expectedResultsSuffix is the suffix of the sidecar file holding a test's
+// expected results, replacing the .txt extension of its test step file.
+const expectedResultsSuffix = ".expected.json"
+
+// Payload statuses a test can expect for a block.
+var expectablePayloadStatuses = map[string]struct{}{
+	"VALID":              {},
+	"INVALID":            {},
+	"ACCEPTED":           {},
+	"INVALID_BLOCK_HASH": {},
+}
+
+// ExpectedResults holds the results a test expects from the client, loaded
+// from a sidecar file or derived from the fixture.
+type ExpectedResults struct {
+	// PayloadStatus maps block hashes to the status expected from the
+	// engine_newPayload and engine_forkchoiceUpdated calls for that block.
+	// Blocks without an entry are expected to be VALID.
+	PayloadStatus map[string]string `json:"payload_status"`
+}
+
+// NewExpectedResults returns expected results for the given block hash to
+// payload status map, normalizing hashes and statuses.
+func NewExpectedResults(payloadStatus map[string]string) (*ExpectedResults, error) {
+	normalized := make(map[string]string, len(payloadStatus))
+
+	for hash, status := range payloadStatus {
+		status = strings.ToUpper(status)
+		if _, ok := expectablePayloadStatuses[status]; !ok {
+			return nil, fmt.Errorf("unsupported payload status %q for block %s", status, hash)
+		}
+
+		normalized[strings.ToLower(hash)] = status
+	}
+
+	return &ExpectedResults{PayloadStatus: normalized}, nil
+}
+
+// payloadStatusFor returns the payload status expected for a JSON-RPC call,
+// or false if the test has no expectation for it.
+func (x *ExpectedResults) payloadStatusFor(method, request string) (string, bool) {
+	if x == nil || len(x.PayloadStatus) == 0 {
+		return "", false
+	}
+
+	var (
+		hash string
+		err  error
+	)
+
+	switch {
+	case strings.HasPrefix(method, "engine_newPayload"):
+		hash, err = extractBlockHash(request)
+	case strings.HasPrefix(method, "engine_forkchoiceUpdated"):
+		hash, err = extractHeadBlockHash(request)
+	default:
+		return "", false
+	}
+
+	if err != nil {
+		return "", false
+	}
+
+	status, ok := x.PayloadStatus[strings.ToLower(hash)]
+
+	return status, ok
+}
+
+// extractHeadBlockHash extracts the headBlockHash from an
+// engine_forkchoiceUpdated request.
+func extractHeadBlockHash(request string) (string, error) {
+	var req struct {
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(request), &req); err != nil {
+		return "", err
+	}
+
+	if len(req.Params) == 0 {
+		return "", fmt.Errorf("no params")
+	}
+
+	var state struct {
+		HeadBlockHash string `json:"headBlockHash"`
+	}
+	if err := json.Unmarshal(req.Params[0], &state); err != nil {
+		return "", err
+	}
+
+	if state.HeadBlockHash == "" {
+		return "", fmt.Errorf("missing headBlockHash")
+	}
+
+	return state.HeadBlockHash, nil
+}
+
+// loadExpectedResults loads the expected results sidecar of a test step
+// file. It returns nil if the test has no sidecar.
+func loadExpectedResults(stepPath string) (*ExpectedResults, error) {
+	path := strings.TrimSuffix(stepPath, ".txt") + expectedResultsSuffix
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var raw ExpectedResults
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	expected, err := NewExpectedResults(raw.PayloadStatus)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return expected, nil
+}
+
+// attachExpectedResults loads the expected results sidecars of tests.
+func attachExpectedResults(tests []*TestWithSteps) error {
+	for _, test := range tests {
+		if test.Test == nil || test.Test.Path == "" {
+			continue
+		}
+
+		expected, err := loadExpectedResults(test.Test.Path)
+		if err != nil {
+			return fmt.Errorf("loading expected results of %s: %w", test.Name, err)
+		}
+
+		test.Expected = expected
+	}
+
+	return nil
+}
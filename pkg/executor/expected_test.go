@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadExpectedResults(t *testing.T) {
+	dir := t.TempDir()
+	stepPath := filepath.Join(dir, "test_a.txt")
+
+	expected, err := loadExpectedResults(stepPath)
+	require.NoError(t, err)
+	assert.Nil(t, expected, "tests without a sidecar have no expectations")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test_a.expected.json"),
+		[]byte(`{"payload_status":{"0xABC":"invalid"}}`), 0o644))
+
+	expected, err = loadExpectedResults(stepPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"0xabc": "INVALID"}, expected.PayloadStatus)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test_a.expected.json"),
+		[]byte(`{"payload_status":{"0xabc":"SYNCING"}}`), 0o644))
+
+	_, err = loadExpectedResults(stepPath)
+	require.Error(t, err)
+}
+
+func TestExpectedResults_PayloadStatusFor(t *testing.T) {
+	expected, err := NewExpectedResults(map[string]string{"0xabc": "INVALID"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		method  string
+		request string
+		status  string
+		ok      bool
+	}{
+		{
+			name:    "new payload",
+			method:  "engine_newPayloadV3",
+			request: `{"method":"engine_newPayloadV3","params":[{"blockHash":"0xABC"}]}`,
+			status:  "INVALID",
+			ok:      true,
+		},
+		{
+			name:    "forkchoice updated",
+			method:  "engine_forkchoiceUpdatedV3",
+			request: `{"method":"engine_forkchoiceUpdatedV3","params":[{"headBlockHash":"0xabc"},null]}`,
+			status:  "INVALID",
+			ok:      true,
+		},
+		{
+			name:    "other block",
+			method:  "engine_newPayloadV3",
+			request: `{"method":"engine_newPayloadV3","params":[{"blockHash":"0xdef"}]}`,
+		},
+		{
+			name:    "other method",
+			method:  "eth_getBlockByHash",
+			request: `{"method":"eth_getBlockByHash","params":["0xabc",false]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, ok := expected.payloadStatusFor(tt.method, tt.request)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.status, status)
+		})
+	}
+
+	var none *ExpectedResults
+
+	_, ok := none.payloadStatusFor("engine_newPayloadV3", tests[0].request)
+	assert.False(t, ok)
+}
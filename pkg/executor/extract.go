@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -143,27 +144,95 @@ func extractTarGzFile(tarballPath, targetDir string) error {
 	return nil
 }
 
+// innerTarballWorkers bounds how many inner tarballs are extracted at once.
+const innerTarballWorkers = 4
+
 // extractInnerTarballs finds .tar.gz files in the directory, extracts them
-// in-place, and removes the original tarball.
+// in-place, and removes the original tarball. Tarballs are extracted in
+// parallel, each into its own staging directory, and then merged into the
+// directory in name order, so a path in several tarballs ends up with the
+// content of the last one, as if they were extracted one after another.
 func extractInnerTarballs(dir string, log logrus.FieldLogger) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return fmt.Errorf("reading directory %s: %w", dir, err)
 	}
 
+	var tarballs []string
+
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tar.gz") {
 			continue
 		}
 
-		tarballPath := filepath.Join(dir, entry.Name())
+		tarballs = append(tarballs, entry.Name())
+	}
+
+	if len(tarballs) == 0 {
+		return nil
+	}
+
+	stagingDirs := make([]string, len(tarballs))
 
-		log.WithField("file", tarballPath).Debug("Extracting inner tarball")
+	defer func() {
+		for _, stagingDir := range stagingDirs {
+			if stagingDir != "" {
+				_ = os.RemoveAll(stagingDir)
+			}
+		}
+	}()
 
-		if err := extractTarGzFile(tarballPath, dir); err != nil {
-			return fmt.Errorf("extracting %s: %w", entry.Name(), err)
+	for i := range tarballs {
+		stagingDir, err := os.MkdirTemp(dir, ".extract-*")
+		if err != nil {
+			return fmt.Errorf("creating staging directory: %w", err)
 		}
 
+		stagingDirs[i] = stagingDir
+	}
+
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, innerTarballWorkers)
+		errs = make([]error, len(tarballs))
+	)
+
+	for i, name := range tarballs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			tarballPath := filepath.Join(dir, name)
+
+			log.WithField("file", tarballPath).Debug("Extracting inner tarball")
+
+			if err := extractTarGzFile(tarballPath, stagingDirs[i]); err != nil {
+				errs[i] = fmt.Errorf("extracting %s: %w", name, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Report the failure of the first tarball by name, regardless of which
+	// worker finished first.
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, name := range tarballs {
+		if err := mergeDir(stagingDirs[i], dir); err != nil {
+			return fmt.Errorf("merging %s: %w", name, err)
+		}
+
+		tarballPath := filepath.Join(dir, name)
+
 		// Remove the tarball after successful extraction.
 		if err := os.Remove(tarballPath); err != nil {
 			log.WithError(err).WithField("file", tarballPath).
@@ -174,6 +243,37 @@ func extractInnerTarballs(dir string, log logrus.FieldLogger) error {
 	return nil
 }
 
+// mergeDir moves the content of src into dst, replacing files that exist in
+// both. Directories missing from dst are moved as a whole.
+func mergeDir(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("reading directory %s: %w", src, err)
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(src, entry.Name())
+		dstPath := filepath.Join(dst, entry.Name())
+
+		if entry.IsDir() {
+			info, err := os.Stat(dstPath)
+			if err == nil && info.IsDir() {
+				if err := mergeDir(srcPath, dstPath); err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		if err := os.Rename(srcPath, dstPath); err != nil {
+			return fmt.Errorf("moving %s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 const (
 	progressLogInterval = 10 * 1024 * 1024 // 10 MiB between progress logs
 	defaultChunkSize    = 25 * 1024 * 1024 // 25 MiB per chunk
@@ -198,7 +298,8 @@ func downloadToFile(
 			"workers": defaultParallelism,
 		}).Info("Downloading with parallel range requests")
 
-		return downloadParallel(ctx, url, destPath, bearerToken, totalSize, log)
+		return downloadParallel(ctx, url, destPath, bearerToken, totalSize,
+			defaultChunkSize, defaultParallelism, log)
 	}
 
 	if totalSize > 0 {
@@ -286,11 +387,12 @@ func downloadSequential(
 	return nil
 }
 
-// downloadParallel downloads the file using multiple concurrent range requests
-// and assembles the chunks into the destination file.
+// downloadParallel downloads the file using concurrent range requests of
+// chunkSize bytes, at most workers at a time, and assembles the chunks into
+// the destination file.
 func downloadParallel(
 	ctx context.Context, url, destPath, bearerToken string,
-	totalSize int64, log logrus.FieldLogger,
+	totalSize, chunkSize int64, workers int, log logrus.FieldLogger,
 ) error {
 	// Pre-allocate the output file.
 	out, err := os.Create(destPath)
@@ -314,10 +416,10 @@ func downloadParallel(
 		start, end int64 // inclusive byte range
 	}
 
-	chunks := make([]chunk, 0, (totalSize/defaultChunkSize)+1)
+	chunks := make([]chunk, 0, (totalSize/chunkSize)+1)
 
-	for start := int64(0); start < totalSize; start += defaultChunkSize {
-		end := start + defaultChunkSize - 1
+	for start := int64(0); start < totalSize; start += chunkSize {
+		end := start + chunkSize - 1
 		if end >= totalSize {
 			end = totalSize - 1
 		}
@@ -336,7 +438,7 @@ func downloadParallel(
 		wg      sync.WaitGroup
 		errOnce sync.Once
 		dlErr   error
-		sem     = make(chan struct{}, defaultParallelism)
+		sem     = make(chan struct{}, workers)
 	)
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -376,6 +478,14 @@ func downloadParallel(
 		return fmt.Errorf("parallel download failed: %w", dlErr)
 	}
 
+	// Every chunk must have delivered its full range.
+	if written := pw.Written(); written != totalSize {
+		_ = os.Remove(destPath)
+
+		return fmt.Errorf("parallel download incomplete: got %s of %s",
+			formatBytes(written), formatBytes(totalSize))
+	}
+
 	log.WithField("size", formatBytes(totalSize)).Info("Download complete")
 
 	return nil
@@ -482,6 +592,75 @@ func (pl *progressLogger) Write(p []byte) (int, error) {
 	return n, nil
 }
 
+// progressReportInterval is how often progressReader reports download
+// progress.
+const progressReportInterval = 5 * time.Second
+
+// progressReader wraps a download body, counts the bytes read through it
+// and calls report at most once per interval, plus once at EOF.
+type progressReader struct {
+	r        io.Reader
+	total    int64 // -1 if unknown
+	read     int64
+	interval time.Duration
+	last     time.Time
+	report   func(read, total int64)
+	done     bool
+}
+
+// newProgressReader creates a progressReader. total is the expected size,
+// usually the response Content-Length, or -1 if unknown.
+func newProgressReader(
+	r io.Reader, total int64, interval time.Duration, report func(read, total int64),
+) *progressReader {
+	return &progressReader{
+		r:        r,
+		total:    total,
+		interval: interval,
+		last:     time.Now(),
+		report:   report,
+	}
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	pr.read += int64(n)
+
+	if err == io.EOF {
+		if !pr.done {
+			pr.done = true
+			pr.report(pr.read, pr.total)
+		}
+
+		return n, err
+	}
+
+	if n > 0 && time.Since(pr.last) >= pr.interval {
+		pr.last = time.Now()
+		pr.report(pr.read, pr.total)
+	}
+
+	return n, err
+}
+
+// logDownloadProgress returns a progressReader callback that logs the
+// downloaded size and, when the total is known, the percentage.
+func logDownloadProgress(log logrus.FieldLogger) func(read, total int64) {
+	return func(read, total int64) {
+		fields := logrus.Fields{
+			"downloaded": formatBytes(read),
+		}
+
+		if total > 0 {
+			pct := float64(read) / float64(total) * 100
+			fields["total"] = formatBytes(total)
+			fields["progress"] = fmt.Sprintf("%.0f%%", pct)
+		}
+
+		log.WithFields(fields).Info("Downloading")
+	}
+}
+
 // formatBytes returns a human-readable byte size string.
 func formatBytes(b int64) string {
 	const (
@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type progressCall struct {
+	read, total int64
+}
+
+func TestProgressReader_ReportsEveryInterval(t *testing.T) {
+	body := bytes.Repeat([]byte("x"), 4096)
+
+	var calls []progressCall
+
+	// A zero interval reports on every read; HalfReader forces several reads.
+	pr := newProgressReader(iotest.HalfReader(bytes.NewReader(body)), int64(len(body)), 0,
+		func(read, total int64) {
+			calls = append(calls, progressCall{read: read, total: total})
+		})
+
+	data, err := io.ReadAll(pr)
+	require.NoError(t, err)
+	assert.Len(t, data, len(body))
+
+	require.Greater(t, len(calls), 2)
+
+	for i, c := range calls {
+		assert.Equal(t, int64(len(body)), c.total)
+
+		if i > 0 {
+			assert.GreaterOrEqual(t, c.read, calls[i-1].read)
+		}
+	}
+
+	assert.Equal(t, progressCall{read: 4096, total: 4096}, calls[len(calls)-1])
+}
+
+func TestProgressReader_ReportsOnceAtEOF(t *testing.T) {
+	body := bytes.Repeat([]byte("x"), 1000)
+
+	var calls []progressCall
+
+	pr := newProgressReader(iotest.HalfReader(bytes.NewReader(body)), -1, time.Hour,
+		func(read, total int64) {
+			calls = append(calls, progressCall{read: read, total: total})
+		})
+
+	_, err := io.ReadAll(pr)
+	require.NoError(t, err)
+
+	// Further reads after EOF must not report again.
+	_, err = pr.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	assert.Equal(t, []progressCall{{read: 1000, total: -1}}, calls)
+}
+
+func TestExtractInnerTarballs(t *testing.T) {
+	dir := t.TempDir()
+
+	// More tarballs than workers, all sharing the fixtures directory.
+	const tarballs = 3 * innerTarballWorkers
+
+	for i := range tarballs {
+		createTestTarGz(t, filepath.Join(dir, fmt.Sprintf("part-%02d.tar.gz", i)), map[string]string{
+			fmt.Sprintf("fixtures/tests/test-%02d.json", i): fmt.Sprintf(`{"part":%d}`, i),
+			"fixtures/shared.json":                          fmt.Sprintf(`{"part":%d}`, i),
+		})
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	require.NoError(t, extractInnerTarballs(dir, log))
+
+	for i := range tarballs {
+		data, err := os.ReadFile(filepath.Join(dir, "fixtures", "tests", fmt.Sprintf("test-%02d.json", i)))
+		require.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`{"part":%d}`, i), string(data))
+	}
+
+	// A path in several tarballs has the content of the last one by name.
+	data, err := os.ReadFile(filepath.Join(dir, "fixtures", "shared.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"part":%d}`, tarballs-1), string(data))
+
+	// Only the extracted content is left: no tarballs or staging dirs.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "fixtures", entries[0].Name())
+}
+
+func TestExtractInnerTarballs_ReportsFirstFailureByName(t *testing.T) {
+	dir := t.TempDir()
+
+	createTestTarGz(t, filepath.Join(dir, "a.tar.gz"), map[string]string{"a.json": "{}"})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.tar.gz"), []byte("not gzip"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.tar.gz"), []byte("not gzip"), 0644))
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	err := extractInnerTarballs(dir, log)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "extracting b.tar.gz")
+
+	// Staging dirs are removed and the tarballs are left in place.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	assert.Equal(t, []string{"a.tar.gz", "b.tar.gz", "c.tar.gz"}, names)
+}
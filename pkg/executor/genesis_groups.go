@@ -0,0 +1,194 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// genesisGroupsManifest is the manifest in the genesis directory of a local
+// or git source declaring its genesis groups.
+const genesisGroupsManifest = "groups.yaml"
+
+// genesisGroupNamePattern restricts group names to characters that are safe
+// in container, volume and directory names, which are derived from them.
+var genesisGroupNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// genesisGroupsFile is the format of the genesis group manifest:
+//
+//	groups:
+//	  - name: small-state
+//	    tests:
+//	      - "small/*"
+//	  - name: big-state
+//	    tests:
+//	      - "big/*"
+//
+// Each group's genesis files live in genesis/<name>/, laid out per client
+// like the genesis directory itself.
+type genesisGroupsFile struct {
+	Groups []genesisGroupEntry `yaml:"groups"`
+}
+
+// genesisGroupEntry is a single group of the genesis group manifest.
+type genesisGroupEntry struct {
+	Name string `yaml:"name"`
+	// Tests are path.Match patterns matched against test names.
+	Tests []string `yaml:"tests"`
+}
+
+// loadGenesisGroups reads the genesis group manifest of the source at basePath
+// and assigns the prepared tests to its groups. Tests are reordered group by
+// group, in manifest order. Returns nil if the source has no manifest.
+func loadGenesisGroups(
+	log logrus.FieldLogger,
+	basePath string,
+	prepared *PreparedSource,
+) ([]*GenesisGroup, error) {
+	manifestPath := filepath.Join(basePath, sourceGenesisDir, genesisGroupsManifest)
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading genesis groups manifest: %w", err)
+	}
+
+	var manifest genesisGroupsFile
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing genesis groups manifest %s: %w", manifestPath, err)
+	}
+
+	if err := validateGenesisGroups(manifest.Groups); err != nil {
+		return nil, fmt.Errorf("genesis groups manifest %s: %w", manifestPath, err)
+	}
+
+	testsByGroup := make(map[string][]*TestWithSteps, len(manifest.Groups))
+
+	for _, test := range prepared.Tests {
+		group, err := genesisGroupFor(manifest.Groups, test.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		test.GenesisHash = group
+		testsByGroup[group] = append(testsByGroup[group], test)
+	}
+
+	groups := make([]*GenesisGroup, 0, len(manifest.Groups))
+	reordered := make([]*TestWithSteps, 0, len(prepared.Tests))
+
+	for _, entry := range manifest.Groups {
+		tests := testsByGroup[entry.Name]
+		if len(tests) == 0 {
+			log.WithField("genesis_group", entry.Name).Debug("Genesis group has no tests, skipping")
+
+			continue
+		}
+
+		groups = append(groups, &GenesisGroup{
+			GenesisHash: entry.Name,
+			Tests:       tests,
+		})
+		reordered = append(reordered, tests...)
+	}
+
+	prepared.Tests = reordered
+	prepared.TestOrder = TestOrderGenesisName
+
+	log.WithField("groups", len(groups)).Info("Discovered genesis groups from manifest")
+
+	return groups, nil
+}
+
+// validateGenesisGroups checks the groups of a genesis group manifest.
+func validateGenesisGroups(groups []genesisGroupEntry) error {
+	if len(groups) == 0 {
+		return fmt.Errorf("no groups declared")
+	}
+
+	seen := make(map[string]struct{}, len(groups))
+
+	for _, group := range groups {
+		if !genesisGroupNamePattern.MatchString(group.Name) {
+			return fmt.Errorf("invalid group name %q", group.Name)
+		}
+
+		if _, ok := seen[group.Name]; ok {
+			return fmt.Errorf("duplicate group name %q", group.Name)
+		}
+
+		seen[group.Name] = struct{}{}
+
+		if len(group.Tests) == 0 {
+			return fmt.Errorf("group %q has no test patterns", group.Name)
+		}
+
+		for _, pattern := range group.Tests {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("group %q: invalid test pattern %q: %w", group.Name, pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// genesisGroupFor returns the name of the single group whose patterns match
+// a test name.
+func genesisGroupFor(groups []genesisGroupEntry, testName string) (string, error) {
+	var matched string
+
+	for _, group := range groups {
+		for _, pattern := range group.Tests {
+			if ok, _ := path.Match(pattern, testName); !ok {
+				continue
+			}
+
+			if matched != "" {
+				return "", fmt.Errorf(
+					"test %q matches genesis groups %q and %q", testName, matched, group.Name,
+				)
+			}
+
+			matched = group.Name
+
+			break
+		}
+	}
+
+	if matched == "" {
+		return "", fmt.Errorf("test %q is not in any genesis group", testName)
+	}
+
+	return matched, nil
+}
+
+// sourceGenesisPathForGroup returns the genesis file for a client type of a
+// genesis group of the source at basePath, or an empty string if it has none.
+func sourceGenesisPathForGroup(
+	log logrus.FieldLogger,
+	basePath, group, clientType string,
+) string {
+	clientDir, filename := clientGenesisFile(clientType)
+	genesisPath := filepath.Join(basePath, sourceGenesisDir, group, clientDir, filename)
+
+	if _, err := os.Stat(genesisPath); err != nil {
+		log.WithFields(logrus.Fields{
+			"genesis_group": group,
+			"client":        clientType,
+			"path":          genesisPath,
+		}).Warn("Genesis file not found for group")
+
+		return ""
+	}
+
+	return genesisPath
+}
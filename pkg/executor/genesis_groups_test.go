@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles writes files relative to base.
+func writeFiles(t *testing.T, base string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(base, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// prepareLocalSource prepares a local source testing tests/test/**.
+func prepareLocalSource(t *testing.T, base string) (Source, *PreparedSource, error) {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	src := NewSource(log, &config.SourceConfig{
+		Local: &config.LocalSourceV2{
+			BaseDir: base,
+			Steps:   &config.StepsConfig{Test: []string{"tests/test/*/*.txt"}},
+		},
+	}, "", "", "", download.RetryPolicy{}, false, false)
+
+	prepared, err := src.Prepare(t.Context())
+
+	return src, prepared, err
+}
+
+func TestLocalSource_GenesisGroups(t *testing.T) {
+	base := t.TempDir()
+
+	writeFiles(t, base, map[string]string{
+		"tests/test/small/a.txt": "payload",
+		"tests/test/small/b.txt": "payload",
+		"tests/test/big/c.txt":   "payload",
+		"genesis/groups.yaml": `groups:
+  - name: big-state
+    tests:
+      - "big/*"
+  - name: small-state
+    tests:
+      - "small/*"
+  - name: unused
+    tests:
+      - "unused/*"
+`,
+		"genesis/big-state/go-ethereum/genesis.json":    "{}",
+		"genesis/small-state/go-ethereum/genesis.json":  "{}",
+		"genesis/small-state/nethermind/chainspec.json": "{}",
+	})
+
+	src, prepared, err := prepareLocalSource(t, base)
+	require.NoError(t, err)
+
+	ggp, ok := src.(GenesisGroupProvider)
+	require.True(t, ok)
+
+	groups := ggp.GetGenesisGroups()
+	require.Len(t, groups, 2)
+
+	assert.Equal(t, "big-state", groups[0].GenesisHash)
+	assert.Equal(t, []string{"big/c.txt"}, testNames(groups[0].Tests))
+	assert.Equal(t, "small-state", groups[1].GenesisHash)
+	assert.Equal(t, []string{"small/a.txt", "small/b.txt"}, testNames(groups[1].Tests))
+
+	// Tests run group by group, in manifest order.
+	assert.Equal(t, []string{"big/c.txt", "small/a.txt", "small/b.txt"}, testNames(prepared.Tests))
+	assert.Equal(t, TestOrderGenesisName, prepared.TestOrder)
+	assert.Equal(t, "small-state", prepared.Tests[1].GenesisHash)
+
+	assert.Equal(t,
+		filepath.Join(base, "genesis", "small-state", "nethermind", "chainspec.json"),
+		ggp.GetGenesisPathForGroup("small-state", "nethermind"),
+	)
+	assert.Equal(t,
+		filepath.Join(base, "genesis", "big-state", "go-ethereum", "genesis.json"),
+		ggp.GetGenesisPathForGroup("big-state", "reth"),
+	)
+	assert.Empty(t, ggp.GetGenesisPathForGroup("big-state", "nethermind"))
+}
+
+func TestLocalSource_NoGenesisGroups(t *testing.T) {
+	base := t.TempDir()
+
+	writeFiles(t, base, map[string]string{"tests/test/small/a.txt": "payload"})
+
+	src, prepared, err := prepareLocalSource(t, base)
+	require.NoError(t, err)
+
+	assert.Nil(t, src.(GenesisGroupProvider).GetGenesisGroups())
+	assert.Equal(t, TestOrderName, prepared.TestOrder)
+	assert.Empty(t, prepared.Tests[0].GenesisHash)
+}
+
+func TestLocalSource_InvalidGenesisGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			name:     "no groups",
+			manifest: "groups: []\n",
+			wantErr:  "no groups declared",
+		},
+		{
+			name:     "invalid name",
+			manifest: "groups:\n  - name: ../x\n    tests: [\"*\"]\n",
+			wantErr:  `invalid group name "../x"`,
+		},
+		{
+			name:     "duplicate name",
+			manifest: "groups:\n  - name: a\n    tests: [\"small/*\"]\n  - name: a\n    tests: [\"big/*\"]\n",
+			wantErr:  `duplicate group name "a"`,
+		},
+		{
+			name:     "no patterns",
+			manifest: "groups:\n  - name: a\n",
+			wantErr:  `group "a" has no test patterns`,
+		},
+		{
+			name:     "invalid pattern",
+			manifest: "groups:\n  - name: a\n    tests: [\"[\"]\n",
+			wantErr:  `invalid test pattern "["`,
+		},
+		{
+			name:     "test in no group",
+			manifest: "groups:\n  - name: a\n    tests: [\"small/*\"]\n",
+			wantErr:  `test "big/c.txt" is not in any genesis group`,
+		},
+		{
+			name:     "test in two groups",
+			manifest: "groups:\n  - name: a\n    tests: [\"*/*\"]\n  - name: b\n    tests: [\"big/*\"]\n",
+			wantErr:  `test "big/c.txt" matches genesis groups "a" and "b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+
+			writeFiles(t, base, map[string]string{
+				"tests/test/small/a.txt": "payload",
+				"tests/test/big/c.txt":   "payload",
+				"genesis/groups.yaml":    tt.manifest,
+			})
+
+			_, _, err := prepareLocalSource(t, base)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// testNames returns the names of tests.
+func testNames(tests []*TestWithSteps) []string {
+	names := make([]string, 0, len(tests))
+	for _, test := range tests {
+		names = append(names, test.Name)
+	}
+
+	return names
+}
@@ -94,7 +94,7 @@ type runConfigJSON struct {
 func GenerateIndex(resultsDir string) (*Index, error) {
 	runsDir := filepath.Join(resultsDir, "runs")
 
-	entries, err := os.ReadDir(runsDir)
+	runIDs, err := ListRunDirs(runsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return &Index{
@@ -106,15 +106,11 @@ func GenerateIndex(resultsDir string) (*Index, error) {
 		return nil, fmt.Errorf("reading runs directory: %w", err)
 	}
 
-	indexEntries := make([]*IndexEntry, 0, len(entries))
+	indexEntries := make([]*IndexEntry, 0, len(runIDs))
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		runDir := filepath.Join(runsDir, entry.Name())
-		indexEntry, err := buildIndexEntry(runDir, entry.Name())
+	for _, runID := range runIDs {
+		runDir := filepath.Join(runsDir, filepath.FromSlash(runID))
+		indexEntry, err := buildIndexEntry(runDir, runID)
 
 		if err != nil {
 			// Skip runs that can't be parsed (incomplete or corrupted).
@@ -265,25 +261,7 @@ func AggregateStepStats(result *RunResult) (*IndexStepsStats, int, int) {
 			}
 		}
 
-		// Determine test-level pass/fail.
-		testFailed := false
-
-		if test.Steps.Setup != nil && test.Steps.Setup.Aggregated != nil &&
-			test.Steps.Setup.Aggregated.Failed > 0 {
-			testFailed = true
-		}
-
-		if test.Steps.Test != nil && test.Steps.Test.Aggregated != nil &&
-			test.Steps.Test.Aggregated.Failed > 0 {
-			testFailed = true
-		}
-
-		if test.Steps.Cleanup != nil && test.Steps.Cleanup.Aggregated != nil &&
-			test.Steps.Cleanup.Aggregated.Failed > 0 {
-			testFailed = true
-		}
-
-		if testFailed {
+		if test.Failed() {
 			testsFailed++
 		} else {
 			testsPassed++
@@ -434,7 +412,7 @@ func GenerateIndexFromS3(
 		runsPrefix += "/"
 	}
 
-	prefixes, err := reader.ListPrefixes(ctx, runsPrefix)
+	prefixes, err := ListRunPrefixes(ctx, reader, runsPrefix)
 	if err != nil {
 		return nil, fmt.Errorf("listing run prefixes: %w", err)
 	}
@@ -442,8 +420,7 @@ func GenerateIndexFromS3(
 	indexEntries := make([]*IndexEntry, 0, len(prefixes))
 
 	for _, prefix := range prefixes {
-		// Extract run ID from prefix (e.g. "demo/results/runs/abc123/" → "abc123").
-		runID := path.Base(strings.TrimRight(prefix, "/"))
+		runID := runIDFromPrefix(runsPrefix, prefix)
 
 		configData, err := reader.GetObject(ctx, prefix+"config.json")
 		if err != nil {
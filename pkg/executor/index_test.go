@@ -1,9 +1,17 @@
 package executor
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -121,3 +129,108 @@ func TestBuildIndexEntryFromData(t *testing.T) {
 		assert.Equal(t, 2, entry.Tests.TestsFailed)
 	})
 }
+
+func TestGenerateIndex_DatePartitionedLayout(t *testing.T) {
+	resultsDir := t.TempDir()
+
+	writeRun := func(runPath string, timestamp int64) {
+		dir := filepath.Join(resultsDir, "runs", filepath.FromSlash(runPath))
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+
+		configJSON := fmt.Sprintf(`{"timestamp": %d, "instance": {"id": "geth", "client": "geth"}}`, timestamp)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(configJSON), 0o600))
+	}
+
+	writeRun("1718000000_a1b2c3d4_geth", 1718000000)
+	writeRun("2024/06/10/1718000100_b1b2c3d4_geth", 1718000100)
+	writeRun("2024/06/11/1718090000_c1b2c3d4_geth", 1718090000)
+	// Directories that are neither runs nor partitions are ignored.
+	require.NoError(t, os.MkdirAll(filepath.Join(resultsDir, "runs", "2024", "misc", "x"), 0o755))
+
+	ids, err := ListRunDirs(filepath.Join(resultsDir, "runs"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"1718000000_a1b2c3d4_geth",
+		"2024/06/10/1718000100_b1b2c3d4_geth",
+		"2024/06/11/1718090000_c1b2c3d4_geth",
+	}, ids)
+
+	index, err := GenerateIndex(resultsDir)
+	require.NoError(t, err)
+
+	runIDs := make([]string, 0, len(index.Entries))
+	for _, entry := range index.Entries {
+		runIDs = append(runIDs, entry.RunID)
+	}
+
+	assert.Equal(t, []string{
+		"2024/06/11/1718090000_c1b2c3d4_geth",
+		"2024/06/10/1718000100_b1b2c3d4_geth",
+		"1718000000_a1b2c3d4_geth",
+	}, runIDs)
+}
+
+// prefixReader is an in-memory IndexObjectReader over a set of object keys.
+type prefixReader struct {
+	objects map[string][]byte
+}
+
+func (r *prefixReader) ListPrefixes(_ context.Context, prefix string) ([]string, error) {
+	seen := make(map[string]bool)
+
+	for key := range r.objects {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+
+		if dir, _, found := strings.Cut(rest, "/"); found {
+			seen[prefix+dir+"/"] = true
+		}
+	}
+
+	prefixes := make([]string, 0, len(seen))
+	for p := range seen {
+		prefixes = append(prefixes, p)
+	}
+
+	sort.Strings(prefixes)
+
+	return prefixes, nil
+}
+
+func (r *prefixReader) GetObject(_ context.Context, key string) ([]byte, error) {
+	return r.objects[key], nil
+}
+
+func TestGenerateIndexFromS3_DatePartitionedLayout(t *testing.T) {
+	configJSON := []byte(`{"timestamp": 1718000000, "instance": {"id": "geth", "client": "geth"}}`)
+	reader := &prefixReader{objects: map[string][]byte{
+		"results/runs/flat_run/config.json":                   configJSON,
+		"results/runs/2024/06/10/partitioned_run/config.json": configJSON,
+		"results/runs/2024/06/10/partitioned_run/result.json": []byte(`{"tests": {}}`),
+		"results/runs/2024/07/01/other_run/config.json":       configJSON,
+	}}
+
+	prefixes, err := ListRunPrefixes(context.Background(), reader, "results/runs/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"results/runs/2024/06/10/partitioned_run/",
+		"results/runs/2024/07/01/other_run/",
+		"results/runs/flat_run/",
+	}, prefixes)
+
+	index, err := GenerateIndexFromS3(context.Background(), logrus.New(), reader, "results/runs")
+	require.NoError(t, err)
+
+	runIDs := make([]string, 0, len(index.Entries))
+	for _, entry := range index.Entries {
+		runIDs = append(runIDs, entry.RunID)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"2024/06/10/partitioned_run",
+		"2024/07/01/other_run",
+		"flat_run",
+	}, runIDs)
+}
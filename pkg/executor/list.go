@@ -0,0 +1,233 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestListing is the set of tests a source and filter resolve to.
+type TestListing struct {
+	PreRunSteps    []string     `json:"pre_run_steps"`
+	PreRunRPCCalls int          `json:"pre_run_rpc_calls"`
+	Tests          []ListedTest `json:"tests"`
+}
+
+// ListedTest describes a discovered test by its step files.
+type ListedTest struct {
+	Name        string `json:"name"`
+	Setup       string `json:"setup,omitempty"`
+	Test        string `json:"test,omitempty"`
+	Cleanup     string `json:"cleanup,omitempty"`
+	GenesisHash string `json:"genesis_hash,omitempty"`
+	RPCCalls    int    `json:"rpc_calls"` // Non-blank lines across all steps
+}
+
+// ListTests prepares the configured source and returns the pre-run steps
+// and the tests matching cfg.Filter, in execution order, without running
+// anything. Only the Source, Filter, AllowEmptyGlobs, CacheDir, GitHubToken,
+// DownloadRetry and Offline fields of cfg are used.
+func ListTests(ctx context.Context, log logrus.FieldLogger, cfg *Config) (*TestListing, error) {
+	source := NewSource(
+		log, cfg.Source, cfg.CacheDir, cfg.Filter, cfg.GitHubToken, cfg.DownloadRetry,
+		cfg.AllowEmptyGlobs, cfg.Offline,
+	)
+	if source == nil {
+		return nil, fmt.Errorf("no test source configured")
+	}
+
+	defer func() {
+		if err := source.Cleanup(); err != nil {
+			log.WithError(err).Warn("Failed to cleanup source")
+		}
+	}()
+
+	prepared, err := source.Prepare(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("preparing source: %w", err)
+	}
+
+	listing := &TestListing{
+		PreRunSteps: make([]string, 0, len(prepared.PreRunSteps)),
+		Tests:       make([]ListedTest, 0, len(prepared.Tests)),
+	}
+
+	for _, step := range prepared.PreRunSteps {
+		calls, err := countStepLines(step)
+		if err != nil {
+			return nil, err
+		}
+
+		listing.PreRunSteps = append(listing.PreRunSteps, step.Name)
+		listing.PreRunRPCCalls += calls
+	}
+
+	for _, test := range prepared.Tests {
+		// Sources filter step files by path, the executor also matches
+		// test names, so apply the same check here.
+		if !test.matchesFilter(cfg.Filter) {
+			continue
+		}
+
+		listed := ListedTest{
+			Name:        test.Name,
+			Setup:       stepName(test.Setup),
+			Test:        stepName(test.Test),
+			Cleanup:     stepName(test.Cleanup),
+			GenesisHash: test.GenesisHash,
+		}
+
+		for _, step := range []*StepFile{test.Setup, test.Test, test.Cleanup} {
+			calls, err := countStepLines(step)
+			if err != nil {
+				return nil, err
+			}
+
+			listed.RPCCalls += calls
+		}
+
+		listing.Tests = append(listing.Tests, listed)
+	}
+
+	return listing, nil
+}
+
+// stepName returns the name of step, or an empty string if it is nil.
+func stepName(step *StepFile) string {
+	if step == nil {
+		return ""
+	}
+
+	return step.Name
+}
+
+// countStepLines returns the number of non-blank lines of step, which is the
+// number of RPC calls the executor sends for it. A nil step has none.
+func countStepLines(step *StepFile) (int, error) {
+	if step == nil {
+		return 0, nil
+	}
+
+	if step.Provider != nil {
+		count := 0
+
+		for _, line := range step.Provider.Lines() {
+			if strings.TrimSpace(line) != "" {
+				count++
+			}
+		}
+
+		return count, nil
+	}
+
+	file, err := os.Open(step.Path)
+	if err != nil {
+		return 0, fmt.Errorf("opening step file: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	count := 0
+
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			count++
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return count, nil
+			}
+
+			return 0, fmt.Errorf("reading step file %s: %w", step.Name, err)
+		}
+	}
+}
+
+// SuiteEstimate summarizes the size and expected duration of a listing.
+type SuiteEstimate struct {
+	Tests             int           `json:"tests"`
+	PreRunSteps       int           `json:"pre_run_steps"`
+	RPCCalls          int           `json:"rpc_calls"`
+	EstimatedDuration time.Duration `json:"estimated_duration_ns"`
+	BaselineTests     int           `json:"baseline_tests"` // Tests estimated from the baseline run
+}
+
+// EstimateSuite counts the tests and RPC calls of listing and estimates how
+// long running them takes. Tests and pre-run steps found in baseline (which
+// may be nil) are estimated by the time their steps took in that run, other
+// tests by perTest. Pre-run steps missing from the baseline are not counted
+// towards the duration. The estimate covers RPC time only, not client
+// startup or cache drops.
+func EstimateSuite(listing *TestListing, baseline *RunResult, perTest time.Duration) *SuiteEstimate {
+	estimate := &SuiteEstimate{
+		Tests:       len(listing.Tests),
+		PreRunSteps: len(listing.PreRunSteps),
+		RPCCalls:    listing.PreRunRPCCalls,
+	}
+
+	for _, test := range listing.Tests {
+		estimate.RPCCalls += test.RPCCalls
+
+		if baseline != nil {
+			if entry, ok := baseline.Tests[filepath.Clean(test.Name)]; ok && entry.Steps != nil {
+				estimate.EstimatedDuration += stepDuration(entry.Steps.Setup) +
+					stepDuration(entry.Steps.Test) + stepDuration(entry.Steps.Cleanup)
+				estimate.BaselineTests++
+
+				continue
+			}
+		}
+
+		estimate.EstimatedDuration += perTest
+	}
+
+	if baseline != nil {
+		for _, name := range listing.PreRunSteps {
+			estimate.EstimatedDuration += stepDuration(baseline.PreRunSteps[filepath.Clean(name)])
+		}
+	}
+
+	return estimate
+}
+
+// stepDuration returns the total RPC time of a step result, or 0 if nil.
+func stepDuration(step *StepResult) time.Duration {
+	if step == nil || step.Aggregated == nil {
+		return 0
+	}
+
+	return time.Duration(step.Aggregated.TotalTime)
+}
+
+// ReadRunResult reads the result.json of a run directory. path may also be
+// the result.json file itself.
+func ReadRunResult(path string) (*RunResult, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "result.json")
+	}
+
+	//nolint:gosec // result.json is a trusted local file written by the tool.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading run result: %w", err)
+	}
+
+	var result RunResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,198 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTests(t *testing.T) {
+	base := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "prerun"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "prerun", "funding.txt"), []byte("line"), 0644))
+
+	for _, name := range []string{"bn128_add", "bn128_mul", "ecrecover"} {
+		for _, step := range []string{"setup", "testing"} {
+			dir := filepath.Join(base, step, name)
+			require.NoError(t, os.MkdirAll(dir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "test.txt"), []byte("call1\ncall2\n"), 0644))
+		}
+	}
+
+	source := &config.SourceConfig{Local: &config.LocalSourceV2{
+		BaseDir:     base,
+		PreRunSteps: []string{"prerun/funding.txt"},
+		Steps: &config.StepsConfig{
+			Setup: []string{"setup/*/*"},
+			Test:  []string{"testing/*/*"},
+		},
+	}}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	names := func(listing *TestListing) []string {
+		out := make([]string, 0, len(listing.Tests))
+		for _, test := range listing.Tests {
+			out = append(out, test.Name)
+		}
+
+		return out
+	}
+
+	t.Run("all tests", func(t *testing.T) {
+		listing, err := ListTests(context.Background(), log, &Config{Source: source})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"prerun/funding.txt"}, listing.PreRunSteps)
+		assert.Equal(t, []string{"bn128_add/test.txt", "bn128_mul/test.txt", "ecrecover/test.txt"}, names(listing))
+		assert.Equal(t, "setup/bn128_add/test.txt", listing.Tests[0].Setup)
+		assert.Equal(t, "testing/bn128_add/test.txt", listing.Tests[0].Test)
+		assert.Empty(t, listing.Tests[0].Cleanup)
+		assert.Equal(t, 4, listing.Tests[0].RPCCalls)
+		assert.Equal(t, 1, listing.PreRunRPCCalls)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		listing, err := ListTests(context.Background(), log, &Config{Source: source, Filter: "bn128"})
+		require.NoError(t, err)
+
+		// Pre-run steps are never filtered.
+		assert.Equal(t, []string{"prerun/funding.txt"}, listing.PreRunSteps)
+		assert.Equal(t, []string{"bn128_add/test.txt", "bn128_mul/test.txt"}, names(listing))
+	})
+
+	t.Run("filter matching nothing", func(t *testing.T) {
+		listing, err := ListTests(context.Background(), log, &Config{Source: source, Filter: "nope"})
+		require.NoError(t, err)
+		assert.Empty(t, listing.Tests)
+	})
+
+	t.Run("no source", func(t *testing.T) {
+		_, err := ListTests(context.Background(), log, &Config{Source: &config.SourceConfig{}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no test source configured")
+	})
+
+	t.Run("missing base dir", func(t *testing.T) {
+		_, err := ListTests(context.Background(), log, &Config{Source: &config.SourceConfig{
+			Local: &config.LocalSourceV2{BaseDir: filepath.Join(base, "missing")},
+		}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "preparing source")
+	})
+}
+
+func TestCountStepLines(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "empty", content: "", want: 0},
+		{name: "trailing newline", content: "a\nb\nc\n", want: 3},
+		{name: "no trailing newline", content: "a\nb", want: 2},
+		{name: "blank lines", content: "a\n\n  \nb\n\n", want: 2},
+		{name: "crlf", content: "a\r\nb\r\n", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".txt")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			count, err := countStepLines(&StepFile{Path: path, Name: tt.name})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+		})
+	}
+
+	t.Run("provider", func(t *testing.T) {
+		count, err := countStepLines(&StepFile{Provider: &linesProvider{lines: []string{"a", " ", "b"}}})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("nil step", func(t *testing.T) {
+		count, err := countStepLines(nil)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := countStepLines(&StepFile{Path: filepath.Join(dir, "missing.txt")})
+		require.Error(t, err)
+	})
+}
+
+func TestEstimateSuite(t *testing.T) {
+	listing := &TestListing{
+		PreRunSteps:    []string{"prerun/funding.txt"},
+		PreRunRPCCalls: 10,
+		Tests: []ListedTest{
+			{Name: "a/test.txt", RPCCalls: 5},
+			{Name: "b/test.txt", RPCCalls: 7},
+			{Name: "c/test.txt", RPCCalls: 3},
+		},
+	}
+
+	step := func(d time.Duration) *StepResult {
+		return &StepResult{Aggregated: &AggregatedStats{TotalTime: int64(d)}}
+	}
+
+	t.Run("fixed estimate", func(t *testing.T) {
+		estimate := EstimateSuite(listing, nil, 10*time.Second)
+
+		assert.Equal(t, &SuiteEstimate{
+			Tests:             3,
+			PreRunSteps:       1,
+			RPCCalls:          25,
+			EstimatedDuration: 30 * time.Second,
+		}, estimate)
+	})
+
+	t.Run("baseline", func(t *testing.T) {
+		baseline := &RunResult{
+			PreRunSteps: map[string]*StepResult{"prerun/funding.txt": step(4 * time.Second)},
+			Tests: map[string]*TestEntry{
+				"a/test.txt": {Steps: &StepsResult{Setup: step(time.Second), Test: step(2 * time.Second)}},
+				"b/test.txt": {Steps: &StepsResult{Test: step(3 * time.Second), Cleanup: step(time.Second)}},
+				"other":      {Steps: &StepsResult{Test: step(time.Hour)}},
+			},
+		}
+
+		estimate := EstimateSuite(listing, baseline, 10*time.Second)
+
+		assert.Equal(t, 2, estimate.BaselineTests)
+		// 4s pre-run + 3s (a) + 4s (b) + 10s fixed (c).
+		assert.Equal(t, 21*time.Second, estimate.EstimatedDuration)
+		assert.Equal(t, 25, estimate.RPCCalls)
+	})
+}
+
+func TestReadRunResult(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"tests": {"a/test.txt": {"dir": "", "steps": {"test": {"aggregated": {"time_total": 1500}}}}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "result.json"), []byte(data), 0644))
+
+	for _, path := range []string{dir, filepath.Join(dir, "result.json")} {
+		result, err := ReadRunResult(path)
+		require.NoError(t, err)
+		require.Contains(t, result.Tests, "a/test.txt")
+		assert.Equal(t, int64(1500), result.Tests["a/test.txt"].Steps.Test.Aggregated.TotalTime)
+	}
+
+	_, err := ReadRunResult(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
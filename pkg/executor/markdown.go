@@ -43,6 +43,8 @@ type markdownSystemInfo struct {
 	CPUCores           int     `json:"cpu_cores"`
 	CPUMhz             float64 `json:"cpu_mhz"`
 	CPUCacheKB         int     `json:"cpu_cache_kb"`
+	CPUGovernor        string  `json:"cpu_freq_governor,omitempty"`
+	CPUTurboBoost      *bool   `json:"cpu_turboboost,omitempty"`
 	MemoryTotalGB      float64 `json:"memory_total_gb"`
 }
 
@@ -238,6 +240,14 @@ func writeSystem(sb *strings.Builder, sys *markdownSystemInfo) {
 		fmt.Fprintf(sb, "| CPU MHz | %.1f |\n", sys.CPUMhz)
 	}
 
+	if sys.CPUGovernor != "" {
+		fmt.Fprintf(sb, "| CPU Governor | %s |\n", sys.CPUGovernor)
+	}
+
+	if sys.CPUTurboBoost != nil {
+		fmt.Fprintf(sb, "| Turbo Boost | %t |\n", *sys.CPUTurboBoost)
+	}
+
 	if sys.MemoryTotalGB > 0 {
 		fmt.Fprintf(sb, "| Memory | %.1f GB |\n", sys.MemoryTotalGB)
 	}
@@ -218,6 +218,8 @@ func TestGenerateRunMarkdown(t *testing.T) {
 		assert.Contains(t, md, "## System")
 		assert.Contains(t, md, "| Hostname | test-host |")
 		assert.Contains(t, md, "| CPU | AMD Ryzen 9 |")
+		assert.Contains(t, md, "| CPU Governor | powersave |")
+		assert.Contains(t, md, "| Turbo Boost | true |")
 		assert.Contains(t, md, "## Resource Limits")
 		assert.Contains(t, md, "| CPU Set | 0-3 |")
 		assert.Contains(t, md, "## Metadata")
@@ -317,6 +319,8 @@ func writeFixtureConfig(t *testing.T, dir string) {
 			CPUModel:      "AMD Ryzen 9",
 			CPUCores:      16,
 			CPUMhz:        5756.0,
+			CPUGovernor:   "powersave",
+			CPUTurboBoost: &turbo,
 			MemoryTotalGB: 64.0,
 		},
 		Instance: &markdownInstance{
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// progressReporter periodically logs how far a test execution has got. A
+// progress line is logged every `every` finished tests or once `interval` has
+// passed since the previous line, whichever comes first. Zero disables either
+// cadence.
+type progressReporter struct {
+	log      logrus.FieldLogger
+	level    logrus.Level
+	now      func() time.Time
+	total    int
+	every    int
+	interval time.Duration
+
+	start    time.Time
+	lastLog  time.Time
+	finished int
+	failed   int
+}
+
+// newProgressReporter returns a reporter for an execution of total tests.
+func (e *executor) newProgressReporter(total int) *progressReporter {
+	now := e.now()
+
+	return &progressReporter{
+		log:      e.log,
+		level:    e.quietLogLevel(),
+		now:      e.now,
+		total:    total,
+		every:    e.cfg.ProgressEveryTests,
+		interval: e.cfg.ProgressInterval,
+		start:    now,
+		lastLog:  now,
+	}
+}
+
+// testFinished records a finished test, passed, failed or skipped, and logs
+// a progress line when one is due.
+func (p *progressReporter) testFinished(failed bool) {
+	p.finished++
+
+	if failed {
+		p.failed++
+	}
+
+	now := p.now()
+
+	dueByCount := p.every > 0 && p.finished%p.every == 0
+	dueByTime := p.interval > 0 && now.Sub(p.lastLog) >= p.interval
+
+	if !dueByCount && !dueByTime {
+		return
+	}
+
+	p.lastLog = now
+
+	elapsed := now.Sub(p.start)
+	eta := time.Duration(0)
+
+	if remaining := p.total - p.finished; remaining > 0 {
+		eta = elapsed / time.Duration(p.finished) * time.Duration(remaining)
+	}
+
+	p.log.WithFields(logrus.Fields{
+		"tests":   fmt.Sprintf("%d/%d", p.finished, p.total),
+		"failed":  p.failed,
+		"elapsed": elapsed.Round(time.Second),
+		"eta":     eta.Round(time.Second),
+	}).Log(p.level, "Test progress")
+}
@@ -0,0 +1,171 @@
+package executor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stepClock returns a clock that advances by step on every call.
+func stepClock(step time.Duration) func() time.Time {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return func() time.Time {
+		now = now.Add(step)
+
+		return now
+	}
+}
+
+// progressEntries returns the logged progress lines.
+func progressEntries(hook *logtest.Hook) []*logrus.Entry {
+	var entries []*logrus.Entry
+
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Test progress" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func TestProgressReporter_Cadence(t *testing.T) {
+	tests := []struct {
+		name     string
+		every    int
+		interval time.Duration
+		want     []string
+	}{
+		{
+			name:  "every N tests",
+			every: 2,
+			want:  []string{"2/5", "4/5"},
+		},
+		{
+			// The clock advances 10s per finished test.
+			name:     "interval",
+			interval: 25 * time.Second,
+			want:     []string{"3/5"},
+		},
+		{
+			name:     "whichever comes first",
+			every:    4,
+			interval: 25 * time.Second,
+			want:     []string{"3/5", "4/5"},
+		},
+		{
+			name: "disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, hook := logtest.NewNullLogger()
+
+			e := NewExecutor(log, &Config{
+				ProgressEveryTests: tt.every,
+				ProgressInterval:   tt.interval,
+			}).(*executor)
+			e.now = stepClock(10 * time.Second)
+
+			p := e.newProgressReporter(5)
+			for range 5 {
+				p.testFinished(false)
+			}
+
+			var got []string
+			for _, entry := range progressEntries(hook) {
+				got = append(got, entry.Data["tests"].(string))
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProgressReporter_FailedAndETA(t *testing.T) {
+	log, hook := logtest.NewNullLogger()
+
+	e := NewExecutor(log, &Config{ProgressEveryTests: 2}).(*executor)
+	e.now = stepClock(10 * time.Second)
+
+	p := e.newProgressReporter(4)
+	p.testFinished(true)
+	p.testFinished(false)
+
+	entries := progressEntries(hook)
+	require.Len(t, entries, 1)
+
+	assert.Equal(t, logrus.InfoLevel, entries[0].Level)
+	assert.Equal(t, 1, entries[0].Data["failed"])
+	assert.Equal(t, 20*time.Second, entries[0].Data["elapsed"])
+	assert.Equal(t, 20*time.Second, entries[0].Data["eta"])
+}
+
+func TestExecuteTests_Progress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	stepFile := func(name string) *StepFile {
+		return &StepFile{Name: name, Provider: &linesProvider{lines: []string{
+			`{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[],"id":1}`,
+		}}}
+	}
+
+	tests := []*TestWithSteps{
+		{Name: "test_a.txt", Test: stepFile("test_a.txt")},
+		{Name: "test_b.txt", Test: stepFile("test_b.txt")},
+		{Name: "skipped.txt"},
+		{Name: "test_c.txt", Test: stepFile("test_c.txt")},
+	}
+
+	run := func(t *testing.T, quiet bool) []*logrus.Entry {
+		t.Helper()
+
+		log, hook := logtest.NewNullLogger()
+		log.SetLevel(logrus.DebugLevel)
+
+		e := NewExecutor(log, &Config{ProgressEveryTests: 2, QuietRPCLogs: quiet}).(*executor)
+		e.prepared = &PreparedSource{}
+
+		_, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+			EngineEndpoint: srv.URL,
+			JWT:            config.DefaultJWT,
+			ResultsDir:     t.TempDir(),
+			Tests:          tests,
+		})
+		require.NoError(t, err)
+
+		return progressEntries(hook)
+	}
+
+	t.Run("default", func(t *testing.T) {
+		entries := run(t, false)
+		require.Len(t, entries, 2)
+
+		// Skipped tests count towards the progress.
+		for i, want := range []string{"2/4", "4/4"} {
+			assert.Equal(t, want, entries[i].Data["tests"])
+			assert.Equal(t, logrus.InfoLevel, entries[i].Level)
+		}
+	})
+
+	t.Run("quiet", func(t *testing.T) {
+		entries := run(t, true)
+		require.Len(t, entries, 2)
+
+		for _, entry := range entries {
+			assert.Equal(t, logrus.DebugLevel, entry.Level)
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package executor
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"maps"
@@ -9,6 +11,8 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
 )
@@ -139,12 +143,24 @@ type ResourceTotals struct {
 	CPUUsec        uint64 `json:"cpu_usec"`
 	MemoryDelta    int64  `json:"memory_delta_bytes"`
 	MemoryBytes    uint64 `json:"memory_bytes,omitempty"`
+	MemoryPeak     uint64 `json:"memory_peak_bytes,omitempty"` // Highest memory reading after any call
 	DiskReadBytes  uint64 `json:"disk_read_bytes"`
 	DiskWriteBytes uint64 `json:"disk_write_bytes"`
 	DiskReadIOPS   uint64 `json:"disk_read_iops"`
 	DiskWriteIOPS  uint64 `json:"disk_write_iops"`
 }
 
+// RunResourceUsage is the resource usage of a whole run, summed over the
+// resource totals of all of its steps.
+type RunResourceUsage struct {
+	CPUSeconds      float64 `json:"cpu_seconds"`
+	PeakMemoryBytes uint64  `json:"peak_memory_bytes"`
+	DiskReadBytes   uint64  `json:"disk_read_bytes"`
+	DiskWriteBytes  uint64  `json:"disk_write_bytes"`
+	DiskReadIOPS    uint64  `json:"disk_read_iops"`
+	DiskWriteIOPS   uint64  `json:"disk_write_iops"`
+}
+
 // AggregatedStats contains the full aggregated output.
 type AggregatedStats struct {
 	TotalTime        int64              `json:"time_total"`
@@ -176,10 +192,42 @@ type TestEntry struct {
 	Steps        *StepsResult `json:"steps,omitempty"`
 }
 
+// Failed reports whether any step of the test had a failed call.
+func (e *TestEntry) Failed() bool {
+	if e.Steps == nil {
+		return false
+	}
+
+	for _, step := range []*StepResult{e.Steps.Setup, e.Steps.Test, e.Steps.Cleanup} {
+		if step != nil && step.Aggregated != nil && step.Aggregated.Failed > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FailedTests returns the sorted names of the failed tests of result.
+func FailedTests(result *RunResult) []string {
+	failed := make([]string, 0)
+
+	for name, entry := range result.Tests {
+		if entry.Failed() {
+			failed = append(failed, name)
+		}
+	}
+
+	slices.Sort(failed)
+
+	return failed
+}
+
 // RunResult contains the aggregated results for all tests in a run.
 type RunResult struct {
-	PreRunSteps map[string]*StepResult `json:"pre_run_steps,omitempty"`
-	Tests       map[string]*TestEntry  `json:"tests"`
+	PreRunSteps   map[string]*StepResult `json:"pre_run_steps,omitempty"`
+	Tests         map[string]*TestEntry  `json:"tests"`
+	Skipped       int                    `json:"skipped,omitempty"`        // Tests skipped instead of passing or failing
+	ResourceUsage *RunResourceUsage      `json:"resource_usage,omitempty"` // Nil without resource metrics
 }
 
 // TestResult contains results for a single test file execution.
@@ -187,10 +235,11 @@ type TestResult struct {
 	TestFile             string
 	Responses            []string
 	Times                []int64
-	Statuses             []int // 0=success, 1=fail
+	Statuses             []int // One of the CallStatus* constants
 	MGasPerSec           map[int]float64
 	GasUsed              map[int]uint64
 	Resources            map[int]*ResourceDelta
+	HTTPStatus           map[int]int // HTTP status code of each call that got a response
 	MethodTimes          map[string][]int64
 	MethodMGasPerSec     map[string][]float64
 	MethodCPUUsec        map[string][]int64
@@ -209,6 +258,7 @@ type ResultDetails struct {
 	MGasPerSec map[int]float64        `json:"mgas_s"`
 	GasUsed    map[int]uint64         `json:"gas_used"`
 	Resources  map[int]*ResourceDelta `json:"resources,omitempty"`
+	HTTPStatus map[int]int            `json:"http_status,omitempty"`
 	// OriginalTestName stores the original test name when using hashed filenames.
 	OriginalTestName string `json:"original_test_name,omitempty"`
 	// FilenameHash stores the truncated+hash filename when the original was too long.
@@ -225,6 +275,7 @@ func NewTestResult(testFile string) *TestResult {
 		MGasPerSec:           make(map[int]float64),
 		GasUsed:              make(map[int]uint64),
 		Resources:            make(map[int]*ResourceDelta),
+		HTTPStatus:           make(map[int]int),
 		MethodTimes:          make(map[string][]int64),
 		MethodMGasPerSec:     make(map[string][]float64),
 		MethodCPUUsec:        make(map[string][]int64),
@@ -235,6 +286,25 @@ func NewTestResult(testFile string) *TestResult {
 	}
 }
 
+// Per-call statuses recorded in TestResult.Statuses and result details.
+const (
+	// CallStatusSuccess is a call that succeeded.
+	CallStatusSuccess = 0
+	// CallStatusFailed is a call that failed, e.g. a transport error, a
+	// JSON-RPC error or a response failing validation.
+	CallStatusFailed = 1
+	// CallStatusUnexpected is a call whose response didn't match the
+	// result the test expects, e.g. VALID for a block expected INVALID.
+	CallStatusUnexpected = 2
+	// CallStatusMalformed is a call whose response body isn't a JSON-RPC
+	// response, e.g. an HTML error page or an empty body.
+	CallStatusMalformed = 3
+)
+
+// maxMalformedResponseBytes bounds the raw body kept for a malformed
+// response.
+const maxMalformedResponseBytes = 4096
+
 // AddResult adds a single RPC call result.
 func (r *TestResult) AddResult(
 	method, request, response string,
@@ -242,6 +312,69 @@ func (r *TestResult) AddResult(
 	succeeded bool,
 	resources *ResourceDelta,
 ) {
+	status := CallStatusSuccess
+	if !succeeded {
+		status = CallStatusFailed
+	}
+
+	r.addResult(method, request, response, elapsed, status, resources)
+}
+
+// AddUnexpectedResult adds a failed RPC call result whose response didn't
+// match the test's expected result.
+func (r *TestResult) AddUnexpectedResult(
+	method, request, response string,
+	elapsed int64,
+	resources *ResourceDelta,
+) {
+	r.addResult(method, request, response, elapsed, CallStatusUnexpected, resources)
+}
+
+// AddMalformedResult adds a failed RPC call result whose response body isn't
+// a JSON-RPC response. The body, truncated to maxMalformedResponseBytes, is
+// stored as a JSON string so it stays on a single line of the response file.
+func (r *TestResult) AddMalformedResult(
+	method, request, body string,
+	elapsed int64,
+	resources *ResourceDelta,
+) {
+	r.addResult(method, request, quoteMalformedBody(body), elapsed, CallStatusMalformed, resources)
+}
+
+// quoteMalformedBody truncates a malformed response body and quotes it as a
+// JSON string.
+func quoteMalformedBody(body string) string {
+	if len(body) > maxMalformedResponseBytes {
+		body = strings.ToValidUTF8(body[:maxMalformedResponseBytes], "") + "...(truncated)"
+	}
+
+	quoted, err := json.Marshal(body)
+	if err != nil {
+		return `""`
+	}
+
+	return string(quoted)
+}
+
+// SetHTTPStatus records the HTTP status code of the most recently added
+// call. Calls without a response, e.g. on a transport error, have none.
+func (r *TestResult) SetHTTPStatus(code int) {
+	if code == 0 || len(r.Times) == 0 {
+		return
+	}
+
+	r.HTTPStatus[len(r.Times)-1] = code
+}
+
+// addResult adds a single RPC call result with the given call status.
+func (r *TestResult) addResult(
+	method, request, response string,
+	elapsed int64,
+	status int,
+	resources *ResourceDelta,
+) {
+	succeeded := status == CallStatusSuccess
+
 	// Get position before appending.
 	pos := len(r.Times)
 
@@ -249,11 +382,6 @@ func (r *TestResult) AddResult(
 	r.Times = append(r.Times, elapsed)
 	r.MethodTimes[method] = append(r.MethodTimes[method], elapsed)
 
-	status := 0
-	if !succeeded {
-		status = 1
-	}
-
 	r.Statuses = append(r.Statuses, status)
 
 	// Store resource delta if available.
@@ -379,6 +507,7 @@ func (r *TestResult) CalculateStats() *AggregatedStats {
 				resourceTotals.DiskWriteBytes += res.DiskWriteBytes
 				resourceTotals.DiskReadIOPS += res.DiskReadOps
 				resourceTotals.DiskWriteIOPS += res.DiskWriteOps
+				resourceTotals.MemoryPeak = max(resourceTotals.MemoryPeak, res.MemoryAbsBytes)
 
 				// Use absolute memory from the last RPC call.
 				if idx == maxIdx {
@@ -526,18 +655,72 @@ func percentileFloat(sorted []float64, p int) float64 {
 	return sorted[idx]
 }
 
+// maxTestDirComponent is the longest path component of a test directory,
+// leaving room below the 255 byte name limit of common filesystems.
+const maxTestDirComponent = 200
+
+// testDirName returns the path of a test's directory relative to the results
+// or suite directory. Slashes in the test name nest directories, as for test
+// names derived from step file paths. Components that would escape the parent
+// directory, contain NUL bytes or exceed maxTestDirComponent are rewritten;
+// other characters, like the colons of pytest node IDs, are valid on the
+// filesystems benchmarks run on and kept as is.
+func testDirName(testName string) string {
+	components := strings.Split(filepath.ToSlash(testName), "/")
+
+	for i, component := range components {
+		component = strings.ReplaceAll(component, "\x00", "_")
+
+		if component == ".." {
+			component = "__"
+		}
+
+		if len(component) > maxTestDirComponent {
+			sum := sha256.Sum256([]byte(component))
+			suffix := "-" + hex.EncodeToString(sum[:8])
+
+			// Cut at a rune boundary to keep the name valid UTF-8.
+			n := maxTestDirComponent - len(suffix)
+			for n > 0 && !utf8.RuneStart(component[n]) {
+				n--
+			}
+
+			component = component[:n] + suffix
+		}
+
+		components[i] = component
+	}
+
+	return filepath.Join(components...)
+}
+
 // WriteStepResults writes the three output files for a test step.
-// Files are written to: resultDir/testName/{stepType}.{response,result-details.json,result-aggregated.json}
+// Files are written to: resultDir/{testDirName(testName)}/{stepType}.{response,result-details.json,result-aggregated.json}
 func WriteStepResults(
 	resultDir, testName string,
 	stepType StepType,
 	result *TestResult,
 	owner *fsutil.OwnerConfig,
 ) error {
+	_, err := writeStepResults(resultDir, testName, stepType, result, owner)
+
+	return err
+}
+
+// writeStepResults writes the output files for a test step and returns the
+// aggregated stats it wrote.
+func writeStepResults(
+	resultDir, testName string,
+	stepType StepType,
+	result *TestResult,
+	owner *fsutil.OwnerConfig,
+) (*AggregatedStats, error) {
 	// Ensure the test directory exists.
-	testDir := filepath.Join(resultDir, testName)
+	dirName := testDirName(testName)
+
+	testDir := filepath.Join(resultDir, dirName)
 	if err := fsutil.MkdirAll(testDir, 0755, owner); err != nil {
-		return fmt.Errorf("creating test result directory: %w", err)
+		return nil, fmt.Errorf("creating test result directory: %w", err)
 	}
 
 	// Base path is the step type (e.g., "setup", "test", "cleanup").
@@ -546,7 +729,7 @@ func WriteStepResults(
 	// Write .response file.
 	responsePath := basePath + ".response"
 	if err := fsutil.WriteFile(responsePath, []byte(strings.Join(result.Responses, "\n")+"\n"), 0644, owner); err != nil {
-		return fmt.Errorf("writing response file: %w", err)
+		return nil, fmt.Errorf("writing response file: %w", err)
 	}
 
 	// Write .result-details.json file.
@@ -557,15 +740,22 @@ func WriteStepResults(
 		MGasPerSec: result.MGasPerSec,
 		GasUsed:    result.GasUsed,
 		Resources:  result.Resources,
+		HTTPStatus: result.HTTPStatus,
+	}
+
+	// Keep the original name of tests whose directory was renamed.
+	if dirName != filepath.Clean(testName) {
+		details.OriginalTestName = testName
+		details.FilenameHash = dirName
 	}
 
 	detailsJSON, err := json.MarshalIndent(details, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling result details: %w", err)
+		return nil, fmt.Errorf("marshaling result details: %w", err)
 	}
 
 	if err := fsutil.WriteFile(detailsPath, detailsJSON, 0644, owner); err != nil {
-		return fmt.Errorf("writing result details file: %w", err)
+		return nil, fmt.Errorf("writing result details file: %w", err)
 	}
 
 	// Write .result-aggregated.json file.
@@ -574,14 +764,14 @@ func WriteStepResults(
 
 	statsJSON, err := json.MarshalIndent(stats, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling stats: %w", err)
+		return nil, fmt.Errorf("marshaling stats: %w", err)
 	}
 
 	if err := fsutil.WriteFile(statsPath, statsJSON, 0644, owner); err != nil {
-		return fmt.Errorf("writing stats file: %w", err)
+		return nil, fmt.Errorf("writing stats file: %w", err)
 	}
 
-	return nil
+	return stats, nil
 }
 
 // GenerateRunResult scans a results directory and builds a RunResult from all aggregated files.
@@ -649,54 +839,215 @@ func GenerateRunResult(resultsDir string) (*RunResult, error) {
 		}
 
 		// The test name is the directory containing the step files.
-		testName := dir
-		if testName == "." {
-			testName = ""
+		addStepResult(result, dir, stepType, &stats)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking results directory: %w", err)
+	}
+
+	// Set PreRunSteps to nil if empty so omitempty works.
+	if len(result.PreRunSteps) == 0 {
+		result.PreRunSteps = nil
+	}
+
+	result.ResourceUsage = runResourceUsage(result)
+
+	return result, nil
+}
+
+// runResourceUsage sums the resource totals of all steps of result. Returns
+// nil if no step has resource metrics.
+func runResourceUsage(result *RunResult) *RunResourceUsage {
+	var (
+		usage   RunResourceUsage
+		cpuUsec uint64
+		found   bool
+	)
+
+	addStep := func(step *StepResult) {
+		if step == nil || step.Aggregated == nil || step.Aggregated.ResourceTotals == nil {
+			return
 		}
 
-		// Set the step result.
-		stepResult := &StepResult{
-			Aggregated: &stats,
+		totals := step.Aggregated.ResourceTotals
+		found = true
+		cpuUsec += totals.CPUUsec
+		usage.DiskReadBytes += totals.DiskReadBytes
+		usage.DiskWriteBytes += totals.DiskWriteBytes
+		usage.DiskReadIOPS += totals.DiskReadIOPS
+		usage.DiskWriteIOPS += totals.DiskWriteIOPS
+		// Steps aggregated before the peak was recorded only have the
+		// reading after their last call.
+		usage.PeakMemoryBytes = max(usage.PeakMemoryBytes, totals.MemoryPeak, totals.MemoryBytes)
+	}
+
+	for _, step := range result.PreRunSteps {
+		addStep(step)
+	}
+
+	for _, entry := range result.Tests {
+		if entry.Steps == nil {
+			continue
 		}
 
-		// Handle pre-run steps separately.
-		if stepType == StepTypePreRun {
-			result.PreRunSteps[testName] = stepResult
+		addStep(entry.Steps.Setup)
+		addStep(entry.Steps.Test)
+		addStep(entry.Steps.Cleanup)
+	}
 
-			return nil
+	if !found {
+		return nil
+	}
+
+	usage.CPUSeconds = float64(cpuUsec) / 1e6
+
+	return &usage
+}
+
+// addStepResult records a step's aggregated stats in result. testName is
+// cleaned so it matches the directory the step files were written to.
+func addStepResult(result *RunResult, testName string, stepType StepType, stats *AggregatedStats) {
+	testName = filepath.Clean(testName)
+	if testName == "." {
+		testName = ""
+	}
+
+	// Set the step result.
+	stepResult := &StepResult{
+		Aggregated: stats,
+	}
+
+	// Handle pre-run steps separately.
+	if stepType == StepTypePreRun {
+		result.PreRunSteps[testName] = stepResult
+
+		return
+	}
+
+	// Get or create the test entry.
+	entry, ok := result.Tests[testName]
+	if !ok {
+		entry = &TestEntry{
+			Dir:   "",
+			Steps: &StepsResult{},
 		}
 
-		// Get or create the test entry.
-		entry, ok := result.Tests[testName]
-		if !ok {
-			entry = &TestEntry{
-				Dir:   "",
-				Steps: &StepsResult{},
-			}
-			result.Tests[testName] = entry
+		if dirName := testDirName(testName); dirName != testName {
+			entry.FilenameHash = dirName
 		}
 
-		switch stepType {
-		case StepTypeSetup:
-			entry.Steps.Setup = stepResult
-		case StepTypeTest:
-			entry.Steps.Test = stepResult
-		case StepTypeCleanup:
-			entry.Steps.Cleanup = stepResult
+		result.Tests[testName] = entry
+	}
+
+	switch stepType {
+	case StepTypeSetup:
+		entry.Steps.Setup = stepResult
+	case StepTypeTest:
+		entry.Steps.Test = stepResult
+	case StepTypeCleanup:
+		entry.Steps.Cleanup = stepResult
+	}
+}
+
+// runResultAccumulator builds the run result of each results directory as
+// step results are written, so writing result.json does not require
+// re-reading every step result from disk.
+type runResultAccumulator struct {
+	mu      sync.Mutex
+	results map[string]*RunResult          // Keyed by results directory.
+	skipped map[string]map[string]struct{} // Skipped test names, keyed by results directory.
+
+	// writeMu serializes writing result.json, so concurrent calls sharing a
+	// results directory never leave an older result behind.
+	writeMu sync.Mutex
+}
+
+// skip records a test of resultsDir as skipped.
+func (a *runResultAccumulator) skip(resultsDir, testName string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.skipped == nil {
+		a.skipped = make(map[string]map[string]struct{})
+	}
+
+	if a.skipped[resultsDir] == nil {
+		a.skipped[resultsDir] = make(map[string]struct{})
+	}
+
+	a.skipped[resultsDir][testName] = struct{}{}
+}
+
+// add records the aggregated stats of a step written to resultsDir.
+func (a *runResultAccumulator) add(resultsDir, testName string, stepType StepType, stats *AggregatedStats) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.results == nil {
+		a.results = make(map[string]*RunResult)
+	}
+
+	result, ok := a.results[resultsDir]
+	if !ok {
+		result = &RunResult{
+			PreRunSteps: make(map[string]*StepResult),
+			Tests:       make(map[string]*TestEntry),
 		}
+		a.results[resultsDir] = result
+	}
 
-		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("walking results directory: %w", err)
+	addStepResult(result, testName, stepType, stats)
+}
+
+// forget drops everything recorded for resultsDir, so a long multi-instance
+// run doesn't keep the results of finished runs in memory.
+func (a *runResultAccumulator) forget(resultsDir string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.results, resultsDir)
+	delete(a.skipped, resultsDir)
+}
+
+// runResult returns the run result of all steps written to resultsDir so
+// far, in the same shape GenerateRunResult builds from disk.
+func (a *runResultAccumulator) runResult(resultsDir string) *RunResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	out := &RunResult{
+		Tests:   make(map[string]*TestEntry),
+		Skipped: len(a.skipped[resultsDir]),
 	}
 
-	// Set PreRunSteps to nil if empty so omitempty works.
-	if len(result.PreRunSteps) == 0 {
-		result.PreRunSteps = nil
+	result, ok := a.results[resultsDir]
+	if !ok {
+		return out
 	}
 
-	return result, nil
+	maps.Copy(out.Tests, result.Tests)
+
+	// Leave PreRunSteps nil if empty so omitempty works.
+	if len(result.PreRunSteps) > 0 {
+		out.PreRunSteps = maps.Clone(result.PreRunSteps)
+	}
+
+	out.ResourceUsage = runResourceUsage(out)
+
+	return out
+}
+
+// writeRunResult writes result.json of resultsDir from the step results
+// accumulated so far and returns the written result.
+func (a *runResultAccumulator) writeRunResult(resultsDir string, owner *fsutil.OwnerConfig) (*RunResult, error) {
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+
+	result := a.runResult(resultsDir)
+
+	return result, WriteRunResult(resultsDir, result, owner)
 }
 
 // WriteRunResult writes the run result to result.json in the results directory.
@@ -0,0 +1,314 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPayloadLine is an engine_newPayload request carrying gas used, so the
+// aggregated stats include MGas/s.
+const newPayloadLine = `{"jsonrpc":"2.0","method":"engine_newPayloadV3",` +
+	`"params":[{"blockNumber":"0x1","blockHash":"0x01","gasUsed":"0x1c9c380"}],"id":1}`
+
+// sampleTestResult returns a step result with n newPayload calls.
+func sampleTestResult(name string, n int) *TestResult {
+	result := NewTestResult(name)
+
+	for i := range n {
+		result.AddResult("engine_newPayloadV3", newPayloadLine,
+			`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`,
+			int64(1_000_000+i*1000), i%10 != 9, nil)
+	}
+
+	return result
+}
+
+func TestRunResultAccumulator_MatchesGenerateRunResult(t *testing.T) {
+	dir := t.TempDir()
+
+	e := &executor{cfg: &Config{}}
+
+	// Steps written across what would be several ExecuteTests calls sharing
+	// a results directory, including nested test names and a single-call
+	// step whose stats only keep count and last.
+	require.NoError(t, e.writeStepResults(dir, "warmup.txt", StepTypePreRun, sampleTestResult("warmup.txt", 3)))
+	require.NoError(t, e.writeStepResults(dir, "a.txt", StepTypeSetup, sampleTestResult("a.txt", 1)))
+	require.NoError(t, e.writeStepResults(dir, "a.txt", StepTypeTest, sampleTestResult("a.txt", 20)))
+	require.NoError(t, e.writeStepResults(dir, "a.txt", StepTypeCleanup, sampleTestResult("a.txt", 2)))
+	require.NoError(t, e.writeStepResults(dir, "nested/b.txt", StepTypeTest, sampleTestResult("nested/b.txt", 5)))
+
+	// Steps of another run must not leak into this one.
+	require.NoError(t, e.writeStepResults(t.TempDir(), "other.txt", StepTypeTest, sampleTestResult("other.txt", 1)))
+
+	fromDisk, err := GenerateRunResult(dir)
+	require.NoError(t, err)
+
+	want, err := json.MarshalIndent(fromDisk, "", "  ")
+	require.NoError(t, err)
+
+	got, err := json.MarshalIndent(e.results.runResult(dir), "", "  ")
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(want), string(got))
+	assert.Len(t, e.results.runResult(dir).Tests, 2)
+}
+
+func TestRunResultAccumulator_Empty(t *testing.T) {
+	var acc runResultAccumulator
+
+	result := acc.runResult(t.TempDir())
+	assert.Empty(t, result.Tests)
+	assert.Nil(t, result.PreRunSteps)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tests":{}}`, string(data))
+}
+
+// resourceTestResult returns a step result whose calls used the given
+// resources.
+func resourceTestResult(name string, deltas ...*ResourceDelta) *TestResult {
+	result := NewTestResult(name)
+
+	for _, delta := range deltas {
+		result.AddResult("engine_newPayloadV3", newPayloadLine,
+			`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`,
+			1_000_000, true, delta)
+	}
+
+	return result
+}
+
+func TestRunResult_ResourceUsage(t *testing.T) {
+	dir := t.TempDir()
+
+	e := &executor{cfg: &Config{}}
+
+	require.NoError(t, e.writeStepResults(dir, "warmup.txt", StepTypePreRun, resourceTestResult("warmup.txt",
+		&ResourceDelta{MemoryAbsBytes: 100, CPUDeltaUsec: 250_000, DiskReadBytes: 10, DiskReadOps: 1},
+	)))
+	require.NoError(t, e.writeStepResults(dir, "a.txt", StepTypeSetup, resourceTestResult("a.txt",
+		&ResourceDelta{MemoryAbsBytes: 300, CPUDeltaUsec: 500_000, DiskWriteBytes: 20, DiskWriteOps: 2},
+	)))
+	// The peak of a step is not necessarily its last reading.
+	require.NoError(t, e.writeStepResults(dir, "a.txt", StepTypeTest, resourceTestResult("a.txt",
+		&ResourceDelta{MemoryAbsBytes: 900, CPUDeltaUsec: 1_000_000, DiskReadBytes: 40, DiskReadOps: 4},
+		&ResourceDelta{MemoryAbsBytes: 200, CPUDeltaUsec: 1_250_000, DiskWriteBytes: 80, DiskWriteOps: 8},
+	)))
+	require.NoError(t, e.writeStepResults(dir, "b.txt", StepTypeTest, resourceTestResult("b.txt",
+		&ResourceDelta{MemoryAbsBytes: 400, CPUDeltaUsec: 2_000_000, DiskReadBytes: 160, DiskReadOps: 16},
+	)))
+	// Steps without resource metrics don't count.
+	require.NoError(t, e.writeStepResults(dir, "c.txt", StepTypeTest, sampleTestResult("c.txt", 2)))
+
+	want := &RunResourceUsage{
+		CPUSeconds:      5,
+		PeakMemoryBytes: 900,
+		DiskReadBytes:   210,
+		DiskWriteBytes:  100,
+		DiskReadIOPS:    21,
+		DiskWriteIOPS:   10,
+	}
+
+	fromDisk, err := GenerateRunResult(dir)
+	require.NoError(t, err)
+
+	for name, result := range map[string]*RunResult{
+		"accumulated": e.results.runResult(dir),
+		"from disk":   fromDisk,
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NotNil(t, result.ResourceUsage)
+			assert.InDelta(t, want.CPUSeconds, result.ResourceUsage.CPUSeconds, 1e-9)
+
+			got := *result.ResourceUsage
+			got.CPUSeconds = want.CPUSeconds
+			assert.Equal(t, *want, got)
+		})
+	}
+
+	t.Run("without resource metrics", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, e.writeStepResults(dir, "a.txt", StepTypeTest, sampleTestResult("a.txt", 2)))
+
+		assert.Nil(t, e.results.runResult(dir).ResourceUsage)
+	})
+}
+
+func TestRunResultAccumulator_Forget(t *testing.T) {
+	var acc runResultAccumulator
+
+	finished, running := t.TempDir(), t.TempDir()
+
+	for _, dir := range []string{finished, running} {
+		acc.add(dir, "a.txt", StepTypeTest, &AggregatedStats{Succeeded: 1})
+		acc.skip(dir, "b.txt")
+	}
+
+	acc.forget(finished)
+
+	assert.NotContains(t, acc.results, finished)
+	assert.NotContains(t, acc.skipped, finished)
+	assert.Empty(t, acc.runResult(finished).Tests)
+
+	// Other runs are kept.
+	result := acc.runResult(running)
+	assert.Len(t, result.Tests, 1)
+	assert.Equal(t, 1, result.Skipped)
+}
+
+// writeBenchmarkSuite writes step results for n tests into dir and returns
+// the executor that wrote them.
+func writeBenchmarkSuite(b *testing.B, dir string, n int) *executor {
+	b.Helper()
+
+	e := &executor{cfg: &Config{}}
+
+	for i := range n {
+		name := fmt.Sprintf("test_%04d.txt", i)
+		if err := e.writeStepResults(dir, name, StepTypeTest, sampleTestResult(name, 50)); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return e
+}
+
+// BenchmarkRunResult_Generate measures building the run result by scanning
+// the step results on disk, as ExecuteTests used to.
+func BenchmarkRunResult_Generate(b *testing.B) {
+	dir := b.TempDir()
+	writeBenchmarkSuite(b, dir, 500)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for b.Loop() {
+		if _, err := GenerateRunResult(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRunResult_Accumulated measures building the run result from the
+// stats accumulated while writing step results.
+func BenchmarkRunResult_Accumulated(b *testing.B) {
+	dir := b.TempDir()
+	e := writeBenchmarkSuite(b, dir, 500)
+
+	// Sanity check that the benchmark covers the same results.
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 500 {
+		b.Fatalf("unexpected results dir: %d entries, %v", len(entries), err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for b.Loop() {
+		_ = e.results.runResult(dir)
+	}
+}
+
+func TestFailedTests(t *testing.T) {
+	step := func(failed int) *StepResult {
+		return &StepResult{Aggregated: &AggregatedStats{Succeeded: 1, Failed: failed}}
+	}
+
+	result := &RunResult{Tests: map[string]*TestEntry{
+		"c/test.txt":       {Steps: &StepsResult{Test: step(2)}},
+		"a/test.txt":       {Steps: &StepsResult{Setup: step(1), Test: step(0)}},
+		"passed/test.txt":  {Steps: &StepsResult{Setup: step(0), Test: step(0), Cleanup: step(0)}},
+		"cleanup/test.txt": {Steps: &StepsResult{Test: step(0), Cleanup: step(1)}},
+		"no-steps":         {},
+		"no-stats":         {Steps: &StepsResult{Test: &StepResult{}}},
+	}}
+
+	assert.Equal(t, []string{"a/test.txt", "c/test.txt", "cleanup/test.txt"}, FailedTests(result))
+	assert.Empty(t, FailedTests(&RunResult{}))
+}
+
+func TestTestDirName(t *testing.T) {
+	long := strings.Repeat("a", 300)
+
+	tests := []struct {
+		name     string
+		testName string
+		want     string
+	}{
+		{name: "plain", testName: "a.txt", want: "a.txt"},
+		{name: "slashes nest directories", testName: "nested/dir/b.txt", want: "nested/dir/b.txt"},
+		{
+			name:     "pytest node id",
+			testName: "benchmark/test_foo.py::test_bar[fork_Prague-blockchain_test]",
+			want:     "benchmark/test_foo.py::test_bar[fork_Prague-blockchain_test]",
+		},
+		{name: "parent components", testName: "../../etc/passwd", want: "__/__/etc/passwd"},
+		{name: "leading slash", testName: "/abs/test.txt", want: "abs/test.txt"},
+		{name: "nul byte", testName: "a\x00b.txt", want: "a_b.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, testDirName(tt.testName))
+		})
+	}
+
+	t.Run("long component", func(t *testing.T) {
+		got := testDirName("dir/" + long)
+
+		dir, base := filepath.Split(got)
+		assert.Equal(t, "dir/", dir)
+		assert.Len(t, base, maxTestDirComponent)
+		assert.True(t, strings.HasPrefix(base, "aaaa"))
+		assert.NotEqual(t, base, testDirName(long+"b"), "distinct names keep distinct directories")
+	})
+}
+
+func TestWriteStepResults_UnsafeTestNames(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "results")
+
+	names := []string{
+		"benchmark/test_foo.py::test_bar[fork_Prague]",
+		"../escape.txt",
+		"nested/" + strings.Repeat("x", 300) + ".txt",
+	}
+
+	e := &executor{cfg: &Config{}}
+
+	for _, name := range names {
+		require.NoError(t, e.writeStepResults(dir, name, StepTypeTest, sampleTestResult(name, 1)))
+	}
+
+	// Nothing is written outside the results directory.
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// Colons are kept, so the directory matches the test name.
+	_, err = os.Stat(filepath.Join(dir, names[0], "test.result-details.json"))
+	require.NoError(t, err)
+
+	// Renamed directories record the original test name.
+	data, err := os.ReadFile(filepath.Join(dir, "__", "escape.txt", "test.result-details.json"))
+	require.NoError(t, err)
+
+	var details ResultDetails
+	require.NoError(t, json.Unmarshal(data, &details))
+	assert.Equal(t, "../escape.txt", details.OriginalTestName)
+	assert.Equal(t, "__/escape.txt", details.FilenameHash)
+
+	// The run result stays keyed by the original names.
+	result := e.results.runResult(dir)
+	require.Len(t, result.Tests, 3)
+	assert.Empty(t, result.Tests[names[0]].FilenameHash)
+	assert.Equal(t, "__/escape.txt", result.Tests["../escape.txt"].FilenameHash)
+	assert.Equal(t, testDirName(names[2]), result.Tests[names[2]].FilenameHash)
+}
@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+)
+
+// ListRunDirs returns the IDs of the run directories under runsDir, sorted
+// by name. Runs of the date layout are found inside their YYYY/MM/DD
+// partition directories and their ID is the slash-separated path relative
+// to runsDir, e.g. "2024/06/10/1718000000_a1b2c3d4_geth".
+func ListRunDirs(runsDir string) ([]string, error) {
+	return listRunDirs(runsDir, "", 0)
+}
+
+func listRunDirs(runsDir, rel string, depth int) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(runsDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		id := path.Join(rel, entry.Name())
+
+		switch {
+		case depth < config.DatePartitionDepth && config.IsDatePartitionDir(depth, entry.Name()):
+			nested, err := listRunDirs(runsDir, id, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			ids = append(ids, nested...)
+		case depth == 0 || depth == config.DatePartitionDepth:
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// ListRunPrefixes returns the prefixes of the runs stored under runsPrefix
+// in remote storage, descending into the date partitions of the date
+// layout. runsPrefix must end with "/".
+func ListRunPrefixes(
+	ctx context.Context,
+	reader IndexObjectReader,
+	runsPrefix string,
+) ([]string, error) {
+	return listRunPrefixes(ctx, reader, runsPrefix, 0)
+}
+
+func listRunPrefixes(
+	ctx context.Context,
+	reader IndexObjectReader,
+	prefix string,
+	depth int,
+) ([]string, error) {
+	prefixes, err := reader.ListPrefixes(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	runPrefixes := make([]string, 0, len(prefixes))
+
+	for _, p := range prefixes {
+		name := path.Base(strings.TrimRight(p, "/"))
+
+		switch {
+		case depth < config.DatePartitionDepth && config.IsDatePartitionDir(depth, name):
+			nested, err := listRunPrefixes(ctx, reader, p, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			runPrefixes = append(runPrefixes, nested...)
+		case depth == 0 || depth == config.DatePartitionDepth:
+			runPrefixes = append(runPrefixes, p)
+		}
+	}
+
+	return runPrefixes, nil
+}
+
+// runIDFromPrefix extracts the run ID from a run prefix, e.g.
+// "demo/results/runs/2024/06/10/abc123/" → "2024/06/10/abc123".
+func runIDFromPrefix(runsPrefix, prefix string) string {
+	return strings.TrimRight(strings.TrimPrefix(prefix, runsPrefix), "/")
+}
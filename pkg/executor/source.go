@@ -1,17 +1,20 @@
 package executor
 
 import (
+	"cmp"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
+	"slices"
 	"strings"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
 	"github.com/ethpandaops/benchmarkoor/pkg/eest"
 	"github.com/sirupsen/logrus"
 )
@@ -47,9 +50,42 @@ type TestWithSteps struct {
 	Setup       *StepFile         // Optional setup step
 	Test        *StepFile         // Optional test step
 	Cleanup     *StepFile         // Optional cleanup step
-	GenesisHash string            // Genesis hash from pre_alloc (empty if single-genesis)
+	GenesisHash string            // Genesis hash from pre_alloc or manifest group name (empty if single-genesis)
 	EESTInfo    *eest.FixtureInfo // EEST fixture metadata (nil for non-EEST sources)
 	OpcodeCount map[string]int    // External opcode counts (nil if not provided)
+	Expected    *ExpectedResults  // Expected results to validate responses against (nil if none)
+}
+
+// matchesFilter reports whether the test's name or any of its step files
+// contain filter. An empty filter matches all tests.
+func (t *TestWithSteps) matchesFilter(filter string) bool {
+	if filter == "" || strings.Contains(t.Name, filter) {
+		return true
+	}
+
+	for _, step := range []*StepFile{t.Setup, t.Test, t.Cleanup} {
+		if step != nil && (strings.Contains(step.Name, filter) || strings.Contains(step.Path, filter)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SelectTests returns the tests of tests whose name is in names, in their
+// original order. Names are compared cleaned, as they are recorded in the run
+// result. The result is never nil, so it can be used as a test subset in
+// ExecuteOptions even if nothing matches.
+func SelectTests(tests []*TestWithSteps, names []string) []*TestWithSteps {
+	selected := make([]*TestWithSteps, 0, len(names))
+
+	for _, test := range tests {
+		if slices.Contains(names, filepath.Clean(test.Name)) {
+			selected = append(selected, test)
+		}
+	}
+
+	return selected
 }
 
 // PreparedSource contains the prepared test source with all discovered tests.
@@ -57,8 +93,18 @@ type PreparedSource struct {
 	BasePath    string
 	PreRunSteps []*StepFile
 	Tests       []*TestWithSteps
+	TestOrder   string // Key the tests are sorted by, one of the TestOrder* constants
 }
 
+// Test orders recorded in the suite output.
+const (
+	// TestOrderName sorts tests by name.
+	TestOrderName = "name"
+	// TestOrderGenesisName groups tests by genesis hash, sorted by name
+	// within each group.
+	TestOrderGenesisName = "genesis,name"
+)
+
 // Source provides test files from local or git sources.
 type Source interface {
 	// Prepare ensures test files are available and returns the prepared source.
@@ -77,17 +123,63 @@ type GenesisProvider interface {
 	GetGenesisPath(clientType string) string
 }
 
-// GenesisGroup represents a group of tests that share the same genesis.
+// sourceGenesisDir is the directory of a local or git source holding genesis
+// files, laid out per client like the genesis of an EEST release, e.g.
+// genesis/go-ethereum/genesis.json or genesis/nethermind/chainspec.json.
+const sourceGenesisDir = "genesis"
+
+// sourceGenesisPath returns the genesis file for a client type in the genesis
+// directory of the source at basePath, or an empty string if it has none.
+func sourceGenesisPath(log logrus.FieldLogger, basePath, clientType string) string {
+	if basePath == "" {
+		return ""
+	}
+
+	clientDir, filename := clientGenesisFile(clientType)
+	genesisPath := filepath.Join(basePath, sourceGenesisDir, clientDir, filename)
+
+	if _, err := os.Stat(genesisPath); err != nil {
+		log.WithFields(logrus.Fields{
+			"client": clientType,
+			"path":   genesisPath,
+		}).Debug("No genesis file in test source")
+
+		return ""
+	}
+
+	return genesisPath
+}
+
+// clientGenesisFile maps a client type to its genesis directory and filename.
+func clientGenesisFile(clientType string) (string, string) {
+	switch clientType {
+	case "geth", "erigon", "reth", "nimbus":
+		return "go-ethereum", "genesis.json"
+	case "nethermind":
+		return "nethermind", "chainspec.json"
+	case "besu":
+		return "besu", "genesis.json"
+	default:
+		return "go-ethereum", "genesis.json"
+	}
+}
+
+// GenesisGroup represents a group of tests that share the same genesis. The
+// GenesisHash of a group declared in a genesis group manifest is its name.
 type GenesisGroup struct {
 	GenesisHash string
 	Tests       []*TestWithSteps
+	// GenesisBlockHash is the expected hash of the genesis block, when the
+	// source knows it (EEST fixtures record it per test).
+	GenesisBlockHash string
 }
 
 // GenesisGroupProvider is an optional interface that sources can implement
 // to provide multiple genesis groups for multi-genesis test execution.
 type GenesisGroupProvider interface {
-	// GetGenesisGroups returns the genesis groups discovered from pre_alloc.
-	// Returns nil if no pre_alloc directory exists (backward compatible).
+	// GetGenesisGroups returns the genesis groups discovered from EEST
+	// pre_alloc or a genesis group manifest. Returns nil if the source has
+	// neither (backward compatible).
 	GetGenesisGroups() []*GenesisGroup
 	// GetGenesisPathForGroup returns the genesis file path for a specific
 	// genesis hash and client type.
@@ -95,36 +187,45 @@ type GenesisGroupProvider interface {
 }
 
 // NewSource creates a Source from the configuration.
-func NewSource(log logrus.FieldLogger, cfg *config.SourceConfig, cacheDir, filter, githubToken string) Source {
+func NewSource(
+	log logrus.FieldLogger,
+	cfg *config.SourceConfig,
+	cacheDir, filter, githubToken string,
+	downloadRetry download.RetryPolicy,
+	allowEmptyGlobs, offline bool,
+) Source {
 	if cfg.Local != nil {
 		return &LocalSource{
-			log:    log.WithField("source", "local"),
-			cfg:    cfg.Local,
-			filter: filter,
+			log:             log.WithField("source", "local"),
+			cfg:             cfg.Local,
+			filter:          filter,
+			allowEmptyGlobs: allowEmptyGlobs,
 		}
 	}
 
 	if cfg.Git != nil {
 		return &GitSource{
-			log:      log.WithField("source", "git"),
-			cfg:      cfg.Git,
-			cacheDir: cacheDir,
-			filter:   filter,
+			log:             log.WithField("source", "git"),
+			cfg:             cfg.Git,
+			cacheDir:        cacheDir,
+			filter:          filter,
+			allowEmptyGlobs: allowEmptyGlobs,
 		}
 	}
 
 	if cfg.Archive != nil {
 		return &ArchiveSource{
-			log:         log.WithField("source", "archive"),
-			cfg:         cfg.Archive,
-			cacheDir:    cacheDir,
-			filter:      filter,
-			githubToken: githubToken,
+			log:             log.WithField("source", "archive"),
+			cfg:             cfg.Archive,
+			cacheDir:        cacheDir,
+			filter:          filter,
+			githubToken:     githubToken,
+			allowEmptyGlobs: allowEmptyGlobs,
 		}
 	}
 
 	if cfg.EESTFixtures != nil {
-		return NewEESTSource(log, cfg.EESTFixtures, cacheDir, filter, githubToken)
+		return NewEESTSource(log, cfg.EESTFixtures, cacheDir, filter, githubToken, downloadRetry, offline)
 	}
 
 	return nil
@@ -132,10 +233,12 @@ func NewSource(log logrus.FieldLogger, cfg *config.SourceConfig, cacheDir, filte
 
 // LocalSource reads tests from a local directory.
 type LocalSource struct {
-	log      logrus.FieldLogger
-	cfg      *config.LocalSourceV2
-	filter   string
-	basePath string
+	log             logrus.FieldLogger
+	cfg             *config.LocalSourceV2
+	filter          string
+	basePath        string
+	allowEmptyGlobs bool // Warn instead of failing when a glob matches no files
+	genesisGroups   []*GenesisGroup
 }
 
 // Prepare validates that the local directory exists and discovers tests.
@@ -152,7 +255,19 @@ func (s *LocalSource) Prepare(_ context.Context) (*PreparedSource, error) {
 
 // discoverTests discovers all tests from the local source.
 func (s *LocalSource) discoverTests() (*PreparedSource, error) {
-	return discoverTestsFromConfig(s.basePath, s.cfg.PreRunSteps, s.cfg.Steps, s.filter, s.log)
+	prepared, err := discoverTestsFromConfig(
+		s.basePath, s.cfg.PreRunSteps, s.cfg.Steps, s.filter, s.allowEmptyGlobs, s.log,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.genesisGroups, err = loadGenesisGroups(s.log, s.basePath, prepared)
+	if err != nil {
+		return nil, err
+	}
+
+	return prepared, nil
 }
 
 // Cleanup is a no-op for local sources.
@@ -160,6 +275,24 @@ func (s *LocalSource) Cleanup() error {
 	return nil
 }
 
+// GetGenesisPath returns the genesis file for a client type from the genesis
+// directory of the base directory, if it has one.
+func (s *LocalSource) GetGenesisPath(clientType string) string {
+	return sourceGenesisPath(s.log, s.basePath, clientType)
+}
+
+// GetGenesisGroups returns the genesis groups declared by the genesis group
+// manifest of the base directory.
+func (s *LocalSource) GetGenesisGroups() []*GenesisGroup {
+	return s.genesisGroups
+}
+
+// GetGenesisPathForGroup returns the genesis file path for a specific
+// genesis group and client type.
+func (s *LocalSource) GetGenesisPathForGroup(genesisHash, clientType string) string {
+	return sourceGenesisPathForGroup(s.log, s.basePath, genesisHash, clientType)
+}
+
 // GetSourceInfo returns source information for the suite summary.
 func (s *LocalSource) GetSourceInfo() (*SuiteSource, error) {
 	local := &LocalSourceInfo{
@@ -185,6 +318,17 @@ type GitSource struct {
 	cacheDir string
 	filter   string
 	basePath string
+
+	// allowEmptyGlobs warns instead of failing when a glob matches no files.
+	allowEmptyGlobs bool
+
+	// preRunSource is the separate repository providing shared pre-run
+	// steps (nil if not configured).
+	preRunSource *GitSource
+
+	// genesisGroups are the genesis groups declared by the repository's
+	// genesis group manifest (nil if it has none).
+	genesisGroups []*GenesisGroup
 }
 
 // Prepare clones or updates the git repository and discovers tests.
@@ -196,13 +340,71 @@ func (s *GitSource) Prepare(ctx context.Context) (*PreparedSource, error) {
 
 	s.basePath = basePath
 
-	return s.discoverTests()
+	prepared, err := s.discoverTests()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cfg.PreRunStepsSource != nil {
+		preRunSteps, err := s.preparePreRunStepsSource(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("preparing pre_run_steps_source: %w", err)
+		}
+
+		// Pre-run steps are stored and reported by name, so the names of
+		// both repos must not collide.
+		names := make(map[string]struct{}, len(preRunSteps))
+		for _, step := range preRunSteps {
+			names[step.Name] = struct{}{}
+		}
+
+		for _, step := range prepared.PreRunSteps {
+			if _, ok := names[step.Name]; ok {
+				return nil, fmt.Errorf("pre-run step %q exists in both repositories", step.Name)
+			}
+		}
+
+		prepared.PreRunSteps = append(preRunSteps, prepared.PreRunSteps...)
+	}
+
+	return prepared, nil
+}
+
+// preparePreRunStepsSource clones or updates the pre-run steps repository
+// and returns its pre-run steps. They run before the source's own, so a
+// shared warmup sequence can be reused across suites.
+func (s *GitSource) preparePreRunStepsSource(ctx context.Context) ([]*StepFile, error) {
+	cfg := s.cfg.PreRunStepsSource
+
+	s.preRunSource = &GitSource{
+		log:             s.log.WithField("pre_run_steps_repo", cfg.Repo),
+		cfg:             cfg,
+		cacheDir:        s.cacheDir,
+		allowEmptyGlobs: s.allowEmptyGlobs,
+	}
+
+	basePath, err := s.preRunSource.prepareRepo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("preparing git repo: %w", err)
+	}
+
+	s.preRunSource.basePath = basePath
+
+	// Pre-run steps are never filtered.
+	prepared, err := discoverTestsFromConfig(
+		basePath, cfg.PreRunSteps, nil, "", s.allowEmptyGlobs, s.preRunSource.log,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return prepared.PreRunSteps, nil
 }
 
-// prepareRepo clones or updates the git repository.
+// prepareRepo clones or updates the git repository. Each repo+version pair
+// gets its own checkout, so switching between versions doesn't re-clone.
 func (s *GitSource) prepareRepo(ctx context.Context) (string, error) {
-	repoHash := hashRepoURL(s.cfg.Repo)
-	localPath := filepath.Join(s.cacheDir, repoHash)
+	localPath := filepath.Join(s.cacheDir, gitCacheKey(s.cfg.Repo, s.cfg.Version))
 
 	log := s.log.WithFields(logrus.Fields{
 		"repo":    s.cfg.Repo,
@@ -217,56 +419,35 @@ func (s *GitSource) prepareRepo(ctx context.Context) (string, error) {
 			return "", fmt.Errorf("creating cache directory: %w", err)
 		}
 
-		if looksLikeCommitHash(s.cfg.Version) {
-			// Commit hashes can't be used with --branch, so we init + fetch instead.
-			if err := s.cloneByCommitHash(ctx, localPath); err != nil {
-				return "", err
-			}
-		} else {
-			// Shallow clone with specific branch/tag.
-			cmd := exec.CommandContext(ctx, "git", "clone",
-				"--depth=1",
-				"--branch", s.cfg.Version,
-				"--single-branch",
-				s.cfg.Repo, localPath)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-
-			if err := cmd.Run(); err != nil {
-				return "", fmt.Errorf("cloning repository: %w", err)
-			}
-		}
-	} else {
-		// For commit hashes, skip fetch if HEAD already matches.
-		if looksLikeCommitHash(s.cfg.Version) {
-			headHash, err := s.getHeadHash(ctx, localPath)
-			if err == nil && strings.HasPrefix(headHash, s.cfg.Version) {
-				log.Info("Cached repository already at requested version")
+		if err := runGitCommands(ctx, s.cloneCommands(localPath)); err != nil {
+			// Don't leave a partial clone behind to be reused by the next run.
+			_ = os.RemoveAll(localPath)
 
-				return localPath, nil
-			}
+			return "", err
 		}
 
-		log.Info("Updating cached repository")
+		return localPath, nil
+	}
+
+	// For commit hashes, skip fetch if HEAD already matches.
+	if looksLikeCommitHash(s.cfg.Version) {
+		headHash, err := s.getHeadHash(ctx, localPath)
+		if err == nil && strings.HasPrefix(headHash, s.cfg.Version) {
+			log.Info("Cached repository already at requested version")
 
-		// Fetch the specific version.
-		cmd := exec.CommandContext(ctx, "git", "-C", localPath, "fetch",
-			"--depth=1", "origin", s.cfg.Version)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+			// The sparse paths may have changed since the checkout.
+			if err := runGitCommands(ctx, s.sparseCheckoutCommands(localPath, true)); err != nil {
+				return "", err
+			}
 
-		if err := cmd.Run(); err != nil {
-			return "", fmt.Errorf("fetching version: %w", err)
+			return localPath, nil
 		}
+	}
 
-		// Checkout FETCH_HEAD.
-		cmd = exec.CommandContext(ctx, "git", "-C", localPath, "checkout", "FETCH_HEAD")
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+	log.Info("Updating cached repository")
 
-		if err := cmd.Run(); err != nil {
-			return "", fmt.Errorf("checking out version: %w", err)
-		}
+	if err := runGitCommands(ctx, s.updateCommands(localPath)); err != nil {
+		return "", err
 	}
 
 	return localPath, nil
@@ -274,7 +455,19 @@ func (s *GitSource) prepareRepo(ctx context.Context) (string, error) {
 
 // discoverTests discovers all tests from the git source.
 func (s *GitSource) discoverTests() (*PreparedSource, error) {
-	return discoverTestsFromConfig(s.basePath, s.cfg.PreRunSteps, s.cfg.Steps, s.filter, s.log)
+	prepared, err := discoverTestsFromConfig(
+		s.basePath, s.cfg.PreRunSteps, s.cfg.Steps, s.filter, s.allowEmptyGlobs, s.log,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.genesisGroups, err = loadGenesisGroups(s.log, s.basePath, prepared)
+	if err != nil {
+		return nil, err
+	}
+
+	return prepared, nil
 }
 
 // Cleanup is a no-op for git sources (we keep the cache).
@@ -282,6 +475,24 @@ func (s *GitSource) Cleanup() error {
 	return nil
 }
 
+// GetGenesisPath returns the genesis file for a client type from the genesis
+// directory of the repository, if it has one.
+func (s *GitSource) GetGenesisPath(clientType string) string {
+	return sourceGenesisPath(s.log, s.basePath, clientType)
+}
+
+// GetGenesisGroups returns the genesis groups declared by the genesis group
+// manifest of the repository.
+func (s *GitSource) GetGenesisGroups() []*GenesisGroup {
+	return s.genesisGroups
+}
+
+// GetGenesisPathForGroup returns the genesis file path for a specific
+// genesis group and client type.
+func (s *GitSource) GetGenesisPathForGroup(genesisHash, clientType string) string {
+	return sourceGenesisPathForGroup(s.log, s.basePath, genesisHash, clientType)
+}
+
 // GetSourceInfo returns source information for the suite summary.
 func (s *GitSource) GetSourceInfo() (*SuiteSource, error) {
 	sha, err := GetGitCommitSHA(s.basePath)
@@ -296,6 +507,15 @@ func (s *GitSource) GetSourceInfo() (*SuiteSource, error) {
 		PreRunSteps: s.cfg.PreRunSteps,
 	}
 
+	if s.preRunSource != nil {
+		preRunInfo, err := s.preRunSource.GetSourceInfo()
+		if err != nil {
+			return nil, fmt.Errorf("getting pre_run_steps_source info: %w", err)
+		}
+
+		git.PreRunStepsSource = preRunInfo.Git
+	}
+
 	if s.cfg.Steps != nil {
 		git.Steps = &SourceStepsGlobs{
 			Setup:   s.cfg.Steps.Setup,
@@ -307,45 +527,136 @@ func (s *GitSource) GetSourceInfo() (*SuiteSource, error) {
 	return &SuiteSource{Git: git}, nil
 }
 
-// cloneByCommitHash initializes a repo and fetches a specific commit hash.
-func (s *GitSource) cloneByCommitHash(ctx context.Context, localPath string) error {
-	// git init
-	cmd := exec.CommandContext(ctx, "git", "init", localPath)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// gitCommand is a git invocation and the description used in its error.
+type gitCommand struct {
+	desc string
+	args []string
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("initializing repository: %w", err)
+// runGitCommands runs git commands in order, stopping at the first failure.
+func runGitCommands(ctx context.Context, cmds []gitCommand) error {
+	for _, c := range cmds {
+		cmd := exec.CommandContext(ctx, "git", c.args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", c.desc, err)
+		}
 	}
 
-	// git remote add origin <repo>
-	cmd = exec.CommandContext(ctx, "git", "-C", localPath,
-		"remote", "add", "origin", s.cfg.Repo)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	return nil
+}
+
+// cloneCommands returns the git commands that clone the configured version
+// into localPath. Branches and tags are cloned directly; commit hashes can't
+// be used with --branch, and sparse checkouts must be configured before the
+// first checkout, so both init an empty repo and fetch instead.
+func (s *GitSource) cloneCommands(localPath string) []gitCommand {
+	if !looksLikeCommitHash(s.cfg.Version) && !s.sparse() {
+		args := append([]string{"clone"}, s.depthArgs()...)
+		args = append(args, "--branch", s.cfg.Version, "--single-branch", s.cfg.Repo, localPath)
+
+		return []gitCommand{{desc: "cloning repository", args: args}}
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("adding remote: %w", err)
+	cmds := []gitCommand{
+		{desc: "initializing repository", args: []string{"init", localPath}},
+		{desc: "adding remote", args: []string{"-C", localPath, "remote", "add", "origin", s.cfg.Repo}},
 	}
 
-	// git fetch --depth=1 origin <hash>
-	cmd = exec.CommandContext(ctx, "git", "-C", localPath,
-		"fetch", "--depth=1", "origin", s.cfg.Version)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmds = append(cmds, s.sparseCheckoutCommands(localPath, false)...)
+
+	return append(cmds, s.fetchCommands(localPath)...)
+}
+
+// updateCommands returns the git commands that check out the configured
+// version in the existing clone at localPath.
+func (s *GitSource) updateCommands(localPath string) []gitCommand {
+	return append(s.sparseCheckoutCommands(localPath, true), s.fetchCommands(localPath)...)
+}
+
+// fetchCommands returns the git commands that fetch and check out the
+// configured version in the repo at localPath.
+func (s *GitSource) fetchCommands(localPath string) []gitCommand {
+	args := append([]string{"-C", localPath, "fetch"}, s.depthArgs()...)
+
+	// Blobs outside the sparse paths are only downloaded if ever checked out.
+	if s.sparse() {
+		args = append(args, "--filter=blob:none")
+	}
+
+	args = append(args, "origin", s.cfg.Version)
+
+	return []gitCommand{
+		{desc: fmt.Sprintf("fetching version %s", s.cfg.Version), args: args},
+		{
+			desc: fmt.Sprintf("checking out version %s", s.cfg.Version),
+			args: []string{"-C", localPath, "checkout", "FETCH_HEAD"},
+		},
+	}
+}
+
+// sparseCheckoutCommands returns the git commands that restrict the checkout
+// at localPath to the sparse paths. Without sparse paths, an existing clone
+// is switched back to a full checkout, since it may have been sparse before.
+func (s *GitSource) sparseCheckoutCommands(localPath string, existing bool) []gitCommand {
+	if !s.sparse() {
+		if !existing {
+			return nil
+		}
+
+		return []gitCommand{{
+			desc: "disabling sparse checkout",
+			args: []string{"-C", localPath, "sparse-checkout", "disable"},
+		}}
+	}
+
+	args := append([]string{"-C", localPath, "sparse-checkout", "set", "--no-cone"}, s.sparsePatterns()...)
+
+	return []gitCommand{{desc: "configuring sparse checkout", args: args}}
+}
+
+// sparse reports whether the checkout is restricted to the sparse paths.
+func (s *GitSource) sparse() bool {
+	return len(s.cfg.SparsePaths) > 0
+}
+
+// sparsePatterns returns the sparse-checkout patterns: the configured sparse
+// paths plus the pre-run step and step globs and the genesis directory,
+// anchored at the repo root.
+func (s *GitSource) sparsePatterns() []string {
+	patterns := make([]string, 0, len(s.cfg.SparsePaths)+len(s.cfg.PreRunSteps)+1)
+	patterns = append(patterns, s.cfg.SparsePaths...)
+	patterns = append(patterns, s.cfg.PreRunSteps...)
+
+	if s.cfg.Steps != nil {
+		patterns = append(patterns, s.cfg.Steps.Setup...)
+		patterns = append(patterns, s.cfg.Steps.Test...)
+		patterns = append(patterns, s.cfg.Steps.Cleanup...)
+	}
+
+	patterns = append(patterns, sourceGenesisDir+"/")
+
+	seen := make(map[string]struct{}, len(patterns))
+	result := make([]string, 0, len(patterns))
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("fetching commit %s: %w", s.cfg.Version, err)
+	for _, p := range patterns {
+		p = "/" + strings.TrimPrefix(filepath.ToSlash(p), "/")
+
+		if _, ok := seen[p]; !ok {
+			seen[p] = struct{}{}
+			result = append(result, p)
+		}
 	}
 
-	// git checkout FETCH_HEAD
-	cmd = exec.CommandContext(ctx, "git", "-C", localPath,
-		"checkout", "FETCH_HEAD")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	return result
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("checking out commit %s: %w", s.cfg.Version, err)
+// depthArgs returns the git arguments limiting the fetched history.
+func (s *GitSource) depthArgs() []string {
+	if depth := s.cfg.GetDepth(); depth > 0 {
+		return []string{fmt.Sprintf("--depth=%d", depth)}
 	}
 
 	return nil
@@ -379,6 +690,14 @@ func looksLikeCommitHash(s string) bool {
 	return true
 }
 
+// gitCacheKey returns the cache directory name for a repo+version pair.
+func gitCacheKey(repo, version string) string {
+	return hashRepoURL(repo) + "-" + hashRepoURL(version)
+}
+
+// errEmptyGlob is returned when a step glob pattern matches no step files.
+var errEmptyGlob = errors.New("matched no step files")
+
 // hashRepoURL creates a hash of the repository URL for caching.
 func hashRepoURL(url string) string {
 	hash := sha256.Sum256([]byte(url))
@@ -392,12 +711,14 @@ func discoverTestsFromConfig(
 	preRunStepPatterns []string,
 	steps *config.StepsConfig,
 	filter string,
+	allowEmptyGlobs bool,
 	log logrus.FieldLogger,
 ) (*PreparedSource, error) {
 	result := &PreparedSource{
 		BasePath:    basePath,
 		PreRunSteps: make([]*StepFile, 0),
 		Tests:       make([]*TestWithSteps, 0),
+		TestOrder:   TestOrderName,
 	}
 
 	// Discover pre-run steps in config order.
@@ -405,8 +726,8 @@ func discoverTestsFromConfig(
 	// Within each pattern, filepath.Glob returns files in lexicographic order.
 	for _, pattern := range preRunStepPatterns {
 		files, _, err := expandGlobPattern(basePath, pattern, "")
-		if err != nil {
-			return nil, fmt.Errorf("expanding pre_run_steps pattern %q: %w", pattern, err)
+		if err != nil && !skipEmptyGlob(err, allowEmptyGlobs, log) {
+			return nil, fmt.Errorf("expanding pre_run_steps: %w", err)
 		}
 
 		result.PreRunSteps = append(result.PreRunSteps, files...)
@@ -420,17 +741,17 @@ func discoverTestsFromConfig(
 	}
 
 	// Discover files for each step type.
-	setupFiles, setupPrefixes, err := expandGlobPatterns(basePath, steps.Setup, filter)
+	setupFiles, setupPrefixes, err := expandGlobPatterns(basePath, steps.Setup, filter, allowEmptyGlobs, log)
 	if err != nil {
 		return nil, fmt.Errorf("expanding setup patterns: %w", err)
 	}
 
-	testFiles, testPrefixes, err := expandGlobPatterns(basePath, steps.Test, filter)
+	testFiles, testPrefixes, err := expandGlobPatterns(basePath, steps.Test, filter, allowEmptyGlobs, log)
 	if err != nil {
 		return nil, fmt.Errorf("expanding test patterns: %w", err)
 	}
 
-	cleanupFiles, cleanupPrefixes, err := expandGlobPatterns(basePath, steps.Cleanup, filter)
+	cleanupFiles, cleanupPrefixes, err := expandGlobPatterns(basePath, steps.Cleanup, filter, allowEmptyGlobs, log)
 	if err != nil {
 		return nil, fmt.Errorf("expanding cleanup patterns: %w", err)
 	}
@@ -442,11 +763,26 @@ func discoverTestsFromConfig(
 	}).Debug("Discovered step files")
 
 	// Group files by matching key (relative path after stripping static prefix).
-	result.Tests = groupTestsByFilename(
+	result.Tests, err = groupTestsByFilename(
 		setupFiles, setupPrefixes,
 		testFiles, testPrefixes,
 		cleanupFiles, cleanupPrefixes,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := attachExpectedResults(result.Tests); err != nil {
+		return nil, err
+	}
+
+	// Setup or cleanup files without a matching test file are most likely
+	// misnamed. Such tests are skipped when executed.
+	for _, test := range result.Tests {
+		if test.Test == nil {
+			log.WithField("test", test.Name).Warn("Test has no test step and will be skipped")
+		}
+	}
 
 	log.WithField("count", len(result.Tests)).Info("Discovered tests with steps")
 
@@ -455,14 +791,16 @@ func discoverTestsFromConfig(
 
 // expandGlobPatterns expands multiple glob patterns and returns unique files
 // along with the collected static prefixes from all patterns.
-func expandGlobPatterns(basePath string, patterns []string, filter string) ([]*StepFile, []string, error) {
+func expandGlobPatterns(
+	basePath string, patterns []string, filter string, allowEmptyGlobs bool, log logrus.FieldLogger,
+) ([]*StepFile, []string, error) {
 	seen := make(map[string]struct{}, len(patterns)*10)
 	result := make([]*StepFile, 0, len(patterns)*10)
 	prefixes := make([]string, 0, len(patterns))
 
 	for _, pattern := range patterns {
 		files, staticPrefix, err := expandGlobPattern(basePath, pattern, filter)
-		if err != nil {
+		if err != nil && !skipEmptyGlob(err, allowEmptyGlobs, log) {
 			return nil, nil, err
 		}
 
@@ -493,6 +831,7 @@ func expandGlobPattern(basePath, pattern, filter string) ([]*StepFile, string, e
 	}
 
 	result := make([]*StepFile, 0, len(matches))
+	matched := 0 // Step files matched before filtering.
 
 	for _, match := range matches {
 		// Skip directories.
@@ -510,6 +849,8 @@ func expandGlobPattern(basePath, pattern, filter string) ([]*StepFile, string, e
 			continue
 		}
 
+		matched++
+
 		// Apply filter if provided.
 		if filter != "" && !strings.Contains(match, filter) {
 			continue
@@ -526,36 +867,53 @@ func expandGlobPattern(basePath, pattern, filter string) ([]*StepFile, string, e
 		})
 	}
 
+	// A filter may legitimately exclude every file, but a pattern matching
+	// nothing at all is most likely a typo.
+	if matched == 0 {
+		return nil, staticPrefix, fmt.Errorf("glob pattern %q %w", pattern, errEmptyGlob)
+	}
+
 	return result, staticPrefix, nil
 }
 
+// skipEmptyGlob reports whether err is an empty glob error that is allowed,
+// logging a warning for it.
+func skipEmptyGlob(err error, allowEmptyGlobs bool, log logrus.FieldLogger) bool {
+	if !allowEmptyGlobs || !errors.Is(err, errEmptyGlob) {
+		return false
+	}
+
+	log.WithError(err).Warn("Step glob pattern matched no files")
+
+	return true
+}
+
 // groupTestsByFilename groups step files by their matching key.
 // The matching key is derived by stripping the static prefix from the file path,
 // allowing files in different directories with the same relative path to be matched.
 // For example: "stateful_tests/setup/001/abc.txt" with prefix "stateful_tests/setup/"
 // produces key "001/abc.txt".
+// Two step files of the same type producing the same key would write their
+// results to the same test, so they are rejected with an error.
 func groupTestsByFilename(
 	setupFiles []*StepFile, setupPrefixes []string,
 	testFiles []*StepFile, testPrefixes []string,
 	cleanupFiles []*StepFile, cleanupPrefixes []string,
-) []*TestWithSteps {
+) ([]*TestWithSteps, error) {
 	// Build maps of matching key -> StepFile for each step type.
-	setupByKey := make(map[string]*StepFile, len(setupFiles))
-	for _, f := range setupFiles {
-		key := findMatchingKey(f.Name, setupPrefixes)
-		setupByKey[key] = f
+	setupByKey, err := stepFilesByKey(StepTypeSetup, setupFiles, setupPrefixes)
+	if err != nil {
+		return nil, err
 	}
 
-	testByKey := make(map[string]*StepFile, len(testFiles))
-	for _, f := range testFiles {
-		key := findMatchingKey(f.Name, testPrefixes)
-		testByKey[key] = f
+	testByKey, err := stepFilesByKey(StepTypeTest, testFiles, testPrefixes)
+	if err != nil {
+		return nil, err
 	}
 
-	cleanupByKey := make(map[string]*StepFile, len(cleanupFiles))
-	for _, f := range cleanupFiles {
-		key := findMatchingKey(f.Name, cleanupPrefixes)
-		cleanupByKey[key] = f
+	cleanupByKey, err := stepFilesByKey(StepTypeCleanup, cleanupFiles, cleanupPrefixes)
+	if err != nil {
+		return nil, err
 	}
 
 	// Collect all unique matching keys.
@@ -572,18 +930,10 @@ func groupTestsByFilename(
 		allKeys[key] = struct{}{}
 	}
 
-	// Create sorted list of keys.
-	keys := make([]string, 0, len(allKeys))
-	for key := range allKeys {
-		keys = append(keys, key)
-	}
-
-	sort.Strings(keys)
-
 	// Build TestWithSteps for each unique matching key.
-	tests := make([]*TestWithSteps, 0, len(keys))
+	tests := make([]*TestWithSteps, 0, len(allKeys))
 
-	for _, key := range keys {
+	for key := range allKeys {
 		test := &TestWithSteps{
 			Name:    key,
 			Setup:   setupByKey[key],
@@ -593,7 +943,53 @@ func groupTestsByFilename(
 		tests = append(tests, test)
 	}
 
-	return tests
+	sortTests(tests)
+
+	return tests, nil
+}
+
+// stepFilesByKey maps the matching key of each step file to the file. It
+// returns an error naming both files if two of them share a key.
+func stepFilesByKey(stepType StepType, files []*StepFile, prefixes []string) (map[string]*StepFile, error) {
+	byKey := make(map[string]*StepFile, len(files))
+
+	for _, f := range files {
+		key := findMatchingKey(f.Name, prefixes)
+
+		if existing, ok := byKey[key]; ok {
+			return nil, fmt.Errorf(
+				"%s files %q and %q both produce test name %q",
+				stepType, existing.Name, f.Name, key,
+			)
+		}
+
+		byKey[key] = f
+	}
+
+	return byKey, nil
+}
+
+// sortTests sorts tests by name, compared as slash-separated paths so the
+// order doesn't depend on the OS, the filesystem or directory read order.
+// Tests with the same name are ordered by their step file paths.
+func sortTests(tests []*TestWithSteps) {
+	slices.SortStableFunc(tests, func(a, b *TestWithSteps) int {
+		return cmp.Or(
+			strings.Compare(filepath.ToSlash(a.Name), filepath.ToSlash(b.Name)),
+			strings.Compare(stepSortKey(a.Test), stepSortKey(b.Test)),
+			strings.Compare(stepSortKey(a.Setup), stepSortKey(b.Setup)),
+			strings.Compare(stepSortKey(a.Cleanup), stepSortKey(b.Cleanup)),
+		)
+	})
+}
+
+// stepSortKey returns the path used to order tests sharing a name.
+func stepSortKey(step *StepFile) string {
+	if step == nil {
+		return ""
+	}
+
+	return filepath.ToSlash(step.Name)
 }
 
 // extractStaticPrefix extracts the static prefix from a glob pattern.
@@ -1,11 +1,19 @@
 package executor
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -38,6 +46,7 @@ func TestDiscoverTestsFromConfig_PreRunStepsNotFiltered(t *testing.T) {
 			Test: []string{"testing/*/*"},
 		},
 		"bn128", // filter that does NOT match pre_run_step paths
+		false,
 		log,
 	)
 	require.NoError(t, err)
@@ -57,6 +66,89 @@ func TestDiscoverTestsFromConfig_PreRunStepsNotFiltered(t *testing.T) {
 	assert.Contains(t, result.Tests[0].Name, "bn128")
 }
 
+func TestDiscoverTestsFromConfig_EmptyGlob(t *testing.T) {
+	base := t.TempDir()
+
+	for _, name := range []string{"funding.txt", "testing/bn128/test.txt", "testing/ecadd/test.txt", "notes.md"} {
+		path := filepath.Join(base, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("payload"), 0644))
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	tests := []struct {
+		name      string
+		preRun    []string
+		steps     *config.StepsConfig
+		filter    string
+		errSubstr string
+	}{
+		{
+			name:      "test glob typo",
+			steps:     &config.StepsConfig{Test: []string{"testing/*/*", "tseting/*/*"}},
+			errSubstr: `expanding test patterns: glob pattern "tseting/*/*" matched no step files`,
+		},
+		{
+			name:      "setup glob matching no files",
+			steps:     &config.StepsConfig{Setup: []string{"setup/*.txt"}, Test: []string{"testing/*/*"}},
+			errSubstr: `expanding setup patterns: glob pattern "setup/*.txt" matched no step files`,
+		},
+		{
+			name:      "cleanup glob matching only non-step files",
+			steps:     &config.StepsConfig{Test: []string{"testing/*/*"}, Cleanup: []string{"*.md"}},
+			errSubstr: `expanding cleanup patterns: glob pattern "*.md" matched no step files`,
+		},
+		{
+			name:      "pre-run step glob",
+			preRun:    []string{"fundng.txt"},
+			errSubstr: `expanding pre_run_steps: glob pattern "fundng.txt" matched no step files`,
+		},
+		{
+			name:   "filter excluding all files is not an error",
+			preRun: []string{"funding.txt"},
+			steps:  &config.StepsConfig{Test: []string{"testing/*/*"}},
+			filter: "modexp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := discoverTestsFromConfig(base, tt.preRun, tt.steps, tt.filter, false, log)
+			if tt.errSubstr == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errEmptyGlob)
+			assert.Contains(t, err.Error(), tt.errSubstr)
+
+			// Allowing empty globs only warns.
+			_, err = discoverTestsFromConfig(base, tt.preRun, tt.steps, tt.filter, true, log)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDiscoverTestsFromConfig_AllowEmptyGlobs(t *testing.T) {
+	base := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "testing"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "testing", "a.txt"), []byte("payload"), 0644))
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	result, err := discoverTestsFromConfig(base, nil, &config.StepsConfig{
+		Test: []string{"missing/*.txt", "testing/*.txt"},
+	}, "", true, log)
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 1)
+	assert.Equal(t, "testing/a.txt", result.Tests[0].Test.Name)
+}
+
 func TestLooksLikeCommitHash(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -84,3 +176,466 @@ func TestLooksLikeCommitHash(t *testing.T) {
 		})
 	}
 }
+
+func gitArgs(cmds []gitCommand) [][]string {
+	args := make([][]string, 0, len(cmds))
+	for _, c := range cmds {
+		args = append(args, c.args)
+	}
+
+	return args
+}
+
+func TestGitSource_CloneCommands(t *testing.T) {
+	const (
+		repo = "https://github.com/example/tests.git"
+		dir  = "/cache/repo"
+	)
+
+	fullDepth := 0
+	steps := &config.StepsConfig{Setup: []string{"tests/setup/*/*"}, Test: []string{"tests/testing/*/*"}}
+
+	tests := []struct {
+		name string
+		cfg  *config.GitSourceV2
+		want [][]string
+	}{
+		{
+			name: "branch",
+			cfg:  &config.GitSourceV2{Repo: repo, Version: "main"},
+			want: [][]string{
+				{"clone", "--depth=1", "--branch", "main", "--single-branch", repo, dir},
+			},
+		},
+		{
+			name: "branch full history",
+			cfg:  &config.GitSourceV2{Repo: repo, Version: "v1.0.0", Depth: &fullDepth},
+			want: [][]string{
+				{"clone", "--branch", "v1.0.0", "--single-branch", repo, dir},
+			},
+		},
+		{
+			name: "commit hash",
+			cfg:  &config.GitSourceV2{Repo: repo, Version: "abc1234"},
+			want: [][]string{
+				{"init", dir},
+				{"-C", dir, "remote", "add", "origin", repo},
+				{"-C", dir, "fetch", "--depth=1", "origin", "abc1234"},
+				{"-C", dir, "checkout", "FETCH_HEAD"},
+			},
+		},
+		{
+			name: "sparse branch",
+			cfg: &config.GitSourceV2{
+				Repo:        repo,
+				Version:     "main",
+				PreRunSteps: []string{"funding/*.txt", "/tests/setup/*/*"},
+				Steps:       steps,
+				SparsePaths: []string{"genesis/"},
+			},
+			want: [][]string{
+				{"init", dir},
+				{"-C", dir, "remote", "add", "origin", repo},
+				{
+					"-C", dir, "sparse-checkout", "set", "--no-cone",
+					"/genesis/", "/funding/*.txt", "/tests/setup/*/*", "/tests/testing/*/*",
+				},
+				{"-C", dir, "fetch", "--depth=1", "--filter=blob:none", "origin", "main"},
+				{"-C", dir, "checkout", "FETCH_HEAD"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GitSource{cfg: tt.cfg}
+			assert.Equal(t, tt.want, gitArgs(s.cloneCommands(dir)))
+		})
+	}
+}
+
+func TestGitSource_UpdateCommands(t *testing.T) {
+	const dir = "/cache/repo"
+
+	full := &GitSource{cfg: &config.GitSourceV2{Repo: "r", Version: "main"}}
+	assert.Equal(t, [][]string{
+		{"-C", dir, "sparse-checkout", "disable"},
+		{"-C", dir, "fetch", "--depth=1", "origin", "main"},
+		{"-C", dir, "checkout", "FETCH_HEAD"},
+	}, gitArgs(full.updateCommands(dir)))
+
+	sparse := &GitSource{cfg: &config.GitSourceV2{Repo: "r", Version: "main", SparsePaths: []string{"tests"}}}
+	assert.Equal(t, [][]string{
+		{"-C", dir, "sparse-checkout", "set", "--no-cone", "/tests", "/genesis/"},
+		{"-C", dir, "fetch", "--depth=1", "--filter=blob:none", "origin", "main"},
+		{"-C", dir, "checkout", "FETCH_HEAD"},
+	}, gitArgs(sparse.updateCommands(dir)))
+}
+
+func TestGitCacheKey(t *testing.T) {
+	const repo = "https://github.com/example/tests.git"
+
+	assert.Equal(t, gitCacheKey(repo, "v1.0.0"), gitCacheKey(repo, "v1.0.0"))
+	assert.NotEqual(t, gitCacheKey(repo, "v1.0.0"), gitCacheKey(repo, "v1.1.0"))
+	assert.NotEqual(t, gitCacheKey(repo, "main"), gitCacheKey(repo+"x", "main"))
+}
+
+// initTestGitRepo creates a git repository with a single commit of files
+// and returns its file:// URL and the commit hash.
+func initTestGitRepo(t *testing.T, files map[string]string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{
+			"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com",
+		}, args...)...)
+
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+
+		return strings.TrimSpace(string(out))
+	}
+
+	git("init", "-b", "main")
+	git("add", ".")
+	git("commit", "-m", "tests")
+
+	return "file://" + dir, git("rev-parse", "HEAD")
+}
+
+func TestGitSource_PrepareReusesPinnedCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo, sha := initTestGitRepo(t, map[string]string{
+		"tests/a.txt":     "a",
+		"tests/b.txt":     "b",
+		"genesis/g.json":  "{}",
+		"other/large.bin": "large",
+	})
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	cacheDir := t.TempDir()
+	cfg := &config.GitSourceV2{
+		Repo:        repo,
+		Version:     sha,
+		Steps:       &config.StepsConfig{Test: []string{"tests/*.txt"}},
+		SparsePaths: []string{"genesis/"},
+	}
+
+	s := &GitSource{log: log, cfg: cfg, cacheDir: cacheDir}
+
+	prepared, err := s.Prepare(context.Background())
+	require.NoError(t, err)
+	require.Len(t, prepared.Tests, 2)
+
+	assert.FileExists(t, filepath.Join(s.basePath, "genesis", "g.json"))
+	assert.NoFileExists(t, filepath.Join(s.basePath, "other", "large.bin"))
+
+	// With the origin gone, a second prepare must reuse the cached checkout.
+	require.NoError(t, os.RemoveAll(strings.TrimPrefix(repo, "file://")))
+
+	s2 := &GitSource{log: log, cfg: cfg, cacheDir: cacheDir}
+
+	prepared, err = s2.Prepare(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, s.basePath, s2.basePath)
+	assert.Len(t, prepared.Tests, 2)
+
+	// Another version of the same repo gets its own checkout.
+	s3 := &GitSource{log: log, cfg: &config.GitSourceV2{Repo: repo, Version: "main"}, cacheDir: cacheDir}
+
+	_, err = s3.Prepare(context.Background())
+	require.Error(t, err)
+	assert.NoDirExists(t, filepath.Join(cacheDir, gitCacheKey(repo, "main")))
+}
+
+func TestGitSource_PreparePreRunStepsSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	setupRepo, setupSHA := initTestGitRepo(t, map[string]string{
+		"warmup/1-fund.txt": "fund",
+		"warmup/2-bump.txt": "bump",
+		"other/unused.txt":  "unused",
+	})
+
+	testsRepo, _ := initTestGitRepo(t, map[string]string{
+		"funding.txt": "local",
+		"tests/a.txt": "a",
+	})
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	s := &GitSource{
+		log:      log,
+		cacheDir: t.TempDir(),
+		cfg: &config.GitSourceV2{
+			Repo:        testsRepo,
+			Version:     "main",
+			PreRunSteps: []string{"funding.txt"},
+			Steps:       &config.StepsConfig{Test: []string{"tests/*.txt"}},
+			PreRunStepsSource: &config.GitSourceV2{
+				Repo:        setupRepo,
+				Version:     setupSHA,
+				PreRunSteps: []string{"warmup/*.txt"},
+			},
+		},
+	}
+
+	prepared, err := s.Prepare(context.Background())
+	require.NoError(t, err)
+	require.Len(t, prepared.Tests, 1)
+
+	// Shared pre-run steps run first, in glob order.
+	names := make([]string, 0, len(prepared.PreRunSteps))
+	for _, step := range prepared.PreRunSteps {
+		names = append(names, step.Name)
+	}
+
+	assert.Equal(t, []string{"warmup/1-fund.txt", "warmup/2-bump.txt", "funding.txt"}, names)
+
+	content, err := os.ReadFile(prepared.PreRunSteps[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, "fund", string(content))
+
+	info, err := s.GetSourceInfo()
+	require.NoError(t, err)
+	require.NotNil(t, info.Git.PreRunStepsSource)
+	assert.Equal(t, setupRepo, info.Git.PreRunStepsSource.Repo)
+	assert.Equal(t, setupSHA, info.Git.PreRunStepsSource.SHA)
+	assert.Equal(t, []string{"warmup/*.txt"}, info.Git.PreRunStepsSource.PreRunSteps)
+}
+
+func TestGitSource_PreRunStepsSourceNameCollision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	setupRepo, _ := initTestGitRepo(t, map[string]string{"funding.txt": "shared"})
+	testsRepo, _ := initTestGitRepo(t, map[string]string{"funding.txt": "local"})
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	s := &GitSource{
+		log:      log,
+		cacheDir: t.TempDir(),
+		cfg: &config.GitSourceV2{
+			Repo:        testsRepo,
+			Version:     "main",
+			PreRunSteps: []string{"funding.txt"},
+			PreRunStepsSource: &config.GitSourceV2{
+				Repo:        setupRepo,
+				Version:     "main",
+				PreRunSteps: []string{"funding.txt"},
+			},
+		},
+	}
+
+	_, err := s.Prepare(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `pre-run step "funding.txt" exists in both repositories`)
+}
+
+func TestGroupTestsByFilename_SortedRegardlessOfInputOrder(t *testing.T) {
+	var testFiles, setupFiles []*StepFile
+
+	for _, name := range []string{"b/002.txt", "a-b/001.txt", "a/010.txt", "a/002.txt", "B/001.txt", "a/b/001.txt"} {
+		testFiles = append(testFiles, &StepFile{Name: "tests/test/" + name, Path: "/base/tests/test/" + name})
+		setupFiles = append(setupFiles, &StepFile{Name: "tests/setup/" + name, Path: "/base/tests/setup/" + name})
+	}
+
+	want := []string{"B/001.txt", "a-b/001.txt", "a/002.txt", "a/010.txt", "a/b/001.txt", "b/002.txt"}
+
+	for i := range 20 {
+		rng := rand.New(rand.NewPCG(uint64(i), 0))
+		rng.Shuffle(len(testFiles), func(i, j int) { testFiles[i], testFiles[j] = testFiles[j], testFiles[i] })
+		rng.Shuffle(len(setupFiles), func(i, j int) { setupFiles[i], setupFiles[j] = setupFiles[j], setupFiles[i] })
+
+		tests, err := groupTestsByFilename(
+			setupFiles, []string{"tests/setup/"},
+			testFiles, []string{"tests/test/"},
+			nil, nil,
+		)
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(tests))
+		for _, test := range tests {
+			names = append(names, test.Name)
+			assert.Equal(t, "tests/setup/"+test.Name, test.Setup.Name)
+			assert.Equal(t, "tests/test/"+test.Name, test.Test.Name)
+		}
+
+		require.Equal(t, want, names, "shuffle %d", i)
+	}
+}
+
+func TestDiscoverTestsFromConfig_DuplicateTestNames(t *testing.T) {
+	base := t.TempDir()
+
+	for _, name := range []string{"a/test/x.txt", "b/test/x.txt", "a/setup/x.txt"} {
+		path := filepath.Join(base, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("payload"), 0644))
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	_, err := discoverTestsFromConfig(base, nil, &config.StepsConfig{
+		Setup: []string{"a/setup/*.txt"},
+		Test:  []string{"a/test/*.txt", "b/test/*.txt"},
+	}, "", false, log)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `test files "a/test/x.txt" and "b/test/x.txt" both produce test name "x.txt"`)
+}
+
+func TestLocalSource_GetGenesisPath(t *testing.T) {
+	base := t.TempDir()
+
+	for _, name := range []string{
+		"tests/test/x.txt",
+		"genesis/go-ethereum/genesis.json",
+		"genesis/nethermind/chainspec.json",
+	} {
+		path := filepath.Join(base, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0644))
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	src := NewSource(log, &config.SourceConfig{
+		Local: &config.LocalSourceV2{
+			BaseDir: base,
+			Steps:   &config.StepsConfig{Test: []string{"tests/test/*.txt"}},
+		},
+	}, "", "", "", download.RetryPolicy{}, false, false)
+
+	_, err := src.Prepare(t.Context())
+	require.NoError(t, err)
+
+	gp, ok := src.(GenesisProvider)
+	require.True(t, ok)
+
+	tests := []struct {
+		client string
+		want   string
+	}{
+		{client: "geth", want: filepath.Join(base, "genesis", "go-ethereum", "genesis.json")},
+		{client: "reth", want: filepath.Join(base, "genesis", "go-ethereum", "genesis.json")},
+		{client: "nethermind", want: filepath.Join(base, "genesis", "nethermind", "chainspec.json")},
+		// No besu genesis in the source.
+		{client: "besu", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.client, func(t *testing.T) {
+			assert.Equal(t, tt.want, gp.GetGenesisPath(tt.client))
+		})
+	}
+}
+
+func TestSortTests_TieBreaksOnStepPath(t *testing.T) {
+	tests := []*TestWithSteps{
+		{Name: "x.txt", Test: &StepFile{Name: "c/x.txt"}},
+		{Name: "x.txt", Test: &StepFile{Name: "a/x.txt"}},
+		{Name: "w.txt", Test: &StepFile{Name: "z/w.txt"}},
+		{Name: "x.txt", Test: &StepFile{Name: "b/x.txt"}},
+	}
+
+	sortTests(tests)
+
+	got := make([]string, 0, len(tests))
+	for _, test := range tests {
+		got = append(got, test.Test.Name)
+	}
+
+	assert.Equal(t, []string{"z/w.txt", "a/x.txt", "b/x.txt", "c/x.txt"}, got)
+}
+
+func TestDiscoverTestsFromConfig_RecordsTestOrder(t *testing.T) {
+	base := t.TempDir()
+
+	// Created in reverse so directory entries aren't already sorted on
+	// filesystems returning them in creation order.
+	for i := 9; i >= 0; i-- {
+		path := filepath.Join(base, "tests", fmt.Sprintf("%02d", i), "test.txt")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("payload"), 0644))
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	result, err := discoverTestsFromConfig(base, nil, &config.StepsConfig{Test: []string{"tests/*/*"}}, "", false, log)
+	require.NoError(t, err)
+	assert.Equal(t, TestOrderName, result.TestOrder)
+	require.Len(t, result.Tests, 10)
+
+	for i, test := range result.Tests {
+		assert.Equal(t, fmt.Sprintf("%02d/test.txt", i), test.Name)
+	}
+
+	resultsDir := t.TempDir()
+	info := &SuiteInfo{Hash: "abc", TestOrder: result.TestOrder}
+	_, err = CreateSuiteOutput(resultsDir, "abc", info, result, nil)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(resultsDir, "suites", "abc", "summary.json"))
+	require.NoError(t, err)
+
+	var summary SuiteInfo
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.Equal(t, TestOrderName, summary.TestOrder)
+	require.Len(t, summary.Tests, 10)
+	assert.Equal(t, "00/test.txt", summary.Tests[0].Name)
+	assert.Equal(t, "09/test.txt", summary.Tests[9].Name)
+}
+
+func TestTestWithSteps_MatchesFilter(t *testing.T) {
+	test := &TestWithSteps{
+		Name:  "add.txt",
+		Setup: &StepFile{Name: "setup/add.txt", Path: "/tests/bn128/setup/add.txt"},
+		Test:  &StepFile{Name: "test/add.txt", Path: "/tests/bn128/test/add.txt"},
+	}
+
+	assert.True(t, test.matchesFilter(""))
+	assert.True(t, test.matchesFilter("add"))
+	assert.True(t, test.matchesFilter("bn128"), "filters match step paths like at discovery")
+	assert.False(t, test.matchesFilter("mul"))
+}
+
+func TestSelectTests(t *testing.T) {
+	tests := []*TestWithSteps{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+
+	selected := SelectTests(tests, []string{"c", "a", "missing"})
+	require.Len(t, selected, 2)
+	assert.Equal(t, "a", selected[0].Name)
+	assert.Equal(t, "c", selected[1].Name)
+
+	none := SelectTests(tests, []string{"missing"})
+	assert.NotNil(t, none)
+	assert.Empty(t, none)
+
+	// The run result records test names cleaned.
+	unclean := []*TestWithSteps{{Name: "./dir//a.txt"}, {Name: "b.txt"}}
+	selected = SelectTests(unclean, []string{"dir/a.txt"})
+	require.Len(t, selected, 1)
+	assert.Equal(t, "./dir//a.txt", selected[0].Name)
+}
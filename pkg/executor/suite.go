@@ -23,6 +23,9 @@ type SuiteInfo struct {
 	Metadata    *config.MetadataConfig `json:"metadata,omitempty"`
 	PreRunSteps []SuiteFile            `json:"pre_run_steps,omitempty"`
 	Tests       []SuiteTest            `json:"tests"`
+	// TestOrder is the key tests are sorted by, e.g. "name". Tests are
+	// listed and executed in this order.
+	TestOrder string `json:"test_order,omitempty"`
 }
 
 // SuiteSource contains source information for the suite.
@@ -40,6 +43,8 @@ type GitSourceInfo struct {
 	SHA         string            `json:"sha"`
 	PreRunSteps []string          `json:"pre_run_steps,omitempty"`
 	Steps       *SourceStepsGlobs `json:"steps,omitempty"`
+	// PreRunStepsSource is the repository providing shared pre-run steps.
+	PreRunStepsSource *GitSourceInfo `json:"pre_run_steps_source,omitempty"`
 }
 
 // LocalSourceInfo contains local directory source information.
@@ -84,52 +89,58 @@ type SuiteTest struct {
 	OpcodeCount map[string]int `json:"opcode_count,omitempty"`
 }
 
-// ComputeSuiteHash computes a hash of all test file contents.
+// suiteHashBufferSize is the buffer size for streaming a step file through
+// the hasher.
+const suiteHashBufferSize = 64 * 1024
+
+// ComputeSuiteHash computes a hash of all test file contents. Step files
+// are streamed through the hasher in suite order, so memory use does not
+// grow with the suite size.
 func ComputeSuiteHash(prepared *PreparedSource) (string, error) {
+	steps := suiteHashSteps(prepared)
 	h := sha256.New()
+	buf := make([]byte, suiteHashBufferSize)
 
-	// Hash pre-run steps first.
-	for _, f := range prepared.PreRunSteps {
-		content, err := getStepContent(f)
-		if err != nil {
-			return "", fmt.Errorf("reading pre-run step %s: %w", f.Name, err)
+	for _, s := range steps {
+		if err := streamStepContent(h, s.step, buf); err != nil {
+			return "", fmt.Errorf("reading %s %s: %w", s.kind, s.step.Name, err)
 		}
+	}
+
+	// Use first 16 characters of the hash.
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
 
-		h.Write(content)
+// suiteHashStep is a step whose content is part of the suite hash.
+type suiteHashStep struct {
+	step *StepFile
+	kind string // Used in error messages, e.g. "setup file".
+}
+
+// suiteHashSteps returns the steps of prepared in hashing order: pre-run
+// steps first, then the setup, test and cleanup steps of each test.
+func suiteHashSteps(prepared *PreparedSource) []suiteHashStep {
+	steps := make([]suiteHashStep, 0, len(prepared.PreRunSteps)+3*len(prepared.Tests))
+
+	for _, f := range prepared.PreRunSteps {
+		steps = append(steps, suiteHashStep{step: f, kind: "pre-run step"})
 	}
 
-	// Hash all test step files.
 	for _, test := range prepared.Tests {
 		if test.Setup != nil {
-			content, err := getStepContent(test.Setup)
-			if err != nil {
-				return "", fmt.Errorf("reading setup file %s: %w", test.Setup.Name, err)
-			}
-
-			h.Write(content)
+			steps = append(steps, suiteHashStep{step: test.Setup, kind: "setup file"})
 		}
 
 		if test.Test != nil {
-			content, err := getStepContent(test.Test)
-			if err != nil {
-				return "", fmt.Errorf("reading test file %s: %w", test.Test.Name, err)
-			}
-
-			h.Write(content)
+			steps = append(steps, suiteHashStep{step: test.Test, kind: "test file"})
 		}
 
 		if test.Cleanup != nil {
-			content, err := getStepContent(test.Cleanup)
-			if err != nil {
-				return "", fmt.Errorf("reading cleanup file %s: %w", test.Cleanup.Name, err)
-			}
-
-			h.Write(content)
+			steps = append(steps, suiteHashStep{step: test.Cleanup, kind: "cleanup file"})
 		}
 	}
 
-	// Use first 16 characters of the hash.
-	return hex.EncodeToString(h.Sum(nil))[:16], nil
+	return steps
 }
 
 // getStepContent returns the content of a step, either from provider or file.
@@ -141,121 +152,226 @@ func getStepContent(step *StepFile) ([]byte, error) {
 	return os.ReadFile(step.Path)
 }
 
-// CreateSuiteOutput creates the suite directory structure with copied files and summary.
+// streamStepContent copies the content of a step to w, streaming files
+// through buf instead of reading them into memory.
+func streamStepContent(w io.Writer, step *StepFile, buf []byte) error {
+	if step.Provider != nil {
+		_, err := w.Write(step.Provider.Content())
+
+		return err
+	}
+
+	f, err := os.Open(step.Path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	// Hide os.File's WriterTo so the copy uses buf instead of allocating.
+	_, err = io.CopyBuffer(w, struct{ io.Reader }{f}, buf)
+
+	return err
+}
+
+// CreateSuiteOutput creates the suite directory structure with copied files
+// and summary. Suite directories are keyed by hash and shared by all runs of
+// the suite: when a complete directory for hash already exists, its files are
+// reused instead of copied again and only summary.json is updated. It reports
+// whether an existing suite directory was reused.
 func CreateSuiteOutput(
 	resultsDir, hash string,
 	info *SuiteInfo,
 	prepared *PreparedSource,
 	owner *fsutil.OwnerConfig,
-) error {
+) (bool, error) {
 	suiteDir := filepath.Join(resultsDir, "suites", hash)
+	summaryPath := filepath.Join(suiteDir, "summary.json")
 
-	suiteExists := false
+	reused := suiteOutputComplete(suiteDir)
+	if !reused {
+		built, err := buildSuiteOutput(suiteDir, info, prepared, owner)
+		if err != nil {
+			return false, err
+		}
 
-	// Check if suite already exists.
-	if _, err := os.Stat(suiteDir); err == nil {
-		suiteExists = true
+		// Another run of the same suite finished building it first.
+		reused = !built
 	}
 
-	if !suiteExists {
-		// Create suite directory.
-		if err := fsutil.MkdirAll(suiteDir, 0755, owner); err != nil {
-			return fmt.Errorf("creating suite dir: %w", err)
-		}
+	// If the suite already existed, read the existing summary to preserve
+	// test/step file references, then overlay the new info fields.
+	if reused {
+		existingData, readErr := os.ReadFile(summaryPath)
+		if readErr == nil {
+			var existing SuiteInfo
+			if jsonErr := json.Unmarshal(existingData, &existing); jsonErr == nil {
+				info.PreRunSteps = existing.PreRunSteps
 
-		// Copy pre-run steps.
-		// Structure: <suite_dir>/<step_name>/pre_run.request (same pattern as tests).
-		for _, f := range prepared.PreRunSteps {
-			suiteFile, err := copyPreRunStepFile(suiteDir, f, owner)
-			if err != nil {
-				return fmt.Errorf("copying pre-run step: %w", err)
-			}
+				// Merge opcode data from prepared tests into existing entries.
+				mergeOpcodeData(existing.Tests, prepared)
 
-			info.PreRunSteps = append(info.PreRunSteps, *suiteFile)
+				info.Tests = existing.Tests
+			}
 		}
+	}
 
-		// Copy test files and build SuiteTest entries.
-		// New structure: <suite_dir>/<test_name>/{setup,test,cleanup}.request
-		for _, test := range prepared.Tests {
-			suiteTest := SuiteTest{
-				Name:        test.Name,
-				GenesisHash: test.GenesisHash,
-			}
+	// Always write summary.json — metadata (e.g. labels) can change between
+	// runs without affecting the suite hash, so we update it every time.
+	if err := writeSuiteSummary(summaryPath, info, owner); err != nil {
+		return false, err
+	}
 
-			if test.EESTInfo != nil {
-				suiteTest.EEST = &SuiteTestEEST{Info: test.EESTInfo}
-				suiteTest.OpcodeCount = test.EESTInfo.OpcodeCount
-			}
+	return reused, nil
+}
 
-			// External opcode data takes precedence over EEST-derived opcodes.
-			if test.OpcodeCount != nil {
-				suiteTest.OpcodeCount = test.OpcodeCount
-			}
+// suiteOutputComplete reports whether suiteDir holds a complete suite
+// output. summary.json is written last, so a directory without it was left
+// behind by an interrupted run.
+func suiteOutputComplete(suiteDir string) bool {
+	_, err := os.Stat(filepath.Join(suiteDir, "summary.json"))
 
-			// Create test directory.
-			testDir := filepath.Join(suiteDir, test.Name)
-			if err := fsutil.MkdirAll(testDir, 0755, owner); err != nil {
-				return fmt.Errorf("creating test dir for %s: %w", test.Name, err)
-			}
+	return err == nil
+}
+
+// buildSuiteOutput copies the suite files into a temporary directory next
+// to suiteDir and renames it into place once complete, so that concurrent or
+// interrupted runs never expose a partial suite. It returns false when a
+// complete suite directory appeared in the meantime and was kept instead.
+func buildSuiteOutput(
+	suiteDir string,
+	info *SuiteInfo,
+	prepared *PreparedSource,
+	owner *fsutil.OwnerConfig,
+) (bool, error) {
+	suitesDir := filepath.Dir(suiteDir)
+	if err := fsutil.MkdirAll(suitesDir, 0755, owner); err != nil {
+		return false, fmt.Errorf("creating suites dir: %w", err)
+	}
 
-			if test.Setup != nil {
-				suiteFile, err := copyTestStepFile(testDir, "setup", test.Setup, owner)
-				if err != nil {
-					return fmt.Errorf("copying setup file: %w", err)
-				}
+	tmpDir, err := os.MkdirTemp(suitesDir, "."+filepath.Base(suiteDir)+".tmp-")
+	if err != nil {
+		return false, fmt.Errorf("creating temporary suite dir: %w", err)
+	}
 
-				suiteTest.Setup = suiteFile
-			}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
 
-			if test.Test != nil {
-				suiteFile, err := copyTestStepFile(testDir, "test", test.Test, owner)
-				if err != nil {
-					return fmt.Errorf("copying test file: %w", err)
-				}
+	if err := fsutil.ChmodDir(tmpDir, 0755, owner); err != nil {
+		return false, fmt.Errorf("setting suite dir permissions: %w", err)
+	}
 
-				suiteTest.Test = suiteFile
-			}
+	fsutil.Chown(tmpDir, owner)
 
-			if test.Cleanup != nil {
-				suiteFile, err := copyTestStepFile(testDir, "cleanup", test.Cleanup, owner)
-				if err != nil {
-					return fmt.Errorf("copying cleanup file: %w", err)
-				}
+	if err := populateSuiteDir(tmpDir, info, prepared, owner); err != nil {
+		return false, err
+	}
 
-				suiteTest.Cleanup = suiteFile
-			}
+	if err := writeSuiteSummary(filepath.Join(tmpDir, "summary.json"), info, owner); err != nil {
+		return false, err
+	}
 
-			info.Tests = append(info.Tests, suiteTest)
+	// Drop what an interrupted run left behind before moving the new
+	// suite into place.
+	if !suiteOutputComplete(suiteDir) {
+		if err := os.RemoveAll(suiteDir); err != nil {
+			return false, fmt.Errorf("removing incomplete suite dir: %w", err)
 		}
 	}
 
-	// Always write summary.json — metadata (e.g. labels) can change between
-	// runs without affecting the suite hash, so we update it every time.
-	summaryPath := filepath.Join(suiteDir, "summary.json")
+	if err := os.Rename(tmpDir, suiteDir); err != nil {
+		if suiteOutputComplete(suiteDir) {
+			return false, nil
+		}
 
-	// If the suite already existed, read the existing summary to preserve
-	// test/step file references, then overlay the new info fields.
-	if suiteExists {
-		existingData, readErr := os.ReadFile(summaryPath)
-		if readErr == nil {
-			var existing SuiteInfo
-			if jsonErr := json.Unmarshal(existingData, &existing); jsonErr == nil {
-				info.PreRunSteps = existing.PreRunSteps
+		return false, fmt.Errorf("moving suite dir into place: %w", err)
+	}
 
-				// Merge opcode data from prepared tests into existing entries.
-				mergeOpcodeData(existing.Tests, prepared)
+	return true, nil
+}
 
-				info.Tests = existing.Tests
+// populateSuiteDir copies the pre-run steps and tests of prepared into
+// suiteDir and records them in info.
+func populateSuiteDir(
+	suiteDir string,
+	info *SuiteInfo,
+	prepared *PreparedSource,
+	owner *fsutil.OwnerConfig,
+) error {
+	// Copy pre-run steps.
+	// Structure: <suite_dir>/<step_name>/pre_run.request (same pattern as tests).
+	for _, f := range prepared.PreRunSteps {
+		suiteFile, err := copyPreRunStepFile(suiteDir, f, owner)
+		if err != nil {
+			return fmt.Errorf("copying pre-run step: %w", err)
+		}
+
+		info.PreRunSteps = append(info.PreRunSteps, *suiteFile)
+	}
+
+	// Copy test files and build SuiteTest entries.
+	// New structure: <suite_dir>/<test_name>/{setup,test,cleanup}.request
+	for _, test := range prepared.Tests {
+		suiteTest := SuiteTest{
+			Name:        test.Name,
+			GenesisHash: test.GenesisHash,
+		}
+
+		if test.EESTInfo != nil {
+			suiteTest.EEST = &SuiteTestEEST{Info: test.EESTInfo}
+			suiteTest.OpcodeCount = test.EESTInfo.OpcodeCount
+		}
+
+		// External opcode data takes precedence over EEST-derived opcodes.
+		if test.OpcodeCount != nil {
+			suiteTest.OpcodeCount = test.OpcodeCount
+		}
+
+		// Create test directory.
+		testDir := filepath.Join(suiteDir, testDirName(test.Name))
+		if err := fsutil.MkdirAll(testDir, 0755, owner); err != nil {
+			return fmt.Errorf("creating test dir for %s: %w", test.Name, err)
+		}
+
+		if test.Setup != nil {
+			suiteFile, err := copyTestStepFile(testDir, "setup", test.Setup, owner)
+			if err != nil {
+				return fmt.Errorf("copying setup file: %w", err)
+			}
+
+			suiteTest.Setup = suiteFile
+		}
+
+		if test.Test != nil {
+			suiteFile, err := copyTestStepFile(testDir, "test", test.Test, owner)
+			if err != nil {
+				return fmt.Errorf("copying test file: %w", err)
+			}
+
+			suiteTest.Test = suiteFile
+		}
+
+		if test.Cleanup != nil {
+			suiteFile, err := copyTestStepFile(testDir, "cleanup", test.Cleanup, owner)
+			if err != nil {
+				return fmt.Errorf("copying cleanup file: %w", err)
 			}
+
+			suiteTest.Cleanup = suiteFile
 		}
+
+		info.Tests = append(info.Tests, suiteTest)
 	}
 
+	return nil
+}
+
+// writeSuiteSummary writes info as the summary.json of a suite.
+func writeSuiteSummary(path string, info *SuiteInfo, owner *fsutil.OwnerConfig) error {
 	summaryData, err := json.MarshalIndent(info, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling summary: %w", err)
 	}
 
-	if err := fsutil.WriteFile(summaryPath, summaryData, 0644, owner); err != nil {
+	if err := fsutil.WriteFile(path, summaryData, 0644, owner); err != nil {
 		return fmt.Errorf("writing summary: %w", err)
 	}
 
@@ -0,0 +1,182 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// suiteHashCacheDir is the subdirectory of the cache dir holding cached
+// suite hashes.
+const suiteHashCacheDir = "suite-hashes"
+
+// suiteHashCacheEntry is a cached suite hash together with the fingerprint
+// of the steps it was computed from.
+type suiteHashCacheEntry struct {
+	Hash  string               `json:"hash"`
+	Steps []suiteHashStepStamp `json:"steps"`
+}
+
+// suiteHashStepStamp identifies the content of a step without reading it.
+// File steps are stamped with their size and mtime; in-memory steps, which
+// are derived from the pinned source, with their content length.
+type suiteHashStepStamp struct {
+	Name      string `json:"name"`
+	Path      string `json:"path,omitempty"`
+	Size      int64  `json:"size"`
+	ModTimeNs int64  `json:"mod_time_ns,omitempty"`
+}
+
+// suiteHashCacheKey returns the cache key for the suite hash of a source, or
+// an empty string if the source is not pinned to a fixed version and its
+// hash must not be cached.
+func suiteHashCacheKey(info *SuiteSource, filter string) string {
+	if info == nil || !isPinnedSource(info) {
+		return ""
+	}
+
+	data, err := json.Marshal(struct {
+		Source *SuiteSource `json:"source"`
+		Filter string       `json:"filter"`
+	}{Source: info, Filter: filter})
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// isPinnedSource reports whether a source resolves to fixed content: a git
+// commit, or an EEST release or artifact run.
+func isPinnedSource(info *SuiteSource) bool {
+	switch {
+	case info.Git != nil:
+		return info.Git.SHA != ""
+	case info.EEST != nil:
+		if info.EEST.LocalFixturesDir != "" || info.EEST.LocalFixturesTarball != "" {
+			return false
+		}
+
+		return info.EEST.GitHubRelease != "" || info.EEST.FixturesArtifactRunID != ""
+	default:
+		return false
+	}
+}
+
+// suiteHashStamps returns the stamps of all steps that are part of the
+// suite hash, in hashing order.
+func suiteHashStamps(prepared *PreparedSource) ([]suiteHashStepStamp, error) {
+	steps := suiteHashSteps(prepared)
+	stamps := make([]suiteHashStepStamp, 0, len(steps))
+
+	for _, s := range steps {
+		stamp := suiteHashStepStamp{Name: s.step.Name}
+
+		if s.step.Provider != nil {
+			stamp.Size = int64(len(s.step.Provider.Content()))
+		} else {
+			fi, err := os.Stat(s.step.Path)
+			if err != nil {
+				return nil, fmt.Errorf("stat %s %s: %w", s.kind, s.step.Name, err)
+			}
+
+			stamp.Path = s.step.Path
+			stamp.Size = fi.Size()
+			stamp.ModTimeNs = fi.ModTime().UnixNano()
+		}
+
+		stamps = append(stamps, stamp)
+	}
+
+	return stamps, nil
+}
+
+// computeSuiteHash returns the suite hash of the prepared source, reusing
+// the hash cached for a pinned source when none of its steps changed.
+func (e *executor) computeSuiteHash(info *SuiteSource) (string, error) {
+	key := suiteHashCacheKey(info, e.cfg.Filter)
+	if e.cfg.CacheDir == "" || key == "" {
+		return ComputeSuiteHash(e.prepared)
+	}
+
+	stamps, err := suiteHashStamps(e.prepared)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(e.cfg.CacheDir, suiteHashCacheDir, key+".json")
+
+	if entry, err := readSuiteHashCache(path); err == nil &&
+		entry.Hash != "" && slices.Equal(entry.Steps, stamps) {
+		e.log.WithField("hash", entry.Hash).Debug("Using cached suite hash")
+
+		return entry.Hash, nil
+	}
+
+	hash, err := ComputeSuiteHash(e.prepared)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeSuiteHashCache(path, &suiteHashCacheEntry{Hash: hash, Steps: stamps}); err != nil {
+		e.log.WithError(err).Warn("Failed to cache suite hash")
+	}
+
+	return hash, nil
+}
+
+// readSuiteHashCache reads a cached suite hash entry.
+func readSuiteHashCache(path string) (*suiteHashCacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry suiteHashCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// writeSuiteHashCache atomically writes a cached suite hash entry.
+func writeSuiteHashCache(path string, entry *suiteHashCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	return nil
+}
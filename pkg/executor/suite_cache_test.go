@@ -0,0 +1,149 @@
+package executor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pinnedGitSource = &SuiteSource{Git: &GitSourceInfo{Repo: "https://example.com/tests", Version: "main", SHA: "abc123"}}
+
+// newSuiteHashExecutor returns an executor with a suite hash cache dir.
+func newSuiteHashExecutor(t *testing.T, prepared *PreparedSource) *executor {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	e := NewExecutor(log, &Config{CacheDir: t.TempDir()}).(*executor)
+	e.prepared = prepared
+
+	return e
+}
+
+// poisonSuiteHashCache replaces the cached hash for key, so a cache hit is
+// distinguishable from a recomputation.
+func poisonSuiteHashCache(t *testing.T, e *executor, key string) {
+	t.Helper()
+
+	path := filepath.Join(e.cfg.CacheDir, suiteHashCacheDir, key+".json")
+
+	entry, err := readSuiteHashCache(path)
+	require.NoError(t, err)
+
+	entry.Hash = "cached"
+	require.NoError(t, writeSuiteHashCache(path, entry))
+}
+
+func TestComputeSuiteHashCached_Hit(t *testing.T) {
+	prepared := testPreparedSource(t)
+	e := newSuiteHashExecutor(t, prepared)
+
+	want, err := ComputeSuiteHash(prepared)
+	require.NoError(t, err)
+
+	got, err := e.computeSuiteHash(pinnedGitSource)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	poisonSuiteHashCache(t, e, suiteHashCacheKey(pinnedGitSource, ""))
+
+	got, err = e.computeSuiteHash(pinnedGitSource)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", got)
+}
+
+func TestComputeSuiteHashCached_MissOnChangedFile(t *testing.T) {
+	prepared := testPreparedSource(t)
+	e := newSuiteHashExecutor(t, prepared)
+
+	_, err := e.computeSuiteHash(pinnedGitSource)
+	require.NoError(t, err)
+
+	poisonSuiteHashCache(t, e, suiteHashCacheKey(pinnedGitSource, ""))
+
+	// Same size, different content and mtime.
+	path := prepared.Tests[5].Test.Path
+	require.NoError(t, os.WriteFile(path, []byte("test X\n"), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)))
+
+	want, err := ComputeSuiteHash(prepared)
+	require.NoError(t, err)
+
+	got, err := e.computeSuiteHash(pinnedGitSource)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// The recomputed hash replaced the stale entry.
+	entry, err := readSuiteHashCache(filepath.Join(
+		e.cfg.CacheDir, suiteHashCacheDir, suiteHashCacheKey(pinnedGitSource, "")+".json"))
+	require.NoError(t, err)
+	assert.Equal(t, want, entry.Hash)
+}
+
+func TestComputeSuiteHashCached_MissOnChangedSteps(t *testing.T) {
+	prepared := testPreparedSource(t)
+	e := newSuiteHashExecutor(t, prepared)
+
+	_, err := e.computeSuiteHash(pinnedGitSource)
+	require.NoError(t, err)
+
+	poisonSuiteHashCache(t, e, suiteHashCacheKey(pinnedGitSource, ""))
+
+	prepared.Tests = prepared.Tests[1:]
+
+	want, err := ComputeSuiteHash(prepared)
+	require.NoError(t, err)
+
+	got, err := e.computeSuiteHash(pinnedGitSource)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestComputeSuiteHashCached_UnpinnedSource(t *testing.T) {
+	prepared := testPreparedSource(t)
+	e := newSuiteHashExecutor(t, prepared)
+
+	local := &SuiteSource{Local: &LocalSourceInfo{BaseDir: "/tests"}}
+
+	_, err := e.computeSuiteHash(local)
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(e.cfg.CacheDir, suiteHashCacheDir))
+	assert.True(t, os.IsNotExist(err), "unpinned sources must not be cached")
+}
+
+func TestSuiteHashCacheKey(t *testing.T) {
+	otherSHA := &SuiteSource{Git: &GitSourceInfo{Repo: "https://example.com/tests", Version: "main", SHA: "def456"}}
+
+	key := suiteHashCacheKey(pinnedGitSource, "")
+	require.NotEmpty(t, key)
+	assert.Equal(t, key, suiteHashCacheKey(pinnedGitSource, ""))
+	assert.NotEqual(t, key, suiteHashCacheKey(pinnedGitSource, "bn128"))
+	assert.NotEqual(t, key, suiteHashCacheKey(otherSHA, ""))
+
+	tests := []struct {
+		name   string
+		source *SuiteSource
+		pinned bool
+	}{
+		{name: "git without sha", source: &SuiteSource{Git: &GitSourceInfo{Repo: "r", Version: "main"}}},
+		{name: "local", source: &SuiteSource{Local: &LocalSourceInfo{BaseDir: "/tests"}}},
+		{name: "archive", source: &SuiteSource{Archive: &ArchiveSourceInfo{File: "tests.tar.gz"}}},
+		{name: "eest release", source: &SuiteSource{EEST: &EESTSourceInfo{GitHubRelease: "v5.0.0"}}, pinned: true},
+		{name: "eest artifact", source: &SuiteSource{EEST: &EESTSourceInfo{FixturesArtifactRunID: "123"}}, pinned: true},
+		{name: "eest local", source: &SuiteSource{EEST: &EESTSourceInfo{LocalFixturesDir: "/fixtures"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.pinned, suiteHashCacheKey(tt.source, "") != "")
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -60,7 +59,7 @@ type RunInfo struct {
 func GenerateAllSuiteStats(resultsDir string) (map[string]*SuiteStats, error) {
 	runsDir := filepath.Join(resultsDir, "runs")
 
-	entries, err := os.ReadDir(runsDir)
+	runIDs, err := ListRunDirs(runsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return make(map[string]*SuiteStats), nil
@@ -72,13 +71,8 @@ func GenerateAllSuiteStats(resultsDir string) (map[string]*SuiteStats, error) {
 	// Group runs by suite hash.
 	suiteRuns := make(map[string][]RunInfo)
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		runID := entry.Name()
-		runDir := filepath.Join(runsDir, runID)
+	for _, runID := range runIDs {
+		runDir := filepath.Join(runsDir, filepath.FromSlash(runID))
 
 		// Read config.json to get suite_hash and client.
 		configPath := filepath.Join(runDir, "config.json")
@@ -128,7 +122,7 @@ func buildSuiteStats(runsDir string, runs []RunInfo) (*SuiteStats, error) {
 	stats := make(SuiteStats)
 
 	for _, run := range runs {
-		runDir := filepath.Join(runsDir, run.RunID)
+		runDir := filepath.Join(runsDir, filepath.FromSlash(run.RunID))
 		resultPath := filepath.Join(runDir, "result.json")
 
 		resultData, err := os.ReadFile(resultPath)
@@ -240,7 +234,7 @@ func GenerateAllSuiteStatsFromS3(
 		runsPrefix += "/"
 	}
 
-	prefixes, err := reader.ListPrefixes(ctx, runsPrefix)
+	prefixes, err := ListRunPrefixes(ctx, reader, runsPrefix)
 	if err != nil {
 		return nil, fmt.Errorf("listing run prefixes: %w", err)
 	}
@@ -250,7 +244,7 @@ func GenerateAllSuiteStatsFromS3(
 	runPrefixes := make(map[string]string) // runID → prefix
 
 	for _, prefix := range prefixes {
-		runID := path.Base(strings.TrimRight(prefix, "/"))
+		runID := runIDFromPrefix(runsPrefix, prefix)
 
 		configData, err := reader.GetObject(ctx, prefix+"config.json")
 		if err != nil {
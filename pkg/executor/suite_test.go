@@ -0,0 +1,189 @@
+package executor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeStepFile writes a step file and returns it.
+func writeStepFile(t testing.TB, dir, name string, content []byte) *StepFile {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, content, 0o644))
+
+	return &StepFile{Path: path, Name: name}
+}
+
+// referenceSuiteHash is the original implementation: the SHA-256 of all
+// step contents concatenated in suite order.
+func referenceSuiteHash(t testing.TB, prepared *PreparedSource) string {
+	t.Helper()
+
+	var all bytes.Buffer
+
+	for _, s := range suiteHashSteps(prepared) {
+		content, err := getStepContent(s.step)
+		require.NoError(t, err)
+
+		all.Write(content)
+	}
+
+	sum := sha256.Sum256(all.Bytes())
+
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func testPreparedSource(t *testing.T) *PreparedSource {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	// Larger than the streaming buffer, so files are hashed in chunks.
+	large := bytes.Repeat([]byte("0123456789abcdef"), suiteHashBufferSize/8)
+
+	prepared := &PreparedSource{
+		PreRunSteps: []*StepFile{writeStepFile(t, dir, "warmup.txt", []byte("warmup\n"))},
+	}
+
+	for i := range 20 {
+		test := &TestWithSteps{
+			Name: fmt.Sprintf("test_%02d.txt", i),
+			Test: writeStepFile(t, dir, fmt.Sprintf("test_%02d.txt", i), fmt.Appendf(nil, "test %d\n", i)),
+		}
+
+		if i%3 == 0 {
+			test.Setup = writeStepFile(t, dir, fmt.Sprintf("setup_%02d.txt", i), large)
+		}
+
+		if i%4 == 0 {
+			test.Cleanup = &StepFile{Name: "cleanup", Provider: &linesProvider{lines: []string{"a", "b"}}}
+		}
+
+		prepared.Tests = append(prepared.Tests, test)
+	}
+
+	return prepared
+}
+
+func TestComputeSuiteHash_MatchesReference(t *testing.T) {
+	prepared := testPreparedSource(t)
+	want := referenceSuiteHash(t, prepared)
+
+	got, err := ComputeSuiteHash(prepared)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestComputeSuiteHash_Stable(t *testing.T) {
+	dir := t.TempDir()
+
+	prepared := &PreparedSource{
+		Tests: []*TestWithSteps{
+			{Name: "a.txt", Test: writeStepFile(t, dir, "a.txt", []byte("hello "))},
+			{Name: "b.txt", Test: &StepFile{Name: "b.txt", Provider: &linesProvider{lines: []string{"world"}}}},
+		},
+	}
+
+	// sha256("hello world"), truncated.
+	got, err := ComputeSuiteHash(prepared)
+	require.NoError(t, err)
+	assert.Equal(t, "b94d27b9934d3e08", got)
+}
+
+func TestComputeSuiteHash_MissingFile(t *testing.T) {
+	prepared := testPreparedSource(t)
+	prepared.Tests[7].Test.Path = filepath.Join(t.TempDir(), "missing.txt")
+
+	_, err := ComputeSuiteHash(prepared)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading test file test_07.txt")
+}
+
+func BenchmarkComputeSuiteHash(b *testing.B) {
+	dir := b.TempDir()
+	content := bytes.Repeat([]byte(`{"jsonrpc":"2.0","method":"engine_newPayloadV4","params":[],"id":1}`+"\n"), 4096)
+
+	prepared := &PreparedSource{}
+	for i := range 200 {
+		name := fmt.Sprintf("test_%03d.txt", i)
+		prepared.Tests = append(prepared.Tests, &TestWithSteps{Name: name, Test: writeStepFile(b, dir, name, content)})
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content) * len(prepared.Tests)))
+
+	for b.Loop() {
+		if _, err := ComputeSuiteHash(prepared); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCreateSuiteOutput_ReusesExistingSuite(t *testing.T) {
+	resultsDir := t.TempDir()
+	prepared := testPreparedSource(t)
+	suiteDir := filepath.Join(resultsDir, "suites", "abc")
+
+	reused, err := CreateSuiteOutput(resultsDir, "abc", &SuiteInfo{Hash: "abc"}, prepared, nil)
+	require.NoError(t, err)
+	assert.False(t, reused)
+
+	setupPath := filepath.Join(suiteDir, "test_00.txt", "setup.request")
+	first, err := os.Stat(setupPath)
+	require.NoError(t, err)
+
+	// The second run of the same suite reuses the files of the first, even
+	// once the source files are gone, and only refreshes the summary.
+	require.NoError(t, os.Remove(prepared.Tests[0].Setup.Path))
+
+	info := &SuiteInfo{Hash: "abc", Metadata: &config.MetadataConfig{Labels: map[string]string{"name": "second"}}}
+	reused, err = CreateSuiteOutput(resultsDir, "abc", info, prepared, nil)
+	require.NoError(t, err)
+	assert.True(t, reused)
+
+	second, err := os.Stat(setupPath)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(first, second), "suite files must not be copied again")
+
+	require.Len(t, info.Tests, 20)
+	require.Len(t, info.PreRunSteps, 1)
+
+	data, err := os.ReadFile(filepath.Join(suiteDir, "summary.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"second"`)
+
+	// No temporary directories are left next to the suite.
+	entries, err := os.ReadDir(filepath.Join(resultsDir, "suites"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "abc", entries[0].Name())
+}
+
+func TestCreateSuiteOutput_RebuildsIncompleteSuite(t *testing.T) {
+	resultsDir := t.TempDir()
+	prepared := testPreparedSource(t)
+	suiteDir := filepath.Join(resultsDir, "suites", "abc")
+
+	// An interrupted run left a suite directory without summary.json.
+	require.NoError(t, os.MkdirAll(filepath.Join(suiteDir, "partial"), 0o755))
+
+	info := &SuiteInfo{Hash: "abc"}
+	reused, err := CreateSuiteOutput(resultsDir, "abc", info, prepared, nil)
+	require.NoError(t, err)
+	assert.False(t, reused)
+	require.Len(t, info.Tests, 20)
+
+	assert.NoDirExists(t, filepath.Join(suiteDir, "partial"))
+	assert.FileExists(t, filepath.Join(suiteDir, "summary.json"))
+	assert.FileExists(t, filepath.Join(suiteDir, "test_19.txt", "test.request"))
+}
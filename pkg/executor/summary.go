@@ -0,0 +1,172 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// RunSummary is a one-line summary of a single finished run, printed at
+// the end of a benchmark.
+type RunSummary struct {
+	RunID         string `json:"run_id"`
+	InstanceID    string `json:"instance_id"`
+	Client        string `json:"client"`
+	ClientVersion string `json:"client_version,omitempty"`
+	Status        string `json:"status,omitempty"`
+	TestsPassed   int    `json:"tests_passed"`
+	TestsFailed   int    `json:"tests_failed"`
+	DurationNs    int64  `json:"duration_ns"`
+	// NewPayloadP95Ns is the 95th percentile of the per-test
+	// engine_newPayload latency in the test step.
+	NewPayloadP95Ns int64 `json:"new_payload_p95_ns,omitempty"`
+}
+
+// summaryRunConfig holds the config.json fields needed for a run summary.
+type summaryRunConfig struct {
+	Timestamp    int64  `json:"timestamp"`
+	TimestampEnd int64  `json:"timestamp_end,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Instance     struct {
+		ID            string `json:"id"`
+		Client        string `json:"client"`
+		ClientVersion string `json:"client_version,omitempty"`
+	} `json:"instance"`
+}
+
+// BuildRunSummaries reads the config.json and result.json files of the
+// given run directories and returns one summary per run, in order.
+func BuildRunSummaries(runDirs []string) ([]*RunSummary, error) {
+	summaries := make([]*RunSummary, 0, len(runDirs))
+
+	for _, runDir := range runDirs {
+		summary, err := buildRunSummary(runDir)
+		if err != nil {
+			return nil, fmt.Errorf("summarizing %s: %w", filepath.Base(runDir), err)
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// buildRunSummary creates a summary from a single run directory.
+func buildRunSummary(runDir string) (*RunSummary, error) {
+	//nolint:gosec // config.json is a trusted local file written by the tool.
+	configData, err := os.ReadFile(filepath.Join(runDir, "config.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading config.json: %w", err)
+	}
+
+	var runConfig summaryRunConfig
+	if err := json.Unmarshal(configData, &runConfig); err != nil {
+		return nil, fmt.Errorf("parsing config.json: %w", err)
+	}
+
+	//nolint:gosec // result.json is a trusted local file written by the tool.
+	resultData, _ := os.ReadFile(filepath.Join(runDir, "result.json"))
+
+	// Reuse the index entry logic so pass/fail counts match index.json.
+	entry, err := BuildIndexEntryFromData(filepath.Base(runDir), configData, resultData)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &RunSummary{
+		RunID:         entry.RunID,
+		InstanceID:    runConfig.Instance.ID,
+		Client:        runConfig.Instance.Client,
+		ClientVersion: runConfig.Instance.ClientVersion,
+		Status:        runConfig.Status,
+		TestsPassed:   entry.Tests.TestsPassed,
+		TestsFailed:   entry.Tests.TestsFailed,
+	}
+
+	if runConfig.TimestampEnd > runConfig.Timestamp {
+		summary.DurationNs = int64(time.Duration(runConfig.TimestampEnd-runConfig.Timestamp) * time.Second)
+	}
+
+	if len(resultData) > 0 {
+		var runResult RunResult
+		if err := json.Unmarshal(resultData, &runResult); err == nil {
+			summary.NewPayloadP95Ns = newPayloadP95(&runResult)
+		}
+	}
+
+	return summary, nil
+}
+
+// newPayloadP95 returns the 95th percentile of the per-test mean
+// engine_newPayload latency in the test step, or 0 without any calls.
+func newPayloadP95(result *RunResult) int64 {
+	latencies := make([]int64, 0, len(result.Tests))
+
+	for _, test := range result.Tests {
+		if test.Steps == nil || test.Steps.Test == nil ||
+			test.Steps.Test.Aggregated == nil || test.Steps.Test.Aggregated.MethodStats == nil {
+			continue
+		}
+
+		for method, stats := range test.Steps.Test.Aggregated.MethodStats.Times {
+			if !strings.HasPrefix(method, "engine_newPayload") || stats == nil {
+				continue
+			}
+
+			// Single-call stats only carry the last value.
+			if stats.Count == 1 {
+				latencies = append(latencies, stats.Last)
+			} else {
+				latencies = append(latencies, stats.Mean)
+			}
+		}
+	}
+
+	slices.Sort(latencies)
+
+	return percentile(latencies, 95)
+}
+
+// WriteRunSummaryTable writes the summaries as an aligned text table.
+func WriteRunSummaryTable(w io.Writer, summaries []*RunSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "INSTANCE\tCLIENT\tVERSION\tSTATUS\tPASSED\tFAILED\tDURATION\tP95 NEWPAYLOAD")
+
+	for _, s := range summaries {
+		version := s.ClientVersion
+		if version == "" {
+			version = "-"
+		}
+
+		status := s.Status
+		if status == "" {
+			status = "-"
+		}
+
+		p95 := "-"
+		if s.NewPayloadP95Ns > 0 {
+			p95 = time.Duration(s.NewPayloadP95Ns).Round(time.Microsecond).String()
+		}
+
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			s.InstanceID, s.Client, version, status,
+			s.TestsPassed, s.TestsFailed, formatDurationNs(s.DurationNs), p95)
+	}
+
+	return tw.Flush()
+}
+
+// WriteRunSummaryJSON writes the summaries as an indented JSON array.
+func WriteRunSummaryJSON(w io.Writer, summaries []*RunSummary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(summaries)
+}
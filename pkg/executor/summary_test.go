@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSummaryRun writes a run directory with the given config.json and
+// result.json contents. An empty resultJSON skips result.json.
+func writeSummaryRun(t *testing.T, name, configJSON, resultJSON string) string {
+	t.Helper()
+
+	runDir := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.MkdirAll(runDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "config.json"), []byte(configJSON), 0o644))
+
+	if resultJSON != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(runDir, "result.json"), []byte(resultJSON), 0o644))
+	}
+
+	return runDir
+}
+
+func summaryTestRunDirs(t *testing.T) []string {
+	t.Helper()
+
+	geth := writeSummaryRun(t, "1700000000_abc_geth", `{
+		"timestamp": 1700000000,
+		"timestamp_end": 1700000125,
+		"status": "completed",
+		"instance": {"id": "geth", "client": "geth", "client_version": "Geth/v1.17.0"},
+		"test_counts": {"total": 3, "passed": 3, "failed": 0}
+	}`, `{
+		"tests": {
+			"a": {"dir": "a", "steps": {"test": {"aggregated": {"method_stats": {"times": {
+				"engine_newPayloadV4": {"count": 1, "last": 10000000},
+				"engine_forkchoiceUpdatedV3": {"count": 1, "last": 900000000}
+			}}}}}},
+			"b": {"dir": "b", "steps": {"test": {"aggregated": {"method_stats": {"times": {
+				"engine_newPayloadV4": {"count": 2, "mean": 20000000, "last": 25000000}
+			}}}}}},
+			"c": {"dir": "c", "steps": {"test": {"aggregated": {"method_stats": {"times": {
+				"engine_newPayloadV4": {"count": 1, "last": 30000000}
+			}}}}}}
+		}
+	}`)
+
+	reth := writeSummaryRun(t, "1700000200_def_reth", `{
+		"timestamp": 1700000200,
+		"timestamp_end": 1700000230,
+		"status": "container_died",
+		"instance": {"id": "reth", "client": "reth"},
+		"test_counts": {"total": 3, "passed": 1, "failed": 2}
+	}`, "")
+
+	return []string{geth, reth}
+}
+
+func TestBuildRunSummaries(t *testing.T) {
+	summaries, err := BuildRunSummaries(summaryTestRunDirs(t))
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+
+	assert.Equal(t, &RunSummary{
+		RunID:           "1700000000_abc_geth",
+		InstanceID:      "geth",
+		Client:          "geth",
+		ClientVersion:   "Geth/v1.17.0",
+		Status:          "completed",
+		TestsPassed:     3,
+		TestsFailed:     0,
+		DurationNs:      125_000_000_000,
+		NewPayloadP95Ns: 30_000_000,
+	}, summaries[0])
+
+	assert.Equal(t, &RunSummary{
+		RunID:       "1700000200_def_reth",
+		InstanceID:  "reth",
+		Client:      "reth",
+		Status:      "container_died",
+		TestsPassed: 1,
+		TestsFailed: 2,
+		DurationNs:  30_000_000_000,
+	}, summaries[1])
+}
+
+func TestBuildRunSummaries_MissingConfig(t *testing.T) {
+	_, err := BuildRunSummaries([]string{t.TempDir()})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading config.json")
+}
+
+func TestWriteRunSummaryTable(t *testing.T) {
+	summaries, err := BuildRunSummaries(summaryTestRunDirs(t))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteRunSummaryTable(&buf, summaries))
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, []string{
+		"INSTANCE", "CLIENT", "VERSION", "STATUS", "PASSED", "FAILED", "DURATION", "P95", "NEWPAYLOAD",
+	}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{
+		"geth", "geth", "Geth/v1.17.0", "completed", "3", "0", "2m", "5s", "30ms",
+	}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{
+		"reth", "reth", "-", "container_died", "1", "2", "30s", "-",
+	}, strings.Fields(lines[2]))
+}
+
+func TestWriteRunSummaryJSON(t *testing.T) {
+	summaries, err := BuildRunSummaries(summaryTestRunDirs(t))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteRunSummaryJSON(&buf, summaries))
+
+	var decoded []*RunSummary
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, summaries, decoded)
+}
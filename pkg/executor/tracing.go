@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope of the executor's spans.
+const tracerName = "github.com/ethpandaops/benchmarkoor/pkg/executor"
+
+// Span attribute keys.
+const (
+	attrTestName    = attribute.Key("benchmarkoor.test.name")
+	attrTestStatus  = attribute.Key("benchmarkoor.test.status")
+	attrStepName    = attribute.Key("benchmarkoor.step.name")
+	attrRPCSystem   = attribute.Key("rpc.system")
+	attrRPCMethod   = attribute.Key("rpc.method")
+	attrBlockNumber = attribute.Key("benchmarkoor.block.number")
+	attrDurationNs  = attribute.Key("benchmarkoor.rpc.duration_ns")
+	attrRPCStatus   = attribute.Key("benchmarkoor.rpc.status")
+)
+
+// RPC statuses recorded on RPC spans.
+const (
+	rpcStatusOK    = "ok"
+	rpcStatusError = "error"
+)
+
+// newTracer returns the executor's tracer. Without a provider, spans are
+// no-ops.
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+
+	return provider.Tracer(tracerName)
+}
+
+// startTestSpan starts the span covering all steps of a test.
+func (e *executor) startTestSpan(ctx context.Context, testName string) (context.Context, trace.Span) {
+	return e.tracer.Start(ctx, "test "+testName,
+		trace.WithAttributes(attrTestName.String(testName)))
+}
+
+// endTestSpan records the test's outcome and ends its span.
+func endTestSpan(span trace.Span, status string) {
+	span.SetAttributes(attrTestStatus.String(status))
+
+	if status != TestMarkerStatusPassed && status != TestMarkerStatusSkipped {
+		span.SetStatus(codes.Error, "test "+status)
+	}
+
+	span.End()
+}
+
+// startRPCSpan starts the span for a single JSON-RPC call. engine_newPayload
+// calls carry the payload's block number.
+func (e *executor) startRPCSpan(
+	ctx context.Context, stepName, method, payload string,
+) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attrRPCSystem.String("jsonrpc"),
+		attrRPCMethod.String(method),
+		attrStepName.String(stepName),
+	}
+
+	if strings.HasPrefix(method, "engine_newPayload") {
+		if number, err := extractBlockNumber(payload); err == nil {
+			attrs = append(attrs, attrBlockNumber.Int64(int64(number))) //nolint:gosec // block numbers fit in int64.
+		}
+	}
+
+	return e.tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// endRPCSpan records the call's server duration and outcome and ends its
+// span.
+func endRPCSpan(span trace.Span, duration int64, succeeded bool, err error) {
+	span.SetAttributes(attrDurationNs.Int64(duration))
+
+	if succeeded {
+		span.SetAttributes(attrRPCStatus.String(rpcStatusOK))
+	} else {
+		span.SetAttributes(attrRPCStatus.String(rpcStatusError))
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetStatus(codes.Error, "invalid response")
+		}
+	}
+
+	span.End()
+}
+
+// extractBlockNumber extracts blockNumber from an engine_newPayload request.
+func extractBlockNumber(request string) (uint64, error) {
+	var req struct {
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(request), &req); err != nil {
+		return 0, err
+	}
+
+	if len(req.Params) == 0 {
+		return 0, fmt.Errorf("no params")
+	}
+
+	var payload struct {
+		BlockNumber string `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(req.Params[0], &payload); err != nil {
+		return 0, err
+	}
+
+	// Parse hex string (0x prefixed).
+	return strconv.ParseUint(strings.TrimPrefix(payload.BlockNumber, "0x"), 16, 64)
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanAttrs returns a span's attributes keyed by name.
+func spanAttrs(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(span.Attributes))
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestExecuteTests_Spans(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "engine_forkchoiceUpdatedV3") {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":2,"error":{"code":-38002,"message":"invalid forkchoice state"}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`))
+	}))
+	defer srv.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	e := NewExecutor(log, &Config{TracerProvider: provider}).(*executor)
+	e.prepared = &PreparedSource{}
+
+	_, err := e.ExecuteTests(t.Context(), &ExecuteOptions{
+		EngineEndpoint: srv.URL,
+		JWT:            config.DefaultJWT,
+		ResultsDir:     t.TempDir(),
+		Tests: []*TestWithSteps{{
+			Name: "test_a.txt",
+			Test: &StepFile{Name: "test_a.txt", Provider: &linesProvider{lines: []string{
+				`{"jsonrpc":"2.0","method":"engine_newPayloadV3","params":[{"blockNumber":"0x2a","blockHash":"0x01"}],"id":1}`,
+				`{"jsonrpc":"2.0","method":"engine_forkchoiceUpdatedV3","params":[],"id":2}`,
+			}}},
+		}},
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	newPayload, fcu, test := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "test test_a.txt", test.Name)
+	assert.Equal(t, "test_a.txt", spanAttrs(test)[attrTestName].AsString())
+	assert.Equal(t, TestMarkerStatusFailed, spanAttrs(test)[attrTestStatus].AsString())
+	assert.Equal(t, codes.Error, test.Status.Code)
+
+	// RPC spans are children of the test span.
+	assert.Equal(t, "engine_newPayloadV3", newPayload.Name)
+	assert.Equal(t, test.SpanContext.SpanID(), newPayload.Parent.SpanID())
+	assert.Equal(t, test.SpanContext.SpanID(), fcu.Parent.SpanID())
+
+	attrs := spanAttrs(newPayload)
+	assert.Equal(t, "jsonrpc", attrs[attrRPCSystem].AsString())
+	assert.Equal(t, "engine_newPayloadV3", attrs[attrRPCMethod].AsString())
+	assert.Equal(t, "test_a.txt", attrs[attrStepName].AsString())
+	assert.Equal(t, int64(42), attrs[attrBlockNumber].AsInt64())
+	assert.Positive(t, attrs[attrDurationNs].AsInt64())
+	assert.Equal(t, rpcStatusOK, attrs[attrRPCStatus].AsString())
+	assert.Equal(t, codes.Unset, newPayload.Status.Code)
+
+	// The block number is only recorded for newPayload calls.
+	attrs = spanAttrs(fcu)
+	assert.NotContains(t, attrs, attrBlockNumber)
+	assert.Equal(t, rpcStatusError, attrs[attrRPCStatus].AsString())
+	assert.Equal(t, codes.Error, fcu.Status.Code)
+}
+
+func TestNewTracer_NoopWithoutProvider(t *testing.T) {
+	_, span := newTracer(nil).Start(t.Context(), "test")
+	defer span.End()
+
+	assert.False(t, span.IsRecording())
+	assert.False(t, span.SpanContext().IsValid())
+}
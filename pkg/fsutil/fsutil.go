@@ -3,41 +3,212 @@ package fsutil
 import (
 	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
 	"strconv"
 	"strings"
 )
 
-// OwnerConfig holds parsed UID/GID for file ownership.
+// OwnerConfig holds parsed UID/GID for file ownership and the optional
+// permission bits of created files.
 type OwnerConfig struct {
 	UID int
 	GID int
+	// Mode, when non-zero, replaces the permission bits of created files.
+	// Directories get Mode plus the search bit of every class that can read.
+	Mode os.FileMode
 }
 
-// ParseOwner parses "UID:GID" string. Returns nil if empty.
+// User and group lookups, replaceable in tests.
+var (
+	lookupUser   = user.Lookup
+	lookupUserID = user.LookupId
+	lookupGroup  = user.LookupGroup
+)
+
+// ParseOwner parses a "USER:GROUP" string, where USER and GROUP are names
+// or numeric IDs. Names must exist on the host, numeric IDs are used as is.
+// The group may be omitted to use the primary group of USER. Returns nil if
+// empty.
 func ParseOwner(owner string) (*OwnerConfig, error) {
 	if owner == "" {
 		return nil, nil
 	}
 
-	parts := strings.Split(owner, ":")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid format %q, expected UID:GID", owner)
+	userPart, groupPart, hasGroup := strings.Cut(owner, ":")
+	if userPart == "" || (hasGroup && groupPart == "") || strings.Contains(groupPart, ":") {
+		return nil, fmt.Errorf("invalid format %q, expected USER:GROUP", owner)
 	}
 
-	uid, err := strconv.Atoi(parts[0])
+	uid, primaryGID, err := resolveUser(userPart)
 	if err != nil {
-		return nil, fmt.Errorf("invalid UID %q: %w", parts[0], err)
+		return nil, err
 	}
 
-	gid, err := strconv.Atoi(parts[1])
+	if !hasGroup {
+		if primaryGID < 0 {
+			return nil, fmt.Errorf(
+				"user %q has no known primary group, expected USER:GROUP", userPart,
+			)
+		}
+
+		return &OwnerConfig{UID: uid, GID: primaryGID}, nil
+	}
+
+	gid, err := resolveGroup(groupPart)
 	if err != nil {
-		return nil, fmt.Errorf("invalid GID %q: %w", parts[1], err)
+		return nil, err
 	}
 
 	return &OwnerConfig{UID: uid, GID: gid}, nil
 }
 
+// resolveUser returns the UID of a user name or numeric ID, and its primary
+// GID, or -1 when the user is not known to the host.
+func resolveUser(name string) (int, int, error) {
+	if uid, ok, err := parseID("UID", name); ok {
+		if err != nil {
+			return 0, 0, err
+		}
+
+		u, lookupErr := lookupUserID(name)
+		if lookupErr != nil {
+			return uid, -1, nil
+		}
+
+		gid, _ := strconv.Atoi(u.Gid)
+
+		return uid, gid, nil
+	}
+
+	u, err := lookupUser(name)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unknown user %q: %w", name, err)
+	}
+
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("user %q has non-numeric UID %q", name, u.Uid)
+	}
+
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		gid = -1
+	}
+
+	return uid, gid, nil
+}
+
+// resolveGroup returns the GID of a group name or numeric ID.
+func resolveGroup(name string) (int, error) {
+	if gid, ok, err := parseID("GID", name); ok {
+		return gid, err
+	}
+
+	g, err := lookupGroup(name)
+	if err != nil {
+		return 0, fmt.Errorf("unknown group %q: %w", name, err)
+	}
+
+	gid, err := strconv.Atoi(g.Gid)
+	if err != nil {
+		return 0, fmt.Errorf("group %q has non-numeric GID %q", name, g.Gid)
+	}
+
+	return gid, nil
+}
+
+// parseID parses a numeric user or group ID. ok reports whether s is
+// numeric at all, i.e. whether it is an ID rather than a name.
+func parseID(kind, s string) (id int, ok bool, err error) {
+	if strings.TrimLeft(s, "0123456789") != "" {
+		return 0, false, nil
+	}
+
+	id, err = strconv.Atoi(s)
+	if err != nil {
+		return 0, true, fmt.Errorf("invalid %s %q: %w", kind, s, err)
+	}
+
+	return id, true, nil
+}
+
+// ParseMode parses an octal permission mode such as "0640". Returns 0 if
+// empty. The mode must grant the owner read and write access, which is
+// needed to update results in place.
+func ParseMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mode %q, expected octal permissions such as 0640", mode)
+	}
+
+	if parsed > 0o777 {
+		return 0, fmt.Errorf("invalid mode %q, only permission bits (up to 0777) are allowed", mode)
+	}
+
+	if parsed&0o600 != 0o600 {
+		return 0, fmt.Errorf("invalid mode %q, the owner needs read and write permission", mode)
+	}
+
+	return os.FileMode(parsed), nil
+}
+
+// WithMode returns owner with the given file mode. A nil owner becomes an
+// OwnerConfig that leaves ownership unchanged. A zero mode returns owner as is.
+func WithMode(owner *OwnerConfig, mode os.FileMode) *OwnerConfig {
+	if mode == 0 {
+		return owner
+	}
+
+	if owner == nil {
+		// Chown leaves the owner and group unchanged for -1.
+		return &OwnerConfig{UID: -1, GID: -1, Mode: mode}
+	}
+
+	withMode := *owner
+	withMode.Mode = mode
+
+	return &withMode
+}
+
+// fileMode returns the permission bits of a created file.
+func fileMode(perm os.FileMode, owner *OwnerConfig) os.FileMode {
+	if owner == nil || owner.Mode == 0 {
+		return perm
+	}
+
+	return owner.Mode
+}
+
+// dirMode returns the permission bits of a created directory.
+func dirMode(perm os.FileMode, owner *OwnerConfig) os.FileMode {
+	if owner == nil || owner.Mode == 0 {
+		return perm
+	}
+
+	return owner.Mode | (owner.Mode&0o444)>>2
+}
+
+// chmod applies the configured mode, if any. The umask does not apply to
+// explicit chmods, so configured modes are honored exactly.
+func chmod(path string, mode os.FileMode, owner *OwnerConfig) error {
+	if owner == nil || owner.Mode == 0 {
+		return nil
+	}
+
+	return os.Chmod(path, mode)
+}
+
+// ChmodDir sets the permissions of a directory created outside MkdirAll,
+// such as with os.MkdirTemp, to those MkdirAll would have given it.
+func ChmodDir(path string, perm os.FileMode, owner *OwnerConfig) error {
+	return os.Chmod(path, dirMode(perm, owner))
+}
+
 // Chown sets ownership if owner is not nil. Best-effort, ignores errors.
 func Chown(path string, owner *OwnerConfig) {
 	if owner == nil {
@@ -63,37 +234,58 @@ func MkdirAll(path string, perm os.FileMode, owner *OwnerConfig) error {
 		}
 	}
 
-	if err := os.MkdirAll(path, perm); err != nil {
+	mode := dirMode(perm, owner)
+
+	if err := os.MkdirAll(path, mode); err != nil {
 		return err
 	}
 
 	// Chown all newly created directories (from leaf up to existing ancestor).
 	for p := path; p != existing; p = filepath.Dir(p) {
 		Chown(p, owner)
+
+		if err := chmod(p, mode, owner); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// WriteFile writes file and sets ownership.
+// WriteFile writes file and sets ownership and permissions.
 func WriteFile(path string, data []byte, perm os.FileMode, owner *OwnerConfig) error {
-	if err := os.WriteFile(path, data, perm); err != nil {
+	mode := fileMode(perm, owner)
+
+	if err := os.WriteFile(path, data, mode); err != nil {
 		return err
 	}
 
 	Chown(path, owner)
 
-	return nil
+	return chmod(path, mode, owner)
 }
 
-// Create creates file and sets ownership.
+// Create creates file and sets ownership and permissions.
 func Create(path string, owner *OwnerConfig) (*os.File, error) {
-	f, err := os.Create(path)
+	return OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666, owner)
+}
+
+// OpenFile opens file like os.OpenFile and sets ownership and permissions.
+func OpenFile(path string, flag int, perm os.FileMode, owner *OwnerConfig) (*os.File, error) {
+	mode := fileMode(perm, owner)
+
+	f, err := os.OpenFile(path, flag, mode) //nolint:gosec // callers pass trusted results paths
 	if err != nil {
 		return nil, err
 	}
 
 	Chown(path, owner)
 
+	if err := chmod(path, mode, owner); err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
 	return f, nil
 }
@@ -0,0 +1,207 @@
+package fsutil
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		want      os.FileMode
+		errSubstr string
+	}{
+		{name: "empty", mode: "", want: 0},
+		{name: "leading zero", mode: "0640", want: 0o640},
+		{name: "without leading zero", mode: "600", want: 0o600},
+		{name: "group writable", mode: "0664", want: 0o664},
+		{name: "not octal", mode: "0948", errSubstr: "expected octal permissions"},
+		{name: "symbolic", mode: "rw-r-----", errSubstr: "expected octal permissions"},
+		{name: "special bits", mode: "4755", errSubstr: "up to 0777"},
+		{name: "owner read only", mode: "0440", errSubstr: "owner needs read and write"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMode(tt.mode)
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithMode(t *testing.T) {
+	assert.Nil(t, WithMode(nil, 0))
+
+	owner := &OwnerConfig{UID: 1000, GID: 1000}
+	assert.Same(t, owner, WithMode(owner, 0))
+
+	withMode := WithMode(owner, 0o640)
+	assert.Equal(t, &OwnerConfig{UID: 1000, GID: 1000, Mode: 0o640}, withMode)
+	assert.Zero(t, owner.Mode, "the original owner is not modified")
+
+	assert.Equal(t, &OwnerConfig{UID: -1, GID: -1, Mode: 0o600}, WithMode(nil, 0o600))
+}
+
+func TestConfiguredModeIsHonored(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     os.FileMode
+		wantFile os.FileMode
+		wantDir  os.FileMode
+	}{
+		{name: "group readable", mode: 0o640, wantFile: 0o640, wantDir: 0o750},
+		{name: "owner only", mode: 0o600, wantFile: 0o600, wantDir: 0o700},
+		// Explicit modes are not restricted by the process umask.
+		{name: "world writable", mode: 0o666, wantFile: 0o666, wantDir: 0o777},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := WithMode(nil, tt.mode)
+			base := t.TempDir()
+
+			dir := filepath.Join(base, "runs", "run1")
+			require.NoError(t, MkdirAll(dir, 0755, owner))
+			assertMode(t, filepath.Join(base, "runs"), tt.wantDir)
+			assertMode(t, dir, tt.wantDir)
+
+			written := filepath.Join(dir, "result.json")
+			require.NoError(t, WriteFile(written, []byte("{}"), 0644, owner))
+			assertMode(t, written, tt.wantFile)
+
+			created, err := Create(filepath.Join(dir, "benchmarkoor.log"), owner)
+			require.NoError(t, err)
+			require.NoError(t, created.Close())
+			assertMode(t, created.Name(), tt.wantFile)
+
+			opened, err := OpenFile(filepath.Join(dir, "container.log"), os.O_CREATE|os.O_WRONLY, 0644, owner)
+			require.NoError(t, err)
+			require.NoError(t, opened.Close())
+			assertMode(t, opened.Name(), tt.wantFile)
+
+			tmpDir, err := os.MkdirTemp(base, "tmp-")
+			require.NoError(t, err)
+			require.NoError(t, ChmodDir(tmpDir, 0755, owner))
+			assertMode(t, tmpDir, tt.wantDir)
+		})
+	}
+}
+
+func TestDefaultModeWithoutConfiguredMode(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "file")
+	require.NoError(t, WriteFile(path, []byte("x"), 0600, nil))
+	assertMode(t, path, 0o600)
+
+	// An existing directory keeps its permissions.
+	require.NoError(t, os.Chmod(dir, 0o700))
+	require.NoError(t, MkdirAll(dir, 0755, WithMode(nil, 0o640)))
+	assertMode(t, dir, 0o700)
+}
+
+func assertMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, info.Mode().Perm(), path)
+}
+
+func TestParseOwner(t *testing.T) {
+	users := map[string]*user.User{
+		"benchuser": {Username: "benchuser", Uid: "1234", Gid: "1234"},
+		"1000":      {Username: "runner", Uid: "1000", Gid: "1001"},
+	}
+	groups := map[string]*user.Group{
+		"benchgroup": {Name: "benchgroup", Gid: "2345"},
+	}
+
+	stubLookups(t, users, groups)
+
+	tests := []struct {
+		name      string
+		owner     string
+		want      *OwnerConfig
+		errSubstr string
+	}{
+		{name: "empty", owner: "", want: nil},
+		{name: "numeric", owner: "1000:1000", want: &OwnerConfig{UID: 1000, GID: 1000}},
+		{name: "numeric unknown to host", owner: "4242:4343", want: &OwnerConfig{UID: 4242, GID: 4343}},
+		{name: "names", owner: "benchuser:benchgroup", want: &OwnerConfig{UID: 1234, GID: 2345}},
+		{name: "name and numeric group", owner: "benchuser:50", want: &OwnerConfig{UID: 1234, GID: 50}},
+		{name: "numeric user and group name", owner: "0:benchgroup", want: &OwnerConfig{UID: 0, GID: 2345}},
+		{name: "user name only", owner: "benchuser", want: &OwnerConfig{UID: 1234, GID: 1234}},
+		{name: "known numeric user only", owner: "1000", want: &OwnerConfig{UID: 1000, GID: 1001}},
+		{name: "unknown numeric user only", owner: "4242", errSubstr: "no known primary group"},
+		{name: "unknown user", owner: "nobody-here:benchgroup", errSubstr: `unknown user "nobody-here"`},
+		{name: "unknown group", owner: "benchuser:nogroup", errSubstr: `unknown group "nogroup"`},
+		{name: "negative id", owner: "-1:-1", errSubstr: `unknown user "-1"`},
+		{name: "missing user", owner: ":1000", errSubstr: "expected USER:GROUP"},
+		{name: "missing group", owner: "1000:", errSubstr: "expected USER:GROUP"},
+		{name: "too many parts", owner: "1:2:3", errSubstr: "expected USER:GROUP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOwner(tt.owner)
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// stubLookups replaces the user and group lookups with lookups in the given
+// maps for the duration of the test. users is keyed by name and UID.
+func stubLookups(t *testing.T, users map[string]*user.User, groups map[string]*user.Group) {
+	t.Helper()
+
+	origUser, origUserID, origGroup := lookupUser, lookupUserID, lookupGroup
+
+	t.Cleanup(func() {
+		lookupUser, lookupUserID, lookupGroup = origUser, origUserID, origGroup
+	})
+
+	lookupUser = func(name string) (*user.User, error) {
+		if u, ok := users[name]; ok {
+			return u, nil
+		}
+
+		return nil, user.UnknownUserError(name)
+	}
+	lookupUserID = func(uid string) (*user.User, error) {
+		if u, ok := users[uid]; ok {
+			return u, nil
+		}
+
+		return nil, user.UnknownUserIdError(0)
+	}
+	lookupGroup = func(name string) (*user.Group, error) {
+		if g, ok := groups[name]; ok {
+			return g, nil
+		}
+
+		return nil, user.UnknownGroupError(name)
+	}
+}
@@ -0,0 +1,158 @@
+package irq
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AffinityManager moves interrupt affinity away from pinned CPUs and
+// restores the original affinity afterwards. The original affinity is also
+// saved to a state file in cacheDir, so that `benchmarkoor cleanup` can
+// restore it after a crash. Changing affinity requires root.
+type AffinityManager struct {
+	log      logrus.FieldLogger
+	procPath string
+	cacheDir string
+
+	mu        sync.Mutex
+	original  map[int]string // IRQ -> original smp_affinity_list.
+	stateFile string
+}
+
+// NewAffinityManager creates an affinity manager for the given procfs path
+// that keeps its state files in cacheDir.
+func NewAffinityManager(log logrus.FieldLogger, procPath, cacheDir string) *AffinityManager {
+	return &AffinityManager{
+		log:      log.WithField("component", "irq"),
+		procPath: procPath,
+		cacheDir: cacheDir,
+		original: make(map[int]string),
+	}
+}
+
+// affinityListPath returns the path to an IRQ's smp_affinity_list file.
+func affinityListPath(procPath string, irq int) string {
+	return filepath.Join(procPath, "irq", strconv.Itoa(irq), "smp_affinity_list")
+}
+
+// MoveAway sets the affinity of each interrupt to allCPUs minus pinned.
+// Interrupts whose affinity cannot be changed (e.g. kernel-managed NVMe
+// queue IRQs) are skipped and reported in the returned error; the others
+// are still moved. It returns the number of interrupts moved.
+func (m *AffinityManager) MoveAway(interrupts []Interrupt, pinned, allCPUs []int) (int, error) {
+	var target []int
+
+	for _, cpu := range allCPUs {
+		if !slices.Contains(pinned, cpu) {
+			target = append(target, cpu)
+		}
+	}
+
+	if len(target) == 0 {
+		return 0, fmt.Errorf("no CPUs left for interrupts outside the pinned cpuset")
+	}
+
+	targetList := FormatCPUList(target)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		moved int
+		errs  []error
+	)
+
+	for _, i := range interrupts {
+		path := affinityListPath(m.procPath, i.IRQ)
+
+		current, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("irq %d: reading affinity: %w", i.IRQ, err))
+
+			continue
+		}
+
+		if err := os.WriteFile(path, []byte(targetList), 0644); err != nil {
+			errs = append(errs, fmt.Errorf("irq %d: setting affinity: %w", i.IRQ, err))
+
+			continue
+		}
+
+		// Keep the first recorded value if the IRQ is moved more than once.
+		if _, ok := m.original[i.IRQ]; !ok {
+			m.original[i.IRQ] = strings.TrimSpace(string(current))
+		}
+
+		moved++
+
+		m.log.WithFields(logrus.Fields{
+			"irq":         i.IRQ,
+			"description": i.Description,
+			"cpus":        targetList,
+		}).Debug("Moved IRQ affinity")
+	}
+
+	if moved > 0 {
+		m.saveState()
+	}
+
+	return moved, errors.Join(errs...)
+}
+
+// Restore restores the original affinity of all moved interrupts.
+func (m *AffinityManager) Restore() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := restoreAffinities(m.procPath, m.original); err != nil {
+		// Keep only the affinities still to be restored for cleanup.
+		m.saveState()
+
+		return err
+	}
+
+	if m.stateFile != "" {
+		if err := RemoveStateFile(m.stateFile); err != nil {
+			m.log.WithError(err).Warn("Failed to remove state file")
+		}
+
+		m.stateFile = ""
+	}
+
+	m.log.Debug("IRQ affinity restored")
+
+	return nil
+}
+
+// saveState persists the original affinities for crash recovery (must be
+// called with lock held). Failures are logged, as the affinity can still be
+// restored by Restore.
+func (m *AffinityManager) saveState() {
+	settings := &OriginalSettings{Affinities: m.original}
+
+	if m.stateFile != "" {
+		if err := UpdateState(m.stateFile, settings); err != nil {
+			m.log.WithError(err).Warn("Failed to update IRQ affinity state file")
+		}
+
+		return
+	}
+
+	stateFile, err := SaveState(m.cacheDir, settings)
+	if err != nil {
+		m.log.WithError(err).Warn("Failed to save IRQ affinity state file")
+
+		return
+	}
+
+	m.stateFile = stateFile
+	m.log.WithField("state_file", stateFile).Debug("Saved IRQ affinity state")
+}
@@ -0,0 +1,207 @@
+package irq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// stateFilePrefix is the prefix for IRQ affinity state files.
+	stateFilePrefix = "benchmarkoor-irq-"
+	// stateFileSuffix is the suffix for IRQ affinity state files.
+	stateFileSuffix = ".json"
+)
+
+// OriginalSettings stores the original affinity of moved interrupts.
+type OriginalSettings struct {
+	// Affinities maps an IRQ to its original smp_affinity_list.
+	Affinities map[int]string `json:"affinities"`
+}
+
+// StateFile represents an IRQ affinity state file for orphan detection.
+type StateFile struct {
+	Path      string
+	Timestamp time.Time
+}
+
+// SaveState saves the original affinities to a new state file.
+func SaveState(cacheDir string, settings *OriginalSettings) (string, error) {
+	if cacheDir == "" {
+		cacheDir = os.TempDir()
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	// Several runs may move interrupts at the same time, so the name must be
+	// unique rather than derived from the time.
+	f, err := os.CreateTemp(cacheDir, stateFilePrefix+"*"+stateFileSuffix)
+	if err != nil {
+		return "", fmt.Errorf("creating state file: %w", err)
+	}
+
+	statePath := f.Name()
+
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("closing state file: %w", err)
+	}
+
+	if err := UpdateState(statePath, settings); err != nil {
+		_ = os.Remove(statePath)
+
+		return "", err
+	}
+
+	return statePath, nil
+}
+
+// UpdateState overwrites an existing state file with settings.
+func UpdateState(statePath string, settings *OriginalSettings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling settings: %w", err)
+	}
+
+	if err := os.WriteFile(statePath, data, 0644); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadState loads the original affinities from a state file.
+func LoadState(statePath string) (*OriginalSettings, error) {
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	var settings OriginalSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// RemoveStateFile removes a state file.
+func RemoveStateFile(statePath string) error {
+	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing state file: %w", err)
+	}
+
+	return nil
+}
+
+// ListOrphanedStateFiles finds state files left behind by interrupted runs.
+func ListOrphanedStateFiles(cacheDir string) ([]StateFile, error) {
+	if cacheDir == "" {
+		cacheDir = os.TempDir()
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	var stateFiles []StateFile
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasPrefix(name, stateFilePrefix) || !strings.HasSuffix(name, stateFileSuffix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		stateFiles = append(stateFiles, StateFile{
+			Path:      filepath.Join(cacheDir, name),
+			Timestamp: info.ModTime(),
+		})
+	}
+
+	return stateFiles, nil
+}
+
+// RestoreFromStateFile restores the interrupt affinities from a state file
+// and removes it. The file is kept if any affinity cannot be restored.
+// procPath is the procfs mount point (e.g. "/proc").
+func RestoreFromStateFile(
+	_ context.Context,
+	log logrus.FieldLogger,
+	statePath, procPath string,
+) error {
+	settings, err := LoadState(statePath)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+
+	if err := restoreAffinities(procPath, settings.Affinities); err != nil {
+		return fmt.Errorf("restoring IRQ affinity: %w", err)
+	}
+
+	if err := RemoveStateFile(statePath); err != nil {
+		log.WithError(err).Warn("Failed to remove state file")
+	}
+
+	log.WithFields(logrus.Fields{
+		"state_file": statePath,
+		"irqs":       len(settings.Affinities),
+	}).Info("Restored IRQ affinity from state file")
+
+	return nil
+}
+
+// CleanupOrphanedIRQState restores the interrupt affinities from all orphaned
+// state files. procPath is the procfs mount point (e.g. "/proc").
+func CleanupOrphanedIRQState(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	stateFiles []StateFile,
+	procPath string,
+) error {
+	for _, sf := range stateFiles {
+		if err := RestoreFromStateFile(ctx, log, sf.Path, procPath); err != nil {
+			log.WithError(err).WithField("state_file", sf.Path).Warn("Failed to restore from state file")
+
+			continue
+		}
+	}
+
+	return nil
+}
+
+// restoreAffinities writes the given affinities back and removes each
+// restored IRQ from the map.
+func restoreAffinities(procPath string, affinities map[int]string) error {
+	var errs []error
+
+	for irq, list := range affinities {
+		if err := os.WriteFile(affinityListPath(procPath, irq), []byte(list), 0644); err != nil {
+			errs = append(errs, fmt.Errorf("irq %d: restoring affinity %q: %w", irq, list, err))
+
+			continue
+		}
+
+		delete(affinities, irq)
+	}
+
+	return errors.Join(errs...)
+}
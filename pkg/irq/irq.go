@@ -0,0 +1,211 @@
+// Package irq inspects which CPUs service device interrupts and moves their
+// affinity away from CPUs pinned for benchmarking.
+package irq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultProcPath is the default procfs mount point.
+const DefaultProcPath = "/proc"
+
+// devicePatterns match the action names of NVMe and NIC interrupts in
+// /proc/interrupts (e.g. "nvme0q3", "eth0-TxRx-1", "mlx5_comp2@pci:...").
+var devicePatterns = []string{
+	"nvme", "eth", "enp", "ens", "eno", "enx", "wlan", "wlp",
+	"mlx4", "mlx5", "ixgbe", "i40e", "ice", "igb", "bnxt", "virtio",
+}
+
+// Table is the parsed content of /proc/interrupts.
+type Table struct {
+	// CPUs lists the CPU IDs of the table columns.
+	CPUs []int
+	// Interrupts lists the numbered interrupts; per-CPU system counters
+	// such as NMI and LOC are skipped.
+	Interrupts []Interrupt
+}
+
+// Interrupt is a single numbered interrupt line.
+type Interrupt struct {
+	IRQ         int
+	Counts      map[int]uint64 // Interrupt count per CPU ID.
+	Description string         // Chip, hwirq and action names.
+}
+
+// ReadInterrupts parses <procPath>/interrupts.
+func ReadInterrupts(procPath string) (*Table, error) {
+	path := filepath.Join(procPath, "interrupts")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	table, err := ParseInterrupts(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return table, nil
+}
+
+// ParseInterrupts parses the /proc/interrupts format.
+func ParseInterrupts(r io.Reader) (*Table, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("missing CPU header")
+	}
+
+	table := &Table{}
+
+	for _, field := range strings.Fields(scanner.Text()) {
+		id, err := strconv.Atoi(strings.TrimPrefix(field, "CPU"))
+		if err != nil || !strings.HasPrefix(field, "CPU") {
+			return nil, fmt.Errorf("invalid CPU header field %q", field)
+		}
+
+		table.CPUs = append(table.CPUs, id)
+	}
+
+	if len(table.CPUs) == 0 {
+		return nil, fmt.Errorf("missing CPU header")
+	}
+
+	for scanner.Scan() {
+		label, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		irq, err := strconv.Atoi(strings.TrimSpace(label))
+		if err != nil {
+			continue // NMI, LOC, ERR, ...
+		}
+
+		fields := strings.Fields(rest)
+		counts := make(map[int]uint64, len(table.CPUs))
+
+		n := 0
+		for ; n < len(table.CPUs) && n < len(fields); n++ {
+			count, err := strconv.ParseUint(fields[n], 10, 64)
+			if err != nil {
+				break
+			}
+
+			counts[table.CPUs[n]] = count
+		}
+
+		table.Interrupts = append(table.Interrupts, Interrupt{
+			IRQ:         irq,
+			Counts:      counts,
+			Description: strings.Join(fields[n:], " "),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// IsDevice reports whether the interrupt belongs to an NVMe drive or NIC.
+func (i *Interrupt) IsDevice() bool {
+	desc := strings.ToLower(i.Description)
+
+	for _, field := range strings.Fields(desc) {
+		for _, pattern := range devicePatterns {
+			if strings.HasPrefix(field, pattern) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CPUs returns the CPUs that have serviced the interrupt, sorted.
+func (i *Interrupt) CPUs() []int {
+	cpus := make([]int, 0, len(i.Counts))
+
+	for cpu, count := range i.Counts {
+		if count > 0 {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	slices.Sort(cpus)
+
+	return cpus
+}
+
+// DeviceInterrupts returns the NVMe and NIC interrupts in the table.
+func (t *Table) DeviceInterrupts() []Interrupt {
+	var device []Interrupt
+
+	for _, i := range t.Interrupts {
+		if i.IsDevice() {
+			device = append(device, i)
+		}
+	}
+
+	return device
+}
+
+// HandlingCPUs returns the sorted set of CPUs that have serviced any of
+// the given interrupts.
+func HandlingCPUs(interrupts []Interrupt) []int {
+	var cpus []int
+
+	for _, i := range interrupts {
+		for _, cpu := range i.CPUs() {
+			if !slices.Contains(cpus, cpu) {
+				cpus = append(cpus, cpu)
+			}
+		}
+	}
+
+	slices.Sort(cpus)
+
+	return cpus
+}
+
+// Overlap returns the sorted CPUs present in both a and b.
+func Overlap(a, b []int) []int {
+	var both []int
+
+	for _, cpu := range a {
+		if slices.Contains(b, cpu) && !slices.Contains(both, cpu) {
+			both = append(both, cpu)
+		}
+	}
+
+	slices.Sort(both)
+
+	return both
+}
+
+// FormatCPUList formats CPU IDs as a comma-separated list ("0,2,5").
+func FormatCPUList(cpus []int) string {
+	parts := make([]string, len(cpus))
+	for i, cpu := range cpus {
+		parts[i] = strconv.Itoa(cpu)
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,167 @@
+package irq
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeInterrupts = `            CPU0       CPU1       CPU2       CPU3
+   0:         41          0          0          0   IO-APIC   2-edge      timer
+   8:          0          0          0          1   IO-APIC   8-edge      rtc0
+  24:       1200          0          0          0  IR-PCI-MSI 524288-edge      nvme0q0
+  25:          0       5400          0          0  IR-PCI-MSI 524289-edge      nvme0q1
+  26:          0          0          0          0  IR-PCI-MSI 524290-edge      nvme0q2
+  30:          0          0        300         12  IR-PCI-MSI 1572864-edge      enp3s0-TxRx-0
+  31:          7          0          0          0  IR-PCI-MSI 327680-edge      xhci_hcd
+ NMI:          0          0          0          0   Non-maskable interrupts
+ LOC:      91234      80123      70456      65012   Local timer interrupts
+ ERR:          0
+`
+
+func discardLogger() logrus.FieldLogger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return log
+}
+
+func TestParseInterrupts(t *testing.T) {
+	table, err := ParseInterrupts(strings.NewReader(fakeInterrupts))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{0, 1, 2, 3}, table.CPUs)
+	require.Len(t, table.Interrupts, 7)
+
+	nvme := table.Interrupts[2]
+	assert.Equal(t, 24, nvme.IRQ)
+	assert.Equal(t, "IR-PCI-MSI 524288-edge nvme0q0", nvme.Description)
+	assert.Equal(t, []int{0}, nvme.CPUs())
+
+	device := table.DeviceInterrupts()
+
+	irqs := make([]int, 0, len(device))
+	for _, i := range device {
+		irqs = append(irqs, i.IRQ)
+	}
+
+	assert.Equal(t, []int{24, 25, 26, 30}, irqs)
+	assert.Equal(t, []int{0, 1, 2, 3}, HandlingCPUs(device))
+}
+
+func TestParseInterrupts_Invalid(t *testing.T) {
+	_, err := ParseInterrupts(strings.NewReader(""))
+	require.Error(t, err)
+
+	_, err = ParseInterrupts(strings.NewReader("  0:  1  2\n"))
+	require.Error(t, err)
+}
+
+func TestOverlap(t *testing.T) {
+	assert.Equal(t, []int{1, 3}, Overlap([]int{3, 1, 5}, []int{0, 1, 2, 3}))
+	assert.Empty(t, Overlap([]int{4, 5}, []int{0, 1}))
+	assert.Equal(t, "0,2,5", FormatCPUList([]int{0, 2, 5}))
+}
+
+// fakeProc creates a fake procfs tree with the test interrupts and an
+// smp_affinity_list of "0-3" for each IRQ.
+func fakeProc(t *testing.T, irqs ...int) string {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "interrupts"), []byte(fakeInterrupts), 0644))
+
+	for _, irq := range irqs {
+		dir := filepath.Join(root, "irq", strconv.Itoa(irq))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "smp_affinity_list"), []byte("0-3\n"), 0644))
+	}
+
+	return root
+}
+
+func readAffinity(t *testing.T, root string, irq int) string {
+	t.Helper()
+
+	data, err := os.ReadFile(affinityListPath(root, irq))
+	require.NoError(t, err)
+
+	return strings.TrimSpace(string(data))
+}
+
+func TestAffinityManager_MoveAwayAndRestore(t *testing.T) {
+	// IRQ 26 has no affinity file, e.g. a managed interrupt.
+	root := fakeProc(t, 24, 25, 30)
+
+	table, err := ReadInterrupts(root)
+	require.NoError(t, err)
+
+	mgr := NewAffinityManager(discardLogger(), root, t.TempDir())
+
+	moved, err := mgr.MoveAway(table.DeviceInterrupts(), []int{0, 1}, table.CPUs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "irq 26")
+	assert.Equal(t, 3, moved)
+
+	for _, irq := range []int{24, 25, 30} {
+		assert.Equal(t, "2,3", readAffinity(t, root, irq))
+	}
+
+	// A second move keeps the original affinity for restore.
+	_, _ = mgr.MoveAway(table.DeviceInterrupts(), []int{0, 1, 2}, table.CPUs)
+	assert.Equal(t, "3", readAffinity(t, root, 24))
+
+	require.NoError(t, mgr.Restore())
+
+	for _, irq := range []int{24, 25, 30} {
+		assert.Equal(t, "0-3", readAffinity(t, root, irq))
+	}
+}
+
+func TestAffinityManager_NoCPUsLeft(t *testing.T) {
+	root := fakeProc(t, 24)
+	mgr := NewAffinityManager(discardLogger(), root, t.TempDir())
+
+	_, err := mgr.MoveAway([]Interrupt{{IRQ: 24}}, []int{0, 1}, []int{0, 1})
+	require.Error(t, err)
+	assert.Equal(t, "0-3", readAffinity(t, root, 24))
+}
+
+func TestCleanupOrphanedIRQState(t *testing.T) {
+	root := fakeProc(t, 24, 25, 30)
+	cacheDir := t.TempDir()
+
+	table, err := ReadInterrupts(root)
+	require.NoError(t, err)
+
+	// A run that moved interrupts and died before restoring them.
+	mgr := NewAffinityManager(discardLogger(), root, cacheDir)
+
+	_, err = mgr.MoveAway(table.DeviceInterrupts(), []int{0, 1}, table.CPUs)
+	require.Error(t, err)
+	assert.Equal(t, "2,3", readAffinity(t, root, 24))
+
+	// Unrelated files are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "benchmarkoor-thp-1.json"), []byte("{}"), 0644))
+
+	stateFiles, err := ListOrphanedStateFiles(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, stateFiles, 1)
+
+	require.NoError(t, CleanupOrphanedIRQState(t.Context(), discardLogger(), stateFiles, root))
+
+	for _, irq := range []int{24, 25, 30} {
+		assert.Equal(t, "0-3", readAffinity(t, root, irq))
+	}
+
+	stateFiles, err = ListOrphanedStateFiles(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, stateFiles)
+}
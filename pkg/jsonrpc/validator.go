@@ -14,6 +14,15 @@ func IsSyncingError(err error) bool {
 	return errors.Is(err, ErrNewPayloadSyncing)
 }
 
+// ErrUnexpectedPayloadStatus is returned when a payload status doesn't match
+// the status a test expects.
+var ErrUnexpectedPayloadStatus = errors.New("unexpected payload status")
+
+// IsUnexpectedStatusError checks if the error is an expectation mismatch.
+func IsUnexpectedStatusError(err error) bool {
+	return errors.Is(err, ErrUnexpectedPayloadStatus)
+}
+
 // Validator validates JSON-RPC responses.
 type Validator interface {
 	Validate(method string, resp *Response) error
@@ -88,6 +97,59 @@ func (v *ForkchoiceUpdatedValidator) Validate(method string, resp *Response) err
 	return nil
 }
 
+// ExpectedPayloadStatusValidator fails if engine_newPayload* and
+// engine_forkchoiceUpdated* responses don't have the expected status, e.g.
+// INVALID for a block a test expects the client to reject.
+type ExpectedPayloadStatusValidator struct {
+	Status string
+}
+
+// Validate checks the payload status against the expected status.
+func (v *ExpectedPayloadStatusValidator) Validate(method string, resp *Response) error {
+	var status PayloadStatus
+
+	switch {
+	case strings.HasPrefix(method, "engine_newPayload"):
+		if err := resp.ParseResult(&status); err != nil {
+			return fmt.Errorf("parsing newPayload result: %w", err)
+		}
+
+		// A client still syncing hasn't decided yet, so it may be retried.
+		if status.Status == "SYNCING" && v.Status != "SYNCING" {
+			return fmt.Errorf("%w", ErrNewPayloadSyncing)
+		}
+	case strings.HasPrefix(method, "engine_forkchoiceUpdated"):
+		var result ForkchoiceUpdatedResult
+		if err := resp.ParseResult(&result); err != nil {
+			return fmt.Errorf("parsing forkchoiceUpdated result: %w", err)
+		}
+
+		status = result.PayloadStatus
+	default:
+		return nil
+	}
+
+	if status.Status != v.Status {
+		errMsg := fmt.Sprintf("status is %s, expected %s", status.Status, v.Status)
+		if status.ValidationError != "" {
+			errMsg = fmt.Sprintf("%s: %s", errMsg, status.ValidationError)
+		}
+
+		return fmt.Errorf("%w: %s", ErrUnexpectedPayloadStatus, errMsg)
+	}
+
+	return nil
+}
+
+// ExpectedStatusValidator returns a composed validator with ErrorValidator
+// and an ExpectedPayloadStatusValidator for status.
+func ExpectedStatusValidator(status string) Validator {
+	return NewComposedValidator(
+		&ErrorValidator{},
+		&ExpectedPayloadStatusValidator{Status: status},
+	)
+}
+
 // ComposedValidator runs multiple validators in sequence.
 type ComposedValidator struct {
 	validators []Validator
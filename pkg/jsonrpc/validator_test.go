@@ -243,3 +243,107 @@ func TestDefaultValidator(t *testing.T) {
 	err = validator.Validate("engine_newPayloadV3", resp)
 	assert.NoError(t, err)
 }
+
+func TestExpectedPayloadStatusValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		expected      string
+		method        string
+		response      string
+		wantErr       bool
+		wantMismatch  bool
+		wantSyncing   bool
+		wantErrSubstr string
+	}{
+		{
+			name:     "newPayload invalid as expected",
+			expected: "INVALID",
+			method:   "engine_newPayloadV4",
+			response: `{"jsonrpc":"2.0","id":1,"result":{"status":"INVALID","validationError":"bad block"}}`,
+		},
+		{
+			name:          "newPayload valid but invalid expected",
+			expected:      "INVALID",
+			method:        "engine_newPayloadV4",
+			response:      `{"jsonrpc":"2.0","id":1,"result":{"status":"VALID"}}`,
+			wantErr:       true,
+			wantMismatch:  true,
+			wantErrSubstr: "status is VALID, expected INVALID",
+		},
+		{
+			name:          "newPayload invalid but valid expected",
+			expected:      "VALID",
+			method:        "engine_newPayloadV3",
+			response:      `{"jsonrpc":"2.0","id":1,"result":{"status":"INVALID","validationError":"bad block"}}`,
+			wantErr:       true,
+			wantMismatch:  true,
+			wantErrSubstr: "status is INVALID, expected VALID: bad block",
+		},
+		{
+			name:        "newPayload syncing",
+			expected:    "INVALID",
+			method:      "engine_newPayloadV3",
+			response:    `{"jsonrpc":"2.0","id":1,"result":{"status":"SYNCING"}}`,
+			wantErr:     true,
+			wantSyncing: true,
+		},
+		{
+			name:     "forkchoiceUpdated invalid as expected",
+			expected: "INVALID",
+			method:   "engine_forkchoiceUpdatedV3",
+			response: `{"jsonrpc":"2.0","id":1,"result":{"payloadStatus":{"status":"INVALID"}}}`,
+		},
+		{
+			name:         "forkchoiceUpdated mismatch",
+			expected:     "INVALID",
+			method:       "engine_forkchoiceUpdatedV3",
+			response:     `{"jsonrpc":"2.0","id":1,"result":{"payloadStatus":{"status":"VALID"}}}`,
+			wantErr:      true,
+			wantMismatch: true,
+		},
+		{
+			name:     "other method",
+			expected: "INVALID",
+			method:   "eth_blockNumber",
+			response: `{"jsonrpc":"2.0","id":1,"result":"0x1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Parse(tt.response)
+			require.NoError(t, err)
+
+			err = (&ExpectedPayloadStatusValidator{Status: tt.expected}).Validate(tt.method, resp)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantMismatch, IsUnexpectedStatusError(err))
+			assert.Equal(t, tt.wantSyncing, IsSyncingError(err))
+
+			if tt.wantErrSubstr != "" {
+				assert.Contains(t, err.Error(), tt.wantErrSubstr)
+			}
+		})
+	}
+}
+
+func TestExpectedStatusValidator(t *testing.T) {
+	validator := ExpectedStatusValidator("INVALID")
+
+	// JSON-RPC errors are transport-level failures, not mismatches.
+	resp, err := Parse(`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params"}}`)
+	require.NoError(t, err)
+
+	err = validator.Validate("engine_newPayloadV3", resp)
+	require.Error(t, err)
+	assert.False(t, IsUnexpectedStatusError(err))
+
+	resp, err = Parse(`{"jsonrpc":"2.0","id":1,"result":{"status":"INVALID"}}`)
+	require.NoError(t, err)
+	assert.NoError(t, validator.Validate("engine_newPayloadV3", resp))
+}
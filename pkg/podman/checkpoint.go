@@ -57,6 +57,13 @@ var _ CheckpointManager = (*manager)(nil)
 // ValidateCheckpointSupport verifies that CRIU is installed and able to
 // perform checkpoint/restore operations on this system.
 func (m *manager) ValidateCheckpointSupport(ctx context.Context) error {
+	if m.mountMode.Rootless {
+		return fmt.Errorf(
+			"checkpoint/restore requires rootful podman; " +
+				"run the podman service as root or use: sudo systemctl start podman.socket",
+		)
+	}
+
 	criuPath, err := exec.LookPath("criu")
 	if err != nil {
 		return fmt.Errorf(
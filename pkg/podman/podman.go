@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	buildahDefine "github.com/containers/buildah/define"
 	"github.com/containers/podman/v5/pkg/bindings"
 	"github.com/containers/podman/v5/pkg/bindings/containers"
 	"github.com/containers/podman/v5/pkg/bindings/images"
@@ -25,6 +28,20 @@ import (
 // DefaultSocket is the default rootful Podman socket path.
 const DefaultSocket = "unix:///run/podman/podman.sock"
 
+// socketURI returns the Podman socket to connect to: the rootful socket if it
+// exists, otherwise the rootless socket of the current user.
+func socketURI() string {
+	if _, err := os.Stat(strings.TrimPrefix(DefaultSocket, "unix://")); err == nil {
+		return DefaultSocket
+	}
+
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return "unix://" + filepath.Join(dir, "podman", "podman.sock")
+	}
+
+	return DefaultSocket
+}
+
 // qualifyImageName ensures the image name is fully qualified for Podman.
 // Docker defaults short names like "ethpandaops/geth:tag" to "docker.io/ethpandaops/geth:tag",
 // but Podman requires fully-qualified names unless unqualified-search registries are configured.
@@ -38,12 +55,114 @@ func qualifyImageName(name string) string {
 	return "docker.io/" + name
 }
 
+// defaultCPUPeriod is the CFS period in microseconds used when only a
+// quota or a fractional CPU count is given, matching Docker's default.
+const defaultCPUPeriod = 100000
+
+// cpuBandwidth converts the CPU bandwidth limits to a CFS quota and period.
+// NanoCPUs take precedence over an explicit quota. A zero quota means no limit.
+func cpuBandwidth(limits *docker.ResourceLimits) (int64, uint64) {
+	if limits.NanoCPUs > 0 {
+		return limits.NanoCPUs * defaultCPUPeriod / 1e9, defaultCPUPeriod
+	}
+
+	if limits.CPUQuota <= 0 {
+		return 0, 0
+	}
+
+	period := uint64(defaultCPUPeriod)
+	if limits.CPUPeriod > 0 {
+		period = uint64(limits.CPUPeriod)
+	}
+
+	return limits.CPUQuota, period
+}
+
+// restartPolicy returns the specgen restart policy and retry limit for the
+// spec. An empty policy means the container is never restarted.
+func restartPolicy(spec *docker.ContainerSpec) (string, *uint) {
+	if spec.RestartPolicy == "" {
+		return "no", nil
+	}
+
+	if spec.RestartRetries <= 0 {
+		return spec.RestartPolicy, nil
+	}
+
+	retries := uint(spec.RestartRetries)
+
+	return spec.RestartPolicy, &retries
+}
+
+// mountMode describes the host properties that change how bind mounts must
+// be set up under Podman.
+type mountMode struct {
+	Rootless bool // Podman runs rootless, in a user namespace.
+	SELinux  bool // SELinux is enforcing labels on the host.
+}
+
+// convertMounts converts mounts to Podman mounts and named volumes.
+// Docker-style "volume" mounts must be mapped to Podman's NamedVolume type;
+// OCI runtimes (crun/runc) don't recognise "volume" as a mount type and would
+// fail with "No such device". Bind mounts are relabeled with "Z" when SELinux
+// is enabled so the container may access them, and writable bind mounts are
+// chowned to the container user with "U" when rootless, as host-owned files
+// are otherwise not writable from the user namespace.
+func convertMounts(mounts []docker.Mount, mode mountMode) ([]specs.Mount, []*specgen.NamedVolume) {
+	var (
+		out     = make([]specs.Mount, 0, len(mounts))
+		volumes []*specgen.NamedVolume
+	)
+
+	for _, mnt := range mounts {
+		if mnt.Type == "volume" {
+			nv := &specgen.NamedVolume{
+				Name: mnt.Source,
+				Dest: mnt.Target,
+			}
+
+			if mnt.ReadOnly {
+				nv.Options = append(nv.Options, "ro")
+			}
+
+			volumes = append(volumes, nv)
+
+			continue
+		}
+
+		m := specs.Mount{
+			Destination: mnt.Target,
+			Source:      mnt.Source,
+			Type:        mnt.Type,
+		}
+
+		if mnt.ReadOnly {
+			m.Options = append(m.Options, "ro")
+		}
+
+		if mnt.Type == "bind" {
+			if mode.SELinux {
+				m.Options = append(m.Options, "Z")
+			}
+
+			if mode.Rootless && !mnt.ReadOnly {
+				m.Options = append(m.Options, "U")
+			}
+		}
+
+		out = append(out, m)
+	}
+
+	return out, volumes
+}
+
 // manager implements docker.ContainerManager using Podman Go bindings.
 type manager struct {
-	log  logrus.FieldLogger
-	conn context.Context // Podman connection context.
-	done chan struct{}
-	wg   sync.WaitGroup
+	log       logrus.FieldLogger
+	conn      context.Context // Podman connection context.
+	mountMode mountMode       // Host-dependent bind mount handling, set by Start.
+	done      chan struct{}
+	wg        sync.WaitGroup
 }
 
 // connWithCtx derives a Podman connection context that carries both the
@@ -86,12 +205,14 @@ func (m *manager) Start(ctx context.Context) error {
 	// store the context inside the connection and use it for every
 	// API call — if we used the caller's ctx here, all Podman
 	// operations would fail after CTRL+C.
-	conn, err := bindings.NewConnection(context.Background(), DefaultSocket)
+	socket := socketURI()
+
+	conn, err := bindings.NewConnection(context.Background(), socket)
 	if err != nil {
 		return fmt.Errorf(
 			"connecting to podman socket (%s): %w\n"+
 				"Ensure the Podman service is running: systemctl start podman.socket",
-			DefaultSocket, err,
+			socket, err,
 		)
 	}
 
@@ -103,16 +224,24 @@ func (m *manager) Start(ctx context.Context) error {
 		return fmt.Errorf("querying podman info: %w", err)
 	}
 
-	if info.Host.Security.Rootless {
-		return fmt.Errorf(
-			"podman is running in rootless mode, but benchmarkoor requires rootful podman; " +
-				"run the podman service as root or use: sudo systemctl start podman.socket",
+	m.mountMode = mountMode{
+		Rootless: info.Host.Security.Rootless,
+		SELinux:  info.Host.Security.SELinuxEnabled,
+	}
+
+	if m.mountMode.Rootless {
+		m.log.Warn(
+			"Podman is running in rootless mode; writable bind mounts are chowned to the " +
+				"container user and container-checkpoint-restore is unavailable",
 		)
 	}
 
 	m.log.WithFields(logrus.Fields{
-		"version": info.Version.Version,
-		"runtime": info.Host.OCIRuntime.Name,
+		"version":  info.Version.Version,
+		"runtime":  info.Host.OCIRuntime.Name,
+		"socket":   socket,
+		"rootless": m.mountMode.Rootless,
+		"selinux":  m.mountMode.SELinux,
 	}).Debug("Connected to Podman daemon")
 
 	return nil
@@ -189,6 +318,7 @@ func (m *manager) CreateContainer(
 	s.Labels = spec.Labels
 	s.User = "root"
 	s.CapAdd = spec.CapAdd
+	s.RestartPolicy, s.RestartRetries = restartPolicy(spec)
 
 	// Map SecurityOpt entries to specgen fields.
 	for _, opt := range spec.SecurityOpt {
@@ -197,6 +327,14 @@ func (m *manager) CreateContainer(
 		}
 	}
 
+	// Podman parses device mappings from the Path field using the same
+	// "host:container:permissions" format as the --device flag.
+	for _, dev := range spec.Devices {
+		s.Devices = append(s.Devices, specs.LinuxDevice{
+			Path: fmt.Sprintf("%s:%s:%s", dev.HostPath, dev.ContainerPath, dev.Permissions),
+		})
+	}
+
 	// Convert env map.
 	if len(spec.Env) > 0 {
 		s.Env = make(map[string]string, len(spec.Env))
@@ -205,40 +343,9 @@ func (m *manager) CreateContainer(
 		}
 	}
 
-	// Convert mounts. Docker-style "volume" mounts must be mapped to Podman's
-	// NamedVolume type; OCI runtimes (crun/runc) don't recognise "volume" as a
-	// mount type and would fail with "No such device".
+	// Convert mounts.
 	if len(spec.Mounts) > 0 {
-		s.Mounts = make([]specs.Mount, 0, len(spec.Mounts))
-
-		for _, mnt := range spec.Mounts {
-			if mnt.Type == "volume" {
-				nv := &specgen.NamedVolume{
-					Name: mnt.Source,
-					Dest: mnt.Target,
-				}
-
-				if mnt.ReadOnly {
-					nv.Options = append(nv.Options, "ro")
-				}
-
-				s.Volumes = append(s.Volumes, nv)
-
-				continue
-			}
-
-			m := specs.Mount{
-				Destination: mnt.Target,
-				Source:      mnt.Source,
-				Type:        mnt.Type,
-			}
-
-			if mnt.ReadOnly {
-				m.Options = append(m.Options, "ro")
-			}
-
-			s.Mounts = append(s.Mounts, m)
-		}
+		s.Mounts, s.Volumes = convertMounts(spec.Mounts, m.mountMode)
 	}
 
 	// Configure network.
@@ -258,6 +365,15 @@ func (m *manager) CreateContainer(
 			}
 		}
 
+		if quota, period := cpuBandwidth(spec.ResourceLimits); quota > 0 {
+			if s.ResourceLimits.CPU == nil {
+				s.ResourceLimits.CPU = &specs.LinuxCPU{}
+			}
+
+			s.ResourceLimits.CPU.Quota = &quota
+			s.ResourceLimits.CPU.Period = &period
+		}
+
 		if spec.ResourceLimits.MemoryBytes > 0 {
 			mem := spec.ResourceLimits.MemoryBytes
 			s.ResourceLimits.Memory = &specs.LinuxMemory{
@@ -269,6 +385,15 @@ func (m *manager) CreateContainer(
 				s.ResourceLimits.Memory.Swap = &swap
 			}
 		}
+
+		if spec.ResourceLimits.MemoryReservation > 0 {
+			if s.ResourceLimits.Memory == nil {
+				s.ResourceLimits.Memory = &specs.LinuxMemory{}
+			}
+
+			reservation := spec.ResourceLimits.MemoryReservation
+			s.ResourceLimits.Memory.Reservation = &reservation
+		}
 	}
 
 	conn, cancel := m.connWithCtx(ctx)
@@ -489,20 +614,97 @@ func (m *manager) GetImageDigest(ctx context.Context, imageName string) (string,
 		return "", fmt.Errorf("inspecting image: %w", err)
 	}
 
-	// RepoDigests contains "image@sha256:hash" format.
-	if len(inspect.RepoDigests) > 0 {
-		digest := inspect.RepoDigests[0]
-		if idx := strings.Index(digest, "sha256:"); idx != -1 {
-			return digest[idx:], nil
-		}
-
-		return digest, nil
+	if digests := docker.RepoDigestHashes(inspect.RepoDigests); len(digests) > 0 {
+		return digests[0], nil
 	}
 
 	// Fallback to image ID.
 	return inspect.ID, nil
 }
 
+// GetImageRepoDigests returns the "sha256:..." digests of all repository
+// references of an image.
+func (m *manager) GetImageRepoDigests(ctx context.Context, imageName string) ([]string, error) {
+	imageName = qualifyImageName(imageName)
+
+	conn, cancel := m.connWithCtx(ctx)
+	defer cancel()
+
+	inspect, err := images.GetImage(conn, imageName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting image: %w", err)
+	}
+
+	return docker.RepoDigestHashes(inspect.RepoDigests), nil
+}
+
+// GetImageLabels returns the labels of a local image.
+func (m *manager) GetImageLabels(ctx context.Context, imageName string) (map[string]string, error) {
+	imageName = qualifyImageName(imageName)
+
+	conn, cancel := m.connWithCtx(ctx)
+	defer cancel()
+
+	inspect, err := images.GetImage(conn, imageName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting image: %w", err)
+	}
+
+	return inspect.Labels, nil
+}
+
+// BuildImage builds an image from spec's build context and tags it. The
+// build output is written to output.
+func (m *manager) BuildImage(ctx context.Context, spec *docker.BuildSpec, output io.Writer) error {
+	tag := qualifyImageName(spec.Tag)
+	log := m.log.WithFields(logrus.Fields{
+		"context": spec.ContextDir,
+		"tag":     tag,
+	})
+
+	conn, cancel := m.connWithCtx(ctx)
+	defer cancel()
+
+	log.Info("Building image")
+
+	// Relative containerfile paths are resolved against the working
+	// directory by the bindings, not the context.
+	dockerfile := filepath.Join(spec.ContextDir, spec.Dockerfile)
+
+	if _, err := images.Build(conn, []string{dockerfile}, entitiesTypes.BuildOptions{
+		BuildOptions: buildahDefine.BuildOptions{
+			ContextDirectory:        spec.ContextDir,
+			Args:                    spec.BuildArgs,
+			Output:                  tag,
+			Out:                     output,
+			Err:                     output,
+			ReportWriter:            output,
+			RemoveIntermediateCtrs:  true,
+			ForceRmIntermediateCtrs: true,
+		},
+	}); err != nil {
+		return fmt.Errorf("building image %s: %w", tag, err)
+	}
+
+	log.Info("Image built successfully")
+
+	return nil
+}
+
+// GetContainerRestartCount returns how often Podman restarted the container
+// under its restart policy.
+func (m *manager) GetContainerRestartCount(ctx context.Context, containerID string) (int, error) {
+	conn, cancel := m.connWithCtx(ctx)
+	defer cancel()
+
+	inspect, err := containers.Inspect(conn, containerID, nil)
+	if err != nil {
+		return 0, fmt.Errorf("inspecting container: %w", err)
+	}
+
+	return int(inspect.RestartCount), nil
+}
+
 // GetContainerIP returns the IP address of a container in the specified network.
 func (m *manager) GetContainerIP(
 	ctx context.Context,
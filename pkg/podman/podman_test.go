@@ -0,0 +1,100 @@
+package podman
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertMounts(t *testing.T) {
+	mounts := []docker.Mount{
+		{Type: "bind", Source: "/tmp/genesis.json", Target: "/genesis.json", ReadOnly: true},
+		{Type: "bind", Source: "/tmp/jwt", Target: "/jwt", ReadOnly: true},
+		{Type: "bind", Source: "/data/geth", Target: "/data"},
+		{Type: "tmpfs", Target: "/scratch"},
+		{Type: "volume", Source: "benchmarkoor-geth", Target: "/volume"},
+	}
+
+	tests := []struct {
+		name string
+		mode mountMode
+		// Expected options per non-volume mount, in order.
+		want [][]string
+	}{
+		{
+			name: "rootful",
+			mode: mountMode{},
+			want: [][]string{{"ro"}, {"ro"}, nil, nil},
+		},
+		{
+			name: "rootful selinux",
+			mode: mountMode{SELinux: true},
+			want: [][]string{{"ro", "Z"}, {"ro", "Z"}, {"Z"}, nil},
+		},
+		{
+			name: "rootless",
+			mode: mountMode{Rootless: true},
+			want: [][]string{{"ro"}, {"ro"}, {"U"}, nil},
+		},
+		{
+			name: "rootless selinux",
+			mode: mountMode{Rootless: true, SELinux: true},
+			want: [][]string{{"ro", "Z"}, {"ro", "Z"}, {"Z", "U"}, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, volumes := convertMounts(mounts, tt.mode)
+
+			require.Len(t, out, len(tt.want))
+
+			for i, m := range out {
+				assert.Equal(t, tt.want[i], m.Options, "mount %s", m.Destination)
+			}
+
+			// Named volumes are managed by Podman and never relabeled.
+			require.Len(t, volumes, 1)
+			assert.Equal(t, "benchmarkoor-geth", volumes[0].Name)
+			assert.Empty(t, volumes[0].Options)
+		})
+	}
+}
+
+func TestSocketURI_Rootless(t *testing.T) {
+	if _, err := os.Stat(strings.TrimPrefix(DefaultSocket, "unix://")); err == nil {
+		t.Skip("rootful podman socket exists on this host")
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+
+	assert.Equal(t, "unix:///run/user/1000/podman/podman.sock", socketURI())
+}
+
+func TestNewManager_InterfaceCompliance(t *testing.T) {
+	mgr, err := NewManager(logrus.New())
+	require.NoError(t, err)
+
+	assert.Implements(t, (*docker.ContainerManager)(nil), mgr)
+	assert.Implements(t, (*CheckpointManager)(nil), mgr)
+}
+
+func TestRestartPolicy(t *testing.T) {
+	policy, retries := restartPolicy(&docker.ContainerSpec{})
+	assert.Equal(t, "no", policy)
+	assert.Nil(t, retries)
+
+	policy, retries = restartPolicy(&docker.ContainerSpec{RestartPolicy: "unless-stopped"})
+	assert.Equal(t, "unless-stopped", policy)
+	assert.Nil(t, retries)
+
+	policy, retries = restartPolicy(&docker.ContainerSpec{RestartPolicy: "on-failure", RestartRetries: 3})
+	assert.Equal(t, "on-failure", policy)
+	require.NotNil(t, retries)
+	assert.Equal(t, uint(3), *retries)
+}
@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
+)
+
+// buildImageRepository is the repository locally built images are tagged
+// under when the instance does not configure an image name.
+const buildImageRepository = "benchmarkoor-build"
+
+// imageBuilder builds container images from a local build context.
+type imageBuilder interface {
+	BuildImage(ctx context.Context, spec *docker.BuildSpec, output io.Writer) error
+}
+
+// buildImageTag returns the tag for an image built for instance: the
+// configured image name, or benchmarkoor-build/<instance id>:latest.
+func buildImageTag(instance *config.ClientInstance) string {
+	if instance.Image != "" {
+		return instance.Image
+	}
+
+	return fmt.Sprintf("%s/%s:latest", buildImageRepository, strings.ToLower(instance.ID))
+}
+
+// buildInstanceImage builds the image configured in instance.Build and
+// returns its tag. The build output is written to build.log in
+// runResultsDir.
+func buildInstanceImage(
+	ctx context.Context,
+	builder imageBuilder,
+	instance *config.ClientInstance,
+	runResultsDir string,
+	owner *fsutil.OwnerConfig,
+) (string, error) {
+	logFile, err := fsutil.Create(filepath.Join(runResultsDir, "build.log"), owner)
+	if err != nil {
+		return "", fmt.Errorf("creating build log file: %w", err)
+	}
+	defer func() { _ = logFile.Close() }()
+
+	tag := buildImageTag(instance)
+
+	if err := builder.BuildImage(ctx, &docker.BuildSpec{
+		ContextDir: instance.Build.Context,
+		Dockerfile: instance.Build.GetDockerfile(),
+		Tag:        tag,
+		BuildArgs:  instance.Build.BuildArgs,
+	}, logFile); err != nil {
+		return "", err
+	}
+
+	return tag, nil
+}
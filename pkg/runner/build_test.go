@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeImageBuilder records the build specs it is asked to build.
+type fakeImageBuilder struct {
+	specs []*docker.BuildSpec
+	err   error
+}
+
+func (f *fakeImageBuilder) BuildImage(_ context.Context, spec *docker.BuildSpec, output io.Writer) error {
+	f.specs = append(f.specs, spec)
+
+	_, _ = fmt.Fprintf(output, "Successfully tagged %s\n", spec.Tag)
+
+	return f.err
+}
+
+func TestBuildImageTag(t *testing.T) {
+	assert.Equal(t, "benchmarkoor-build/geth-wip:latest",
+		buildImageTag(&config.ClientInstance{ID: "Geth-WIP"}))
+	assert.Equal(t, "geth:wip",
+		buildImageTag(&config.ClientInstance{ID: "geth", Image: "geth:wip"}))
+}
+
+func TestBuildInstanceImage(t *testing.T) {
+	resultsDir := t.TempDir()
+	builder := &fakeImageBuilder{}
+
+	instance := &config.ClientInstance{
+		ID:     "geth-wip",
+		Client: "geth",
+		Build: &config.BuildConfig{
+			Context: "/src/go-ethereum",
+			BuildArgs: map[string]string{
+				"COMMIT": "4f2a9c1",
+			},
+		},
+	}
+
+	tag, err := buildInstanceImage(context.Background(), builder, instance, resultsDir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "benchmarkoor-build/geth-wip:latest", tag)
+
+	require.Len(t, builder.specs, 1)
+	assert.Equal(t, &docker.BuildSpec{
+		ContextDir: "/src/go-ethereum",
+		Dockerfile: "Dockerfile",
+		Tag:        tag,
+		BuildArgs:  map[string]string{"COMMIT": "4f2a9c1"},
+	}, builder.specs[0])
+
+	// The build output is kept with the run results.
+	out, err := os.ReadFile(filepath.Join(resultsDir, "build.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "Successfully tagged "+tag)
+}
+
+func TestBuildInstanceImage_Error(t *testing.T) {
+	builder := &fakeImageBuilder{err: errors.New("step 3/7 failed")}
+
+	instance := &config.ClientInstance{
+		ID:    "geth",
+		Build: &config.BuildConfig{Context: "/src/go-ethereum", Dockerfile: "Dockerfile.dev"},
+	}
+
+	_, err := buildInstanceImage(context.Background(), builder, instance, t.TempDir(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "step 3/7 failed")
+
+	require.Len(t, builder.specs, 1)
+	assert.Equal(t, "Dockerfile.dev", builder.specs[0].Dockerfile)
+}
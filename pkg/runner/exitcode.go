@@ -0,0 +1,74 @@
+package runner
+
+// Process exit codes of the run command, so CI can branch on why a run
+// did not succeed.
+const (
+	ExitCodeSuccess         = 0
+	ExitCodeFailure         = 1   // Generic failure, e.g. an instance failed to start.
+	ExitCodeConfigError     = 2   // The config could not be loaded.
+	ExitCodeValidationError = 3   // The config failed validation.
+	ExitCodeContainerDied   = 4   // A client container exited or restarted mid-run.
+	ExitCodeTestsFailed     = 5   // All runs completed, but some tests failed.
+	ExitCodeCancelled       = 130 // The run was interrupted (128 + SIGINT).
+)
+
+// exitCodeSeverity orders exit codes from least to most severe when
+// several runs end differently.
+var exitCodeSeverity = map[int]int{
+	ExitCodeSuccess:         0,
+	ExitCodeTestsFailed:     1,
+	ExitCodeFailure:         2,
+	ExitCodeContainerDied:   3,
+	ExitCodeValidationError: 4,
+	ExitCodeConfigError:     5,
+	ExitCodeCancelled:       6,
+}
+
+// ExitCodeForStatus maps a run status to an exit code. A completed run with
+// failed tests maps to ExitCodeTestsFailed.
+func ExitCodeForStatus(status string, failedTests int) int {
+	switch status {
+	case RunStatusCompleted:
+		if failedTests > 0 {
+			return ExitCodeTestsFailed
+		}
+
+		return ExitCodeSuccess
+	case RunStatusContainerDied:
+		return ExitCodeContainerDied
+	case RunStatusCancelled:
+		return ExitCodeCancelled
+	default:
+		return ExitCodeFailure
+	}
+}
+
+// WorseExitCode returns the more severe of two exit codes.
+func WorseExitCode(a, b int) int {
+	if exitCodeSeverity[b] > exitCodeSeverity[a] {
+		return b
+	}
+
+	return a
+}
+
+// recordRunOutcome folds a finished run's status into the runner's exit code.
+func (r *runner) recordRunOutcome(runConfig *RunConfig) {
+	var failedTests int
+	if runConfig.TestCounts != nil {
+		failedTests = runConfig.TestCounts.Failed
+	}
+
+	r.exitCodeMu.Lock()
+	defer r.exitCodeMu.Unlock()
+
+	r.exitCode = WorseExitCode(r.exitCode, ExitCodeForStatus(runConfig.Status, failedTests))
+}
+
+// ExitCode returns the most severe exit code of all runs so far.
+func (r *runner) ExitCode() int {
+	r.exitCodeMu.Lock()
+	defer r.exitCodeMu.Unlock()
+
+	return r.exitCode
+}
@@ -0,0 +1,210 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/client"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/executor"
+	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// errGenesisGroupFailed is the cause of the cancellation of the genesis
+// groups still running when another group of the run fails.
+var errGenesisGroupFailed = errors.New("another genesis group failed")
+
+// runGenesisGroups runs the tests of each genesis group in its own container
+// lifecycle, started from the group's genesis. Up to
+// Config.GenesisGroupParallelism groups run at once; the first group to fail
+// cancels the others with errGenesisGroupFailed. base holds the parameters
+// shared by all groups.
+func (r *runner) runGenesisGroups(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ggp executor.GenesisGroupProvider,
+	groups []*executor.GenesisGroup,
+	base *containerRunParams,
+	spec client.Spec,
+	datadirCfg *config.DataDirConfig,
+	useDataDir bool,
+) error {
+	instance := base.Instance
+
+	genesisGroups := make(map[string]string, len(groups))
+	for _, group := range groups {
+		genesisGroups[group.GenesisHash] = ggp.GetGenesisPathForGroup(
+			group.GenesisHash, instance.Client,
+		)
+	}
+
+	// Resolve every group before starting any, so a missing genesis file
+	// fails the instance before containers are created.
+	runs := make([]*containerRunParams, 0, len(groups))
+
+	for _, group := range groups {
+		groupTests := group.Tests
+		if r.cfg.RerunTests != nil {
+			groupTests = executor.SelectTests(groupTests, r.cfg.RerunTests)
+			if len(groupTests) == 0 {
+				continue
+			}
+		}
+
+		groupGenesis := genesisGroups[group.GenesisHash]
+		if groupGenesis == "" {
+			return fmt.Errorf(
+				"no genesis file for group %s and client %s",
+				group.GenesisHash, instance.Client,
+			)
+		}
+
+		params := *base
+		params.GenesisSource = groupGenesis
+		params.Tests = groupTests
+		params.GenesisGroupHash = group.GenesisHash
+		params.GenesisGroups = genesisGroups
+
+		if r.cfg.VerifyGenesisHash {
+			if group.GenesisBlockHash == "" {
+				log.WithField("genesis_hash", group.GenesisHash).Warn(
+					"Genesis block hash of group unknown, not verifying it",
+				)
+			}
+
+			params.GenesisBlockHash = group.GenesisBlockHash
+		}
+
+		runs = append(runs, &params)
+	}
+
+	parallelism := max(r.cfg.GenesisGroupParallelism, 1)
+	if parallelism > 1 {
+		log.WithField("parallelism", parallelism).Info("Running genesis groups in parallel")
+	}
+
+	// Counts and result files are shared by all groups of the run.
+	shared := &sharedRunResults{}
+
+	gCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var g errgroup.Group
+
+	g.SetLimit(parallelism)
+
+	for i, params := range runs {
+		params.SharedResults = shared
+
+		g.Go(func() error {
+			// Don't start further groups once one has failed.
+			if err := gCtx.Err(); err != nil {
+				return err
+			}
+
+			log.WithFields(logrus.Fields{
+				"group":        i + 1,
+				"total_groups": len(runs),
+				"genesis_hash": params.GenesisGroupHash,
+				"tests":        len(params.Tests),
+			}).Info("Running genesis group")
+
+			if err := r.runContainerLifecycle(
+				gCtx, params, spec, datadirCfg, useDataDir,
+			); err != nil {
+				cancel(errGenesisGroupFailed)
+
+				return fmt.Errorf(
+					"running genesis group %s: %w",
+					params.GenesisGroupHash, err,
+				)
+			}
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// sharedRunResults holds the results shared by the genesis groups of a run,
+// which all write into the same run directory and may run concurrently.
+type sharedRunResults struct {
+	mu         sync.Mutex
+	testCounts TestCounts
+
+	// outcome is the run config of the group whose status is the most
+	// severe so far. Its status, termination reason and container exit
+	// details stand for the whole run.
+	outcome *RunConfig
+}
+
+// addTestCounts adds the counts of a group's execution result and returns
+// the counts of all groups so far. suiteTotal is the number of tests in the
+// suite.
+func (s *sharedRunResults) addTestCounts(suiteTotal int, result *executor.ExecutionResult) *TestCounts {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.testCounts.Total = suiteTotal
+	s.testCounts.Passed += result.Passed
+	s.testCounts.Failed += result.Failed
+	s.testCounts.Skipped += result.Skipped
+
+	counts := s.testCounts
+
+	return &counts
+}
+
+// writeRunConfig writes the run config of a group. Writes are serialized
+// and carry the counts of all groups so far, so the group that finishes last
+// leaves the complete counts behind. They also carry the most severe status
+// of all groups so far, the first one on a tie, so a group finishing later
+// can't hide the failure of another.
+func (s *sharedRunResults) writeRunConfig(resultsDir string, cfg *RunConfig, owner *fsutil.OwnerConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cfg.TestCounts != nil {
+		counts := s.testCounts
+		cfg.TestCounts = &counts
+	}
+
+	if cfg.Status != "" &&
+		(s.outcome == nil || statusSeverity(cfg.Status) > statusSeverity(s.outcome.Status)) {
+		outcome := *cfg
+		s.outcome = &outcome
+	}
+
+	if s.outcome == nil {
+		return writeRunConfig(resultsDir, cfg, owner)
+	}
+
+	merged := *cfg
+	merged.Status = s.outcome.Status
+	merged.TerminationReason = s.outcome.TerminationReason
+	merged.ContainerExitCode = s.outcome.ContainerExitCode
+	merged.ContainerOOMKilled = s.outcome.ContainerOOMKilled
+
+	return writeRunConfig(resultsDir, &merged, owner)
+}
+
+// statusSeverity orders run statuses like the exit codes they map to.
+func statusSeverity(status string) int {
+	return exitCodeSeverity[ExitCodeForStatus(status, 0)]
+}
+
+// writeBlockLogs merges a group's block logs into the run's block logs file.
+func (s *sharedRunResults) writeBlockLogs(
+	resultsDir string, blockLogs map[string]json.RawMessage, owner *fsutil.OwnerConfig,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return executor.WriteBlockLogsResult(resultsDir, blockLogs, owner)
+}
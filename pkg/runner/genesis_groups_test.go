@@ -0,0 +1,330 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/client"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGroupManager is a container manager whose container creation waits
+// until concurrent creations are in flight, or a timeout, and then fails,
+// ending the lifecycle. It records the peak number of concurrent creations
+// and the container and volume names.
+type fakeGroupManager struct {
+	docker.ContainerManager
+
+	concurrent int
+	ready      chan struct{}
+	readyOnce  sync.Once
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	containers  []string
+	volumes     []string
+}
+
+func (f *fakeGroupManager) CreateVolume(_ context.Context, name string, _ map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.volumes = append(f.volumes, name)
+
+	return nil
+}
+
+func (f *fakeGroupManager) RemoveVolume(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeGroupManager) CreateContainer(_ context.Context, spec *docker.ContainerSpec) (string, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	f.containers = append(f.containers, spec.Name)
+
+	if f.inFlight >= f.concurrent {
+		f.readyOnce.Do(func() { close(f.ready) })
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-f.ready:
+	case <-time.After(time.Second):
+	}
+
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+
+	return "", errContainerCreation
+}
+
+// fakeGenesisGroups provides the same genesis file for every group.
+type fakeGenesisGroups struct {
+	path string
+}
+
+func (f *fakeGenesisGroups) GetGenesisGroups() []*executor.GenesisGroup {
+	return nil
+}
+
+func (f *fakeGenesisGroups) GetGenesisPathForGroup(_, _ string) string {
+	return f.path
+}
+
+func TestRunGenesisGroups_Parallelism(t *testing.T) {
+	tests := []struct {
+		name           string
+		parallelism    int
+		wantConcurrent int
+		wantContainers []string
+	}{
+		{
+			// The first failing group stops the others.
+			name:           "sequential",
+			wantConcurrent: 1,
+			wantContainers: []string{"benchmarkoor-a1b2c3d4-reth-1-0x01"},
+		},
+		{
+			// The third group does not start once the first two failed.
+			name:           "parallel",
+			parallelism:    2,
+			wantConcurrent: 2,
+			wantContainers: []string{
+				"benchmarkoor-a1b2c3d4-reth-1-0x01",
+				"benchmarkoor-a1b2c3d4-reth-1-0x02",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			genesis := filepath.Join(t.TempDir(), "genesis.json")
+			require.NoError(t, os.WriteFile(genesis, []byte(`{}`), 0o644))
+
+			mgr := &fakeGroupManager{concurrent: tt.wantConcurrent, ready: make(chan struct{})}
+			instance := &config.ClientInstance{ID: "reth-1", Client: "reth"}
+
+			r := &runner{
+				log:          discardLogger(),
+				containerMgr: mgr,
+				cfg: &Config{
+					TmpCacheDir:             t.TempDir(),
+					JWT:                     config.DefaultJWT,
+					GenesisGroupParallelism: tt.parallelism,
+					FullConfig: &config.Config{
+						Runner: config.RunnerConfig{Instances: []config.ClientInstance{*instance}},
+					},
+				},
+			}
+
+			groups := []*executor.GenesisGroup{
+				{GenesisHash: "0x01"},
+				{GenesisHash: "0x02"},
+				{GenesisHash: "0x03"},
+			}
+
+			err := r.runGenesisGroups(t.Context(), discardLogger(), &fakeGenesisGroups{path: genesis},
+				groups, &containerRunParams{
+					Instance:      instance,
+					RunID:         "a1b2c3d4",
+					RunResultsDir: t.TempDir(),
+					ImageName:     "ghcr.io/paradigmxyz/reth:latest",
+				}, client.NewRethSpec(), nil, false)
+			require.ErrorIs(t, err, errContainerCreation)
+
+			assert.Equal(t, tt.wantConcurrent, mgr.maxInFlight)
+			assert.ElementsMatch(t, tt.wantContainers, mgr.containers)
+
+			// Each group gets its own volume.
+			assert.Len(t, mgr.volumes, len(tt.wantContainers))
+			assert.Len(t, uniqueStrings(mgr.volumes), len(mgr.volumes))
+		})
+	}
+}
+
+func TestRunGenesisGroups_MissingGenesis(t *testing.T) {
+	mgr := &fakeGroupManager{concurrent: 1, ready: make(chan struct{})}
+	instance := &config.ClientInstance{ID: "reth-1", Client: "reth"}
+
+	r := &runner{log: discardLogger(), containerMgr: mgr, cfg: &Config{GenesisGroupParallelism: 2}}
+
+	err := r.runGenesisGroups(t.Context(), discardLogger(), &fakeGenesisGroups{},
+		[]*executor.GenesisGroup{{GenesisHash: "0x01"}},
+		&containerRunParams{Instance: instance}, client.NewRethSpec(), nil, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no genesis file for group 0x01 and client reth")
+	assert.Empty(t, mgr.containers, "no container is created")
+}
+
+func TestSharedRunResults(t *testing.T) {
+	dir := t.TempDir()
+	shared := &sharedRunResults{}
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			cfg := &RunConfig{
+				Status:     RunStatusCompleted,
+				TestCounts: shared.addTestCounts(10, &executor.ExecutionResult{Passed: 1, Failed: 1}),
+			}
+			assert.NoError(t, shared.writeRunConfig(dir, cfg, nil))
+		}()
+	}
+
+	wg.Wait()
+
+	// The last write carries the counts of all groups.
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+
+	var cfg RunConfig
+	require.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, &TestCounts{Total: 10, Passed: 8, Failed: 8}, cfg.TestCounts)
+
+	// No temporary files are left behind.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestSharedRunResults_Status(t *testing.T) {
+	dir := t.TempDir()
+	shared := &sharedRunResults{}
+
+	exitCode := int64(137)
+	oomKilled := true
+
+	writes := []*RunConfig{
+		{Status: RunStatusCompleted},
+		{
+			Status:             RunStatusContainerDied,
+			TerminationReason:  "container exited during test execution",
+			ContainerExitCode:  &exitCode,
+			ContainerOOMKilled: &oomKilled,
+		},
+		// A group stopped after the failure doesn't replace it, nor does a
+		// later failure of the same severity.
+		{Status: RunStatusFailed, TerminationReason: "stopped after another genesis group failed"},
+		{Status: RunStatusContainerDied, TerminationReason: "container exited while waiting for RPC"},
+		{Status: RunStatusCompleted},
+	}
+
+	for _, cfg := range writes {
+		require.NoError(t, shared.writeRunConfig(dir, cfg, nil))
+
+		data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+		require.NoError(t, err)
+
+		var written RunConfig
+		require.NoError(t, json.Unmarshal(data, &written))
+
+		if cfg == writes[0] {
+			assert.Equal(t, RunStatusCompleted, written.Status)
+
+			continue
+		}
+
+		assert.Equal(t, RunStatusContainerDied, written.Status)
+		assert.Equal(t, "container exited during test execution", written.TerminationReason)
+		assert.Equal(t, &exitCode, written.ContainerExitCode)
+		assert.Equal(t, &oomKilled, written.ContainerOOMKilled)
+	}
+
+	// The group's own run config is left as is.
+	assert.Equal(t, RunStatusCompleted, writes[4].Status)
+}
+
+// causeRecordingManager fails the container creation of group 0x01 once the
+// creation of group 0x02 is in flight, and records the cause of the
+// cancellation the latter sees.
+type causeRecordingManager struct {
+	docker.ContainerManager
+
+	secondStarted chan struct{}
+	cause         error
+}
+
+func (f *causeRecordingManager) CreateVolume(_ context.Context, _ string, _ map[string]string) error {
+	return nil
+}
+
+func (f *causeRecordingManager) RemoveVolume(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *causeRecordingManager) CreateContainer(ctx context.Context, spec *docker.ContainerSpec) (string, error) {
+	if strings.HasSuffix(spec.Name, "0x01") {
+		<-f.secondStarted
+
+		return "", errContainerCreation
+	}
+
+	close(f.secondStarted)
+	<-ctx.Done()
+	f.cause = context.Cause(ctx)
+
+	return "", ctx.Err()
+}
+
+func TestRunGenesisGroups_FailureCancelsOthers(t *testing.T) {
+	genesis := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(genesis, []byte(`{}`), 0o644))
+
+	mgr := &causeRecordingManager{secondStarted: make(chan struct{})}
+	instance := &config.ClientInstance{ID: "reth-1", Client: "reth"}
+
+	r := &runner{
+		log:          discardLogger(),
+		containerMgr: mgr,
+		cfg: &Config{
+			TmpCacheDir:             t.TempDir(),
+			JWT:                     config.DefaultJWT,
+			GenesisGroupParallelism: 2,
+			FullConfig: &config.Config{
+				Runner: config.RunnerConfig{Instances: []config.ClientInstance{*instance}},
+			},
+		},
+	}
+
+	err := r.runGenesisGroups(t.Context(), discardLogger(), &fakeGenesisGroups{path: genesis},
+		[]*executor.GenesisGroup{{GenesisHash: "0x01"}, {GenesisHash: "0x02"}},
+		&containerRunParams{
+			Instance:      instance,
+			RunID:         "a1b2c3d4",
+			RunResultsDir: t.TempDir(),
+			ImageName:     "ghcr.io/paradigmxyz/reth:latest",
+		}, client.NewRethSpec(), nil, false)
+	require.ErrorIs(t, err, errContainerCreation)
+
+	// The other group is told apart from a user cancellation.
+	require.ErrorIs(t, mgr.cause, errGenesisGroupFailed)
+}
+
+// uniqueStrings returns the distinct values of s.
+func uniqueStrings(s []string) map[string]struct{} {
+	seen := make(map[string]struct{}, len(s))
+	for _, v := range s {
+		seen[v] = struct{}{}
+	}
+
+	return seen
+}
@@ -0,0 +1,212 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// hookCommandTimeout bounds how long a pre-run or post-run command may
+	// run.
+	hookCommandTimeout = 10 * time.Minute
+
+	// preRunCommandLog is the file in the run directory receiving the
+	// output of the pre-run command.
+	preRunCommandLog = "pre_run_command.log"
+
+	// postRunCommandLogDir is the directory in the results dir receiving
+	// the output of the post-run command, one <run dir name>.log per run.
+	// It is kept outside the run directory, which the command may archive
+	// or move, and which has already been uploaded when it runs.
+	postRunCommandLogDir = "post_run_command"
+)
+
+// postRunInfo describes a finished run to the post-run command.
+type postRunInfo struct {
+	RunID    string
+	RunDir   string
+	Instance *config.ClientInstance
+	Status   string
+}
+
+// postRunCommand returns the configured post-run command, nil if none is
+// configured, and whether its failure fails the run.
+func (r *runner) postRunCommand() ([]string, bool) {
+	if r.cfg.FullConfig == nil {
+		return nil, false
+	}
+
+	return r.cfg.FullConfig.Runner.PostRunCommand, r.cfg.FullConfig.Runner.PostRunCommandRequired
+}
+
+// runPreRunCommand runs the pre-run command of an instance before its
+// container is created. Its output is appended to pre_run_command.log in the
+// run directory, once per container for multi-genesis runs. A failing
+// command aborts the instance.
+func (r *runner) runPreRunCommand(ctx context.Context, params *containerRunParams) error {
+	if r.cfg.FullConfig == nil {
+		return nil
+	}
+
+	command := r.cfg.FullConfig.GetPreRunCommand(params.Instance)
+	if len(command) == 0 {
+		return nil
+	}
+
+	r.log.WithFields(logrus.Fields{
+		"instance": params.Instance.ID,
+		"run_id":   params.RunID,
+		"command":  command[0],
+	}).Info("Running pre-run command")
+
+	env := append(hookEnv(r.cfg.ResultsDir, params.RunResultsDir, params.RunID, params.Instance),
+		"BENCHMARKOOR_IMAGE="+params.ImageName,
+		"BENCHMARKOOR_GENESIS_GROUP="+params.GenesisGroupHash,
+	)
+
+	if err := execHookCommand(
+		ctx, command, params.RunResultsDir, filepath.Join(params.RunResultsDir, preRunCommandLog),
+		true, env, r.cfg.ResultsOwner,
+	); err != nil {
+		return fmt.Errorf("pre_run_command: %w", err)
+	}
+
+	return nil
+}
+
+// runPostRunCommand runs the configured post-run command for a finished run.
+// Its output is written to post_run_command/<run dir name>.log in the results
+// dir (see postRunCommandLog). A failing command is logged and only returned
+// as an error when post_run_command_required is set.
+func (r *runner) runPostRunCommand(info *postRunInfo) error {
+	command, required := r.postRunCommand()
+	if len(command) == 0 {
+		return nil
+	}
+
+	log := r.log.WithFields(logrus.Fields{
+		"instance": info.Instance.ID,
+		"run_id":   info.RunID,
+		"status":   info.Status,
+	})
+	log.WithField("command", command[0]).Info("Running post-run command")
+
+	env := append(hookEnv(r.cfg.ResultsDir, info.RunDir, info.RunID, info.Instance),
+		"BENCHMARKOOR_STATUS="+info.Status,
+	)
+
+	logPath := postRunCommandLog(r.cfg.ResultsDir, info.RunDir)
+	if err := fsutil.MkdirAll(filepath.Dir(logPath), 0755, r.cfg.ResultsOwner); err != nil {
+		return fmt.Errorf("post_run_command: creating log directory: %w", err)
+	}
+
+	// Use a fresh context so the command also runs after the run was
+	// cancelled.
+	if err := execHookCommand(
+		context.Background(), command, info.RunDir, logPath, false, env, r.cfg.ResultsOwner,
+	); err != nil {
+		if required {
+			return fmt.Errorf("post_run_command: %w", err)
+		}
+
+		log.WithError(err).Warn("Post-run command failed")
+
+		return nil
+	}
+
+	log.Info("Post-run command completed")
+
+	return nil
+}
+
+// postRunCommandLog returns the path of the post-run command log of the run
+// in runDir.
+func postRunCommandLog(resultsDir, runDir string) string {
+	return filepath.Join(resultsDir, postRunCommandLogDir, filepath.Base(runDir)+".log")
+}
+
+// hookEnv returns the BENCHMARKOOR_* environment variables describing a run
+// to a hook command.
+func hookEnv(resultsDir, runDir, runID string, instance *config.ClientInstance) []string {
+	return []string{
+		"BENCHMARKOOR_RESULTS_DIR=" + resultsDir,
+		"BENCHMARKOOR_RUN_DIR=" + runDir,
+		"BENCHMARKOOR_RUN_ID=" + runID,
+		"BENCHMARKOOR_INSTANCE=" + instance.ID,
+		"BENCHMARKOOR_CLIENT=" + instance.Client,
+	}
+}
+
+// execHookCommand runs command in runDir with env added to the environment,
+// writing its output to logPath. The log is appended to when appendLog is
+// set and truncated otherwise.
+func execHookCommand(
+	ctx context.Context,
+	command []string,
+	runDir, logPath string,
+	appendLog bool,
+	env []string,
+	owner *fsutil.OwnerConfig,
+) error {
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendLog {
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	logFile, err := fsutil.OpenFile(logPath, flag, 0644, owner)
+	if err != nil {
+		return fmt.Errorf("creating log file: %w", err)
+	}
+	defer func() { _ = logFile.Close() }()
+
+	ctx, cancel := context.WithTimeout(ctx, hookCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...) //nolint:gosec // command comes from the config
+	cmd.Dir = runDir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = append(os.Environ(), env...)
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s", hookCommandTimeout)
+		}
+
+		return fmt.Errorf("%w (output in %s)", err, logPath)
+	}
+
+	return nil
+}
+
+// runStatus returns the status recorded in the config.json of a run
+// directory. Runs that ended before writing it are reported as failed when
+// runErr is set and as completed otherwise.
+func runStatus(runDir string, runErr error) string {
+	data, err := os.ReadFile(filepath.Join(runDir, "config.json"))
+	if err == nil {
+		var runConfig struct {
+			Status string `json:"status"`
+		}
+
+		if json.Unmarshal(data, &runConfig) == nil && runConfig.Status != "" {
+			return runConfig.Status
+		}
+	}
+
+	if runErr != nil {
+		return RunStatusFailed
+	}
+
+	return RunStatusCompleted
+}
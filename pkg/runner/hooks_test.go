@@ -0,0 +1,239 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/client"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPostRunRunner(t *testing.T, command []string, required bool) (*runner, *postRunInfo) {
+	t.Helper()
+
+	resultsDir := t.TempDir()
+	runDir := filepath.Join(resultsDir, "runs", "1718000000_a1b2c3d4_geth")
+	require.NoError(t, os.MkdirAll(runDir, 0o755))
+
+	r := &runner{
+		log: discardLogger(),
+		cfg: &Config{
+			ResultsDir: resultsDir,
+			FullConfig: &config.Config{Runner: config.RunnerConfig{
+				PostRunCommand:         command,
+				PostRunCommandRequired: required,
+			}},
+		},
+	}
+
+	info := &postRunInfo{
+		RunID:    "a1b2c3d4",
+		RunDir:   runDir,
+		Instance: &config.ClientInstance{ID: "geth-latest", Client: "geth"},
+		Status:   RunStatusCompleted,
+	}
+
+	return r, info
+}
+
+func TestRunPostRunCommand_Env(t *testing.T) {
+	r, info := newPostRunRunner(t, []string{"sh", "-c", `env | grep ^BENCHMARKOOR_ | sort; pwd`}, false)
+
+	require.NoError(t, r.runPostRunCommand(info))
+
+	out, err := os.ReadFile(postRunCommandLog(r.cfg.ResultsDir, info.RunDir))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	assert.Equal(t, []string{
+		"BENCHMARKOOR_CLIENT=geth",
+		"BENCHMARKOOR_INSTANCE=geth-latest",
+		"BENCHMARKOOR_RESULTS_DIR=" + r.cfg.ResultsDir,
+		"BENCHMARKOOR_RUN_DIR=" + info.RunDir,
+		"BENCHMARKOOR_RUN_ID=a1b2c3d4",
+		"BENCHMARKOOR_STATUS=completed",
+		info.RunDir,
+	}, lines)
+
+	// The log stays out of the run directory, which the command may move.
+	assert.Equal(t,
+		filepath.Join(r.cfg.ResultsDir, "post_run_command", "1718000000_a1b2c3d4_geth.log"),
+		postRunCommandLog(r.cfg.ResultsDir, info.RunDir),
+	)
+	assert.NoFileExists(t, filepath.Join(info.RunDir, "post_run_command.log"))
+}
+
+func TestRunPostRunCommand_Failure(t *testing.T) {
+	command := []string{"sh", "-c", "echo compress failed >&2; exit 3"}
+
+	t.Run("optional", func(t *testing.T) {
+		r, info := newPostRunRunner(t, command, false)
+		require.NoError(t, r.runPostRunCommand(info))
+
+		out, err := os.ReadFile(postRunCommandLog(r.cfg.ResultsDir, info.RunDir))
+		require.NoError(t, err)
+		assert.Equal(t, "compress failed\n", string(out))
+	})
+
+	t.Run("required", func(t *testing.T) {
+		r, info := newPostRunRunner(t, command, true)
+
+		err := r.runPostRunCommand(info)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "post_run_command: exit status 3")
+	})
+}
+
+func TestRunPostRunCommand_TruncatesLog(t *testing.T) {
+	r, info := newPostRunRunner(t, []string{"sh", "-c", "echo $BENCHMARKOOR_STATUS"}, false)
+
+	require.NoError(t, r.runPostRunCommand(info))
+
+	info.Status = RunStatusFailed
+	require.NoError(t, r.runPostRunCommand(info))
+
+	out, err := os.ReadFile(postRunCommandLog(r.cfg.ResultsDir, info.RunDir))
+	require.NoError(t, err)
+	assert.Equal(t, "failed\n", string(out))
+}
+
+func TestRunPostRunCommand_NotConfigured(t *testing.T) {
+	r, info := newPostRunRunner(t, nil, false)
+	require.NoError(t, r.runPostRunCommand(info))
+	assert.NoDirExists(t, filepath.Join(r.cfg.ResultsDir, postRunCommandLogDir))
+}
+
+func TestRunStatus(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.Equal(t, RunStatusCompleted, runStatus(dir, nil))
+	assert.Equal(t, RunStatusFailed, runStatus(dir, errors.New("pulling image")))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"status": "container_died"}`), 0o644))
+	assert.Equal(t, RunStatusContainerDied, runStatus(dir, nil))
+}
+
+// fakeLifecycleManager is a container manager that records the container
+// spec, appends container creation to an events file and fails it, ending the
+// lifecycle before anything is started.
+type fakeLifecycleManager struct {
+	docker.ContainerManager
+
+	events string
+	spec   *docker.ContainerSpec
+}
+
+var errContainerCreation = errors.New("container creation not supported")
+
+func (f *fakeLifecycleManager) CreateVolume(_ context.Context, _ string, _ map[string]string) error {
+	return nil
+}
+
+func (f *fakeLifecycleManager) RemoveVolume(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeLifecycleManager) CreateContainer(_ context.Context, spec *docker.ContainerSpec) (string, error) {
+	f.spec = spec
+
+	events, err := os.OpenFile(f.events, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = events.Close() }()
+
+	if _, err := events.WriteString("create container\n"); err != nil {
+		return "", err
+	}
+
+	return "", errContainerCreation
+}
+
+// runPreRunLifecycle runs the container lifecycle of an instance with the
+// given pre-run command up to container creation. The command can append
+// lines to the events file at $EVENTS, which are returned together with the
+// run directory and the lifecycle error.
+func runPreRunLifecycle(t *testing.T, command []string) ([]string, string, error) {
+	t.Helper()
+
+	resultsDir := t.TempDir()
+	runDir := filepath.Join(resultsDir, "runs", "run1")
+	require.NoError(t, os.MkdirAll(runDir, 0o755))
+
+	genesis := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(genesis, []byte(`{}`), 0o644))
+
+	events := filepath.Join(t.TempDir(), "events")
+	t.Setenv("EVENTS", events)
+
+	instance := &config.ClientInstance{ID: "reth-1", Client: "reth", PreRunCommand: command}
+
+	r := &runner{
+		log:          discardLogger(),
+		containerMgr: &fakeLifecycleManager{events: events},
+		cfg: &Config{
+			ResultsDir:  resultsDir,
+			TmpCacheDir: t.TempDir(),
+			JWT:         config.DefaultJWT,
+			FullConfig:  &config.Config{Runner: config.RunnerConfig{Instances: []config.ClientInstance{*instance}}},
+		},
+	}
+
+	params := &containerRunParams{
+		Instance:      instance,
+		RunID:         "a1b2c3d4",
+		RunResultsDir: runDir,
+		GenesisSource: genesis,
+		ImageName:     "ghcr.io/paradigmxyz/reth:latest",
+	}
+
+	err := r.runContainerLifecycle(context.Background(), params, client.NewRethSpec(), nil, false)
+
+	data, readErr := os.ReadFile(events)
+	if readErr != nil && !os.IsNotExist(readErr) {
+		require.NoError(t, readErr)
+	}
+
+	lines := strings.FieldsFunc(string(data), func(r rune) bool { return r == '\n' })
+
+	return lines, runDir, err
+}
+
+func TestRunContainerLifecycle_PreRunCommand(t *testing.T) {
+	t.Run("runs before the container is created", func(t *testing.T) {
+		events, runDir, err := runPreRunLifecycle(t, []string{
+			"sh", "-c", `echo "pre-run $BENCHMARKOOR_INSTANCE $BENCHMARKOOR_CLIENT $BENCHMARKOOR_IMAGE" >> "$EVENTS"; echo warmed`,
+		})
+		require.ErrorIs(t, err, errContainerCreation)
+		assert.Equal(t, []string{
+			"pre-run reth-1 reth ghcr.io/paradigmxyz/reth:latest",
+			"create container",
+		}, events)
+
+		out, err := os.ReadFile(filepath.Join(runDir, preRunCommandLog))
+		require.NoError(t, err)
+		assert.Equal(t, "warmed\n", string(out))
+	})
+
+	t.Run("failure aborts the instance", func(t *testing.T) {
+		events, _, err := runPreRunLifecycle(t, []string{"sh", "-c", `echo pre-run >> "$EVENTS"; exit 1`})
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, errContainerCreation)
+		assert.Contains(t, err.Error(), "instance reth-1: pre_run_command: exit status 1")
+		assert.Equal(t, []string{"pre-run"}, events, "no container is created")
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		events, runDir, err := runPreRunLifecycle(t, nil)
+		require.ErrorIs(t, err, errContainerCreation)
+		assert.Equal(t, []string{"create container"}, events)
+		assert.NoFileExists(t, filepath.Join(runDir, preRunCommandLog))
+	})
+}
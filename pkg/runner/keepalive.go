@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fcuKeepalive periodically re-sends engine_forkchoiceUpdated for the
+// client's current head while no tests are running, so that clients without
+// a consensus layer do not consider their head stale or prune state.
+//
+// The executor holds the gate while it drives the Engine API. Ticks that
+// find the gate taken are skipped, so a keepalive never interleaves with an
+// in-flight newPayload or rollback.
+type fcuKeepalive struct {
+	log      logrus.FieldLogger
+	interval time.Duration
+	send     func(ctx context.Context) error
+	gate     sync.Mutex
+
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startFCUKeepalive starts a goroutine that calls send every interval until
+// Stop is called or ctx is cancelled. Failed sends are logged and retried on
+// the next tick.
+func startFCUKeepalive(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	interval time.Duration,
+	send func(ctx context.Context) error,
+) *fcuKeepalive {
+	ctx, cancel := context.WithCancel(ctx)
+
+	k := &fcuKeepalive{
+		log:      log,
+		interval: interval,
+		send:     send,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	log.WithField("interval", interval).Info("Starting FCU keepalive")
+
+	go k.run(ctx)
+
+	return k
+}
+
+func (k *fcuKeepalive) run(ctx context.Context) {
+	defer close(k.done)
+
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !k.gate.TryLock() {
+				k.log.Debug("Skipping FCU keepalive while tests are running")
+
+				continue
+			}
+
+			err := k.send(ctx)
+
+			k.gate.Unlock()
+
+			if err != nil && ctx.Err() == nil {
+				k.log.WithError(err).Warn("FCU keepalive failed")
+			}
+		}
+	}
+}
+
+// Gate returns the lock to hold while sending Engine API calls that must
+// not interleave with a keepalive.
+func (k *fcuKeepalive) Gate() sync.Locker {
+	return &k.gate
+}
+
+// Stop stops the keepalive and waits for an in-flight send to finish. It is
+// safe to call more than once.
+func (k *fcuKeepalive) Stop() {
+	k.stopOnce.Do(func() {
+		k.cancel()
+		<-k.done
+	})
+}
+
+// sendKeepaliveFCU sends engine_forkchoiceUpdatedV3 for the client's latest
+// block, as reported by its RPC endpoint.
+func (r *runner) sendKeepaliveFCU(
+	ctx context.Context, host string, rpcPort, enginePort int,
+) error {
+	_, headHash, _, err := r.getLatestBlock(ctx, host, rpcPort)
+	if err != nil {
+		return fmt.Errorf("getting latest block: %w", err)
+	}
+
+	url := fmt.Sprintf("http://%s:%d", host, enginePort)
+
+	return r.doBootstrapFCURequest(ctx, url, forkchoiceUpdatedPayload(headHash))
+}
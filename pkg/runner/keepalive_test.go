@@ -0,0 +1,211 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() logrus.FieldLogger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return log
+}
+
+func TestFCUKeepalive_SendsAtInterval(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		sends []time.Time
+	)
+
+	interval := 20 * time.Millisecond
+	start := time.Now()
+
+	k := startFCUKeepalive(t.Context(), discardLogger(), interval,
+		func(context.Context) error {
+			mu.Lock()
+			sends = append(sends, time.Now())
+			mu.Unlock()
+
+			return nil
+		})
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(sends) >= 3
+	}, 2*time.Second, 5*time.Millisecond)
+
+	k.Stop()
+
+	mu.Lock()
+	sent := append([]time.Time(nil), sends...)
+	mu.Unlock()
+
+	// No keepalive fires before the first interval has elapsed, and
+	// consecutive keepalives are at least roughly one interval apart.
+	assert.GreaterOrEqual(t, sent[0].Sub(start), interval)
+
+	for i := 1; i < len(sent); i++ {
+		assert.GreaterOrEqual(t, sent[i].Sub(sent[i-1]), interval/2)
+	}
+
+	// Nothing is sent after Stop, and Stop is idempotent.
+	time.Sleep(3 * interval)
+	k.Stop()
+
+	mu.Lock()
+	assert.Len(t, sends, len(sent))
+	mu.Unlock()
+}
+
+func TestFCUKeepalive_ContinuesAfterFailures(t *testing.T) {
+	var calls atomic.Int32
+
+	k := startFCUKeepalive(t.Context(), discardLogger(), 5*time.Millisecond,
+		func(context.Context) error {
+			calls.Add(1)
+
+			return assert.AnError
+		})
+	defer k.Stop()
+
+	require.Eventually(t, func() bool { return calls.Load() >= 3 },
+		2*time.Second, 5*time.Millisecond)
+}
+
+// fakeClient is an httptest server that answers eth_getBlockByNumber and
+// records engine_forkchoiceUpdatedV3 head hashes.
+type fakeClient struct {
+	srv  *httptest.Server
+	mu   sync.Mutex
+	fcus []string
+}
+
+func newFakeClient(t *testing.T, headHash string) *fakeClient {
+	t.Helper()
+
+	fc := &fakeClient{}
+
+	fc.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch {
+		case req.Method == "eth_getBlockByNumber":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x10",` +
+				`"hash":"` + headHash + `","stateRoot":"0x01"}}`))
+		case strings.HasPrefix(req.Method, "engine_forkchoiceUpdated"):
+			assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+
+			var state struct {
+				HeadBlockHash string `json:"headBlockHash"`
+			}
+
+			require.NoError(t, json.Unmarshal(req.Params[0], &state))
+
+			fc.mu.Lock()
+			fc.fcus = append(fc.fcus, state.HeadBlockHash)
+			fc.mu.Unlock()
+
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"payloadStatus":` +
+				`{"status":"VALID","latestValidHash":"` + headHash + `"}}}`))
+		default:
+			http.Error(w, "unexpected method", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(fc.srv.Close)
+
+	return fc
+}
+
+func (fc *fakeClient) hostPort(t *testing.T) (string, int) {
+	t.Helper()
+
+	host, portStr, err := net.SplitHostPort(fc.srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	return host, port
+}
+
+func (fc *fakeClient) keepalives() []string {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	return append([]string(nil), fc.fcus...)
+}
+
+func TestSendKeepaliveFCU_UsesLatestHead(t *testing.T) {
+	const head = "0xabababababababababababababababababababababababababababababababab"
+
+	fc := newFakeClient(t, head)
+	host, port := fc.hostPort(t)
+
+	r := &runner{log: discardLogger(), cfg: &Config{JWT: config.DefaultJWT}}
+
+	require.NoError(t, r.sendKeepaliveFCU(t.Context(), host, port, port))
+	assert.Equal(t, []string{head}, fc.keepalives())
+}
+
+func TestFCUKeepalive_SkipsWhileGateHeld(t *testing.T) {
+	const head = "0xcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcd"
+
+	fc := newFakeClient(t, head)
+	host, port := fc.hostPort(t)
+
+	r := &runner{log: discardLogger(), cfg: &Config{JWT: config.DefaultJWT}}
+	interval := 10 * time.Millisecond
+
+	k := startFCUKeepalive(t.Context(), discardLogger(), interval,
+		func(ctx context.Context) error {
+			return r.sendKeepaliveFCU(ctx, host, port, port)
+		})
+	defer k.Stop()
+
+	require.Eventually(t, func() bool { return len(fc.keepalives()) >= 2 },
+		2*time.Second, 5*time.Millisecond)
+
+	// Simulate a test step: once the gate is held, no keepalive may reach
+	// the client until it is released.
+	gate := k.Gate()
+	gate.Lock()
+
+	held := len(fc.keepalives())
+
+	time.Sleep(10 * interval)
+	assert.Len(t, fc.keepalives(), held)
+
+	gate.Unlock()
+
+	require.Eventually(t, func() bool { return len(fc.keepalives()) > held },
+		2*time.Second, 5*time.Millisecond)
+
+	k.Stop()
+
+	for _, hash := range fc.keepalives() {
+		assert.Equal(t, head, hash)
+	}
+}
@@ -2,10 +2,13 @@ package runner
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -19,10 +22,14 @@ import (
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
 	"github.com/ethpandaops/benchmarkoor/pkg/cpufreq"
 	"github.com/ethpandaops/benchmarkoor/pkg/datadir"
+	"github.com/ethpandaops/benchmarkoor/pkg/diskbench"
 	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
 	"github.com/ethpandaops/benchmarkoor/pkg/executor"
 	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
+	"github.com/ethpandaops/benchmarkoor/pkg/irq"
 	"github.com/ethpandaops/benchmarkoor/pkg/podman"
+	"github.com/ethpandaops/benchmarkoor/pkg/thp"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/host"
 	"github.com/shirou/gopsutil/v4/mem"
@@ -70,9 +77,39 @@ func (r *runner) runContainerLifecycle(
 		}
 	}()
 
+	// Reject extra_args/command that fight the flags the runner manages,
+	// before any expensive datadir or genesis setup.
+	containerDataDir := spec.DataDir()
+	if useDataDir && datadirCfg.ContainerDir != "" {
+		containerDataDir = datadirCfg.ContainerDir
+	}
+
+	if err := client.CheckArgConflicts(
+		spec, instance.Command, instance.ExtraArgs, genesisSource != "", containerDataDir,
+	); err != nil {
+		return fmt.Errorf("instance %s: %w", instance.ID, err)
+	}
+
+	if err := client.CheckRemovedDefaultArgs(spec, instance.RemoveDefaultArgs); err != nil {
+		return fmt.Errorf("instance %s: %w", instance.ID, err)
+	}
+
+	var isolateSetting *bool
+	if r.cfg.FullConfig != nil {
+		isolateSetting = r.cfg.FullConfig.GetIsolateNetwork(instance)
+	}
+
+	isolateNetwork, err := resolveNetworkIsolation(spec, isolateSetting)
+	if err != nil {
+		return fmt.Errorf("instance %s: %w", instance.ID, err)
+	}
+
 	// Setup data directory: either container volume or copied datadir.
 	// Each container lifecycle gets a fresh volume/datadir.
-	var dataMount docker.Mount
+	var (
+		dataMount      docker.Mount
+		dataBackingDir string // Host directory storing the datadir's writes
+	)
 
 	if useDataDir {
 		log.WithFields(logrus.Fields{
@@ -110,6 +147,7 @@ func (r *runner) runContainerLifecycle(
 			Source: prepared.MountPath,
 			Target: containerDir,
 		}
+		dataBackingDir = prepared.BackingDir()
 	} else if r.cfg.FullConfig != nil &&
 		r.cfg.FullConfig.GetRollbackStrategy(instance) == config.RollbackStrategyCheckpointRestore {
 		// Checkpoint-restore without a pre-populated datadir uses a bind
@@ -178,7 +216,9 @@ func (r *runner) runContainerLifecycle(
 
 		var loadErr error
 
-		genesisContent, loadErr = r.loadFile(ctx, genesisSource)
+		genesisContent, loadErr = r.loadFile(
+			ctx, genesisSource, params.GenesisMirrors...,
+		)
 		if loadErr != nil {
 			return fmt.Errorf("loading genesis: %w", loadErr)
 		}
@@ -263,6 +303,14 @@ func (r *runner) runContainerLifecycle(
 		})
 	}
 
+	// Mount secret files. Only their container paths end up in config.json.
+	secretMounts, err := buildSecretMounts(tempDir, instance.SecretFiles)
+	if err != nil {
+		return err
+	}
+
+	mounts = append(mounts, secretMounts...)
+
 	// Run init container if required (skip when using datadir or no genesis).
 	if spec.RequiresInit() && !useDataDir && genesisSource != "" {
 		log.Info("Running init container")
@@ -304,39 +352,43 @@ func (r *runner) runContainerLifecycle(
 			fsutil.Chown(initLogFile, r.cfg.ResultsOwner)
 		}
 
-		_, _ = fmt.Fprint(initFile, formatStartMarker("INIT_CONTAINER", &containerLogInfo{
+		initOut := r.groupLogWriter(initFile, params.GenesisGroupHash)
+
+		_, _ = fmt.Fprint(initOut, formatStartMarker("INIT_CONTAINER", &containerLogInfo{
 			Name:             initSpec.Name,
 			Image:            initSpec.Image,
 			GenesisGroupHash: params.GenesisGroupHash,
 		}))
 
-		var initStdout, initStderr io.Writer = initFile, initFile
+		var initStdout, initStderr io.Writer = initOut, initOut
 		if r.cfg.ClientLogsToStdout {
-			pfxFn := clientLogPrefix(instance.ID + "-init")
+			initName := instance.ID + "-init"
 			stdoutPrefixWriter := &prefixedWriter{
-				prefixFn: pfxFn, writer: os.Stdout,
+				prefixFn: clientLogPrefix(instance.ID, initName, r.cfg.DisableLogColors),
+				writer:   os.Stdout,
 			}
 			logFilePrefixWriter := &prefixedWriter{
-				prefixFn: pfxFn, writer: benchmarkoorLogFile,
+				prefixFn: clientLogPrefix(instance.ID, initName, true),
+				writer:   benchmarkoorLogFile,
 			}
 			initStdout = io.MultiWriter(
-				initFile, stdoutPrefixWriter, logFilePrefixWriter,
+				initOut, stdoutPrefixWriter, logFilePrefixWriter,
 			)
 			initStderr = io.MultiWriter(
-				initFile, stdoutPrefixWriter, logFilePrefixWriter,
+				initOut, stdoutPrefixWriter, logFilePrefixWriter,
 			)
 		}
 
 		if err := r.containerMgr.RunInitContainer(
 			ctx, initSpec, initStdout, initStderr,
 		); err != nil {
-			_, _ = fmt.Fprintf(initFile, "#INIT_CONTAINER:END\n")
+			_, _ = fmt.Fprintf(initOut, "#INIT_CONTAINER:END\n")
 			_ = initFile.Close()
 
 			return fmt.Errorf("running init container: %w", err)
 		}
 
-		_, _ = fmt.Fprintf(initFile, "#INIT_CONTAINER:END\n")
+		_, _ = fmt.Fprintf(initOut, "#INIT_CONTAINER:END\n")
 		_ = initFile.Close()
 
 		log.Info("Init container completed")
@@ -346,52 +398,7 @@ func (r *runner) runContainerLifecycle(
 		log.Info("Skipping init container (using pre-populated datadir)")
 	}
 
-	// Determine command.
-	cmd := make([]string, len(instance.Command))
-	copy(cmd, instance.Command)
-
-	if len(cmd) == 0 {
-		cmd = spec.DefaultCommand()
-	}
-
-	// Add genesis flag if genesis is configured and client uses a genesis flag.
-	if genesisSource != "" && spec.GenesisFlag() != "" {
-		cmd = append(cmd, spec.GenesisFlag()+spec.GenesisPath())
-	}
-
-	// Append extra args if provided, replacing any base args that share a flag prefix.
-	if len(instance.ExtraArgs) > 0 {
-		// Build set of flag prefixes from extra_args (e.g. "--config=" from "--config=mainnet.cfg").
-		prefixes := make([]string, 0, len(instance.ExtraArgs))
-		for _, arg := range instance.ExtraArgs {
-			if idx := strings.Index(arg, "="); idx != -1 {
-				prefixes = append(prefixes, arg[:idx+1])
-			}
-		}
-
-		// Remove any existing args that share a prefix with an extra arg.
-		if len(prefixes) > 0 {
-			filtered := make([]string, 0, len(cmd))
-			for _, c := range cmd {
-				override := false
-				for _, p := range prefixes {
-					if strings.HasPrefix(c, p) {
-						override = true
-
-						break
-					}
-				}
-
-				if !override {
-					filtered = append(filtered, c)
-				}
-			}
-
-			cmd = filtered
-		}
-
-		cmd = append(cmd, instance.ExtraArgs...)
-	}
+	cmd := buildCommand(spec, instance, genesisSource != "", isolateNetwork)
 
 	// Build environment (default first, instance overrides).
 	env := make(
@@ -464,10 +471,15 @@ func (r *runner) runContainerLifecycle(
 		}
 	}
 
-	// Resolve drop_memory_caches setting.
-	var dropMemoryCaches string
+	// Resolve drop_memory_caches and sync_between_tests settings.
+	var (
+		dropMemoryCaches string
+		syncBetweenTests bool
+	)
+
 	if r.cfg.FullConfig != nil {
 		dropMemoryCaches = r.cfg.FullConfig.GetDropMemoryCaches(instance)
+		syncBetweenTests = r.cfg.FullConfig.GetSyncBetweenTests(instance)
 	}
 
 	// Resolve resource limits.
@@ -478,6 +490,26 @@ func (r *runner) runContainerLifecycle(
 	if r.cfg.FullConfig != nil {
 		resourceLimitsCfg := r.cfg.FullConfig.GetResourceLimits(instance)
 		if resourceLimitsCfg != nil {
+			if resourceLimitsCfg.BlkioConfig.UsesAutoDevice() {
+				if !useDataDir {
+					return fmt.Errorf("blkio_config device path %q requires a datadir", config.BlkioDeviceAuto)
+				}
+
+				// Throttle the device the container's datadir lives on, which
+				// differs from the source datadir's unless it is used in place.
+				device, err := resolveBlockDevice("/proc/mounts", "/sys/class/block", dataBackingDir)
+				if err != nil {
+					return fmt.Errorf("resolving blkio device for datadir: %w", err)
+				}
+
+				log.WithFields(logrus.Fields{
+					"datadir": dataBackingDir,
+					"device":  device,
+				}).Info("Resolved blkio device backing datadir")
+
+				resourceLimitsCfg = withBlkioDevice(resourceLimitsCfg, device)
+			}
+
 			var err error
 
 			containerResourceLimits, resolvedResourceLimits, err =
@@ -497,6 +529,8 @@ func (r *runner) runContainerLifecycle(
 				fields["blkio_write_bps_devices"] = len(resolvedResourceLimits.BlkioConfig.DeviceWriteBps)
 				fields["blkio_read_iops_devices"] = len(resolvedResourceLimits.BlkioConfig.DeviceReadIOps)
 				fields["blkio_write_iops_devices"] = len(resolvedResourceLimits.BlkioConfig.DeviceWriteIOps)
+				fields["blkio_weight"] = resolvedResourceLimits.BlkioConfig.Weight
+				fields["blkio_weight_devices"] = len(resolvedResourceLimits.BlkioConfig.DeviceWeight)
 			}
 
 			log.WithFields(fields).Info("Resource limits configured")
@@ -538,6 +572,32 @@ func (r *runner) runContainerLifecycle(
 				resolvedResourceLimits.CPUTurboBoost = cpufreqCfg.TurboBoost
 				resolvedResourceLimits.CPUGovernor = cpufreqCfg.Governor
 			}
+
+			// Apply transparent huge pages mode if configured.
+			if r.thpMgr != nil && resourceLimitsCfg.TransparentHugepage != "" {
+				if err := r.thpMgr.Apply(ctx, resourceLimitsCfg.TransparentHugepage); err != nil {
+					return fmt.Errorf("applying transparent_hugepage mode: %w", err)
+				}
+
+				localCleanupFuncs = append(localCleanupFuncs, func() {
+					if restoreErr := r.thpMgr.Restore(context.Background()); restoreErr != nil {
+						log.WithError(restoreErr).Warn("Failed to restore THP mode")
+					}
+				})
+
+				resolvedResourceLimits.THPMode = resourceLimitsCfg.TransparentHugepage
+			}
+
+			// Check (and optionally move) device IRQs on the pinned CPUs.
+			if resourceLimitsCfg.IRQAffinity != "" {
+				restore := applyIRQAffinity(
+					log, irq.DefaultProcPath, r.cfg.StateDir, resourceLimitsCfg.IRQAffinity,
+					targetCPUs, resolvedResourceLimits,
+				)
+				if restore != nil {
+					localCleanupFuncs = append(localCleanupFuncs, restore)
+				}
+			}
 		}
 	}
 
@@ -560,9 +620,41 @@ func (r *runner) runContainerLifecycle(
 	}
 
 	// Write run configuration with resolved values.
+	cpuSysfsPath, thpSysfsPath := config.DefaultCPUSysfsPath, config.DefaultTHPSysfsPath
+	if r.cfg.FullConfig != nil {
+		cpuSysfsPath = r.cfg.FullConfig.GetCPUSysfsPath()
+		thpSysfsPath = r.cfg.FullConfig.GetTHPSysfsPath()
+	}
+
+	systemInfo, fingerprint := getSystemInfo(cpuSysfsPath, thpSysfsPath)
+
+	// Probe the disk backing the datadir before the client starts.
+	if r.cfg.FullConfig != nil {
+		if dbCfg := r.cfg.FullConfig.Runner.DiskBenchmark; dbCfg != nil && dbCfg.Enabled {
+			probeDir := r.cfg.TmpDataDir
+			if probeDir == "" {
+				probeDir = os.TempDir()
+			}
+
+			// Bind-mounted datadirs are probed next to the mount source,
+			// on the same filesystem, without touching the datadir itself.
+			if dataMount.Type == "bind" {
+				probeDir = filepath.Dir(dataMount.Source)
+			}
+
+			runDiskBenchmark(ctx, log, dbCfg, probeDir, systemInfo)
+		}
+	}
+
+	restartPolicy, err := config.ParseRestartPolicy(instance.Restart)
+	if err != nil {
+		return fmt.Errorf("instance %q: %w", instance.ID, err)
+	}
+
 	runConfig := &RunConfig{
-		Timestamp: params.RunTimestamp,
-		System:    getSystemInfo(),
+		Timestamp:          params.RunTimestamp,
+		System:             systemInfo,
+		MachineFingerprint: fingerprint,
 		Instance: &ResolvedInstance{
 			ID:     instance.ID,
 			Client: instance.Client,
@@ -572,15 +664,23 @@ func (r *runner) runContainerLifecycle(
 				}
 				return "docker"
 			}(),
-			Image:       imageName,
-			ImageSHA256: imageDigest,
-			Entrypoint:  instance.Entrypoint,
-			Command:     cmd,
-			ExtraArgs:   instance.ExtraArgs,
-			PullPolicy:  instance.PullPolicy,
-			Restart:     instance.Restart,
-			Environment: env,
-			DataDir:     datadirCfg,
+			Image:             imageName,
+			ImageSHA256:       imageDigest,
+			ClientCommit:      params.ClientCommit,
+			Build:             instance.Build,
+			Entrypoint:        instance.Entrypoint,
+			Command:           cmd,
+			ExtraArgs:         instance.ExtraArgs,
+			RemoveDefaultArgs: instance.RemoveDefaultArgs,
+			PullPolicy:        instance.PullPolicy,
+			Restart:           restartPolicy.String(),
+			Environment: func() map[string]string {
+				if r.cfg.FullConfig != nil {
+					return config.RedactEnv(env, r.cfg.FullConfig.GetRedactEnvPatterns())
+				}
+				return config.RedactEnv(env, config.DefaultRedactEnvPatterns)
+			}(),
+			DataDir: datadirCfg,
 			RollbackStrategy: func() string {
 				if r.cfg.FullConfig != nil {
 					return r.cfg.FullConfig.GetRollbackStrategy(instance)
@@ -588,6 +688,7 @@ func (r *runner) runContainerLifecycle(
 				return ""
 			}(),
 			DropMemoryCaches:  dropMemoryCaches,
+			SyncBetweenTests:  syncBetweenTests,
 			WaitAfterRPCReady: waitAfterRPCReadyStr,
 			RunTimeout:        runTimeoutStr,
 			RetryNewPayloadsSyncingState: func() *config.RetryNewPayloadsSyncingConfig {
@@ -597,6 +698,8 @@ func (r *runner) runContainerLifecycle(
 				return nil
 			}(),
 			ResourceLimits: resolvedResourceLimits,
+			Devices:        instance.Devices,
+			SecretFiles:    secretContainerPaths(instance.SecretFiles),
 			PostTestRPCCalls: func() []config.PostTestRPCCall {
 				if r.cfg.FullConfig != nil {
 					return r.cfg.FullConfig.GetPostTestRPCCalls(instance)
@@ -643,7 +746,13 @@ func (r *runner) runContainerLifecycle(
 		runConfig.SuiteHash = r.executor.GetSuiteHash()
 	}
 
-	if err := writeRunConfig(
+	// Genesis groups share the run's config.json, so their writes are merged.
+	writeConfig := writeRunConfig
+	if params.SharedResults != nil {
+		writeConfig = params.SharedResults.writeRunConfig
+	}
+
+	if err := writeConfig(
 		runResultsDir, runConfig, r.cfg.ResultsOwner,
 	); err != nil {
 		log.WithError(err).Warn("Failed to write run config")
@@ -668,6 +777,9 @@ func (r *runner) runContainerLifecycle(
 		NetworkName:    r.cfg.ContainerNetwork,
 		ResourceLimits: containerResourceLimits,
 		SecurityOpt:    []string{"seccomp=unconfined"},
+		Devices:        buildContainerDevices(instance.Devices),
+		RestartPolicy:  restartPolicy.Name,
+		RestartRetries: restartPolicy.MaxRetries,
 		Labels: map[string]string{
 			"benchmarkoor.instance":   instance.ID,
 			"benchmarkoor.client":     instance.Client,
@@ -681,6 +793,10 @@ func (r *runner) runContainerLifecycle(
 	params.DataDirCfg = datadirCfg
 	params.UseDataDir = useDataDir
 
+	if err := r.runPreRunCommand(ctx, params); err != nil {
+		return fmt.Errorf("instance %s: %w", instance.ID, err)
+	}
+
 	// Create container.
 	containerID, err := r.containerMgr.CreateContainer(ctx, containerSpec)
 	if err != nil {
@@ -692,21 +808,20 @@ func (r *runner) runContainerLifecycle(
 
 	logFilePath := filepath.Join(runResultsDir, "container.log")
 
-	logFile, err := os.OpenFile(logFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	logFile, err := fsutil.OpenFile(
+		logFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644, r.cfg.ResultsOwner,
+	)
 	if err != nil {
 		logCancel()
 
 		return fmt.Errorf("opening container log file: %w", err)
 	}
 
-	if r.cfg.ResultsOwner != nil {
-		fsutil.Chown(logFilePath, r.cfg.ResultsOwner)
-	}
-
 	// Create block log collector to capture JSON payloads from client logs.
 	blockLogParser := blocklog.NewParser(client.ClientType(instance.Client))
-	blockLogCollector := blocklog.NewCollector(blockLogParser, logFile)
+	blockLogCollector := blocklog.NewCollector(blockLogParser, nil)
 	params.BlockLogCollector = blockLogCollector
+	params.TestMarkers = &testMarkerWriter{}
 
 	logDone := make(chan struct{})
 
@@ -724,7 +839,7 @@ func (r *runner) runContainerLifecycle(
 				Image:            imageName,
 				GenesisGroupHash: params.GenesisGroupHash,
 			},
-			blockLogCollector,
+			blockLogCollector, params.TestMarkers,
 		); err != nil {
 			// Context cancellation during cleanup is expected.
 			select {
@@ -810,6 +925,7 @@ func (r *runner) runContainerLifecycle(
 	var containerDied bool
 	var containerExitCode *int64
 	var containerOOMKilled *bool
+	var containerRestarts int
 	var mu sync.Mutex
 
 	containerExitCh, containerErrCh := r.containerMgr.WaitForContainerExit(
@@ -885,7 +1001,9 @@ func (r *runner) runContainerLifecycle(
 		runConfig.TimestampEnd = time.Now().Unix()
 		mu.Unlock()
 
-		if writeErr := writeRunConfig(
+		r.recordRunOutcome(runConfig)
+
+		if writeErr := writeConfig(
 			runResultsDir, runConfig, r.cfg.ResultsOwner,
 		); writeErr != nil {
 			log.WithError(writeErr).Warn(
@@ -911,6 +1029,35 @@ func (r *runner) runContainerLifecycle(
 		}
 	}
 
+	// Fail fast when the client was started with the wrong genesis.
+	if params.GenesisBlockHash != "" {
+		if err := r.verifyGenesisBlock(
+			execCtx, containerIP, spec.RPCPort(), params.GenesisBlockHash,
+		); err != nil {
+			mu.Lock()
+			runConfig.Status = RunStatusFailed
+			runConfig.TerminationReason = fmt.Sprintf("verifying genesis: %v", err)
+			runConfig.TimestampEnd = time.Now().Unix()
+			mu.Unlock()
+
+			r.recordRunOutcome(runConfig)
+
+			if writeErr := writeConfig(
+				runResultsDir, runConfig, r.cfg.ResultsOwner,
+			); writeErr != nil {
+				log.WithError(writeErr).Warn(
+					"Failed to write run config with failed status",
+				)
+			}
+
+			return fmt.Errorf("verifying genesis: %w", err)
+		}
+
+		log.WithField("genesis_block_hash", params.GenesisBlockHash).Info(
+			"Genesis block hash verified",
+		)
+	}
+
 	// Log the latest block info.
 	blockNum, blockHash, stateRoot, blkErr := r.getLatestBlock(execCtx, containerIP, spec.RPCPort())
 	if blkErr != nil {
@@ -974,10 +1121,47 @@ func (r *runner) runContainerLifecycle(
 		}
 	}
 
+	// Read the datadir into the page cache so the first test does not start
+	// cold. Only host-side datadirs can be read; volumes are skipped.
+	if r.cfg.FullConfig != nil && r.cfg.FullConfig.GetPrimePageCache(instance) {
+		if useDataDir {
+			var available uint64
+			if vm, vmErr := mem.VirtualMemory(); vmErr == nil {
+				available = vm.Available
+			}
+
+			runPrimePageCache(execCtx, log, dataMount.Source, available)
+
+			runConfig.Instance.PrimePageCache = true
+		} else {
+			log.Warn("prime_page_cache requires a datadir, skipping")
+		}
+	}
+
+	// Keep the head fresh while no tests are running. The executor holds
+	// engineLock during steps so keepalives only fill the idle gaps.
+	var (
+		stopKeepalive = func() {}
+		engineLock    sync.Locker
+	)
+
+	if r.cfg.FullConfig != nil {
+		if interval := r.cfg.FullConfig.GetFCUKeepaliveInterval(instance); interval > 0 {
+			keepalive := startFCUKeepalive(execCtx, log, interval,
+				func(ctx context.Context) error {
+					return r.sendKeepaliveFCU(ctx, containerIP, spec.RPCPort(), spec.EnginePort())
+				})
+			defer keepalive.Stop()
+
+			stopKeepalive = keepalive.Stop
+			engineLock = keepalive.Gate()
+		}
+	}
+
 	// Update config with client version.
 	runConfig.Instance.ClientVersion = clientVersion
 
-	if err := writeRunConfig(
+	if err := writeConfig(
 		runResultsDir, runConfig, r.cfg.ResultsOwner,
 	); err != nil {
 		log.WithError(err).Warn(
@@ -1025,6 +1209,8 @@ func (r *runner) runContainerLifecycle(
 			// containers. Signal cleanup-started so the death monitor
 			// treats container exits as expected (debug-level logging),
 			// and cancel execCtx so the monitor's execCancel() is a no-op.
+			// The keepalive targets this container, so it stops here.
+			stopKeepalive()
 			localCleanupOnce.Do(func() { close(localCleanupStarted) })
 			execCancel()
 
@@ -1056,19 +1242,33 @@ func (r *runner) runContainerLifecycle(
 				DockerClient:          r.getDockerClient(),
 				DropMemoryCaches:      dropMemoryCaches,
 				DropCachesPath:        dropCachesPath,
+				DropCachesCommand:     r.dropCachesCommand(),
+				SyncBetweenTests:      syncBetweenTests,
 				RollbackStrategy:      rollbackStrategy,
 				ClientRPCRollbackSpec: spec.RPCRollbackSpec(),
 				RPCEndpoint: fmt.Sprintf(
 					"http://%s:%d", containerIP, spec.RPCPort(),
 				),
 				Tests:                         params.Tests,
+				PreRunStepsWithTests:          params.RerunSubset,
 				BlockLogCollector:             params.BlockLogCollector,
+				TestMarker:                    params.TestMarkers,
 				RetryNewPayloadsSyncingConfig: r.cfg.FullConfig.GetRetryNewPayloadsSyncingState(instance),
 				PostTestRPCCalls:              r.cfg.FullConfig.GetPostTestRPCCalls(instance),
 				PostTestSleepDuration:         r.cfg.FullConfig.GetPostTestSleepDuration(instance),
+				EngineLock:                    engineLock,
 			}
 
 			result, execErr = r.executor.ExecuteTests(execCtx, execOpts)
+			stopKeepalive()
+
+			// A restart by the runtime's restart policy may be missed by the
+			// death monitor, so check the restart count explicitly.
+			if ctx.Err() == nil {
+				containerRestarts = checkContainerRestarts(
+					ctx, log, r.containerMgr, containerID,
+				)
+			}
 		}
 
 		if execErr != nil {
@@ -1087,6 +1287,7 @@ func (r *runner) runContainerLifecycle(
 				"total":    result.TotalTests,
 				"passed":   result.Passed,
 				"failed":   result.Failed,
+				"skipped":  result.Skipped,
 				"duration": result.TotalDuration,
 			}).Info("Test execution completed")
 
@@ -1099,21 +1300,15 @@ func (r *runner) runContainerLifecycle(
 
 			mu.Lock()
 
-			if params.AccumulatedTestCount != nil {
+			if params.SharedResults != nil {
 				// Multi-genesis mode: accumulate counts across groups.
-				params.AccumulatedTestCount.Total = suiteTotal
-				params.AccumulatedTestCount.Passed += result.Passed
-				params.AccumulatedTestCount.Failed += result.Failed
-				runConfig.TestCounts = &TestCounts{
-					Total:  params.AccumulatedTestCount.Total,
-					Passed: params.AccumulatedTestCount.Passed,
-					Failed: params.AccumulatedTestCount.Failed,
-				}
+				runConfig.TestCounts = params.SharedResults.addTestCounts(suiteTotal, result)
 			} else {
 				runConfig.TestCounts = &TestCounts{
-					Total:  suiteTotal,
-					Passed: result.Passed,
-					Failed: result.Failed,
+					Total:   suiteTotal,
+					Passed:  result.Passed,
+					Failed:  result.Failed,
+					Skipped: result.Skipped,
 				}
 			}
 
@@ -1148,6 +1343,11 @@ func (r *runner) runContainerLifecycle(
 	if timeoutCancel != nil && testCtx.Err() == context.DeadlineExceeded {
 		runConfig.Status = RunStatusTimedOut
 		runConfig.TerminationReason = fmt.Sprintf("the run_timeout of %s was reached", runTimeout)
+	} else if errors.Is(context.Cause(ctx), errGenesisGroupFailed) {
+		// Stopped because another genesis group failed, whose status the
+		// shared run config keeps.
+		runConfig.Status = RunStatusFailed
+		runConfig.TerminationReason = "stopped after " + errGenesisGroupFailed.Error()
 	} else if ctx.Err() != nil {
 		runConfig.Status = RunStatusCancelled
 		runConfig.TerminationReason = "run was cancelled"
@@ -1156,16 +1356,26 @@ func (r *runner) runContainerLifecycle(
 		runConfig.TerminationReason = "container exited during test execution"
 		runConfig.ContainerExitCode = containerExitCode
 		runConfig.ContainerOOMKilled = containerOOMKilled
+	} else if containerRestarts > 0 {
+		runConfig.Status = RunStatusContainerDied
+		runConfig.TerminationReason = fmt.Sprintf(
+			"container was restarted %d time(s) by the container runtime during test execution",
+			containerRestarts,
+		)
 	} else if runConfig.Status == "" {
 		runConfig.Status = RunStatusCompleted
 	}
+
+	runConfig.ContainerRestartCount = containerRestarts
 	mu.Unlock()
 
 	// Record when the run ended.
 	runConfig.TimestampEnd = time.Now().Unix()
 
+	r.recordRunOutcome(runConfig)
+
 	// Write final config with status.
-	if err := writeRunConfig(
+	if err := writeConfig(
 		runResultsDir, runConfig, r.cfg.ResultsOwner,
 	); err != nil {
 		log.WithError(err).Warn("Failed to write final run config with status")
@@ -1177,7 +1387,12 @@ func (r *runner) runContainerLifecycle(
 	if params.BlockLogCollector != nil {
 		blockLogs := params.BlockLogCollector.GetBlockLogs()
 		if len(blockLogs) > 0 {
-			if err := executor.WriteBlockLogsResult(
+			writeBlockLogs := executor.WriteBlockLogsResult
+			if params.SharedResults != nil {
+				writeBlockLogs = params.SharedResults.writeBlockLogs
+			}
+
+			if err := writeBlockLogs(
 				runResultsDir, blockLogs, r.cfg.ResultsOwner,
 			); err != nil {
 				log.WithError(err).Warn("Failed to write block logs result")
@@ -1196,17 +1411,43 @@ func (r *runner) runContainerLifecycle(
 	return nil
 }
 
-// loadFile loads content from a URL or local file path.
-func (r *runner) loadFile(ctx context.Context, source string) ([]byte, error) {
+// loadFile loads content from a URL or local file path. When source is a
+// URL, the mirrors are tried in order if it cannot be downloaded.
+func (r *runner) loadFile(
+	ctx context.Context, source string, mirrors ...string,
+) ([]byte, error) {
 	// Check if source is a URL.
 	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
-		return r.downloadFromURL(ctx, source)
+		if r.cfg.Offline {
+			return nil, fmt.Errorf("downloading %s: %w", source, download.ErrOffline)
+		}
+
+		return r.downloadWithFallback(ctx, append([]string{source}, mirrors...))
 	}
 
 	// Treat as local file path.
 	return r.readFromFile(source)
 }
 
+// downloadWithFallback downloads content from the first URL that succeeds.
+func (r *runner) downloadWithFallback(ctx context.Context, urls []string) ([]byte, error) {
+	var data []byte
+
+	err := download.WithFallback(ctx, r.log, urls, r.cfg.DownloadRetry.Wrap(r.log,
+		func(ctx context.Context, url string) error {
+			var err error
+
+			data, err = r.downloadFromURL(ctx, url)
+
+			return err
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
 // downloadFromURL downloads content from a URL.
 func (r *runner) downloadFromURL(ctx context.Context, url string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -1221,7 +1462,7 @@ func (r *runner) downloadFromURL(ctx context.Context, url string) ([]byte, error
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &download.StatusError{StatusCode: resp.StatusCode}
 	}
 
 	data, err := io.ReadAll(resp.Body)
@@ -1242,7 +1483,10 @@ func (r *runner) readFromFile(path string) ([]byte, error) {
 	return data, nil
 }
 
-func getSystemInfo() *SystemInfo {
+// getSystemInfo collects host hardware and OS details, including the CPU
+// frequency state under cpuSysfsPath and the THP mode under thpSysfsPath,
+// along with the machine fingerprint derived from them.
+func getSystemInfo(cpuSysfsPath, thpSysfsPath string) (*SystemInfo, string) {
 	info := &SystemInfo{}
 
 	if hostInfo, err := host.Info(); err == nil {
@@ -1271,7 +1515,82 @@ func getSystemInfo() *SystemInfo {
 		info.MemoryTotalGB = float64(memInfo.Total) / (1024 * 1024 * 1024)
 	}
 
-	return info
+	if swapInfo, err := mem.SwapMemory(); err == nil {
+		info.SwapTotalGB = float64(swapInfo.Total) / (1024 * 1024 * 1024)
+	}
+
+	readCPUFreqState(info, cpuSysfsPath)
+
+	if mode, err := thp.GetMode(thpSysfsPath); err == nil {
+		info.THPMode = mode
+	}
+
+	return info, machineFingerprint(info)
+}
+
+// readCPUFreqState records the current CPU governor and turbo boost state,
+// whether or not cpu_freq is configured. Fields stay empty where cpufreq is
+// not exposed (e.g. in most VMs).
+func readCPUFreqState(info *SystemInfo, cpuSysfsPath string) {
+	if govs, err := cpufreq.GetGovernors(cpuSysfsPath); err == nil {
+		info.CPUGovernor = strings.Join(govs, ",")
+	}
+
+	if enabled, _, err := cpufreq.GetTurboBoostEnabled(cpuSysfsPath); err == nil {
+		info.CPUTurboBoost = &enabled
+	}
+}
+
+// runDiskBenchmark probes the disk backing dir and records the results in
+// info. Failures are logged and leave the disk fields empty.
+func runDiskBenchmark(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	cfg *config.DiskBenchmarkConfig,
+	dir string,
+	info *SystemInfo,
+) {
+	// Size and duration are checked by Validate.
+	size, _ := config.ParseByteSize(cfg.Size)
+	duration, _ := time.ParseDuration(cfg.Duration)
+
+	result, err := diskbench.Run(ctx, log, &diskbench.Config{
+		Dir:      dir,
+		Tool:     cfg.Tool,
+		Size:     int64(size),
+		Duration: duration,
+	})
+	if err != nil {
+		log.WithError(err).Warn("Disk probe failed")
+
+		return
+	}
+
+	info.DiskBenchTool = result.Tool
+	info.DiskBenchPath = dir
+	info.DiskSeqWriteMBps = math.Round(result.SeqWriteMBps*100) / 100
+	info.DiskRandReadIOPS = math.Round(result.RandReadIOPS)
+	info.DiskRandReadLatencyUs = math.Round(result.RandReadLatencyUs*100) / 100
+
+	log.WithFields(logrus.Fields{
+		"seq_write_mbps":       info.DiskSeqWriteMBps,
+		"rand_read_iops":       info.DiskRandReadIOPS,
+		"rand_read_latency_us": info.DiskRandReadLatencyUs,
+	}).Info("Disk probe completed")
+}
+
+// machineFingerprint hashes the hardware details that affect benchmark
+// results (CPU model, core count, memory size and kernel) so results from
+// different machines can be told apart. Memory is rounded to whole GB, as
+// the reported total varies slightly with kernel reservations.
+func machineFingerprint(info *SystemInfo) string {
+	h := sha256.New()
+
+	fmt.Fprintf(h, "cpu_model=%s\ncpu_cores=%d\nmemory_gb=%d\nkernel=%s\n",
+		info.CPUModel, info.CPUCores, int64(math.Round(info.MemoryTotalGB)), info.KernelVersion)
+
+	// Use first 16 characters of the hash.
+	return hex.EncodeToString(h.Sum(nil))[:16]
 }
 
 func writeRunConfig(resultsDir string, cfg *RunConfig, owner *fsutil.OwnerConfig) error {
@@ -1280,10 +1599,110 @@ func writeRunConfig(resultsDir string, cfg *RunConfig, owner *fsutil.OwnerConfig
 		return fmt.Errorf("marshaling run config: %w", err)
 	}
 
+	// Write to a temporary file and rename it, so the genesis groups of a
+	// run writing concurrently never leave a partially written config.
 	configPath := filepath.Join(resultsDir, "config.json")
-	if err := fsutil.WriteFile(configPath, data, 0644, owner); err != nil {
+
+	tmp, err := os.CreateTemp(resultsDir, ".config.json.*")
+	if err != nil {
+		return fmt.Errorf("creating temporary config.json: %w", err)
+	}
+
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+
+	if err := fsutil.WriteFile(tmpPath, data, 0644, owner); err != nil {
+		_ = os.Remove(tmpPath)
+
+		return fmt.Errorf("writing config.json: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		_ = os.Remove(tmpPath)
+
 		return fmt.Errorf("writing config.json: %w", err)
 	}
 
 	return nil
 }
+
+// resolveNetworkIsolation reports whether the client's isolation args are
+// applied. An unset isolate_network isolates clients that support it; an
+// explicit true fails for clients that do not.
+func resolveNetworkIsolation(spec client.Spec, setting *bool) (bool, error) {
+	supported := len(spec.IsolationArgs()) > 0
+
+	if setting == nil {
+		return supported, nil
+	}
+
+	if *setting && !supported {
+		return false, fmt.Errorf("isolate_network is not supported by client %s", spec.Type())
+	}
+
+	return *setting, nil
+}
+
+// buildCommand assembles the container command: the instance command (or
+// the client default), the genesis flag when a genesis is injected, the
+// client's benchmark default args, its isolation args when isolateNetwork
+// is set, and finally the instance extra_args. Default command and benchmark
+// default args starting with a remove_default_args prefix are dropped. An
+// extra arg "--flag=value" replaces earlier args setting the same flag,
+// including a bare "--flag".
+func buildCommand(
+	spec client.Spec, instance *config.ClientInstance, genesisInjected, isolateNetwork bool,
+) []string {
+	cmd := make([]string, len(instance.Command))
+	copy(cmd, instance.Command)
+
+	if len(cmd) == 0 {
+		cmd = client.RemoveArgs(spec.DefaultCommand(), instance.RemoveDefaultArgs)
+	}
+
+	// Add genesis flag if genesis is configured and client uses a genesis flag.
+	if genesisInjected && spec.GenesisFlag() != "" {
+		cmd = append(cmd, spec.GenesisFlag()+spec.GenesisPath())
+	}
+
+	cmd = append(cmd, client.RemoveArgs(spec.BenchmarkDefaultArgs(), instance.RemoveDefaultArgs)...)
+
+	if isolateNetwork {
+		cmd = append(cmd, spec.IsolationArgs()...)
+	}
+
+	if len(instance.ExtraArgs) == 0 {
+		return cmd
+	}
+
+	// Build set of flag prefixes from extra_args (e.g. "--config=" from "--config=mainnet.cfg").
+	prefixes := make([]string, 0, len(instance.ExtraArgs))
+	for _, arg := range instance.ExtraArgs {
+		if idx := strings.Index(arg, "="); idx != -1 {
+			prefixes = append(prefixes, arg[:idx+1])
+		}
+	}
+
+	// Remove any existing args that share a prefix with an extra arg.
+	if len(prefixes) > 0 {
+		filtered := make([]string, 0, len(cmd))
+		for _, c := range cmd {
+			override := false
+			for _, p := range prefixes {
+				if strings.HasPrefix(c, p) || c == strings.TrimSuffix(p, "=") {
+					override = true
+
+					break
+				}
+			}
+
+			if !override {
+				filtered = append(filtered, c)
+			}
+		}
+
+		cmd = filtered
+	}
+
+	return append(cmd, instance.ExtraArgs...)
+}
@@ -1,15 +1,20 @@
 package runner
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/ethpandaops/benchmarkoor/pkg/blocklog"
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/executor"
+	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
 	"github.com/sirupsen/logrus"
 )
 
@@ -58,13 +63,154 @@ func (w *prefixedWriter) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
+// clientLogStyle is the emoji and ANSI color used to mark the logs of one
+// client instance.
+type clientLogStyle struct {
+	emoji string
+	color string
+}
+
+// clientLogColorReset resets the ANSI color after an instance name.
+const clientLogColorReset = "\033[0m"
+
+// clientLogStyles are assigned to instances by hashing the instance ID. The
+// emojis are all two columns wide so prefixes stay aligned. Blue is left out
+// as it marks benchmarkoor's own log lines.
+var clientLogStyles = []clientLogStyle{
+	{emoji: "🟣", color: "\033[35m"},
+	{emoji: "🟢", color: "\033[32m"},
+	{emoji: "🟡", color: "\033[33m"},
+	{emoji: "🟠", color: "\033[38;5;208m"},
+	{emoji: "🔴", color: "\033[31m"},
+	{emoji: "🟤", color: "\033[38;5;130m"},
+	{emoji: "⚪", color: "\033[37m"},
+	{emoji: "⚫", color: "\033[90m"},
+}
+
+// clientLogStyleFor returns the stable log style for an instance ID.
+func clientLogStyleFor(instanceID string) clientLogStyle {
+	sum := crc32.ChecksumIEEE([]byte(instanceID))
+
+	return clientLogStyles[sum%uint32(len(clientLogStyles))]
+}
+
 // clientLogPrefix returns a function that generates a consistent log prefix
-// for client container logs: "🟣 $TIMESTAMP CLIE | $clientName | ".
-func clientLogPrefix(clientName string) func() string {
+// for client container logs: "$EMOJI $TIMESTAMP CLIE | $name | ". The emoji
+// and name color are derived from instanceID, so an instance's containers
+// (e.g. its init container) share a style. With disableColors the name is
+// left uncolored.
+func clientLogPrefix(instanceID, name string, disableColors bool) func() string {
+	style := clientLogStyleFor(instanceID)
+	if !disableColors {
+		name = style.color + name + clientLogColorReset
+	}
+
 	return func() string {
 		ts := time.Now().UTC().Format(config.LogTimestampFormat)
 
-		return fmt.Sprintf("🟣 %s CLIE | %s | ", ts, clientName)
+		return fmt.Sprintf("%s %s CLIE | %s | ", style.emoji, ts, name)
+	}
+}
+
+// groupLogWriter prefixes the container.log lines written through w with
+// "[$GENESIS_GROUP] " when genesis groups run in parallel, since their
+// containers then append to container.log at the same time.
+func (r *runner) groupLogWriter(w io.Writer, genesisGroupHash string) io.Writer {
+	if r.cfg.GenesisGroupParallelism <= 1 || genesisGroupHash == "" {
+		return w
+	}
+
+	return &prefixedWriter{prefix: "[" + genesisGroupHash + "] ", writer: w}
+}
+
+// receiveTimestampPrefix returns benchmarkoor's UTC receive time for a
+// container log line: "$TIMESTAMP ". It is independent of any timestamp the
+// client writes itself.
+func receiveTimestampPrefix() string {
+	return time.Now().UTC().Format(config.LogTimestampFormat) + " "
+}
+
+// testMarkerWriter writes client log lines to container.log and inserts
+// "#TEST:START name=..." / "#TEST:END name=... status=..." markers between
+// them. Lines are buffered until complete so a marker never splits a client
+// line.
+type testMarkerWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf []byte
+}
+
+// Ensure interface compliance.
+var _ executor.TestMarker = (*testMarkerWriter)(nil)
+
+// attach directs log lines and markers to w, e.g. the container.log handle
+// of a freshly started log stream, and returns the writer to stream into.
+func (m *testMarkerWriter) attach(w io.Writer) io.Writer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.w = w
+	m.buf = nil
+
+	return m
+}
+
+// detach flushes a trailing partial line and stops writing to the attached
+// writer. Markers written while detached are dropped.
+func (m *testMarkerWriter) detach() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.w != nil && len(m.buf) > 0 {
+		_, _ = m.w.Write(m.buf)
+	}
+
+	m.w = nil
+	m.buf = nil
+}
+
+func (m *testMarkerWriter) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.buf = append(m.buf, p...)
+
+	idx := bytes.LastIndexByte(m.buf, '\n')
+	if idx == -1 {
+		return len(p), nil
+	}
+
+	if m.w != nil {
+		if _, err := m.w.Write(m.buf[:idx+1]); err != nil {
+			return len(p), err
+		}
+	}
+
+	m.buf = append(m.buf[:0], m.buf[idx+1:]...)
+
+	return len(p), nil
+}
+
+// MarkTestStart writes a test start marker.
+func (m *testMarkerWriter) MarkTestStart(testName string) {
+	m.writeMarker("#TEST:START name=" + testName + "\n")
+}
+
+// MarkTestEnd writes a test end marker with the test's status.
+func (m *testMarkerWriter) MarkTestEnd(testName, status string) {
+	m.writeMarker("#TEST:END name=" + testName + " status=" + status + "\n")
+}
+
+func (m *testMarkerWriter) writeMarker(marker string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.w != nil {
+		_, _ = io.WriteString(m.w, marker)
 	}
 }
 
@@ -92,7 +238,8 @@ func (h *fileHook) Fire(entry *logrus.Entry) error {
 // streamLogs streams container logs to file and optionally stdout/benchmarkoor log.
 // The log file should be opened in append mode before calling this function.
 // If blockLogCollector is provided, the collector's writer wraps the file writer
-// to intercept and parse JSON payloads from log lines.
+// to intercept and parse JSON payloads from log lines. If testMarkers is
+// provided, it is attached to the file for the duration of the stream.
 func (r *runner) streamLogs(
 	ctx context.Context,
 	instanceID, containerID string,
@@ -100,30 +247,53 @@ func (r *runner) streamLogs(
 	benchmarkoorLog io.Writer,
 	logInfo *containerLogInfo,
 	blockLogCollector blocklog.Collector,
+	testMarkers *testMarkerWriter,
 ) error {
+	out := r.groupLogWriter(file, logInfo.GenesisGroupHash)
+
 	// Write start marker with container metadata.
-	_, _ = fmt.Fprint(file, formatStartMarker("CONTAINER", logInfo))
+	_, _ = fmt.Fprint(out, formatStartMarker("CONTAINER", logInfo))
+
+	fileWriter := out
+	if testMarkers != nil {
+		fileWriter = testMarkers.attach(out)
+	}
+
+	// Base writer is the file, optionally teed into the block log collector.
+	// The collector parses the raw client lines, so receive timestamps are
+	// only added on the file side.
+	baseWriter := fileWriter
+	if r.cfg.ClientLogTimestamps {
+		baseWriter = &prefixedWriter{prefixFn: receiveTimestampPrefix, writer: fileWriter}
+	}
 
-	// Base writer is the file, optionally wrapped by block log collector.
-	var baseWriter io.Writer = file
 	if blockLogCollector != nil {
-		baseWriter = blockLogCollector.Writer()
+		baseWriter = io.MultiWriter(baseWriter, blockLogCollector.Writer())
 	}
 
 	stdout, stderr := baseWriter, baseWriter
 
 	if r.cfg.ClientLogsToStdout {
-		pfxFn := clientLogPrefix(instanceID)
-		stdoutPrefixWriter := &prefixedWriter{prefixFn: pfxFn, writer: os.Stdout}
-		logFilePrefixWriter := &prefixedWriter{prefixFn: pfxFn, writer: benchmarkoorLog}
+		stdoutPrefixWriter := &prefixedWriter{
+			prefixFn: clientLogPrefix(instanceID, instanceID, r.cfg.DisableLogColors),
+			writer:   os.Stdout,
+		}
+		logFilePrefixWriter := &prefixedWriter{
+			prefixFn: clientLogPrefix(instanceID, instanceID, true),
+			writer:   benchmarkoorLog,
+		}
 		stdout = io.MultiWriter(baseWriter, stdoutPrefixWriter, logFilePrefixWriter)
 		stderr = io.MultiWriter(baseWriter, stdoutPrefixWriter, logFilePrefixWriter)
 	}
 
 	streamErr := r.containerMgr.StreamLogs(ctx, containerID, stdout, stderr)
 
+	if testMarkers != nil {
+		testMarkers.detach()
+	}
+
 	// Write end marker (best-effort, even if streaming failed).
-	_, _ = fmt.Fprintf(file, "#CONTAINER:END\n")
+	_, _ = fmt.Fprintf(out, "#CONTAINER:END\n")
 
 	return streamErr
 }
@@ -139,6 +309,7 @@ func (r *runner) startLogStreaming(
 	benchmarkoorLog io.Writer,
 	logInfo *containerLogInfo,
 	blockLogCollector blocklog.Collector,
+	testMarkers *testMarkerWriter,
 	cleanupStarted <-chan struct{},
 	logDone *chan struct{},
 	logCancel *context.CancelFunc,
@@ -149,8 +320,8 @@ func (r *runner) startLogStreaming(
 
 	logFilePath := filepath.Join(resultsDir, "container.log")
 
-	logFile, err := os.OpenFile(
-		logFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644,
+	logFile, err := fsutil.OpenFile(
+		logFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644, r.cfg.ResultsOwner,
 	)
 	if err != nil {
 		cancel()
@@ -171,7 +342,7 @@ func (r *runner) startLogStreaming(
 
 		if streamErr := r.streamLogs(
 			logCtx, instanceID, containerID, logFile,
-			benchmarkoorLog, logInfo, blockLogCollector,
+			benchmarkoorLog, logInfo, blockLogCollector, testMarkers,
 		); streamErr != nil {
 			select {
 			case <-cleanupStarted:
@@ -0,0 +1,252 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientLogStyleFor_Stable(t *testing.T) {
+	// The mapping is part of the user-visible output; changing the palette
+	// or hash reshuffles everyone's colors.
+	assert.Equal(t, clientLogStyles[5], clientLogStyleFor("geth"))
+	assert.Equal(t, clientLogStyles[1], clientLogStyleFor("nethermind"))
+	assert.Equal(t, clientLogStyles[0], clientLogStyleFor("reth"))
+
+	for _, id := range []string{"geth", "nethermind", "reth", "erigon-archive"} {
+		assert.Equal(t, clientLogStyleFor(id), clientLogStyleFor(id), id)
+	}
+}
+
+func TestClientLogPrefix(t *testing.T) {
+	style := clientLogStyleFor("geth")
+
+	prefix := clientLogPrefix("geth", "geth-init", false)()
+
+	assert.True(t, strings.HasPrefix(prefix, style.emoji+" "), prefix)
+	assert.True(t, strings.HasSuffix(prefix,
+		" CLIE | "+style.color+"geth-init"+clientLogColorReset+" | "), prefix)
+
+	prefix = clientLogPrefix("geth", "geth-init", true)()
+
+	assert.True(t, strings.HasSuffix(prefix, " CLIE | geth-init | "), prefix)
+	assert.NotContains(t, prefix, "\033[")
+}
+
+func TestPrefixedWriter_ClientLogPrefix(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := &prefixedWriter{prefixFn: clientLogPrefix("besu", "besu", false), writer: &buf}
+
+	_, err := w.Write([]byte("first\nsec"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("ond\n"))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	style := clientLogStyleFor("besu")
+	for i, want := range []string{"first", "second"} {
+		assert.True(t, strings.HasPrefix(lines[i], style.emoji), lines[i])
+		assert.True(t, strings.HasSuffix(lines[i], "besu"+clientLogColorReset+" | "+want), lines[i])
+	}
+}
+
+// fakeLogStreamer is a container manager that only streams fixed log output.
+type fakeLogStreamer struct {
+	docker.ContainerManager
+
+	output string
+}
+
+func (f *fakeLogStreamer) StreamLogs(_ context.Context, _ string, stdout, _ io.Writer) error {
+	_, err := io.WriteString(stdout, f.output)
+
+	return err
+}
+
+// streamTestLogs streams output through runner.streamLogs and returns the
+// client lines written to container.log, without the start/end markers.
+func streamTestLogs(t *testing.T, cfg *Config, output string) []string {
+	t.Helper()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "container.log"))
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	r := &runner{cfg: cfg, containerMgr: &fakeLogStreamer{output: output}}
+
+	require.NoError(t, r.streamLogs(
+		context.Background(), "geth", "abc123", file, io.Discard,
+		&containerLogInfo{Name: "benchmarkoor-geth"}, nil, nil,
+	))
+
+	data, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+
+	var lines []string
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func TestStreamLogs_ReceiveTimestamps(t *testing.T) {
+	output := "INFO [01-02|03:04:05.678] Starting Geth\nno timestamp here\n"
+
+	t.Run("disabled", func(t *testing.T) {
+		lines := streamTestLogs(t, &Config{}, output)
+
+		assert.Equal(t, []string{"INFO [01-02|03:04:05.678] Starting Geth", "no timestamp here"}, lines)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		lines := streamTestLogs(t, &Config{ClientLogTimestamps: true}, output)
+		require.Len(t, lines, 2)
+
+		// The receive time is prepended in UTC; the client's own timestamp
+		// is kept as-is.
+		ts := `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}Z `
+		assert.Regexp(t, regexp.MustCompile(ts+`INFO \[01-02\|03:04:05\.678\] Starting Geth$`), lines[0])
+		assert.Regexp(t, regexp.MustCompile(ts+`no timestamp here$`), lines[1])
+	})
+}
+
+func TestTestMarkerWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	m := &testMarkerWriter{}
+	w := m.attach(&buf)
+
+	m.MarkTestStart("test_a.txt")
+	_, _ = io.WriteString(w, "block 1 imported\nblock 2 ")
+
+	// A partial client line is held back so the marker cannot split it.
+	m.MarkTestEnd("test_a.txt", executor.TestMarkerStatusPassed)
+	_, _ = io.WriteString(w, "imported\n")
+
+	m.MarkTestStart("test_b.txt")
+	_, _ = io.WriteString(w, "shutting down")
+	m.detach()
+
+	// Markers are dropped while no log stream is attached.
+	m.MarkTestEnd("test_b.txt", executor.TestMarkerStatusInterrupted)
+
+	assert.Equal(t, "#TEST:START name=test_a.txt\n"+
+		"block 1 imported\n"+
+		"#TEST:END name=test_a.txt status=passed\n"+
+		"block 2 imported\n"+
+		"#TEST:START name=test_b.txt\n"+
+		"shutting down", buf.String())
+}
+
+func TestTestMarkerWriter_Nil(t *testing.T) {
+	var m *testMarkerWriter
+
+	assert.NotPanics(t, func() {
+		m.MarkTestStart("test_a.txt")
+		m.MarkTestEnd("test_a.txt", executor.TestMarkerStatusFailed)
+	})
+}
+
+func TestStreamLogs_TestMarkers(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "container.log"))
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	markers := &testMarkerWriter{}
+	streamer := &markingLogStreamer{markers: markers}
+
+	r := &runner{cfg: &Config{}, containerMgr: streamer}
+
+	require.NoError(t, r.streamLogs(
+		context.Background(), "geth", "abc123", file, io.Discard,
+		&containerLogInfo{Name: "benchmarkoor-geth", Image: "geth:latest"}, nil, markers,
+	))
+
+	data, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+
+	assert.Equal(t, "#CONTAINER:START name=benchmarkoor-geth image=geth:latest\n"+
+		"starting\n"+
+		"#TEST:START name=test_a.txt\n"+
+		"processing block\n"+
+		"#TEST:END name=test_a.txt status=passed\n"+
+		"#CONTAINER:END\n", string(data))
+}
+
+func TestStreamLogs_ParallelGenesisGroups(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "container.log"))
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	markers := &testMarkerWriter{}
+	streamer := &markingLogStreamer{markers: markers}
+
+	r := &runner{cfg: &Config{GenesisGroupParallelism: 2}, containerMgr: streamer}
+
+	require.NoError(t, r.streamLogs(
+		context.Background(), "geth", "abc123", file, io.Discard,
+		&containerLogInfo{Name: "benchmarkoor-geth", Image: "geth:latest", GenesisGroupHash: "0x01"},
+		nil, markers,
+	))
+
+	data, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+
+	// Every line, markers included, carries the genesis group.
+	assert.Equal(t, "[0x01] #CONTAINER:START name=benchmarkoor-geth image=geth:latest genesis_group=0x01\n"+
+		"[0x01] starting\n"+
+		"[0x01] #TEST:START name=test_a.txt\n"+
+		"[0x01] processing block\n"+
+		"[0x01] #TEST:END name=test_a.txt status=passed\n"+
+		"[0x01] #CONTAINER:END\n", string(data))
+}
+
+func TestGroupLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	sequential := &runner{cfg: &Config{GenesisGroupParallelism: 1}}
+	assert.Same(t, &buf, sequential.groupLogWriter(&buf, "0x01"))
+
+	parallel := &runner{cfg: &Config{GenesisGroupParallelism: 2}}
+	assert.Same(t, &buf, parallel.groupLogWriter(&buf, ""), "no genesis group")
+
+	_, _ = io.WriteString(parallel.groupLogWriter(&buf, "0x01"), "a\nb\n")
+	assert.Equal(t, "[0x01] a\n[0x01] b\n", buf.String())
+}
+
+// markingLogStreamer emits client log lines interleaved with the test
+// markers the executor would write while the stream is running.
+type markingLogStreamer struct {
+	docker.ContainerManager
+
+	markers *testMarkerWriter
+}
+
+func (f *markingLogStreamer) StreamLogs(_ context.Context, _ string, stdout, _ io.Writer) error {
+	_, _ = io.WriteString(stdout, "starting\n")
+	f.markers.MarkTestStart("test_a.txt")
+	_, _ = io.WriteString(stdout, "processing block\n")
+	f.markers.MarkTestEnd("test_a.txt", executor.TestMarkerStatusPassed)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pageCacheReadBufferSize is the buffer size used to read files when
+// priming the page cache.
+const pageCacheReadBufferSize = 1024 * 1024
+
+// pageCacheStats summarizes a page cache priming pass.
+type pageCacheStats struct {
+	Files int
+	Bytes int64
+	// Truncated is set when priming stopped at the byte limit.
+	Truncated bool
+}
+
+// primePageCache reads every regular file under dir so its pages are in the
+// page cache before the first test. Reading stops once limit bytes have been
+// read, as priming more than fits in memory only evicts earlier files again.
+// A limit of 0 reads everything. Symlinks are not followed.
+func primePageCache(ctx context.Context, dir string, limit int64) (*pageCacheStats, error) {
+	stats := &pageCacheStats{}
+	buf := make([]byte, pageCacheReadBufferSize)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		size := info.Size()
+		if limit > 0 && stats.Bytes+size > limit {
+			size = limit - stats.Bytes
+			stats.Truncated = true
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer f.Close()
+
+		n, err := io.CopyBuffer(io.Discard, io.LimitReader(f, size), buf)
+		stats.Bytes += n
+
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		stats.Files++
+
+		if stats.Truncated {
+			return filepath.SkipAll
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// runPrimePageCache primes the page cache with the datadir at dir, capped
+// at the currently available memory. Failures are logged and do not abort
+// the run.
+func runPrimePageCache(ctx context.Context, log logrus.FieldLogger, dir string, available uint64) {
+	log.WithField("path", dir).Info("Priming page cache with datadir")
+
+	stats, err := primePageCache(ctx, dir, int64(available))
+	if err != nil {
+		log.WithError(err).Warn("Failed to prime page cache")
+
+		return
+	}
+
+	fields := logrus.Fields{
+		"files": stats.Files,
+		"bytes": stats.Bytes,
+	}
+
+	if stats.Truncated {
+		log.WithFields(fields).Warn(
+			"Datadir is larger than available memory, page cache only partially primed",
+		)
+
+		return
+	}
+
+	log.WithFields(fields).Info("Page cache primed")
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrimeFixture(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "chaindata", "ancient"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "LOCK"), nil, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "chaindata", "000001.ldb"), make([]byte, 3000), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "chaindata", "ancient", "bodies.cdat"), make([]byte, 5000), 0o644))
+	require.NoError(t, os.Symlink(
+		filepath.Join(dir, "chaindata", "ancient", "bodies.cdat"),
+		filepath.Join(dir, "bodies-link"),
+	))
+
+	return dir
+}
+
+func TestPrimePageCache(t *testing.T) {
+	dir := writePrimeFixture(t)
+
+	stats, err := primePageCache(context.Background(), dir, 0)
+	require.NoError(t, err)
+
+	// Symlinks are not followed, so the 5000 byte file is read once.
+	assert.Equal(t, 3, stats.Files)
+	assert.Equal(t, int64(8000), stats.Bytes)
+	assert.False(t, stats.Truncated)
+}
+
+func TestPrimePageCache_Limit(t *testing.T) {
+	dir := writePrimeFixture(t)
+
+	stats, err := primePageCache(context.Background(), dir, 4000)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4000), stats.Bytes)
+	assert.True(t, stats.Truncated)
+}
+
+func TestPrimePageCache_Cancelled(t *testing.T) {
+	dir := writePrimeFixture(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := primePageCache(ctx, dir, 0)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPrimePageCache_MissingDir(t *testing.T) {
+	_, err := primePageCache(context.Background(), filepath.Join(t.TempDir(), "missing"), 0)
+	require.Error(t, err)
+}
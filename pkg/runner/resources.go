@@ -1,8 +1,12 @@
 package runner
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	mrand "math/rand/v2"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -10,6 +14,7 @@ import (
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
 	"github.com/ethpandaops/benchmarkoor/pkg/cpufreq"
 	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/irq"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/sirupsen/logrus"
 )
@@ -77,6 +82,23 @@ func buildContainerResourceLimits(cfg *config.ResourceLimits) (*docker.ResourceL
 		resolved.CpusetCpus = containerLimits.CpusetCpus
 	}
 
+	// Handle CPU bandwidth limit.
+	if cfg.CPUs != "" {
+		nanoCPUs, err := config.ParseNanoCPUs(cfg.CPUs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing cpus: %w", err)
+		}
+
+		containerLimits.NanoCPUs = nanoCPUs
+		resolved.CPUs = cfg.CPUs
+		resolved.NanoCPUs = nanoCPUs
+	} else if cfg.CPUQuota > 0 {
+		containerLimits.CPUQuota = cfg.CPUQuota
+		containerLimits.CPUPeriod = int64(cfg.CPUPeriod)
+		resolved.CPUQuota = cfg.CPUQuota
+		resolved.CPUPeriod = cfg.CPUPeriod
+	}
+
 	// Handle memory limit.
 	if cfg.Memory != "" {
 		memBytes, err := units.RAMInBytes(cfg.Memory)
@@ -99,6 +121,18 @@ func buildContainerResourceLimits(cfg *config.ResourceLimits) (*docker.ResourceL
 		}
 	}
 
+	// Handle memory reservation (soft limit).
+	if cfg.MemoryReservation != "" {
+		reservation, err := config.ParseByteSize(cfg.MemoryReservation)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing memory reservation: %w", err)
+		}
+
+		containerLimits.MemoryReservation = int64(reservation)
+		resolved.MemoryReservation = cfg.MemoryReservation
+		resolved.MemoryReservationBytes = int64(reservation)
+	}
+
 	// Handle blkio config.
 	if cfg.BlkioConfig != nil {
 		blkioCfg := cfg.BlkioConfig
@@ -124,9 +158,20 @@ func buildContainerResourceLimits(cfg *config.ResourceLimits) (*docker.ResourceL
 			containerLimits.BlkioDeviceWriteIOps, resolvedBlkio.DeviceWriteIOps = convertBlkioDevicesIOps(blkioCfg.DeviceWriteIOps)
 		}
 
+		// Process weight and device_weight.
+		if blkioCfg.Weight > 0 {
+			containerLimits.BlkioWeight = blkioCfg.Weight
+			resolvedBlkio.Weight = blkioCfg.Weight
+		}
+
+		if len(blkioCfg.DeviceWeight) > 0 {
+			containerLimits.BlkioWeightDevice, resolvedBlkio.DeviceWeight = convertBlkioWeightDevices(blkioCfg.DeviceWeight)
+		}
+
 		// Only set if we have any blkio config.
 		if len(resolvedBlkio.DeviceReadBps) > 0 || len(resolvedBlkio.DeviceWriteBps) > 0 ||
-			len(resolvedBlkio.DeviceReadIOps) > 0 || len(resolvedBlkio.DeviceWriteIOps) > 0 {
+			len(resolvedBlkio.DeviceReadIOps) > 0 || len(resolvedBlkio.DeviceWriteIOps) > 0 ||
+			resolvedBlkio.Weight > 0 || len(resolvedBlkio.DeviceWeight) > 0 {
 			resolved.BlkioConfig = resolvedBlkio
 		}
 	}
@@ -134,6 +179,25 @@ func buildContainerResourceLimits(cfg *config.ResourceLimits) (*docker.ResourceL
 	return containerLimits, resolved, nil
 }
 
+// buildContainerDevices converts config device mappings to docker devices,
+// filling in the default container path and permissions.
+func buildContainerDevices(devices []config.Device) []docker.Device {
+	if len(devices) == 0 {
+		return nil
+	}
+
+	result := make([]docker.Device, len(devices))
+	for i := range devices {
+		result[i] = docker.Device{
+			HostPath:      devices[i].HostPath,
+			ContainerPath: devices[i].GetContainerPath(),
+			Permissions:   devices[i].GetPermissions(),
+		}
+	}
+
+	return result
+}
+
 // convertBlkioDevicesBps converts config blkio devices with bps rates to docker and resolved formats.
 func convertBlkioDevicesBps(devices []config.ThrottleDevice) ([]docker.BlkioThrottleDevice, []ResolvedThrottleDevice) {
 	dockerDevices := make([]docker.BlkioThrottleDevice, len(devices))
@@ -178,6 +242,25 @@ func convertBlkioDevicesIOps(devices []config.ThrottleDevice) ([]docker.BlkioThr
 	return dockerDevices, resolvedDevices
 }
 
+// convertBlkioWeightDevices converts config blkio weight devices to docker and resolved formats.
+func convertBlkioWeightDevices(devices []config.WeightDevice) ([]docker.BlkioWeightDevice, []ResolvedWeightDevice) {
+	dockerDevices := make([]docker.BlkioWeightDevice, len(devices))
+	resolvedDevices := make([]ResolvedWeightDevice, len(devices))
+
+	for i, dev := range devices {
+		dockerDevices[i] = docker.BlkioWeightDevice{
+			Path:   dev.Path,
+			Weight: dev.Weight,
+		}
+		resolvedDevices[i] = ResolvedWeightDevice{
+			Path:   dev.Path,
+			Weight: dev.Weight,
+		}
+	}
+
+	return dockerDevices, resolvedDevices
+}
+
 // hasCPUFreqSettings returns true if the resource limits have any CPU frequency settings.
 func hasCPUFreqSettings(cfg *config.ResourceLimits) bool {
 	if cfg == nil {
@@ -239,3 +322,265 @@ func logCPUFreqInfo(log logrus.FieldLogger, mgr cpufreq.Manager, targetCPUs []in
 		}).Info("CPU frequency info")
 	}
 }
+
+// applyIRQAffinity records the CPUs servicing NVMe and NIC interrupts in
+// resolved and warns when they overlap the pinned CPUs. In move mode, those
+// interrupts are moved to the unpinned CPUs and the returned function
+// restores their original affinity, which is also saved to a state file in
+// stateDir for the cleanup command. Failures are logged, not fatal.
+func applyIRQAffinity(
+	log logrus.FieldLogger,
+	procPath, stateDir, mode string,
+	pinned []int,
+	resolved *ResolvedResourceLimits,
+) func() {
+	table, err := irq.ReadInterrupts(procPath)
+	if err != nil {
+		log.WithError(err).Warn("Failed to read interrupts, skipping irq_affinity")
+
+		return nil
+	}
+
+	device := table.DeviceInterrupts()
+	irqCPUs := irq.HandlingCPUs(device)
+
+	resolved.IRQAffinity = mode
+	resolved.IRQCPUs = irq.FormatCPUList(irqCPUs)
+
+	overlap := irq.Overlap(irqCPUs, pinned)
+	if len(overlap) == 0 {
+		log.WithField("irq_cpus", resolved.IRQCPUs).Info("No device IRQs on pinned CPUs")
+
+		return nil
+	}
+
+	fields := logrus.Fields{
+		"irq_cpus":    resolved.IRQCPUs,
+		"pinned_cpus": irq.FormatCPUList(pinned),
+		"overlap":     irq.FormatCPUList(overlap),
+	}
+
+	if mode != config.IRQAffinityMove {
+		log.WithFields(fields).Warn(
+			"NVMe/NIC interrupts are serviced on pinned CPUs and may add noise; " +
+				"set irq_affinity: move to move them",
+		)
+
+		return nil
+	}
+
+	mgr := irq.NewAffinityManager(log, procPath, stateDir)
+
+	moved, err := mgr.MoveAway(device, pinned, table.CPUs)
+	if err != nil {
+		log.WithFields(fields).WithError(err).Warn("Failed to move some device IRQs off pinned CPUs")
+	}
+
+	if moved == 0 {
+		return nil
+	}
+
+	log.WithFields(fields).WithField("moved", moved).Info("Moved device IRQs off pinned CPUs")
+
+	return func() {
+		if err := mgr.Restore(); err != nil {
+			log.WithError(err).Warn("Failed to restore IRQ affinity")
+		}
+	}
+}
+
+// restartCounter reports how often a container was restarted by the runtime.
+type restartCounter interface {
+	GetContainerRestartCount(ctx context.Context, containerID string) (int, error)
+}
+
+// checkContainerRestarts returns how often the container was restarted by
+// the runtime under its restart policy. A restart means the client process
+// started over mid-run, so the results are not comparable. Inspect failures
+// are logged and treated as no restarts.
+func checkContainerRestarts(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	counter restartCounter,
+	containerID string,
+) int {
+	count, err := counter.GetContainerRestartCount(ctx, containerID)
+	if err != nil {
+		log.WithError(err).Warn("Failed to check container restart count")
+
+		return 0
+	}
+
+	if count > 0 {
+		log.WithField("restart_count", count).Warn(
+			"Container was restarted by the container runtime during the run",
+		)
+	}
+
+	return count
+}
+
+// resolveBlockDevice returns the block device backing dir, using the mount
+// table at mountsPath (normally /proc/mounts) and the block device tree at
+// sysBlockPath (normally /sys/class/block). Partitions resolve to their
+// parent disk, since blkio throttling applies to whole disks.
+func resolveBlockDevice(mountsPath, sysBlockPath, dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", dir, err)
+	}
+
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+
+	source, mountPoint, err := findMountSource(mountsPath, absDir)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(source, "/dev/") {
+		return "", fmt.Errorf("%s is mounted from %q at %s, which is not a block device",
+			dir, source, mountPoint)
+	}
+
+	name := filepath.Base(source)
+	if _, err := os.Stat(filepath.Join(sysBlockPath, name)); err != nil {
+		// Device mapper and by-id paths are symlinks to the kernel name.
+		resolved, evalErr := filepath.EvalSymlinks(source)
+		if evalErr != nil {
+			return "", fmt.Errorf("block device %s not found in %s", source, sysBlockPath)
+		}
+
+		name = filepath.Base(resolved)
+		if _, err := os.Stat(filepath.Join(sysBlockPath, name)); err != nil {
+			return "", fmt.Errorf("block device %s not found in %s", source, sysBlockPath)
+		}
+	}
+
+	// A partition's sysfs entry lives inside its parent disk's entry.
+	if _, err := os.Stat(filepath.Join(sysBlockPath, name, "partition")); err == nil {
+		entry, err := filepath.EvalSymlinks(filepath.Join(sysBlockPath, name))
+		if err != nil {
+			return "", fmt.Errorf("resolving parent of partition %s: %w", name, err)
+		}
+
+		name = filepath.Base(filepath.Dir(entry))
+	}
+
+	return "/dev/" + name, nil
+}
+
+// findMountSource returns the source and mount point of the mount in the
+// mount table at mountsPath that contains dir.
+func findMountSource(mountsPath, dir string) (source, mountPoint string, err error) {
+	file, err := os.Open(mountsPath)
+	if err != nil {
+		return "", "", fmt.Errorf("reading mount table: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		mp := unescapeMountField(fields[1])
+
+		// The longest (last, for overmounts) containing mount point wins.
+		if isPathWithin(dir, mp) && len(mp) >= len(mountPoint) {
+			source, mountPoint = unescapeMountField(fields[0]), mp
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("reading mount table: %w", err)
+	}
+
+	if mountPoint == "" {
+		return "", "", fmt.Errorf("no mount found for %s", dir)
+	}
+
+	return source, mountPoint, nil
+}
+
+// unescapeMountField decodes the octal escapes (e.g. \040 for a space) used
+// in /proc/mounts fields.
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if v, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+
+				continue
+			}
+		}
+
+		b.WriteByte(field[i])
+	}
+
+	return b.String()
+}
+
+// isPathWithin reports whether path is dir or lies below it.
+func isPathWithin(path, dir string) bool {
+	if dir == "/" {
+		return true
+	}
+
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// withBlkioDevice returns a copy of cfg with every "auto" blkio device path
+// replaced by device.
+func withBlkioDevice(cfg *config.ResourceLimits, device string) *config.ResourceLimits {
+	replace := func(devices []config.ThrottleDevice) []config.ThrottleDevice {
+		if devices == nil {
+			return nil
+		}
+
+		out := make([]config.ThrottleDevice, len(devices))
+		for i, dev := range devices {
+			if dev.Path == config.BlkioDeviceAuto {
+				dev.Path = device
+			}
+
+			out[i] = dev
+		}
+
+		return out
+	}
+
+	var weightDevices []config.WeightDevice
+	if cfg.BlkioConfig.DeviceWeight != nil {
+		weightDevices = make([]config.WeightDevice, len(cfg.BlkioConfig.DeviceWeight))
+		for i, dev := range cfg.BlkioConfig.DeviceWeight {
+			if dev.Path == config.BlkioDeviceAuto {
+				dev.Path = device
+			}
+
+			weightDevices[i] = dev
+		}
+	}
+
+	limits := *cfg
+	limits.BlkioConfig = &config.BlkioConfig{
+		DeviceReadBps:   replace(cfg.BlkioConfig.DeviceReadBps),
+		DeviceReadIOps:  replace(cfg.BlkioConfig.DeviceReadIOps),
+		DeviceWriteBps:  replace(cfg.BlkioConfig.DeviceWriteBps),
+		DeviceWriteIOps: replace(cfg.BlkioConfig.DeviceWriteIOps),
+		Weight:          cfg.BlkioConfig.Weight,
+		DeviceWeight:    weightDevices,
+	}
+
+	return &limits
+}
@@ -80,11 +80,34 @@ func (r *runner) checkRPCHealth(ctx context.Context, url string) (string, bool)
 
 // getLatestBlock fetches the latest block number, hash, and state root from the RPC endpoint.
 func (r *runner) getLatestBlock(ctx context.Context, host string, port int) (uint64, string, string, error) {
+	return r.getBlock(ctx, host, port, "latest")
+}
+
+// verifyGenesisBlock checks that the client's genesis block has the expected
+// hash, so a client started with the wrong genesis fails before any test runs.
+func (r *runner) verifyGenesisBlock(ctx context.Context, host string, port int, expected string) error {
+	_, hash, _, err := r.getBlock(ctx, host, port, "0x0")
+	if err != nil {
+		return fmt.Errorf("getting genesis block: %w", err)
+	}
+
+	if !strings.EqualFold(hash, expected) {
+		return fmt.Errorf("genesis block hash %s does not match expected %s", hash, expected)
+	}
+
+	return nil
+}
+
+// getBlock fetches the number, hash, and state root of the block identified
+// by blockTag (a block tag or hex block number) from the RPC endpoint.
+func (r *runner) getBlock(ctx context.Context, host string, port int, blockTag string) (uint64, string, string, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	url := fmt.Sprintf("http://%s:%d", host, port)
-	body := `{"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["latest",false],"id":1}`
+	body := fmt.Sprintf(
+		`{"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["%s",false],"id":1}`, blockTag,
+	)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
 	if err != nil {
@@ -142,20 +165,12 @@ func (r *runner) sendBootstrapFCU(
 	headBlockHash string,
 	cfg *config.BootstrapFCUConfig,
 ) error {
-	const zeroHash = "0x0000000000000000000000000000000000000000000000000000000000000000"
-
 	backoff, err := time.ParseDuration(cfg.Backoff)
 	if err != nil {
 		return fmt.Errorf("parsing backoff duration: %w", err)
 	}
 
-	// Build the forkchoiceUpdatedV3 payload.
-	payload := fmt.Sprintf(
-		`{"jsonrpc":"2.0","method":"engine_forkchoiceUpdatedV3",`+
-			`"params":[{"headBlockHash":"%s","safeBlockHash":"%s",`+
-			`"finalizedBlockHash":"%s"},null],"id":1}`,
-		headBlockHash, zeroHash, zeroHash,
-	)
+	payload := forkchoiceUpdatedPayload(headBlockHash)
 
 	url := fmt.Sprintf("http://%s:%d", host, enginePort)
 
@@ -195,6 +210,19 @@ func (r *runner) sendBootstrapFCU(
 	return fmt.Errorf("bootstrap FCU failed after %d attempts: %w", cfg.MaxRetries, lastErr)
 }
 
+// forkchoiceUpdatedPayload builds an engine_forkchoiceUpdatedV3 request that
+// sets headBlockHash as head, without payload attributes.
+func forkchoiceUpdatedPayload(headBlockHash string) string {
+	const zeroHash = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+	return fmt.Sprintf(
+		`{"jsonrpc":"2.0","method":"engine_forkchoiceUpdatedV3",`+
+			`"params":[{"headBlockHash":"%s","safeBlockHash":"%s",`+
+			`"finalizedBlockHash":"%s"},null],"id":1}`,
+		headBlockHash, zeroHash, zeroHash,
+	)
+}
+
 // doBootstrapFCURequest performs a single bootstrap FCU HTTP request.
 func (r *runner) doBootstrapFCURequest(
 	ctx context.Context,
@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyGenesisBlock(t *testing.T) {
+	const genesisHash = "0xaaaa000000000000000000000000000000000000000000000000000000000000"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params []any  `json:"params"`
+		}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "eth_getBlockByNumber", req.Method)
+		assert.Equal(t, "0x0", req.Params[0])
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x0",` +
+			`"hash":"` + genesisHash + `","stateRoot":"0x01"}}`))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	r := &runner{log: discardLogger(), cfg: &Config{}}
+
+	t.Run("match", func(t *testing.T) {
+		require.NoError(t, r.verifyGenesisBlock(t.Context(), u.Hostname(), port, genesisHash))
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		expected := "0xbbbb000000000000000000000000000000000000000000000000000000000000"
+
+		err := r.verifyGenesisBlock(t.Context(), u.Hostname(), port, expected)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "genesis block hash "+genesisHash+" does not match expected "+expected)
+	})
+}
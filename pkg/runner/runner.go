@@ -7,18 +7,23 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
-	dockerclient "github.com/docker/docker/client"
 	"github.com/ethpandaops/benchmarkoor/pkg/blocklog"
 	"github.com/ethpandaops/benchmarkoor/pkg/client"
 	"github.com/ethpandaops/benchmarkoor/pkg/config"
 	"github.com/ethpandaops/benchmarkoor/pkg/cpufreq"
 	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
 	"github.com/ethpandaops/benchmarkoor/pkg/executor"
 	"github.com/ethpandaops/benchmarkoor/pkg/fsutil"
+	"github.com/ethpandaops/benchmarkoor/pkg/stats"
+	"github.com/ethpandaops/benchmarkoor/pkg/thp"
 	"github.com/ethpandaops/benchmarkoor/pkg/upload"
+	"github.com/shirou/gopsutil/v4/mem"
 	"github.com/sirupsen/logrus"
 )
 
@@ -32,6 +37,9 @@ const (
 	// logDrainTimeout is the maximum time to wait for log streaming to
 	// finish after a container has been stopped.
 	logDrainTimeout = 5 * time.Second
+
+	// ociRevisionLabel is the OCI image label holding the source commit.
+	ociRevisionLabel = "org.opencontainers.image.revision"
 )
 
 // Runner orchestrates client container lifecycle.
@@ -44,29 +52,48 @@ type Runner interface {
 
 	// RunAll runs all configured instances sequentially.
 	RunAll(ctx context.Context) error
+
+	// ExitCode returns the most severe exit code of the runs so far.
+	ExitCode() int
+
+	// RunDirs returns the result directories of the runs so far, in order.
+	RunDirs() []string
 }
 
 // Config for the runner.
 type Config struct {
-	ResultsDir         string
-	ResultsOwner       *fsutil.OwnerConfig // Optional file ownership for results directory
-	ClientLogsToStdout bool
-	ContainerNetwork   string
-	JWT                string
-	GenesisURLs        map[string]string
-	DataDirs           map[string]*config.DataDirConfig
-	TmpDataDir         string // Directory for temporary datadir copies (empty = system default)
-	TmpCacheDir        string // Directory for temporary cache files (empty = system default)
-	ReadyTimeout       time.Duration
-	TestFilter         string
-	FullConfig         *config.Config // Full config for resolving per-instance settings
+	ResultsDir              string
+	ResultsOwner            *fsutil.OwnerConfig // Optional file ownership for results directory
+	ResultsDirTemplate      string              // Run directory name template (empty = config.DefaultResultsDirTemplate)
+	ResultsLayout           string              // Placement of run directories under runs/ (empty = config.ResultsLayoutFlat)
+	ClientLogsToStdout      bool
+	ClientLogTimestamps     bool             // Prefix container.log lines with the UTC receive time
+	DisableLogColors        bool             // Leave ANSI colors out of client log prefixes on stdout
+	LogFileFormatter        logrus.Formatter // Uncolored formatter for benchmarkoor.log (nil = the logger's formatter)
+	ContainerNetwork        string
+	JWT                     string
+	GenesisURLs             map[string]string
+	DownloadRetry           download.RetryPolicy // Retries for genesis downloads
+	Offline                 bool                 // Fail instead of downloading genesis files from URLs
+	DataDirs                map[string]*config.DataDirConfig
+	TmpDataDir              string // Directory for temporary datadir copies (empty = system default)
+	TmpCacheDir             string // Directory for temporary cache files (empty = system default)
+	StateDir                string // Directory for IRQ affinity state files restored by cleanup (empty = system temp dir)
+	RunDirsFile             string // File each run directory is appended to once created (empty = none)
+	ReadyTimeout            time.Duration
+	TestFilter              string
+	RerunTests              []string       // Optional test names to run instead of all tests (e.g. the failed tests of a previous run)
+	VerifyGenesisHash       bool           // Check each genesis group's genesis block hash after the client starts
+	GenesisGroupParallelism int            // Genesis groups of an instance run at once (0 or 1 = one at a time)
+	FullConfig              *config.Config // Full config for resolving per-instance settings
 }
 
 // TestCounts contains test count statistics for a run.
 type TestCounts struct {
-	Total  int `json:"total"`
-	Passed int `json:"passed"`
-	Failed int `json:"failed"`
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped,omitempty"`
 }
 
 // StartBlock contains block information captured at the start of a run.
@@ -83,6 +110,7 @@ type RunConfig struct {
 	SuiteHash                      string                 `json:"suite_hash,omitempty"`
 	SystemResourceCollectionMethod string                 `json:"system_resource_collection_method,omitempty"`
 	System                         *SystemInfo            `json:"system"`
+	MachineFingerprint             string                 `json:"machine_fingerprint,omitempty"`
 	Instance                       *ResolvedInstance      `json:"instance"`
 	Metadata                       *config.MetadataConfig `json:"metadata,omitempty"`
 	StartBlock                     *StartBlock            `json:"start_block,omitempty"`
@@ -91,6 +119,7 @@ type RunConfig struct {
 	TerminationReason              string                 `json:"termination_reason,omitempty"`
 	ContainerExitCode              *int64                 `json:"container_exit_code,omitempty"`
 	ContainerOOMKilled             *bool                  `json:"container_oom_killed,omitempty"`
+	ContainerRestartCount          int                    `json:"container_restart_count,omitempty"`
 }
 
 // Run status constants.
@@ -104,32 +133,50 @@ const (
 
 // SystemInfo contains system hardware and OS information.
 type SystemInfo struct {
-	Hostname           string  `json:"hostname"`
-	OS                 string  `json:"os"`
-	Platform           string  `json:"platform"`
-	PlatformVersion    string  `json:"platform_version"`
-	KernelVersion      string  `json:"kernel_version"`
-	Arch               string  `json:"arch"`
-	Virtualization     string  `json:"virtualization,omitempty"`
-	VirtualizationRole string  `json:"virtualization_role,omitempty"`
-	CPUVendor          string  `json:"cpu_vendor"`
-	CPUModel           string  `json:"cpu_model"`
-	CPUCores           int     `json:"cpu_cores"`
-	CPUMhz             float64 `json:"cpu_mhz"`
-	CPUCacheKB         int     `json:"cpu_cache_kb"`
-	MemoryTotalGB      float64 `json:"memory_total_gb"`
+	Hostname              string  `json:"hostname"`
+	OS                    string  `json:"os"`
+	Platform              string  `json:"platform"`
+	PlatformVersion       string  `json:"platform_version"`
+	KernelVersion         string  `json:"kernel_version"`
+	Arch                  string  `json:"arch"`
+	Virtualization        string  `json:"virtualization,omitempty"`
+	VirtualizationRole    string  `json:"virtualization_role,omitempty"`
+	CPUVendor             string  `json:"cpu_vendor"`
+	CPUModel              string  `json:"cpu_model"`
+	CPUCores              int     `json:"cpu_cores"`
+	CPUMhz                float64 `json:"cpu_mhz"`
+	CPUCacheKB            int     `json:"cpu_cache_kb"`
+	CPUGovernor           string  `json:"cpu_freq_governor,omitempty"`
+	CPUTurboBoost         *bool   `json:"cpu_turboboost,omitempty"`
+	MemoryTotalGB         float64 `json:"memory_total_gb"`
+	SwapTotalGB           float64 `json:"swap_total_gb"`
+	THPMode               string  `json:"transparent_hugepage,omitempty"`
+	DiskBenchTool         string  `json:"disk_bench_tool,omitempty"`
+	DiskBenchPath         string  `json:"disk_bench_path,omitempty"`
+	DiskSeqWriteMBps      float64 `json:"disk_seq_write_mbps,omitempty"`
+	DiskRandReadIOPS      float64 `json:"disk_rand_read_iops,omitempty"`
+	DiskRandReadLatencyUs float64 `json:"disk_rand_read_latency_us,omitempty"`
 }
 
 // ResolvedResourceLimits contains the resolved resource limits for config.json output.
 type ResolvedResourceLimits struct {
-	CpusetCpus    string               `json:"cpuset_cpus,omitempty"`
-	Memory        string               `json:"memory,omitempty"`
-	MemoryBytes   int64                `json:"memory_bytes,omitempty"`
-	SwapDisabled  bool                 `json:"swap_disabled,omitempty"`
-	BlkioConfig   *ResolvedBlkioConfig `json:"blkio_config,omitempty"`
-	CPUFreqKHz    *uint64              `json:"cpu_freq_khz,omitempty"`
-	CPUTurboBoost *bool                `json:"cpu_turboboost,omitempty"`
-	CPUGovernor   string               `json:"cpu_freq_governor,omitempty"`
+	CpusetCpus             string               `json:"cpuset_cpus,omitempty"`
+	CPUs                   string               `json:"cpus,omitempty"`
+	NanoCPUs               int64                `json:"nano_cpus,omitempty"`
+	CPUQuota               int64                `json:"cpu_quota,omitempty"`
+	CPUPeriod              uint64               `json:"cpu_period,omitempty"`
+	Memory                 string               `json:"memory,omitempty"`
+	MemoryBytes            int64                `json:"memory_bytes,omitempty"`
+	MemoryReservation      string               `json:"memory_reservation,omitempty"`
+	MemoryReservationBytes int64                `json:"memory_reservation_bytes,omitempty"`
+	SwapDisabled           bool                 `json:"swap_disabled,omitempty"`
+	BlkioConfig            *ResolvedBlkioConfig `json:"blkio_config,omitempty"`
+	CPUFreqKHz             *uint64              `json:"cpu_freq_khz,omitempty"`
+	CPUTurboBoost          *bool                `json:"cpu_turboboost,omitempty"`
+	CPUGovernor            string               `json:"cpu_freq_governor,omitempty"`
+	THPMode                string               `json:"transparent_hugepage,omitempty"`
+	IRQAffinity            string               `json:"irq_affinity,omitempty"`
+	IRQCPUs                string               `json:"irq_cpus,omitempty"`
 }
 
 // ResolvedBlkioConfig contains the resolved blkio configuration for config.json output.
@@ -138,6 +185,8 @@ type ResolvedBlkioConfig struct {
 	DeviceReadIOps  []ResolvedThrottleDevice `json:"device_read_iops,omitempty"`
 	DeviceWriteBps  []ResolvedThrottleDevice `json:"device_write_bps,omitempty"`
 	DeviceWriteIOps []ResolvedThrottleDevice `json:"device_write_iops,omitempty"`
+	Weight          uint16                   `json:"weight,omitempty"`
+	DeviceWeight    []ResolvedWeightDevice   `json:"device_weight,omitempty"`
 }
 
 // ResolvedThrottleDevice contains a resolved throttle device for config.json output.
@@ -146,6 +195,12 @@ type ResolvedThrottleDevice struct {
 	Rate uint64 `json:"rate"`
 }
 
+// ResolvedWeightDevice contains a resolved weight device for config.json output.
+type ResolvedWeightDevice struct {
+	Path   string `json:"path"`
+	Weight uint16 `json:"weight"`
+}
+
 // ResolvedInstance contains the resolved configuration for a client instance.
 type ResolvedInstance struct {
 	ID                               string                                   `json:"id"`
@@ -153,9 +208,12 @@ type ResolvedInstance struct {
 	ContainerRuntime                 string                                   `json:"container_runtime,omitempty"`
 	Image                            string                                   `json:"image"`
 	ImageSHA256                      string                                   `json:"image_sha256,omitempty"`
+	ClientCommit                     string                                   `json:"client_commit,omitempty"`
+	Build                            *config.BuildConfig                      `json:"build,omitempty"`
 	Entrypoint                       []string                                 `json:"entrypoint,omitempty"`
 	Command                          []string                                 `json:"command,omitempty"`
 	ExtraArgs                        []string                                 `json:"extra_args,omitempty"`
+	RemoveDefaultArgs                []string                                 `json:"remove_default_args,omitempty"`
 	PullPolicy                       string                                   `json:"pull_policy"`
 	Restart                          string                                   `json:"restart,omitempty"`
 	Environment                      map[string]string                        `json:"environment,omitempty"`
@@ -165,10 +223,14 @@ type ResolvedInstance struct {
 	ClientVersion                    string                                   `json:"client_version,omitempty"`
 	RollbackStrategy                 string                                   `json:"rollback_strategy,omitempty"`
 	DropMemoryCaches                 string                                   `json:"drop_memory_caches,omitempty"`
+	PrimePageCache                   bool                                     `json:"prime_page_cache,omitempty"`
+	SyncBetweenTests                 bool                                     `json:"sync_between_tests,omitempty"`
 	WaitAfterRPCReady                string                                   `json:"wait_after_rpc_ready,omitempty"`
 	RunTimeout                       string                                   `json:"run_timeout,omitempty"`
 	RetryNewPayloadsSyncingState     *config.RetryNewPayloadsSyncingConfig    `json:"retry_new_payloads_syncing_state,omitempty"`
 	ResourceLimits                   *ResolvedResourceLimits                  `json:"resource_limits,omitempty"`
+	Devices                          []config.Device                          `json:"devices,omitempty"`
+	SecretFiles                      []string                                 `json:"secret_files,omitempty"`
 	PostTestRPCCalls                 []config.PostTestRPCCall                 `json:"post_test_rpc_calls,omitempty"`
 	PostTestSleepDuration            string                                   `json:"post_test_sleep_duration,omitempty"`
 	BootstrapFCU                     *config.BootstrapFCUConfig               `json:"bootstrap_fcu,omitempty"`
@@ -183,6 +245,7 @@ func NewRunner(
 	registry client.Registry,
 	exec executor.Executor,
 	cpufreqMgr cpufreq.Manager,
+	thpMgr thp.Manager,
 	uploader upload.Uploader,
 ) Runner {
 	if cfg.ReadyTimeout == 0 {
@@ -197,6 +260,7 @@ func NewRunner(
 		registry:     registry,
 		executor:     exec,
 		cpufreqMgr:   cpufreqMgr,
+		thpMgr:       thpMgr,
 		uploader:     uploader,
 		done:         make(chan struct{}),
 	}
@@ -210,17 +274,58 @@ type runner struct {
 	registry     client.Registry
 	executor     executor.Executor
 	cpufreqMgr   cpufreq.Manager
+	thpMgr       thp.Manager
 	uploader     upload.Uploader
 	done         chan struct{}
 	wg           sync.WaitGroup
+
+	exitCodeMu sync.Mutex
+	exitCode   int
+
+	runDirsMu sync.Mutex
+	runDirs   []string
 }
 
 // Ensure interface compliance.
 var _ Runner = (*runner)(nil)
 
+// RunDirs returns the result directories of the runs so far, in order.
+func (r *runner) RunDirs() []string {
+	r.runDirsMu.Lock()
+	defer r.runDirsMu.Unlock()
+
+	return slices.Clone(r.runDirs)
+}
+
+// recordRunDir adds a run directory to RunDirs and, when configured, appends
+// it to the run dirs file so that other processes can follow the run's logs.
+func (r *runner) recordRunDir(dir string) {
+	r.runDirsMu.Lock()
+	defer r.runDirsMu.Unlock()
+
+	r.runDirs = append(r.runDirs, dir)
+
+	if r.cfg.RunDirsFile == "" {
+		return
+	}
+
+	f, err := os.OpenFile(r.cfg.RunDirsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		r.log.WithError(err).Warn("Failed to open run dirs file")
+
+		return
+	}
+
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.WriteString(dir + "\n"); err != nil {
+		r.log.WithError(err).Warn("Failed to record run directory")
+	}
+}
+
 // getDockerClient returns the underlying Docker client if the container manager
 // is a Docker manager, or nil otherwise (e.g., when using Podman).
-func (r *runner) getDockerClient() *dockerclient.Client {
+func (r *runner) getDockerClient() stats.StatsClient {
 	if dm, ok := r.containerMgr.(docker.Manager); ok {
 		return dm.GetClient()
 	}
@@ -228,6 +333,16 @@ func (r *runner) getDockerClient() *dockerclient.Client {
 	return nil
 }
 
+// dropCachesCommand returns the configured command that drops caches in
+// place of writing the drop_caches file, or nil if none is configured.
+func (r *runner) dropCachesCommand() []string {
+	if r.cfg.FullConfig == nil {
+		return nil
+	}
+
+	return r.cfg.FullConfig.Runner.DropCachesCommand
+}
+
 // Start initializes the runner.
 func (r *runner) Start(ctx context.Context) error {
 	// Ensure results directory exists.
@@ -240,11 +355,56 @@ func (r *runner) Start(ctx context.Context) error {
 		return fmt.Errorf("ensuring container network: %w", err)
 	}
 
+	if swap, err := mem.SwapMemory(); err != nil {
+		r.log.WithError(err).Debug("Failed to read host swap state")
+	} else if err := r.checkHostSwap(swap.Total); err != nil {
+		return err
+	}
+
 	r.log.Debug("Runner started")
 
 	return nil
 }
 
+// checkHostSwap warns, or fails when runner.fail_on_host_swap is set, if the
+// host has swap and any instance limits memory without swap_disabled, as its
+// client could then swap instead of hitting the limit.
+func (r *runner) checkHostSwap(swapTotal uint64) error {
+	if swapTotal == 0 || r.cfg.FullConfig == nil {
+		return nil
+	}
+
+	var instances []string
+
+	for i := range r.cfg.FullConfig.Runner.Instances {
+		instance := &r.cfg.FullConfig.Runner.Instances[i]
+
+		limits := r.cfg.FullConfig.GetResourceLimits(instance)
+		if limits != nil && limits.Memory != "" && !limits.SwapDisabled {
+			instances = append(instances, instance.ID)
+		}
+	}
+
+	if len(instances) == 0 {
+		return nil
+	}
+
+	if r.cfg.FullConfig.Runner.FailOnHostSwap {
+		return fmt.Errorf(
+			"host swap is enabled and instances %s set a memory limit without swap_disabled",
+			strings.Join(instances, ", "),
+		)
+	}
+
+	r.log.WithFields(logrus.Fields{
+		"swap_total_gb": float64(swapTotal) / (1024 * 1024 * 1024),
+		"instances":     instances,
+	}).Warn("Host swap is enabled and memory-limited instances may swap, " +
+		"skewing results; set resource_limits.swap_disabled: true")
+
+	return nil
+}
+
 // Stop cleans up the runner.
 func (r *runner) Stop() error {
 	close(r.done)
@@ -335,45 +495,87 @@ func formatStartMarker(marker string, info *containerLogInfo) string {
 
 // containerRunParams contains parameters for a single container lifecycle run.
 type containerRunParams struct {
-	Instance             *config.ClientInstance
-	RunID                string
-	RunTimestamp         int64
-	RunResultsDir        string
-	BenchmarkoorLog      *os.File
-	LogHook              *fileHook
-	GenesisSource        string                    // Path or URL to genesis file.
-	Tests                []*executor.TestWithSteps // Optional test subset (nil = all).
-	GenesisGroupHash     string                    // Non-empty when running a specific genesis group.
-	GenesisGroups        map[string]string         // All genesis hash → path mappings (multi-genesis).
-	ImageName            string                    // Resolved image name (pulled once by caller).
-	ImageDigest          string                    // Image SHA256 digest (resolved once by caller).
-	ContainerSpec        *docker.ContainerSpec     // Saved for container-recreate strategy.
-	DataDirCfg           *config.DataDirConfig     // Resolved datadir config (nil if not using datadir).
-	UseDataDir           bool                      // Whether a pre-populated datadir is used.
-	BlockLogCollector    blocklog.Collector        // Optional collector for capturing block logs.
-	AccumulatedTestCount *TestCounts               // Shared across genesis groups for accumulation.
+	Instance          *config.ClientInstance
+	RunID             string
+	RunTimestamp      int64
+	RunResultsDir     string
+	BenchmarkoorLog   *os.File
+	LogHook           *fileHook
+	GenesisSource     string                    // Path or URL to genesis file.
+	GenesisMirrors    []string                  // Fallback URLs when GenesisSource is a URL.
+	Tests             []*executor.TestWithSteps // Optional test subset (nil = all).
+	RerunSubset       bool                      // Tests is a re-run subset, so pre-run steps still run.
+	GenesisGroupHash  string                    // Non-empty when running a specific genesis group.
+	GenesisGroups     map[string]string         // All genesis hash → path mappings (multi-genesis).
+	GenesisBlockHash  string                    // Expected genesis block hash, verified after start when non-empty.
+	ImageName         string                    // Resolved image name (pulled once by caller).
+	ImageDigest       string                    // Image SHA256 digest (resolved once by caller).
+	ClientCommit      string                    // Client source commit (resolved once by caller).
+	ContainerSpec     *docker.ContainerSpec     // Saved for container-recreate strategy.
+	DataDirCfg        *config.DataDirConfig     // Resolved datadir config (nil if not using datadir).
+	UseDataDir        bool                      // Whether a pre-populated datadir is used.
+	BlockLogCollector blocklog.Collector        // Optional collector for capturing block logs.
+	TestMarkers       *testMarkerWriter         // Writes test boundary markers into container.log.
+	SharedResults     *sharedRunResults         // Shared by the genesis groups writing into the run directory.
 }
 
 // RunInstance runs a single client instance through its lifecycle.
-func (r *runner) RunInstance(ctx context.Context, instance *config.ClientInstance) error {
+func (r *runner) RunInstance(ctx context.Context, instance *config.ClientInstance) (err error) {
 	// Generate a short random ID for this run.
 	runID := generateShortID()
 	runTimestamp := time.Now().Unix()
 
+	var suiteHash string
+	if r.executor != nil {
+		suiteHash = r.executor.GetSuiteHash()
+	}
+
 	// Create run results directory under runs/.
+	runDirTemplate := r.cfg.ResultsDirTemplate
+	if runDirTemplate == "" {
+		runDirTemplate = config.DefaultResultsDirTemplate
+	}
+
+	runDirName, err := config.RenderRunDirName(runDirTemplate, config.RunDirTemplateData{
+		Timestamp: runTimestamp,
+		RunID:     runID,
+		Instance:  instance.ID,
+		Client:    instance.Client,
+		SuiteHash: suiteHash,
+	})
+	if err != nil {
+		return fmt.Errorf("naming run results directory: %w", err)
+	}
+
 	runResultsDir := filepath.Join(
 		r.cfg.ResultsDir, "runs",
-		fmt.Sprintf("%d_%s_%s", runTimestamp, runID, instance.ID),
+		filepath.FromSlash(config.RunDirPath(r.cfg.ResultsLayout, runTimestamp, runDirName)),
 	)
 	if err := fsutil.MkdirAll(runResultsDir, 0755, r.cfg.ResultsOwner); err != nil {
 		return fmt.Errorf("creating run results directory: %w", err)
 	}
 
-	var suiteHash string
+	r.recordRunDir(runResultsDir)
+
+	// All genesis groups of the run have finished once this returns.
 	if r.executor != nil {
-		suiteHash = r.executor.GetSuiteHash()
+		defer r.executor.FinishRun(runResultsDir)
 	}
 
+	// The post-run command runs last, after the upload, so it may move or
+	// compress the local results.
+	defer func() {
+		hookErr := r.runPostRunCommand(&postRunInfo{
+			RunID:    runID,
+			RunDir:   runResultsDir,
+			Instance: instance,
+			Status:   runStatus(runResultsDir, err),
+		})
+		if err == nil {
+			err = hookErr
+		}
+	}()
+
 	defer r.uploadResults(runResultsDir, suiteHash)
 
 	// Setup benchmarkoor log file for this run.
@@ -383,9 +585,14 @@ func (r *runner) RunInstance(ctx context.Context, instance *config.ClientInstanc
 	}
 	defer func() { _ = benchmarkoorLogFile.Close() }()
 
+	fileFormatter := r.cfg.LogFileFormatter
+	if fileFormatter == nil {
+		fileFormatter = r.logger.Formatter
+	}
+
 	logHook := &fileHook{
 		writer:    benchmarkoorLogFile,
-		formatter: r.logger.Formatter,
+		formatter: fileFormatter,
 	}
 	r.logger.AddHook(logHook)
 	defer r.removeHook(logHook)
@@ -406,23 +613,60 @@ func (r *runner) RunInstance(ctx context.Context, instance *config.ClientInstanc
 	datadirCfg := r.resolveDataDir(instance)
 	useDataDir := datadirCfg != nil
 
-	// Pull image once for this instance (shared across genesis groups).
+	// Pull or build image once for this instance (shared across genesis groups).
 	imageName := instance.Image
 	if imageName == "" {
 		imageName = spec.DefaultImage()
 	}
 
-	if err := r.containerMgr.PullImage(ctx, imageName, instance.PullPolicy); err != nil {
+	if instance.Build != nil {
+		imageName, err = buildInstanceImage(
+			ctx, r.containerMgr, instance, runResultsDir, r.cfg.ResultsOwner,
+		)
+		if err != nil {
+			return fmt.Errorf("building image: %w", err)
+		}
+	} else if err := r.containerMgr.PullImage(ctx, imageName, instance.PullPolicy); err != nil {
 		return fmt.Errorf("pulling image: %w", err)
 	}
 
 	imageDigest, err := r.containerMgr.GetImageDigest(ctx, imageName)
 	if err != nil {
+		if instance.ImageDigest != "" {
+			return fmt.Errorf("getting digest of pinned image: %w", err)
+		}
+
 		log.WithError(err).Warn("Failed to get image digest")
 	} else {
 		log.WithField("digest", imageDigest).Debug("Got image digest")
 	}
 
+	if instance.ImageDigest != "" {
+		repoDigests, err := r.containerMgr.GetImageRepoDigests(ctx, imageName)
+		if err != nil {
+			return fmt.Errorf("getting digests of pinned image: %w", err)
+		}
+
+		// Images without a registry reference are identified by their ID.
+		if len(repoDigests) == 0 {
+			repoDigests = []string{imageDigest}
+		}
+
+		if err := verifyImageDigest(imageName, instance.ImageDigest, repoDigests); err != nil {
+			return err
+		}
+
+		// Record the pinned digest, which need not be the first reference.
+		imageDigest = instance.ImageDigest
+	}
+
+	imageLabels, err := r.containerMgr.GetImageLabels(ctx, imageName)
+	if err != nil {
+		log.WithError(err).Warn("Failed to get image labels")
+	}
+
+	clientCommit := resolveClientCommit(log, instance.ClientCommit, imageLabels)
+
 	// Determine genesis source (URL or local file path).
 	// Priority: instance config > global config > EEST source
 	genesisSource := instance.Genesis
@@ -430,6 +674,11 @@ func (r *runner) RunInstance(ctx context.Context, instance *config.ClientInstanc
 		genesisSource = r.cfg.GenesisURLs[instance.Client]
 	}
 
+	var genesisMirrors []string
+	if genesisSource != "" && r.cfg.FullConfig != nil {
+		genesisMirrors = r.cfg.FullConfig.GetGenesisMirrors(instance)
+	}
+
 	// Check for multi-genesis support (EEST pre_alloc).
 	if genesisSource == "" && r.executor != nil {
 		if ggp, ok := r.executor.GetSource().(executor.GenesisGroupProvider); ok {
@@ -438,59 +687,17 @@ func (r *runner) RunInstance(ctx context.Context, instance *config.ClientInstanc
 					"Running multi-genesis mode",
 				)
 
-				genesisGroups := make(map[string]string, len(groups))
-				for _, group := range groups {
-					genesisGroups[group.GenesisHash] = ggp.GetGenesisPathForGroup(
-						group.GenesisHash, instance.Client,
-					)
-				}
-
-				// Shared test counts accumulator across all genesis groups.
-				accumulatedTestCounts := &TestCounts{}
-
-				for i, group := range groups {
-					groupGenesis := genesisGroups[group.GenesisHash]
-					if groupGenesis == "" {
-						return fmt.Errorf(
-							"no genesis file for group %s and client %s",
-							group.GenesisHash, instance.Client,
-						)
-					}
-
-					log.WithFields(logrus.Fields{
-						"group":        i + 1,
-						"total_groups": len(groups),
-						"genesis_hash": group.GenesisHash,
-						"tests":        len(group.Tests),
-					}).Info("Running genesis group")
-
-					params := &containerRunParams{
-						Instance:             instance,
-						RunID:                runID,
-						RunTimestamp:         runTimestamp,
-						RunResultsDir:        runResultsDir,
-						BenchmarkoorLog:      benchmarkoorLogFile,
-						LogHook:              logHook,
-						GenesisSource:        groupGenesis,
-						Tests:                group.Tests,
-						GenesisGroupHash:     group.GenesisHash,
-						GenesisGroups:        genesisGroups,
-						ImageName:            imageName,
-						ImageDigest:          imageDigest,
-						AccumulatedTestCount: accumulatedTestCounts,
-					}
-
-					if err := r.runContainerLifecycle(
-						ctx, params, spec, datadirCfg, useDataDir,
-					); err != nil {
-						return fmt.Errorf(
-							"running genesis group %s: %w",
-							group.GenesisHash, err,
-						)
-					}
-				}
-
-				return nil
+				return r.runGenesisGroups(ctx, log, ggp, groups, &containerRunParams{
+					Instance:        instance,
+					RunID:           runID,
+					RunTimestamp:    runTimestamp,
+					RunResultsDir:   runResultsDir,
+					BenchmarkoorLog: benchmarkoorLogFile,
+					LogHook:         logHook,
+					ImageName:       imageName,
+					ImageDigest:     imageDigest,
+					ClientCommit:    clientCommit,
+				}, spec, datadirCfg, useDataDir)
 			}
 		}
 	}
@@ -514,8 +721,20 @@ func (r *runner) RunInstance(ctx context.Context, instance *config.ClientInstanc
 		BenchmarkoorLog: benchmarkoorLogFile,
 		LogHook:         logHook,
 		GenesisSource:   genesisSource,
+		GenesisMirrors:  genesisMirrors,
 		ImageName:       imageName,
 		ImageDigest:     imageDigest,
+		ClientCommit:    clientCommit,
+	}
+
+	if r.cfg.RerunTests != nil && r.executor != nil {
+		params.Tests = executor.SelectTests(r.executor.GetTests(), r.cfg.RerunTests)
+		params.RerunSubset = true
+
+		log.WithFields(logrus.Fields{
+			"tests":    len(params.Tests),
+			"previous": len(r.cfg.RerunTests),
+		}).Info("Re-running failed tests of a previous run")
 	}
 
 	return r.runContainerLifecycle(
@@ -533,3 +752,46 @@ func generateShortID() string {
 
 	return hex.EncodeToString(b)
 }
+
+// resolveClientCommit returns the source commit of the client under test.
+// The image's OCI revision label is preferred, as it is set by the build that
+// produced the image; the configured client_commit is used when the label is
+// missing. A mismatch between the two is logged.
+func resolveClientCommit(log logrus.FieldLogger, configured string, labels map[string]string) string {
+	revision := strings.TrimSpace(labels[ociRevisionLabel])
+	if revision == "" {
+		return configured
+	}
+
+	if configured != "" && !strings.EqualFold(configured, revision) &&
+		!strings.HasPrefix(strings.ToLower(revision), strings.ToLower(configured)) {
+		log.WithFields(logrus.Fields{
+			"client_commit":  configured,
+			"image_revision": revision,
+		}).Warn("Configured client_commit differs from the image revision label, using the label")
+	}
+
+	return revision
+}
+
+// verifyImageDigest checks a pulled image against the digest pinned in the
+// instance config, so a moving tag such as :latest cannot silently change
+// the client under test. The image matches if any of its digests, one per
+// repository reference, equals the pinned one. An empty pinned digest
+// disables the check.
+func verifyImageDigest(imageName, pinned string, actual []string) error {
+	if pinned == "" {
+		return nil
+	}
+
+	if !slices.ContainsFunc(actual, func(digest string) bool {
+		return strings.EqualFold(pinned, digest)
+	}) {
+		return fmt.Errorf(
+			"image digest drift for %s: pinned %s, pulled %s",
+			imageName, pinned, strings.Join(actual, ", "),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,961 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/client"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/ethpandaops/benchmarkoor/pkg/download"
+	"github.com/ethpandaops/benchmarkoor/pkg/irq"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyImageDigest(t *testing.T) {
+	const (
+		digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		other  = "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	)
+
+	tests := []struct {
+		name    string
+		pinned  string
+		actual  []string
+		wantErr bool
+	}{
+		{
+			name:   "not pinned",
+			pinned: "",
+			actual: []string{other},
+		},
+		{
+			name:   "match",
+			pinned: digest,
+			actual: []string{digest},
+		},
+		{
+			name:   "match ignores hex case",
+			pinned: "sha256:0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF",
+			actual: []string{digest},
+		},
+		{
+			name:   "match on a later reference",
+			pinned: digest,
+			actual: []string{other, digest},
+		},
+		{
+			name:    "mismatch",
+			pinned:  digest,
+			actual:  []string{other},
+			wantErr: true,
+		},
+		{
+			name:    "unknown digest",
+			pinned:  digest,
+			actual:  nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyImageDigest("ethpandaops/geth:latest", tt.pinned, tt.actual)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "image digest drift")
+				assert.Contains(t, err.Error(), tt.pinned)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDownloadWithFallback(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"config":{"chainId":1}}`))
+		},
+	))
+	defer mirror.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	r := &runner{logger: log, log: log, cfg: &Config{}}
+
+	data, err := r.loadFile(t.Context(), primary.URL, mirror.URL)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"config":{"chainId":1}}`, string(data))
+
+	_, err = r.loadFile(t.Context(), primary.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 500")
+}
+
+func TestDownloadWithFallback_RetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/missing.json" {
+				calls.Add(1)
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"config":{"chainId":1}}`))
+		},
+	))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	r := &runner{
+		logger: log,
+		log:    log,
+		cfg: &Config{DownloadRetry: download.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+		}},
+	}
+
+	data, err := r.loadFile(t.Context(), srv.URL+"/genesis.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"config":{"chainId":1}}`, string(data))
+	assert.Equal(t, int32(2), calls.Load())
+
+	// A 404 is not retried.
+	calls.Store(0)
+
+	_, err = r.loadFile(t.Context(), srv.URL+"/missing.json")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLoadFile_Offline(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			_, _ = w.Write([]byte(`{"config":{"chainId":1}}`))
+		},
+	))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	r := &runner{logger: log, log: log, cfg: &Config{Offline: true}}
+
+	_, err := r.loadFile(t.Context(), srv.URL+"/genesis.json")
+	require.ErrorIs(t, err, download.ErrOffline)
+	assert.Zero(t, calls.Load())
+
+	// Local files are still loaded.
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"config":{"chainId":1}}`), 0644))
+
+	data, err := r.loadFile(t.Context(), path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"config":{"chainId":1}}`, string(data))
+}
+
+func TestBuildCommand(t *testing.T) {
+	geth := client.NewGethSpec()
+
+	t.Run("isolation args follow the default command", func(t *testing.T) {
+		cmd := buildCommand(geth, &config.ClientInstance{ID: "geth", Client: "geth"}, true, true)
+
+		assert.Subset(t, cmd, geth.DefaultCommand())
+		assert.Contains(t, cmd, "--override.genesis=/tmp/genesis.json")
+		assert.Equal(t, geth.IsolationArgs(), cmd[len(cmd)-len(geth.IsolationArgs()):])
+	})
+
+	t.Run("isolation args are added to a custom command", func(t *testing.T) {
+		cmd := buildCommand(geth, &config.ClientInstance{
+			ID:      "geth",
+			Client:  "geth",
+			Command: []string{"--datadir=/data", "--http"},
+		}, false, true)
+
+		want := append([]string{"--datadir=/data", "--http"}, geth.BenchmarkDefaultArgs()...)
+		assert.Equal(t, append(want, geth.IsolationArgs()...), cmd)
+	})
+
+	t.Run("isolation disabled", func(t *testing.T) {
+		cmd := buildCommand(geth, &config.ClientInstance{ID: "geth", Client: "geth"}, false, false)
+
+		assert.Equal(t, append(geth.DefaultCommand(), geth.BenchmarkDefaultArgs()...), cmd)
+		assert.NotContains(t, cmd, "--nodiscover")
+	})
+
+	t.Run("extra args override isolation args", func(t *testing.T) {
+		cmd := buildCommand(geth, &config.ClientInstance{
+			ID:        "geth",
+			Client:    "geth",
+			ExtraArgs: []string{"--maxpeers=25", "--nodiscover=false", "--cache=4096"},
+		}, false, true)
+
+		assert.NotContains(t, cmd, "--maxpeers=0")
+		assert.NotContains(t, cmd, "--nodiscover")
+		assert.Contains(t, cmd, "--port=0")
+		assert.Equal(t, []string{"--maxpeers=25", "--nodiscover=false", "--cache=4096"},
+			cmd[len(cmd)-3:])
+	})
+
+	t.Run("remove default args", func(t *testing.T) {
+		cmd := buildCommand(geth, &config.ClientInstance{
+			ID:                "geth",
+			Client:            "geth",
+			RemoveDefaultArgs: []string{"--syncmode", "--metrics"},
+			ExtraArgs:         []string{"--syncmode=snap"},
+		}, false, false)
+
+		assert.NotContains(t, cmd, "--syncmode=full")
+		assert.NotContains(t, cmd, "--metrics")
+		assert.NotContains(t, cmd, "--metrics.port=8008")
+		assert.Contains(t, cmd, "--http")
+		assert.Contains(t, cmd, "--snapshot=false")
+		assert.Len(t, cmd, len(geth.DefaultCommand())+len(geth.BenchmarkDefaultArgs())-2)
+		assert.Equal(t, "--syncmode=snap", cmd[len(cmd)-1])
+	})
+
+	t.Run("remove default args does not apply to a custom command", func(t *testing.T) {
+		cmd := buildCommand(geth, &config.ClientInstance{
+			ID:                "geth",
+			Client:            "geth",
+			Command:           []string{"--datadir=/data", "--metrics"},
+			RemoveDefaultArgs: []string{"--metrics"},
+		}, false, false)
+
+		assert.Equal(t, append([]string{"--datadir=/data", "--metrics"}, geth.BenchmarkDefaultArgs()...), cmd)
+	})
+
+	t.Run("benchmark default args follow the genesis flag", func(t *testing.T) {
+		reth := client.NewRethSpec()
+		cmd := buildCommand(reth, &config.ClientInstance{ID: "reth", Client: "reth"}, true, false)
+
+		want := append(reth.DefaultCommand(), "--chain=/tmp/genesis.json")
+		assert.Equal(t, append(want, reth.BenchmarkDefaultArgs()...), cmd)
+	})
+
+	t.Run("client without isolation args", func(t *testing.T) {
+		spec := noIsolationSpec{client.NewNimbusSpec()}
+		cmd := buildCommand(spec, &config.ClientInstance{ID: "nimbus", Client: "nimbus"}, false, true)
+
+		assert.Equal(t, spec.DefaultCommand(), cmd)
+	})
+}
+
+// noIsolationSpec is a client spec without isolation args.
+type noIsolationSpec struct {
+	client.Spec
+}
+
+func (noIsolationSpec) IsolationArgs() []string {
+	return nil
+}
+
+func TestResolveNetworkIsolation(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name      string
+		spec      client.Spec
+		setting   *bool
+		want      bool
+		errSubstr string
+	}{
+		{name: "unset isolates supported client", spec: client.NewGethSpec(), want: true},
+		{name: "unset skips unsupported client", spec: noIsolationSpec{client.NewNimbusSpec()}, want: false},
+		{name: "explicitly enabled", spec: client.NewBesuSpec(), setting: &enabled, want: true},
+		{name: "explicitly disabled", spec: client.NewGethSpec(), setting: &disabled, want: false},
+		{name: "disabled on unsupported client", spec: noIsolationSpec{client.NewNimbusSpec()}, setting: &disabled, want: false},
+		{
+			name:      "enabled on unsupported client",
+			spec:      noIsolationSpec{client.NewNimbusSpec()},
+			setting:   &enabled,
+			errSubstr: "isolate_network is not supported by client nimbus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveNetworkIsolation(tt.spec, tt.setting)
+			if tt.errSubstr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMachineFingerprint(t *testing.T) {
+	base := SystemInfo{
+		Hostname:      "bench-1",
+		KernelVersion: "6.8.0-45-generic",
+		CPUModel:      "AMD EPYC 9454P 48-Core Processor",
+		CPUCores:      48,
+		CPUMhz:        2750,
+		MemoryTotalGB: 251.6,
+	}
+
+	fp := machineFingerprint(&base)
+	require.Len(t, fp, 16)
+
+	t.Run("stable for identical hardware", func(t *testing.T) {
+		same := base
+		same.Hostname = "bench-2"
+		same.CPUMhz = 3700
+		same.MemoryTotalGB = 251.7
+
+		assert.Equal(t, fp, machineFingerprint(&base))
+		assert.Equal(t, fp, machineFingerprint(&same))
+	})
+
+	t.Run("differs for different hardware", func(t *testing.T) {
+		for name, mutate := range map[string]func(*SystemInfo){
+			"cpu model": func(s *SystemInfo) { s.CPUModel = "Intel(R) Xeon(R) Gold 6338" },
+			"cpu cores": func(s *SystemInfo) { s.CPUCores = 32 },
+			"memory":    func(s *SystemInfo) { s.MemoryTotalGB = 125.8 },
+			"kernel":    func(s *SystemInfo) { s.KernelVersion = "6.11.0-9-generic" },
+		} {
+			other := base
+			mutate(&other)
+
+			assert.NotEqual(t, fp, machineFingerprint(&other), name)
+		}
+	})
+}
+
+// writeSysfsFile writes content to path under the fake sysfs root.
+func writeSysfsFile(t *testing.T, root, path, content string) {
+	t.Helper()
+
+	full := filepath.Join(root, path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+	require.NoError(t, os.WriteFile(full, []byte(content+"\n"), 0o644))
+}
+
+func TestReadCPUFreqState(t *testing.T) {
+	t.Run("intel powersave with turbo", func(t *testing.T) {
+		root := t.TempDir()
+		writeSysfsFile(t, root, "online", "0-1")
+		writeSysfsFile(t, root, "cpu0/cpufreq/scaling_governor", "powersave")
+		writeSysfsFile(t, root, "cpu1/cpufreq/scaling_governor", "powersave")
+		writeSysfsFile(t, root, "intel_pstate/no_turbo", "0")
+
+		info := &SystemInfo{}
+		readCPUFreqState(info, root)
+
+		assert.Equal(t, "powersave", info.CPUGovernor)
+		require.NotNil(t, info.CPUTurboBoost)
+		assert.True(t, *info.CPUTurboBoost)
+	})
+
+	t.Run("amd mixed governors without boost", func(t *testing.T) {
+		root := t.TempDir()
+		writeSysfsFile(t, root, "online", "0-2")
+		writeSysfsFile(t, root, "cpu0/cpufreq/scaling_governor", "performance")
+		writeSysfsFile(t, root, "cpu1/cpufreq/scaling_governor", "schedutil")
+		writeSysfsFile(t, root, "cpu2/cpufreq/scaling_governor", "performance")
+		writeSysfsFile(t, root, "cpufreq/boost", "0")
+
+		info := &SystemInfo{}
+		readCPUFreqState(info, root)
+
+		assert.Equal(t, "performance,schedutil", info.CPUGovernor)
+		require.NotNil(t, info.CPUTurboBoost)
+		assert.False(t, *info.CPUTurboBoost)
+	})
+
+	t.Run("no cpufreq support", func(t *testing.T) {
+		root := t.TempDir()
+		writeSysfsFile(t, root, "online", "0")
+
+		info := &SystemInfo{}
+		readCPUFreqState(info, root)
+
+		assert.Empty(t, info.CPUGovernor)
+		assert.Nil(t, info.CPUTurboBoost)
+	})
+}
+
+func TestCheckHostSwap(t *testing.T) {
+	const swapGiB = 8 << 30
+
+	limited := &config.ResourceLimits{Memory: "16g"}
+	noSwap := &config.ResourceLimits{Memory: "16g", SwapDisabled: true}
+
+	tests := []struct {
+		name      string
+		swapTotal uint64
+		global    *config.ResourceLimits
+		instances []config.ClientInstance
+		strict    bool
+		wantWarn  bool
+		wantErr   bool
+	}{
+		{
+			name:      "no host swap",
+			swapTotal: 0,
+			global:    limited,
+			instances: []config.ClientInstance{{ID: "geth"}},
+			strict:    true,
+		},
+		{
+			name:      "swap without memory limits",
+			swapTotal: swapGiB,
+			instances: []config.ClientInstance{{ID: "geth"}},
+		},
+		{
+			name:      "swap with swap disabled",
+			swapTotal: swapGiB,
+			global:    noSwap,
+			instances: []config.ClientInstance{{ID: "geth"}},
+			strict:    true,
+		},
+		{
+			name:      "swap with memory limit warns",
+			swapTotal: swapGiB,
+			global:    limited,
+			instances: []config.ClientInstance{{ID: "geth"}},
+			wantWarn:  true,
+		},
+		{
+			name:      "instance override enables swap",
+			swapTotal: swapGiB,
+			global:    noSwap,
+			instances: []config.ClientInstance{
+				{ID: "geth"},
+				{ID: "reth", ResourceLimits: limited},
+			},
+			wantWarn: true,
+		},
+		{
+			name:      "strict mode fails",
+			swapTotal: swapGiB,
+			global:    limited,
+			instances: []config.ClientInstance{{ID: "geth"}},
+			strict:    true,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, hook := logtest.NewNullLogger()
+
+			fullCfg := &config.Config{}
+			fullCfg.Runner.FailOnHostSwap = tt.strict
+			fullCfg.Runner.Client.Config.ResourceLimits = tt.global
+			fullCfg.Runner.Instances = tt.instances
+
+			r := &runner{logger: log, log: log, cfg: &Config{FullConfig: fullCfg}}
+
+			err := r.checkHostSwap(tt.swapTotal)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "swap_disabled")
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var warned bool
+
+			for _, entry := range hook.AllEntries() {
+				if entry.Level == logrus.WarnLevel {
+					warned = true
+				}
+			}
+
+			assert.Equal(t, tt.wantWarn, warned)
+		})
+	}
+}
+
+func TestApplyIRQAffinity(t *testing.T) {
+	const interrupts = `            CPU0       CPU1       CPU2       CPU3
+  24:       1200          0          0          0  IR-PCI-MSI 524288-edge      nvme0q0
+  30:          0          0        300          0  IR-PCI-MSI 1572864-edge      enp3s0-TxRx-0
+  31:          0          7          0          0  IR-PCI-MSI 327680-edge      xhci_hcd
+`
+
+	fakeProc := func(t *testing.T) string {
+		t.Helper()
+
+		root := t.TempDir()
+		writeSysfsFile(t, root, "interrupts", interrupts)
+		writeSysfsFile(t, root, "irq/24/smp_affinity_list", "0-3")
+		writeSysfsFile(t, root, "irq/30/smp_affinity_list", "0-3")
+
+		return root
+	}
+
+	readAffinity := func(t *testing.T, root, irqNum string) string {
+		t.Helper()
+
+		data, err := os.ReadFile(filepath.Join(root, "irq", irqNum, "smp_affinity_list"))
+		require.NoError(t, err)
+
+		return strings.TrimSpace(string(data))
+	}
+
+	t.Run("check warns on overlap", func(t *testing.T) {
+		root := fakeProc(t)
+		log, hook := logtest.NewNullLogger()
+		resolved := &ResolvedResourceLimits{}
+
+		restore := applyIRQAffinity(log, root, t.TempDir(), config.IRQAffinityCheck, []int{0, 1}, resolved)
+		assert.Nil(t, restore)
+		assert.Equal(t, "0,2", resolved.IRQCPUs)
+		assert.Equal(t, config.IRQAffinityCheck, resolved.IRQAffinity)
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, "0-3", readAffinity(t, root, "24"))
+	})
+
+	t.Run("check without overlap", func(t *testing.T) {
+		root := fakeProc(t)
+		log, hook := logtest.NewNullLogger()
+		resolved := &ResolvedResourceLimits{}
+
+		assert.Nil(t, applyIRQAffinity(log, root, t.TempDir(), config.IRQAffinityCheck, []int{1, 3}, resolved))
+		assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+	})
+
+	t.Run("move and restore", func(t *testing.T) {
+		root := fakeProc(t)
+		log, _ := logtest.NewNullLogger()
+		resolved := &ResolvedResourceLimits{}
+
+		stateDir := t.TempDir()
+
+		restore := applyIRQAffinity(log, root, stateDir, config.IRQAffinityMove, []int{0, 1}, resolved)
+		require.NotNil(t, restore)
+		assert.Equal(t, "2,3", readAffinity(t, root, "24"))
+		assert.Equal(t, "2,3", readAffinity(t, root, "30"))
+
+		stateFiles, err := irq.ListOrphanedStateFiles(stateDir)
+		require.NoError(t, err)
+		assert.Len(t, stateFiles, 1)
+
+		restore()
+		assert.Equal(t, "0-3", readAffinity(t, root, "24"))
+		assert.Equal(t, "0-3", readAffinity(t, root, "30"))
+
+		stateFiles, err = irq.ListOrphanedStateFiles(stateDir)
+		require.NoError(t, err)
+		assert.Empty(t, stateFiles)
+	})
+
+	t.Run("missing interrupts file", func(t *testing.T) {
+		log, _ := logtest.NewNullLogger()
+		resolved := &ResolvedResourceLimits{}
+
+		assert.Nil(t, applyIRQAffinity(log, t.TempDir(), t.TempDir(), config.IRQAffinityMove, []int{0}, resolved))
+		assert.Empty(t, resolved.IRQAffinity)
+	})
+}
+
+func TestBuildContainerResourceLimits_CPUBandwidth(t *testing.T) {
+	t.Run("fractional cpus", func(t *testing.T) {
+		limits, resolved, err := buildContainerResourceLimits(&config.ResourceLimits{CPUs: "2.5"})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2_500_000_000), limits.NanoCPUs)
+		assert.Zero(t, limits.CPUQuota)
+		assert.Zero(t, limits.CPUPeriod)
+		assert.Equal(t, "2.5", resolved.CPUs)
+		assert.Equal(t, int64(2_500_000_000), resolved.NanoCPUs)
+	})
+
+	t.Run("quota and period", func(t *testing.T) {
+		limits, resolved, err := buildContainerResourceLimits(&config.ResourceLimits{
+			CPUQuota:  150000,
+			CPUPeriod: 100000,
+		})
+		require.NoError(t, err)
+
+		assert.Zero(t, limits.NanoCPUs)
+		assert.Equal(t, int64(150000), limits.CPUQuota)
+		assert.Equal(t, int64(100000), limits.CPUPeriod)
+		assert.Equal(t, int64(150000), resolved.CPUQuota)
+		assert.Equal(t, uint64(100000), resolved.CPUPeriod)
+	})
+}
+
+func TestBuildContainerResourceLimits_MemoryReservation(t *testing.T) {
+	limits, resolved, err := buildContainerResourceLimits(&config.ResourceLimits{
+		Memory:            "16g",
+		MemoryReservation: "12g",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(16*1024*1024*1024), limits.MemoryBytes)
+	assert.Equal(t, int64(12*1024*1024*1024), limits.MemoryReservation)
+	assert.Equal(t, "12g", resolved.MemoryReservation)
+	assert.Equal(t, int64(12*1024*1024*1024), resolved.MemoryReservationBytes)
+}
+
+func TestBuildContainerResourceLimits_BlkioWeight(t *testing.T) {
+	limits, resolved, err := buildContainerResourceLimits(&config.ResourceLimits{
+		BlkioConfig: &config.BlkioConfig{
+			Weight: 300,
+			DeviceWeight: []config.WeightDevice{
+				{Path: "/dev/nvme0n1", Weight: 800},
+				{Path: "/dev/sdb", Weight: 10},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint16(300), limits.BlkioWeight)
+	assert.Equal(t, []docker.BlkioWeightDevice{
+		{Path: "/dev/nvme0n1", Weight: 800},
+		{Path: "/dev/sdb", Weight: 10},
+	}, limits.BlkioWeightDevice)
+
+	require.NotNil(t, resolved.BlkioConfig)
+	assert.Equal(t, uint16(300), resolved.BlkioConfig.Weight)
+	assert.Equal(t, []ResolvedWeightDevice{
+		{Path: "/dev/nvme0n1", Weight: 800},
+		{Path: "/dev/sdb", Weight: 10},
+	}, resolved.BlkioConfig.DeviceWeight)
+	assert.Empty(t, resolved.BlkioConfig.DeviceReadBps)
+
+	t.Run("weight only", func(t *testing.T) {
+		limits, resolved, err := buildContainerResourceLimits(&config.ResourceLimits{
+			BlkioConfig: &config.BlkioConfig{Weight: 500},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, uint16(500), limits.BlkioWeight)
+		assert.Nil(t, limits.BlkioWeightDevice)
+		require.NotNil(t, resolved.BlkioConfig)
+		assert.Equal(t, uint16(500), resolved.BlkioConfig.Weight)
+	})
+}
+
+func TestBuildContainerDevices(t *testing.T) {
+	assert.Nil(t, buildContainerDevices(nil))
+
+	devices := buildContainerDevices([]config.Device{
+		{HostPath: "/dev/nvme1n1"},
+		{HostPath: "/dev/nvme2n1", ContainerPath: "/dev/bench", Permissions: "rw"},
+	})
+
+	assert.Equal(t, []docker.Device{
+		{HostPath: "/dev/nvme1n1", ContainerPath: "/dev/nvme1n1", Permissions: "rwm"},
+		{HostPath: "/dev/nvme2n1", ContainerPath: "/dev/bench", Permissions: "rw"},
+	}, devices)
+}
+
+func TestResolveBlockDevice(t *testing.T) {
+	const mounts = `sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda1 / ext4 rw,relatime 0 0
+tmpfs /tmp tmpfs rw,nosuid,nodev 0 0
+/dev/nvme0n1p2 /data xfs rw,noatime 0 0
+/dev/nvme1n1 /data/fast\040disk ext4 rw,noatime 0 0
+tank/geth /tank zfs rw,xattr,noacl 0 0
+`
+
+	// Fake sysfs: partitions are nested inside their parent disk's entry
+	// and linked from the block class directory, as on a real host.
+	root := t.TempDir()
+	mountsPath := filepath.Join(root, "mounts")
+	require.NoError(t, os.WriteFile(mountsPath, []byte(mounts), 0o644))
+
+	sysBlock := filepath.Join(root, "sys", "class", "block")
+	require.NoError(t, os.MkdirAll(sysBlock, 0o755))
+
+	for _, dev := range []string{"sda/sda1", "nvme0n1/nvme0n1p2", "nvme1n1"} {
+		entry := filepath.Join(root, "sys", "devices", "pci0000:00", dev)
+		if strings.Contains(dev, "/") {
+			writeSysfsFile(t, entry, "partition", "1")
+		} else {
+			require.NoError(t, os.MkdirAll(entry, 0o755))
+		}
+
+		require.NoError(t, os.Symlink(entry, filepath.Join(sysBlock, filepath.Base(dev))))
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		want    string
+		wantErr string
+	}{
+		{name: "partition resolves to disk", dir: "/data/geth", want: "/dev/nvme0n1"},
+		{name: "mount point itself", dir: "/data", want: "/dev/nvme0n1"},
+		{name: "nested mount with escaped space", dir: "/data/fast disk/reth", want: "/dev/nvme1n1"},
+		{name: "sibling with common prefix", dir: "/database", want: "/dev/sda"},
+		{name: "root mount", dir: "/var/lib/geth", want: "/dev/sda"},
+		{name: "tmpfs", dir: "/tmp/geth", wantErr: "not a block device"},
+		{name: "zfs dataset", dir: "/tank/geth", wantErr: "not a block device"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveBlockDevice(mountsPath, sysBlock, tt.dir)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("device missing from sysfs", func(t *testing.T) {
+		missing := filepath.Join(root, "missing-mounts")
+		require.NoError(t, os.WriteFile(missing, []byte("/dev/sdz1 / ext4 rw 0 0\n"), 0o644))
+
+		_, err := resolveBlockDevice(missing, sysBlock, "/data")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestWithBlkioDevice(t *testing.T) {
+	cfg := &config.ResourceLimits{
+		Memory: "16g",
+		BlkioConfig: &config.BlkioConfig{
+			DeviceReadBps:  []config.ThrottleDevice{{Path: config.BlkioDeviceAuto, Rate: "100mb"}},
+			DeviceWriteBps: []config.ThrottleDevice{{Path: "/dev/sdb", Rate: "50mb"}},
+			Weight:         200,
+			DeviceWeight:   []config.WeightDevice{{Path: config.BlkioDeviceAuto, Weight: 900}},
+		},
+	}
+
+	got := withBlkioDevice(cfg, "/dev/nvme0n1")
+
+	assert.Equal(t, "16g", got.Memory)
+	assert.Equal(t, []config.ThrottleDevice{{Path: "/dev/nvme0n1", Rate: "100mb"}}, got.BlkioConfig.DeviceReadBps)
+	assert.Equal(t, []config.ThrottleDevice{{Path: "/dev/sdb", Rate: "50mb"}}, got.BlkioConfig.DeviceWriteBps)
+	assert.Nil(t, got.BlkioConfig.DeviceReadIOps)
+	assert.Equal(t, uint16(200), got.BlkioConfig.Weight)
+	assert.Equal(t, []config.WeightDevice{{Path: "/dev/nvme0n1", Weight: 900}}, got.BlkioConfig.DeviceWeight)
+
+	// The shared config is left untouched for other instances.
+	assert.Equal(t, config.BlkioDeviceAuto, cfg.BlkioConfig.DeviceReadBps[0].Path)
+}
+
+func TestRunDiskBenchmark(t *testing.T) {
+	dir := t.TempDir()
+	info := &SystemInfo{}
+
+	runDiskBenchmark(context.Background(), discardLogger(), &config.DiskBenchmarkConfig{
+		Enabled:  true,
+		Tool:     "internal",
+		Size:     "2m",
+		Duration: "1s",
+	}, dir, info)
+
+	assert.Equal(t, "internal", info.DiskBenchTool)
+	assert.Equal(t, dir, info.DiskBenchPath)
+	assert.Positive(t, info.DiskSeqWriteMBps)
+	assert.Positive(t, info.DiskRandReadIOPS)
+
+	// A failing probe leaves the disk fields empty.
+	info = &SystemInfo{}
+	runDiskBenchmark(context.Background(), discardLogger(), &config.DiskBenchmarkConfig{
+		Enabled:  true,
+		Tool:     "internal",
+		Size:     "2m",
+		Duration: "1s",
+	}, filepath.Join(dir, "missing"), info)
+
+	assert.Empty(t, info.DiskBenchTool)
+	assert.Zero(t, info.DiskSeqWriteMBps)
+}
+
+func TestResolveClientCommit(t *testing.T) {
+	const revision = "4f2a9c1d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a39"
+
+	tests := []struct {
+		name       string
+		configured string
+		labels     map[string]string
+		want       string
+		wantWarn   bool
+	}{
+		{name: "nothing set", want: ""},
+		{name: "configured only", configured: "abc1234", want: "abc1234"},
+		{
+			name:   "label only",
+			labels: map[string]string{ociRevisionLabel: revision},
+			want:   revision,
+		},
+		{
+			name:       "label preferred over configured",
+			configured: "abc1234",
+			labels:     map[string]string{ociRevisionLabel: revision},
+			want:       revision,
+			wantWarn:   true,
+		},
+		{
+			name:       "abbreviated configured matches label",
+			configured: revision[:8],
+			labels:     map[string]string{ociRevisionLabel: revision},
+			want:       revision,
+		},
+		{
+			name:       "empty label ignored",
+			configured: "abc1234",
+			labels:     map[string]string{ociRevisionLabel: " "},
+			want:       "abc1234",
+		},
+		{
+			name:   "other labels ignored",
+			labels: map[string]string{"org.opencontainers.image.version": "v1.15.0"},
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, hook := logtest.NewNullLogger()
+
+			assert.Equal(t, tt.want, resolveClientCommit(log, tt.configured, tt.labels))
+
+			if tt.wantWarn {
+				require.Len(t, hook.AllEntries(), 1)
+				assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+			} else {
+				assert.Empty(t, hook.AllEntries())
+			}
+		})
+	}
+}
+
+// fakeRestartCounter returns a fixed restart count for any container.
+type fakeRestartCounter struct {
+	count   int
+	err     error
+	checked []string
+}
+
+func (f *fakeRestartCounter) GetContainerRestartCount(_ context.Context, containerID string) (int, error) {
+	f.checked = append(f.checked, containerID)
+
+	return f.count, f.err
+}
+
+func TestCheckContainerRestarts(t *testing.T) {
+	t.Run("not restarted", func(t *testing.T) {
+		log, hook := logtest.NewNullLogger()
+		counter := &fakeRestartCounter{}
+
+		assert.Equal(t, 0, checkContainerRestarts(context.Background(), log, counter, "abc123"))
+		assert.Equal(t, []string{"abc123"}, counter.checked)
+		assert.Empty(t, hook.AllEntries())
+	})
+
+	t.Run("restarted", func(t *testing.T) {
+		log, hook := logtest.NewNullLogger()
+		counter := &fakeRestartCounter{count: 2}
+
+		assert.Equal(t, 2, checkContainerRestarts(context.Background(), log, counter, "abc123"))
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, 2, hook.LastEntry().Data["restart_count"])
+	})
+
+	t.Run("inspect error", func(t *testing.T) {
+		log, hook := logtest.NewNullLogger()
+		counter := &fakeRestartCounter{count: 1, err: io.ErrUnexpectedEOF}
+
+		assert.Equal(t, 0, checkContainerRestarts(context.Background(), log, counter, "abc123"))
+		require.NotNil(t, hook.LastEntry())
+		assert.Contains(t, hook.LastEntry().Message, "Failed to check container restart count")
+	})
+}
+
+func TestExitCodeForStatus(t *testing.T) {
+	tests := []struct {
+		status      string
+		failedTests int
+		want        int
+	}{
+		{status: RunStatusCompleted, want: ExitCodeSuccess},
+		{status: RunStatusCompleted, failedTests: 3, want: ExitCodeTestsFailed},
+		{status: RunStatusContainerDied, want: ExitCodeContainerDied},
+		{status: RunStatusContainerDied, failedTests: 3, want: ExitCodeContainerDied},
+		{status: RunStatusCancelled, want: ExitCodeCancelled},
+		{status: RunStatusFailed, want: ExitCodeFailure},
+		{status: RunStatusTimedOut, want: ExitCodeFailure},
+		{status: "", want: ExitCodeFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.status, tt.failedTests), func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCodeForStatus(tt.status, tt.failedTests))
+		})
+	}
+}
+
+func TestWorseExitCode(t *testing.T) {
+	assert.Equal(t, ExitCodeTestsFailed, WorseExitCode(ExitCodeSuccess, ExitCodeTestsFailed))
+	assert.Equal(t, ExitCodeFailure, WorseExitCode(ExitCodeTestsFailed, ExitCodeFailure))
+	assert.Equal(t, ExitCodeContainerDied, WorseExitCode(ExitCodeContainerDied, ExitCodeFailure))
+	assert.Equal(t, ExitCodeCancelled, WorseExitCode(ExitCodeContainerDied, ExitCodeCancelled))
+	assert.Equal(t, ExitCodeSuccess, WorseExitCode(ExitCodeSuccess, ExitCodeSuccess))
+}
+
+func TestRecordRunOutcome(t *testing.T) {
+	r := &runner{}
+	assert.Equal(t, ExitCodeSuccess, r.ExitCode())
+
+	r.recordRunOutcome(&RunConfig{Status: RunStatusCompleted, TestCounts: &TestCounts{Total: 5, Failed: 1}})
+	assert.Equal(t, ExitCodeTestsFailed, r.ExitCode())
+
+	r.recordRunOutcome(&RunConfig{Status: RunStatusContainerDied})
+	assert.Equal(t, ExitCodeContainerDied, r.ExitCode())
+
+	// A later successful run does not hide an earlier failure.
+	r.recordRunOutcome(&RunConfig{Status: RunStatusCompleted})
+	assert.Equal(t, ExitCodeContainerDied, r.ExitCode())
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+)
+
+// buildSecretMounts returns read-only bind mounts for the instance's secret
+// files. Secrets sourced from a host file are mounted directly; secrets
+// sourced from an environment variable are written into tempDir first. The
+// values never leave the mounts, so they are not recorded in config.json.
+func buildSecretMounts(tempDir string, secrets []config.SecretFile) ([]docker.Mount, error) {
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+
+	mounts := make([]docker.Mount, 0, len(secrets))
+
+	for i, secret := range secrets {
+		source := secret.File
+
+		if source == "" {
+			value, ok := os.LookupEnv(secret.Env)
+			if !ok {
+				return nil, fmt.Errorf(
+					"secret %s: environment variable %s is not set", secret.ContainerPath, secret.Env,
+				)
+			}
+
+			source = filepath.Join(tempDir, "secret-"+strconv.Itoa(i))
+			if err := os.WriteFile(source, []byte(value), 0444); err != nil {
+				return nil, fmt.Errorf("writing secret %s: %w", secret.ContainerPath, err)
+			}
+		}
+
+		mounts = append(mounts, docker.Mount{
+			Type:     "bind",
+			Source:   source,
+			Target:   secret.ContainerPath,
+			ReadOnly: true,
+		})
+	}
+
+	return mounts, nil
+}
+
+// secretContainerPaths returns the container paths of the secret files, the
+// only part of them recorded in config.json.
+func secretContainerPaths(secrets []config.SecretFile) []string {
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	paths := make([]string, len(secrets))
+	for i := range secrets {
+		paths[i] = secrets[i].ContainerPath
+	}
+
+	return paths
+}
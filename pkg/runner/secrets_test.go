@@ -0,0 +1,181 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/client"
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/ethpandaops/benchmarkoor/pkg/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testFileSecret = "file-secret-value-4f1c"
+	testEnvSecret  = "env-secret-value-9b2e"
+)
+
+func TestBuildSecretMounts(t *testing.T) {
+	hostFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(hostFile, []byte(testFileSecret), 0o600))
+	t.Setenv("TEST_SECRET", testEnvSecret)
+
+	tempDir := t.TempDir()
+	mounts, err := buildSecretMounts(tempDir, []config.SecretFile{
+		{ContainerPath: "/secrets/token", File: hostFile},
+		{ContainerPath: "/secrets/api-key", Env: "TEST_SECRET"},
+	})
+	require.NoError(t, err)
+	require.Len(t, mounts, 2)
+
+	assert.Equal(t, docker.Mount{Type: "bind", Source: hostFile, Target: "/secrets/token", ReadOnly: true}, mounts[0])
+	assert.Equal(t, "/secrets/api-key", mounts[1].Target)
+	assert.True(t, mounts[1].ReadOnly)
+	assert.Equal(t, tempDir, filepath.Dir(mounts[1].Source))
+
+	data, err := os.ReadFile(mounts[1].Source)
+	require.NoError(t, err)
+	assert.Equal(t, testEnvSecret, string(data))
+
+	t.Run("unset env", func(t *testing.T) {
+		_, err := buildSecretMounts(t.TempDir(), []config.SecretFile{
+			{ContainerPath: "/secrets/missing", Env: "TEST_SECRET_UNSET"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_SECRET_UNSET is not set")
+	})
+
+	t.Run("none", func(t *testing.T) {
+		mounts, err := buildSecretMounts(t.TempDir(), nil)
+		require.NoError(t, err)
+		assert.Nil(t, mounts)
+	})
+}
+
+// runSecretsLifecycle runs the container lifecycle of an instance up to
+// container creation and returns the container spec and the written
+// config.json.
+func runSecretsLifecycle(t *testing.T, instance *config.ClientInstance, redactEnv []string) (*docker.ContainerSpec, string) {
+	t.Helper()
+
+	resultsDir := t.TempDir()
+	runDir := filepath.Join(resultsDir, "runs", "run1")
+	require.NoError(t, os.MkdirAll(runDir, 0o755))
+
+	genesis := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(genesis, []byte(`{}`), 0o644))
+
+	mgr := &fakeLifecycleManager{events: filepath.Join(t.TempDir(), "events")}
+	r := &runner{
+		log:          discardLogger(),
+		containerMgr: mgr,
+		cfg: &Config{
+			ResultsDir:  resultsDir,
+			TmpCacheDir: t.TempDir(),
+			JWT:         config.DefaultJWT,
+			FullConfig: &config.Config{Runner: config.RunnerConfig{
+				RedactEnv: redactEnv,
+				Instances: []config.ClientInstance{*instance},
+			}},
+		},
+	}
+
+	params := &containerRunParams{
+		Instance:      instance,
+		RunID:         "a1b2c3d4",
+		RunResultsDir: runDir,
+		GenesisSource: genesis,
+		ImageName:     "ghcr.io/paradigmxyz/reth:latest",
+	}
+
+	err := r.runContainerLifecycle(context.Background(), params, client.NewRethSpec(), nil, false)
+	require.ErrorIs(t, err, errContainerCreation)
+	require.NotNil(t, mgr.spec)
+
+	data, err := os.ReadFile(filepath.Join(runDir, "config.json"))
+	require.NoError(t, err)
+
+	return mgr.spec, string(data)
+}
+
+func TestRunContainerLifecycle_SecretFilesNotInRunConfig(t *testing.T) {
+	hostFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(hostFile, []byte(testFileSecret), 0o600))
+	t.Setenv("TEST_SECRET", testEnvSecret)
+
+	spec, runConfig := runSecretsLifecycle(t, &config.ClientInstance{
+		ID:     "reth-1",
+		Client: "reth",
+		SecretFiles: []config.SecretFile{
+			{ContainerPath: "/secrets/token", File: hostFile},
+			{ContainerPath: "/secrets/api-key", Env: "TEST_SECRET"},
+		},
+	}, nil)
+
+	// The secrets are mounted into the container, not passed as env.
+	targets := make(map[string]bool, len(spec.Mounts))
+	for _, m := range spec.Mounts {
+		targets[m.Target] = m.ReadOnly
+	}
+
+	assert.True(t, targets["/secrets/token"], "file secret mounted read-only")
+	assert.True(t, targets["/secrets/api-key"], "env secret mounted read-only")
+
+	for k, v := range spec.Env {
+		assert.NotContains(t, v, testEnvSecret, "env %s", k)
+	}
+
+	// The run config records where secrets are mounted but not their values.
+	assert.NotContains(t, runConfig, testFileSecret)
+	assert.NotContains(t, runConfig, testEnvSecret)
+	assert.Contains(t, runConfig, `"secret_files"`)
+	assert.Contains(t, runConfig, "/secrets/api-key")
+}
+
+func TestRunContainerLifecycle_RedactsEnvironment(t *testing.T) {
+	instance := &config.ClientInstance{
+		ID:     "reth-1",
+		Client: "reth",
+		Environment: map[string]string{
+			"GITHUB_TOKEN":   "ghp-value-1d7a",
+			"db_password":    "hunter2-value",
+			"OTEL_ENDPOINT":  "http://collector:4318",
+			"CUSTOM_API_KEY": "api-key-value-5c0f",
+		},
+	}
+
+	t.Run("default patterns", func(t *testing.T) {
+		spec, runConfig := runSecretsLifecycle(t, instance, nil)
+
+		// The container still receives the real values.
+		assert.Equal(t, "ghp-value-1d7a", spec.Env["GITHUB_TOKEN"])
+		assert.Equal(t, "hunter2-value", spec.Env["db_password"])
+
+		var parsed struct {
+			Instance struct {
+				Environment map[string]string `json:"environment"`
+			} `json:"instance"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(runConfig), &parsed))
+
+		env := parsed.Instance.Environment
+		assert.Equal(t, config.RedactedValue, env["GITHUB_TOKEN"])
+		assert.Equal(t, config.RedactedValue, env["db_password"])
+		assert.Equal(t, "http://collector:4318", env["OTEL_ENDPOINT"])
+		assert.Equal(t, "api-key-value-5c0f", env["CUSTOM_API_KEY"])
+		assert.NotContains(t, runConfig, "ghp-value-1d7a")
+		assert.NotContains(t, runConfig, "hunter2-value")
+	})
+
+	t.Run("configured patterns", func(t *testing.T) {
+		spec, runConfig := runSecretsLifecycle(t, instance, []string{"*_API_KEY"})
+
+		assert.Equal(t, "api-key-value-5c0f", spec.Env["CUSTOM_API_KEY"])
+		assert.NotContains(t, runConfig, "api-key-value-5c0f")
+		assert.Contains(t, runConfig, "ghp-value-1d7a", "configured patterns replace the defaults")
+	})
+}
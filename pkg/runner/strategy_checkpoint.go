@@ -2,6 +2,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -139,7 +140,7 @@ func (r *runner) runTestsWithCheckpointRestore(
 				Image:            params.ContainerSpec.Image,
 				GenesisGroupHash: params.GenesisGroupHash,
 			},
-			params.BlockLogCollector, cleanupStarted,
+			params.BlockLogCollector, params.TestMarkers, cleanupStarted,
 			logDone, logCancel, cleanupFuncs,
 		); logErr != nil {
 			return nil, fmt.Errorf(
@@ -413,7 +414,7 @@ func (r *runner) runTestsWithCheckpointRestore(
 				testLog.WithError(syncErr).Warn("Failed to sync before rollback")
 			}
 
-			if cacheErr := os.WriteFile(dropCachesPath, []byte("3"), 0); cacheErr != nil {
+			if cacheErr := executor.DropCaches(dropCachesPath, r.dropCachesCommand()); cacheErr != nil {
 				testLog.WithError(cacheErr).Warn("Failed to drop page caches before rollback")
 			}
 		}
@@ -477,7 +478,7 @@ func (r *runner) runTestsWithCheckpointRestore(
 				Image:            params.ContainerSpec.Image,
 				GenesisGroupHash: params.GenesisGroupHash,
 			},
-			params.BlockLogCollector, cleanupStarted,
+			params.BlockLogCollector, params.TestMarkers, cleanupStarted,
 			logDone, logCancel, cleanupFuncs,
 		); logErr != nil {
 			combined.TotalDuration = time.Since(startTime)
@@ -495,19 +496,21 @@ func (r *runner) runTestsWithCheckpointRestore(
 			EngineEndpoint: fmt.Sprintf(
 				"http://%s:%d", restoredIP, spec.EnginePort(),
 			),
-			JWT:              r.cfg.JWT,
-			ResultsDir:       resultsDir,
-			Filter:           r.cfg.TestFilter,
-			ContainerID:      restoredID,
-			DockerClient:     r.getDockerClient(),
-			DropMemoryCaches: dropMemoryCaches,
-			DropCachesPath:   dropCachesPath,
-			RollbackStrategy: config.RollbackStrategyNone,
+			JWT:               r.cfg.JWT,
+			ResultsDir:        resultsDir,
+			Filter:            r.cfg.TestFilter,
+			ContainerID:       restoredID,
+			DockerClient:      r.getDockerClient(),
+			DropMemoryCaches:  dropMemoryCaches,
+			DropCachesPath:    dropCachesPath,
+			DropCachesCommand: r.dropCachesCommand(),
+			RollbackStrategy:  config.RollbackStrategyNone,
 			RPCEndpoint: fmt.Sprintf(
 				"http://%s:%d", restoredIP, spec.RPCPort(),
 			),
 			Tests:                         []*executor.TestWithSteps{test},
 			BlockLogCollector:             params.BlockLogCollector,
+			TestMarker:                    params.TestMarkers,
 			RetryNewPayloadsSyncingConfig: r.cfg.FullConfig.GetRetryNewPayloadsSyncingState(params.Instance),
 			PostTestRPCCalls:              r.cfg.FullConfig.GetPostTestRPCCalls(params.Instance),
 			PostTestSleepDuration:         r.cfg.FullConfig.GetPostTestSleepDuration(params.Instance),
@@ -549,6 +552,7 @@ func (r *runner) runTestsWithCheckpointRestore(
 			combined.TotalTests += result.TotalTests
 			combined.Passed += result.Passed
 			combined.Failed += result.Failed
+			combined.Skipped += result.Skipped
 
 			if result.StatsReaderType != "" {
 				combined.StatsReaderType = result.StatsReaderType
@@ -561,6 +565,13 @@ func (r *runner) runTestsWithCheckpointRestore(
 				return combined, nil
 			}
 		}
+
+		// Restoring the next container won't fix the JWT secret.
+		if errors.Is(execErr, executor.ErrJWTAuthFailing) {
+			combined.TotalDuration = time.Since(startTime)
+
+			return combined, fmt.Errorf("executing test %d: %w", i, execErr)
+		}
 	}
 
 	combined.TotalDuration = time.Since(startTime)
@@ -2,6 +2,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -304,8 +305,8 @@ func (r *runner) runTestsWithContainerStrategy(
 						)
 					}
 
-					if cacheErr := os.WriteFile(
-						dropCachesPath, []byte("3"), 0,
+					if cacheErr := executor.DropCaches(
+						dropCachesPath, r.dropCachesCommand(),
 					); cacheErr != nil {
 						testLog.WithError(cacheErr).Warn(
 							"Failed to drop page caches before rollback",
@@ -362,7 +363,7 @@ func (r *runner) runTestsWithContainerStrategy(
 					Image:            newSpec.Image,
 					GenesisGroupHash: params.GenesisGroupHash,
 				},
-				params.BlockLogCollector, cleanupStarted,
+				params.BlockLogCollector, params.TestMarkers, cleanupStarted,
 				logDone, logCancel, cleanupFuncs,
 			); err != nil {
 				combined.TotalDuration = time.Since(startTime)
@@ -599,7 +600,7 @@ func (r *runner) runTestsWithContainerStrategy(
 					Image:            newSpec.Image,
 					GenesisGroupHash: params.GenesisGroupHash,
 				},
-				params.BlockLogCollector, cleanupStarted,
+				params.BlockLogCollector, params.TestMarkers, cleanupStarted,
 				logDone, logCancel, cleanupFuncs,
 			); err != nil {
 				combined.TotalDuration = time.Since(startTime)
@@ -748,19 +749,21 @@ func (r *runner) runTestsWithContainerStrategy(
 			EngineEndpoint: fmt.Sprintf(
 				"http://%s:%d", currentContainerIP, spec.EnginePort(),
 			),
-			JWT:              r.cfg.JWT,
-			ResultsDir:       resultsDir,
-			Filter:           r.cfg.TestFilter,
-			ContainerID:      currentContainerID,
-			DockerClient:     r.getDockerClient(),
-			DropMemoryCaches: dropMemoryCaches,
-			DropCachesPath:   dropCachesPath,
-			RollbackStrategy: config.RollbackStrategyNone,
+			JWT:               r.cfg.JWT,
+			ResultsDir:        resultsDir,
+			Filter:            r.cfg.TestFilter,
+			ContainerID:       currentContainerID,
+			DockerClient:      r.getDockerClient(),
+			DropMemoryCaches:  dropMemoryCaches,
+			DropCachesPath:    dropCachesPath,
+			DropCachesCommand: r.dropCachesCommand(),
+			RollbackStrategy:  config.RollbackStrategyNone,
 			RPCEndpoint: fmt.Sprintf(
 				"http://%s:%d", currentContainerIP, spec.RPCPort(),
 			),
 			Tests:                         []*executor.TestWithSteps{test},
 			BlockLogCollector:             params.BlockLogCollector,
+			TestMarker:                    params.TestMarkers,
 			RetryNewPayloadsSyncingConfig: r.cfg.FullConfig.GetRetryNewPayloadsSyncingState(params.Instance),
 			PostTestRPCCalls:              r.cfg.FullConfig.GetPostTestRPCCalls(params.Instance),
 			PostTestSleepDuration:         r.cfg.FullConfig.GetPostTestSleepDuration(params.Instance),
@@ -770,6 +773,14 @@ func (r *runner) runTestsWithContainerStrategy(
 		if err != nil {
 			testLog.WithError(err).Error("Test execution failed")
 
+			// Recreating the container won't fix the JWT secret.
+			if errors.Is(err, executor.ErrJWTAuthFailing) {
+				waitForLogDrain(logDone, logCancel, logDrainTimeout)
+				combined.TotalDuration = time.Since(startTime)
+
+				return combined, fmt.Errorf("executing test %d: %w", i, err)
+			}
+
 			continue
 		}
 
@@ -777,6 +788,7 @@ func (r *runner) runTestsWithContainerStrategy(
 		combined.TotalTests += result.TotalTests
 		combined.Passed += result.Passed
 		combined.Failed += result.Failed
+		combined.Skipped += result.Skipped
 
 		if result.StatsReaderType != "" {
 			combined.StatsReaderType = result.StatsReaderType
@@ -961,31 +973,39 @@ func (r *runner) runInitForRecreate(
 		fsutil.Chown(initLogFile, r.cfg.ResultsOwner)
 	}
 
-	_, _ = fmt.Fprint(initFile, formatStartMarker("INIT_CONTAINER", &containerLogInfo{
+	initOut := r.groupLogWriter(initFile, params.GenesisGroupHash)
+
+	_, _ = fmt.Fprint(initOut, formatStartMarker("INIT_CONTAINER", &containerLogInfo{
 		Name:             initSpec.Name,
 		Image:            initSpec.Image,
 		GenesisGroupHash: params.GenesisGroupHash,
 	}))
 
-	var initStdout, initStderr io.Writer = initFile, initFile
+	var initStdout, initStderr io.Writer = initOut, initOut
 	if r.cfg.ClientLogsToStdout {
-		pfxFn := clientLogPrefix(instance.ID + "-init")
-		stdoutPW := &prefixedWriter{prefixFn: pfxFn, writer: os.Stdout}
-		logPW := &prefixedWriter{prefixFn: pfxFn, writer: benchmarkoorLog}
-		initStdout = io.MultiWriter(initFile, stdoutPW, logPW)
-		initStderr = io.MultiWriter(initFile, stdoutPW, logPW)
+		initName := instance.ID + "-init"
+		stdoutPW := &prefixedWriter{
+			prefixFn: clientLogPrefix(instance.ID, initName, r.cfg.DisableLogColors),
+			writer:   os.Stdout,
+		}
+		logPW := &prefixedWriter{
+			prefixFn: clientLogPrefix(instance.ID, initName, true),
+			writer:   benchmarkoorLog,
+		}
+		initStdout = io.MultiWriter(initOut, stdoutPW, logPW)
+		initStderr = io.MultiWriter(initOut, stdoutPW, logPW)
 	}
 
 	if err := r.containerMgr.RunInitContainer(
 		ctx, initSpec, initStdout, initStderr,
 	); err != nil {
-		_, _ = fmt.Fprintf(initFile, "#INIT_CONTAINER:END\n")
+		_, _ = fmt.Fprintf(initOut, "#INIT_CONTAINER:END\n")
 		_ = initFile.Close()
 
 		return fmt.Errorf("running init container: %w", err)
 	}
 
-	_, _ = fmt.Fprintf(initFile, "#INIT_CONTAINER:END\n")
+	_, _ = fmt.Fprintf(initOut, "#INIT_CONTAINER:END\n")
 	_ = initFile.Close()
 
 	return nil
@@ -6,14 +6,19 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/client"
 	"github.com/sirupsen/logrus"
 )
 
+// StatsClient is the part of the Docker API client used by the Docker Stats
+// API reader.
+type StatsClient interface {
+	ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error)
+}
+
 // dockerReader implements Reader using Docker Stats API.
 type dockerReader struct {
 	log         logrus.FieldLogger
-	client      *client.Client
+	client      StatsClient
 	containerID string
 }
 
@@ -23,7 +28,7 @@ var _ Reader = (*dockerReader)(nil)
 // newDockerReader creates a new Docker Stats API reader.
 func newDockerReader(
 	log logrus.FieldLogger,
-	dockerClient *client.Client,
+	dockerClient StatsClient,
 	containerID string,
 ) (*dockerReader, error) {
 	if dockerClient == nil {
@@ -1,7 +1,6 @@
 package stats
 
 import (
-	"github.com/docker/docker/client"
 	"github.com/sirupsen/logrus"
 )
 
@@ -40,7 +39,7 @@ type Reader interface {
 // Priority: 1) Cgroup v2 (low overhead), 2) Docker Stats API (fallback)
 func NewReader(
 	log logrus.FieldLogger,
-	dockerClient *client.Client,
+	dockerClient StatsClient,
 	containerID string,
 ) (Reader, error) {
 	// Try cgroup v2 first (Linux with native Docker).
@@ -0,0 +1,164 @@
+package thp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// stateFilePrefix is the prefix for THP state files.
+	stateFilePrefix = "benchmarkoor-thp-"
+	// stateFileSuffix is the suffix for THP state files.
+	stateFileSuffix = ".json"
+)
+
+// StateFile represents a THP state file for orphan detection.
+type StateFile struct {
+	Path      string
+	Timestamp time.Time
+}
+
+// SaveState saves the original THP mode to a state file.
+func SaveState(cacheDir string, settings *OriginalSettings) (string, error) {
+	if cacheDir == "" {
+		cacheDir = os.TempDir()
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s%d%s", stateFilePrefix, time.Now().Unix(), stateFileSuffix)
+	statePath := filepath.Join(cacheDir, filename)
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling settings: %w", err)
+	}
+
+	if err := os.WriteFile(statePath, data, 0644); err != nil {
+		return "", fmt.Errorf("writing state file: %w", err)
+	}
+
+	return statePath, nil
+}
+
+// LoadState loads the original THP mode from a state file.
+func LoadState(statePath string) (*OriginalSettings, error) {
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	var settings OriginalSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+
+	if settings.Mode == "" {
+		return nil, fmt.Errorf("state file has no mode")
+	}
+
+	return &settings, nil
+}
+
+// RemoveStateFile removes a state file.
+func RemoveStateFile(statePath string) error {
+	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing state file: %w", err)
+	}
+
+	return nil
+}
+
+// ListOrphanedStateFiles finds state files left behind by interrupted runs.
+func ListOrphanedStateFiles(cacheDir string) ([]StateFile, error) {
+	if cacheDir == "" {
+		cacheDir = os.TempDir()
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	var stateFiles []StateFile
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasPrefix(name, stateFilePrefix) || !strings.HasSuffix(name, stateFileSuffix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		stateFiles = append(stateFiles, StateFile{
+			Path:      filepath.Join(cacheDir, name),
+			Timestamp: info.ModTime(),
+		})
+	}
+
+	return stateFiles, nil
+}
+
+// RestoreFromStateFile restores the THP mode from a state file and removes it.
+// sysfsBasePath is the base path for THP sysfs files (e.g. "/sys/kernel/mm/transparent_hugepage").
+func RestoreFromStateFile(
+	_ context.Context,
+	log logrus.FieldLogger,
+	statePath, sysfsBasePath string,
+) error {
+	settings, err := LoadState(statePath)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+
+	if err := SetMode(sysfsBasePath, settings.Mode); err != nil {
+		return fmt.Errorf("restoring THP mode: %w", err)
+	}
+
+	if err := RemoveStateFile(statePath); err != nil {
+		log.WithError(err).Warn("Failed to remove state file")
+	}
+
+	log.WithFields(logrus.Fields{
+		"state_file": statePath,
+		"mode":       settings.Mode,
+	}).Info("Restored THP mode from state file")
+
+	return nil
+}
+
+// CleanupOrphanedTHPState restores the THP mode from all orphaned state files.
+// sysfsBasePath is the base path for THP sysfs files (e.g. "/sys/kernel/mm/transparent_hugepage").
+func CleanupOrphanedTHPState(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	stateFiles []StateFile,
+	sysfsBasePath string,
+) error {
+	for _, sf := range stateFiles {
+		if err := RestoreFromStateFile(ctx, log, sf.Path, sysfsBasePath); err != nil {
+			log.WithError(err).WithField("state_file", sf.Path).Warn("Failed to restore from state file")
+
+			continue
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package thp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultSysfsPath is the default sysfs path for transparent huge pages.
+	DefaultSysfsPath = "/sys/kernel/mm/transparent_hugepage"
+
+	enabledFile = "enabled"
+)
+
+// Supported THP modes.
+const (
+	ModeAlways  = "always"
+	ModeMadvise = "madvise"
+	ModeNever   = "never"
+)
+
+// ValidModes lists the THP modes that can be configured.
+var ValidModes = []string{ModeAlways, ModeMadvise, ModeNever}
+
+// enabledPath returns the path to the THP enabled file.
+func enabledPath(basePath string) string {
+	return filepath.Join(basePath, enabledFile)
+}
+
+// parseModes parses the content of the THP enabled file, e.g.
+// "always [madvise] never", into the active mode and all available modes.
+func parseModes(content string) (string, []string, error) {
+	var (
+		active string
+		modes  []string
+	)
+
+	for _, field := range strings.Fields(content) {
+		if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+			field = strings.Trim(field, "[]")
+			active = field
+		}
+
+		modes = append(modes, field)
+	}
+
+	if active == "" {
+		return "", nil, fmt.Errorf("no active mode in %q", strings.TrimSpace(content))
+	}
+
+	return active, modes, nil
+}
+
+// readModes reads the active and available THP modes.
+func readModes(basePath string) (string, []string, error) {
+	path := enabledPath(basePath)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	active, modes, err := parseModes(string(data))
+	if err != nil {
+		return "", nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return active, modes, nil
+}
+
+// GetMode returns the active THP mode (e.g. "madvise").
+func GetMode(basePath string) (string, error) {
+	active, _, err := readModes(basePath)
+
+	return active, err
+}
+
+// SetMode sets the active THP mode.
+func SetMode(basePath, mode string) error {
+	path := enabledPath(basePath)
+	if err := os.WriteFile(path, []byte(mode), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// IsTHPSupported checks if THP control is available on this system.
+func IsTHPSupported(basePath string) bool {
+	_, err := GetMode(basePath)
+
+	return err == nil
+}
+
+// HasWriteAccess checks if we have write access to the THP enabled file.
+func HasWriteAccess(basePath string) error {
+	path := enabledPath(basePath)
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		if os.IsPermission(err) {
+			return fmt.Errorf("no write permission to %s (requires root)", path)
+		}
+
+		return fmt.Errorf("accessing %s: %w", path, err)
+	}
+
+	_ = file.Close()
+
+	return nil
+}
+
+// ValidateMode checks if the specified mode is available on the system.
+func ValidateMode(basePath, mode string) error {
+	_, modes, err := readModes(basePath)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(modes, mode) {
+		return fmt.Errorf(
+			"transparent_hugepage mode %q not available (available: %s)",
+			mode, strings.Join(modes, ", "),
+		)
+	}
+
+	return nil
+}
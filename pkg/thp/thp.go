@@ -0,0 +1,142 @@
+// Package thp reads and controls the host's transparent huge pages (THP)
+// mode for the duration of a benchmark run.
+package thp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Manager controls the host THP mode.
+type Manager interface {
+	Start(ctx context.Context) error
+	Stop() error
+	// Apply sets the THP mode, saving the original mode on first use.
+	Apply(ctx context.Context, mode string) error
+	// Restore restores the original THP mode.
+	Restore(ctx context.Context) error
+}
+
+// OriginalSettings stores the original THP mode before modification.
+type OriginalSettings struct {
+	Mode string `json:"mode"`
+}
+
+// NewManager creates a new THP manager.
+// sysfsBasePath is the base path for THP sysfs files (e.g. "/sys/kernel/mm/transparent_hugepage").
+func NewManager(log logrus.FieldLogger, cacheDir, sysfsBasePath string) Manager {
+	return &manager{
+		log:           log.WithField("component", "thp"),
+		cacheDir:      cacheDir,
+		sysfsBasePath: sysfsBasePath,
+	}
+}
+
+type manager struct {
+	log           logrus.FieldLogger
+	cacheDir      string
+	sysfsBasePath string
+
+	mu               sync.Mutex
+	originalSettings *OriginalSettings
+	stateFile        string
+}
+
+// Ensure interface compliance.
+var _ Manager = (*manager)(nil)
+
+// Start initializes the manager.
+func (m *manager) Start(_ context.Context) error {
+	m.log.Debug("THP manager started")
+
+	return nil
+}
+
+// Stop restores the original mode if it was changed.
+func (m *manager) Stop() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.restoreSettings(); err != nil {
+		m.log.WithError(err).Warn("Failed to restore THP mode")
+	}
+
+	m.log.Debug("THP manager stopped")
+
+	return nil
+}
+
+// Apply sets the THP mode.
+func (m *manager) Apply(_ context.Context, mode string) error {
+	if mode == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Save the original mode before making changes.
+	if m.originalSettings == nil {
+		current, err := GetMode(m.sysfsBasePath)
+		if err != nil {
+			return fmt.Errorf("capturing original THP mode: %w", err)
+		}
+
+		m.originalSettings = &OriginalSettings{Mode: current}
+
+		// Persist state file for crash recovery.
+		stateFile, err := SaveState(m.cacheDir, m.originalSettings)
+		if err != nil {
+			m.log.WithError(err).Warn("Failed to save THP state file")
+		} else {
+			m.stateFile = stateFile
+			m.log.WithField("state_file", stateFile).Debug("Saved THP state")
+		}
+	}
+
+	if err := SetMode(m.sysfsBasePath, mode); err != nil {
+		return fmt.Errorf("setting THP mode: %w", err)
+	}
+
+	m.log.WithFields(logrus.Fields{
+		"mode":     mode,
+		"original": m.originalSettings.Mode,
+	}).Info("Applied THP mode")
+
+	return nil
+}
+
+// Restore restores the original THP mode.
+func (m *manager) Restore(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.restoreSettings()
+}
+
+// restoreSettings restores the original mode (must be called with lock held).
+func (m *manager) restoreSettings() error {
+	if m.originalSettings == nil {
+		return nil
+	}
+
+	if err := SetMode(m.sysfsBasePath, m.originalSettings.Mode); err != nil {
+		return fmt.Errorf("restoring THP mode %q: %w", m.originalSettings.Mode, err)
+	}
+
+	if m.stateFile != "" {
+		if err := RemoveStateFile(m.stateFile); err != nil {
+			m.log.WithError(err).Warn("Failed to remove state file")
+		}
+
+		m.stateFile = ""
+	}
+
+	m.log.WithField("mode", m.originalSettings.Mode).Info("THP mode restored")
+	m.originalSettings = nil
+
+	return nil
+}
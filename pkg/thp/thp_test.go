@@ -0,0 +1,170 @@
+package thp
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSysfs creates a fake THP sysfs directory. Unlike the kernel, a write
+// replaces the file content with the bare mode, which readRaw returns.
+func fakeSysfs(t *testing.T, content string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, enabledFile), []byte(content), 0644))
+
+	return dir
+}
+
+// readRaw returns the raw content of the fake enabled file.
+func readRaw(t *testing.T, dir string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, enabledFile))
+	require.NoError(t, err)
+
+	return strings.TrimSpace(string(data))
+}
+
+func discardLogger() logrus.FieldLogger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return log
+}
+
+func TestParseModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantActive string
+		wantModes  []string
+		wantErr    bool
+	}{
+		{
+			name:       "madvise",
+			content:    "always [madvise] never\n",
+			wantActive: "madvise",
+			wantModes:  []string{"always", "madvise", "never"},
+		},
+		{
+			name:       "always",
+			content:    "[always] madvise never",
+			wantActive: "always",
+			wantModes:  []string{"always", "madvise", "never"},
+		},
+		{
+			name:    "no active mode",
+			content: "always madvise never",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			content: "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, modes, err := parseModes(tt.content)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive, active)
+			assert.Equal(t, tt.wantModes, modes)
+		})
+	}
+}
+
+func TestGetModeAndValidate(t *testing.T) {
+	dir := fakeSysfs(t, "always madvise [never]\n")
+
+	mode, err := GetMode(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ModeNever, mode)
+	assert.True(t, IsTHPSupported(dir))
+
+	require.NoError(t, ValidateMode(dir, ModeMadvise))
+
+	err = ValidateMode(dir, "defer")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not available")
+
+	assert.False(t, IsTHPSupported(t.TempDir()))
+}
+
+func TestManager_ApplyAndRestore(t *testing.T) {
+	dir := fakeSysfs(t, "always [madvise] never\n")
+	cacheDir := t.TempDir()
+
+	mgr := NewManager(discardLogger(), cacheDir, dir)
+	require.NoError(t, mgr.Start(t.Context()))
+
+	require.NoError(t, mgr.Apply(t.Context(), ModeNever))
+	assert.Equal(t, ModeNever, readRaw(t, dir))
+
+	stateFiles, err := ListOrphanedStateFiles(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, stateFiles, 1)
+
+	// Emulate the kernel rendering the new mode, then apply again: the
+	// original mode must not be overwritten.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, enabledFile), []byte("[always] madvise never"), 0644))
+	require.NoError(t, mgr.Apply(t.Context(), ModeAlways))
+
+	require.NoError(t, mgr.Restore(t.Context()))
+	assert.Equal(t, ModeMadvise, readRaw(t, dir))
+
+	stateFiles, err = ListOrphanedStateFiles(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, stateFiles)
+
+	// Restore and Stop without changes are no-ops.
+	require.NoError(t, mgr.Restore(t.Context()))
+	require.NoError(t, mgr.Stop())
+	assert.Equal(t, ModeMadvise, readRaw(t, dir))
+}
+
+func TestManager_StopRestores(t *testing.T) {
+	dir := fakeSysfs(t, "[always] madvise never\n")
+
+	mgr := NewManager(discardLogger(), t.TempDir(), dir)
+	require.NoError(t, mgr.Apply(t.Context(), ModeNever))
+	require.NoError(t, mgr.Stop())
+
+	assert.Equal(t, ModeAlways, readRaw(t, dir))
+}
+
+func TestCleanupOrphanedTHPState(t *testing.T) {
+	dir := fakeSysfs(t, "always madvise [never]\n")
+	cacheDir := t.TempDir()
+
+	_, err := SaveState(cacheDir, &OriginalSettings{Mode: ModeMadvise})
+	require.NoError(t, err)
+
+	// Unrelated files are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "benchmarkoor-cpufreq-1.json"), []byte("{}"), 0644))
+
+	stateFiles, err := ListOrphanedStateFiles(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, stateFiles, 1)
+
+	require.NoError(t, CleanupOrphanedTHPState(t.Context(), discardLogger(), stateFiles, dir))
+	assert.Equal(t, ModeMadvise, readRaw(t, dir))
+
+	stateFiles, err = ListOrphanedStateFiles(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, stateFiles)
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// ShutdownFunc flushes pending spans and releases the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// NewTracerProvider returns a tracer provider that exports spans to the
+// configured OTLP/HTTP endpoint. With a nil cfg it returns a no-op provider,
+// so instrumented code does not need to check whether tracing is enabled.
+func NewTracerProvider(
+	ctx context.Context, cfg *config.TracingConfig, version string,
+) (trace.TracerProvider, ShutdownFunc, error) {
+	if cfg == nil {
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, exporterOptions(cfg)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.GetServiceName()),
+			attribute.String("service.version", version),
+		)),
+	)
+
+	return provider, provider.Shutdown, nil
+}
+
+// exporterOptions converts the tracing config to OTLP/HTTP exporter options.
+func exporterOptions(cfg *config.TracingConfig) []otlptracehttp.Option {
+	var opts []otlptracehttp.Option
+
+	if strings.Contains(cfg.Endpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+
+	return opts
+}
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/benchmarkoor/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewTracerProvider_Disabled(t *testing.T) {
+	provider, shutdown, err := NewTracerProvider(t.Context(), nil, "dev")
+	require.NoError(t, err)
+
+	_, span := provider.Tracer("test").Start(t.Context(), "span")
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	require.NoError(t, shutdown(t.Context()))
+}
+
+func TestNewTracerProvider_OTLP(t *testing.T) {
+	// The exporter connects lazily, so no collector is needed.
+	provider, shutdown, err := NewTracerProvider(t.Context(), &config.TracingConfig{
+		Endpoint: "localhost:4318",
+		Insecure: true,
+	}, "dev")
+	require.NoError(t, err)
+
+	assert.IsType(t, &sdktrace.TracerProvider{}, provider)
+
+	_, span := provider.Tracer("test").Start(t.Context(), "span")
+	assert.True(t, span.IsRecording())
+
+	require.NoError(t, shutdown(t.Context()))
+}
+
+func TestExporterOptions(t *testing.T) {
+	assert.Len(t, exporterOptions(&config.TracingConfig{Endpoint: "localhost:4318"}), 1)
+	assert.Len(t, exporterOptions(&config.TracingConfig{Endpoint: "localhost:4318", Insecure: true}), 2)
+	assert.Len(t, exporterOptions(&config.TracingConfig{
+		Endpoint: "https://otlp.example.com/v1/traces",
+		Insecure: true,
+		Headers:  map[string]string{"Authorization": "Bearer token"},
+	}), 2)
+}
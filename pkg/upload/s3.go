@@ -96,8 +96,7 @@ type uploadJob struct {
 
 // Upload walks localDir and uploads all files to S3 under the configured prefix.
 func (u *s3Uploader) Upload(ctx context.Context, localDir string) error {
-	baseName := filepath.Base(localDir)
-	prefix := u.resolvePrefix(baseName)
+	prefix := u.resolvePrefix(config.RunDirRelPath(localDir))
 
 	jobs, err := u.collectJobs(localDir, prefix)
 	if err != nil {
@@ -267,14 +266,15 @@ func (u *s3Uploader) uploadFile(ctx context.Context, localPath, key string) erro
 
 // resolvePrefix builds the S3 key prefix for a run directory.
 // The configured prefix is the base (default "results"), and runs are stored
-// under prefix + "/runs/" + baseName.
-func (u *s3Uploader) resolvePrefix(baseName string) string {
+// under prefix + "/runs/" + runPath, where runPath is the run directory path
+// relative to the local runs/ directory (including any date partitions).
+func (u *s3Uploader) resolvePrefix(runPath string) string {
 	prefix := u.cfg.Prefix
 	if prefix == "" {
 		prefix = "results"
 	}
 
-	return strings.TrimRight(prefix, "/") + "/runs/" + baseName
+	return strings.TrimRight(prefix, "/") + "/runs/" + runPath
 }
 
 // UploadSuiteDir uploads all files in a suite directory to S3 under
@@ -32,6 +32,12 @@ func TestResolvePrefix(t *testing.T) {
 			baseName: "run123",
 			want:     "my-prefix/runs/run123",
 		},
+		{
+			name:     "date partitioned",
+			prefix:   "archive",
+			baseName: "2024/06/10/1718000000_a1b2c3d4_geth",
+			want:     "archive/runs/2024/06/10/1718000000_a1b2c3d4_geth",
+		},
 	}
 
 	for _, tt := range tests {
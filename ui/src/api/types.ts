@@ -131,17 +131,20 @@ export interface RunConfig {
   suite_hash?: string
   system_resource_collection_method?: string // "cgroupv2" or "dockerstats"
   system: SystemInfo
+  machine_fingerprint?: string
   instance: InstanceConfig
   start_block?: StartBlock
   test_counts?: {
     total: number
     passed: number
     failed: number
+    skipped?: number
   }
   status?: RunStatus
   termination_reason?: string
   container_exit_code?: number
   container_oom_killed?: boolean
+  container_restart_count?: number
   metadata?: {
     labels?: Record<string, string>
   }
@@ -161,7 +164,16 @@ export interface SystemInfo {
   cpu_cores: number
   cpu_mhz: number
   cpu_cache_kb: number
+  cpu_freq_governor?: string
+  cpu_turboboost?: boolean
   memory_total_gb: number
+  swap_total_gb?: number
+  transparent_hugepage?: string
+  disk_bench_tool?: string
+  disk_bench_path?: string
+  disk_seq_write_mbps?: number
+  disk_rand_read_iops?: number
+  disk_rand_read_latency_us?: number
 }
 
 export interface DataDirConfig {
@@ -175,22 +187,44 @@ export interface ThrottleDeviceConfig {
   rate: number
 }
 
+export interface WeightDeviceConfig {
+  path: string
+  weight: number
+}
+
 export interface BlkioConfig {
   device_read_bps?: ThrottleDeviceConfig[]
   device_read_iops?: ThrottleDeviceConfig[]
   device_write_bps?: ThrottleDeviceConfig[]
   device_write_iops?: ThrottleDeviceConfig[]
+  weight?: number
+  device_weight?: WeightDeviceConfig[]
 }
 
 export interface ResourceLimitsConfig {
   cpuset_cpus?: string
+  cpus?: string
+  nano_cpus?: number
+  cpu_quota?: number
+  cpu_period?: number
   memory?: string
   memory_bytes?: number
+  memory_reservation?: string
+  memory_reservation_bytes?: number
   swap_disabled?: boolean
   blkio_config?: BlkioConfig
   cpu_freq_khz?: number
   cpu_turboboost?: boolean
   cpu_freq_governor?: string
+  transparent_hugepage?: string
+  irq_affinity?: string
+  irq_cpus?: string
+}
+
+export interface DeviceConfig {
+  host_path: string
+  container_path?: string
+  permissions?: string
 }
 
 export interface RetryNewPayloadsSyncingConfig {
@@ -217,15 +251,24 @@ export interface CheckpointRestoreStrategyOptions {
   restart_container?: boolean
 }
 
+export interface BuildConfig {
+  context: string
+  dockerfile?: string
+  build_args?: Record<string, string>
+}
+
 export interface InstanceConfig {
   id: string
   client: string
   container_runtime?: string
   image: string
   image_sha256?: string
+  client_commit?: string
+  build?: BuildConfig
   entrypoint?: string[]
   command?: string[]
   extra_args?: string[]
+  remove_default_args?: string[]
   pull_policy: string
   restart?: string
   environment?: Record<string, string>
@@ -235,10 +278,14 @@ export interface InstanceConfig {
   client_version?: string
   rollback_strategy?: string
   drop_memory_caches?: string
+  prime_page_cache?: boolean
+  sync_between_tests?: boolean
   wait_after_rpc_ready?: string
   run_timeout?: string
   retry_new_payloads_syncing_state?: RetryNewPayloadsSyncingConfig
   resource_limits?: ResourceLimitsConfig
+  devices?: DeviceConfig[]
+  secret_files?: string[]
   post_test_rpc_calls?: PostTestRPCCallConfig[]
   post_test_sleep_duration?: string
   checkpoint_restore_strategy_options?: CheckpointRestoreStrategyOptions
@@ -248,6 +295,18 @@ export interface InstanceConfig {
 export interface RunResult {
   pre_run_steps?: Record<string, StepResult>
   tests: Record<string, TestEntry>
+  skipped?: number // tests skipped instead of passing or failing
+  resource_usage?: RunResourceUsage
+}
+
+// Resource usage of the whole run, summed over all steps
+export interface RunResourceUsage {
+  cpu_seconds: number
+  peak_memory_bytes: number
+  disk_read_bytes: number
+  disk_write_bytes: number
+  disk_read_iops: number
+  disk_write_iops: number
 }
 
 export interface StepResult {
@@ -270,6 +329,7 @@ export interface ResourceTotals {
   cpu_usec: number
   memory_delta_bytes: number
   memory_bytes?: number
+  memory_peak_bytes?: number
   disk_read_bytes: number
   disk_write_bytes: number
   disk_read_iops: number
@@ -328,10 +388,11 @@ export interface ResourceDelta {
 // .result-details.json per test
 export interface ResultDetails {
   duration_ns: number[]
-  status: number[] // 0=success, 1=fail
+  status: number[] // 0=success, 1=fail, 2=unexpected result, 3=malformed response
   mgas_s: Record<string, number> // map of index -> MGas/s value
   gas_used: Record<string, number> // map of index -> gas used value
   resources?: Record<string, ResourceDelta> // map of index -> resource delta
+  http_status?: Record<string, number> // map of index -> HTTP status code of the response
   original_test_name?: string // original test name when using hashed filenames
   filename_hash?: string // truncated+hash filename when original was too long
 }
@@ -376,6 +437,7 @@ export interface SuiteInfo {
   }
   pre_run_steps?: SuiteFile[]
   tests: SuiteTest[]
+  test_order?: string
 }
 
 export interface SuiteTestEEST {
@@ -436,6 +498,7 @@ export interface SourceInfo {
     fixtures_url?: string
     genesis_url?: string
     fixtures_subdir?: string
+    fixture_format_versions?: string[]
     fixtures_artifact_name?: string
     genesis_artifact_name?: string
     fixtures_artifact_run_id?: string
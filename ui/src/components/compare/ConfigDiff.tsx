@@ -106,6 +106,12 @@ export function ConfigDiff({ runs, labelMode }: ConfigDiffProps) {
               {instances.some((i) => i.extra_args) && (
                 <DiffRow label="Extra Args" values={instances.map((i) => i.extra_args?.join(' ') ?? '')} />
               )}
+              {instances.some((i) => i.remove_default_args) && (
+                <DiffRow
+                  label="Removed Default Args"
+                  values={instances.map((i) => i.remove_default_args?.join(' ') ?? '')}
+                />
+              )}
               {instances.some((i) => i.rollback_strategy) && (
                 <DiffRow label="Rollback Strategy" values={instances.map((i) => i.rollback_strategy ?? 'none')} />
               )}
@@ -33,7 +33,7 @@ function computeMetrics(config: RunConfig, result: RunResult | null, stepFilter:
 
   const testCount = config.test_counts?.total ?? (result ? Object.keys(result.tests).length : 0)
   const passedTests = config.test_counts?.passed ?? aggregatedStats.filter((s) => s.fail === 0).length
-  const failedTests = config.test_counts ? (config.test_counts.total - config.test_counts.passed) : aggregatedStats.filter((s) => s.fail > 0).length
+  const failedTests = config.test_counts ? (config.test_counts.total - config.test_counts.passed - (config.test_counts.skipped ?? 0)) : aggregatedStats.filter((s) => s.fail > 0).length
   const totalDuration = aggregatedStats.reduce((sum, s) => sum + s.time_total, 0)
   const totalGasUsed = aggregatedStats.reduce((sum, s) => sum + s.gas_used_total, 0)
   const totalGasUsedTime = aggregatedStats.reduce((sum, s) => sum + s.gas_used_time_total, 0)
@@ -66,6 +66,7 @@ interface ExecutionsListProps {
   runId: string
   suiteHash: string
   testName: string
+  testDir?: string // directory of the test's files, if not its name
   stepType: StepType
 }
 
@@ -112,7 +113,7 @@ interface ExecutionRowProps {
   response?: string
   responseSize?: number
   time?: number
-  status?: number // 0=success, 1=fail
+  status?: number // 0=success, 1=fail, 2=unexpected result, 3=malformed response
   mgasPerSec?: number
   gasUsed?: number
   /** File viewer link for the response file at this line. */
@@ -125,6 +126,8 @@ function StatusIndicator({ status }: { status?: number }) {
   if (status === undefined) return null
 
   const isSuccess = status === 0
+  const isUnexpected = status === 2
+  const isMalformed = status === 3
 
   return (
     <span
@@ -132,10 +135,12 @@ function StatusIndicator({ status }: { status?: number }) {
         'shrink-0 rounded-full px-2 py-0.5 text-xs/5 font-medium',
         isSuccess
           ? 'bg-green-100 text-green-700 dark:bg-green-900/30 dark:text-green-400'
-          : 'bg-red-100 text-red-700 dark:bg-red-900/30 dark:text-red-400',
+          : isUnexpected
+            ? 'bg-orange-100 text-orange-700 dark:bg-orange-900/30 dark:text-orange-400'
+            : 'bg-red-100 text-red-700 dark:bg-red-900/30 dark:text-red-400',
       )}
     >
-      {isSuccess ? 'OK' : 'FAIL'}
+      {isSuccess ? 'OK' : isUnexpected ? 'UNEXPECTED' : isMalformed ? 'MALFORMED' : 'FAIL'}
     </span>
   )
 }
@@ -306,16 +311,16 @@ function ExecutionRow({ index, request, requestSize, methodName, requestLineInfo
 
 const EXECUTIONS_PAGE_SIZE = 100
 
-export function ExecutionsList({ runId, suiteHash, testName, stepType }: ExecutionsListProps) {
-  const { data: requests, isLoading: requestsLoading, error: requestsError } = useTestRequests(suiteHash, testName, stepType)
-  const { data: responses, error: responsesError } = useTestResponses(runId, testName, stepType)
-  const { data: resultDetails, isLoading: detailsLoading, error: detailsError } = useTestResultDetails(runId, testName, stepType)
-  const { data: requestSummaries } = useTestRequestSummaries(suiteHash, testName, stepType)
-  const { data: responseSummaries } = useTestResponseSummaries(runId, testName, stepType)
+export function ExecutionsList({ runId, suiteHash, testName, testDir = testName, stepType }: ExecutionsListProps) {
+  const { data: requests, isLoading: requestsLoading, error: requestsError } = useTestRequests(suiteHash, testDir, stepType)
+  const { data: responses, error: responsesError } = useTestResponses(runId, testDir, stepType)
+  const { data: resultDetails, isLoading: detailsLoading, error: detailsError } = useTestResultDetails(runId, testDir, stepType)
+  const { data: requestSummaries } = useTestRequestSummaries(suiteHash, testDir, stepType)
+  const { data: responseSummaries } = useTestResponseSummaries(runId, testDir, stepType)
   const [page, setPage] = useState(1)
 
   // Compute byte offsets for each request line (for lazy Range fetches)
-  const requestFilePath = `suites/${suiteHash}/${testName}/${stepType}.request`
+  const requestFilePath = `suites/${suiteHash}/${testDir}/${stepType}.request`
   const requestByteOffsets = requestSummaries
     ? (() => {
         const offsets: number[] = []
@@ -400,10 +405,10 @@ export function ExecutionsList({ runId, suiteHash, testName, stepType }: Executi
               mgasPerSec={safeDetails?.mgas_s[String(index)]}
               gasUsed={safeDetails?.gas_used[String(index)]}
               responseViewerUrl={!safeResponses?.[index] && responseSummaries?.[index] && responseSummaries[index].size > 1_000_000
-                ? `/runs/${runId}/fileviewer?file=${encodeURIComponent(`${testName}/${stepType}.response`)}&lines=${index + 1}`
+                ? `/runs/${runId}/fileviewer?file=${encodeURIComponent(`${testDir}/${stepType}.response`)}&lines=${index + 1}`
                 : undefined}
               requestViewerUrl={!safeRequests?.[index] && requestSummaries?.[index] && requestSummaries[index].size > 1_000_000
-                ? `/runs/${runId}/fileviewer?base=${encodeURIComponent(`suites/${suiteHash}`)}&file=${encodeURIComponent(`${testName}/${stepType}.request`)}&lines=${index + 1}`
+                ? `/runs/${runId}/fileviewer?base=${encodeURIComponent(`suites/${suiteHash}`)}&file=${encodeURIComponent(`${testDir}/${stepType}.request`)}&lines=${index + 1}`
                 : undefined}
             />
           )
@@ -7,6 +7,7 @@ import { FolderOpen, Folder, Check, Copy, Download, List, ChevronDown, ChevronRi
 import type { PostTestRPCCallConfig, TestEntry } from '@/api/types'
 import { fetchHead, type HeadResult } from '@/api/client'
 import { formatBytes } from '@/utils/format'
+import { testDir } from '@/utils/test-dir'
 import { getDataUrl, isS3Mode, loadRuntimeConfig, toAbsoluteUrl } from '@/config/runtime'
 import { useAuth } from '@/hooks/useAuth'
 import { Modal } from '@/components/shared/Modal'
@@ -85,15 +86,16 @@ function getTestSteps(entry: TestEntry): StepName[] {
 function buildTestStatsEntries(runId: string, tests: Record<string, TestEntry>): FileEntry[] {
   const entries: FileEntry[] = []
   for (const [testName, testEntry] of Object.entries(tests)) {
+    const dir = testDir(testName, testEntry)
     for (const step of getTestSteps(testEntry)) {
       for (const suffix of ['result-aggregated.json', 'result-details.json']) {
         const filename = `${step}.${suffix}`
         entries.push({
           testName,
           filename,
-          path: `runs/${runId}/${testName}/${filename}`,
+          path: `runs/${runId}/${dir}/${filename}`,
           displayPath: filename,
-          outputPath: `${runId}/${testName}/${filename}`,
+          outputPath: `${runId}/${dir}/${filename}`,
         })
       }
     }
@@ -104,32 +106,34 @@ function buildTestStatsEntries(runId: string, tests: Record<string, TestEntry>):
 function buildTestResponsesEntries(runId: string, tests: Record<string, TestEntry>): FileEntry[] {
   const entries: FileEntry[] = []
   for (const [testName, testEntry] of Object.entries(tests)) {
+    const dir = testDir(testName, testEntry)
     for (const step of getTestSteps(testEntry)) {
       const filename = `${step}.response`
       entries.push({
         testName,
         filename,
-        path: `runs/${runId}/${testName}/${filename}`,
+        path: `runs/${runId}/${dir}/${filename}`,
         displayPath: filename,
-        outputPath: `${runId}/${testName}/${filename}`,
+        outputPath: `${runId}/${dir}/${filename}`,
       })
     }
   }
   return entries
 }
 
-function buildPostTestDumpEntries(runId: string, testNames: string[], postTestRPCCalls: PostTestRPCCallConfig[]): FileEntry[] {
+function buildPostTestDumpEntries(runId: string, tests: Record<string, TestEntry>, postTestRPCCalls: PostTestRPCCallConfig[]): FileEntry[] {
   const dumpCalls = postTestRPCCalls.filter((c) => c.dump?.enabled && c.dump.filename)
   const entries: FileEntry[] = []
-  for (const testName of testNames) {
+  for (const [testName, testEntry] of Object.entries(tests)) {
+    const dir = testDir(testName, testEntry)
     for (const call of dumpCalls) {
       const filename = `${call.dump!.filename}.json`
       entries.push({
         testName,
         filename,
-        path: `runs/${runId}/${testName}/post_test_rpc_calls/${filename}`,
+        path: `runs/${runId}/${dir}/post_test_rpc_calls/${filename}`,
         displayPath: `post_test_rpc_calls/${filename}`,
-        outputPath: `${runId}/${testName}/post_test_rpc_calls/${filename}`,
+        outputPath: `${runId}/${dir}/post_test_rpc_calls/${filename}`,
       })
     }
   }
@@ -167,6 +171,7 @@ function buildFileTree(
 
   // Test directories
   for (const [testName, testEntry] of Object.entries(tests)) {
+    const dir = testDir(testName, testEntry)
     const children: TreeNode[] = []
     const steps = getTestSteps(testEntry)
 
@@ -174,7 +179,7 @@ function buildFileTree(
     for (const step of steps) {
       for (const suffix of ['response', 'result-aggregated.json', 'result-details.json']) {
         const filename = `${step}.${suffix}`
-        const path = `runs/${runId}/${testName}/${filename}`
+        const path = `runs/${runId}/${dir}/${filename}`
         children.push({
           id: path,
           name: filename,
@@ -184,7 +189,7 @@ function buildFileTree(
             filename,
             path,
             displayPath: filename,
-            outputPath: `${runId}/${testName}/${filename}`,
+            outputPath: `${runId}/${dir}/${filename}`,
           },
           depth: 1,
         })
@@ -196,7 +201,7 @@ function buildFileTree(
       const dumpChildren: TreeNode[] = []
       for (const call of dumpCalls) {
         const filename = `${call.dump!.filename}.json`
-        const path = `runs/${runId}/${testName}/post_test_rpc_calls/${filename}`
+        const path = `runs/${runId}/${dir}/post_test_rpc_calls/${filename}`
         dumpChildren.push({
           id: path,
           name: filename,
@@ -206,7 +211,7 @@ function buildFileTree(
             filename,
             path,
             displayPath: `post_test_rpc_calls/${filename}`,
-            outputPath: `${runId}/${testName}/post_test_rpc_calls/${filename}`,
+            outputPath: `${runId}/${dir}/post_test_rpc_calls/${filename}`,
           },
           depth: 2,
         })
@@ -241,11 +246,12 @@ function buildDumpsOnlyTree(
   if (dumpCalls.length === 0) return []
 
   const nodes: TreeNode[] = []
-  for (const testName of Object.keys(tests)) {
+  for (const [testName, testEntry] of Object.entries(tests)) {
+    const dir = testDir(testName, testEntry)
     const children: TreeNode[] = []
     for (const call of dumpCalls) {
       const filename = `${call.dump!.filename}.json`
-      const path = `runs/${runId}/${testName}/post_test_rpc_calls/${filename}`
+      const path = `runs/${runId}/${dir}/post_test_rpc_calls/${filename}`
       children.push({
         id: path,
         name: filename,
@@ -255,7 +261,7 @@ function buildDumpsOnlyTree(
           filename,
           path,
           displayPath: `post_test_rpc_calls/${filename}`,
-          outputPath: `${runId}/${testName}/post_test_rpc_calls/${filename}`,
+          outputPath: `${runId}/${dir}/post_test_rpc_calls/${filename}`,
         },
         depth: 1,
       })
@@ -475,14 +481,12 @@ export function FilesPanel({ runId, tests, postTestRPCCalls, showDownloadList, d
   const [fileFilter, setFileFilter] = useState<'all' | 'dumps'>('all')
   const [excludedCategories, setExcludedCategories] = useState<Set<string>>(() => new Set())
 
-  const testNames = useMemo(() => Object.keys(tests), [tests])
-
   const generalEntries = useMemo(() => buildGeneralEntries(runId), [runId])
   const testStatsEntries = useMemo(() => buildTestStatsEntries(runId, tests), [runId, tests])
   const testResponsesEntries = useMemo(() => buildTestResponsesEntries(runId, tests), [runId, tests])
   const postTestDumpEntries = useMemo(
-    () => buildPostTestDumpEntries(runId, testNames, postTestRPCCalls ?? []),
-    [runId, testNames, postTestRPCCalls],
+    () => buildPostTestDumpEntries(runId, tests, postTestRPCCalls ?? []),
+    [runId, tests, postTestRPCCalls],
   )
 
   const hasPostTestDumps = postTestDumpEntries.length > 0
@@ -122,6 +122,31 @@ export function RunConfiguration({ instance, system, startBlock, metadata }: Run
                 </div>
               )}
 
+              {instance.client_commit && (
+                <div>
+                  <dt className="text-xs/5 font-medium text-gray-500 dark:text-gray-400">Client Commit</dt>
+                  <dd className="mt-1 flex items-center gap-2">
+                    <span className="font-mono text-sm/6 text-gray-900 dark:text-gray-100">
+                      {instance.client_commit.length > 12
+                        ? instance.client_commit.slice(0, 12)
+                        : instance.client_commit}
+                    </span>
+                    <CopyButton text={instance.client_commit} />
+                  </dd>
+                </div>
+              )}
+
+              {instance.build && (
+                <div>
+                  <dt className="text-xs/5 font-medium text-gray-500 dark:text-gray-400">Built From</dt>
+                  <dd className="mt-1 flex items-center gap-2">
+                    <span className="font-mono text-sm/6 text-gray-900 dark:text-gray-100">
+                      {instance.build.context}/{instance.build.dockerfile ?? 'Dockerfile'}
+                    </span>
+                  </dd>
+                </div>
+              )}
+
               {instance.container_runtime && (
                 <div>
                   <dt className="text-xs/5 font-medium text-gray-500 dark:text-gray-400">Container Runtime</dt>
@@ -158,6 +183,28 @@ export function RunConfiguration({ instance, system, startBlock, metadata }: Run
                 </div>
               )}
 
+              {instance.remove_default_args && instance.remove_default_args.length > 0 && (
+                <div>
+                  <dt className="text-xs/5 font-medium text-gray-500 dark:text-gray-400">
+                    Removed Default Arguments
+                  </dt>
+                  <dd className="mt-1 overflow-x-auto rounded-sm bg-gray-100 p-2 font-mono text-xs/5 text-gray-900 dark:bg-gray-900 dark:text-gray-100">
+                    {instance.remove_default_args.join(' ')}
+                  </dd>
+                </div>
+              )}
+
+              {instance.secret_files && instance.secret_files.length > 0 && (
+                <div>
+                  <dt className="text-xs/5 font-medium text-gray-500 dark:text-gray-400">
+                    Secret Files
+                  </dt>
+                  <dd className="mt-1 overflow-x-auto rounded-sm bg-gray-100 p-2 font-mono text-xs/5 text-gray-900 dark:bg-gray-900 dark:text-gray-100">
+                    {instance.secret_files.join(' ')}
+                  </dd>
+                </div>
+              )}
+
               {instance.environment && Object.keys(instance.environment).length > 0 && (
                 <div>
                   <dt className="text-xs/5 font-medium text-gray-500 dark:text-gray-400">
@@ -490,9 +537,21 @@ export function RunConfiguration({ instance, system, startBlock, metadata }: Run
                       <InfoItem label="CPU Pinning" value={instance.resource_limits.cpuset_cpus} />
                     </>
                   )}
+                  {instance.resource_limits.cpus && (
+                    <InfoItem label="CPU Limit" value={instance.resource_limits.cpus} />
+                  )}
+                  {instance.resource_limits.cpu_quota !== undefined && (
+                    <InfoItem
+                      label="CPU Quota"
+                      value={`${instance.resource_limits.cpu_quota}µs / ${instance.resource_limits.cpu_period ?? 100000}µs`}
+                    />
+                  )}
                   {instance.resource_limits.memory && (
                     <InfoItem label="Memory Limit" value={instance.resource_limits.memory} />
                   )}
+                  {instance.resource_limits.memory_reservation && (
+                    <InfoItem label="Memory Reservation" value={instance.resource_limits.memory_reservation} />
+                  )}
                   {instance.resource_limits.swap_disabled !== undefined && (
                     <InfoItem label="Swap Disabled" value={instance.resource_limits.swap_disabled ? 'Yes' : 'No'} />
                   )}
@@ -567,6 +626,24 @@ export function RunConfiguration({ instance, system, startBlock, metadata }: Run
                               ))}
                             </div>
                           )}
+                        {instance.resource_limits.blkio_config.weight !== undefined && (
+                          <div>
+                            <span className="text-gray-500 dark:text-gray-400">Weight: </span>
+                            {instance.resource_limits.blkio_config.weight}
+                          </div>
+                        )}
+                        {instance.resource_limits.blkio_config.device_weight &&
+                          instance.resource_limits.blkio_config.device_weight.length > 0 && (
+                            <div>
+                              <span className="text-gray-500 dark:text-gray-400">Device Weight: </span>
+                              {instance.resource_limits.blkio_config.device_weight.map((dev, i) => (
+                                <span key={i}>
+                                  {i > 0 && ', '}
+                                  {dev.path} @ {dev.weight}
+                                </span>
+                              ))}
+                            </div>
+                          )}
                       </div>
                     </div>
                   </div>
@@ -12,6 +12,7 @@ import { BlockLogDetails } from './BlockLogDetails'
 import type { TestStatusFilter } from './TestsTable'
 import { type StepTypeOption, ALL_STEP_TYPES } from '@/pages/RunDetailPage'
 import { formatDuration, formatBytes } from '@/utils/format'
+import { testDir } from '@/utils/test-dir'
 import { EESTInfoContent, type OpcodeSortMode } from '@/components/suite-detail/TestFilesList'
 import { useBlockLogs } from '@/api/hooks/useBlockLogs'
 
@@ -182,13 +183,13 @@ const NO_DATA_STYLE = {
   backgroundImage: 'repeating-linear-gradient(45deg, transparent, transparent 2px, #1f2937 2px, #1f2937 4px)',
 }
 
-function PostTestDumps({ runId, testName, calls }: { runId: string; testName: string; calls: PostTestRPCCallConfig[] }) {
+function PostTestDumps({ runId, testName, dir, calls }: { runId: string; testName: string; dir: string; calls: PostTestRPCCallConfig[] }) {
   const dumpCalls = calls.filter((c) => c.dump?.enabled && c.dump.filename)
 
   const fileQueries = useQueries({
     queries: dumpCalls.map((call) => ({
       queryKey: ['post-test-dump', runId, testName, call.dump!.filename],
-      queryFn: () => fetchHead(`runs/${runId}/${testName}/post_test_rpc_calls/${call.dump!.filename}.json`),
+      queryFn: () => fetchHead(`runs/${runId}/${dir}/post_test_rpc_calls/${call.dump!.filename}.json`),
       staleTime: Infinity,
     })),
   })
@@ -831,6 +832,7 @@ export function TestHeatmap({
                           runId={runId}
                           suiteHash={suiteHash}
                           testName={selectedTest}
+                          testDir={testDir(selectedTest, entry)}
                           stepType={activeStep.key}
                         />
                       </div>
@@ -839,7 +841,7 @@ export function TestHeatmap({
                 )
               })()}
               {postTestRPCCalls && postTestRPCCalls.length > 0 && (
-                <PostTestDumps runId={runId} testName={selectedTest} calls={postTestRPCCalls} />
+                <PostTestDumps runId={runId} testName={selectedTest} dir={testDir(selectedTest, entry)} calls={postTestRPCCalls} />
               )}
             </div>
           </Modal>
@@ -77,9 +77,16 @@ interface StatusAlertProps {
   terminationReason?: string
   containerExitCode?: number
   containerOOMKilled?: boolean
+  containerRestartCount?: number
 }
 
-export function StatusAlert({ status, terminationReason, containerExitCode, containerOOMKilled }: StatusAlertProps) {
+export function StatusAlert({
+  status,
+  terminationReason,
+  containerExitCode,
+  containerOOMKilled,
+  containerRestartCount,
+}: StatusAlertProps) {
   // Only show alert for non-completed statuses
   if (!status || status === 'completed') {
     return null
@@ -124,6 +131,11 @@ export function StatusAlert({ status, terminationReason, containerExitCode, cont
             Container was killed due to out of memory (OOM)
           </p>
         )}
+        {containerRestartCount !== undefined && containerRestartCount > 0 && (
+          <p className={clsx('mt-1 text-sm/6', textClasses[status])}>
+            Container restarts: <span className="font-mono font-semibold">{containerRestartCount}</span>
+          </p>
+        )}
         {containerExitCode !== undefined && (
           <p className={clsx('mt-1 text-sm/6', textClasses[status])}>
             Container exit code: <span className="font-mono font-semibold">{containerExitCode}</span>
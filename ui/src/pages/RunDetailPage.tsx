@@ -311,7 +311,7 @@ export function RunDetailPage() {
     : []
   const testCount = config.test_counts?.total ?? (result ? Object.keys(result.tests).length : 0)
   const passedTests = config.test_counts?.passed ?? aggregatedStats.filter((s) => s.fail === 0).length
-  const failedTests = config.test_counts ? (config.test_counts.total - config.test_counts.passed) : aggregatedStats.filter((s) => s.fail > 0).length
+  const failedTests = config.test_counts ? (config.test_counts.total - config.test_counts.passed - (config.test_counts.skipped ?? 0)) : aggregatedStats.filter((s) => s.fail > 0).length
   const totalDuration = aggregatedStats.reduce((sum, s) => sum + s.time_total, 0)
   const totalGasUsed = aggregatedStats.reduce((sum, s) => sum + s.gas_used_total, 0)
   const totalGasUsedTime = aggregatedStats.reduce((sum, s) => sum + s.gas_used_time_total, 0)
@@ -465,6 +465,7 @@ export function RunDetailPage() {
         terminationReason={config.termination_reason}
         containerExitCode={config.container_exit_code}
         containerOOMKilled={config.container_oom_killed}
+        containerRestartCount={config.container_restart_count}
       />
 
       <div className="grid grid-cols-2 gap-4 sm:grid-cols-5">
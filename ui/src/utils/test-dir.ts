@@ -0,0 +1,8 @@
+import type { TestEntry } from '@/api/types'
+
+// Directory of a test's result files, relative to the run or suite directory.
+// Tests whose name is unsafe or too long as a path are stored under
+// filename_hash instead of their name.
+export function testDir(testName: string, entry?: TestEntry): string {
+  return entry?.filename_hash || testName
+}